@@ -10,14 +10,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/AndikaPrasetia/pos-cafee/internal/availability"
 	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
 	"github.com/AndikaPrasetia/pos-cafee/internal/config"
 	"github.com/AndikaPrasetia/pos-cafee/internal/handlers"
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
 	"github.com/AndikaPrasetia/pos-cafee/internal/middleware"
+	"github.com/AndikaPrasetia/pos-cafee/internal/notify"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
 	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
 	"github.com/AndikaPrasetia/pos-cafee/internal/services"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
@@ -53,21 +59,97 @@ func main() {
 	// Initialize repositories
 	repo := repositories.NewRepository(db)
 
+	// Load checkout pricing rules
+	pricingRules, err := pricing.ParseRules(cfg.Pricing.TaxRate, cfg.Pricing.ServiceChargeRate, cfg.Pricing.RoundingIncrement, cfg.Pricing.RoundingMode)
+	if err != nil {
+		log.Fatal("Invalid pricing configuration:", err)
+	}
+
+	refundApprovalThreshold, err := decimal.NewFromString(cfg.Order.RefundApprovalThreshold)
+	if err != nil {
+		log.Fatal("Invalid refund approval threshold:", err)
+	}
+
+	orderNumberFormat := services.OrderNumberFormat{
+		Prefix:         cfg.Order.NumberPrefix,
+		Pattern:        cfg.Order.NumberPattern,
+		DateLayout:     cfg.Order.NumberDateLayout,
+		SequenceDigits: cfg.Order.NumberDigits,
+	}
+	if err := orderNumberFormat.Validate(); err != nil {
+		log.Fatal("Invalid order number configuration:", err)
+	}
+
+	// Live kitchen display updates are fanned out in process
+	kitchenEvents := kitchen.NewBroker()
+
+	// So are menu items selling out and coming back as stock moves
+	menuEvents := availability.NewBroker()
+	menuAvailability := services.NewMenuAvailability(cacheClient, menuEvents)
+
 	// Initialize services
 	authService := services.NewAuthService(repo.UserRepo, cfg.JWTSecret, parseDuration(cfg.JWTExpiry))
-	menuService := services.NewMenuService(repo.MenuRepo, repo.InventoryRepo, cacheClient)
-	orderService := services.NewOrderService(repo.OrderRepo, repo.OrderItemRepo, repo.MenuRepo, repo.InventoryRepo, repo.StockTransactionRepo, cacheClient)
-	inventoryService := services.NewInventoryService(repo.InventoryRepo, repo.StockTransactionRepo, repo.MenuRepo)
+	menuService := services.NewMenuService(repo.MenuRepo, repo.InventoryRepo, cacheClient, menuAvailability)
+	modifierService := services.NewModifierService(repo, repo.UnitOfWork)
+	orderService := services.NewOrderService(repo, repo.UnitOfWork, cacheClient, kitchenEvents, orderNumberFormat, pricingRules, menuAvailability)
+	inventoryService := services.NewInventoryService(repo, repo.UnitOfWork, menuAvailability)
+	recipeService := services.NewRecipeService(repo, repo.UnitOfWork)
+	unitService := services.NewUnitService(repo.UnitRepo)
+	supplierService := services.NewSupplierService(repo.SupplierRepo)
+	purchaseOrderService := services.NewPurchaseOrderService(repo, repo.UnitOfWork, menuAvailability)
+	wasteService := services.NewWasteService(repo, repo.UnitOfWork, menuAvailability)
+	stockTakeService := services.NewStockTakeService(repo, repo.UnitOfWork, menuAvailability)
+	stockLocationService := services.NewStockLocationService(repo, repo.UnitOfWork, menuAvailability)
 	expenseService := services.NewExpenseService(repo.ExpenseRepo)
+	promotionService := services.NewPromotionService(repo.PromotionRepo)
+	refundService := services.NewRefundService(repo, repo.UnitOfWork, cacheClient, refundApprovalThreshold, menuAvailability)
+	kitchenService := services.NewKitchenService(repo, repo.UnitOfWork, kitchenEvents)
+	tableService := services.NewTableService(repo.TableRepo)
+	receiptSettings := receipt.Settings{
+		StoreName: cfg.Receipt.StoreName,
+		Address:   cfg.Receipt.Address,
+		NPWP:      cfg.Receipt.NPWP,
+		Footer:    cfg.Receipt.Footer,
+	}
+	receiptService := services.NewReceiptService(repo, repo.UnitOfWork, orderService, receiptSettings)
+	shiftService := services.NewShiftService(repo, repo.UnitOfWork)
+	salesReportService := services.NewSalesReportService(repo, repo.UnitOfWork, receiptSettings)
 	reportService := services.NewReportService(repo.OrderRepo, repo.MenuRepo, repo.InventoryRepo, repo.ExpenseRepo, repo.Queries, cacheClient)
 
+	// Low stock alerts always reach the in-app feed, and email and the webhook
+	// when they are configured
+	var alertChannels []notify.Channel
+	if cfg.Notification.SMTPHost != "" && len(cfg.Notification.AlertEmails) > 0 {
+		alertChannels = append(alertChannels, notify.NewEmailChannel(cfg.Notification.SMTPHost, cfg.Notification.SMTPPort, cfg.Notification.SMTPUsername, cfg.Notification.SMTPPassword, cfg.Notification.SMTPFrom, cfg.Notification.AlertEmails))
+	}
+	if cfg.Notification.WebhookURL != "" {
+		alertChannels = append(alertChannels, notify.NewWebhookChannel(cfg.Notification.WebhookURL, cfg.Notification.WebhookSecret))
+	}
+	lowStockAlertService := services.NewLowStockAlertService(repo.LowStockAlertRepo, alertChannels)
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	menuHandler := handlers.NewMenuHandler(menuService)
+	modifierHandler := handlers.NewModifierHandler(modifierService)
 	orderHandler := handlers.NewOrderHandler(orderService)
 	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	recipeHandler := handlers.NewRecipeHandler(recipeService)
+	unitHandler := handlers.NewUnitHandler(unitService)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+	stockTakeHandler := handlers.NewStockTakeHandler(stockTakeService)
+	stockLocationHandler := handlers.NewStockLocationHandler(stockLocationService)
+	wasteHandler := handlers.NewWasteHandler(wasteService)
 	expenseHandler := handlers.NewExpenseHandler(expenseService)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	kitchenHandler := handlers.NewKitchenHandler(kitchenService)
+	tableHandler := handlers.NewTableHandler(tableService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	shiftHandler := handlers.NewShiftHandler(shiftService)
 	reportHandler := handlers.NewReportHandler(reportService)
+	salesReportHandler := handlers.NewSalesReportHandler(salesReportService)
+	lowStockAlertHandler := handlers.NewLowStockAlertHandler(lowStockAlertService)
 
 	// Initialize Gin router
 	router := gin.New()
@@ -99,6 +181,13 @@ func main() {
 		authProtected.POST("/logout", authHandler.Logout)
 	}
 
+	// Live menu availability for the tills (require cashier role or above)
+	menuDisplay := router.Group("/api/menu")
+	menuDisplay.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		menuDisplay.GET("/availability/stream", menuHandler.StreamAvailability)
+	}
+
 	// Menu management routes (require manager or admin role)
 	menu := router.Group("/api/menu")
 	menu.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
@@ -116,6 +205,27 @@ func main() {
 		menu.GET("/items/:id", menuHandler.GetMenuItem)
 		menu.PUT("/items/:id", menuHandler.UpdateMenuItem)
 		menu.DELETE("/items/:id", menuHandler.DeleteMenuItem)
+
+		// Modifier groups offered with a menu item
+		menu.GET("/items/:id/modifier-groups", modifierHandler.ListMenuItemModifierGroups)
+		menu.POST("/items/:id/modifier-groups", modifierHandler.AttachModifierGroup)
+		menu.DELETE("/items/:id/modifier-groups/:groupId", modifierHandler.DetachModifierGroup)
+
+		// Ingredients that go into a menu item
+		menu.GET("/items/:id/recipe", recipeHandler.GetMenuItemRecipe)
+		menu.PUT("/items/:id/recipe", recipeHandler.SetMenuItemRecipe)
+
+		// Modifier group and option endpoints (sizes, add-ons)
+		menu.GET("/modifier-groups", modifierHandler.ListModifierGroups)
+		menu.POST("/modifier-groups", modifierHandler.CreateModifierGroup)
+		menu.GET("/modifier-groups/:id", modifierHandler.GetModifierGroup)
+		menu.PUT("/modifier-groups/:id", modifierHandler.UpdateModifierGroup)
+		menu.DELETE("/modifier-groups/:id", modifierHandler.DeleteModifierGroup)
+		menu.POST("/modifier-groups/:id/options", modifierHandler.AddModifierOption)
+		menu.PUT("/modifier-options/:optionId", modifierHandler.UpdateModifierOption)
+		menu.DELETE("/modifier-options/:optionId", modifierHandler.DeleteModifierOption)
+		menu.GET("/modifier-options/:optionId/recipe", recipeHandler.GetModifierOptionRecipe)
+		menu.PUT("/modifier-options/:optionId/recipe", recipeHandler.SetModifierOptionRecipe)
 	}
 
 	// Order management routes (require cashier role or higher)
@@ -126,8 +236,80 @@ func main() {
 		orders.POST("/", orderHandler.CreateOrder)
 		orders.GET("/:id", orderHandler.GetOrder)
 		orders.POST("/:id/items", orderHandler.AddItemToOrder)
+		orders.PUT("/:id/items/:itemId", orderHandler.UpdateOrderItem)
+		orders.DELETE("/:id/items/:itemId", orderHandler.RemoveOrderItem)
+		orders.PUT("/:id/submit", orderHandler.SubmitOrder)
+		orders.PUT("/:id/table", orderHandler.TransferOrder)
+		orders.POST("/:id/merge", orderHandler.MergeOrders)
+		orders.POST("/:id/split", orderHandler.SplitOrder)
 		orders.PUT("/:id/complete", orderHandler.CompleteOrder)
 		orders.PUT("/:id/cancel", orderHandler.CancelOrder)
+		orders.GET("/:id/refunds", refundHandler.ListRefunds)
+		orders.POST("/:id/refunds", refundHandler.CreateRefund)
+		orders.GET("/:id/receipt", receiptHandler.PrintReceipt)
+		orders.GET("/:id/receipts", receiptHandler.ListReceiptPrints)
+	}
+
+	// Cash drawer shift routes (require cashier role or higher)
+	shifts := router.Group("/api/shifts")
+	shifts.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		shifts.POST("/", shiftHandler.OpenShift)
+		shifts.GET("/current", shiftHandler.GetCurrentShift)
+		shifts.GET("/:id", shiftHandler.GetShift)
+		shifts.POST("/:id/movements", shiftHandler.AddMovement)
+		shifts.PUT("/:id/close", shiftHandler.CloseShift)
+	}
+
+	// Shift history routes (require manager or admin role)
+	shiftHistory := router.Group("/api/shifts")
+	shiftHistory.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		shiftHistory.GET("/", shiftHandler.ListShifts)
+	}
+
+	// Kitchen display routes (require cashier role or higher)
+	kitchenDisplay := router.Group("/api/kitchen")
+	kitchenDisplay.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		kitchenDisplay.GET("/stations", kitchenHandler.ListStations)
+		kitchenDisplay.GET("/tickets", kitchenHandler.ListTickets)
+		kitchenDisplay.PUT("/tickets/:id/bump", kitchenHandler.BumpTicket)
+		kitchenDisplay.GET("/stream", kitchenHandler.StreamTickets)
+	}
+
+	// Kitchen station management routes (require manager or admin role)
+	kitchenStations := router.Group("/api/kitchen")
+	kitchenStations.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		kitchenStations.POST("/stations", kitchenHandler.CreateStation)
+		kitchenStations.GET("/stations/:id", kitchenHandler.GetStation)
+		kitchenStations.PUT("/stations/:id", kitchenHandler.UpdateStation)
+		kitchenStations.DELETE("/stations/:id", kitchenHandler.DeleteStation)
+	}
+
+	// Floor plan routes (require cashier role or higher)
+	floorPlan := router.Group("/api/tables")
+	floorPlan.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		floorPlan.GET("/floor-plan", tableHandler.GetFloorPlan)
+	}
+
+	// Floor area and table management routes (require manager or admin role)
+	tables := router.Group("/api/tables")
+	tables.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		tables.GET("/areas", tableHandler.ListFloorAreas)
+		tables.POST("/areas", tableHandler.CreateFloorArea)
+		tables.GET("/areas/:id", tableHandler.GetFloorArea)
+		tables.PUT("/areas/:id", tableHandler.UpdateFloorArea)
+		tables.DELETE("/areas/:id", tableHandler.DeleteFloorArea)
+
+		tables.GET("/", tableHandler.ListTables)
+		tables.POST("/", tableHandler.CreateTable)
+		tables.GET("/:id", tableHandler.GetTable)
+		tables.PUT("/:id", tableHandler.UpdateTable)
+		tables.DELETE("/:id", tableHandler.DeleteTable)
 	}
 
 	// Inventory management routes (require manager or admin role)
@@ -138,6 +320,94 @@ func main() {
 		inventory.GET("/low-stock", inventoryHandler.GetLowStockItems)
 		inventory.POST("/adjust", inventoryHandler.UpdateInventory)
 		inventory.GET("/transactions", inventoryHandler.ListStockTransactions)
+		inventory.GET("/lots", inventoryHandler.ListStockLots)
+		inventory.GET("/lots/expiring", inventoryHandler.ListExpiringStockLots)
+
+		// Ingredients used by menu item recipes
+		inventory.GET("/ingredients", inventoryHandler.ListIngredients)
+		inventory.POST("/ingredients", inventoryHandler.CreateIngredient)
+		inventory.GET("/ingredients/:id", inventoryHandler.GetIngredient)
+		inventory.PUT("/ingredients/:id", inventoryHandler.UpdateIngredient)
+
+		// Units of measure stock can be counted, bought and used in
+		inventory.GET("/units", unitHandler.ListUnits)
+		inventory.POST("/units", unitHandler.CreateUnit)
+	}
+
+	// Supplier management routes (require manager or admin role)
+	suppliers := router.Group("/api/suppliers")
+	suppliers.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		suppliers.GET("/", supplierHandler.ListSuppliers)
+		suppliers.POST("/", supplierHandler.CreateSupplier)
+		suppliers.GET("/:id", supplierHandler.GetSupplier)
+		suppliers.PUT("/:id", supplierHandler.UpdateSupplier)
+	}
+
+	// Purchase order and goods receiving routes (require manager or admin role)
+	purchaseOrders := router.Group("/api/purchase-orders")
+	purchaseOrders.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		purchaseOrders.GET("/", purchaseOrderHandler.ListPurchaseOrders)
+		purchaseOrders.POST("/", purchaseOrderHandler.CreatePurchaseOrder)
+		purchaseOrders.GET("/:id", purchaseOrderHandler.GetPurchaseOrder)
+		purchaseOrders.PUT("/:id", purchaseOrderHandler.UpdatePurchaseOrder)
+		purchaseOrders.POST("/:id/send", purchaseOrderHandler.SendPurchaseOrder)
+		purchaseOrders.POST("/:id/cancel", purchaseOrderHandler.CancelPurchaseOrder)
+		purchaseOrders.POST("/:id/receive", purchaseOrderHandler.ReceiveGoods)
+	}
+
+	// Stock counting routes (require cashier role or higher)
+	stockCounts := router.Group("/api/stock-takes")
+	stockCounts.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		stockCounts.GET("/:id", stockTakeHandler.GetStockTake)
+		stockCounts.PUT("/:id/counts", stockTakeHandler.RecordCounts)
+		stockCounts.POST("/:id/scans", stockTakeHandler.RecordScan)
+	}
+
+	// Stock take management routes (require manager or admin role)
+	stockTakes := router.Group("/api/stock-takes")
+	stockTakes.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		stockTakes.GET("/", stockTakeHandler.ListStockTakes)
+		stockTakes.POST("/", stockTakeHandler.CreateStockTake)
+		stockTakes.POST("/:id/approve", stockTakeHandler.ApproveStockTake)
+		stockTakes.POST("/:id/cancel", stockTakeHandler.CancelStockTake)
+	}
+
+	// Stock location routes (require manager or admin role)
+	stockLocations := router.Group("/api/stock-locations")
+	stockLocations.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		stockLocations.GET("/", stockLocationHandler.ListStockLocations)
+		stockLocations.POST("/", stockLocationHandler.CreateStockLocation)
+		stockLocations.PUT("/:id", stockLocationHandler.UpdateStockLocation)
+		stockLocations.GET("/:id/balances", stockLocationHandler.ListStockBalances)
+	}
+
+	// Stock transfer routes (require manager or admin role)
+	stockTransfers := router.Group("/api/stock-transfers")
+	stockTransfers.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		stockTransfers.GET("/", stockLocationHandler.ListStockTransfers)
+		stockTransfers.POST("/", stockLocationHandler.CreateStockTransfer)
+		stockTransfers.GET("/:id", stockLocationHandler.GetStockTransfer)
+	}
+
+	// Waste logging routes (require cashier role or higher)
+	wasteLog := router.Group("/api/waste")
+	wasteLog.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "cashier"))
+	{
+		wasteLog.POST("/", wasteHandler.RecordWaste)
+	}
+
+	// Waste history routes (require manager or admin role)
+	waste := router.Group("/api/waste")
+	waste.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		waste.GET("/", wasteHandler.ListWaste)
+		waste.POST("/expired-lots", wasteHandler.WriteOffExpiredLots)
 	}
 
 	// Reporting routes (require manager or admin role)
@@ -147,7 +417,23 @@ func main() {
 		reports.GET("/daily-sales", reportHandler.GetDailySalesReport)
 		reports.GET("/financial-summary", reportHandler.GetFinancialSummaryReport)
 		reports.GET("/sales-by-category", reportHandler.GetSalesByCategoryReport)
+		reports.GET("/sales-by-modifier", reportHandler.GetSalesByModifierReport)
+		reports.GET("/prep-times", reportHandler.GetPrepTimeReport)
+		reports.GET("/waste", reportHandler.GetWasteReport)
+		reports.GET("/stock-valuation", reportHandler.GetStockValuationReport)
 		reports.GET("/top-selling-items", reportHandler.GetTopSellingItemsReport)
+		reports.GET("/x", salesReportHandler.GetXReport)
+		reports.POST("/z", salesReportHandler.GenerateZReport)
+		reports.GET("/z", salesReportHandler.ListZReports)
+		reports.GET("/z/:id", salesReportHandler.GetZReport)
+	}
+
+	// Low stock notification feed routes (require manager or admin role)
+	notifications := router.Group("/api/notifications")
+	notifications.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		notifications.GET("/", lowStockAlertHandler.ListLowStockAlerts)
+		notifications.PUT("/:id/read", lowStockAlertHandler.MarkLowStockAlertRead)
 	}
 
 	// Expense management routes (require manager or admin role)
@@ -162,6 +448,17 @@ func main() {
 		expenses.DELETE("/:id", expenseHandler.DeleteExpense)
 	}
 
+	// Promotion and voucher management routes (require manager or admin role)
+	promotions := router.Group("/api/promotions")
+	promotions.Use(middleware.RoleAuthMiddleware(cfg.JWTSecret, "manager"))
+	{
+		promotions.GET("/", promotionHandler.ListPromotions)
+		promotions.POST("/", promotionHandler.CreatePromotion)
+		promotions.GET("/:id", promotionHandler.GetPromotion)
+		promotions.PUT("/:id", promotionHandler.UpdatePromotion)
+		promotions.DELETE("/:id", promotionHandler.DeletePromotion)
+	}
+
 	// Maintenance routes (admin for backups)
 	// Note: Health check already added earlier
 	maintenance := router.Group("/api/maintenance")
@@ -179,6 +476,15 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// End kitchen display and menu availability streams so shutdown does not wait on them
+	srv.RegisterOnShutdown(kitchenEvents.Close)
+	srv.RegisterOnShutdown(menuEvents.Close)
+
+	// Deliver low stock alerts in the background until the server shuts down
+	alertCtx, stopAlerts := context.WithCancel(context.Background())
+	go lowStockAlertService.Run(alertCtx, parseDuration(cfg.Notification.DispatchInterval))
+	srv.RegisterOnShutdown(stopAlerts)
+
 	// channel for signal interrupt
 	quit := make(chan os.Signal, 1)
 	// signal interrupted (Ctrl+C)
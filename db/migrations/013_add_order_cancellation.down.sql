@@ -0,0 +1,12 @@
+-- Remove order cancellation tracking
+DROP INDEX IF EXISTS idx_stock_transactions_reference;
+DROP INDEX IF EXISTS idx_orders_cancelled_at;
+
+ALTER TABLE orders DROP COLUMN cancelled_at;
+ALTER TABLE orders DROP COLUMN cancelled_by;
+ALTER TABLE orders DROP COLUMN cancellation_reason;
+
+UPDATE orders SET payment_status = 'paid' WHERE payment_status = 'refunded';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'failed'));
@@ -0,0 +1,13 @@
+-- Allow completed orders to be voided with their payment refunded
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'failed', 'refunded'));
+
+-- Record why, when and by whom an order was cancelled
+ALTER TABLE orders ADD COLUMN cancellation_reason VARCHAR(255);
+ALTER TABLE orders ADD COLUMN cancelled_by UUID REFERENCES users(id);
+ALTER TABLE orders ADD COLUMN cancelled_at TIMESTAMP;
+
+-- Add indexes for performance optimization
+CREATE INDEX idx_orders_cancelled_at ON orders(cancelled_at);
+CREATE INDEX idx_stock_transactions_reference ON stock_transactions(reference_type, reference_id);
@@ -0,0 +1,2 @@
+-- Drop order number sequences table
+DROP TABLE IF EXISTS order_number_sequences;
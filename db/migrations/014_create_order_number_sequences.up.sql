@@ -0,0 +1,10 @@
+-- Create order number sequences table
+-- One row per prefix and business day; the row is locked by the order-creating
+-- transaction, so numbers are handed out in order and never skipped or reused
+CREATE TABLE order_number_sequences (
+    prefix VARCHAR(20) NOT NULL,
+    business_date DATE NOT NULL,
+    last_number INTEGER NOT NULL DEFAULT 0 CHECK (last_number >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (prefix, business_date)
+);
@@ -0,0 +1,6 @@
+-- Remove order price breakdown
+ALTER TABLE orders DROP COLUMN tax_rate;
+ALTER TABLE orders DROP COLUMN service_charge_rate;
+ALTER TABLE orders DROP COLUMN rounding_amount;
+ALTER TABLE orders DROP COLUMN service_charge_amount;
+ALTER TABLE orders DROP COLUMN subtotal_amount;
@@ -0,0 +1,12 @@
+-- Store the full price breakdown of an order:
+-- total = subtotal - discount + service charge + tax + rounding
+ALTER TABLE orders ADD COLUMN subtotal_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (subtotal_amount >= 0);
+ALTER TABLE orders ADD COLUMN service_charge_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (service_charge_amount >= 0);
+ALTER TABLE orders ADD COLUMN rounding_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00;
+
+-- Rates in percent that were applied when the order was priced
+ALTER TABLE orders ADD COLUMN service_charge_rate DECIMAL(5,2) NOT NULL DEFAULT 0.00 CHECK (service_charge_rate >= 0);
+ALTER TABLE orders ADD COLUMN tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0);
+
+-- Existing orders were never priced, so their subtotal is what they were charged
+UPDATE orders SET subtotal_amount = total_amount + discount_amount - tax_amount;
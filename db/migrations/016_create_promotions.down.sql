@@ -0,0 +1,3 @@
+-- Drop promotions tables
+DROP TABLE IF EXISTS order_promotions;
+DROP TABLE IF EXISTS promotions;
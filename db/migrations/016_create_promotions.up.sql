@@ -0,0 +1,47 @@
+-- Create promotions table
+-- A promotion without a voucher code is applied automatically to every order it qualifies for
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    promotion_type VARCHAR(20) NOT NULL CHECK (promotion_type IN ('percentage', 'fixed_amount', 'buy_x_get_y')),
+    value DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (value >= 0),
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    target_type VARCHAR(20) NOT NULL DEFAULT 'order' CHECK (target_type IN ('order', 'category', 'menu_item')),
+    target_id UUID,
+    voucher_code VARCHAR(50) UNIQUE,
+    min_spend DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (min_spend >= 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    daily_start_time VARCHAR(5) CHECK (daily_start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    daily_end_time VARCHAR(5) CHECK (daily_end_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    usage_count INTEGER NOT NULL DEFAULT 0 CHECK (usage_count >= 0),
+    is_stackable BOOLEAN NOT NULL DEFAULT false,
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (promotion_type <> 'buy_x_get_y' OR (buy_quantity IS NOT NULL AND get_quantity IS NOT NULL)),
+    CHECK (target_type = 'order' OR target_id IS NOT NULL),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+-- Create order promotions table recording the promotions applied to each order
+CREATE TABLE order_promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id UUID REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name VARCHAR(100) NOT NULL,
+    discount_amount DECIMAL(12,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_promotions_is_active ON promotions(is_active);
+CREATE INDEX idx_promotions_voucher_code ON promotions(voucher_code);
+CREATE INDEX idx_promotions_starts_at_ends_at ON promotions(starts_at, ends_at);
+CREATE INDEX idx_order_promotions_order_id ON order_promotions(order_id);
+CREATE INDEX idx_order_promotions_promotion_id ON order_promotions(promotion_id);
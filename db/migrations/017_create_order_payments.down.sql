@@ -0,0 +1,8 @@
+-- Drop order_payments table
+DROP TABLE IF EXISTS order_payments;
+
+-- Split orders cannot be represented by the old constraint
+UPDATE orders SET payment_method = NULL WHERE payment_method = 'split';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_method_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_method_check
+    CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer'));
@@ -0,0 +1,28 @@
+-- Create order_payments table
+-- One row per tender used to settle an order, e.g. part cash and part QRIS
+CREATE TABLE order_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    reference VARCHAR(100),
+    change_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (change_amount >= 0 AND change_amount <= amount),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Orders settled with more than one tender record 'split' as their payment method
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_method_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_method_check
+    CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer', 'split'));
+
+-- Existing paid orders were settled with a single tender for the full total
+INSERT INTO order_payments (order_id, payment_method, amount, created_at)
+SELECT id, payment_method, total_amount, COALESCE(completed_at, updated_at)
+FROM orders
+WHERE payment_method IS NOT NULL
+AND payment_status IN ('paid', 'refunded')
+AND total_amount > 0;
+
+-- Add indexes for performance optimization
+CREATE INDEX idx_order_payments_order_id ON order_payments(order_id);
+CREATE INDEX idx_order_payments_payment_method ON order_payments(payment_method);
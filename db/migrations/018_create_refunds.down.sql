@@ -0,0 +1,8 @@
+-- Drop refunds tables
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+
+UPDATE orders SET payment_status = 'paid' WHERE payment_status = 'partially_refunded';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'failed', 'refunded'));
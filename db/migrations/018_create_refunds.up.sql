@@ -0,0 +1,36 @@
+-- Allow orders that had part of their lines returned
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_payment_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_payment_status_check
+    CHECK (payment_status IN ('pending', 'paid', 'failed', 'refunded', 'partially_refunded'));
+
+-- Create refunds table
+-- A refund returns money for some or all lines of a completed order
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount >= 0),
+    tax_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (tax_amount >= 0),
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    reference VARCHAR(100),
+    reason VARCHAR(255) NOT NULL,
+    restocked BOOLEAN NOT NULL DEFAULT TRUE,
+    refunded_by UUID NOT NULL REFERENCES users(id),
+    approved_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create refund_items table
+CREATE TABLE refund_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    refund_id UUID NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount >= 0)
+);
+
+-- Add indexes for performance optimization
+CREATE INDEX idx_refunds_order_id ON refunds(order_id);
+CREATE INDEX idx_refunds_created_at ON refunds(created_at);
+CREATE INDEX idx_refund_items_refund_id ON refund_items(refund_id);
+CREATE INDEX idx_refund_items_order_item_id ON refund_items(order_item_id);
@@ -0,0 +1,2 @@
+-- Drop per-line notes
+ALTER TABLE order_items DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,2 @@
+-- Per-line preparation notes, e.g. "less sugar" or "no ice"
+ALTER TABLE order_items ADD COLUMN notes TEXT;
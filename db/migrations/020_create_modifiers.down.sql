@@ -0,0 +1,5 @@
+-- Drop modifier tables
+DROP TABLE IF EXISTS order_item_modifiers;
+DROP TABLE IF EXISTS menu_item_modifier_groups;
+DROP TABLE IF EXISTS modifier_options;
+DROP TABLE IF EXISTS modifier_groups;
@@ -0,0 +1,50 @@
+-- Create modifier groups, e.g. "Size" (choose exactly one) or "Add-ons" (choose up to three)
+CREATE TABLE modifier_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 1 CHECK (max_select > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (max_select >= min_select)
+);
+
+-- Create modifier options with the amount they add to a line's unit price
+CREATE TABLE modifier_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    modifier_group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    is_available BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Link modifier groups to the menu items they apply to
+CREATE TABLE menu_item_modifier_groups (
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    modifier_group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (menu_item_id, modifier_group_id)
+);
+
+-- Create order item modifiers table recording the options chosen for each line.
+-- Names and prices are copied so the line reads the same after the menu changes.
+CREATE TABLE order_item_modifiers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    modifier_option_id UUID REFERENCES modifier_options(id) ON DELETE SET NULL,
+    group_name VARCHAR(100) NOT NULL,
+    option_name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_modifier_options_modifier_group_id ON modifier_options(modifier_group_id);
+CREATE INDEX idx_menu_item_modifier_groups_modifier_group_id ON menu_item_modifier_groups(modifier_group_id);
+CREATE INDEX idx_order_item_modifiers_order_item_id ON order_item_modifiers(order_item_id);
+CREATE INDEX idx_order_item_modifiers_modifier_option_id ON order_item_modifiers(modifier_option_id);
@@ -0,0 +1,4 @@
+-- Drop kitchen display tables
+DROP TABLE IF EXISTS kitchen_tickets;
+DROP TABLE IF EXISTS kitchen_station_categories;
+DROP TABLE IF EXISTS kitchen_stations;
@@ -0,0 +1,37 @@
+-- Create kitchen stations, e.g. "Bar", "Kitchen" or "Pastry"
+CREATE TABLE kitchen_stations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Route each menu category to the station that prepares it. A category goes to
+-- at most one station; lines of unrouted categories show on every display.
+CREATE TABLE kitchen_station_categories (
+    category_id UUID PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE,
+    station_id UUID NOT NULL REFERENCES kitchen_stations(id) ON DELETE CASCADE
+);
+
+-- Create kitchen tickets table tracking each order line through the kitchen,
+-- with the time it reached every stage for prep-time reporting
+CREATE TABLE kitchen_tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID UNIQUE NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    station_id UUID REFERENCES kitchen_stations(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'preparing', 'ready', 'served')),
+    queued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    preparing_at TIMESTAMP,
+    ready_at TIMESTAMP,
+    served_at TIMESTAMP,
+    bumped_by UUID REFERENCES users(id)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_kitchen_station_categories_station_id ON kitchen_station_categories(station_id);
+CREATE INDEX idx_kitchen_tickets_order_id ON kitchen_tickets(order_id);
+CREATE INDEX idx_kitchen_tickets_station_id_status ON kitchen_tickets(station_id, status);
+CREATE INDEX idx_kitchen_tickets_queued_at ON kitchen_tickets(queued_at);
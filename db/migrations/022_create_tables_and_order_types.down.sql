@@ -0,0 +1,8 @@
+-- Drop order types and tables
+DROP INDEX IF EXISTS idx_orders_table_id_status;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS guest_count,
+    DROP COLUMN IF EXISTS table_id,
+    DROP COLUMN IF EXISTS order_type;
+DROP TABLE IF EXISTS dining_tables;
+DROP TABLE IF EXISTS floor_areas;
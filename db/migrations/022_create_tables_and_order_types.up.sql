@@ -0,0 +1,33 @@
+-- Create floor areas, e.g. "Indoor", "Terrace" or "Second floor"
+CREATE TABLE floor_areas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create dining tables within floor areas. An area cannot be deleted while it has tables.
+CREATE TABLE dining_tables (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    floor_area_id UUID NOT NULL REFERENCES floor_areas(id) ON DELETE RESTRICT,
+    name VARCHAR(50) NOT NULL,
+    seats INTEGER NOT NULL DEFAULT 2 CHECK (seats > 0),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (floor_area_id, name)
+);
+
+-- Record how an order is served and, for dine-in, the table it is open on.
+-- Existing orders were all served over the counter.
+ALTER TABLE orders
+    ADD COLUMN order_type VARCHAR(20) NOT NULL DEFAULT 'takeaway' CHECK (order_type IN ('dine_in', 'takeaway', 'delivery')),
+    ADD COLUMN table_id UUID REFERENCES dining_tables(id) ON DELETE SET NULL,
+    ADD COLUMN guest_count INTEGER CHECK (guest_count > 0);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_dining_tables_floor_area_id ON dining_tables(floor_area_id);
+CREATE INDEX idx_orders_table_id_status ON orders(table_id, status);
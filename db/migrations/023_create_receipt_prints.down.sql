@@ -0,0 +1,2 @@
+-- Drop receipt prints table
+DROP TABLE IF EXISTS receipt_prints;
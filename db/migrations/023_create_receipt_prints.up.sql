@@ -0,0 +1,14 @@
+-- Create receipt prints table logging every time an order's receipt is printed,
+-- so copies after the first are marked as reprints
+CREATE TABLE receipt_prints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    printed_by UUID NOT NULL REFERENCES users(id),
+    format VARCHAR(20) NOT NULL CHECK (format IN ('escpos58', 'escpos80', 'text', 'pdf')),
+    copy_number INTEGER NOT NULL CHECK (copy_number > 0),
+    printed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, copy_number)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_receipt_prints_printed_at ON receipt_prints(printed_at);
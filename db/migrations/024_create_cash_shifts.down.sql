@@ -0,0 +1,7 @@
+-- Drop cash drawer shifts
+DROP INDEX IF EXISTS idx_orders_shift_id;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS shift_id;
+DROP TABLE IF EXISTS cash_shift_counts;
+DROP TABLE IF EXISTS cash_movements;
+DROP TABLE IF EXISTS cash_shifts;
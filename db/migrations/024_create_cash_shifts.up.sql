@@ -0,0 +1,45 @@
+-- Create cash drawer shifts. A cashier opens a shift with a float, and it is
+-- closed with the counted cash and the variance against what was expected.
+CREATE TABLE cash_shifts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float DECIMAL(12,2) NOT NULL CHECK (opening_float >= 0),
+    expected_cash DECIMAL(12,2),
+    counted_cash DECIMAL(12,2) CHECK (counted_cash >= 0),
+    variance DECIMAL(12,2),
+    notes TEXT,
+    opened_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    closed_by UUID REFERENCES users(id)
+);
+
+-- Create cash movements table for cash taken out of the drawer during a shift:
+-- drops to the safe and payouts such as buying ice or paying a supplier
+CREATE TABLE cash_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shift_id UUID NOT NULL REFERENCES cash_shifts(id) ON DELETE CASCADE,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('drop', 'payout')),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create cash shift counts table with the denominations counted at close-out
+CREATE TABLE cash_shift_counts (
+    shift_id UUID NOT NULL REFERENCES cash_shifts(id) ON DELETE CASCADE,
+    denomination DECIMAL(12,2) NOT NULL CHECK (denomination > 0),
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    PRIMARY KEY (shift_id, denomination)
+);
+
+-- Record the shift an order was paid in
+ALTER TABLE orders
+    ADD COLUMN shift_id UUID REFERENCES cash_shifts(id) ON DELETE SET NULL;
+
+-- Create indexes for performance optimization. A cashier has at most one open shift.
+CREATE UNIQUE INDEX idx_cash_shifts_open_user_id ON cash_shifts(user_id) WHERE status = 'open';
+CREATE INDEX idx_cash_shifts_opened_at ON cash_shifts(opened_at);
+CREATE INDEX idx_cash_movements_shift_id ON cash_movements(shift_id);
+CREATE INDEX idx_orders_shift_id ON orders(shift_id);
@@ -0,0 +1,4 @@
+-- Drop Z reports
+DROP TABLE IF EXISTS z_report_cashiers;
+DROP TABLE IF EXISTS z_report_payment_methods;
+DROP TABLE IF EXISTS z_reports;
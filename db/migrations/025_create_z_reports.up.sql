@@ -0,0 +1,51 @@
+-- Create Z reports table. A Z report closes the day: it covers everything since
+-- the previous Z report and is numbered and stored as generated, so it can be
+-- reprinted later with the same figures.
+CREATE TABLE z_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    report_number INTEGER NOT NULL UNIQUE CHECK (report_number > 0),
+    period_start TIMESTAMP, -- NULL for the first report, which covers everything before it
+    period_end TIMESTAMP NOT NULL,
+    generated_by UUID NOT NULL REFERENCES users(id),
+    generated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    gross_sales DECIMAL(12,2) NOT NULL,
+    discount_amount DECIMAL(12,2) NOT NULL,
+    service_charge_amount DECIMAL(12,2) NOT NULL,
+    tax_amount DECIMAL(12,2) NOT NULL,
+    rounding_amount DECIMAL(12,2) NOT NULL,
+    total_sales DECIMAL(12,2) NOT NULL,
+    refund_count INTEGER NOT NULL CHECK (refund_count >= 0),
+    refund_amount DECIMAL(12,2) NOT NULL,
+    refund_tax DECIMAL(12,2) NOT NULL,
+    cancelled_count INTEGER NOT NULL CHECK (cancelled_count >= 0),
+    voided_count INTEGER NOT NULL CHECK (voided_count >= 0),
+    voided_amount DECIMAL(12,2) NOT NULL,
+    net_sales DECIMAL(12,2) NOT NULL,
+    first_order_number VARCHAR(50),
+    last_order_number VARCHAR(50),
+    CHECK (period_start IS NULL OR period_start < period_end)
+);
+
+-- Create Z report payment method totals
+CREATE TABLE z_report_payment_methods (
+    z_report_id UUID NOT NULL REFERENCES z_reports(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    total_amount DECIMAL(12,2) NOT NULL,
+    PRIMARY KEY (z_report_id, payment_method)
+);
+
+-- Create Z report cashier totals. The cashier's name is kept as it was when the
+-- report was generated.
+CREATE TABLE z_report_cashiers (
+    z_report_id UUID NOT NULL REFERENCES z_reports(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    cashier_name VARCHAR(255) NOT NULL,
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    total_sales DECIMAL(12,2) NOT NULL,
+    PRIMARY KEY (z_report_id, user_id)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_z_reports_generated_at ON z_reports(generated_at);
@@ -0,0 +1,9 @@
+-- Drop ingredients and recipes. Ingredient stock transactions go with them.
+DROP INDEX IF EXISTS idx_stock_transactions_ingredient_id;
+DELETE FROM stock_transactions WHERE ingredient_id IS NOT NULL;
+ALTER TABLE stock_transactions
+    DROP CONSTRAINT IF EXISTS stock_transactions_item_check,
+    DROP COLUMN IF EXISTS ingredient_id,
+    ALTER COLUMN menu_item_id SET NOT NULL;
+DROP TABLE IF EXISTS recipe_items;
+DROP TABLE IF EXISTS ingredients;
@@ -0,0 +1,38 @@
+-- Create ingredients table for stock that goes into menu items, e.g. coffee
+-- beans in grams, milk in millilitres and cups in pieces
+CREATE TABLE ingredients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    current_stock INTEGER NOT NULL DEFAULT 0 CHECK (current_stock >= 0),
+    minimum_stock INTEGER NOT NULL DEFAULT 0 CHECK (minimum_stock >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_updated_by UUID REFERENCES users(id)
+);
+
+-- Create recipe_items table with the ingredients used for one unit of a menu
+-- item, or on top of it when a modifier option is chosen
+CREATE TABLE recipe_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    modifier_option_id UUID REFERENCES modifier_options(id) ON DELETE CASCADE,
+    ingredient_id UUID NOT NULL REFERENCES ingredients(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    CHECK ((menu_item_id IS NULL) <> (modifier_option_id IS NULL)),
+    UNIQUE (menu_item_id, ingredient_id),
+    UNIQUE (modifier_option_id, ingredient_id)
+);
+
+-- Stock transactions move either a menu item's finished-goods stock or an
+-- ingredient's stock
+ALTER TABLE stock_transactions
+    ALTER COLUMN menu_item_id DROP NOT NULL,
+    ADD COLUMN ingredient_id UUID REFERENCES ingredients(id),
+    ADD CONSTRAINT stock_transactions_item_check CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL));
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_ingredients_current_stock ON ingredients(current_stock);
+CREATE INDEX idx_recipe_items_ingredient_id ON recipe_items(ingredient_id);
+CREATE INDEX idx_stock_transactions_ingredient_id ON stock_transactions(ingredient_id);
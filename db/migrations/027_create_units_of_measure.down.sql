@@ -0,0 +1,43 @@
+-- Go back to whole-number stock in free-text units. Fractions are rounded.
+DROP VIEW IF EXISTS inventory_with_details;
+
+ALTER TABLE stock_transactions
+    DROP COLUMN IF EXISTS unit,
+    ALTER COLUMN quantity TYPE INTEGER USING round(quantity),
+    ALTER COLUMN previous_stock TYPE INTEGER USING round(previous_stock),
+    ALTER COLUMN current_stock TYPE INTEGER USING round(current_stock);
+
+ALTER TABLE recipe_items
+    ALTER COLUMN quantity TYPE INTEGER USING greatest(round(quantity), 1);
+
+ALTER TABLE ingredients
+    DROP COLUMN IF EXISTS purchase_unit,
+    DROP CONSTRAINT IF EXISTS ingredients_unit_fkey,
+    ALTER COLUMN current_stock TYPE INTEGER USING round(current_stock),
+    ALTER COLUMN minimum_stock TYPE INTEGER USING round(minimum_stock);
+
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS inventory_unit_fkey,
+    ALTER COLUMN unit SET DEFAULT 'pieces',
+    ALTER COLUMN current_stock TYPE INTEGER USING round(current_stock),
+    ALTER COLUMN minimum_stock TYPE INTEGER USING round(minimum_stock);
+
+DROP TABLE IF EXISTS units_of_measure;
+
+CREATE VIEW inventory_with_details AS
+SELECT
+    i.id,
+    i.menu_item_id,
+    mi.name AS menu_item_name,
+    i.current_stock,
+    i.minimum_stock,
+    i.unit,
+    i.last_updated_at,
+    u.username AS last_updated_by_username,
+    CASE
+        WHEN i.current_stock <= i.minimum_stock THEN 'LOW'
+        ELSE 'OK'
+    END AS stock_status
+FROM inventory i
+JOIN menu_items mi ON i.menu_item_id = mi.id
+LEFT JOIN users u ON i.last_updated_by = u.id;
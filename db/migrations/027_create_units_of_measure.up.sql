@@ -0,0 +1,93 @@
+-- Create units_of_measure table. A unit converts to any other unit of the same
+-- dimension through its factor, the number of base units one of it is worth,
+-- e.g. 1 l = 1000 ml.
+CREATE TABLE units_of_measure (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    dimension VARCHAR(50) NOT NULL,
+    factor NUMERIC(18,6) NOT NULL CHECK (factor > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO units_of_measure (code, name, dimension, factor) VALUES
+    ('mg', 'Milligram', 'mass', 0.001),
+    ('g', 'Gram', 'mass', 1),
+    ('kg', 'Kilogram', 'mass', 1000),
+    ('ml', 'Millilitre', 'volume', 1),
+    ('l', 'Litre', 'volume', 1000),
+    ('pcs', 'Piece', 'count', 1),
+    ('dozen', 'Dozen', 'count', 12);
+
+-- Map the common spellings of the free-text units onto the registry
+UPDATE inventory SET unit = CASE lower(unit)
+    WHEN 'pieces' THEN 'pcs' WHEN 'piece' THEN 'pcs' WHEN 'pc' THEN 'pcs'
+    WHEN 'gram' THEN 'g' WHEN 'grams' THEN 'g'
+    WHEN 'kilogram' THEN 'kg' WHEN 'kilograms' THEN 'kg'
+    WHEN 'millilitre' THEN 'ml' WHEN 'milliliter' THEN 'ml'
+    WHEN 'litre' THEN 'l' WHEN 'liter' THEN 'l' WHEN 'liters' THEN 'l' WHEN 'litres' THEN 'l'
+    ELSE unit END;
+UPDATE ingredients SET unit = CASE lower(unit)
+    WHEN 'pieces' THEN 'pcs' WHEN 'piece' THEN 'pcs' WHEN 'pc' THEN 'pcs'
+    WHEN 'gram' THEN 'g' WHEN 'grams' THEN 'g'
+    WHEN 'kilogram' THEN 'kg' WHEN 'kilograms' THEN 'kg'
+    WHEN 'millilitre' THEN 'ml' WHEN 'milliliter' THEN 'ml'
+    WHEN 'litre' THEN 'l' WHEN 'liter' THEN 'l' WHEN 'liters' THEN 'l' WHEN 'litres' THEN 'l'
+    ELSE unit END;
+
+-- Any other unit in use becomes a unit of its own that converts only to itself
+INSERT INTO units_of_measure (code, name, dimension, factor)
+SELECT DISTINCT unit, unit, unit, 1
+FROM (SELECT unit FROM inventory UNION SELECT unit FROM ingredients) AS used
+WHERE unit NOT IN (SELECT code FROM units_of_measure);
+
+-- Stock is counted in decimals, e.g. 1.5 kg of beans
+DROP VIEW IF EXISTS inventory_with_details;
+
+ALTER TABLE inventory
+    ALTER COLUMN current_stock TYPE NUMERIC(12,3),
+    ALTER COLUMN minimum_stock TYPE NUMERIC(12,3),
+    ALTER COLUMN unit SET DEFAULT 'pcs',
+    ADD CONSTRAINT inventory_unit_fkey FOREIGN KEY (unit) REFERENCES units_of_measure(code);
+
+ALTER TABLE ingredients
+    ALTER COLUMN current_stock TYPE NUMERIC(12,3),
+    ALTER COLUMN minimum_stock TYPE NUMERIC(12,3),
+    ADD CONSTRAINT ingredients_unit_fkey FOREIGN KEY (unit) REFERENCES units_of_measure(code),
+    ADD COLUMN purchase_unit VARCHAR(50) REFERENCES units_of_measure(code);
+
+-- Recipe quantities are kept in the ingredient's stock unit
+ALTER TABLE recipe_items
+    ALTER COLUMN quantity TYPE NUMERIC(12,3);
+
+-- Stock transactions record their quantities in the stock unit of what moved
+ALTER TABLE stock_transactions
+    ALTER COLUMN quantity TYPE NUMERIC(12,3),
+    ALTER COLUMN previous_stock TYPE NUMERIC(12,3),
+    ALTER COLUMN current_stock TYPE NUMERIC(12,3),
+    ADD COLUMN unit VARCHAR(50) REFERENCES units_of_measure(code);
+
+UPDATE stock_transactions st SET unit = i.unit
+FROM inventory i
+WHERE st.menu_item_id = i.menu_item_id;
+
+UPDATE stock_transactions st SET unit = ing.unit
+FROM ingredients ing
+WHERE st.ingredient_id = ing.id;
+
+CREATE VIEW inventory_with_details AS
+SELECT
+    i.id,
+    i.menu_item_id,
+    mi.name AS menu_item_name,
+    i.current_stock,
+    i.minimum_stock,
+    i.unit,
+    i.last_updated_at,
+    u.username AS last_updated_by_username,
+    CASE
+        WHEN i.current_stock <= i.minimum_stock THEN 'LOW'
+        ELSE 'OK'
+    END AS stock_status
+FROM inventory i
+JOIN menu_items mi ON i.menu_item_id = mi.id
+LEFT JOIN users u ON i.last_updated_by = u.id;
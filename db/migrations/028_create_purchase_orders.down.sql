@@ -0,0 +1,10 @@
+-- Drop purchase orders and suppliers. Stock received against them stays, as do
+-- the expenses recorded for it.
+ALTER TABLE ingredients DROP COLUMN IF EXISTS cost_price;
+DROP TABLE IF EXISTS goods_received_note_items;
+DROP TABLE IF EXISTS goods_received_notes;
+DROP TABLE IF EXISTS purchase_order_items;
+DROP TABLE IF EXISTS purchase_orders;
+DROP SEQUENCE IF EXISTS goods_received_note_number_seq;
+DROP SEQUENCE IF EXISTS purchase_order_number_seq;
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,82 @@
+-- Create suppliers table for the businesses stock is bought from
+CREATE TABLE suppliers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    contact_name VARCHAR(100),
+    phone VARCHAR(50),
+    email VARCHAR(100),
+    address TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Purchase orders and goods received notes are numbered from their own sequences
+CREATE SEQUENCE purchase_order_number_seq;
+CREATE SEQUENCE goods_received_note_number_seq;
+
+-- Create purchase_orders table. An order is drafted, sent to the supplier and
+-- then received in one or more deliveries.
+CREATE TABLE purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    po_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'PO-' || LPAD(nextval('purchase_order_number_seq')::TEXT, 6, '0'),
+    supplier_id UUID NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+    expected_date DATE,
+    notes TEXT,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    created_by UUID NOT NULL REFERENCES users(id),
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create purchase_order_items table with the menu items (finished goods) or
+-- ingredients ordered, in any unit compatible with their stock unit
+CREATE TABLE purchase_order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    received_quantity NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_cost DECIMAL(12,2) NOT NULL CHECK (unit_cost >= 0),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Create goods_received_notes table, one per delivery against a purchase order
+CREATE TABLE goods_received_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    grn_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'GRN-' || LPAD(nextval('goods_received_note_number_seq')::TEXT, 6, '0'),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id),
+    total_amount DECIMAL(12,2) NOT NULL CHECK (total_amount >= 0),
+    expense_id UUID REFERENCES expenses(id) ON DELETE SET NULL,
+    notes TEXT,
+    received_by UUID NOT NULL REFERENCES users(id),
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create goods_received_note_items table with the quantity received of each
+-- purchase order line, in the line's unit and converted to the stock unit
+CREATE TABLE goods_received_note_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    goods_received_note_id UUID NOT NULL REFERENCES goods_received_notes(id) ON DELETE CASCADE,
+    purchase_order_item_id UUID NOT NULL REFERENCES purchase_order_items(id),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    stock_quantity NUMERIC(12,3) NOT NULL CHECK (stock_quantity > 0),
+    unit_cost DECIMAL(12,2) NOT NULL CHECK (unit_cost >= 0)
+);
+
+-- Ingredients keep the cost of one stock unit from their latest delivery
+ALTER TABLE ingredients
+    ADD COLUMN cost_price NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (cost_price >= 0);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX idx_purchase_orders_created_at ON purchase_orders(created_at);
+CREATE INDEX idx_purchase_order_items_purchase_order_id ON purchase_order_items(purchase_order_id);
+CREATE INDEX idx_goods_received_notes_purchase_order_id ON goods_received_notes(purchase_order_id);
+CREATE INDEX idx_goods_received_note_items_note_id ON goods_received_note_items(goods_received_note_id);
@@ -0,0 +1,4 @@
+-- Drop stock takes. Adjustments already posted from them stay in stock_transactions.
+DROP TABLE IF EXISTS stock_take_lines;
+DROP TABLE IF EXISTS stock_takes;
+DROP SEQUENCE IF EXISTS stock_take_number_seq;
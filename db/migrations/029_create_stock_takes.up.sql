@@ -0,0 +1,42 @@
+-- Stock takes are numbered from their own sequence
+CREATE SEQUENCE stock_take_number_seq;
+
+-- Create stock_takes table for counting the shelf against the system stock. A
+-- stock take may cover every item or be a cycle count of one menu category.
+CREATE TABLE stock_takes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_take_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'ST-' || LPAD(nextval('stock_take_number_seq')::TEXT, 6, '0'),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    category_id UUID REFERENCES categories(id),
+    include_menu_items BOOLEAN NOT NULL DEFAULT true,
+    include_ingredients BOOLEAN NOT NULL DEFAULT true,
+    notes TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    approved_by UUID REFERENCES users(id),
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create stock_take_lines table with the system quantity and cost of each item
+-- when the stock take started, and the quantity counted on the shelf
+CREATE TABLE stock_take_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_take_id UUID NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    system_quantity NUMERIC(12,3) NOT NULL,
+    counted_quantity NUMERIC(12,3) CHECK (counted_quantity >= 0),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    counted_by UUID REFERENCES users(id),
+    counted_at TIMESTAMP,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    UNIQUE (stock_take_id, menu_item_id),
+    UNIQUE (stock_take_id, ingredient_id)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_stock_takes_status ON stock_takes(status);
+CREATE INDEX idx_stock_takes_created_at ON stock_takes(created_at);
+CREATE INDEX idx_stock_take_lines_stock_take_id ON stock_take_lines(stock_take_id);
@@ -0,0 +1,2 @@
+-- Drop waste records. The stock they used up stays recorded in stock_transactions.
+DROP TABLE IF EXISTS waste_records;
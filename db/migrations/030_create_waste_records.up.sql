@@ -0,0 +1,22 @@
+-- Create waste_records table for stock thrown away, eaten by staff or given
+-- away, each valued at cost. A wasted menu item made from a recipe uses up
+-- its ingredients; one stocked as finished goods uses up its own stock.
+CREATE TABLE waste_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('expired', 'damaged', 'staff_meal', 'comp')),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    total_cost NUMERIC(12,2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    user_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_waste_records_created_at ON waste_records(created_at);
+CREATE INDEX idx_waste_records_reason ON waste_records(reason);
+CREATE INDEX idx_waste_records_user_id ON waste_records(user_id);
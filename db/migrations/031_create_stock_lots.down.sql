@@ -0,0 +1,2 @@
+-- Drop stock lots. Stock levels stay in inventory and ingredients.
+DROP TABLE IF EXISTS stock_lots;
@@ -0,0 +1,25 @@
+-- Create stock_lots table so stock is held in lots, each with the date it was
+-- received, when it expires and what it cost. Stock taken out of a menu item
+-- or ingredient is taken from its lots first-expiring first, falling back to
+-- first-received first for lots that do not expire.
+CREATE TABLE stock_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    lot_number VARCHAR(100),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity_received NUMERIC(12,3) NOT NULL CHECK (quantity_received > 0),
+    quantity_remaining NUMERIC(12,3) NOT NULL CHECK (quantity_remaining >= 0),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    goods_received_note_id UUID REFERENCES goods_received_notes(id),
+    received_by UUID REFERENCES users(id),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    CHECK (quantity_remaining <= quantity_received)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_stock_lots_menu_item_open ON stock_lots(menu_item_id, expires_at, received_at) WHERE quantity_remaining > 0;
+CREATE INDEX idx_stock_lots_ingredient_open ON stock_lots(ingredient_id, expires_at, received_at) WHERE quantity_remaining > 0;
+CREATE INDEX idx_stock_lots_expires_at ON stock_lots(expires_at) WHERE quantity_remaining > 0;
@@ -0,0 +1,8 @@
+-- Drop stock costing. Item costs stay in menu_items.cost and ingredients.cost_price.
+DROP INDEX IF EXISTS idx_stock_transactions_reference_type_created_at;
+ALTER TABLE stock_transactions ALTER COLUMN created_at SET DEFAULT NOW();
+ALTER TABLE stock_transactions DROP COLUMN IF EXISTS average_cost;
+ALTER TABLE stock_transactions DROP COLUMN IF EXISTS total_cost;
+ALTER TABLE stock_transactions DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE ingredients DROP COLUMN IF EXISTS average_cost;
+ALTER TABLE inventory DROP COLUMN IF EXISTS average_cost;
@@ -0,0 +1,33 @@
+-- Keep a weighted average cost on every item held in stock, and value every
+-- stock movement at it. Stock coming in at a known cost, such as a delivery,
+-- moves the average towards that cost; everything else moves at the average.
+ALTER TABLE inventory ADD COLUMN average_cost NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (average_cost >= 0);
+ALTER TABLE ingredients ADD COLUMN average_cost NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (average_cost >= 0);
+
+-- Stock already held is valued at the item's current cost
+UPDATE inventory i SET average_cost = mi.cost FROM menu_items mi WHERE mi.id = i.menu_item_id;
+UPDATE ingredients SET average_cost = cost_price;
+
+-- unit_cost is what one stock unit moved at, total_cost the signed value of the
+-- movement and average_cost the weighted average cost of the stock left after it
+ALTER TABLE stock_transactions ADD COLUMN unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0;
+ALTER TABLE stock_transactions ADD COLUMN total_cost NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE stock_transactions ADD COLUMN average_cost NUMERIC(12,4) NOT NULL DEFAULT 0;
+
+-- Movements recorded before costing are valued at the item's current cost
+UPDATE stock_transactions st
+SET unit_cost = i.average_cost, average_cost = i.average_cost, total_cost = ROUND(st.quantity * i.average_cost, 2)
+FROM inventory i
+WHERE st.menu_item_id = i.menu_item_id;
+
+UPDATE stock_transactions st
+SET unit_cost = ig.average_cost, average_cost = ig.average_cost, total_cost = ROUND(st.quantity * ig.average_cost, 2)
+FROM ingredients ig
+WHERE st.ingredient_id = ig.id;
+
+-- Stock is valued as of a point in time from the last movement before it, so
+-- movements made in the same database transaction need distinct times
+ALTER TABLE stock_transactions ALTER COLUMN created_at SET DEFAULT clock_timestamp();
+
+-- Add an index for costing sales over a date range
+CREATE INDEX idx_stock_transactions_reference_type_created_at ON stock_transactions(reference_type, created_at);
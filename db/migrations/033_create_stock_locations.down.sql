@@ -0,0 +1,8 @@
+-- Drop stock locations. Each item's stock stays in inventory and ingredients.
+DROP TABLE IF EXISTS stock_transfer_lines;
+DROP TABLE IF EXISTS stock_transfers;
+DROP SEQUENCE IF EXISTS stock_transfer_number_seq;
+DROP INDEX IF EXISTS idx_stock_transactions_location_id;
+ALTER TABLE stock_transactions DROP COLUMN IF EXISTS location_id;
+DROP TABLE IF EXISTS stock_balances;
+DROP TABLE IF EXISTS stock_locations;
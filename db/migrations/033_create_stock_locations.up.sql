@@ -0,0 +1,85 @@
+-- Stock is kept in several places, such as the back store room, the bar fridge
+-- and another outlet's warehouse. inventory and ingredients keep each item's
+-- stock across all of them and stock_balances splits it by location.
+CREATE TABLE stock_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    is_selling BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (is_active OR NOT is_selling)
+);
+
+-- Sales are made from exactly one location
+CREATE UNIQUE INDEX idx_stock_locations_selling ON stock_locations(is_selling) WHERE is_selling;
+
+INSERT INTO stock_locations (code, name, is_selling) VALUES ('MAIN', 'Main store', true);
+
+-- Create stock_balances table with the stock of each item at each location,
+-- in the item's stock unit
+CREATE TABLE stock_balances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    location_id UUID NOT NULL REFERENCES stock_locations(id),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id UUID REFERENCES ingredients(id) ON DELETE CASCADE,
+    quantity NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_stock_balances_location_menu_item ON stock_balances(location_id, menu_item_id) WHERE menu_item_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_stock_balances_location_ingredient ON stock_balances(location_id, ingredient_id) WHERE ingredient_id IS NOT NULL;
+
+-- Stock already held is at the selling location
+INSERT INTO stock_balances (location_id, menu_item_id, quantity)
+SELECT l.id, i.menu_item_id, i.current_stock
+FROM inventory i, stock_locations l
+WHERE l.is_selling AND i.current_stock > 0;
+
+INSERT INTO stock_balances (location_id, ingredient_id, quantity)
+SELECT l.id, ig.id, ig.current_stock
+FROM ingredients ig, stock_locations l
+WHERE l.is_selling AND ig.current_stock > 0;
+
+-- Every stock movement happens at a location
+ALTER TABLE stock_transactions ADD COLUMN location_id UUID REFERENCES stock_locations(id);
+UPDATE stock_transactions SET location_id = (SELECT id FROM stock_locations WHERE is_selling);
+CREATE INDEX idx_stock_transactions_location_id ON stock_transactions(location_id);
+
+-- Stock transfers are numbered from their own sequence
+CREATE SEQUENCE stock_transfer_number_seq;
+
+-- Create stock_transfers table for moving stock from one location to another
+CREATE TABLE stock_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'TR-' || LPAD(nextval('stock_transfer_number_seq')::TEXT, 6, '0'),
+    from_location_id UUID NOT NULL REFERENCES stock_locations(id),
+    to_location_id UUID NOT NULL REFERENCES stock_locations(id),
+    notes TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (from_location_id <> to_location_id)
+);
+
+-- Create stock_transfer_lines table with the quantity of each item moved, in
+-- the item's stock unit
+CREATE TABLE stock_transfer_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_transfer_id UUID NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    UNIQUE (stock_transfer_id, menu_item_id),
+    UNIQUE (stock_transfer_id, ingredient_id)
+);
+
+-- Create indexes for performance optimization
+CREATE INDEX idx_stock_transfers_from_location_id ON stock_transfers(from_location_id);
+CREATE INDEX idx_stock_transfers_to_location_id ON stock_transfers(to_location_id);
+CREATE INDEX idx_stock_transfers_created_at ON stock_transfers(created_at);
+CREATE INDEX idx_stock_transfer_lines_stock_transfer_id ON stock_transfer_lines(stock_transfer_id);
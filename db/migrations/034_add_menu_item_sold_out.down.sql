@@ -0,0 +1,3 @@
+-- Drop sold out tracking. Items sold out stay off the menu until put back by hand.
+ALTER TABLE menu_items DROP CONSTRAINT IF EXISTS menu_items_sold_out_unavailable;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS sold_out;
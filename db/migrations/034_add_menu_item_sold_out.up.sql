@@ -0,0 +1,7 @@
+-- A menu item is sold out when the selling location no longer holds enough
+-- stock, or enough of each ingredient in its recipe, to make one. Selling out
+-- takes the item off the menu (is_available = false) and restocking puts it
+-- back; sold_out tells the two apart from an item taken off the menu by hand,
+-- which restocking leaves alone.
+ALTER TABLE menu_items ADD COLUMN sold_out BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE menu_items ADD CONSTRAINT menu_items_sold_out_unavailable CHECK (NOT (sold_out AND is_available));
@@ -0,0 +1,3 @@
+-- Drop low stock alerts
+DROP TABLE IF EXISTS low_stock_alert_deliveries;
+DROP TABLE IF EXISTS low_stock_alerts;
@@ -0,0 +1,39 @@
+-- A low stock alert is raised when a stock movement takes a menu item or an
+-- ingredient from above its minimum stock to it or below, and stays open
+-- until the item is restocked above it. An item is alerted at most once while
+-- it is low, however often it sells.
+CREATE TABLE low_stock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id UUID REFERENCES ingredients(id) ON DELETE CASCADE,
+    item_name VARCHAR(255) NOT NULL,
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    current_stock NUMERIC(12,3) NOT NULL,
+    minimum_stock NUMERIC(12,3) NOT NULL,
+    stock_transaction_id UUID NOT NULL REFERENCES stock_transactions(id),
+    raised_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP,
+    read_at TIMESTAMP,
+    read_by UUID REFERENCES users(id),
+    -- Delivery through the notification channels, retried with backoff
+    dispatched_at TIMESTAMP,
+    dispatch_attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Each item has at most one open alert
+CREATE UNIQUE INDEX idx_low_stock_alerts_open_menu_item ON low_stock_alerts(menu_item_id) WHERE menu_item_id IS NOT NULL AND resolved_at IS NULL;
+CREATE UNIQUE INDEX idx_low_stock_alerts_open_ingredient ON low_stock_alerts(ingredient_id) WHERE ingredient_id IS NOT NULL AND resolved_at IS NULL;
+CREATE INDEX idx_low_stock_alerts_raised_at ON low_stock_alerts(raised_at);
+CREATE INDEX idx_low_stock_alerts_undispatched ON low_stock_alerts(next_attempt_at) WHERE dispatched_at IS NULL;
+
+-- Create low_stock_alert_deliveries table with the channels an alert has been
+-- delivered through, so a retry only goes to the channels that failed
+CREATE TABLE low_stock_alert_deliveries (
+    alert_id UUID NOT NULL REFERENCES low_stock_alerts(id) ON DELETE CASCADE,
+    channel VARCHAR(50) NOT NULL,
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (alert_id, channel)
+);
@@ -0,0 +1,4 @@
+-- Drop refund shifts
+DROP INDEX IF EXISTS idx_refunds_shift_id;
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS shift_id;
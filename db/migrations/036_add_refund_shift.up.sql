@@ -0,0 +1,6 @@
+-- Record the shift a refund was paid in, so cash handed back from the drawer
+-- comes out of the cash the shift is expected to hold
+ALTER TABLE refunds
+    ADD COLUMN shift_id UUID REFERENCES cash_shifts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_refunds_shift_id ON refunds(shift_id);
@@ -0,0 +1,4 @@
+UPDATE orders SET status = 'cancelled' WHERE status = 'merged';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('draft', 'pending', 'completed', 'cancelled'));
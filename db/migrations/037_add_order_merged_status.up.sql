@@ -0,0 +1,8 @@
+-- An order merged into another is closed as merged rather than cancelled, so
+-- merging tabs does not show up as cancellations in sales reports
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('draft', 'pending', 'completed', 'cancelled', 'merged'));
+
+UPDATE orders SET status = 'merged'
+WHERE status = 'cancelled' AND cancellation_reason LIKE 'Merged into order %';
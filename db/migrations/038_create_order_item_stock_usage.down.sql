@@ -0,0 +1 @@
+DROP TABLE IF EXISTS order_item_stock_usage;
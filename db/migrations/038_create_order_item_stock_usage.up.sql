@@ -0,0 +1,12 @@
+-- Record the stock each order line took when its order was completed, so a
+-- refund puts back what the sale took even if recipes have changed since
+CREATE TABLE order_item_stock_usage (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+CREATE INDEX idx_order_item_stock_usage_order_item_id ON order_item_stock_usage(order_item_id);
@@ -0,0 +1,4 @@
+-- Drop order void shifts
+DROP INDEX IF EXISTS idx_orders_void_shift_id;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS void_shift_id;
@@ -0,0 +1,6 @@
+-- Record the shift a completed order was voided in, so cash handed back from
+-- the drawer comes out of the cash the shift is expected to hold
+ALTER TABLE orders
+    ADD COLUMN void_shift_id UUID REFERENCES cash_shifts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_orders_void_shift_id ON orders(void_shift_id);
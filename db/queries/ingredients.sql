@@ -0,0 +1,45 @@
+-- name: CreateIngredient :one
+INSERT INTO ingredients (
+    name, unit, minimum_stock, is_active, purchase_unit
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost;
+
+-- name: GetIngredient :one
+SELECT id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost
+FROM ingredients
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListIngredients :many
+SELECT id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost
+FROM ingredients
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+  AND (NOT sqlc.arg('low_stock_only')::boolean OR current_stock <= minimum_stock)
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateIngredient :one
+UPDATE ingredients
+SET name = $2, unit = $3, minimum_stock = $4, is_active = $5, purchase_unit = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost;
+
+-- name: AdjustIngredientStock :one
+-- Applies a relative stock change atomically; returns no row when the
+-- change would take the stock below zero
+UPDATE ingredients
+SET current_stock = current_stock + sqlc.arg(quantity)::numeric,
+    updated_at = NOW(),
+    last_updated_by = sqlc.arg(last_updated_by)
+WHERE id = sqlc.arg(id)
+  AND current_stock + sqlc.arg(quantity)::numeric >= 0
+RETURNING id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost;
+
+-- name: UpdateIngredientCostPrice :one
+-- Records the cost of one stock unit from the latest delivery
+UPDATE ingredients
+SET cost_price = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, unit, current_stock, minimum_stock, is_active, created_at, updated_at, last_updated_by, purchase_unit, cost_price, average_cost;
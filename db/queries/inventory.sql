@@ -21,4 +21,15 @@ WHERE menu_item_id = $1;
 
 -- name: CreateInventoryRecord :exec
 INSERT INTO inventory (menu_item_id, current_stock, minimum_stock, unit)
-VALUES ($1, 0, 0, 'pieces');
\ No newline at end of file
+VALUES ($1, 0, 0, 'pcs');
+
+-- name: AdjustInventoryStock :one
+-- Applies a relative stock change atomically; returns no row when the
+-- change would take the stock below zero
+UPDATE inventory
+SET current_stock = current_stock + sqlc.arg(quantity)::numeric,
+    last_updated_at = NOW(),
+    last_updated_by = sqlc.arg(last_updated_by)
+WHERE menu_item_id = sqlc.arg(menu_item_id)
+  AND current_stock + sqlc.arg(quantity)::numeric >= 0
+RETURNING id, menu_item_id, current_stock, minimum_stock, unit, last_updated_at, last_updated_by;
@@ -0,0 +1,148 @@
+-- name: CreateKitchenStation :one
+INSERT INTO kitchen_stations (
+    name, description, is_active
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, name, description, is_active, created_at, updated_at;
+
+-- name: GetKitchenStation :one
+SELECT id, name, description, is_active, created_at, updated_at
+FROM kitchen_stations
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListKitchenStations :many
+SELECT id, name, description, is_active, created_at, updated_at
+FROM kitchen_stations
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+ORDER BY name;
+
+-- name: UpdateKitchenStation :one
+UPDATE kitchen_stations
+SET name = $2, description = $3, is_active = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, description, is_active, created_at, updated_at;
+
+-- name: DeleteKitchenStation :exec
+DELETE FROM kitchen_stations
+WHERE id = $1;
+
+-- name: AssignCategoryToStation :exec
+-- A category is routed to one station, so assigning it moves it from any other
+INSERT INTO kitchen_station_categories (
+    category_id, station_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (category_id) DO UPDATE SET station_id = EXCLUDED.station_id;
+
+-- name: ClearStationCategories :exec
+DELETE FROM kitchen_station_categories
+WHERE station_id = $1;
+
+-- name: ListCategoryIDsByStationID :many
+SELECT category_id
+FROM kitchen_station_categories
+WHERE station_id = $1
+ORDER BY category_id;
+
+-- name: CreateKitchenTicketsForOrder :many
+-- Sends the order's lines that are not in the kitchen yet to the active station
+-- their category is routed to
+INSERT INTO kitchen_tickets (order_id, order_item_id, station_id)
+SELECT oi.order_id, oi.id, ks.id
+FROM order_items oi
+JOIN menu_items mi ON mi.id = oi.menu_item_id
+LEFT JOIN kitchen_station_categories ksc ON ksc.category_id = mi.category_id
+LEFT JOIN kitchen_stations ks ON ks.id = ksc.station_id AND ks.is_active = true
+WHERE oi.order_id = $1
+  AND NOT EXISTS (SELECT 1 FROM kitchen_tickets kt WHERE kt.order_item_id = oi.id)
+ON CONFLICT (order_item_id) DO NOTHING
+RETURNING id;
+
+-- name: GetKitchenTicket :one
+SELECT kt.id, kt.order_id, o.order_number, kt.order_item_id, kt.station_id, ks.name AS station_name,
+       mi.name AS menu_item_name, oi.quantity, oi.notes,
+       COALESCE((SELECT string_agg(oim.option_name, ', ' ORDER BY oim.created_at)
+                 FROM order_item_modifiers oim
+                 WHERE oim.order_item_id = kt.order_item_id), '')::text AS modifiers,
+       kt.status, kt.queued_at, kt.preparing_at, kt.ready_at, kt.served_at, kt.bumped_by
+FROM kitchen_tickets kt
+JOIN orders o ON o.id = kt.order_id
+JOIN order_items oi ON oi.id = kt.order_item_id
+JOIN menu_items mi ON mi.id = oi.menu_item_id
+LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+WHERE kt.id = $1
+LIMIT 1;
+
+-- name: ListKitchenTickets :many
+-- Without a status filter only lines still in the kitchen are listed. Lines with
+-- no station show on every station's display.
+SELECT kt.id, kt.order_id, o.order_number, kt.order_item_id, kt.station_id, ks.name AS station_name,
+       mi.name AS menu_item_name, oi.quantity, oi.notes,
+       COALESCE((SELECT string_agg(oim.option_name, ', ' ORDER BY oim.created_at)
+                 FROM order_item_modifiers oim
+                 WHERE oim.order_item_id = kt.order_item_id), '')::text AS modifiers,
+       kt.status, kt.queued_at, kt.preparing_at, kt.ready_at, kt.served_at, kt.bumped_by
+FROM kitchen_tickets kt
+JOIN orders o ON o.id = kt.order_id
+JOIN order_items oi ON oi.id = kt.order_item_id
+JOIN menu_items mi ON mi.id = oi.menu_item_id
+LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+WHERE (sqlc.narg('station_id')::uuid IS NULL OR kt.station_id = sqlc.narg('station_id')::uuid OR kt.station_id IS NULL)
+  AND (sqlc.narg('order_id')::uuid IS NULL OR kt.order_id = sqlc.narg('order_id')::uuid)
+  AND ((sqlc.narg('status')::varchar IS NULL AND kt.status <> 'served') OR kt.status = sqlc.narg('status')::varchar)
+ORDER BY kt.queued_at, o.order_number
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: BumpKitchenTicket :one
+-- Moves a line on from the status it was read in, stamping the time it reached
+-- the new one. No row is returned if someone else bumped it first.
+UPDATE kitchen_tickets
+SET status = sqlc.arg('status')::varchar,
+    preparing_at = CASE WHEN sqlc.arg('status')::varchar = 'preparing' THEN NOW() ELSE preparing_at END,
+    ready_at = CASE WHEN sqlc.arg('status')::varchar = 'ready' THEN NOW() ELSE ready_at END,
+    served_at = CASE WHEN sqlc.arg('status')::varchar = 'served' THEN NOW() ELSE served_at END,
+    bumped_by = sqlc.arg('bumped_by')
+WHERE id = sqlc.arg('id') AND status = sqlc.arg('current_status')::varchar
+RETURNING id;
+
+-- name: MoveKitchenTicket :exec
+UPDATE kitchen_tickets
+SET order_id = $2
+WHERE order_item_id = $1;
+
+-- name: CopyKitchenTicket :exec
+-- Gives a line split off another the same place in the kitchen as the original
+INSERT INTO kitchen_tickets (
+    order_id, order_item_id, station_id, status, queued_at, preparing_at, ready_at, served_at, bumped_by
+)
+SELECT oi.order_id, oi.id, kt.station_id, kt.status, kt.queued_at, kt.preparing_at, kt.ready_at, kt.served_at, kt.bumped_by
+FROM kitchen_tickets kt
+JOIN order_items oi ON oi.id = sqlc.arg('order_item_id')::uuid
+WHERE kt.order_item_id = sqlc.arg('source_order_item_id')
+ON CONFLICT (order_item_id) DO NOTHING;
+
+-- name: DeleteOpenKitchenTicketsByOrderID :exec
+DELETE FROM kitchen_tickets
+WHERE order_id = $1 AND status <> 'served';
+
+-- name: GetPrepTimesByDateRange :many
+-- Time from a line reaching the kitchen to it being ready (or served, for lines
+-- bumped straight through), per station and menu item
+SELECT COALESCE(ks.name, 'Unassigned')::varchar AS station_name,
+       mi.name AS menu_item_name,
+       COUNT(*) AS tickets,
+       SUM(oi.quantity) AS total_quantity,
+       AVG(EXTRACT(EPOCH FROM (COALESCE(kt.ready_at, kt.served_at) - kt.queued_at)))::float8 AS avg_prep_seconds,
+       MAX(EXTRACT(EPOCH FROM (COALESCE(kt.ready_at, kt.served_at) - kt.queued_at)))::float8 AS max_prep_seconds,
+       COALESCE(AVG(EXTRACT(EPOCH FROM (kt.served_at - kt.queued_at))), 0)::float8 AS avg_serve_seconds
+FROM kitchen_tickets kt
+JOIN order_items oi ON oi.id = kt.order_item_id
+JOIN menu_items mi ON mi.id = oi.menu_item_id
+LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+WHERE kt.queued_at >= sqlc.arg('start_date') AND kt.queued_at <= sqlc.arg('end_date')
+  AND COALESCE(kt.ready_at, kt.served_at) IS NOT NULL
+GROUP BY ks.name, mi.name
+ORDER BY station_name, avg_prep_seconds DESC;
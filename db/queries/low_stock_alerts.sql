@@ -0,0 +1,102 @@
+-- name: RaiseLowStockAlert :many
+-- Opens an alert for a menu item or an ingredient that a stock movement took
+-- from above its minimum stock to it or below. Items without a minimum stock
+-- and items that already have an open alert are left alone. Returns the alert
+-- raised, if any.
+INSERT INTO low_stock_alerts (
+    menu_item_id, ingredient_id, item_name, unit, current_stock, minimum_stock, stock_transaction_id
+)
+SELECT i.menu_item_id, NULL::uuid, mi.name, i.unit, i.current_stock, i.minimum_stock, sqlc.arg('stock_transaction_id')::uuid
+FROM inventory i
+JOIN menu_items mi ON i.menu_item_id = mi.id
+WHERE i.menu_item_id = sqlc.narg('menu_item_id')
+  AND i.minimum_stock > 0
+  AND i.current_stock <= i.minimum_stock
+  AND sqlc.arg('previous_stock')::numeric > i.minimum_stock
+UNION ALL
+SELECT NULL::uuid, ig.id, ig.name, ig.unit, ig.current_stock, ig.minimum_stock, sqlc.arg('stock_transaction_id')::uuid
+FROM ingredients ig
+WHERE ig.id = sqlc.narg('ingredient_id')
+  AND ig.minimum_stock > 0
+  AND ig.current_stock <= ig.minimum_stock
+  AND sqlc.arg('previous_stock')::numeric > ig.minimum_stock
+ON CONFLICT DO NOTHING
+RETURNING id, menu_item_id, ingredient_id, item_name, unit, current_stock, minimum_stock, stock_transaction_id,
+          raised_at, resolved_at, read_at, read_by, dispatched_at, dispatch_attempts, next_attempt_at, last_error;
+
+-- name: ResolveLowStockAlerts :exec
+-- Closes the open alert of a menu item or an ingredient once it is back above
+-- its minimum stock, or no longer has one
+UPDATE low_stock_alerts a
+SET resolved_at = NOW()
+WHERE a.resolved_at IS NULL
+  AND ((a.menu_item_id = sqlc.narg('menu_item_id') AND EXISTS (
+          SELECT 1 FROM inventory i
+          WHERE i.menu_item_id = a.menu_item_id AND (i.minimum_stock = 0 OR i.current_stock > i.minimum_stock)
+       ))
+    OR (a.ingredient_id = sqlc.narg('ingredient_id') AND EXISTS (
+          SELECT 1 FROM ingredients ig
+          WHERE ig.id = a.ingredient_id AND (ig.minimum_stock = 0 OR ig.current_stock > ig.minimum_stock)
+       )));
+
+-- name: ListLowStockAlerts :many
+SELECT id, menu_item_id, ingredient_id, item_name, unit, current_stock, minimum_stock, stock_transaction_id,
+       raised_at, resolved_at, read_at, read_by, dispatched_at, dispatch_attempts, next_attempt_at, last_error
+FROM low_stock_alerts
+WHERE (NOT sqlc.arg('open_only')::boolean OR resolved_at IS NULL)
+  AND (NOT sqlc.arg('unread_only')::boolean OR read_at IS NULL)
+ORDER BY raised_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadLowStockAlerts :one
+SELECT COUNT(*) FROM low_stock_alerts WHERE read_at IS NULL;
+
+-- name: MarkLowStockAlertRead :one
+-- Marks an alert read, keeping who read it first
+UPDATE low_stock_alerts
+SET read_at = COALESCE(read_at, NOW()), read_by = COALESCE(read_by, $2)
+WHERE id = $1
+RETURNING id, menu_item_id, ingredient_id, item_name, unit, current_stock, minimum_stock, stock_transaction_id,
+          raised_at, resolved_at, read_at, read_by, dispatched_at, dispatch_attempts, next_attempt_at, last_error;
+
+-- name: ClaimLowStockAlerts :many
+-- Claims the alerts due to be delivered through the notification channels.
+-- Claiming pushes the next attempt back, 1, 2, 4, 8... minutes, so an alert
+-- is not claimed again while it is being delivered, nor retried straight away
+-- when delivery fails. Alerts claimed by another server are skipped, and so
+-- are alerts for items restocked before they were delivered.
+UPDATE low_stock_alerts
+SET dispatch_attempts = dispatch_attempts + 1,
+    next_attempt_at = NOW() + make_interval(mins => power(2, dispatch_attempts)::int)
+WHERE id IN (
+    SELECT due.id
+    FROM low_stock_alerts due
+    WHERE due.dispatched_at IS NULL
+      AND due.resolved_at IS NULL
+      AND due.dispatch_attempts < sqlc.arg('max_attempts')
+      AND due.next_attempt_at <= NOW()
+    ORDER BY due.raised_at
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, menu_item_id, ingredient_id, item_name, unit, current_stock, minimum_stock, stock_transaction_id,
+          raised_at, resolved_at, read_at, read_by, dispatched_at, dispatch_attempts, next_attempt_at, last_error;
+
+-- name: ListLowStockAlertDeliveries :many
+-- The channels an alert has been delivered through
+SELECT channel FROM low_stock_alert_deliveries WHERE alert_id = $1;
+
+-- name: CreateLowStockAlertDelivery :exec
+INSERT INTO low_stock_alert_deliveries (alert_id, channel)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: MarkLowStockAlertDispatched :exec
+UPDATE low_stock_alerts
+SET dispatched_at = NOW(), last_error = NULL
+WHERE id = $1;
+
+-- name: SetLowStockAlertError :exec
+UPDATE low_stock_alerts
+SET last_error = $2
+WHERE id = $1;
@@ -1,18 +1,18 @@
 -- name: GetMenuItem :one
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
-WHERE id = $1 AND is_available = true
+WHERE id = $1 AND (is_available = true OR sold_out = true)
 LIMIT 1;
 
 -- name: ListMenuItems :many
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
 WHERE is_available = $1
 ORDER BY name
 LIMIT $2 OFFSET $3;
 
 -- name: ListMenuItemsByCategory :many
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
 WHERE category_id = $1 AND is_available = true
 ORDER BY name
@@ -24,15 +24,64 @@ INSERT INTO menu_items (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at;
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out;
 
 -- name: UpdateMenuItem :one
 UPDATE menu_items
-SET name = $2, category_id = $3, description = $4, price = $5, cost = $6, is_available = $7, updated_at = NOW()
+SET name = $2, category_id = $3, description = $4, price = $5, cost = $6, is_available = $7, sold_out = $8, updated_at = NOW()
 WHERE id = $1
-RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at;
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out;
 
 -- name: DeleteMenuItem :exec
 UPDATE menu_items
-SET is_available = false, updated_at = NOW()
-WHERE id = $1;
\ No newline at end of file
+SET is_available = false, sold_out = false, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateMenuItemCost :one
+-- Records the cost of a menu item bought in as finished goods
+UPDATE menu_items
+SET cost = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out;
+
+-- name: SyncMenuItemAvailability :many
+-- Takes the menu items made from the stock of a menu item or an ingredient off
+-- the menu when the selling location no longer holds enough to make one, and
+-- puts those it took off back when it does. A menu item with a recipe is made
+-- from its ingredients, one without from its own stock. Items taken off the
+-- menu by hand are left alone. Returns the items whose availability changed.
+WITH selling AS (
+    SELECT id FROM stock_locations WHERE is_selling = true
+), affected AS (
+    SELECT mi.id
+    FROM menu_items mi
+    WHERE mi.id = sqlc.narg('menu_item_id')
+      AND NOT EXISTS (SELECT 1 FROM recipe_items ri WHERE ri.menu_item_id = mi.id)
+    UNION
+    SELECT ri.menu_item_id
+    FROM recipe_items ri
+    WHERE ri.ingredient_id = sqlc.narg('ingredient_id') AND ri.menu_item_id IS NOT NULL
+), stocked AS (
+    SELECT a.id,
+           CASE WHEN EXISTS (SELECT 1 FROM recipe_items ri WHERE ri.menu_item_id = a.id) THEN
+               NOT EXISTS (
+                   SELECT 1
+                   FROM recipe_items ri
+                   LEFT JOIN stock_balances sb ON sb.ingredient_id = ri.ingredient_id AND sb.location_id = (SELECT id FROM selling)
+                   WHERE ri.menu_item_id = a.id AND COALESCE(sb.quantity, 0) < ri.quantity
+               )
+           ELSE
+               COALESCE((
+                   SELECT sb.quantity
+                   FROM stock_balances sb
+                   WHERE sb.menu_item_id = a.id AND sb.location_id = (SELECT id FROM selling)
+               ), 0) >= 1
+           END AS in_stock
+    FROM affected a
+)
+UPDATE menu_items mi
+SET is_available = s.in_stock, sold_out = NOT s.in_stock, updated_at = NOW()
+FROM stocked s
+WHERE mi.id = s.id
+  AND ((s.in_stock AND mi.sold_out) OR (NOT s.in_stock AND mi.is_available))
+RETURNING mi.id, mi.name, mi.category_id, mi.is_available, mi.sold_out;
@@ -0,0 +1,123 @@
+-- name: CreateModifierGroup :one
+INSERT INTO modifier_groups (
+    name, description, min_select, max_select, is_active
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, name, description, min_select, max_select, is_active, created_at, updated_at;
+
+-- name: GetModifierGroup :one
+SELECT id, name, description, min_select, max_select, is_active, created_at, updated_at
+FROM modifier_groups
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListModifierGroups :many
+SELECT id, name, description, min_select, max_select, is_active, created_at, updated_at
+FROM modifier_groups
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateModifierGroup :one
+UPDATE modifier_groups
+SET name = $2, description = $3, min_select = $4, max_select = $5, is_active = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, description, min_select, max_select, is_active, created_at, updated_at;
+
+-- name: DeleteModifierGroup :exec
+DELETE FROM modifier_groups
+WHERE id = $1;
+
+-- name: CreateModifierOption :one
+INSERT INTO modifier_options (
+    modifier_group_id, name, price_delta, is_available, sort_order
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, modifier_group_id, name, price_delta, is_available, sort_order, created_at, updated_at;
+
+-- name: GetModifierOption :one
+SELECT id, modifier_group_id, name, price_delta, is_available, sort_order, created_at, updated_at
+FROM modifier_options
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListModifierOptionsByGroupID :many
+SELECT id, modifier_group_id, name, price_delta, is_available, sort_order, created_at, updated_at
+FROM modifier_options
+WHERE modifier_group_id = $1
+ORDER BY sort_order, name;
+
+-- name: UpdateModifierOption :one
+UPDATE modifier_options
+SET name = $2, price_delta = $3, is_available = $4, sort_order = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING id, modifier_group_id, name, price_delta, is_available, sort_order, created_at, updated_at;
+
+-- name: DeleteModifierOption :exec
+DELETE FROM modifier_options
+WHERE id = $1;
+
+-- name: AttachModifierGroup :exec
+INSERT INTO menu_item_modifier_groups (
+    menu_item_id, modifier_group_id, sort_order
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (menu_item_id, modifier_group_id) DO UPDATE SET sort_order = EXCLUDED.sort_order;
+
+-- name: DetachModifierGroup :exec
+DELETE FROM menu_item_modifier_groups
+WHERE menu_item_id = $1 AND modifier_group_id = $2;
+
+-- name: ListModifierGroupsByMenuItemID :many
+-- The active modifier groups offered with a menu item, in display order
+SELECT mg.id, mg.name, mg.description, mg.min_select, mg.max_select, mg.is_active, mg.created_at, mg.updated_at
+FROM modifier_groups mg
+JOIN menu_item_modifier_groups mimg ON mimg.modifier_group_id = mg.id
+WHERE mimg.menu_item_id = $1
+  AND mg.is_active = true
+ORDER BY mimg.sort_order, mg.name;
+
+-- name: CreateOrderItemModifier :one
+INSERT INTO order_item_modifiers (
+    order_item_id, modifier_option_id, group_name, option_name, price_delta
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, order_item_id, modifier_option_id, group_name, option_name, price_delta, created_at;
+
+-- name: CopyOrderItemModifiers :exec
+-- Gives a line split off another the same options the original was ordered with
+INSERT INTO order_item_modifiers (
+    order_item_id, modifier_option_id, group_name, option_name, price_delta
+)
+SELECT sqlc.arg('order_item_id')::uuid, modifier_option_id, group_name, option_name, price_delta
+FROM order_item_modifiers
+WHERE order_item_id = sqlc.arg('source_order_item_id')::uuid
+ORDER BY created_at;
+
+-- name: ListOrderItemModifiersByOrderID :many
+SELECT oim.id, oim.order_item_id, oim.modifier_option_id, oim.group_name, oim.option_name, oim.price_delta, oim.created_at
+FROM order_item_modifiers oim
+JOIN order_items oi ON oim.order_item_id = oi.id
+WHERE oi.order_id = $1
+ORDER BY oim.created_at;
+
+-- name: GetSalesByModifierByDateRange :many
+SELECT
+    oim.group_name,
+    oim.option_name,
+    COUNT(oim.id) AS times_selected,
+    SUM(oi.quantity) AS total_quantity,
+    SUM(oim.price_delta * oi.quantity)::TEXT AS total_revenue
+FROM order_item_modifiers oim
+JOIN order_items oi ON oim.order_item_id = oi.id
+JOIN orders o ON oi.order_id = o.id
+WHERE o.status = 'completed'
+AND o.completed_at IS NOT NULL
+AND o.completed_at >= $1::timestamp
+AND o.completed_at <= $2::timestamp
+GROUP BY oim.group_name, oim.option_name
+ORDER BY total_quantity DESC;
@@ -1,28 +1,33 @@
 -- name: GetOrderItem :one
-SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 FROM order_items
 WHERE id = $1
 LIMIT 1;
 
 -- name: GetOrderItemsByOrderID :many
-SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 FROM order_items
 WHERE order_id = $1
 ORDER BY created_at;
 
 -- name: CreateOrderItem :one
 INSERT INTO order_items (
-    order_id, menu_item_id, quantity, unit_price, total_price
+    order_id, menu_item_id, quantity, unit_price, total_price, notes
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
-RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at;
+RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at;
 
 -- name: UpdateOrderItem :one
 UPDATE order_items
-SET quantity = $2, unit_price = $3, total_price = $4, updated_at = NOW()
+SET quantity = $2, unit_price = $3, total_price = $4, notes = $5, updated_at = NOW()
 WHERE id = $1
-RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at;
+RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at;
+
+-- name: MoveOrderItem :exec
+UPDATE order_items
+SET order_id = $2, updated_at = NOW()
+WHERE id = $1;
 
 -- name: DeleteOrderItem :exec
 DELETE FROM order_items
@@ -33,8 +38,22 @@ DELETE FROM order_items
 WHERE order_id = $1;
 
 -- name: GetOrderItemsWithDetails :many
-SELECT oi.id, oi.order_id, oi.menu_item_id, mi.name as menu_item_name, oi.quantity, oi.unit_price, oi.total_price, oi.created_at, oi.updated_at
+SELECT oi.id, oi.order_id, oi.menu_item_id, mi.name as menu_item_name, oi.quantity, oi.unit_price, oi.total_price, oi.notes, oi.created_at, oi.updated_at
 FROM order_items oi
 JOIN menu_items mi ON oi.menu_item_id = mi.id
 WHERE oi.order_id = $1
-ORDER BY oi.created_at;
\ No newline at end of file
+ORDER BY oi.created_at;
+-- name: CreateOrderItemStockUsage :exec
+INSERT INTO order_item_stock_usage (
+    order_item_id, menu_item_id, ingredient_id, quantity
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ListOrderItemStockUsage :many
+-- The stock each line of an order took when the order was completed
+SELECT u.order_item_id, u.menu_item_id, u.ingredient_id, u.quantity::text AS quantity
+FROM order_item_stock_usage u
+JOIN order_items oi ON u.order_item_id = oi.id
+WHERE oi.order_id = $1
+ORDER BY u.order_item_id, u.menu_item_id, u.ingredient_id;
@@ -0,0 +1,27 @@
+-- name: CreateOrderPayment :one
+INSERT INTO order_payments (
+    order_id, payment_method, amount, reference, change_amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, order_id, payment_method, amount, reference, change_amount, created_at;
+
+-- name: ListOrderPayments :many
+SELECT id, order_id, payment_method, amount, reference, change_amount, created_at
+FROM order_payments
+WHERE order_id = $1
+ORDER BY created_at, id;
+
+-- name: GetPaymentMethodTotalsByDateRange :many
+-- Net takings per payment method: cash change handed back is not revenue
+SELECT
+    op.payment_method,
+    COUNT(DISTINCT op.order_id) AS total_orders,
+    COALESCE(SUM(op.amount - op.change_amount), '0')::TEXT AS total_amount
+FROM order_payments op
+JOIN orders o ON op.order_id = o.id
+WHERE o.status = 'completed'
+AND o.completed_at IS NOT NULL
+AND o.completed_at >= $1::timestamp
+AND o.completed_at <= $2::timestamp
+GROUP BY op.payment_method
+ORDER BY op.payment_method;
@@ -1,20 +1,29 @@
 -- name: GetOrder :one
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE id = $1
 LIMIT 1;
 
 -- name: GetOrderByNumber :one
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE order_number = $1
 LIMIT 1;
 
 -- name: ListOrders :many
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE ($1 = '' OR status = $1) 
   AND ($2 = '00000000-0000-0000-0000-000000000000'::uuid OR user_id = $2)
@@ -25,12 +34,28 @@ LIMIT $5 OFFSET $6;
 
 -- name: CreateOrder :one
 INSERT INTO orders (
-    order_number, user_id, total_amount, discount_amount, tax_amount
+    order_number, user_id, total_amount, discount_amount, tax_amount, subtotal_amount,
+    order_type, table_id, guest_count
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-          payment_method, payment_status, completed_at, created_at, updated_at;
+          payment_method, payment_status, completed_at, created_at, updated_at,
+          cancellation_reason, cancelled_by, cancelled_at,
+          subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+          order_type, table_id, guest_count, shift_id, void_shift_id;
+
+-- name: CancelOrder :exec
+UPDATE orders
+SET status = 'cancelled', payment_status = $2, cancellation_reason = $3,
+    cancelled_by = $4, cancelled_at = clock_timestamp(), void_shift_id = $5, updated_at = NOW()
+WHERE id = $1;
+
+-- name: MergeOrder :exec
+-- Closes an order whose lines were all moved into another order
+UPDATE orders
+SET status = 'merged', cancellation_reason = $2, cancelled_by = $3, cancelled_at = NOW(), updated_at = NOW()
+WHERE id = $1;
 
 -- name: UpdateOrderStatus :exec
 UPDATE orders
@@ -38,11 +63,51 @@ SET status = $2, updated_at = NOW()
 WHERE id = $1;
 
 -- name: UpdateOrderPayment :exec
+-- completed_at is taken when the order completes rather than when its
+-- transaction started, so it falls after any Z report the order waited for
 UPDATE orders
-SET payment_method = $2, payment_status = $3, completed_at = NOW(), updated_at = NOW()
+SET payment_method = $2, payment_status = $3, completed_at = clock_timestamp(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateOrderPaymentStatus :exec
+UPDATE orders
+SET payment_status = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateOrderShift :exec
+UPDATE orders
+SET shift_id = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateOrderTable :exec
+UPDATE orders
+SET order_type = $2, table_id = $3, guest_count = $4, updated_at = NOW()
 WHERE id = $1;
 
 -- name: UpdateOrderTotal :exec
 UPDATE orders
-SET total_amount = $2, discount_amount = $3, tax_amount = $4, updated_at = NOW()
-WHERE id = $1;
\ No newline at end of file
+SET total_amount = $2, discount_amount = $3, tax_amount = $4,
+    subtotal_amount = $5, service_charge_amount = $6, rounding_amount = $7,
+    service_charge_rate = $8, tax_rate = $9, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetOrderForUpdate :one
+SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
+FROM orders
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: NextOrderNumber :one
+-- Claims the next number for the prefix and business day. The upsert keeps the
+-- counter row locked until the caller's transaction ends, so a rolled back order
+-- releases its number instead of leaving a gap.
+INSERT INTO order_number_sequences (prefix, business_date, last_number)
+VALUES ($1, $2, 1)
+ON CONFLICT (prefix, business_date)
+DO UPDATE SET last_number = order_number_sequences.last_number + 1, updated_at = NOW()
+RETURNING last_number;
@@ -0,0 +1,103 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (
+    name, description, promotion_type, value, buy_quantity, get_quantity,
+    target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+    daily_start_time, daily_end_time, usage_limit, is_stackable, priority, is_active, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+)
+RETURNING id, name, description, promotion_type, value, buy_quantity, get_quantity,
+          target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+          daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+          priority, is_active, created_by, created_at, updated_at;
+
+-- name: GetPromotion :one
+SELECT id, name, description, promotion_type, value, buy_quantity, get_quantity,
+       target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+       daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+       priority, is_active, created_by, created_at, updated_at
+FROM promotions
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetPromotionByVoucherCode :one
+SELECT id, name, description, promotion_type, value, buy_quantity, get_quantity,
+       target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+       daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+       priority, is_active, created_by, created_at, updated_at
+FROM promotions
+WHERE voucher_code = $1
+LIMIT 1;
+
+-- name: ListPromotions :many
+SELECT id, name, description, promotion_type, value, buy_quantity, get_quantity,
+       target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+       daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+       priority, is_active, created_by, created_at, updated_at
+FROM promotions
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+ORDER BY priority DESC, created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListAutomaticPromotions :many
+-- Active promotions without a voucher code that are running at the given time
+-- and still have uses left
+SELECT id, name, description, promotion_type, value, buy_quantity, get_quantity,
+       target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+       daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+       priority, is_active, created_by, created_at, updated_at
+FROM promotions
+WHERE is_active = true
+  AND voucher_code IS NULL
+  AND (starts_at IS NULL OR starts_at <= $1::timestamp)
+  AND (ends_at IS NULL OR ends_at > $1::timestamp)
+  AND (usage_limit IS NULL OR usage_count < usage_limit)
+ORDER BY priority DESC, created_at;
+
+-- name: UpdatePromotion :one
+UPDATE promotions
+SET name = $2, description = $3, promotion_type = $4, value = $5, buy_quantity = $6,
+    get_quantity = $7, target_type = $8, target_id = $9, voucher_code = $10,
+    min_spend = $11, starts_at = $12, ends_at = $13, daily_start_time = $14,
+    daily_end_time = $15, usage_limit = $16, is_stackable = $17, priority = $18,
+    is_active = $19, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, description, promotion_type, value, buy_quantity, get_quantity,
+          target_type, target_id, voucher_code, min_spend, starts_at, ends_at,
+          daily_start_time, daily_end_time, usage_limit, usage_count, is_stackable,
+          priority, is_active, created_by, created_at, updated_at;
+
+-- name: DeletePromotion :exec
+DELETE FROM promotions
+WHERE id = $1;
+
+-- name: ClaimPromotionUsage :one
+-- Uses up one redemption; returns no row once the usage limit has been reached
+UPDATE promotions
+SET usage_count = usage_count + 1, updated_at = NOW()
+WHERE id = $1
+  AND (usage_limit IS NULL OR usage_count < usage_limit)
+RETURNING usage_count;
+
+-- name: ReleasePromotionUsage :exec
+UPDATE promotions
+SET usage_count = GREATEST(usage_count - 1, 0), updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateOrderPromotion :one
+INSERT INTO order_promotions (
+    order_id, promotion_id, promotion_name, discount_amount
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, order_id, promotion_id, promotion_name, discount_amount, created_at;
+
+-- name: ListOrderPromotions :many
+SELECT id, order_id, promotion_id, promotion_name, discount_amount, created_at
+FROM order_promotions
+WHERE order_id = $1
+ORDER BY created_at;
+
+-- name: DeleteOrderPromotionsByOrderID :exec
+DELETE FROM order_promotions
+WHERE order_id = $1;
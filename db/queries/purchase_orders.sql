@@ -0,0 +1,115 @@
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+    supplier_id, expected_date, notes, created_by
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, po_number, supplier_id, status, expected_date, notes, total_amount, created_by, sent_at, received_at, created_at, updated_at;
+
+-- name: GetPurchaseOrder :one
+SELECT po.id, po.po_number, po.supplier_id, s.name AS supplier_name, po.status, po.expected_date, po.notes, po.total_amount, po.created_by, po.sent_at, po.received_at, po.created_at, po.updated_at
+FROM purchase_orders po
+JOIN suppliers s ON po.supplier_id = s.id
+WHERE po.id = $1
+LIMIT 1;
+
+-- name: GetPurchaseOrderForUpdate :one
+-- Locks the purchase order so that concurrent deliveries cannot receive the
+-- same outstanding quantity twice
+SELECT po.id, po.po_number, po.supplier_id, s.name AS supplier_name, po.status, po.expected_date, po.notes, po.total_amount, po.created_by, po.sent_at, po.received_at, po.created_at, po.updated_at
+FROM purchase_orders po
+JOIN suppliers s ON po.supplier_id = s.id
+WHERE po.id = $1
+LIMIT 1
+FOR UPDATE OF po;
+
+-- name: ListPurchaseOrders :many
+SELECT po.id, po.po_number, po.supplier_id, s.name AS supplier_name, po.status, po.expected_date, po.notes, po.total_amount, po.created_by, po.sent_at, po.received_at, po.created_at, po.updated_at
+FROM purchase_orders po
+JOIN suppliers s ON po.supplier_id = s.id
+WHERE (sqlc.narg('supplier_id')::uuid IS NULL OR po.supplier_id = sqlc.narg('supplier_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR po.status = sqlc.narg('status')::text)
+ORDER BY po.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdatePurchaseOrder :exec
+UPDATE purchase_orders
+SET supplier_id = $2, expected_date = $3, notes = $4, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdatePurchaseOrderStatus :exec
+-- Moves a purchase order along, stamping when it was sent and fully received
+UPDATE purchase_orders
+SET status = sqlc.arg(status)::text,
+    sent_at = CASE WHEN sqlc.arg(status)::text = 'sent' THEN NOW() ELSE sent_at END,
+    received_at = CASE WHEN sqlc.arg(status)::text = 'received' THEN NOW() ELSE received_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: RefreshPurchaseOrderTotal :exec
+-- Recalculates the order total from its lines
+UPDATE purchase_orders
+SET total_amount = (
+        SELECT COALESCE(SUM(ROUND(quantity * unit_cost, 2)), 0)
+        FROM purchase_order_items
+        WHERE purchase_order_id = $1
+    ),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreatePurchaseOrderItem :exec
+INSERT INTO purchase_order_items (
+    purchase_order_id, menu_item_id, ingredient_id, unit, quantity, unit_cost
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: DeletePurchaseOrderItems :exec
+DELETE FROM purchase_order_items
+WHERE purchase_order_id = $1;
+
+-- name: ListPurchaseOrderItems :many
+SELECT poi.id, poi.purchase_order_id, poi.menu_item_id, poi.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, poi.unit, poi.quantity, poi.received_quantity, poi.unit_cost
+FROM purchase_order_items poi
+LEFT JOIN menu_items mi ON poi.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON poi.ingredient_id = ig.id
+WHERE poi.purchase_order_id = $1
+ORDER BY item_name, poi.id;
+
+-- name: ReceivePurchaseOrderItem :one
+-- Adds a delivered quantity to a line; returns no row when it would take the
+-- line past the quantity ordered
+UPDATE purchase_order_items
+SET received_quantity = received_quantity + sqlc.arg(quantity)::numeric
+WHERE id = sqlc.arg(id)
+  AND received_quantity + sqlc.arg(quantity)::numeric <= quantity
+RETURNING id;
+
+-- name: CreateGoodsReceivedNote :one
+INSERT INTO goods_received_notes (
+    purchase_order_id, total_amount, expense_id, notes, received_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, grn_number, purchase_order_id, total_amount, expense_id, notes, received_by, received_at;
+
+-- name: CreateGoodsReceivedNoteItem :exec
+INSERT INTO goods_received_note_items (
+    goods_received_note_id, purchase_order_item_id, quantity, stock_quantity, unit_cost
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListGoodsReceivedNotes :many
+SELECT id, grn_number, purchase_order_id, total_amount, expense_id, notes, received_by, received_at
+FROM goods_received_notes
+WHERE purchase_order_id = $1
+ORDER BY received_at, grn_number;
+
+-- name: ListGoodsReceivedNoteItems :many
+-- Lists the lines of every delivery against a purchase order
+SELECT gi.id, gi.goods_received_note_id, gi.purchase_order_item_id, gi.quantity, gi.stock_quantity, gi.unit_cost
+FROM goods_received_note_items gi
+JOIN goods_received_notes g ON gi.goods_received_note_id = g.id
+WHERE g.purchase_order_id = $1
+ORDER BY g.received_at, gi.id;
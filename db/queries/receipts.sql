@@ -0,0 +1,15 @@
+-- name: CreateReceiptPrint :one
+-- Copies are numbered per order; the caller holds the order's row lock
+INSERT INTO receipt_prints (
+    order_id, printed_by, format, copy_number
+)
+SELECT sqlc.arg('order_id')::uuid, sqlc.arg('printed_by')::uuid, sqlc.arg('format')::varchar, COALESCE(MAX(copy_number), 0) + 1
+FROM receipt_prints
+WHERE order_id = sqlc.arg('order_id')::uuid
+RETURNING id, order_id, printed_by, format, copy_number, printed_at;
+
+-- name: ListReceiptPrintsByOrderID :many
+SELECT id, order_id, printed_by, format, copy_number, printed_at
+FROM receipt_prints
+WHERE order_id = $1
+ORDER BY copy_number;
@@ -0,0 +1,33 @@
+-- name: ListMenuItemRecipeItems :many
+SELECT ri.ingredient_id, i.name AS ingredient_name, i.unit, ri.quantity
+FROM recipe_items ri
+JOIN ingredients i ON i.id = ri.ingredient_id
+WHERE ri.menu_item_id = $1
+ORDER BY i.name;
+
+-- name: ListModifierOptionRecipeItems :many
+SELECT ri.ingredient_id, i.name AS ingredient_name, i.unit, ri.quantity
+FROM recipe_items ri
+JOIN ingredients i ON i.id = ri.ingredient_id
+WHERE ri.modifier_option_id = $1
+ORDER BY i.name;
+
+-- name: CreateRecipeItem :exec
+INSERT INTO recipe_items (
+    menu_item_id, modifier_option_id, ingredient_id, quantity
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: DeleteMenuItemRecipeItems :exec
+DELETE FROM recipe_items
+WHERE menu_item_id = $1;
+
+-- name: DeleteModifierOptionRecipeItems :exec
+DELETE FROM recipe_items
+WHERE modifier_option_id = $1;
+
+-- name: CountRecipeItemsByIngredient :one
+SELECT COUNT(*)
+FROM recipe_items
+WHERE ingredient_id = $1;
@@ -0,0 +1,41 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+    order_id, amount, tax_amount, payment_method, reference, reason, restocked, refunded_by, approved_by, shift_id, created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, clock_timestamp()
+) RETURNING id, order_id, amount, tax_amount, payment_method, reference, reason, restocked, refunded_by, approved_by, created_at, shift_id;
+
+-- name: CreateRefundItem :one
+INSERT INTO refund_items (
+    refund_id, order_item_id, menu_item_id, quantity, amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, refund_id, order_item_id, menu_item_id, quantity, amount;
+
+-- name: ListRefundsByOrderID :many
+SELECT id, order_id, amount, tax_amount, payment_method, reference, reason, restocked, refunded_by, approved_by, created_at, shift_id
+FROM refunds
+WHERE order_id = $1
+ORDER BY created_at, id;
+
+-- name: ListRefundItemsByRefundID :many
+SELECT id, refund_id, order_item_id, menu_item_id, quantity, amount
+FROM refund_items
+WHERE refund_id = $1
+ORDER BY id;
+
+-- name: GetRefundedQuantitiesByOrderID :many
+SELECT ri.order_item_id, SUM(ri.quantity)::INTEGER AS refunded_quantity
+FROM refund_items ri
+JOIN refunds r ON ri.refund_id = r.id
+WHERE r.order_id = $1
+GROUP BY ri.order_item_id;
+
+-- name: GetRefundTotalsByDateRange :one
+SELECT
+    COUNT(r.id) AS total_refunds,
+    COALESCE(SUM(r.amount), '0')::TEXT AS total_amount,
+    COALESCE(SUM(r.tax_amount), '0')::TEXT AS total_tax
+FROM refunds r
+WHERE r.created_at >= $1::timestamp
+AND r.created_at <= $2::timestamp;
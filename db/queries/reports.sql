@@ -26,7 +26,10 @@ SELECT
     COUNT(o.id) AS total_orders,
     COALESCE(SUM(o.total_amount), '0')::TEXT AS total_sales,
     COALESCE(SUM(o.discount_amount), '0')::TEXT AS total_discount,
-    COALESCE(SUM(o.tax_amount), '0')::TEXT AS total_tax
+    COALESCE(SUM(o.tax_amount), '0')::TEXT AS total_tax,
+    COALESCE(SUM(o.subtotal_amount), '0')::TEXT AS total_subtotal,
+    COALESCE(SUM(o.service_charge_amount), '0')::TEXT AS total_service_charge,
+    COALESCE(SUM(o.rounding_amount), '0')::TEXT AS total_rounding
 FROM orders o
 WHERE o.status = 'completed'
 AND o.completed_at IS NOT NULL
@@ -0,0 +1,97 @@
+-- name: CreateCashShift :one
+INSERT INTO cash_shifts (
+    user_id, opening_float
+) VALUES (
+    $1, $2
+)
+RETURNING id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by;
+
+-- name: GetCashShift :one
+SELECT id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by
+FROM cash_shifts
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetCashShiftForUpdate :one
+SELECT id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by
+FROM cash_shifts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetOpenCashShiftByUserID :one
+SELECT id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by
+FROM cash_shifts
+WHERE user_id = $1 AND status = 'open'
+LIMIT 1
+FOR SHARE;
+
+-- name: ListCashShifts :many
+SELECT id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by
+FROM cash_shifts
+WHERE (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id')::uuid)
+AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')::varchar)
+ORDER BY opened_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CloseCashShift :one
+UPDATE cash_shifts
+SET status = 'closed', expected_cash = $2, counted_cash = $3, variance = $4, notes = $5, closed_by = $6, closed_at = NOW()
+WHERE id = $1 AND status = 'open'
+RETURNING id, user_id, status, opening_float, expected_cash, counted_cash, variance, notes, opened_at, closed_at, closed_by;
+
+-- name: CreateCashMovement :one
+INSERT INTO cash_movements (
+    shift_id, movement_type, amount, reason, created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, shift_id, movement_type, amount, reason, created_by, created_at;
+
+-- name: ListCashMovementsByShiftID :many
+SELECT id, shift_id, movement_type, amount, reason, created_by, created_at
+FROM cash_movements
+WHERE shift_id = $1
+ORDER BY created_at, id;
+
+-- name: CreateCashShiftCount :exec
+INSERT INTO cash_shift_counts (
+    shift_id, denomination, quantity
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: ListCashShiftCountsByShiftID :many
+SELECT shift_id, denomination, quantity
+FROM cash_shift_counts
+WHERE shift_id = $1
+ORDER BY denomination DESC;
+
+-- name: GetShiftCashSales :one
+-- Cash kept from the orders completed in the shift, counting the cash part of
+-- split bills; change handed back never reached the drawer. An order voided
+-- later still brought its cash in; the void hands it back in its own shift.
+SELECT
+    COUNT(DISTINCT o.id) AS cash_orders,
+    COALESCE(SUM(op.amount - op.change_amount), '0')::TEXT AS cash_sales
+FROM orders o
+JOIN order_payments op ON op.order_id = o.id
+WHERE o.shift_id = $1
+AND o.status IN ('completed', 'cancelled')
+AND op.payment_method = 'cash';
+
+-- name: GetShiftCashRefunds :one
+-- Cash handed back from the drawer for refunds paid in the shift
+SELECT COALESCE(SUM(amount), '0')::TEXT AS cash_refunds
+FROM refunds
+WHERE shift_id = $1
+AND payment_method = 'cash';
+
+-- name: GetShiftCashVoids :one
+-- Cash handed back from the drawer for completed orders voided in the shift
+SELECT COALESCE(SUM(op.amount - op.change_amount), '0')::TEXT AS cash_voids
+FROM orders o
+JOIN order_payments op ON op.order_id = o.id
+WHERE o.void_shift_id = $1
+AND o.status = 'cancelled'
+AND op.payment_method = 'cash';
@@ -0,0 +1,127 @@
+-- name: CreateStockLocation :one
+INSERT INTO stock_locations (
+    code, name, description, is_selling
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, code, name, description, is_selling, is_active, created_at, updated_at;
+
+-- name: GetStockLocation :one
+SELECT id, code, name, description, is_selling, is_active, created_at, updated_at
+FROM stock_locations
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetSellingStockLocation :one
+-- The location sales are made from
+SELECT id, code, name, description, is_selling, is_active, created_at, updated_at
+FROM stock_locations
+WHERE is_selling
+LIMIT 1;
+
+-- name: ListStockLocations :many
+SELECT id, code, name, description, is_selling, is_active, created_at, updated_at
+FROM stock_locations
+WHERE (sqlc.arg('include_inactive')::boolean OR is_active)
+ORDER BY is_selling DESC, name;
+
+-- name: UpdateStockLocation :one
+UPDATE stock_locations
+SET name = $2, description = $3, is_selling = $4, is_active = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING id, code, name, description, is_selling, is_active, created_at, updated_at;
+
+-- name: ClearSellingStockLocation :exec
+-- Stops selling from the current selling location, before another takes over
+UPDATE stock_locations
+SET is_selling = false, updated_at = NOW()
+WHERE is_selling AND id <> $1;
+
+-- name: AdjustStockBalance :one
+-- Applies a relative change to the stock of an item at a location; returns no
+-- row when the location holds no stock of the item or the change would take
+-- it below zero
+UPDATE stock_balances
+SET quantity = quantity + sqlc.arg('quantity')::numeric, updated_at = NOW()
+WHERE location_id = sqlc.arg('location_id')
+  AND (menu_item_id = sqlc.narg('menu_item_id') OR ingredient_id = sqlc.narg('ingredient_id'))
+  AND quantity + sqlc.arg('quantity')::numeric >= 0
+RETURNING quantity;
+
+-- name: CreateStockBalance :one
+-- Records the first stock of an item put into a location
+INSERT INTO stock_balances (
+    location_id, menu_item_id, ingredient_id, quantity
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING quantity;
+
+-- name: ListStockBalances :many
+-- The stock held at a location, by menu item and ingredient
+SELECT b.location_id, b.menu_item_id, b.ingredient_id,
+       COALESCE(mi.name, ig.name)::text AS item_name,
+       CASE WHEN b.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+       COALESCE(i.unit, ig.unit)::text AS unit,
+       b.quantity, b.updated_at
+FROM stock_balances b
+LEFT JOIN menu_items mi ON b.menu_item_id = mi.id
+LEFT JOIN inventory i ON b.menu_item_id = i.menu_item_id
+LEFT JOIN ingredients ig ON b.ingredient_id = ig.id
+WHERE b.location_id = $1 AND b.quantity > 0
+ORDER BY item_name;
+
+-- name: LockMenuItemStock :one
+-- Locks a menu item's stock against other movements and returns it
+SELECT current_stock
+FROM inventory
+WHERE menu_item_id = $1
+FOR UPDATE;
+
+-- name: LockIngredientStock :one
+-- Locks an ingredient's stock against other movements and returns it
+SELECT current_stock
+FROM ingredients
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CreateStockTransfer :one
+INSERT INTO stock_transfers (
+    from_location_id, to_location_id, notes, created_by
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, transfer_number, from_location_id, to_location_id, notes, created_by, created_at;
+
+-- name: CreateStockTransferLine :exec
+INSERT INTO stock_transfer_lines (
+    stock_transfer_id, menu_item_id, ingredient_id, unit, quantity
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetStockTransfer :one
+SELECT t.id, t.transfer_number, t.from_location_id, fl.name AS from_location_name, t.to_location_id, tl.name AS to_location_name, t.notes, t.created_by, t.created_at
+FROM stock_transfers t
+JOIN stock_locations fl ON t.from_location_id = fl.id
+JOIN stock_locations tl ON t.to_location_id = tl.id
+WHERE t.id = $1
+LIMIT 1;
+
+-- name: ListStockTransfers :many
+-- Transfers out of or into a location, or between any locations, newest first
+SELECT t.id, t.transfer_number, t.from_location_id, fl.name AS from_location_name, t.to_location_id, tl.name AS to_location_name, t.notes, t.created_by, t.created_at
+FROM stock_transfers t
+JOIN stock_locations fl ON t.from_location_id = fl.id
+JOIN stock_locations tl ON t.to_location_id = tl.id
+WHERE (sqlc.narg('location_id')::uuid IS NULL OR t.from_location_id = sqlc.narg('location_id')::uuid OR t.to_location_id = sqlc.narg('location_id')::uuid)
+ORDER BY t.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListStockTransferLines :many
+SELECT l.id, l.stock_transfer_id, l.menu_item_id, l.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, l.unit, l.quantity
+FROM stock_transfer_lines l
+LEFT JOIN menu_items mi ON l.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON l.ingredient_id = ig.id
+WHERE l.stock_transfer_id = $1
+ORDER BY item_name, l.id;
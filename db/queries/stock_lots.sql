@@ -0,0 +1,79 @@
+-- name: CreateStockLot :one
+INSERT INTO stock_lots (
+    menu_item_id, ingredient_id, lot_number, unit, quantity_received, quantity_remaining, unit_cost, expires_at, goods_received_note_id, received_by
+) VALUES (
+    $1, $2, $3, $4, $5, $5, $6, $7, $8, $9
+)
+RETURNING id, menu_item_id, ingredient_id, lot_number, unit, quantity_received, quantity_remaining, unit_cost, received_at, expires_at, goods_received_note_id, received_by;
+
+-- name: ListStockLots :many
+-- Lots with stock left, in the order they are consumed
+SELECT l.id, l.menu_item_id, l.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, l.lot_number, l.unit, l.quantity_received, l.quantity_remaining, l.unit_cost, l.received_at, l.expires_at, l.goods_received_note_id, l.received_by
+FROM stock_lots l
+LEFT JOIN menu_items mi ON l.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON l.ingredient_id = ig.id
+WHERE l.quantity_remaining > 0
+  AND (sqlc.narg('menu_item_id')::uuid IS NULL OR l.menu_item_id = sqlc.narg('menu_item_id')::uuid)
+  AND (sqlc.narg('ingredient_id')::uuid IS NULL OR l.ingredient_id = sqlc.narg('ingredient_id')::uuid)
+ORDER BY item_name, l.expires_at NULLS LAST, l.received_at, l.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListStockLotsExpiringBefore :many
+-- Lots with stock left that expire at or before the given time, soonest first
+SELECT l.id, l.menu_item_id, l.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, l.lot_number, l.unit, l.quantity_received, l.quantity_remaining, l.unit_cost, l.received_at, l.expires_at, l.goods_received_note_id, l.received_by
+FROM stock_lots l
+LEFT JOIN menu_items mi ON l.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON l.ingredient_id = ig.id
+WHERE l.quantity_remaining > 0
+  AND l.expires_at <= $1
+ORDER BY l.expires_at, l.received_at, l.id;
+
+-- name: GetExpiredStock :one
+-- What is left in a menu item's or an ingredient's lots that have expired,
+-- which is still in its stock until written off but cannot be used
+SELECT COALESCE(SUM(quantity_remaining), 0)::text AS expired_quantity
+FROM stock_lots
+WHERE (menu_item_id = sqlc.narg('menu_item_id')::uuid OR ingredient_id = sqlc.narg('ingredient_id')::uuid)
+  AND quantity_remaining > 0
+  AND expires_at <= NOW();
+
+-- name: ConsumeMenuItemStockLots :exec
+-- Takes a quantity out of a menu item's lots, first-expiring first and then
+-- first-received first. Each lot gives up what is left of the quantity after
+-- the lots ahead of it, up to what it holds. Expired lots are left to be
+-- written off, never sold from; callers keep stock taken out to what
+-- GetExpiredStock leaves. Stock beyond what the lots hold was never put into
+-- a lot and is taken without touching any.
+WITH open_lots AS (
+    SELECT id, quantity_remaining,
+           SUM(quantity_remaining) OVER (ORDER BY expires_at NULLS LAST, received_at, id) AS running_total
+    FROM stock_lots
+    WHERE menu_item_id = sqlc.arg('menu_item_id')::uuid AND quantity_remaining > 0
+      AND (expires_at IS NULL OR expires_at > NOW())
+)
+UPDATE stock_lots l
+SET quantity_remaining = l.quantity_remaining - LEAST(o.quantity_remaining, sqlc.arg('quantity')::numeric - (o.running_total - o.quantity_remaining))
+FROM open_lots o
+WHERE l.id = o.id
+  AND o.running_total - o.quantity_remaining < sqlc.arg('quantity')::numeric;
+
+-- name: ConsumeIngredientStockLots :exec
+-- Takes a quantity out of an ingredient's lots, in the same order as
+-- ConsumeMenuItemStockLots
+WITH open_lots AS (
+    SELECT id, quantity_remaining,
+           SUM(quantity_remaining) OVER (ORDER BY expires_at NULLS LAST, received_at, id) AS running_total
+    FROM stock_lots
+    WHERE ingredient_id = sqlc.arg('ingredient_id')::uuid AND quantity_remaining > 0
+      AND (expires_at IS NULL OR expires_at > NOW())
+)
+UPDATE stock_lots l
+SET quantity_remaining = l.quantity_remaining - LEAST(o.quantity_remaining, sqlc.arg('quantity')::numeric - (o.running_total - o.quantity_remaining))
+FROM open_lots o
+WHERE l.id = o.id
+  AND o.running_total - o.quantity_remaining < sqlc.arg('quantity')::numeric;
+
+-- name: EmptyStockLot :exec
+UPDATE stock_lots
+SET quantity_remaining = 0
+WHERE id = $1;
@@ -0,0 +1,73 @@
+-- name: CreateStockTake :one
+INSERT INTO stock_takes (
+    category_id, include_menu_items, include_ingredients, notes, created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, stock_take_number, status, category_id, include_menu_items, include_ingredients, notes, created_by, approved_by, approved_at, created_at, updated_at;
+
+-- name: SnapshotStockTakeMenuItems :exec
+-- Records the stock and cost of every menu item stocked as finished goods,
+-- optionally only those of one category
+INSERT INTO stock_take_lines (stock_take_id, menu_item_id, unit, system_quantity, unit_cost)
+SELECT sqlc.arg(stock_take_id), i.menu_item_id, i.unit, i.current_stock, mi.cost
+FROM inventory i
+JOIN menu_items mi ON i.menu_item_id = mi.id
+WHERE (sqlc.narg('category_id')::uuid IS NULL OR mi.category_id = sqlc.narg('category_id')::uuid);
+
+-- name: SnapshotStockTakeIngredients :exec
+-- Records the stock and cost of every active ingredient
+INSERT INTO stock_take_lines (stock_take_id, ingredient_id, unit, system_quantity, unit_cost)
+SELECT $1, ig.id, ig.unit, ig.current_stock, ig.cost_price
+FROM ingredients ig
+WHERE ig.is_active = true;
+
+-- name: GetStockTake :one
+SELECT id, stock_take_number, status, category_id, include_menu_items, include_ingredients, notes, created_by, approved_by, approved_at, created_at, updated_at
+FROM stock_takes
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetStockTakeForUpdate :one
+-- Locks the stock take so that counts cannot change while it is approved
+SELECT id, stock_take_number, status, category_id, include_menu_items, include_ingredients, notes, created_by, approved_by, approved_at, created_at, updated_at
+FROM stock_takes
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ListStockTakes :many
+SELECT id, stock_take_number, status, category_id, include_menu_items, include_ingredients, notes, created_by, approved_by, approved_at, created_at, updated_at
+FROM stock_takes
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListStockTakeLines :many
+SELECT sl.id, sl.stock_take_id, sl.menu_item_id, sl.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, sl.unit, sl.system_quantity, sl.counted_quantity, sl.unit_cost, sl.counted_by, sl.counted_at
+FROM stock_take_lines sl
+LEFT JOIN menu_items mi ON sl.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON sl.ingredient_id = ig.id
+WHERE sl.stock_take_id = $1
+ORDER BY item_name, sl.id;
+
+-- name: SetStockTakeLineCount :exec
+-- Replaces the counted quantity of a line
+UPDATE stock_take_lines
+SET counted_quantity = $2, counted_by = $3, counted_at = NOW()
+WHERE id = $1;
+
+-- name: AddStockTakeLineCount :exec
+-- Adds to the counted quantity of a line, as each scan of an item does
+UPDATE stock_take_lines
+SET counted_quantity = COALESCE(counted_quantity, 0) + sqlc.arg(quantity)::numeric, counted_by = sqlc.arg(counted_by), counted_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateStockTakeStatus :exec
+-- Closes a stock take, recording who approved it when it is approved
+UPDATE stock_takes
+SET status = sqlc.arg(status)::text,
+    approved_by = sqlc.narg('approved_by'),
+    approved_at = CASE WHEN sqlc.arg(status)::text = 'approved' THEN NOW() ELSE approved_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
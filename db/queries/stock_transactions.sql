@@ -1,22 +1,97 @@
 -- name: CreateStockTransaction :one
 INSERT INTO stock_transactions (
     menu_item_id, transaction_type, quantity, previous_stock, current_stock,
-    reason, reference_type, reference_id, user_id
+    reason, reference_type, reference_id, user_id, ingredient_id, unit,
+    unit_cost, total_cost, average_cost, location_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+    -- Quantities are always in the stock unit of the item or ingredient
+    COALESCE((SELECT unit FROM inventory WHERE menu_item_id = $1),
+             (SELECT unit FROM ingredients WHERE id = $10)),
+    $11, $12, $13, $14
 )
 RETURNING id, menu_item_id, transaction_type, quantity, previous_stock, current_stock,
-          reason, reference_type, reference_id, user_id, created_at;
+          reason, reference_type, reference_id, user_id, created_at, ingredient_id, unit,
+          unit_cost, total_cost, average_cost, location_id;
 
 -- name: ListStockTransactions :many
 SELECT st.id, st.menu_item_id, mi.name as menu_item_name, st.transaction_type,
        st.quantity, st.previous_stock, st.current_stock, st.reason,
-       st.reference_type, st.reference_id, st.user_id, u.username as user_name, st.created_at
+       st.reference_type, st.reference_id, st.user_id, u.username as user_name, st.created_at,
+       st.ingredient_id, ing.name as ingredient_name, st.unit,
+       st.unit_cost, st.total_cost, st.average_cost, st.location_id, sl.name as location_name
 FROM stock_transactions st
 LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
 LEFT JOIN users u ON st.user_id = u.id
+LEFT JOIN stock_locations sl ON st.location_id = sl.id
 WHERE ($1 = '00000000-0000-0000-0000-000000000000'::uuid OR st.menu_item_id = $1)
   AND ($2 = '0001-01-01'::date OR st.created_at >= $2)
   AND ($3 = '0001-01-01'::date OR st.created_at <= $3)
+  AND ($6 = '00000000-0000-0000-0000-000000000000'::uuid OR st.ingredient_id = $6)
+  AND ($7 = '00000000-0000-0000-0000-000000000000'::uuid OR st.location_id = $7)
 ORDER BY st.created_at DESC
-LIMIT $4 OFFSET $5;
\ No newline at end of file
+LIMIT $4 OFFSET $5;
+
+-- name: ListStockMovedByReference :many
+-- The net quantity of each menu item and ingredient moved by the stock
+-- transactions of one reference, such as an order
+SELECT menu_item_id, ingredient_id, SUM(quantity)::text AS quantity
+FROM stock_transactions
+WHERE reference_type = $1 AND reference_id = $2
+GROUP BY menu_item_id, ingredient_id
+ORDER BY menu_item_id, ingredient_id;
+
+-- name: GetStockAverageCost :one
+-- The weighted average cost of one stock unit of a menu item or ingredient
+SELECT COALESCE(
+    (SELECT average_cost FROM inventory WHERE menu_item_id = sqlc.narg('menu_item_id')::uuid),
+    (SELECT average_cost FROM ingredients WHERE id = sqlc.narg('ingredient_id')::uuid),
+    0
+)::text AS average_cost;
+
+-- name: SetStockAverageCost :exec
+WITH updated_inventory AS (
+    UPDATE inventory
+    SET average_cost = sqlc.arg('average_cost')::numeric
+    WHERE menu_item_id = sqlc.narg('menu_item_id')::uuid
+)
+UPDATE ingredients
+SET average_cost = sqlc.arg('average_cost')::numeric
+WHERE id = sqlc.narg('ingredient_id')::uuid;
+
+-- name: GetStockValuationAsOf :many
+-- The stock of every menu item and ingredient after its last movement up to
+-- the given time, valued at the weighted average cost at that point
+SELECT v.menu_item_id, v.ingredient_id, v.item_name, v.item_type, v.unit, v.current_stock, v.average_cost, v.stock_value
+FROM (
+    SELECT DISTINCT ON (st.menu_item_id, st.ingredient_id)
+           st.menu_item_id, st.ingredient_id,
+           COALESCE(mi.name, ing.name)::text AS item_name,
+           CASE WHEN st.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+           st.unit, st.current_stock, st.average_cost,
+           ROUND(st.current_stock * st.average_cost, 2) AS stock_value
+    FROM stock_transactions st
+    LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+    LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
+    WHERE st.created_at <= $1
+    ORDER BY st.menu_item_id, st.ingredient_id, st.created_at DESC
+) v
+WHERE v.current_stock <> 0
+ORDER BY v.stock_value DESC, v.item_name;
+
+-- name: GetCostOfGoodsSoldByItemByDateRange :many
+-- The cost of the stock that went out for orders, less what was put back by
+-- cancellations and refunds, per menu item and ingredient, most costly first
+SELECT COALESCE(mi.name, ing.name)::text AS item_name,
+       CASE WHEN st.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+       st.unit,
+       (-SUM(st.quantity))::text AS quantity,
+       (-SUM(st.total_cost))::text AS cost
+FROM stock_transactions st
+LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
+WHERE st.reference_type IN ('order', 'refund')
+  AND st.created_at >= sqlc.arg('start_date') AND st.created_at <= sqlc.arg('end_date')
+GROUP BY item_name, item_type, st.unit
+ORDER BY -SUM(st.total_cost) DESC, item_name;
@@ -0,0 +1,26 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (
+    name, contact_name, phone, email, address, is_active
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, name, contact_name, phone, email, address, is_active, created_at, updated_at;
+
+-- name: GetSupplier :one
+SELECT id, name, contact_name, phone, email, address, is_active, created_at, updated_at
+FROM suppliers
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListSuppliers :many
+SELECT id, name, contact_name, phone, email, address, is_active, created_at, updated_at
+FROM suppliers
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = $2, contact_name = $3, phone = $4, email = $5, address = $6, is_active = $7, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, contact_name, phone, email, address, is_active, created_at, updated_at;
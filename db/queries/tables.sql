@@ -0,0 +1,78 @@
+-- name: CreateFloorArea :one
+INSERT INTO floor_areas (
+    name, sort_order, is_active
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, name, sort_order, is_active, created_at, updated_at;
+
+-- name: GetFloorArea :one
+SELECT id, name, sort_order, is_active, created_at, updated_at
+FROM floor_areas
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListFloorAreas :many
+SELECT id, name, sort_order, is_active, created_at, updated_at
+FROM floor_areas
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+ORDER BY sort_order, name;
+
+-- name: UpdateFloorArea :one
+UPDATE floor_areas
+SET name = $2, sort_order = $3, is_active = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, sort_order, is_active, created_at, updated_at;
+
+-- name: DeleteFloorArea :exec
+DELETE FROM floor_areas
+WHERE id = $1;
+
+-- name: CreateDiningTable :one
+INSERT INTO dining_tables (
+    floor_area_id, name, seats, sort_order, is_active
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, floor_area_id, name, seats, sort_order, is_active, created_at, updated_at;
+
+-- name: GetDiningTable :one
+SELECT id, floor_area_id, name, seats, sort_order, is_active, created_at, updated_at
+FROM dining_tables
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListDiningTables :many
+SELECT dt.id, dt.floor_area_id, dt.name, dt.seats, dt.sort_order, dt.is_active, dt.created_at, dt.updated_at
+FROM dining_tables dt
+JOIN floor_areas fa ON fa.id = dt.floor_area_id
+WHERE (sqlc.narg('floor_area_id')::uuid IS NULL OR dt.floor_area_id = sqlc.narg('floor_area_id')::uuid)
+  AND (sqlc.narg('is_active')::boolean IS NULL OR dt.is_active = sqlc.narg('is_active')::boolean)
+ORDER BY fa.sort_order, fa.name, dt.sort_order, dt.name;
+
+-- name: UpdateDiningTable :one
+UPDATE dining_tables
+SET floor_area_id = $2, name = $3, seats = $4, sort_order = $5, is_active = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING id, floor_area_id, name, seats, sort_order, is_active, created_at, updated_at;
+
+-- name: DeleteDiningTable :exec
+DELETE FROM dining_tables
+WHERE id = $1;
+
+-- name: CountDiningTablesByFloorAreaID :one
+SELECT COUNT(*)
+FROM dining_tables
+WHERE floor_area_id = $1;
+
+-- name: CountOpenOrdersByTableID :one
+SELECT COUNT(*)
+FROM orders
+WHERE table_id = $1 AND status IN ('draft', 'pending');
+
+-- name: ListOpenTableOrders :many
+-- Orders still open on a table, i.e. the tabs shown on the floor plan
+SELECT id, order_number, table_id, status, guest_count, total_amount, created_at
+FROM orders
+WHERE table_id IS NOT NULL AND status IN ('draft', 'pending')
+ORDER BY created_at;
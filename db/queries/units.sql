@@ -0,0 +1,18 @@
+-- name: ListUnitsOfMeasure :many
+SELECT code, name, dimension, factor, created_at
+FROM units_of_measure
+ORDER BY dimension, factor;
+
+-- name: GetUnitOfMeasure :one
+SELECT code, name, dimension, factor, created_at
+FROM units_of_measure
+WHERE code = $1
+LIMIT 1;
+
+-- name: CreateUnitOfMeasure :one
+INSERT INTO units_of_measure (
+    code, name, dimension, factor
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING code, name, dimension, factor, created_at;
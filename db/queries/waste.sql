@@ -0,0 +1,54 @@
+-- name: CreateWasteRecord :one
+INSERT INTO waste_records (
+    menu_item_id, ingredient_id, reason, quantity, unit, unit_cost, total_cost, notes, user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, menu_item_id, ingredient_id, reason, quantity, unit, unit_cost, total_cost, notes, user_id, created_at;
+
+-- name: ListWasteRecords :many
+SELECT w.id, w.menu_item_id, w.ingredient_id, COALESCE(mi.name, ig.name)::text AS item_name, w.reason, w.quantity, w.unit, w.unit_cost, w.total_cost, w.notes, w.user_id, u.username, w.created_at
+FROM waste_records w
+LEFT JOIN menu_items mi ON w.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON w.ingredient_id = ig.id
+JOIN users u ON w.user_id = u.id
+WHERE (sqlc.narg('reason')::text IS NULL OR w.reason = sqlc.narg('reason')::text)
+  AND (sqlc.narg('user_id')::uuid IS NULL OR w.user_id = sqlc.narg('user_id')::uuid)
+ORDER BY w.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetWasteByItemByDateRange :many
+-- Waste per menu item and ingredient, most costly first
+SELECT COALESCE(mi.name, ig.name)::text AS item_name,
+       CASE WHEN w.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+       w.unit,
+       COUNT(*) AS records,
+       SUM(w.quantity)::text AS total_quantity,
+       SUM(w.total_cost)::text AS total_cost
+FROM waste_records w
+LEFT JOIN menu_items mi ON w.menu_item_id = mi.id
+LEFT JOIN ingredients ig ON w.ingredient_id = ig.id
+WHERE w.created_at >= sqlc.arg('start_date') AND w.created_at <= sqlc.arg('end_date')
+GROUP BY item_name, item_type, w.unit
+ORDER BY SUM(w.total_cost) DESC, item_name;
+
+-- name: GetWasteByReasonByDateRange :many
+SELECT w.reason,
+       COUNT(*) AS records,
+       SUM(w.total_cost)::text AS total_cost
+FROM waste_records w
+WHERE w.created_at >= sqlc.arg('start_date') AND w.created_at <= sqlc.arg('end_date')
+GROUP BY w.reason
+ORDER BY SUM(w.total_cost) DESC;
+
+-- name: GetWasteByUserByDateRange :many
+-- Waste per staff member who recorded it, most costly first
+SELECT w.user_id,
+       u.username,
+       COUNT(*) AS records,
+       SUM(w.total_cost)::text AS total_cost
+FROM waste_records w
+JOIN users u ON w.user_id = u.id
+WHERE w.created_at >= sqlc.arg('start_date') AND w.created_at <= sqlc.arg('end_date')
+GROUP BY w.user_id, u.username
+ORDER BY SUM(w.total_cost) DESC;
@@ -0,0 +1,136 @@
+-- name: LockZReports :exec
+-- Serializes Z report generation so report numbers and periods never overlap,
+-- and waits for orders being completed, voided or refunded to commit so none
+-- fall between periods
+SELECT pg_advisory_xact_lock(hashtext('z_reports'));
+
+-- name: HoldOffZReports :exec
+-- Held while an order is completed, voided or refunded so a Z report cannot
+-- close the period until the change is committed
+SELECT pg_advisory_xact_lock_shared(hashtext('z_reports'));
+
+-- name: GetDatabaseTime :one
+-- The clock sales, voids and refunds are stamped with, so a period ends on it
+SELECT clock_timestamp()::timestamp AS now;
+
+-- name: GetLatestZReport :one
+SELECT id, report_number, period_start, period_end, generated_by, generated_at, order_count, gross_sales, discount_amount, service_charge_amount, tax_amount, rounding_amount, total_sales, refund_count, refund_amount, refund_tax, cancelled_count, voided_count, voided_amount, net_sales, first_order_number, last_order_number
+FROM z_reports
+ORDER BY report_number DESC
+LIMIT 1;
+
+-- name: GetZReport :one
+SELECT id, report_number, period_start, period_end, generated_by, generated_at, order_count, gross_sales, discount_amount, service_charge_amount, tax_amount, rounding_amount, total_sales, refund_count, refund_amount, refund_tax, cancelled_count, voided_count, voided_amount, net_sales, first_order_number, last_order_number
+FROM z_reports
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListZReports :many
+SELECT id, report_number, period_start, period_end, generated_by, generated_at, order_count, gross_sales, discount_amount, service_charge_amount, tax_amount, rounding_amount, total_sales, refund_count, refund_amount, refund_tax, cancelled_count, voided_count, voided_amount, net_sales, first_order_number, last_order_number
+FROM z_reports
+ORDER BY report_number DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CreateZReport :one
+INSERT INTO z_reports (
+    report_number, period_start, period_end, generated_by, order_count, gross_sales, discount_amount, service_charge_amount, tax_amount, rounding_amount, total_sales, refund_count, refund_amount, refund_tax, cancelled_count, voided_count, voided_amount, net_sales, first_order_number, last_order_number
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+)
+RETURNING id, report_number, period_start, period_end, generated_by, generated_at, order_count, gross_sales, discount_amount, service_charge_amount, tax_amount, rounding_amount, total_sales, refund_count, refund_amount, refund_tax, cancelled_count, voided_count, voided_amount, net_sales, first_order_number, last_order_number;
+
+-- name: CreateZReportPaymentMethod :exec
+INSERT INTO z_report_payment_methods (
+    z_report_id, payment_method, order_count, total_amount
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ListZReportPaymentMethods :many
+SELECT z_report_id, payment_method, order_count, total_amount
+FROM z_report_payment_methods
+WHERE z_report_id = $1
+ORDER BY payment_method;
+
+-- name: CreateZReportCashier :exec
+INSERT INTO z_report_cashiers (
+    z_report_id, user_id, cashier_name, order_count, total_sales
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListZReportCashiers :many
+SELECT z_report_id, user_id, cashier_name, order_count, total_sales
+FROM z_report_cashiers
+WHERE z_report_id = $1
+ORDER BY cashier_name, user_id;
+
+-- name: GetSalesTotalsForPeriod :one
+-- Orders completed after period_start (when set) up to and including period_end
+SELECT
+    COUNT(o.id) AS order_count,
+    COALESCE(SUM(o.subtotal_amount), '0')::TEXT AS gross_sales,
+    COALESCE(SUM(o.discount_amount), '0')::TEXT AS discount_amount,
+    COALESCE(SUM(o.service_charge_amount), '0')::TEXT AS service_charge_amount,
+    COALESCE(SUM(o.tax_amount), '0')::TEXT AS tax_amount,
+    COALESCE(SUM(o.rounding_amount), '0')::TEXT AS rounding_amount,
+    COALESCE(SUM(o.total_amount), '0')::TEXT AS total_sales,
+    COALESCE((ARRAY_AGG(o.order_number ORDER BY o.created_at, o.order_number))[1], '')::VARCHAR AS first_order_number,
+    COALESCE((ARRAY_AGG(o.order_number ORDER BY o.created_at DESC, o.order_number DESC))[1], '')::VARCHAR AS last_order_number
+FROM orders o
+WHERE o.status = 'completed'
+AND (sqlc.narg('period_start')::timestamp IS NULL OR o.completed_at > sqlc.narg('period_start')::timestamp)
+AND o.completed_at <= sqlc.arg('period_end')::timestamp;
+
+-- name: GetPaymentMethodTotalsForPeriod :many
+-- Net takings per payment method: cash change handed back is not revenue
+SELECT
+    op.payment_method,
+    COUNT(DISTINCT op.order_id) AS order_count,
+    COALESCE(SUM(op.amount - op.change_amount), '0')::TEXT AS total_amount
+FROM order_payments op
+JOIN orders o ON op.order_id = o.id
+WHERE o.status = 'completed'
+AND (sqlc.narg('period_start')::timestamp IS NULL OR o.completed_at > sqlc.narg('period_start')::timestamp)
+AND o.completed_at <= sqlc.arg('period_end')::timestamp
+GROUP BY op.payment_method
+ORDER BY op.payment_method;
+
+-- name: GetCashierTotalsForPeriod :many
+SELECT
+    o.user_id,
+    u.username,
+    u.first_name,
+    u.last_name,
+    COUNT(o.id) AS order_count,
+    COALESCE(SUM(o.total_amount), '0')::TEXT AS total_sales
+FROM orders o
+JOIN users u ON o.user_id = u.id
+WHERE o.status = 'completed'
+AND (sqlc.narg('period_start')::timestamp IS NULL OR o.completed_at > sqlc.narg('period_start')::timestamp)
+AND o.completed_at <= sqlc.arg('period_end')::timestamp
+GROUP BY o.user_id, u.username, u.first_name, u.last_name
+ORDER BY u.first_name, u.last_name, o.user_id;
+
+-- name: GetRefundTotalsForPeriod :one
+SELECT
+    COUNT(r.id) AS refund_count,
+    COALESCE(SUM(r.amount), '0')::TEXT AS refund_amount,
+    COALESCE(SUM(r.tax_amount), '0')::TEXT AS refund_tax
+FROM refunds r
+WHERE (sqlc.narg('period_start')::timestamp IS NULL OR r.created_at > sqlc.narg('period_start')::timestamp)
+AND r.created_at <= sqlc.arg('period_end')::timestamp;
+
+-- name: GetCancellationTotalsForPeriod :one
+-- Orders cancelled in the period; voided orders had been paid for and their
+-- payment was given back. Voided orders completed before the period were
+-- counted in an earlier period's sales, so their net amount is taken off this one.
+SELECT
+    COUNT(o.id) AS cancelled_count,
+    COUNT(o.id) FILTER (WHERE o.payment_status = 'refunded') AS voided_count,
+    COALESCE(SUM(o.total_amount) FILTER (WHERE o.payment_status = 'refunded'), '0')::TEXT AS voided_amount,
+    COALESCE(SUM(o.total_amount - o.tax_amount) FILTER (WHERE o.payment_status = 'refunded' AND o.completed_at <= sqlc.narg('period_start')::timestamp), '0')::TEXT AS earlier_voided_net
+FROM orders o
+WHERE o.status = 'cancelled'
+AND (sqlc.narg('period_start')::timestamp IS NULL OR o.cancelled_at > sqlc.narg('period_start')::timestamp)
+AND o.cancelled_at <= sqlc.arg('period_end')::timestamp;
@@ -0,0 +1,84 @@
+package availability
+
+import (
+	"sync"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// EventMenuAvailabilityChanged is pushed when menu items sell out or come back
+// on the menu as stock moves
+const EventMenuAvailabilityChanged = "menu_availability_changed"
+
+// subscriberBuffer is how many events a client may fall behind before it
+// starts missing them
+const subscriberBuffer = 32
+
+// Event is a change to which menu items can be sold
+type Event struct {
+	Type  string                         `json:"type"`
+	Items []*models.MenuItemAvailability `json:"items"`
+}
+
+// Broker fans menu availability events out to the clients connected to this
+// server. It keeps no history: a client that connects, reconnects or falls
+// behind should reload the menu from the API.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewBroker creates a new menu availability event broker
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a client. The returned function unsubscribes and closes
+// the channel.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	events := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[events] = struct{}{}
+	b.mu.Unlock()
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[events]; ok {
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Close ends every subscription, so streams finish when the server shuts down
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers {
+		delete(b.subscribers, events)
+		close(events)
+	}
+}
+
+// Publish sends an event to every client. Publishing on a nil broker does nothing.
+func (b *Broker) Publish(event Event) {
+	if b == nil || len(event.Items) == 0 {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for events := range b.subscribers {
+		// Never block the request that published: a client that is not
+		// keeping up misses the event and catches up on its next reload
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -25,15 +27,62 @@ type RedisConfig struct {
 	URL string
 }
 
+// OrderConfig holds order numbering configuration
+type OrderConfig struct {
+	NumberPrefix     string // e.g. a per-outlet code; each prefix has its own daily counter
+	NumberPattern    string // placeholders: {prefix}, {date}, {seq}
+	NumberDateLayout string
+	NumberDigits     int
+	// RefundApprovalThreshold is the refund amount above which a manager must
+	// authorize the refund; 0 means every refund needs a manager
+	RefundApprovalThreshold string
+}
+
+// PricingConfig holds checkout pricing configuration. Rates are percentages.
+type PricingConfig struct {
+	TaxRate           string // PPN
+	ServiceChargeRate string
+	RoundingIncrement string // smallest payable unit, e.g. 100; 0 disables rounding
+	RoundingMode      string // nearest, up or down
+}
+
+// ReceiptConfig holds the store details printed on receipts. The address and
+// footer may span several lines separated by a literal \n.
+type ReceiptConfig struct {
+	StoreName string
+	Address   string
+	NPWP      string
+	Footer    string
+}
+
+// NotificationConfig holds where low stock alerts are delivered. Email is sent
+// when an SMTP host and recipients are set, and the webhook is called when a
+// URL is set; alerts always appear in the in-app feed.
+type NotificationConfig struct {
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPFrom         string
+	AlertEmails      []string
+	WebhookURL       string
+	WebhookSecret    string // signs webhook bodies with HMAC-SHA256 when set
+	DispatchInterval string // how often pending alerts are delivered
+}
+
 // AppConfig holds application configuration
 type AppConfig struct {
-	Environment string
-	Port        string
-	JWTSecret   string
-	JWTExpiry   string
-	LogLevel    string
-	DB          DBConfig
-	Redis       RedisConfig
+	Environment  string
+	Port         string
+	JWTSecret    string
+	JWTExpiry    string
+	LogLevel     string
+	DB           DBConfig
+	Redis        RedisConfig
+	Order        OrderConfig
+	Pricing      PricingConfig
+	Receipt      ReceiptConfig
+	Notification NotificationConfig
 }
 
 // LoadConfig loads configuration from environment variables
@@ -75,6 +124,37 @@ func LoadConfig() *AppConfig {
 		Redis: RedisConfig{
 			URL: getEnv("REDIS_URL", ""),
 		},
+		Order: OrderConfig{
+			NumberPrefix:     getEnv("ORDER_NUMBER_PREFIX", "ORD"),
+			NumberPattern:    getEnv("ORDER_NUMBER_PATTERN", "{prefix}-{date}-{seq}"),
+			NumberDateLayout: getEnv("ORDER_NUMBER_DATE_LAYOUT", "20060102"),
+			NumberDigits:     getEnvInt("ORDER_NUMBER_DIGITS", 4),
+
+			RefundApprovalThreshold: getEnv("REFUND_APPROVAL_THRESHOLD", "0"),
+		},
+		Pricing: PricingConfig{
+			TaxRate:           getEnv("TAX_RATE", "0"),
+			ServiceChargeRate: getEnv("SERVICE_CHARGE_RATE", "0"),
+			RoundingIncrement: getEnv("ROUNDING_INCREMENT", "0"),
+			RoundingMode:      getEnv("ROUNDING_MODE", "nearest"),
+		},
+		Receipt: ReceiptConfig{
+			StoreName: getEnv("STORE_NAME", "POS Cafe"),
+			Address:   strings.ReplaceAll(getEnv("STORE_ADDRESS", ""), `\n`, "\n"),
+			NPWP:      getEnv("STORE_NPWP", ""),
+			Footer:    strings.ReplaceAll(getEnv("RECEIPT_FOOTER", "Thank you for your visit!"), `\n`, "\n"),
+		},
+		Notification: NotificationConfig{
+			SMTPHost:         getEnv("SMTP_HOST", ""),
+			SMTPPort:         getEnvInt("SMTP_PORT", 587),
+			SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:         getEnv("SMTP_FROM", ""),
+			AlertEmails:      getEnvList("LOW_STOCK_ALERT_EMAILS"),
+			WebhookURL:       getEnv("LOW_STOCK_WEBHOOK_URL", ""),
+			WebhookSecret:    getEnv("LOW_STOCK_WEBHOOK_SECRET", ""),
+			DispatchInterval: getEnv("LOW_STOCK_ALERT_INTERVAL", "30s"),
+		},
 	}
 
 	// If DATABASE_URL is not set, construct it from individual components
@@ -94,3 +174,22 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt retrieves an integer environment variable or returns default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList retrieves a comma-separated environment variable as a list,
+// leaving out empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
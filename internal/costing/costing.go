@@ -0,0 +1,43 @@
+package costing
+
+import "github.com/shopspring/decimal"
+
+// Scale is the number of decimal places unit costs are kept to
+const Scale = 4
+
+// MoneyScale is the number of decimal places the value of stock is kept to
+const MoneyScale = 2
+
+// Movement is a stock movement valued under weighted-average costing
+type Movement struct {
+	UnitCost    decimal.Decimal // What one stock unit moved at
+	TotalCost   decimal.Decimal // The signed value of the movement
+	AverageCost decimal.Decimal // The average cost of the stock left after it
+}
+
+// Value values a change in stock. Stock coming in at a known cost, such as a
+// delivery, moves the average cost of what is held towards that cost; any
+// other movement, in or out, is valued at the average cost and leaves it as
+// it was.
+func Value(previousStock, averageCost, quantity decimal.Decimal, receivedCost *decimal.Decimal) Movement {
+	movement := Movement{
+		UnitCost:    averageCost,
+		AverageCost: averageCost,
+	}
+
+	if quantity.IsPositive() && receivedCost != nil {
+		movement.UnitCost = receivedCost.Round(Scale)
+		currentStock := previousStock.Add(quantity)
+		if previousStock.IsPositive() {
+			movement.AverageCost = previousStock.Mul(averageCost).
+				Add(quantity.Mul(movement.UnitCost)).
+				Div(currentStock).
+				Round(Scale)
+		} else {
+			movement.AverageCost = movement.UnitCost
+		}
+	}
+
+	movement.TotalCost = quantity.Mul(movement.UnitCost).Round(MoneyScale)
+	return movement
+}
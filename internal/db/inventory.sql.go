@@ -13,9 +13,52 @@ import (
 	"github.com/google/uuid"
 )
 
+const adjustInventoryStock = `-- name: AdjustInventoryStock :one
+UPDATE inventory
+SET current_stock = current_stock + $1::numeric,
+    last_updated_at = NOW(),
+    last_updated_by = $2
+WHERE menu_item_id = $3
+  AND current_stock + $1::numeric >= 0
+RETURNING id, menu_item_id, current_stock, minimum_stock, unit, last_updated_at, last_updated_by
+`
+
+type AdjustInventoryStockParams struct {
+	Quantity      string        `db:"quantity" json:"quantity"`
+	LastUpdatedBy uuid.NullUUID `db:"last_updated_by" json:"last_updated_by"`
+	MenuItemID    uuid.UUID     `db:"menu_item_id" json:"menu_item_id"`
+}
+
+type AdjustInventoryStockRow struct {
+	ID            uuid.UUID     `db:"id" json:"id"`
+	MenuItemID    uuid.UUID     `db:"menu_item_id" json:"menu_item_id"`
+	CurrentStock  string        `db:"current_stock" json:"current_stock"`
+	MinimumStock  string        `db:"minimum_stock" json:"minimum_stock"`
+	Unit          string        `db:"unit" json:"unit"`
+	LastUpdatedAt time.Time     `db:"last_updated_at" json:"last_updated_at"`
+	LastUpdatedBy uuid.NullUUID `db:"last_updated_by" json:"last_updated_by"`
+}
+
+// Applies a relative stock change atomically; returns no row when the
+// change would take the stock below zero
+func (q *Queries) AdjustInventoryStock(ctx context.Context, arg AdjustInventoryStockParams) (AdjustInventoryStockRow, error) {
+	row := q.db.QueryRowContext(ctx, adjustInventoryStock, arg.Quantity, arg.LastUpdatedBy, arg.MenuItemID)
+	var i AdjustInventoryStockRow
+	err := row.Scan(
+		&i.ID,
+		&i.MenuItemID,
+		&i.CurrentStock,
+		&i.MinimumStock,
+		&i.Unit,
+		&i.LastUpdatedAt,
+		&i.LastUpdatedBy,
+	)
+	return i, err
+}
+
 const createInventoryRecord = `-- name: CreateInventoryRecord :exec
 INSERT INTO inventory (menu_item_id, current_stock, minimum_stock, unit)
-VALUES ($1, 0, 0, 'pieces')
+VALUES ($1, 0, 0, 'pcs')
 `
 
 func (q *Queries) CreateInventoryRecord(ctx context.Context, menuItemID uuid.UUID) error {
@@ -30,9 +73,19 @@ WHERE menu_item_id = $1
 LIMIT 1
 `
 
-func (q *Queries) GetInventoryByMenuItem(ctx context.Context, menuItemID uuid.UUID) (Inventory, error) {
+type GetInventoryByMenuItemRow struct {
+	ID            uuid.UUID     `db:"id" json:"id"`
+	MenuItemID    uuid.UUID     `db:"menu_item_id" json:"menu_item_id"`
+	CurrentStock  string        `db:"current_stock" json:"current_stock"`
+	MinimumStock  string        `db:"minimum_stock" json:"minimum_stock"`
+	Unit          string        `db:"unit" json:"unit"`
+	LastUpdatedAt time.Time     `db:"last_updated_at" json:"last_updated_at"`
+	LastUpdatedBy uuid.NullUUID `db:"last_updated_by" json:"last_updated_by"`
+}
+
+func (q *Queries) GetInventoryByMenuItem(ctx context.Context, menuItemID uuid.UUID) (GetInventoryByMenuItemRow, error) {
 	row := q.db.QueryRowContext(ctx, getInventoryByMenuItem, menuItemID)
-	var i Inventory
+	var i GetInventoryByMenuItemRow
 	err := row.Scan(
 		&i.ID,
 		&i.MenuItemID,
@@ -66,8 +119,8 @@ type ListInventoryRow struct {
 	ID            uuid.UUID      `db:"id" json:"id"`
 	MenuItemID    uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
 	MenuItemName  string         `db:"menu_item_name" json:"menu_item_name"`
-	CurrentStock  int32          `db:"current_stock" json:"current_stock"`
-	MinimumStock  int32          `db:"minimum_stock" json:"minimum_stock"`
+	CurrentStock  string         `db:"current_stock" json:"current_stock"`
+	MinimumStock  string         `db:"minimum_stock" json:"minimum_stock"`
 	Unit          string         `db:"unit" json:"unit"`
 	LastUpdatedAt time.Time      `db:"last_updated_at" json:"last_updated_at"`
 	LastUpdatedBy sql.NullString `db:"last_updated_by" json:"last_updated_by"`
@@ -113,7 +166,7 @@ WHERE menu_item_id = $1
 
 type UpdateInventoryStockParams struct {
 	MenuItemID    uuid.UUID     `db:"menu_item_id" json:"menu_item_id"`
-	CurrentStock  int32         `db:"current_stock" json:"current_stock"`
+	CurrentStock  string        `db:"current_stock" json:"current_stock"`
 	LastUpdatedBy uuid.NullUUID `db:"last_updated_by" json:"last_updated_by"`
 }
 
@@ -18,7 +18,7 @@ INSERT INTO menu_items (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 `
 
 type CreateMenuItemParams struct {
@@ -48,13 +48,14 @@ func (q *Queries) CreateMenuItem(ctx context.Context, arg CreateMenuItemParams)
 		&i.IsAvailable,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoldOut,
 	)
 	return i, err
 }
 
 const deleteMenuItem = `-- name: DeleteMenuItem :exec
 UPDATE menu_items
-SET is_available = false, updated_at = NOW()
+SET is_available = false, sold_out = false, updated_at = NOW()
 WHERE id = $1
 `
 
@@ -64,9 +65,9 @@ func (q *Queries) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
 }
 
 const getMenuItem = `-- name: GetMenuItem :one
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
-WHERE id = $1 AND is_available = true
+WHERE id = $1 AND (is_available = true OR sold_out = true)
 LIMIT 1
 `
 
@@ -83,12 +84,13 @@ func (q *Queries) GetMenuItem(ctx context.Context, id uuid.UUID) (MenuItem, erro
 		&i.IsAvailable,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoldOut,
 	)
 	return i, err
 }
 
 const listMenuItems = `-- name: ListMenuItems :many
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
 WHERE is_available = $1
 ORDER BY name
@@ -120,6 +122,7 @@ func (q *Queries) ListMenuItems(ctx context.Context, arg ListMenuItemsParams) ([
 			&i.IsAvailable,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SoldOut,
 		); err != nil {
 			return nil, err
 		}
@@ -135,7 +138,7 @@ func (q *Queries) ListMenuItems(ctx context.Context, arg ListMenuItemsParams) ([
 }
 
 const listMenuItemsByCategory = `-- name: ListMenuItemsByCategory :many
-SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at
+SELECT id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 FROM menu_items
 WHERE category_id = $1 AND is_available = true
 ORDER BY name
@@ -167,6 +170,92 @@ func (q *Queries) ListMenuItemsByCategory(ctx context.Context, arg ListMenuItems
 			&i.IsAvailable,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SoldOut,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncMenuItemAvailability = `-- name: SyncMenuItemAvailability :many
+WITH selling AS (
+    SELECT id FROM stock_locations WHERE is_selling = true
+), affected AS (
+    SELECT mi.id
+    FROM menu_items mi
+    WHERE mi.id = $1
+      AND NOT EXISTS (SELECT 1 FROM recipe_items ri WHERE ri.menu_item_id = mi.id)
+    UNION
+    SELECT ri.menu_item_id
+    FROM recipe_items ri
+    WHERE ri.ingredient_id = $2 AND ri.menu_item_id IS NOT NULL
+), stocked AS (
+    SELECT a.id,
+           CASE WHEN EXISTS (SELECT 1 FROM recipe_items ri WHERE ri.menu_item_id = a.id) THEN
+               NOT EXISTS (
+                   SELECT 1
+                   FROM recipe_items ri
+                   LEFT JOIN stock_balances sb ON sb.ingredient_id = ri.ingredient_id AND sb.location_id = (SELECT id FROM selling)
+                   WHERE ri.menu_item_id = a.id AND COALESCE(sb.quantity, 0) < ri.quantity
+               )
+           ELSE
+               COALESCE((
+                   SELECT sb.quantity
+                   FROM stock_balances sb
+                   WHERE sb.menu_item_id = a.id AND sb.location_id = (SELECT id FROM selling)
+               ), 0) >= 1
+           END AS in_stock
+    FROM affected a
+)
+UPDATE menu_items mi
+SET is_available = s.in_stock, sold_out = NOT s.in_stock, updated_at = NOW()
+FROM stocked s
+WHERE mi.id = s.id
+  AND ((s.in_stock AND mi.sold_out) OR (NOT s.in_stock AND mi.is_available))
+RETURNING mi.id, mi.name, mi.category_id, mi.is_available, mi.sold_out
+`
+
+type SyncMenuItemAvailabilityParams struct {
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+}
+
+type SyncMenuItemAvailabilityRow struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	CategoryID  uuid.UUID `db:"category_id" json:"category_id"`
+	IsAvailable bool      `db:"is_available" json:"is_available"`
+	SoldOut     bool      `db:"sold_out" json:"sold_out"`
+}
+
+// Takes the menu items made from the stock of a menu item or an ingredient off
+// the menu when the selling location no longer holds enough to make one, and
+// puts those it took off back when it does. A menu item with a recipe is made
+// from its ingredients, one without from its own stock. Items taken off the
+// menu by hand are left alone. Returns the items whose availability changed.
+func (q *Queries) SyncMenuItemAvailability(ctx context.Context, arg SyncMenuItemAvailabilityParams) ([]SyncMenuItemAvailabilityRow, error) {
+	rows, err := q.db.QueryContext(ctx, syncMenuItemAvailability, arg.MenuItemID, arg.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SyncMenuItemAvailabilityRow
+	for rows.Next() {
+		var i SyncMenuItemAvailabilityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CategoryID,
+			&i.IsAvailable,
+			&i.SoldOut,
 		); err != nil {
 			return nil, err
 		}
@@ -183,9 +272,9 @@ func (q *Queries) ListMenuItemsByCategory(ctx context.Context, arg ListMenuItems
 
 const updateMenuItem = `-- name: UpdateMenuItem :one
 UPDATE menu_items
-SET name = $2, category_id = $3, description = $4, price = $5, cost = $6, is_available = $7, updated_at = NOW()
+SET name = $2, category_id = $3, description = $4, price = $5, cost = $6, is_available = $7, sold_out = $8, updated_at = NOW()
 WHERE id = $1
-RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
 `
 
 type UpdateMenuItemParams struct {
@@ -196,6 +285,7 @@ type UpdateMenuItemParams struct {
 	Price       string         `db:"price" json:"price"`
 	Cost        string         `db:"cost" json:"cost"`
 	IsAvailable bool           `db:"is_available" json:"is_available"`
+	SoldOut     bool           `db:"sold_out" json:"sold_out"`
 }
 
 func (q *Queries) UpdateMenuItem(ctx context.Context, arg UpdateMenuItemParams) (MenuItem, error) {
@@ -207,7 +297,39 @@ func (q *Queries) UpdateMenuItem(ctx context.Context, arg UpdateMenuItemParams)
 		arg.Price,
 		arg.Cost,
 		arg.IsAvailable,
+		arg.SoldOut,
+	)
+	var i MenuItem
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CategoryID,
+		&i.Description,
+		&i.Price,
+		&i.Cost,
+		&i.IsAvailable,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoldOut,
 	)
+	return i, err
+}
+
+const updateMenuItemCost = `-- name: UpdateMenuItemCost :one
+UPDATE menu_items
+SET cost = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, category_id, description, price, cost, is_available, created_at, updated_at, sold_out
+`
+
+type UpdateMenuItemCostParams struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Cost string    `db:"cost" json:"cost"`
+}
+
+// Records the cost of a menu item bought in as finished goods
+func (q *Queries) UpdateMenuItemCost(ctx context.Context, arg UpdateMenuItemCostParams) (MenuItem, error) {
+	row := q.db.QueryRowContext(ctx, updateMenuItemCost, arg.ID, arg.Cost)
 	var i MenuItem
 	err := row.Scan(
 		&i.ID,
@@ -219,6 +341,7 @@ func (q *Queries) UpdateMenuItem(ctx context.Context, arg UpdateMenuItemParams)
 		&i.IsAvailable,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoldOut,
 	)
 	return i, err
 }
@@ -11,6 +11,36 @@ import (
 	"github.com/google/uuid"
 )
 
+type CashMovement struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	ShiftID      uuid.UUID `db:"shift_id" json:"shift_id"`
+	MovementType string    `db:"movement_type" json:"movement_type"`
+	Amount       string    `db:"amount" json:"amount"`
+	Reason       string    `db:"reason" json:"reason"`
+	CreatedBy    uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+type CashShift struct {
+	ID           uuid.UUID      `db:"id" json:"id"`
+	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
+	Status       string         `db:"status" json:"status"`
+	OpeningFloat string         `db:"opening_float" json:"opening_float"`
+	ExpectedCash sql.NullString `db:"expected_cash" json:"expected_cash"`
+	CountedCash  sql.NullString `db:"counted_cash" json:"counted_cash"`
+	Variance     sql.NullString `db:"variance" json:"variance"`
+	Notes        sql.NullString `db:"notes" json:"notes"`
+	OpenedAt     time.Time      `db:"opened_at" json:"opened_at"`
+	ClosedAt     sql.NullTime   `db:"closed_at" json:"closed_at"`
+	ClosedBy     uuid.NullUUID  `db:"closed_by" json:"closed_by"`
+}
+
+type CashShiftCount struct {
+	ShiftID      uuid.UUID `db:"shift_id" json:"shift_id"`
+	Denomination string    `db:"denomination" json:"denomination"`
+	Quantity     int32     `db:"quantity" json:"quantity"`
+}
+
 type Category struct {
 	ID          uuid.UUID      `db:"id" json:"id"`
 	Name        string         `db:"name" json:"name"`
@@ -28,6 +58,17 @@ type DailySalesSummary struct {
 	TotalTax      int64     `db:"total_tax" json:"total_tax"`
 }
 
+type DiningTable struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	FloorAreaID uuid.UUID `db:"floor_area_id" json:"floor_area_id"`
+	Name        string    `db:"name" json:"name"`
+	Seats       int32     `db:"seats" json:"seats"`
+	SortOrder   int32     `db:"sort_order" json:"sort_order"`
+	IsActive    bool      `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Expense struct {
 	ID          uuid.UUID      `db:"id" json:"id"`
 	Category    string         `db:"category" json:"category"`
@@ -38,28 +79,125 @@ type Expense struct {
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 }
 
+type FloorArea struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	SortOrder int32     `db:"sort_order" json:"sort_order"`
+	IsActive  bool      `db:"is_active" json:"is_active"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type GoodsReceivedNote struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	GrnNumber       string         `db:"grn_number" json:"grn_number"`
+	PurchaseOrderID uuid.UUID      `db:"purchase_order_id" json:"purchase_order_id"`
+	TotalAmount     string         `db:"total_amount" json:"total_amount"`
+	ExpenseID       uuid.NullUUID  `db:"expense_id" json:"expense_id"`
+	Notes           sql.NullString `db:"notes" json:"notes"`
+	ReceivedBy      uuid.UUID      `db:"received_by" json:"received_by"`
+	ReceivedAt      time.Time      `db:"received_at" json:"received_at"`
+}
+
+type GoodsReceivedNoteItem struct {
+	ID                  uuid.UUID `db:"id" json:"id"`
+	GoodsReceivedNoteID uuid.UUID `db:"goods_received_note_id" json:"goods_received_note_id"`
+	PurchaseOrderItemID uuid.UUID `db:"purchase_order_item_id" json:"purchase_order_item_id"`
+	Quantity            string    `db:"quantity" json:"quantity"`
+	StockQuantity       string    `db:"stock_quantity" json:"stock_quantity"`
+	UnitCost            string    `db:"unit_cost" json:"unit_cost"`
+}
+
+type Ingredient struct {
+	ID            uuid.UUID      `db:"id" json:"id"`
+	Name          string         `db:"name" json:"name"`
+	Unit          string         `db:"unit" json:"unit"`
+	CurrentStock  string         `db:"current_stock" json:"current_stock"`
+	MinimumStock  string         `db:"minimum_stock" json:"minimum_stock"`
+	IsActive      bool           `db:"is_active" json:"is_active"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+	LastUpdatedBy uuid.NullUUID  `db:"last_updated_by" json:"last_updated_by"`
+	PurchaseUnit  sql.NullString `db:"purchase_unit" json:"purchase_unit"`
+	CostPrice     string         `db:"cost_price" json:"cost_price"`
+	AverageCost   string         `db:"average_cost" json:"average_cost"`
+}
+
 type Inventory struct {
 	ID            uuid.UUID     `db:"id" json:"id"`
 	MenuItemID    uuid.UUID     `db:"menu_item_id" json:"menu_item_id"`
-	CurrentStock  int32         `db:"current_stock" json:"current_stock"`
-	MinimumStock  int32         `db:"minimum_stock" json:"minimum_stock"`
+	CurrentStock  string        `db:"current_stock" json:"current_stock"`
+	MinimumStock  string        `db:"minimum_stock" json:"minimum_stock"`
 	Unit          string        `db:"unit" json:"unit"`
 	LastUpdatedAt time.Time     `db:"last_updated_at" json:"last_updated_at"`
 	LastUpdatedBy uuid.NullUUID `db:"last_updated_by" json:"last_updated_by"`
+	AverageCost   string        `db:"average_cost" json:"average_cost"`
 }
 
 type InventoryWithDetail struct {
 	ID                    uuid.UUID      `db:"id" json:"id"`
 	MenuItemID            uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
 	MenuItemName          string         `db:"menu_item_name" json:"menu_item_name"`
-	CurrentStock          int32          `db:"current_stock" json:"current_stock"`
-	MinimumStock          int32          `db:"minimum_stock" json:"minimum_stock"`
+	CurrentStock          string         `db:"current_stock" json:"current_stock"`
+	MinimumStock          string         `db:"minimum_stock" json:"minimum_stock"`
 	Unit                  string         `db:"unit" json:"unit"`
 	LastUpdatedAt         time.Time      `db:"last_updated_at" json:"last_updated_at"`
 	LastUpdatedByUsername sql.NullString `db:"last_updated_by_username" json:"last_updated_by_username"`
 	StockStatus           string         `db:"stock_status" json:"stock_status"`
 }
 
+type KitchenStation struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Name        string         `db:"name" json:"name"`
+	Description sql.NullString `db:"description" json:"description"`
+	IsActive    bool           `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type KitchenStationCategory struct {
+	CategoryID uuid.UUID `db:"category_id" json:"category_id"`
+	StationID  uuid.UUID `db:"station_id" json:"station_id"`
+}
+
+type KitchenTicket struct {
+	ID          uuid.UUID     `db:"id" json:"id"`
+	OrderID     uuid.UUID     `db:"order_id" json:"order_id"`
+	OrderItemID uuid.UUID     `db:"order_item_id" json:"order_item_id"`
+	StationID   uuid.NullUUID `db:"station_id" json:"station_id"`
+	Status      string        `db:"status" json:"status"`
+	QueuedAt    time.Time     `db:"queued_at" json:"queued_at"`
+	PreparingAt sql.NullTime  `db:"preparing_at" json:"preparing_at"`
+	ReadyAt     sql.NullTime  `db:"ready_at" json:"ready_at"`
+	ServedAt    sql.NullTime  `db:"served_at" json:"served_at"`
+	BumpedBy    uuid.NullUUID `db:"bumped_by" json:"bumped_by"`
+}
+
+type LowStockAlert struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	MenuItemID         uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID       uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	ItemName           string         `db:"item_name" json:"item_name"`
+	Unit               string         `db:"unit" json:"unit"`
+	CurrentStock       string         `db:"current_stock" json:"current_stock"`
+	MinimumStock       string         `db:"minimum_stock" json:"minimum_stock"`
+	StockTransactionID uuid.UUID      `db:"stock_transaction_id" json:"stock_transaction_id"`
+	RaisedAt           time.Time      `db:"raised_at" json:"raised_at"`
+	ResolvedAt         sql.NullTime   `db:"resolved_at" json:"resolved_at"`
+	ReadAt             sql.NullTime   `db:"read_at" json:"read_at"`
+	ReadBy             uuid.NullUUID  `db:"read_by" json:"read_by"`
+	DispatchedAt       sql.NullTime   `db:"dispatched_at" json:"dispatched_at"`
+	DispatchAttempts   int32          `db:"dispatch_attempts" json:"dispatch_attempts"`
+	NextAttemptAt      time.Time      `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError          sql.NullString `db:"last_error" json:"last_error"`
+}
+
+type LowStockAlertDelivery struct {
+	AlertID     uuid.UUID `db:"alert_id" json:"alert_id"`
+	Channel     string    `db:"channel" json:"channel"`
+	DeliveredAt time.Time `db:"delivered_at" json:"delivered_at"`
+}
+
 type MenuItem struct {
 	ID          uuid.UUID      `db:"id" json:"id"`
 	Name        string         `db:"name" json:"name"`
@@ -70,6 +208,13 @@ type MenuItem struct {
 	IsAvailable bool           `db:"is_available" json:"is_available"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	SoldOut     bool           `db:"sold_out" json:"sold_out"`
+}
+
+type MenuItemModifierGroup struct {
+	MenuItemID      uuid.UUID `db:"menu_item_id" json:"menu_item_id"`
+	ModifierGroupID uuid.UUID `db:"modifier_group_id" json:"modifier_group_id"`
+	SortOrder       int32     `db:"sort_order" json:"sort_order"`
 }
 
 type MenuItemsWithCategory struct {
@@ -86,6 +231,28 @@ type MenuItemsWithCategory struct {
 	CategoryDescription sql.NullString `db:"category_description" json:"category_description"`
 }
 
+type ModifierGroup struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Name        string         `db:"name" json:"name"`
+	Description sql.NullString `db:"description" json:"description"`
+	MinSelect   int32          `db:"min_select" json:"min_select"`
+	MaxSelect   int32          `db:"max_select" json:"max_select"`
+	IsActive    bool           `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type ModifierOption struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	ModifierGroupID uuid.UUID `db:"modifier_group_id" json:"modifier_group_id"`
+	Name            string    `db:"name" json:"name"`
+	PriceDelta      string    `db:"price_delta" json:"price_delta"`
+	IsAvailable     bool      `db:"is_available" json:"is_available"`
+	SortOrder       int32     `db:"sort_order" json:"sort_order"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type MonthlySalesSummary struct {
 	SaleMonth     time.Time `db:"sale_month" json:"sale_month"`
 	TotalOrders   int64     `db:"total_orders" json:"total_orders"`
@@ -95,29 +262,61 @@ type MonthlySalesSummary struct {
 }
 
 type Order struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	OrderNumber    string         `db:"order_number" json:"order_number"`
-	UserID         uuid.UUID      `db:"user_id" json:"user_id"`
-	Status         string         `db:"status" json:"status"`
-	TotalAmount    string         `db:"total_amount" json:"total_amount"`
-	DiscountAmount string         `db:"discount_amount" json:"discount_amount"`
-	TaxAmount      string         `db:"tax_amount" json:"tax_amount"`
-	PaymentMethod  sql.NullString `db:"payment_method" json:"payment_method"`
-	PaymentStatus  string         `db:"payment_status" json:"payment_status"`
-	CompletedAt    sql.NullTime   `db:"completed_at" json:"completed_at"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	ID                  uuid.UUID      `db:"id" json:"id"`
+	OrderNumber         string         `db:"order_number" json:"order_number"`
+	UserID              uuid.UUID      `db:"user_id" json:"user_id"`
+	Status              string         `db:"status" json:"status"`
+	TotalAmount         string         `db:"total_amount" json:"total_amount"`
+	DiscountAmount      string         `db:"discount_amount" json:"discount_amount"`
+	TaxAmount           string         `db:"tax_amount" json:"tax_amount"`
+	PaymentMethod       sql.NullString `db:"payment_method" json:"payment_method"`
+	PaymentStatus       string         `db:"payment_status" json:"payment_status"`
+	CompletedAt         sql.NullTime   `db:"completed_at" json:"completed_at"`
+	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
+	CancellationReason  sql.NullString `db:"cancellation_reason" json:"cancellation_reason"`
+	CancelledBy         uuid.NullUUID  `db:"cancelled_by" json:"cancelled_by"`
+	CancelledAt         sql.NullTime   `db:"cancelled_at" json:"cancelled_at"`
+	SubtotalAmount      string         `db:"subtotal_amount" json:"subtotal_amount"`
+	ServiceChargeAmount string         `db:"service_charge_amount" json:"service_charge_amount"`
+	RoundingAmount      string         `db:"rounding_amount" json:"rounding_amount"`
+	ServiceChargeRate   string         `db:"service_charge_rate" json:"service_charge_rate"`
+	TaxRate             string         `db:"tax_rate" json:"tax_rate"`
+	OrderType           string         `db:"order_type" json:"order_type"`
+	TableID             uuid.NullUUID  `db:"table_id" json:"table_id"`
+	GuestCount          sql.NullInt32  `db:"guest_count" json:"guest_count"`
+	ShiftID             uuid.NullUUID  `db:"shift_id" json:"shift_id"`
+	VoidShiftID         uuid.NullUUID  `db:"void_shift_id" json:"void_shift_id"`
 }
 
 type OrderItem struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	OrderID    uuid.UUID `db:"order_id" json:"order_id"`
-	MenuItemID uuid.UUID `db:"menu_item_id" json:"menu_item_id"`
-	Quantity   int32     `db:"quantity" json:"quantity"`
-	UnitPrice  string    `db:"unit_price" json:"unit_price"`
-	TotalPrice string    `db:"total_price" json:"total_price"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	ID         uuid.UUID      `db:"id" json:"id"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+}
+
+type OrderItemModifier struct {
+	ID               uuid.UUID     `db:"id" json:"id"`
+	OrderItemID      uuid.UUID     `db:"order_item_id" json:"order_item_id"`
+	ModifierOptionID uuid.NullUUID `db:"modifier_option_id" json:"modifier_option_id"`
+	GroupName        string        `db:"group_name" json:"group_name"`
+	OptionName       string        `db:"option_name" json:"option_name"`
+	PriceDelta       string        `db:"price_delta" json:"price_delta"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+}
+
+type OrderItemStockUsage struct {
+	ID           uuid.UUID     `db:"id" json:"id"`
+	OrderItemID  uuid.UUID     `db:"order_item_id" json:"order_item_id"`
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Quantity     string        `db:"quantity" json:"quantity"`
 }
 
 type OrderItemsWithDetail struct {
@@ -131,6 +330,32 @@ type OrderItemsWithDetail struct {
 	TotalPrice   string    `db:"total_price" json:"total_price"`
 }
 
+type OrderNumberSequence struct {
+	Prefix       string    `db:"prefix" json:"prefix"`
+	BusinessDate time.Time `db:"business_date" json:"business_date"`
+	LastNumber   int32     `db:"last_number" json:"last_number"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type OrderPayment struct {
+	ID            uuid.UUID      `db:"id" json:"id"`
+	OrderID       uuid.UUID      `db:"order_id" json:"order_id"`
+	PaymentMethod string         `db:"payment_method" json:"payment_method"`
+	Amount        string         `db:"amount" json:"amount"`
+	Reference     sql.NullString `db:"reference" json:"reference"`
+	ChangeAmount  string         `db:"change_amount" json:"change_amount"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+}
+
+type OrderPromotion struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrderID        uuid.UUID     `db:"order_id" json:"order_id"`
+	PromotionID    uuid.NullUUID `db:"promotion_id" json:"promotion_id"`
+	PromotionName  string        `db:"promotion_name" json:"promotion_name"`
+	DiscountAmount string        `db:"discount_amount" json:"discount_amount"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
 type OrdersWithUser struct {
 	ID             uuid.UUID      `db:"id" json:"id"`
 	OrderNumber    string         `db:"order_number" json:"order_number"`
@@ -149,18 +374,211 @@ type OrdersWithUser struct {
 	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
 }
 
+type Promotion struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	Name           string         `db:"name" json:"name"`
+	Description    sql.NullString `db:"description" json:"description"`
+	PromotionType  string         `db:"promotion_type" json:"promotion_type"`
+	Value          string         `db:"value" json:"value"`
+	BuyQuantity    sql.NullInt32  `db:"buy_quantity" json:"buy_quantity"`
+	GetQuantity    sql.NullInt32  `db:"get_quantity" json:"get_quantity"`
+	TargetType     string         `db:"target_type" json:"target_type"`
+	TargetID       uuid.NullUUID  `db:"target_id" json:"target_id"`
+	VoucherCode    sql.NullString `db:"voucher_code" json:"voucher_code"`
+	MinSpend       string         `db:"min_spend" json:"min_spend"`
+	StartsAt       sql.NullTime   `db:"starts_at" json:"starts_at"`
+	EndsAt         sql.NullTime   `db:"ends_at" json:"ends_at"`
+	DailyStartTime sql.NullString `db:"daily_start_time" json:"daily_start_time"`
+	DailyEndTime   sql.NullString `db:"daily_end_time" json:"daily_end_time"`
+	UsageLimit     sql.NullInt32  `db:"usage_limit" json:"usage_limit"`
+	UsageCount     int32          `db:"usage_count" json:"usage_count"`
+	IsStackable    bool           `db:"is_stackable" json:"is_stackable"`
+	Priority       int32          `db:"priority" json:"priority"`
+	IsActive       bool           `db:"is_active" json:"is_active"`
+	CreatedBy      uuid.NullUUID  `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type PurchaseOrder struct {
+	ID           uuid.UUID      `db:"id" json:"id"`
+	PoNumber     string         `db:"po_number" json:"po_number"`
+	SupplierID   uuid.UUID      `db:"supplier_id" json:"supplier_id"`
+	Status       string         `db:"status" json:"status"`
+	ExpectedDate sql.NullTime   `db:"expected_date" json:"expected_date"`
+	Notes        sql.NullString `db:"notes" json:"notes"`
+	TotalAmount  string         `db:"total_amount" json:"total_amount"`
+	CreatedBy    uuid.UUID      `db:"created_by" json:"created_by"`
+	SentAt       sql.NullTime   `db:"sent_at" json:"sent_at"`
+	ReceivedAt   sql.NullTime   `db:"received_at" json:"received_at"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               uuid.UUID     `db:"id" json:"id"`
+	PurchaseOrderID  uuid.UUID     `db:"purchase_order_id" json:"purchase_order_id"`
+	MenuItemID       uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID     uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Unit             string        `db:"unit" json:"unit"`
+	Quantity         string        `db:"quantity" json:"quantity"`
+	ReceivedQuantity string        `db:"received_quantity" json:"received_quantity"`
+	UnitCost         string        `db:"unit_cost" json:"unit_cost"`
+}
+
+type ReceiptPrint struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	OrderID    uuid.UUID `db:"order_id" json:"order_id"`
+	PrintedBy  uuid.UUID `db:"printed_by" json:"printed_by"`
+	Format     string    `db:"format" json:"format"`
+	CopyNumber int32     `db:"copy_number" json:"copy_number"`
+	PrintedAt  time.Time `db:"printed_at" json:"printed_at"`
+}
+
+type RecipeItem struct {
+	ID               uuid.UUID     `db:"id" json:"id"`
+	MenuItemID       uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	ModifierOptionID uuid.NullUUID `db:"modifier_option_id" json:"modifier_option_id"`
+	IngredientID     uuid.UUID     `db:"ingredient_id" json:"ingredient_id"`
+	Quantity         string        `db:"quantity" json:"quantity"`
+}
+
+type Refund struct {
+	ID            uuid.UUID      `db:"id" json:"id"`
+	OrderID       uuid.UUID      `db:"order_id" json:"order_id"`
+	Amount        string         `db:"amount" json:"amount"`
+	TaxAmount     string         `db:"tax_amount" json:"tax_amount"`
+	PaymentMethod string         `db:"payment_method" json:"payment_method"`
+	Reference     sql.NullString `db:"reference" json:"reference"`
+	Reason        string         `db:"reason" json:"reason"`
+	Restocked     bool           `db:"restocked" json:"restocked"`
+	RefundedBy    uuid.UUID      `db:"refunded_by" json:"refunded_by"`
+	ApprovedBy    uuid.NullUUID  `db:"approved_by" json:"approved_by"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	ShiftID       uuid.NullUUID  `db:"shift_id" json:"shift_id"`
+}
+
+type RefundItem struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	RefundID    uuid.UUID `db:"refund_id" json:"refund_id"`
+	OrderItemID uuid.UUID `db:"order_item_id" json:"order_item_id"`
+	MenuItemID  uuid.UUID `db:"menu_item_id" json:"menu_item_id"`
+	Quantity    int32     `db:"quantity" json:"quantity"`
+	Amount      string    `db:"amount" json:"amount"`
+}
+
+type StockBalance struct {
+	ID           uuid.UUID     `db:"id" json:"id"`
+	LocationID   uuid.UUID     `db:"location_id" json:"location_id"`
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Quantity     string        `db:"quantity" json:"quantity"`
+	UpdatedAt    time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+type StockLocation struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Code        string         `db:"code" json:"code"`
+	Name        string         `db:"name" json:"name"`
+	Description sql.NullString `db:"description" json:"description"`
+	IsSelling   bool           `db:"is_selling" json:"is_selling"`
+	IsActive    bool           `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type StockLot struct {
+	ID                  uuid.UUID      `db:"id" json:"id"`
+	MenuItemID          uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID        uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	LotNumber           sql.NullString `db:"lot_number" json:"lot_number"`
+	Unit                string         `db:"unit" json:"unit"`
+	QuantityReceived    string         `db:"quantity_received" json:"quantity_received"`
+	QuantityRemaining   string         `db:"quantity_remaining" json:"quantity_remaining"`
+	UnitCost            string         `db:"unit_cost" json:"unit_cost"`
+	ReceivedAt          time.Time      `db:"received_at" json:"received_at"`
+	ExpiresAt           sql.NullTime   `db:"expires_at" json:"expires_at"`
+	GoodsReceivedNoteID uuid.NullUUID  `db:"goods_received_note_id" json:"goods_received_note_id"`
+	ReceivedBy          uuid.NullUUID  `db:"received_by" json:"received_by"`
+}
+
+type StockTake struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	StockTakeNumber    string         `db:"stock_take_number" json:"stock_take_number"`
+	Status             string         `db:"status" json:"status"`
+	CategoryID         uuid.NullUUID  `db:"category_id" json:"category_id"`
+	IncludeMenuItems   bool           `db:"include_menu_items" json:"include_menu_items"`
+	IncludeIngredients bool           `db:"include_ingredients" json:"include_ingredients"`
+	Notes              sql.NullString `db:"notes" json:"notes"`
+	CreatedBy          uuid.UUID      `db:"created_by" json:"created_by"`
+	ApprovedBy         uuid.NullUUID  `db:"approved_by" json:"approved_by"`
+	ApprovedAt         sql.NullTime   `db:"approved_at" json:"approved_at"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type StockTakeLine struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	StockTakeID     uuid.UUID      `db:"stock_take_id" json:"stock_take_id"`
+	MenuItemID      uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID    uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	Unit            string         `db:"unit" json:"unit"`
+	SystemQuantity  string         `db:"system_quantity" json:"system_quantity"`
+	CountedQuantity sql.NullString `db:"counted_quantity" json:"counted_quantity"`
+	UnitCost        string         `db:"unit_cost" json:"unit_cost"`
+	CountedBy       uuid.NullUUID  `db:"counted_by" json:"counted_by"`
+	CountedAt       sql.NullTime   `db:"counted_at" json:"counted_at"`
+}
+
 type StockTransaction struct {
 	ID              uuid.UUID      `db:"id" json:"id"`
-	MenuItemID      uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	MenuItemID      uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
 	TransactionType string         `db:"transaction_type" json:"transaction_type"`
-	Quantity        int32          `db:"quantity" json:"quantity"`
-	PreviousStock   int32          `db:"previous_stock" json:"previous_stock"`
-	CurrentStock    int32          `db:"current_stock" json:"current_stock"`
+	Quantity        string         `db:"quantity" json:"quantity"`
+	PreviousStock   string         `db:"previous_stock" json:"previous_stock"`
+	CurrentStock    string         `db:"current_stock" json:"current_stock"`
 	Reason          string         `db:"reason" json:"reason"`
 	ReferenceType   sql.NullString `db:"reference_type" json:"reference_type"`
 	ReferenceID     uuid.NullUUID  `db:"reference_id" json:"reference_id"`
 	UserID          uuid.NullUUID  `db:"user_id" json:"user_id"`
 	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	IngredientID    uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	Unit            sql.NullString `db:"unit" json:"unit"`
+	UnitCost        string         `db:"unit_cost" json:"unit_cost"`
+	TotalCost       string         `db:"total_cost" json:"total_cost"`
+	AverageCost     string         `db:"average_cost" json:"average_cost"`
+	LocationID      uuid.NullUUID  `db:"location_id" json:"location_id"`
+}
+
+type StockTransfer struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	TransferNumber string         `db:"transfer_number" json:"transfer_number"`
+	FromLocationID uuid.UUID      `db:"from_location_id" json:"from_location_id"`
+	ToLocationID   uuid.UUID      `db:"to_location_id" json:"to_location_id"`
+	Notes          sql.NullString `db:"notes" json:"notes"`
+	CreatedBy      uuid.UUID      `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+}
+
+type StockTransferLine struct {
+	ID              uuid.UUID     `db:"id" json:"id"`
+	StockTransferID uuid.UUID     `db:"stock_transfer_id" json:"stock_transfer_id"`
+	MenuItemID      uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID    uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Unit            string        `db:"unit" json:"unit"`
+	Quantity        string        `db:"quantity" json:"quantity"`
+}
+
+type Supplier struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Name        string         `db:"name" json:"name"`
+	ContactName sql.NullString `db:"contact_name" json:"contact_name"`
+	Phone       sql.NullString `db:"phone" json:"phone"`
+	Email       sql.NullString `db:"email" json:"email"`
+	Address     sql.NullString `db:"address" json:"address"`
+	IsActive    bool           `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 type TopSellingItem struct {
@@ -173,6 +591,14 @@ type TopSellingItem struct {
 	TimesOrdered      int64          `db:"times_ordered" json:"times_ordered"`
 }
 
+type UnitsOfMeasure struct {
+	Code      string    `db:"code" json:"code"`
+	Name      string    `db:"name" json:"name"`
+	Dimension string    `db:"dimension" json:"dimension"`
+	Factor    string    `db:"factor" json:"factor"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 type User struct {
 	ID           uuid.UUID `db:"id" json:"id"`
 	Username     string    `db:"username" json:"username"`
@@ -194,3 +620,57 @@ type UserSession struct {
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 	LastAccessedAt time.Time `db:"last_accessed_at" json:"last_accessed_at"`
 }
+
+type WasteRecord struct {
+	ID           uuid.UUID      `db:"id" json:"id"`
+	MenuItemID   uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	Reason       string         `db:"reason" json:"reason"`
+	Quantity     string         `db:"quantity" json:"quantity"`
+	Unit         string         `db:"unit" json:"unit"`
+	UnitCost     string         `db:"unit_cost" json:"unit_cost"`
+	TotalCost    string         `db:"total_cost" json:"total_cost"`
+	Notes        sql.NullString `db:"notes" json:"notes"`
+	UserID       uuid.UUID      `db:"user_id" json:"user_id"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+}
+
+type ZReport struct {
+	ID                  uuid.UUID      `db:"id" json:"id"`
+	ReportNumber        int32          `db:"report_number" json:"report_number"`
+	PeriodStart         sql.NullTime   `db:"period_start" json:"period_start"`
+	PeriodEnd           time.Time      `db:"period_end" json:"period_end"`
+	GeneratedBy         uuid.UUID      `db:"generated_by" json:"generated_by"`
+	GeneratedAt         time.Time      `db:"generated_at" json:"generated_at"`
+	OrderCount          int32          `db:"order_count" json:"order_count"`
+	GrossSales          string         `db:"gross_sales" json:"gross_sales"`
+	DiscountAmount      string         `db:"discount_amount" json:"discount_amount"`
+	ServiceChargeAmount string         `db:"service_charge_amount" json:"service_charge_amount"`
+	TaxAmount           string         `db:"tax_amount" json:"tax_amount"`
+	RoundingAmount      string         `db:"rounding_amount" json:"rounding_amount"`
+	TotalSales          string         `db:"total_sales" json:"total_sales"`
+	RefundCount         int32          `db:"refund_count" json:"refund_count"`
+	RefundAmount        string         `db:"refund_amount" json:"refund_amount"`
+	RefundTax           string         `db:"refund_tax" json:"refund_tax"`
+	CancelledCount      int32          `db:"cancelled_count" json:"cancelled_count"`
+	VoidedCount         int32          `db:"voided_count" json:"voided_count"`
+	VoidedAmount        string         `db:"voided_amount" json:"voided_amount"`
+	NetSales            string         `db:"net_sales" json:"net_sales"`
+	FirstOrderNumber    sql.NullString `db:"first_order_number" json:"first_order_number"`
+	LastOrderNumber     sql.NullString `db:"last_order_number" json:"last_order_number"`
+}
+
+type ZReportCashier struct {
+	ZReportID   uuid.UUID `db:"z_report_id" json:"z_report_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	CashierName string    `db:"cashier_name" json:"cashier_name"`
+	OrderCount  int32     `db:"order_count" json:"order_count"`
+	TotalSales  string    `db:"total_sales" json:"total_sales"`
+}
+
+type ZReportPaymentMethod struct {
+	ZReportID     uuid.UUID `db:"z_report_id" json:"z_report_id"`
+	PaymentMethod string    `db:"payment_method" json:"payment_method"`
+	OrderCount    int32     `db:"order_count" json:"order_count"`
+	TotalAmount   string    `db:"total_amount" json:"total_amount"`
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,30 +15,44 @@ import (
 
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
-    order_id, menu_item_id, quantity, unit_price, total_price
+    order_id, menu_item_id, quantity, unit_price, total_price, notes
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
-RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 `
 
 type CreateOrderItemParams struct {
-	OrderID    uuid.UUID `db:"order_id" json:"order_id"`
-	MenuItemID uuid.UUID `db:"menu_item_id" json:"menu_item_id"`
-	Quantity   int32     `db:"quantity" json:"quantity"`
-	UnitPrice  string    `db:"unit_price" json:"unit_price"`
-	TotalPrice string    `db:"total_price" json:"total_price"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
 }
 
-func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
+type CreateOrderItemRow struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (CreateOrderItemRow, error) {
 	row := q.db.QueryRowContext(ctx, createOrderItem,
 		arg.OrderID,
 		arg.MenuItemID,
 		arg.Quantity,
 		arg.UnitPrice,
 		arg.TotalPrice,
+		arg.Notes,
 	)
-	var i OrderItem
+	var i CreateOrderItemRow
 	err := row.Scan(
 		&i.ID,
 		&i.OrderID,
@@ -45,12 +60,38 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.Quantity,
 		&i.UnitPrice,
 		&i.TotalPrice,
+		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const createOrderItemStockUsage = `-- name: CreateOrderItemStockUsage :exec
+INSERT INTO order_item_stock_usage (
+    order_item_id, menu_item_id, ingredient_id, quantity
+) VALUES (
+    $1, $2, $3, $4
+)
+`
+
+type CreateOrderItemStockUsageParams struct {
+	OrderItemID  uuid.UUID     `db:"order_item_id" json:"order_item_id"`
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Quantity     string        `db:"quantity" json:"quantity"`
+}
+
+func (q *Queries) CreateOrderItemStockUsage(ctx context.Context, arg CreateOrderItemStockUsageParams) error {
+	_, err := q.db.ExecContext(ctx, createOrderItemStockUsage,
+		arg.OrderItemID,
+		arg.MenuItemID,
+		arg.IngredientID,
+		arg.Quantity,
+	)
+	return err
+}
+
 const deleteOrderItem = `-- name: DeleteOrderItem :exec
 DELETE FROM order_items
 WHERE id = $1
@@ -72,15 +113,27 @@ func (q *Queries) DeleteOrderItemsByOrderID(ctx context.Context, orderID uuid.UU
 }
 
 const getOrderItem = `-- name: GetOrderItem :one
-SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 FROM order_items
 WHERE id = $1
 LIMIT 1
 `
 
-func (q *Queries) GetOrderItem(ctx context.Context, id uuid.UUID) (OrderItem, error) {
+type GetOrderItemRow struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) GetOrderItem(ctx context.Context, id uuid.UUID) (GetOrderItemRow, error) {
 	row := q.db.QueryRowContext(ctx, getOrderItem, id)
-	var i OrderItem
+	var i GetOrderItemRow
 	err := row.Scan(
 		&i.ID,
 		&i.OrderID,
@@ -88,6 +141,7 @@ func (q *Queries) GetOrderItem(ctx context.Context, id uuid.UUID) (OrderItem, er
 		&i.Quantity,
 		&i.UnitPrice,
 		&i.TotalPrice,
+		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -95,21 +149,33 @@ func (q *Queries) GetOrderItem(ctx context.Context, id uuid.UUID) (OrderItem, er
 }
 
 const getOrderItemsByOrderID = `-- name: GetOrderItemsByOrderID :many
-SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+SELECT id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 FROM order_items
 WHERE order_id = $1
 ORDER BY created_at
 `
 
-func (q *Queries) GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error) {
+type GetOrderItemsByOrderIDRow struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]GetOrderItemsByOrderIDRow, error) {
 	rows, err := q.db.QueryContext(ctx, getOrderItemsByOrderID, orderID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []OrderItem
+	var items []GetOrderItemsByOrderIDRow
 	for rows.Next() {
-		var i OrderItem
+		var i GetOrderItemsByOrderIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.OrderID,
@@ -117,6 +183,7 @@ func (q *Queries) GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID)
 			&i.Quantity,
 			&i.UnitPrice,
 			&i.TotalPrice,
+			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -134,7 +201,7 @@ func (q *Queries) GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID)
 }
 
 const getOrderItemsWithDetails = `-- name: GetOrderItemsWithDetails :many
-SELECT oi.id, oi.order_id, oi.menu_item_id, mi.name as menu_item_name, oi.quantity, oi.unit_price, oi.total_price, oi.created_at, oi.updated_at
+SELECT oi.id, oi.order_id, oi.menu_item_id, mi.name as menu_item_name, oi.quantity, oi.unit_price, oi.total_price, oi.notes, oi.created_at, oi.updated_at
 FROM order_items oi
 JOIN menu_items mi ON oi.menu_item_id = mi.id
 WHERE oi.order_id = $1
@@ -142,15 +209,16 @@ ORDER BY oi.created_at
 `
 
 type GetOrderItemsWithDetailsRow struct {
-	ID           uuid.UUID `db:"id" json:"id"`
-	OrderID      uuid.UUID `db:"order_id" json:"order_id"`
-	MenuItemID   uuid.UUID `db:"menu_item_id" json:"menu_item_id"`
-	MenuItemName string    `db:"menu_item_name" json:"menu_item_name"`
-	Quantity     int32     `db:"quantity" json:"quantity"`
-	UnitPrice    string    `db:"unit_price" json:"unit_price"`
-	TotalPrice   string    `db:"total_price" json:"total_price"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID      `db:"id" json:"id"`
+	OrderID      uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID   uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	MenuItemName string         `db:"menu_item_name" json:"menu_item_name"`
+	Quantity     int32          `db:"quantity" json:"quantity"`
+	UnitPrice    string         `db:"unit_price" json:"unit_price"`
+	TotalPrice   string         `db:"total_price" json:"total_price"`
+	Notes        sql.NullString `db:"notes" json:"notes"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) GetOrderItemsWithDetails(ctx context.Context, orderID uuid.UUID) ([]GetOrderItemsWithDetailsRow, error) {
@@ -170,6 +238,7 @@ func (q *Queries) GetOrderItemsWithDetails(ctx context.Context, orderID uuid.UUI
 			&i.Quantity,
 			&i.UnitPrice,
 			&i.TotalPrice,
+			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -186,28 +255,102 @@ func (q *Queries) GetOrderItemsWithDetails(ctx context.Context, orderID uuid.UUI
 	return items, nil
 }
 
+const listOrderItemStockUsage = `-- name: ListOrderItemStockUsage :many
+SELECT u.order_item_id, u.menu_item_id, u.ingredient_id, u.quantity::text AS quantity
+FROM order_item_stock_usage u
+JOIN order_items oi ON u.order_item_id = oi.id
+WHERE oi.order_id = $1
+ORDER BY u.order_item_id, u.menu_item_id, u.ingredient_id
+`
+
+type ListOrderItemStockUsageRow struct {
+	OrderItemID  uuid.UUID     `db:"order_item_id" json:"order_item_id"`
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Quantity     string        `db:"quantity" json:"quantity"`
+}
+
+// The stock each line of an order took when the order was completed
+func (q *Queries) ListOrderItemStockUsage(ctx context.Context, orderID uuid.UUID) ([]ListOrderItemStockUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderItemStockUsage, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrderItemStockUsageRow
+	for rows.Next() {
+		var i ListOrderItemStockUsageRow
+		if err := rows.Scan(
+			&i.OrderItemID,
+			&i.MenuItemID,
+			&i.IngredientID,
+			&i.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveOrderItem = `-- name: MoveOrderItem :exec
+UPDATE order_items
+SET order_id = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type MoveOrderItemParams struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	OrderID uuid.UUID `db:"order_id" json:"order_id"`
+}
+
+func (q *Queries) MoveOrderItem(ctx context.Context, arg MoveOrderItemParams) error {
+	_, err := q.db.ExecContext(ctx, moveOrderItem, arg.ID, arg.OrderID)
+	return err
+}
+
 const updateOrderItem = `-- name: UpdateOrderItem :one
 UPDATE order_items
-SET quantity = $2, unit_price = $3, total_price = $4, updated_at = NOW()
+SET quantity = $2, unit_price = $3, total_price = $4, notes = $5, updated_at = NOW()
 WHERE id = $1
-RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, created_at, updated_at
+RETURNING id, order_id, menu_item_id, quantity, unit_price, total_price, notes, created_at, updated_at
 `
 
 type UpdateOrderItemParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	Quantity   int32     `db:"quantity" json:"quantity"`
-	UnitPrice  string    `db:"unit_price" json:"unit_price"`
-	TotalPrice string    `db:"total_price" json:"total_price"`
+	ID         uuid.UUID      `db:"id" json:"id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+}
+
+type UpdateOrderItemRow struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	OrderID    uuid.UUID      `db:"order_id" json:"order_id"`
+	MenuItemID uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	Quantity   int32          `db:"quantity" json:"quantity"`
+	UnitPrice  string         `db:"unit_price" json:"unit_price"`
+	TotalPrice string         `db:"total_price" json:"total_price"`
+	Notes      sql.NullString `db:"notes" json:"notes"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-func (q *Queries) UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams) (OrderItem, error) {
+func (q *Queries) UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams) (UpdateOrderItemRow, error) {
 	row := q.db.QueryRowContext(ctx, updateOrderItem,
 		arg.ID,
 		arg.Quantity,
 		arg.UnitPrice,
 		arg.TotalPrice,
+		arg.Notes,
 	)
-	var i OrderItem
+	var i UpdateOrderItemRow
 	err := row.Scan(
 		&i.ID,
 		&i.OrderID,
@@ -215,6 +358,7 @@ func (q *Queries) UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams
 		&i.Quantity,
 		&i.UnitPrice,
 		&i.TotalPrice,
+		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
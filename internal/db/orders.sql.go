@@ -8,26 +8,61 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const cancelOrder = `-- name: CancelOrder :exec
+UPDATE orders
+SET status = 'cancelled', payment_status = $2, cancellation_reason = $3,
+    cancelled_by = $4, cancelled_at = clock_timestamp(), void_shift_id = $5, updated_at = NOW()
+WHERE id = $1
+`
+
+type CancelOrderParams struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	PaymentStatus      string         `db:"payment_status" json:"payment_status"`
+	CancellationReason sql.NullString `db:"cancellation_reason" json:"cancellation_reason"`
+	CancelledBy        uuid.NullUUID  `db:"cancelled_by" json:"cancelled_by"`
+	VoidShiftID        uuid.NullUUID  `db:"void_shift_id" json:"void_shift_id"`
+}
+
+func (q *Queries) CancelOrder(ctx context.Context, arg CancelOrderParams) error {
+	_, err := q.db.ExecContext(ctx, cancelOrder,
+		arg.ID,
+		arg.PaymentStatus,
+		arg.CancellationReason,
+		arg.CancelledBy,
+		arg.VoidShiftID,
+	)
+	return err
+}
+
 const createOrder = `-- name: CreateOrder :one
 INSERT INTO orders (
-    order_number, user_id, total_amount, discount_amount, tax_amount
+    order_number, user_id, total_amount, discount_amount, tax_amount, subtotal_amount,
+    order_type, table_id, guest_count
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-          payment_method, payment_status, completed_at, created_at, updated_at
+          payment_method, payment_status, completed_at, created_at, updated_at,
+          cancellation_reason, cancelled_by, cancelled_at,
+          subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+          order_type, table_id, guest_count, shift_id, void_shift_id
 `
 
 type CreateOrderParams struct {
-	OrderNumber    string    `db:"order_number" json:"order_number"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	TotalAmount    string    `db:"total_amount" json:"total_amount"`
-	DiscountAmount string    `db:"discount_amount" json:"discount_amount"`
-	TaxAmount      string    `db:"tax_amount" json:"tax_amount"`
+	OrderNumber    string        `db:"order_number" json:"order_number"`
+	UserID         uuid.UUID     `db:"user_id" json:"user_id"`
+	TotalAmount    string        `db:"total_amount" json:"total_amount"`
+	DiscountAmount string        `db:"discount_amount" json:"discount_amount"`
+	TaxAmount      string        `db:"tax_amount" json:"tax_amount"`
+	SubtotalAmount string        `db:"subtotal_amount" json:"subtotal_amount"`
+	OrderType      string        `db:"order_type" json:"order_type"`
+	TableID        uuid.NullUUID `db:"table_id" json:"table_id"`
+	GuestCount     sql.NullInt32 `db:"guest_count" json:"guest_count"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -37,6 +72,10 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.TotalAmount,
 		arg.DiscountAmount,
 		arg.TaxAmount,
+		arg.SubtotalAmount,
+		arg.OrderType,
+		arg.TableID,
+		arg.GuestCount,
 	)
 	var i Order
 	err := row.Scan(
@@ -52,13 +91,29 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.CompletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.SubtotalAmount,
+		&i.ServiceChargeAmount,
+		&i.RoundingAmount,
+		&i.ServiceChargeRate,
+		&i.TaxRate,
+		&i.OrderType,
+		&i.TableID,
+		&i.GuestCount,
+		&i.ShiftID,
+		&i.VoidShiftID,
 	)
 	return i, err
 }
 
 const getOrder = `-- name: GetOrder :one
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE id = $1
 LIMIT 1
@@ -80,13 +135,29 @@ func (q *Queries) GetOrder(ctx context.Context, id uuid.UUID) (Order, error) {
 		&i.CompletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.SubtotalAmount,
+		&i.ServiceChargeAmount,
+		&i.RoundingAmount,
+		&i.ServiceChargeRate,
+		&i.TaxRate,
+		&i.OrderType,
+		&i.TableID,
+		&i.GuestCount,
+		&i.ShiftID,
+		&i.VoidShiftID,
 	)
 	return i, err
 }
 
 const getOrderByNumber = `-- name: GetOrderByNumber :one
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE order_number = $1
 LIMIT 1
@@ -108,13 +179,74 @@ func (q *Queries) GetOrderByNumber(ctx context.Context, orderNumber string) (Ord
 		&i.CompletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.SubtotalAmount,
+		&i.ServiceChargeAmount,
+		&i.RoundingAmount,
+		&i.ServiceChargeRate,
+		&i.TaxRate,
+		&i.OrderType,
+		&i.TableID,
+		&i.GuestCount,
+		&i.ShiftID,
+		&i.VoidShiftID,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
+FROM orders
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.UserID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.DiscountAmount,
+		&i.TaxAmount,
+		&i.PaymentMethod,
+		&i.PaymentStatus,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.CancelledAt,
+		&i.SubtotalAmount,
+		&i.ServiceChargeAmount,
+		&i.RoundingAmount,
+		&i.ServiceChargeRate,
+		&i.TaxRate,
+		&i.OrderType,
+		&i.TableID,
+		&i.GuestCount,
+		&i.ShiftID,
+		&i.VoidShiftID,
 	)
 	return i, err
 }
 
 const listOrders = `-- name: ListOrders :many
 SELECT id, order_number, user_id, status, total_amount, discount_amount, tax_amount, 
-       payment_method, payment_status, completed_at, created_at, updated_at
+       payment_method, payment_status, completed_at, created_at, updated_at,
+       cancellation_reason, cancelled_by, cancelled_at,
+       subtotal_amount, service_charge_amount, rounding_amount, service_charge_rate, tax_rate,
+       order_type, table_id, guest_count, shift_id, void_shift_id
 FROM orders
 WHERE ($1 = '' OR status = $1) 
   AND ($2 = '00000000-0000-0000-0000-000000000000'::uuid OR user_id = $2)
@@ -162,6 +294,19 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 			&i.CompletedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.CancelledAt,
+			&i.SubtotalAmount,
+			&i.ServiceChargeAmount,
+			&i.RoundingAmount,
+			&i.ServiceChargeRate,
+			&i.TaxRate,
+			&i.OrderType,
+			&i.TableID,
+			&i.GuestCount,
+			&i.ShiftID,
+			&i.VoidShiftID,
 		); err != nil {
 			return nil, err
 		}
@@ -176,9 +321,50 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 	return items, nil
 }
 
+const mergeOrder = `-- name: MergeOrder :exec
+UPDATE orders
+SET status = 'merged', cancellation_reason = $2, cancelled_by = $3, cancelled_at = NOW(), updated_at = NOW()
+WHERE id = $1
+`
+
+type MergeOrderParams struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	CancellationReason sql.NullString `db:"cancellation_reason" json:"cancellation_reason"`
+	CancelledBy        uuid.NullUUID  `db:"cancelled_by" json:"cancelled_by"`
+}
+
+// Closes an order whose lines were all moved into another order
+func (q *Queries) MergeOrder(ctx context.Context, arg MergeOrderParams) error {
+	_, err := q.db.ExecContext(ctx, mergeOrder, arg.ID, arg.CancellationReason, arg.CancelledBy)
+	return err
+}
+
+const nextOrderNumber = `-- name: NextOrderNumber :one
+INSERT INTO order_number_sequences (prefix, business_date, last_number)
+VALUES ($1, $2, 1)
+ON CONFLICT (prefix, business_date)
+DO UPDATE SET last_number = order_number_sequences.last_number + 1, updated_at = NOW()
+RETURNING last_number
+`
+
+type NextOrderNumberParams struct {
+	Prefix       string    `db:"prefix" json:"prefix"`
+	BusinessDate time.Time `db:"business_date" json:"business_date"`
+}
+
+// Claims the next number for the prefix and business day. The upsert keeps the
+// counter row locked until the caller's transaction ends, so a rolled back order
+// releases its number instead of leaving a gap.
+func (q *Queries) NextOrderNumber(ctx context.Context, arg NextOrderNumberParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, nextOrderNumber, arg.Prefix, arg.BusinessDate)
+	var last_number int32
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
 const updateOrderPayment = `-- name: UpdateOrderPayment :exec
 UPDATE orders
-SET payment_method = $2, payment_status = $3, completed_at = NOW(), updated_at = NOW()
+SET payment_method = $2, payment_status = $3, completed_at = clock_timestamp(), updated_at = NOW()
 WHERE id = $1
 `
 
@@ -188,11 +374,45 @@ type UpdateOrderPaymentParams struct {
 	PaymentStatus string         `db:"payment_status" json:"payment_status"`
 }
 
+// completed_at is taken when the order completes rather than when its
+// transaction started, so it falls after any Z report the order waited for
 func (q *Queries) UpdateOrderPayment(ctx context.Context, arg UpdateOrderPaymentParams) error {
 	_, err := q.db.ExecContext(ctx, updateOrderPayment, arg.ID, arg.PaymentMethod, arg.PaymentStatus)
 	return err
 }
 
+const updateOrderPaymentStatus = `-- name: UpdateOrderPaymentStatus :exec
+UPDATE orders
+SET payment_status = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateOrderPaymentStatusParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	PaymentStatus string    `db:"payment_status" json:"payment_status"`
+}
+
+func (q *Queries) UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderPaymentStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateOrderPaymentStatus, arg.ID, arg.PaymentStatus)
+	return err
+}
+
+const updateOrderShift = `-- name: UpdateOrderShift :exec
+UPDATE orders
+SET shift_id = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateOrderShiftParams struct {
+	ID      uuid.UUID     `db:"id" json:"id"`
+	ShiftID uuid.NullUUID `db:"shift_id" json:"shift_id"`
+}
+
+func (q *Queries) UpdateOrderShift(ctx context.Context, arg UpdateOrderShiftParams) error {
+	_, err := q.db.ExecContext(ctx, updateOrderShift, arg.ID, arg.ShiftID)
+	return err
+}
+
 const updateOrderStatus = `-- name: UpdateOrderStatus :exec
 UPDATE orders
 SET status = $2, updated_at = NOW()
@@ -209,17 +429,47 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 	return err
 }
 
+const updateOrderTable = `-- name: UpdateOrderTable :exec
+UPDATE orders
+SET order_type = $2, table_id = $3, guest_count = $4, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateOrderTableParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	OrderType  string        `db:"order_type" json:"order_type"`
+	TableID    uuid.NullUUID `db:"table_id" json:"table_id"`
+	GuestCount sql.NullInt32 `db:"guest_count" json:"guest_count"`
+}
+
+func (q *Queries) UpdateOrderTable(ctx context.Context, arg UpdateOrderTableParams) error {
+	_, err := q.db.ExecContext(ctx, updateOrderTable,
+		arg.ID,
+		arg.OrderType,
+		arg.TableID,
+		arg.GuestCount,
+	)
+	return err
+}
+
 const updateOrderTotal = `-- name: UpdateOrderTotal :exec
 UPDATE orders
-SET total_amount = $2, discount_amount = $3, tax_amount = $4, updated_at = NOW()
+SET total_amount = $2, discount_amount = $3, tax_amount = $4,
+    subtotal_amount = $5, service_charge_amount = $6, rounding_amount = $7,
+    service_charge_rate = $8, tax_rate = $9, updated_at = NOW()
 WHERE id = $1
 `
 
 type UpdateOrderTotalParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	TotalAmount    string    `db:"total_amount" json:"total_amount"`
-	DiscountAmount string    `db:"discount_amount" json:"discount_amount"`
-	TaxAmount      string    `db:"tax_amount" json:"tax_amount"`
+	ID                  uuid.UUID `db:"id" json:"id"`
+	TotalAmount         string    `db:"total_amount" json:"total_amount"`
+	DiscountAmount      string    `db:"discount_amount" json:"discount_amount"`
+	TaxAmount           string    `db:"tax_amount" json:"tax_amount"`
+	SubtotalAmount      string    `db:"subtotal_amount" json:"subtotal_amount"`
+	ServiceChargeAmount string    `db:"service_charge_amount" json:"service_charge_amount"`
+	RoundingAmount      string    `db:"rounding_amount" json:"rounding_amount"`
+	ServiceChargeRate   string    `db:"service_charge_rate" json:"service_charge_rate"`
+	TaxRate             string    `db:"tax_rate" json:"tax_rate"`
 }
 
 func (q *Queries) UpdateOrderTotal(ctx context.Context, arg UpdateOrderTotalParams) error {
@@ -228,6 +478,11 @@ func (q *Queries) UpdateOrderTotal(ctx context.Context, arg UpdateOrderTotalPara
 		arg.TotalAmount,
 		arg.DiscountAmount,
 		arg.TaxAmount,
+		arg.SubtotalAmount,
+		arg.ServiceChargeAmount,
+		arg.RoundingAmount,
+		arg.ServiceChargeRate,
+		arg.TaxRate,
 	)
 	return err
 }
@@ -6,57 +6,366 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	// Adds to the counted quantity of a line, as each scan of an item does
+	AddStockTakeLineCount(ctx context.Context, arg AddStockTakeLineCountParams) error
+	// Applies a relative stock change atomically; returns no row when the
+	// change would take the stock below zero
+	AdjustIngredientStock(ctx context.Context, arg AdjustIngredientStockParams) (Ingredient, error)
+	// Applies a relative stock change atomically; returns no row when the
+	// change would take the stock below zero
+	AdjustInventoryStock(ctx context.Context, arg AdjustInventoryStockParams) (AdjustInventoryStockRow, error)
+	// Applies a relative change to the stock of an item at a location; returns no
+	// row when the location holds no stock of the item or the change would take
+	// it below zero
+	AdjustStockBalance(ctx context.Context, arg AdjustStockBalanceParams) (string, error)
+	// A category is routed to one station, so assigning it moves it from any other
+	AssignCategoryToStation(ctx context.Context, arg AssignCategoryToStationParams) error
+	AttachModifierGroup(ctx context.Context, arg AttachModifierGroupParams) error
+	// Moves a line on from the status it was read in, stamping the time it reached
+	// the new one. No row is returned if someone else bumped it first.
+	BumpKitchenTicket(ctx context.Context, arg BumpKitchenTicketParams) (uuid.UUID, error)
+	CancelOrder(ctx context.Context, arg CancelOrderParams) error
+	// Claims the alerts due to be delivered through the notification channels.
+	// Claiming pushes the next attempt back, 1, 2, 4, 8... minutes, so an alert
+	// is not claimed again while it is being delivered, nor retried straight away
+	// when delivery fails. Alerts claimed by another server are skipped, and so
+	// are alerts for items restocked before they were delivered.
+	ClaimLowStockAlerts(ctx context.Context, arg ClaimLowStockAlertsParams) ([]LowStockAlert, error)
+	// Uses up one redemption; returns no row once the usage limit has been reached
+	ClaimPromotionUsage(ctx context.Context, id uuid.UUID) (int32, error)
+	// Stops selling from the current selling location, before another takes over
+	ClearSellingStockLocation(ctx context.Context, id uuid.UUID) error
+	ClearStationCategories(ctx context.Context, stationID uuid.UUID) error
+	CloseCashShift(ctx context.Context, arg CloseCashShiftParams) (CashShift, error)
+	// Takes a quantity out of an ingredient's lots, in the same order as
+	// ConsumeMenuItemStockLots
+	ConsumeIngredientStockLots(ctx context.Context, arg ConsumeIngredientStockLotsParams) error
+	// Takes a quantity out of a menu item's lots, first-expiring first and then
+	// first-received first. Each lot gives up what is left of the quantity after
+	// the lots ahead of it, up to what it holds. Expired lots are left to be
+	// written off, never sold from; callers keep stock taken out to what
+	// GetExpiredStock leaves. Stock beyond what the lots hold was never put into
+	// a lot and is taken without touching any.
+	ConsumeMenuItemStockLots(ctx context.Context, arg ConsumeMenuItemStockLotsParams) error
+	// Gives a line split off another the same place in the kitchen as the original
+	CopyKitchenTicket(ctx context.Context, arg CopyKitchenTicketParams) error
+	// Gives a line split off another the same options the original was ordered with
+	CopyOrderItemModifiers(ctx context.Context, arg CopyOrderItemModifiersParams) error
+	CountDiningTablesByFloorAreaID(ctx context.Context, floorAreaID uuid.UUID) (int64, error)
+	CountOpenOrdersByTableID(ctx context.Context, tableID uuid.NullUUID) (int64, error)
+	CountRecipeItemsByIngredient(ctx context.Context, ingredientID uuid.UUID) (int64, error)
+	CountUnreadLowStockAlerts(ctx context.Context) (int64, error)
+	CreateCashMovement(ctx context.Context, arg CreateCashMovementParams) (CashMovement, error)
+	CreateCashShift(ctx context.Context, arg CreateCashShiftParams) (CashShift, error)
+	CreateCashShiftCount(ctx context.Context, arg CreateCashShiftCountParams) error
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateDiningTable(ctx context.Context, arg CreateDiningTableParams) (DiningTable, error)
 	CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error)
+	CreateFloorArea(ctx context.Context, arg CreateFloorAreaParams) (FloorArea, error)
+	CreateGoodsReceivedNote(ctx context.Context, arg CreateGoodsReceivedNoteParams) (GoodsReceivedNote, error)
+	CreateGoodsReceivedNoteItem(ctx context.Context, arg CreateGoodsReceivedNoteItemParams) error
+	CreateIngredient(ctx context.Context, arg CreateIngredientParams) (Ingredient, error)
 	CreateInventoryRecord(ctx context.Context, menuItemID uuid.UUID) error
+	CreateKitchenStation(ctx context.Context, arg CreateKitchenStationParams) (KitchenStation, error)
+	// Sends the order's lines that are not in the kitchen yet to the active station
+	// their category is routed to
+	CreateKitchenTicketsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error)
+	CreateLowStockAlertDelivery(ctx context.Context, arg CreateLowStockAlertDeliveryParams) error
 	CreateMenuItem(ctx context.Context, arg CreateMenuItemParams) (MenuItem, error)
+	CreateModifierGroup(ctx context.Context, arg CreateModifierGroupParams) (ModifierGroup, error)
+	CreateModifierOption(ctx context.Context, arg CreateModifierOptionParams) (ModifierOption, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
-	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (CreateOrderItemRow, error)
+	CreateOrderItemModifier(ctx context.Context, arg CreateOrderItemModifierParams) (OrderItemModifier, error)
+	CreateOrderItemStockUsage(ctx context.Context, arg CreateOrderItemStockUsageParams) error
+	CreateOrderPayment(ctx context.Context, arg CreateOrderPaymentParams) (OrderPayment, error)
+	CreateOrderPromotion(ctx context.Context, arg CreateOrderPromotionParams) (OrderPromotion, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (Promotion, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) error
+	// Copies are numbered per order; the caller holds the order's row lock
+	CreateReceiptPrint(ctx context.Context, arg CreateReceiptPrintParams) (ReceiptPrint, error)
+	CreateRecipeItem(ctx context.Context, arg CreateRecipeItemParams) error
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	CreateRefundItem(ctx context.Context, arg CreateRefundItemParams) (RefundItem, error)
+	// Records the first stock of an item put into a location
+	CreateStockBalance(ctx context.Context, arg CreateStockBalanceParams) (string, error)
+	CreateStockLocation(ctx context.Context, arg CreateStockLocationParams) (StockLocation, error)
+	CreateStockLot(ctx context.Context, arg CreateStockLotParams) (StockLot, error)
+	CreateStockTake(ctx context.Context, arg CreateStockTakeParams) (StockTake, error)
 	CreateStockTransaction(ctx context.Context, arg CreateStockTransactionParams) (StockTransaction, error)
+	CreateStockTransfer(ctx context.Context, arg CreateStockTransferParams) (StockTransfer, error)
+	CreateStockTransferLine(ctx context.Context, arg CreateStockTransferLineParams) error
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	CreateUnitOfMeasure(ctx context.Context, arg CreateUnitOfMeasureParams) (UnitsOfMeasure, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error)
+	CreateWasteRecord(ctx context.Context, arg CreateWasteRecordParams) (WasteRecord, error)
+	CreateZReport(ctx context.Context, arg CreateZReportParams) (ZReport, error)
+	CreateZReportCashier(ctx context.Context, arg CreateZReportCashierParams) error
+	CreateZReportPaymentMethod(ctx context.Context, arg CreateZReportPaymentMethodParams) error
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	DeleteDiningTable(ctx context.Context, id uuid.UUID) error
 	DeleteExpense(ctx context.Context, id uuid.UUID) error
+	DeleteFloorArea(ctx context.Context, id uuid.UUID) error
+	DeleteKitchenStation(ctx context.Context, id uuid.UUID) error
 	DeleteMenuItem(ctx context.Context, id uuid.UUID) error
+	DeleteMenuItemRecipeItems(ctx context.Context, menuItemID uuid.NullUUID) error
+	DeleteModifierGroup(ctx context.Context, id uuid.UUID) error
+	DeleteModifierOption(ctx context.Context, id uuid.UUID) error
+	DeleteModifierOptionRecipeItems(ctx context.Context, modifierOptionID uuid.NullUUID) error
+	DeleteOpenKitchenTicketsByOrderID(ctx context.Context, orderID uuid.UUID) error
 	DeleteOrderItem(ctx context.Context, id uuid.UUID) error
 	DeleteOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) error
+	DeleteOrderPromotionsByOrderID(ctx context.Context, orderID uuid.UUID) error
+	DeletePromotion(ctx context.Context, id uuid.UUID) error
+	DeletePurchaseOrderItems(ctx context.Context, purchaseOrderID uuid.UUID) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DetachModifierGroup(ctx context.Context, arg DetachModifierGroupParams) error
+	EmptyStockLot(ctx context.Context, id uuid.UUID) error
+	// Orders cancelled in the period; voided orders had been paid for and their
+	// payment was given back. Voided orders completed before the period were
+	// counted in an earlier period's sales, so their net amount is taken off this one.
+	GetCancellationTotalsForPeriod(ctx context.Context, arg GetCancellationTotalsForPeriodParams) (GetCancellationTotalsForPeriodRow, error)
+	GetCashShift(ctx context.Context, id uuid.UUID) (CashShift, error)
+	GetCashShiftForUpdate(ctx context.Context, id uuid.UUID) (CashShift, error)
+	GetCashierTotalsForPeriod(ctx context.Context, arg GetCashierTotalsForPeriodParams) ([]GetCashierTotalsForPeriodRow, error)
 	GetCategory(ctx context.Context, id uuid.UUID) (Category, error)
+	// The cost of the stock that went out for orders, less what was put back by
+	// cancellations and refunds, per menu item and ingredient, most costly first
+	GetCostOfGoodsSoldByItemByDateRange(ctx context.Context, arg GetCostOfGoodsSoldByItemByDateRangeParams) ([]GetCostOfGoodsSoldByItemByDateRangeRow, error)
 	GetDailySalesReportData(ctx context.Context, dollar_1 time.Time) (GetDailySalesReportDataRow, error)
+	// The clock sales, voids and refunds are stamped with, so a period ends on it
+	GetDatabaseTime(ctx context.Context) (time.Time, error)
+	GetDiningTable(ctx context.Context, id uuid.UUID) (DiningTable, error)
 	GetExpense(ctx context.Context, id uuid.UUID) (Expense, error)
+	// What is left in a menu item's or an ingredient's lots that have expired,
+	// which is still in its stock until written off but cannot be used
+	GetExpiredStock(ctx context.Context, arg GetExpiredStockParams) (string, error)
 	GetFinancialSummaryByDateRange(ctx context.Context, arg GetFinancialSummaryByDateRangeParams) (GetFinancialSummaryByDateRangeRow, error)
-	GetInventoryByMenuItem(ctx context.Context, menuItemID uuid.UUID) (Inventory, error)
+	GetFloorArea(ctx context.Context, id uuid.UUID) (FloorArea, error)
+	GetIngredient(ctx context.Context, id uuid.UUID) (Ingredient, error)
+	GetInventoryByMenuItem(ctx context.Context, menuItemID uuid.UUID) (GetInventoryByMenuItemRow, error)
+	GetKitchenStation(ctx context.Context, id uuid.UUID) (KitchenStation, error)
+	GetKitchenTicket(ctx context.Context, id uuid.UUID) (GetKitchenTicketRow, error)
+	GetLatestZReport(ctx context.Context) (ZReport, error)
 	GetMenuItem(ctx context.Context, id uuid.UUID) (MenuItem, error)
+	GetModifierGroup(ctx context.Context, id uuid.UUID) (ModifierGroup, error)
+	GetModifierOption(ctx context.Context, id uuid.UUID) (ModifierOption, error)
+	GetOpenCashShiftByUserID(ctx context.Context, userID uuid.UUID) (CashShift, error)
 	GetOrder(ctx context.Context, id uuid.UUID) (Order, error)
 	GetOrderByNumber(ctx context.Context, orderNumber string) (Order, error)
-	GetOrderItem(ctx context.Context, id uuid.UUID) (OrderItem, error)
-	GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error)
+	GetOrderForUpdate(ctx context.Context, id uuid.UUID) (Order, error)
+	GetOrderItem(ctx context.Context, id uuid.UUID) (GetOrderItemRow, error)
+	GetOrderItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]GetOrderItemsByOrderIDRow, error)
 	GetOrderItemsWithDetails(ctx context.Context, orderID uuid.UUID) ([]GetOrderItemsWithDetailsRow, error)
+	// Net takings per payment method: cash change handed back is not revenue
+	GetPaymentMethodTotalsByDateRange(ctx context.Context, arg GetPaymentMethodTotalsByDateRangeParams) ([]GetPaymentMethodTotalsByDateRangeRow, error)
+	// Net takings per payment method: cash change handed back is not revenue
+	GetPaymentMethodTotalsForPeriod(ctx context.Context, arg GetPaymentMethodTotalsForPeriodParams) ([]GetPaymentMethodTotalsForPeriodRow, error)
+	// Time from a line reaching the kitchen to it being ready (or served, for lines
+	// bumped straight through), per station and menu item
+	GetPrepTimesByDateRange(ctx context.Context, arg GetPrepTimesByDateRangeParams) ([]GetPrepTimesByDateRangeRow, error)
+	GetPromotion(ctx context.Context, id uuid.UUID) (Promotion, error)
+	GetPromotionByVoucherCode(ctx context.Context, voucherCode sql.NullString) (Promotion, error)
+	GetPurchaseOrder(ctx context.Context, id uuid.UUID) (GetPurchaseOrderRow, error)
+	// Locks the purchase order so that concurrent deliveries cannot receive the
+	// same outstanding quantity twice
+	GetPurchaseOrderForUpdate(ctx context.Context, id uuid.UUID) (GetPurchaseOrderForUpdateRow, error)
+	GetRefundTotalsByDateRange(ctx context.Context, arg GetRefundTotalsByDateRangeParams) (GetRefundTotalsByDateRangeRow, error)
+	GetRefundTotalsForPeriod(ctx context.Context, arg GetRefundTotalsForPeriodParams) (GetRefundTotalsForPeriodRow, error)
+	GetRefundedQuantitiesByOrderID(ctx context.Context, orderID uuid.UUID) ([]GetRefundedQuantitiesByOrderIDRow, error)
 	GetSalesByCategoryByDateRange(ctx context.Context, arg GetSalesByCategoryByDateRangeParams) ([]GetSalesByCategoryByDateRangeRow, error)
+	GetSalesByModifierByDateRange(ctx context.Context, arg GetSalesByModifierByDateRangeParams) ([]GetSalesByModifierByDateRangeRow, error)
+	// Orders completed after period_start (when set) up to and including period_end
+	GetSalesTotalsForPeriod(ctx context.Context, arg GetSalesTotalsForPeriodParams) (GetSalesTotalsForPeriodRow, error)
+	// The location sales are made from
+	GetSellingStockLocation(ctx context.Context) (StockLocation, error)
+	// Cash handed back from the drawer for refunds paid in the shift
+	GetShiftCashRefunds(ctx context.Context, shiftID uuid.NullUUID) (string, error)
+	// Cash kept from the orders completed in the shift, counting the cash part of
+	// split bills; change handed back never reached the drawer. An order voided
+	// later still brought its cash in; the void hands it back in its own shift.
+	GetShiftCashSales(ctx context.Context, shiftID uuid.NullUUID) (GetShiftCashSalesRow, error)
+	// Cash handed back from the drawer for completed orders voided in the shift
+	GetShiftCashVoids(ctx context.Context, voidShiftID uuid.NullUUID) (string, error)
+	// The weighted average cost of one stock unit of a menu item or ingredient
+	GetStockAverageCost(ctx context.Context, arg GetStockAverageCostParams) (string, error)
+	GetStockLocation(ctx context.Context, id uuid.UUID) (StockLocation, error)
+	GetStockTake(ctx context.Context, id uuid.UUID) (StockTake, error)
+	// Locks the stock take so that counts cannot change while it is approved
+	GetStockTakeForUpdate(ctx context.Context, id uuid.UUID) (StockTake, error)
+	GetStockTransfer(ctx context.Context, id uuid.UUID) (GetStockTransferRow, error)
+	// The stock of every menu item and ingredient after its last movement up to
+	// the given time, valued at the weighted average cost at that point
+	GetStockValuationAsOf(ctx context.Context, createdAt time.Time) ([]GetStockValuationAsOfRow, error)
+	GetSupplier(ctx context.Context, id uuid.UUID) (Supplier, error)
 	GetTopSellingItemsByDateRange(ctx context.Context, arg GetTopSellingItemsByDateRangeParams) ([]GetTopSellingItemsByDateRangeRow, error)
+	GetUnitOfMeasure(ctx context.Context, code string) (UnitsOfMeasure, error)
 	GetUser(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	// Waste per menu item and ingredient, most costly first
+	GetWasteByItemByDateRange(ctx context.Context, arg GetWasteByItemByDateRangeParams) ([]GetWasteByItemByDateRangeRow, error)
+	GetWasteByReasonByDateRange(ctx context.Context, arg GetWasteByReasonByDateRangeParams) ([]GetWasteByReasonByDateRangeRow, error)
+	// Waste per staff member who recorded it, most costly first
+	GetWasteByUserByDateRange(ctx context.Context, arg GetWasteByUserByDateRangeParams) ([]GetWasteByUserByDateRangeRow, error)
+	GetZReport(ctx context.Context, id uuid.UUID) (ZReport, error)
+	// Held while an order is completed, voided or refunded so a Z report cannot
+	// close the period until the change is committed
+	HoldOffZReports(ctx context.Context) error
+	// Active promotions without a voucher code that are running at the given time
+	// and still have uses left
+	ListAutomaticPromotions(ctx context.Context, dollar_1 time.Time) ([]Promotion, error)
+	ListCashMovementsByShiftID(ctx context.Context, shiftID uuid.UUID) ([]CashMovement, error)
+	ListCashShiftCountsByShiftID(ctx context.Context, shiftID uuid.UUID) ([]CashShiftCount, error)
+	ListCashShifts(ctx context.Context, arg ListCashShiftsParams) ([]CashShift, error)
 	ListCategories(ctx context.Context, arg ListCategoriesParams) ([]Category, error)
+	ListCategoryIDsByStationID(ctx context.Context, stationID uuid.UUID) ([]uuid.UUID, error)
+	ListDiningTables(ctx context.Context, arg ListDiningTablesParams) ([]DiningTable, error)
 	ListExpenses(ctx context.Context, arg ListExpensesParams) ([]Expense, error)
+	ListFloorAreas(ctx context.Context, isActive sql.NullBool) ([]FloorArea, error)
+	// Lists the lines of every delivery against a purchase order
+	ListGoodsReceivedNoteItems(ctx context.Context, purchaseOrderID uuid.UUID) ([]GoodsReceivedNoteItem, error)
+	ListGoodsReceivedNotes(ctx context.Context, purchaseOrderID uuid.UUID) ([]GoodsReceivedNote, error)
+	ListIngredients(ctx context.Context, arg ListIngredientsParams) ([]Ingredient, error)
 	ListInventory(ctx context.Context, arg ListInventoryParams) ([]ListInventoryRow, error)
+	ListKitchenStations(ctx context.Context, isActive sql.NullBool) ([]KitchenStation, error)
+	// Without a status filter only lines still in the kitchen are listed. Lines with
+	// no station show on every station's display.
+	ListKitchenTickets(ctx context.Context, arg ListKitchenTicketsParams) ([]ListKitchenTicketsRow, error)
+	// The channels an alert has been delivered through
+	ListLowStockAlertDeliveries(ctx context.Context, alertID uuid.UUID) ([]string, error)
+	ListLowStockAlerts(ctx context.Context, arg ListLowStockAlertsParams) ([]LowStockAlert, error)
+	ListMenuItemRecipeItems(ctx context.Context, menuItemID uuid.NullUUID) ([]ListMenuItemRecipeItemsRow, error)
 	ListMenuItems(ctx context.Context, arg ListMenuItemsParams) ([]MenuItem, error)
 	ListMenuItemsByCategory(ctx context.Context, arg ListMenuItemsByCategoryParams) ([]MenuItem, error)
+	ListModifierGroups(ctx context.Context, arg ListModifierGroupsParams) ([]ModifierGroup, error)
+	// The active modifier groups offered with a menu item, in display order
+	ListModifierGroupsByMenuItemID(ctx context.Context, menuItemID uuid.UUID) ([]ModifierGroup, error)
+	ListModifierOptionRecipeItems(ctx context.Context, modifierOptionID uuid.NullUUID) ([]ListModifierOptionRecipeItemsRow, error)
+	ListModifierOptionsByGroupID(ctx context.Context, modifierGroupID uuid.UUID) ([]ModifierOption, error)
+	// Orders still open on a table, i.e. the tabs shown on the floor plan
+	ListOpenTableOrders(ctx context.Context) ([]ListOpenTableOrdersRow, error)
+	ListOrderItemModifiersByOrderID(ctx context.Context, orderID uuid.UUID) ([]OrderItemModifier, error)
+	// The stock each line of an order took when the order was completed
+	ListOrderItemStockUsage(ctx context.Context, orderID uuid.UUID) ([]ListOrderItemStockUsageRow, error)
+	ListOrderPayments(ctx context.Context, orderID uuid.UUID) ([]OrderPayment, error)
+	ListOrderPromotions(ctx context.Context, orderID uuid.UUID) ([]OrderPromotion, error)
 	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
+	ListPromotions(ctx context.Context, arg ListPromotionsParams) ([]Promotion, error)
+	ListPurchaseOrderItems(ctx context.Context, purchaseOrderID uuid.UUID) ([]ListPurchaseOrderItemsRow, error)
+	ListPurchaseOrders(ctx context.Context, arg ListPurchaseOrdersParams) ([]ListPurchaseOrdersRow, error)
+	ListReceiptPrintsByOrderID(ctx context.Context, orderID uuid.UUID) ([]ReceiptPrint, error)
+	ListRefundItemsByRefundID(ctx context.Context, refundID uuid.UUID) ([]RefundItem, error)
+	ListRefundsByOrderID(ctx context.Context, orderID uuid.UUID) ([]Refund, error)
+	// The stock held at a location, by menu item and ingredient
+	ListStockBalances(ctx context.Context, locationID uuid.UUID) ([]ListStockBalancesRow, error)
+	ListStockLocations(ctx context.Context, includeInactive bool) ([]StockLocation, error)
+	// Lots with stock left, in the order they are consumed
+	ListStockLots(ctx context.Context, arg ListStockLotsParams) ([]ListStockLotsRow, error)
+	// Lots with stock left that expire at or before the given time, soonest first
+	ListStockLotsExpiringBefore(ctx context.Context, expiresAt sql.NullTime) ([]ListStockLotsExpiringBeforeRow, error)
+	// The net quantity of each menu item and ingredient moved by the stock
+	// transactions of one reference, such as an order
+	ListStockMovedByReference(ctx context.Context, arg ListStockMovedByReferenceParams) ([]ListStockMovedByReferenceRow, error)
+	ListStockTakeLines(ctx context.Context, stockTakeID uuid.UUID) ([]ListStockTakeLinesRow, error)
+	ListStockTakes(ctx context.Context, arg ListStockTakesParams) ([]StockTake, error)
 	ListStockTransactions(ctx context.Context, arg ListStockTransactionsParams) ([]ListStockTransactionsRow, error)
+	ListStockTransferLines(ctx context.Context, stockTransferID uuid.UUID) ([]ListStockTransferLinesRow, error)
+	// Transfers out of or into a location, or between any locations, newest first
+	ListStockTransfers(ctx context.Context, arg ListStockTransfersParams) ([]ListStockTransfersRow, error)
+	ListSuppliers(ctx context.Context, arg ListSuppliersParams) ([]Supplier, error)
+	ListUnitsOfMeasure(ctx context.Context) ([]UnitsOfMeasure, error)
+	ListWasteRecords(ctx context.Context, arg ListWasteRecordsParams) ([]ListWasteRecordsRow, error)
+	ListZReportCashiers(ctx context.Context, zReportID uuid.UUID) ([]ZReportCashier, error)
+	ListZReportPaymentMethods(ctx context.Context, zReportID uuid.UUID) ([]ZReportPaymentMethod, error)
+	ListZReports(ctx context.Context, arg ListZReportsParams) ([]ZReport, error)
+	// Locks an ingredient's stock against other movements and returns it
+	LockIngredientStock(ctx context.Context, id uuid.UUID) (string, error)
+	// Locks a menu item's stock against other movements and returns it
+	LockMenuItemStock(ctx context.Context, menuItemID uuid.UUID) (string, error)
+	// Serializes Z report generation so report numbers and periods never overlap,
+	// and waits for orders being completed, voided or refunded to commit so none
+	// fall between periods
+	LockZReports(ctx context.Context) error
+	MarkLowStockAlertDispatched(ctx context.Context, id uuid.UUID) error
+	// Marks an alert read, keeping who read it first
+	MarkLowStockAlertRead(ctx context.Context, arg MarkLowStockAlertReadParams) (LowStockAlert, error)
+	// Closes an order whose lines were all moved into another order
+	MergeOrder(ctx context.Context, arg MergeOrderParams) error
+	MoveKitchenTicket(ctx context.Context, arg MoveKitchenTicketParams) error
+	MoveOrderItem(ctx context.Context, arg MoveOrderItemParams) error
+	// Claims the next number for the prefix and business day. The upsert keeps the
+	// counter row locked until the caller's transaction ends, so a rolled back order
+	// releases its number instead of leaving a gap.
+	NextOrderNumber(ctx context.Context, arg NextOrderNumberParams) (int32, error)
+	// Opens an alert for a menu item or an ingredient that a stock movement took
+	// from above its minimum stock to it or below. Items without a minimum stock
+	// and items that already have an open alert are left alone. Returns the alert
+	// raised, if any.
+	RaiseLowStockAlert(ctx context.Context, arg RaiseLowStockAlertParams) ([]LowStockAlert, error)
+	// Adds a delivered quantity to a line; returns no row when it would take the
+	// line past the quantity ordered
+	ReceivePurchaseOrderItem(ctx context.Context, arg ReceivePurchaseOrderItemParams) (uuid.UUID, error)
+	// Recalculates the order total from its lines
+	RefreshPurchaseOrderTotal(ctx context.Context, purchaseOrderID uuid.UUID) error
+	ReleasePromotionUsage(ctx context.Context, id uuid.UUID) error
+	// Closes the open alert of a menu item or an ingredient once it is back above
+	// its minimum stock, or no longer has one
+	ResolveLowStockAlerts(ctx context.Context, arg ResolveLowStockAlertsParams) error
+	SetLowStockAlertError(ctx context.Context, arg SetLowStockAlertErrorParams) error
+	SetStockAverageCost(ctx context.Context, arg SetStockAverageCostParams) error
+	// Replaces the counted quantity of a line
+	SetStockTakeLineCount(ctx context.Context, arg SetStockTakeLineCountParams) error
+	// Records the stock and cost of every active ingredient
+	SnapshotStockTakeIngredients(ctx context.Context, stockTakeID uuid.UUID) error
+	// Records the stock and cost of every menu item stocked as finished goods,
+	// optionally only those of one category
+	SnapshotStockTakeMenuItems(ctx context.Context, arg SnapshotStockTakeMenuItemsParams) error
+	// Takes the menu items made from the stock of a menu item or an ingredient off
+	// the menu when the selling location no longer holds enough to make one, and
+	// puts those it took off back when it does. A menu item with a recipe is made
+	// from its ingredients, one without from its own stock. Items taken off the
+	// menu by hand are left alone. Returns the items whose availability changed.
+	SyncMenuItemAvailability(ctx context.Context, arg SyncMenuItemAvailabilityParams) ([]SyncMenuItemAvailabilityRow, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	UpdateDiningTable(ctx context.Context, arg UpdateDiningTableParams) (DiningTable, error)
 	UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error)
+	UpdateFloorArea(ctx context.Context, arg UpdateFloorAreaParams) (FloorArea, error)
+	UpdateIngredient(ctx context.Context, arg UpdateIngredientParams) (Ingredient, error)
+	// Records the cost of one stock unit from the latest delivery
+	UpdateIngredientCostPrice(ctx context.Context, arg UpdateIngredientCostPriceParams) (Ingredient, error)
 	UpdateInventoryStock(ctx context.Context, arg UpdateInventoryStockParams) error
+	UpdateKitchenStation(ctx context.Context, arg UpdateKitchenStationParams) (KitchenStation, error)
 	UpdateMenuItem(ctx context.Context, arg UpdateMenuItemParams) (MenuItem, error)
-	UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams) (OrderItem, error)
+	// Records the cost of a menu item bought in as finished goods
+	UpdateMenuItemCost(ctx context.Context, arg UpdateMenuItemCostParams) (MenuItem, error)
+	UpdateModifierGroup(ctx context.Context, arg UpdateModifierGroupParams) (ModifierGroup, error)
+	UpdateModifierOption(ctx context.Context, arg UpdateModifierOptionParams) (ModifierOption, error)
+	UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams) (UpdateOrderItemRow, error)
+	// completed_at is taken when the order completes rather than when its
+	// transaction started, so it falls after any Z report the order waited for
 	UpdateOrderPayment(ctx context.Context, arg UpdateOrderPaymentParams) error
+	UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderPaymentStatusParams) error
+	UpdateOrderShift(ctx context.Context, arg UpdateOrderShiftParams) error
 	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) error
+	UpdateOrderTable(ctx context.Context, arg UpdateOrderTableParams) error
 	UpdateOrderTotal(ctx context.Context, arg UpdateOrderTotalParams) error
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (Promotion, error)
+	UpdatePurchaseOrder(ctx context.Context, arg UpdatePurchaseOrderParams) error
+	// Moves a purchase order along, stamping when it was sent and fully received
+	UpdatePurchaseOrderStatus(ctx context.Context, arg UpdatePurchaseOrderStatusParams) error
+	UpdateStockLocation(ctx context.Context, arg UpdateStockLocationParams) (StockLocation, error)
+	// Closes a stock take, recording who approved it when it is approved
+	UpdateStockTakeStatus(ctx context.Context, arg UpdateStockTakeStatusParams) error
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) error
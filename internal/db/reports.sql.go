@@ -35,7 +35,10 @@ SELECT
     COUNT(o.id) AS total_orders,
     COALESCE(SUM(o.total_amount), '0')::TEXT AS total_sales,
     COALESCE(SUM(o.discount_amount), '0')::TEXT AS total_discount,
-    COALESCE(SUM(o.tax_amount), '0')::TEXT AS total_tax
+    COALESCE(SUM(o.tax_amount), '0')::TEXT AS total_tax,
+    COALESCE(SUM(o.subtotal_amount), '0')::TEXT AS total_subtotal,
+    COALESCE(SUM(o.service_charge_amount), '0')::TEXT AS total_service_charge,
+    COALESCE(SUM(o.rounding_amount), '0')::TEXT AS total_rounding
 FROM orders o
 WHERE o.status = 'completed'
 AND o.completed_at IS NOT NULL
@@ -49,10 +52,13 @@ type GetFinancialSummaryByDateRangeParams struct {
 }
 
 type GetFinancialSummaryByDateRangeRow struct {
-	TotalOrders   int64  `db:"total_orders" json:"total_orders"`
-	TotalSales    string `db:"total_sales" json:"total_sales"`
-	TotalDiscount string `db:"total_discount" json:"total_discount"`
-	TotalTax      string `db:"total_tax" json:"total_tax"`
+	TotalOrders        int64  `db:"total_orders" json:"total_orders"`
+	TotalSales         string `db:"total_sales" json:"total_sales"`
+	TotalDiscount      string `db:"total_discount" json:"total_discount"`
+	TotalTax           string `db:"total_tax" json:"total_tax"`
+	TotalSubtotal      string `db:"total_subtotal" json:"total_subtotal"`
+	TotalServiceCharge string `db:"total_service_charge" json:"total_service_charge"`
+	TotalRounding      string `db:"total_rounding" json:"total_rounding"`
 }
 
 func (q *Queries) GetFinancialSummaryByDateRange(ctx context.Context, arg GetFinancialSummaryByDateRangeParams) (GetFinancialSummaryByDateRangeRow, error) {
@@ -63,6 +69,9 @@ func (q *Queries) GetFinancialSummaryByDateRange(ctx context.Context, arg GetFin
 		&i.TotalSales,
 		&i.TotalDiscount,
 		&i.TotalTax,
+		&i.TotalSubtotal,
+		&i.TotalServiceCharge,
+		&i.TotalRounding,
 	)
 	return i, err
 }
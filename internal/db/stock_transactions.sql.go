@@ -16,24 +16,35 @@ import (
 const createStockTransaction = `-- name: CreateStockTransaction :one
 INSERT INTO stock_transactions (
     menu_item_id, transaction_type, quantity, previous_stock, current_stock,
-    reason, reference_type, reference_id, user_id
+    reason, reference_type, reference_id, user_id, ingredient_id, unit,
+    unit_cost, total_cost, average_cost, location_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+    -- Quantities are always in the stock unit of the item or ingredient
+    COALESCE((SELECT unit FROM inventory WHERE menu_item_id = $1),
+             (SELECT unit FROM ingredients WHERE id = $10)),
+    $11, $12, $13, $14
 )
 RETURNING id, menu_item_id, transaction_type, quantity, previous_stock, current_stock,
-          reason, reference_type, reference_id, user_id, created_at
+          reason, reference_type, reference_id, user_id, created_at, ingredient_id, unit,
+          unit_cost, total_cost, average_cost, location_id
 `
 
 type CreateStockTransactionParams struct {
-	MenuItemID      uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	MenuItemID      uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
 	TransactionType string         `db:"transaction_type" json:"transaction_type"`
-	Quantity        int32          `db:"quantity" json:"quantity"`
-	PreviousStock   int32          `db:"previous_stock" json:"previous_stock"`
-	CurrentStock    int32          `db:"current_stock" json:"current_stock"`
+	Quantity        string         `db:"quantity" json:"quantity"`
+	PreviousStock   string         `db:"previous_stock" json:"previous_stock"`
+	CurrentStock    string         `db:"current_stock" json:"current_stock"`
 	Reason          string         `db:"reason" json:"reason"`
 	ReferenceType   sql.NullString `db:"reference_type" json:"reference_type"`
 	ReferenceID     uuid.NullUUID  `db:"reference_id" json:"reference_id"`
 	UserID          uuid.NullUUID  `db:"user_id" json:"user_id"`
+	IngredientID    uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	UnitCost        string         `db:"unit_cost" json:"unit_cost"`
+	TotalCost       string         `db:"total_cost" json:"total_cost"`
+	AverageCost     string         `db:"average_cost" json:"average_cost"`
+	LocationID      uuid.NullUUID  `db:"location_id" json:"location_id"`
 }
 
 func (q *Queries) CreateStockTransaction(ctx context.Context, arg CreateStockTransactionParams) (StockTransaction, error) {
@@ -47,6 +58,11 @@ func (q *Queries) CreateStockTransaction(ctx context.Context, arg CreateStockTra
 		arg.ReferenceType,
 		arg.ReferenceID,
 		arg.UserID,
+		arg.IngredientID,
+		arg.UnitCost,
+		arg.TotalCost,
+		arg.AverageCost,
+		arg.LocationID,
 	)
 	var i StockTransaction
 	err := row.Scan(
@@ -61,20 +77,220 @@ func (q *Queries) CreateStockTransaction(ctx context.Context, arg CreateStockTra
 		&i.ReferenceID,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.IngredientID,
+		&i.Unit,
+		&i.UnitCost,
+		&i.TotalCost,
+		&i.AverageCost,
+		&i.LocationID,
 	)
 	return i, err
 }
 
+const getCostOfGoodsSoldByItemByDateRange = `-- name: GetCostOfGoodsSoldByItemByDateRange :many
+SELECT COALESCE(mi.name, ing.name)::text AS item_name,
+       CASE WHEN st.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+       st.unit,
+       (-SUM(st.quantity))::text AS quantity,
+       (-SUM(st.total_cost))::text AS cost
+FROM stock_transactions st
+LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
+WHERE st.reference_type IN ('order', 'refund')
+  AND st.created_at >= $1 AND st.created_at <= $2
+GROUP BY item_name, item_type, st.unit
+ORDER BY -SUM(st.total_cost) DESC, item_name
+`
+
+type GetCostOfGoodsSoldByItemByDateRangeParams struct {
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
+}
+
+type GetCostOfGoodsSoldByItemByDateRangeRow struct {
+	ItemName string         `db:"item_name" json:"item_name"`
+	ItemType string         `db:"item_type" json:"item_type"`
+	Unit     sql.NullString `db:"unit" json:"unit"`
+	Quantity string         `db:"quantity" json:"quantity"`
+	Cost     string         `db:"cost" json:"cost"`
+}
+
+// The cost of the stock that went out for orders, less what was put back by
+// cancellations and refunds, per menu item and ingredient, most costly first
+func (q *Queries) GetCostOfGoodsSoldByItemByDateRange(ctx context.Context, arg GetCostOfGoodsSoldByItemByDateRangeParams) ([]GetCostOfGoodsSoldByItemByDateRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCostOfGoodsSoldByItemByDateRange, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCostOfGoodsSoldByItemByDateRangeRow
+	for rows.Next() {
+		var i GetCostOfGoodsSoldByItemByDateRangeRow
+		if err := rows.Scan(
+			&i.ItemName,
+			&i.ItemType,
+			&i.Unit,
+			&i.Quantity,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStockAverageCost = `-- name: GetStockAverageCost :one
+SELECT COALESCE(
+    (SELECT average_cost FROM inventory WHERE menu_item_id = $1::uuid),
+    (SELECT average_cost FROM ingredients WHERE id = $2::uuid),
+    0
+)::text AS average_cost
+`
+
+type GetStockAverageCostParams struct {
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+}
+
+// The weighted average cost of one stock unit of a menu item or ingredient
+func (q *Queries) GetStockAverageCost(ctx context.Context, arg GetStockAverageCostParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getStockAverageCost, arg.MenuItemID, arg.IngredientID)
+	var average_cost string
+	err := row.Scan(&average_cost)
+	return average_cost, err
+}
+
+const getStockValuationAsOf = `-- name: GetStockValuationAsOf :many
+SELECT v.menu_item_id, v.ingredient_id, v.item_name, v.item_type, v.unit, v.current_stock, v.average_cost, v.stock_value
+FROM (
+    SELECT DISTINCT ON (st.menu_item_id, st.ingredient_id)
+           st.menu_item_id, st.ingredient_id,
+           COALESCE(mi.name, ing.name)::text AS item_name,
+           CASE WHEN st.menu_item_id IS NOT NULL THEN 'menu_item' ELSE 'ingredient' END::text AS item_type,
+           st.unit, st.current_stock, st.average_cost,
+           ROUND(st.current_stock * st.average_cost, 2) AS stock_value
+    FROM stock_transactions st
+    LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+    LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
+    WHERE st.created_at <= $1
+    ORDER BY st.menu_item_id, st.ingredient_id, st.created_at DESC
+) v
+WHERE v.current_stock <> 0
+ORDER BY v.stock_value DESC, v.item_name
+`
+
+type GetStockValuationAsOfRow struct {
+	MenuItemID   uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	ItemName     string         `db:"item_name" json:"item_name"`
+	ItemType     string         `db:"item_type" json:"item_type"`
+	Unit         sql.NullString `db:"unit" json:"unit"`
+	CurrentStock string         `db:"current_stock" json:"current_stock"`
+	AverageCost  string         `db:"average_cost" json:"average_cost"`
+	StockValue   string         `db:"stock_value" json:"stock_value"`
+}
+
+// The stock of every menu item and ingredient after its last movement up to
+// the given time, valued at the weighted average cost at that point
+func (q *Queries) GetStockValuationAsOf(ctx context.Context, createdAt time.Time) ([]GetStockValuationAsOfRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStockValuationAsOf, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStockValuationAsOfRow
+	for rows.Next() {
+		var i GetStockValuationAsOfRow
+		if err := rows.Scan(
+			&i.MenuItemID,
+			&i.IngredientID,
+			&i.ItemName,
+			&i.ItemType,
+			&i.Unit,
+			&i.CurrentStock,
+			&i.AverageCost,
+			&i.StockValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStockMovedByReference = `-- name: ListStockMovedByReference :many
+SELECT menu_item_id, ingredient_id, SUM(quantity)::text AS quantity
+FROM stock_transactions
+WHERE reference_type = $1 AND reference_id = $2
+GROUP BY menu_item_id, ingredient_id
+ORDER BY menu_item_id, ingredient_id
+`
+
+type ListStockMovedByReferenceParams struct {
+	ReferenceType sql.NullString `db:"reference_type" json:"reference_type"`
+	ReferenceID   uuid.NullUUID  `db:"reference_id" json:"reference_id"`
+}
+
+type ListStockMovedByReferenceRow struct {
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	Quantity     string        `db:"quantity" json:"quantity"`
+}
+
+// The net quantity of each menu item and ingredient moved by the stock
+// transactions of one reference, such as an order
+func (q *Queries) ListStockMovedByReference(ctx context.Context, arg ListStockMovedByReferenceParams) ([]ListStockMovedByReferenceRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStockMovedByReference, arg.ReferenceType, arg.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStockMovedByReferenceRow
+	for rows.Next() {
+		var i ListStockMovedByReferenceRow
+		if err := rows.Scan(&i.MenuItemID, &i.IngredientID, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listStockTransactions = `-- name: ListStockTransactions :many
 SELECT st.id, st.menu_item_id, mi.name as menu_item_name, st.transaction_type,
        st.quantity, st.previous_stock, st.current_stock, st.reason,
-       st.reference_type, st.reference_id, st.user_id, u.username as user_name, st.created_at
+       st.reference_type, st.reference_id, st.user_id, u.username as user_name, st.created_at,
+       st.ingredient_id, ing.name as ingredient_name, st.unit,
+       st.unit_cost, st.total_cost, st.average_cost, st.location_id, sl.name as location_name
 FROM stock_transactions st
 LEFT JOIN menu_items mi ON st.menu_item_id = mi.id
+LEFT JOIN ingredients ing ON st.ingredient_id = ing.id
 LEFT JOIN users u ON st.user_id = u.id
+LEFT JOIN stock_locations sl ON st.location_id = sl.id
 WHERE ($1 = '00000000-0000-0000-0000-000000000000'::uuid OR st.menu_item_id = $1)
   AND ($2 = '0001-01-01'::date OR st.created_at >= $2)
   AND ($3 = '0001-01-01'::date OR st.created_at <= $3)
+  AND ($6 = '00000000-0000-0000-0000-000000000000'::uuid OR st.ingredient_id = $6)
+  AND ($7 = '00000000-0000-0000-0000-000000000000'::uuid OR st.location_id = $7)
 ORDER BY st.created_at DESC
 LIMIT $4 OFFSET $5
 `
@@ -85,22 +301,32 @@ type ListStockTransactionsParams struct {
 	Column3 interface{} `db:"column_3" json:"column_3"`
 	Limit   int32       `db:"limit" json:"limit"`
 	Offset  int32       `db:"offset" json:"offset"`
+	Column6 interface{} `db:"column_6" json:"column_6"`
+	Column7 interface{} `db:"column_7" json:"column_7"`
 }
 
 type ListStockTransactionsRow struct {
 	ID              uuid.UUID      `db:"id" json:"id"`
-	MenuItemID      uuid.UUID      `db:"menu_item_id" json:"menu_item_id"`
+	MenuItemID      uuid.NullUUID  `db:"menu_item_id" json:"menu_item_id"`
 	MenuItemName    sql.NullString `db:"menu_item_name" json:"menu_item_name"`
 	TransactionType string         `db:"transaction_type" json:"transaction_type"`
-	Quantity        int32          `db:"quantity" json:"quantity"`
-	PreviousStock   int32          `db:"previous_stock" json:"previous_stock"`
-	CurrentStock    int32          `db:"current_stock" json:"current_stock"`
+	Quantity        string         `db:"quantity" json:"quantity"`
+	PreviousStock   string         `db:"previous_stock" json:"previous_stock"`
+	CurrentStock    string         `db:"current_stock" json:"current_stock"`
 	Reason          string         `db:"reason" json:"reason"`
 	ReferenceType   sql.NullString `db:"reference_type" json:"reference_type"`
 	ReferenceID     uuid.NullUUID  `db:"reference_id" json:"reference_id"`
 	UserID          uuid.NullUUID  `db:"user_id" json:"user_id"`
 	UserName        sql.NullString `db:"user_name" json:"user_name"`
 	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	IngredientID    uuid.NullUUID  `db:"ingredient_id" json:"ingredient_id"`
+	IngredientName  sql.NullString `db:"ingredient_name" json:"ingredient_name"`
+	Unit            sql.NullString `db:"unit" json:"unit"`
+	UnitCost        string         `db:"unit_cost" json:"unit_cost"`
+	TotalCost       string         `db:"total_cost" json:"total_cost"`
+	AverageCost     string         `db:"average_cost" json:"average_cost"`
+	LocationID      uuid.NullUUID  `db:"location_id" json:"location_id"`
+	LocationName    sql.NullString `db:"location_name" json:"location_name"`
 }
 
 func (q *Queries) ListStockTransactions(ctx context.Context, arg ListStockTransactionsParams) ([]ListStockTransactionsRow, error) {
@@ -110,6 +336,8 @@ func (q *Queries) ListStockTransactions(ctx context.Context, arg ListStockTransa
 		arg.Column3,
 		arg.Limit,
 		arg.Offset,
+		arg.Column6,
+		arg.Column7,
 	)
 	if err != nil {
 		return nil, err
@@ -132,6 +360,14 @@ func (q *Queries) ListStockTransactions(ctx context.Context, arg ListStockTransa
 			&i.UserID,
 			&i.UserName,
 			&i.CreatedAt,
+			&i.IngredientID,
+			&i.IngredientName,
+			&i.Unit,
+			&i.UnitCost,
+			&i.TotalCost,
+			&i.AverageCost,
+			&i.LocationID,
+			&i.LocationName,
 		); err != nil {
 			return nil, err
 		}
@@ -145,3 +381,25 @@ func (q *Queries) ListStockTransactions(ctx context.Context, arg ListStockTransa
 	}
 	return items, nil
 }
+
+const setStockAverageCost = `-- name: SetStockAverageCost :exec
+WITH updated_inventory AS (
+    UPDATE inventory
+    SET average_cost = $1::numeric
+    WHERE menu_item_id = $3::uuid
+)
+UPDATE ingredients
+SET average_cost = $1::numeric
+WHERE id = $2::uuid
+`
+
+type SetStockAverageCostParams struct {
+	AverageCost  string        `db:"average_cost" json:"average_cost"`
+	IngredientID uuid.NullUUID `db:"ingredient_id" json:"ingredient_id"`
+	MenuItemID   uuid.NullUUID `db:"menu_item_id" json:"menu_item_id"`
+}
+
+func (q *Queries) SetStockAverageCost(ctx context.Context, arg SetStockAverageCostParams) error {
+	_, err := q.db.ExecContext(ctx, setStockAverageCost, arg.AverageCost, arg.IngredientID, arg.MenuItemID)
+	return err
+}
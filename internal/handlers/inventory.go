@@ -10,6 +10,8 @@ import (
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // InventoryHandler handles inventory-related HTTP requests
@@ -103,7 +105,7 @@ func (h *InventoryHandler) UpdateInventory(c *gin.Context) {
 	}
 
 	// Check that quantity is not zero
-	if updateData.Quantity == 0 {
+	if decimal.Decimal(updateData.Quantity).IsZero() {
 		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Quantity must not be zero"))
 		return
 	}
@@ -123,6 +125,8 @@ func (h *InventoryHandler) ListStockTransactions(c *gin.Context) {
 
 	// Get query parameters
 	menuItemID := c.Query("menu_item_id")
+	ingredientID := c.Query("ingredient_id")
+	locationID := c.Query("location_id")
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 	limitStr := c.DefaultQuery("limit", "50")
@@ -137,6 +141,14 @@ func (h *InventoryHandler) ListStockTransactions(c *gin.Context) {
 		filter.MenuItemID = &defaultUUID
 	}
 
+	if ingredientID != "" {
+		filter.IngredientID = &ingredientID
+	}
+
+	if locationID != "" {
+		filter.LocationID = &locationID
+	}
+
 	// Parse date parameters if provided
 	if startDateStr != "" {
 		startDate, err := time.Parse("2006-01-02", startDateStr)
@@ -217,3 +229,173 @@ func (h *InventoryHandler) GetLowStockItems(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// CreateIngredient handles creating an ingredient
+func (h *InventoryHandler) CreateIngredient(c *gin.Context) {
+	var ingredientData models.IngredientCreate
+	if err := c.ShouldBindJSON(&ingredientData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(ingredientData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.inventoryService.CreateIngredient(&ingredientData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetIngredient handles retrieving an ingredient with its stock level
+func (h *InventoryHandler) GetIngredient(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid ingredient ID"))
+		return
+	}
+
+	response, err := h.inventoryService.GetIngredient(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListIngredients handles retrieving ingredients with optional filtering
+func (h *InventoryHandler) ListIngredients(c *gin.Context) {
+	var filter models.IngredientFilter
+
+	isActive, ok := parseIsActive(c)
+	if !ok {
+		return
+	}
+	filter.IsActive = isActive
+
+	lowStockOnly, err := strconv.ParseBool(c.DefaultQuery("low_stock_only", "false"))
+	if err != nil {
+		lowStockOnly = false
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.LowStockOnly = lowStockOnly
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.inventoryService.ListIngredients(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateIngredient handles updating an ingredient's details
+func (h *InventoryHandler) UpdateIngredient(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid ingredient ID"))
+		return
+	}
+
+	var ingredientData models.IngredientUpdate
+	if err := c.ShouldBindJSON(&ingredientData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(ingredientData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.inventoryService.UpdateIngredient(id, &ingredientData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStockLots handles listing the lots with stock left
+func (h *InventoryHandler) ListStockLots(c *gin.Context) {
+	var filter models.StockLotFilter
+
+	if menuItemID := c.Query("menu_item_id"); menuItemID != "" {
+		if _, err := uuid.Parse(menuItemID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid menu item ID"))
+			return
+		}
+		filter.MenuItemID = &menuItemID
+	}
+
+	if ingredientID := c.Query("ingredient_id"); ingredientID != "" {
+		if _, err := uuid.Parse(ingredientID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid ingredient ID"))
+			return
+		}
+		filter.IngredientID = &ingredientID
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.inventoryService.ListStockLots(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListExpiringStockLots handles listing the lots that expire within a number of days
+func (h *InventoryHandler) ListExpiringStockLots(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 0 {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid days value, expected a whole number of days"))
+		return
+	}
+
+	response, err := h.inventoryService.ListExpiringStockLots(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
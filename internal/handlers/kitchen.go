@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// streamKeepAlive is how often an idle kitchen stream is pinged so proxies and
+// clients do not drop it
+const streamKeepAlive = 15 * time.Second
+
+// KitchenHandler handles kitchen station and kitchen display HTTP requests
+type KitchenHandler struct {
+	kitchenService *services.KitchenService
+	validate       *validator.Validate
+}
+
+// NewKitchenHandler creates a new kitchen handler
+func NewKitchenHandler(kitchenService *services.KitchenService) *KitchenHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &KitchenHandler{
+		kitchenService: kitchenService,
+		validate:       validate,
+	}
+}
+
+// CreateStation handles creating a kitchen station
+func (h *KitchenHandler) CreateStation(c *gin.Context) {
+	var stationData models.KitchenStationCreate
+	if err := c.ShouldBindJSON(&stationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(stationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.kitchenService.CreateStation(&stationData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetStation handles retrieving a kitchen station by ID
+func (h *KitchenHandler) GetStation(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid kitchen station ID"))
+		return
+	}
+
+	response, err := h.kitchenService.GetStation(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStations handles retrieving the kitchen stations
+func (h *KitchenHandler) ListStations(c *gin.Context) {
+	var isActive *bool
+	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
+		active, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid is_active value, expected true or false"))
+			return
+		}
+		isActive = &active
+	}
+
+	response, err := h.kitchenService.ListStations(isActive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateStation handles updating a kitchen station
+func (h *KitchenHandler) UpdateStation(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid kitchen station ID"))
+		return
+	}
+
+	var stationData models.KitchenStationUpdate
+	if err := c.ShouldBindJSON(&stationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(stationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.kitchenService.UpdateStation(id, &stationData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteStation handles deleting a kitchen station
+func (h *KitchenHandler) DeleteStation(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid kitchen station ID"))
+		return
+	}
+
+	response, err := h.kitchenService.DeleteStation(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListTickets handles retrieving the lines on a kitchen display
+func (h *KitchenHandler) ListTickets(c *gin.Context) {
+	var filter models.KitchenTicketFilter
+
+	if stationID := c.Query("station_id"); stationID != "" {
+		filter.StationID = &stationID
+	}
+	if orderID := c.Query("order_id"); orderID != "" {
+		filter.OrderID = &orderID
+	}
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := types.KitchenStatus(statusStr)
+		if status != types.KitchenStatusQueued && status != types.KitchenStatusPreparing &&
+			status != types.KitchenStatusReady && status != types.KitchenStatusServed {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid status, expected queued, preparing, ready or served"))
+			return
+		}
+		filter.Status = &status
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.kitchenService.ListTickets(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BumpTicket handles moving a line on the kitchen display to a later stage
+func (h *KitchenHandler) BumpTicket(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid kitchen ticket ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	// The body is optional: an empty bump moves the line to its next stage
+	var bumpData models.KitchenTicketBump
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&bumpData); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+			return
+		}
+	}
+
+	if err := h.validate.Struct(bumpData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.kitchenService.BumpTicket(id, userID.(string), &bumpData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StreamTickets streams changes to the kitchen display as Server-Sent Events.
+// Clients load the queue with ListTickets first, and again after reconnecting.
+func (h *KitchenHandler) StreamTickets(c *gin.Context) {
+	events, unsubscribe, err := h.kitchenService.Subscribe(c.Query("station_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError(err.Error()))
+		return
+	}
+	defer unsubscribe()
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().UTC())
+			return true
+		}
+	})
+}
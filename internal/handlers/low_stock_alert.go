@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LowStockAlertHandler handles in-app low stock notification HTTP requests
+type LowStockAlertHandler struct {
+	lowStockAlertService *services.LowStockAlertService
+}
+
+// NewLowStockAlertHandler creates a new low stock alert handler
+func NewLowStockAlertHandler(lowStockAlertService *services.LowStockAlertService) *LowStockAlertHandler {
+	return &LowStockAlertHandler{
+		lowStockAlertService: lowStockAlertService,
+	}
+}
+
+// ListLowStockAlerts handles listing the low stock alert feed
+func (h *LowStockAlertHandler) ListLowStockAlerts(c *gin.Context) {
+	filter := models.LowStockAlertFilter{
+		OpenOnly:   c.Query("open") == "true",
+		UnreadOnly: c.Query("unread") == "true",
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.lowStockAlertService.ListLowStockAlerts(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MarkLowStockAlertRead handles marking a low stock alert read
+func (h *LowStockAlertHandler) MarkLowStockAlertRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid low stock alert ID"))
+		return
+	}
+
+	response, err := h.lowStockAlertService.MarkLowStockAlertRead(id, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
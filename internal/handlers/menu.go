@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/internal/services"
@@ -229,4 +231,43 @@ func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, result)
-}
\ No newline at end of file
+}
+
+// StreamAvailability streams menu items selling out and coming back on the
+// menu as Server-Sent Events. Clients load the menu with ListMenuItems first,
+// and again after reconnecting.
+func (h *MenuHandler) StreamAvailability(c *gin.Context) {
+	events, unsubscribe, err := h.menuService.SubscribeAvailability()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError(err.Error()))
+		return
+	}
+	defer unsubscribe()
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().UTC())
+			return true
+		}
+	})
+}
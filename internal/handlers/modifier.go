@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ModifierHandler handles menu modifier HTTP requests
+type ModifierHandler struct {
+	modifierService *services.ModifierService
+	validate        *validator.Validate
+}
+
+// NewModifierHandler creates a new modifier handler
+func NewModifierHandler(modifierService *services.ModifierService) *ModifierHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &ModifierHandler{
+		modifierService: modifierService,
+		validate:        validate,
+	}
+}
+
+// CreateModifierGroup handles creating a modifier group with its options
+func (h *ModifierHandler) CreateModifierGroup(c *gin.Context) {
+	var groupData models.ModifierGroupCreate
+	if err := c.ShouldBindJSON(&groupData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(groupData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.modifierService.CreateModifierGroup(&groupData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetModifierGroup handles retrieving a modifier group by ID
+func (h *ModifierHandler) GetModifierGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier group ID"))
+		return
+	}
+
+	response, err := h.modifierService.GetModifierGroup(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListModifierGroups handles retrieving a list of modifier groups
+func (h *ModifierHandler) ListModifierGroups(c *gin.Context) {
+	var filter models.ModifierGroupFilter
+
+	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid is_active value, expected true or false"))
+			return
+		}
+		filter.IsActive = &isActive
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.modifierService.ListModifierGroups(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateModifierGroup handles updating an existing modifier group
+func (h *ModifierHandler) UpdateModifierGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier group ID"))
+		return
+	}
+
+	var groupData models.ModifierGroupUpdate
+	if err := c.ShouldBindJSON(&groupData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(groupData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.modifierService.UpdateModifierGroup(id, &groupData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteModifierGroup handles deleting a modifier group
+func (h *ModifierHandler) DeleteModifierGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier group ID"))
+		return
+	}
+
+	response, err := h.modifierService.DeleteModifierGroup(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddModifierOption handles adding an option to a modifier group
+func (h *ModifierHandler) AddModifierOption(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(groupID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier group ID"))
+		return
+	}
+
+	var optionData models.ModifierOptionCreate
+	if err := c.ShouldBindJSON(&optionData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(optionData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.modifierService.AddModifierOption(groupID, &optionData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateModifierOption handles updating a modifier option
+func (h *ModifierHandler) UpdateModifierOption(c *gin.Context) {
+	id := c.Param("optionId")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier option ID"))
+		return
+	}
+
+	var optionData models.ModifierOptionUpdate
+	if err := c.ShouldBindJSON(&optionData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(optionData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.modifierService.UpdateModifierOption(id, &optionData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteModifierOption handles deleting a modifier option
+func (h *ModifierHandler) DeleteModifierOption(c *gin.Context) {
+	id := c.Param("optionId")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier option ID"))
+		return
+	}
+
+	response, err := h.modifierService.DeleteModifierOption(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListMenuItemModifierGroups handles listing the modifier groups offered with a menu item
+func (h *ModifierHandler) ListMenuItemModifierGroups(c *gin.Context) {
+	menuItemID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid menu item ID"))
+		return
+	}
+
+	response, err := h.modifierService.ListMenuItemModifierGroups(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AttachModifierGroup handles offering a modifier group with a menu item
+func (h *ModifierHandler) AttachModifierGroup(c *gin.Context) {
+	menuItemID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid menu item ID"))
+		return
+	}
+
+	var attachData models.MenuItemModifierGroupAttach
+	if err := c.ShouldBindJSON(&attachData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(attachData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.modifierService.AttachModifierGroup(menuItemID, &attachData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DetachModifierGroup handles removing a modifier group from a menu item
+func (h *ModifierHandler) DetachModifierGroup(c *gin.Context) {
+	menuItemID := c.Param("id")
+	groupID := c.Param("groupId")
+
+	response, err := h.modifierService.DetachModifierGroup(menuItemID, groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -149,6 +149,160 @@ func (h *OrderHandler) AddItemToOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// UpdateOrderItem handles changing the quantity or notes of an order line
+func (h *OrderHandler) UpdateOrderItem(c *gin.Context) {
+	orderID := c.Param("id")
+	itemID := c.Param("itemId")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var itemData models.OrderItemUpdate
+	if err := c.ShouldBindJSON(&itemData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: " + err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(itemData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
+	}
+
+	result, err := h.orderService.UpdateOrderItem(orderID, itemID, userID.(string), &itemData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RemoveOrderItem handles removing a line from an order
+func (h *OrderHandler) RemoveOrderItem(c *gin.Context) {
+	orderID := c.Param("id")
+	itemID := c.Param("itemId")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	result, err := h.orderService.RemoveOrderItem(orderID, itemID, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SubmitOrder handles sending a draft order to the kitchen
+func (h *OrderHandler) SubmitOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	result, err := h.orderService.SubmitOrder(orderID, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// TransferOrder handles moving an open order to another table or changing its guest count
+func (h *OrderHandler) TransferOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var transferData models.OrderTransfer
+	if err := c.ShouldBindJSON(&transferData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: " + err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(transferData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
+	}
+
+	result, err := h.orderService.TransferOrder(orderID, userID.(string), &transferData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MergeOrders handles moving every line of another open order into this one
+func (h *OrderHandler) MergeOrders(c *gin.Context) {
+	orderID := c.Param("id")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var mergeData models.OrderMerge
+	if err := c.ShouldBindJSON(&mergeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: " + err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(mergeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
+	}
+
+	result, err := h.orderService.MergeOrders(orderID, userID.(string), &mergeData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SplitOrder handles moving some lines of an open order to a new order
+func (h *OrderHandler) SplitOrder(c *gin.Context) {
+	orderID := c.Param("id")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var splitData models.OrderSplit
+	if err := c.ShouldBindJSON(&splitData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: " + err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(splitData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
+	}
+
+	result, err := h.orderService.SplitOrder(orderID, userID.(string), &splitData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // CompleteOrder handles order completion and payment processing
 func (h *OrderHandler) CompleteOrder(c *gin.Context) {
 	orderID := c.Param("id")
@@ -164,14 +318,9 @@ func (h *OrderHandler) CompleteOrder(c *gin.Context) {
 		return
 	}
 
-	// Validation for completion is different, so we'll do minimal validation here
-	if updateData.PaymentMethod != nil {
-		pm := *updateData.PaymentMethod
-		if pm != types.PaymentMethodCash && pm != types.PaymentMethodCard && 
-		   pm != types.PaymentMethodQris && pm != types.PaymentMethodTransfer {
-			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid payment method"))
-			return
-		}
+	if err := h.validate.Struct(updateData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
 	}
 
 	result, err := h.orderService.CompleteOrder(orderID, userID.(string), &updateData)
@@ -198,6 +347,11 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
 		return
 	}
 
+	if err := h.validate.Struct(updateData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: " + err.Error()))
+		return
+	}
+
 	result, err := h.orderService.CancelOrder(orderID, userID.(string), &updateData)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PromotionHandler handles promotion and voucher HTTP requests
+type PromotionHandler struct {
+	promotionService *services.PromotionService
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(promotionService *services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// CreatePromotion handles creating a new promotion or voucher
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var promotionData models.PromotionCreate
+	if err := c.ShouldBindJSON(&promotionData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("User not authenticated"))
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError("Internal server error"))
+		return
+	}
+
+	response, err := h.promotionService.CreatePromotion(userIDStr, &promotionData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetPromotion handles retrieving a promotion by ID
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid promotion ID"))
+		return
+	}
+
+	response, err := h.promotionService.GetPromotion(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListPromotions handles retrieving a list of promotions
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	var filter models.PromotionFilter
+
+	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid is_active value, expected true or false"))
+			return
+		}
+		filter.IsActive = &isActive
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.promotionService.ListPromotions(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdatePromotion handles updating an existing promotion
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid promotion ID"))
+		return
+	}
+
+	var updateData models.PromotionUpdate
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	response, err := h.promotionService.UpdatePromotion(id, &updateData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeletePromotion handles deleting a promotion
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid promotion ID"))
+		return
+	}
+
+	response, err := h.promotionService.DeletePromotion(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
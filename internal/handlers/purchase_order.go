@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// PurchaseOrderHandler handles purchase order and goods receiving HTTP requests
+type PurchaseOrderHandler struct {
+	purchaseOrderService *services.PurchaseOrderService
+	validate             *validator.Validate
+}
+
+// NewPurchaseOrderHandler creates a new purchase order handler
+func NewPurchaseOrderHandler(purchaseOrderService *services.PurchaseOrderService) *PurchaseOrderHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &PurchaseOrderHandler{
+		purchaseOrderService: purchaseOrderService,
+		validate:             validate,
+	}
+}
+
+// CreatePurchaseOrder handles drafting a purchase order
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var orderData models.PurchaseOrderCreate
+	if err := c.ShouldBindJSON(&orderData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(orderData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.purchaseOrderService.CreatePurchaseOrder(userID.(string), &orderData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetPurchaseOrder handles retrieving a purchase order with its lines and deliveries
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid purchase order ID"))
+		return
+	}
+
+	response, err := h.purchaseOrderService.GetPurchaseOrder(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListPurchaseOrders handles listing purchase orders
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	var filter models.PurchaseOrderFilter
+
+	if supplierID := c.Query("supplier_id"); supplierID != "" {
+		if _, err := uuid.Parse(supplierID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid supplier ID"))
+			return
+		}
+		filter.SupplierID = &supplierID
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := types.PurchaseOrderStatus(statusStr)
+		switch status {
+		case types.PurchaseOrderStatusDraft, types.PurchaseOrderStatusSent, types.PurchaseOrderStatusPartiallyReceived,
+			types.PurchaseOrderStatusReceived, types.PurchaseOrderStatusCancelled:
+		default:
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid status value, expected draft, sent, partially_received, received or cancelled"))
+			return
+		}
+		filter.Status = &status
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.purchaseOrderService.ListPurchaseOrders(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdatePurchaseOrder handles changing a draft purchase order
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid purchase order ID"))
+		return
+	}
+
+	var orderData models.PurchaseOrderUpdate
+	if err := c.ShouldBindJSON(&orderData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(orderData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.purchaseOrderService.UpdatePurchaseOrder(id, &orderData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SendPurchaseOrder handles marking a draft purchase order as sent to the supplier
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid purchase order ID"))
+		return
+	}
+
+	response, err := h.purchaseOrderService.SendPurchaseOrder(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelPurchaseOrder handles cancelling a purchase order
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid purchase order ID"))
+		return
+	}
+
+	response, err := h.purchaseOrderService.CancelPurchaseOrder(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReceiveGoods handles recording a delivery against a purchase order
+func (h *PurchaseOrderHandler) ReceiveGoods(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid purchase order ID"))
+		return
+	}
+
+	var receiptData models.GoodsReceiptCreate
+	if err := c.ShouldBindJSON(&receiptData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(receiptData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.purchaseOrderService.ReceiveGoods(id, userID.(string), &receiptData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
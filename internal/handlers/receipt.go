@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReceiptHandler handles receipt printing HTTP requests
+type ReceiptHandler struct {
+	receiptService *services.ReceiptService
+}
+
+// NewReceiptHandler creates a new receipt handler
+func NewReceiptHandler(receiptService *services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptService: receiptService,
+	}
+}
+
+// PrintReceipt handles rendering a completed order's receipt for printing. Each
+// call is logged as a print, so repeated calls return marked reprints.
+func (h *ReceiptHandler) PrintReceipt(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid order ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	format, err := receipt.ParseFormat(c.DefaultQuery("format", string(receipt.FormatText)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	document, err := h.receiptService.PrintReceipt(orderID, userID.(string), format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", document.Filename))
+	c.Header("X-Receipt-Copy", strconv.Itoa(document.Copy))
+	c.Data(http.StatusOK, document.ContentType, document.Body)
+}
+
+// ListReceiptPrints handles retrieving the print history of an order's receipt
+func (h *ReceiptHandler) ListReceiptPrints(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid order ID"))
+		return
+	}
+
+	response, err := h.receiptService.ListReceiptPrints(orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// RecipeHandler handles menu item and modifier option recipe HTTP requests
+type RecipeHandler struct {
+	recipeService *services.RecipeService
+	validate      *validator.Validate
+}
+
+// NewRecipeHandler creates a new recipe handler
+func NewRecipeHandler(recipeService *services.RecipeService) *RecipeHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &RecipeHandler{
+		recipeService: recipeService,
+		validate:      validate,
+	}
+}
+
+// GetMenuItemRecipe handles retrieving the ingredients of a menu item
+func (h *RecipeHandler) GetMenuItemRecipe(c *gin.Context) {
+	menuItemID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid menu item ID"))
+		return
+	}
+
+	response, err := h.recipeService.GetMenuItemRecipe(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetMenuItemRecipe handles replacing the ingredients of a menu item
+func (h *RecipeHandler) SetMenuItemRecipe(c *gin.Context) {
+	menuItemID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid menu item ID"))
+		return
+	}
+
+	var recipeData models.RecipeUpdate
+	if err := c.ShouldBindJSON(&recipeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(recipeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.recipeService.SetMenuItemRecipe(menuItemID, &recipeData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetModifierOptionRecipe handles retrieving the ingredients a modifier option adds
+func (h *RecipeHandler) GetModifierOptionRecipe(c *gin.Context) {
+	optionID := c.Param("optionId")
+
+	// Validate UUID
+	_, err := uuid.Parse(optionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier option ID"))
+		return
+	}
+
+	response, err := h.recipeService.GetModifierOptionRecipe(optionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetModifierOptionRecipe handles replacing the ingredients a modifier option adds
+func (h *RecipeHandler) SetModifierOptionRecipe(c *gin.Context) {
+	optionID := c.Param("optionId")
+
+	// Validate UUID
+	_, err := uuid.Parse(optionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid modifier option ID"))
+		return
+	}
+
+	var recipeData models.RecipeUpdate
+	if err := c.ShouldBindJSON(&recipeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(recipeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.recipeService.SetModifierOptionRecipe(optionID, &recipeData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
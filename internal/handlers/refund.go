@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RefundHandler handles refund and return HTTP requests
+type RefundHandler struct {
+	refundService *services.RefundService
+}
+
+// NewRefundHandler creates a new refund handler
+func NewRefundHandler(refundService *services.RefundService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+	}
+}
+
+// CreateRefund handles refunding some or all of a completed order's items
+func (h *RefundHandler) CreateRefund(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid order ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	userRoleStr, _ := userRole.(string)
+
+	var refundData models.RefundCreate
+	if err := c.ShouldBindJSON(&refundData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if refundData.PaymentMethod != types.PaymentMethodCash && refundData.PaymentMethod != types.PaymentMethodCard &&
+		refundData.PaymentMethod != types.PaymentMethodQris && refundData.PaymentMethod != types.PaymentMethodTransfer {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid payment method"))
+		return
+	}
+
+	response, err := h.refundService.CreateRefund(orderID, userID.(string), userRoleStr, &refundData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListRefunds handles listing the refunds made against an order
+func (h *RefundHandler) ListRefunds(c *gin.Context) {
+	orderID := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid order ID"))
+		return
+	}
+
+	response, err := h.refundService.ListRefunds(orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
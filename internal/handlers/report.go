@@ -76,6 +76,75 @@ func (h *ReportHandler) GetSalesByCategoryReport(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetSalesByModifierReport handles sales by modifier report requests
+func (h *ReportHandler) GetSalesByModifierReport(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("start_date and end_date parameters are required in YYYY-MM-DD format"))
+		return
+	}
+
+	result, err := h.reportService.GetSalesByModifierReport(startDateStr, endDateStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPrepTimeReport handles kitchen prep time report requests
+func (h *ReportHandler) GetPrepTimeReport(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("start_date and end_date parameters are required in YYYY-MM-DD format"))
+		return
+	}
+
+	result, err := h.reportService.GetPrepTimeReport(startDateStr, endDateStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetWasteReport handles waste report requests
+func (h *ReportHandler) GetWasteReport(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("start_date and end_date parameters are required in YYYY-MM-DD format"))
+		return
+	}
+
+	result, err := h.reportService.GetWasteReport(startDateStr, endDateStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetStockValuationReport handles stock valuation report requests; the date
+// defaults to today
+func (h *ReportHandler) GetStockValuationReport(c *gin.Context) {
+	result, err := h.reportService.GetStockValuationReport(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetTopSellingItemsReport handles top selling items report requests
 func (h *ReportHandler) GetTopSellingItemsReport(c *gin.Context) {
 	startDateStr := c.Query("start_date")
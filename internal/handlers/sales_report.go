@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SalesReportHandler handles X and Z report HTTP requests
+type SalesReportHandler struct {
+	salesReportService *services.SalesReportService
+}
+
+// NewSalesReportHandler creates a new sales report handler
+func NewSalesReportHandler(salesReportService *services.SalesReportService) *SalesReportHandler {
+	return &SalesReportHandler{
+		salesReportService: salesReportService,
+	}
+}
+
+// writeDocument sends a rendered report for printing
+func writeDocument(c *gin.Context, document *receipt.Document) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", document.Filename))
+	c.Data(http.StatusOK, document.ContentType, document.Body)
+}
+
+// GetXReport handles retrieving the running totals since the last Z report, as
+// JSON or, when format is given, rendered for printing
+func (h *SalesReportHandler) GetXReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	if formatStr := c.Query("format"); formatStr != "" {
+		format, err := receipt.ParseFormat(formatStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError(err.Error()))
+			return
+		}
+
+		document, err := h.salesReportService.PrintXReport(userID.(string), format)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+			return
+		}
+
+		writeDocument(c, document)
+		return
+	}
+
+	response, err := h.salesReportService.GetXReport(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GenerateZReport handles closing the period since the last Z report
+func (h *SalesReportHandler) GenerateZReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	response, err := h.salesReportService.GenerateZReport(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetZReport handles retrieving a stored Z report, as JSON or, when format is
+// given, rendered for reprinting
+func (h *SalesReportHandler) GetZReport(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid Z report ID"))
+		return
+	}
+
+	if formatStr := c.Query("format"); formatStr != "" {
+		format, err := receipt.ParseFormat(formatStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError(err.Error()))
+			return
+		}
+
+		document, err := h.salesReportService.PrintZReport(id, format)
+		if err != nil {
+			c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+			return
+		}
+
+		writeDocument(c, document)
+		return
+	}
+
+	response, err := h.salesReportService.GetZReport(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListZReports handles retrieving stored Z reports, newest first
+func (h *SalesReportHandler) ListZReports(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	response, err := h.salesReportService.ListZReports(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ShiftHandler handles cash drawer shift HTTP requests
+type ShiftHandler struct {
+	shiftService *services.ShiftService
+	validate     *validator.Validate
+}
+
+// NewShiftHandler creates a new shift handler
+func NewShiftHandler(shiftService *services.ShiftService) *ShiftHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &ShiftHandler{
+		shiftService: shiftService,
+		validate:     validate,
+	}
+}
+
+// OpenShift handles opening a cash drawer shift for the current cashier
+func (h *ShiftHandler) OpenShift(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var shiftData models.CashShiftOpen
+	if err := c.ShouldBindJSON(&shiftData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	response, err := h.shiftService.OpenShift(userID.(string), &shiftData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetCurrentShift handles retrieving the current cashier's open shift
+func (h *ShiftHandler) GetCurrentShift(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	response, err := h.shiftService.GetCurrentShift(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetShift handles retrieving a shift by ID
+func (h *ShiftHandler) GetShift(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid shift ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	userRoleStr, _ := userRole.(string)
+
+	response, err := h.shiftService.GetShift(id, userID.(string), userRoleStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListShifts handles retrieving shifts, optionally filtered by cashier and status
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	var filter models.CashShiftFilter
+
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid user ID"))
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := types.ShiftStatus(statusStr)
+		if status != types.ShiftStatusOpen && status != types.ShiftStatusClosed {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid status value, expected open or closed"))
+			return
+		}
+		filter.Status = &status
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.shiftService.ListShifts(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddMovement handles recording a cash drop or payout on a shift
+func (h *ShiftHandler) AddMovement(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid shift ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	userRoleStr, _ := userRole.(string)
+
+	var movementData models.CashMovementCreate
+	if err := c.ShouldBindJSON(&movementData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(movementData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.shiftService.AddMovement(id, userID.(string), userRoleStr, &movementData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CloseShift handles closing a shift with the counted drawer
+func (h *ShiftHandler) CloseShift(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid shift ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+	userRole, _ := c.Get("user_role")
+	userRoleStr, _ := userRole.(string)
+
+	var closeData models.CashShiftClose
+	if err := c.ShouldBindJSON(&closeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(closeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.shiftService.CloseShift(id, userID.(string), userRoleStr, &closeData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// StockLocationHandler handles stock location and stock transfer HTTP requests
+type StockLocationHandler struct {
+	stockLocationService *services.StockLocationService
+	validate             *validator.Validate
+}
+
+// NewStockLocationHandler creates a new stock location handler
+func NewStockLocationHandler(stockLocationService *services.StockLocationService) *StockLocationHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &StockLocationHandler{
+		stockLocationService: stockLocationService,
+		validate:             validate,
+	}
+}
+
+// CreateStockLocation handles adding a stock location
+func (h *StockLocationHandler) CreateStockLocation(c *gin.Context) {
+	var locationData models.StockLocationCreate
+	if err := c.ShouldBindJSON(&locationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(locationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockLocationService.CreateStockLocation(&locationData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateStockLocation handles updating a stock location's details
+func (h *StockLocationHandler) UpdateStockLocation(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock location ID"))
+		return
+	}
+
+	var locationData models.StockLocationUpdate
+	if err := c.ShouldBindJSON(&locationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(locationData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockLocationService.UpdateStockLocation(id, &locationData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStockLocations handles listing stock locations
+func (h *StockLocationHandler) ListStockLocations(c *gin.Context) {
+	includeInactive := c.Query("include_inactive") == "true"
+
+	response, err := h.stockLocationService.ListStockLocations(includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStockBalances handles retrieving the stock held at a location
+func (h *StockLocationHandler) ListStockBalances(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock location ID"))
+		return
+	}
+
+	response, err := h.stockLocationService.ListStockBalances(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateStockTransfer handles moving stock from one location to another
+func (h *StockLocationHandler) CreateStockTransfer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var transferData models.StockTransferCreate
+	if err := c.ShouldBindJSON(&transferData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(transferData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockLocationService.CreateStockTransfer(userID.(string), &transferData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetStockTransfer handles retrieving a stock transfer with its lines
+func (h *StockLocationHandler) GetStockTransfer(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock transfer ID"))
+		return
+	}
+
+	response, err := h.stockLocationService.GetStockTransfer(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStockTransfers handles listing stock transfers
+func (h *StockLocationHandler) ListStockTransfers(c *gin.Context) {
+	var filter models.StockTransferFilter
+
+	if locationID := c.Query("location_id"); locationID != "" {
+		if _, err := uuid.Parse(locationID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock location ID"))
+			return
+		}
+		filter.LocationID = &locationID
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.stockLocationService.ListStockTransfers(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// StockTakeHandler handles stock take HTTP requests
+type StockTakeHandler struct {
+	stockTakeService *services.StockTakeService
+	validate         *validator.Validate
+}
+
+// NewStockTakeHandler creates a new stock take handler
+func NewStockTakeHandler(stockTakeService *services.StockTakeService) *StockTakeHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &StockTakeHandler{
+		stockTakeService: stockTakeService,
+		validate:         validate,
+	}
+}
+
+// CreateStockTake handles starting a stock take
+func (h *StockTakeHandler) CreateStockTake(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var stockTakeData models.StockTakeCreate
+	if err := c.ShouldBindJSON(&stockTakeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(stockTakeData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockTakeService.CreateStockTake(userID.(string), &stockTakeData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetStockTake handles retrieving a stock take with its lines and variances
+func (h *StockTakeHandler) GetStockTake(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock take ID"))
+		return
+	}
+
+	response, err := h.stockTakeService.GetStockTake(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStockTakes handles listing stock takes
+func (h *StockTakeHandler) ListStockTakes(c *gin.Context) {
+	var filter models.StockTakeFilter
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := types.StockTakeStatus(statusStr)
+		switch status {
+		case types.StockTakeStatusOpen, types.StockTakeStatusApproved, types.StockTakeStatusCancelled:
+		default:
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid status value, expected open, approved or cancelled"))
+			return
+		}
+		filter.Status = &status
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.stockTakeService.ListStockTakes(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RecordCounts handles entering counted quantities in bulk
+func (h *StockTakeHandler) RecordCounts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock take ID"))
+		return
+	}
+
+	var countData models.StockTakeCounts
+	if err := c.ShouldBindJSON(&countData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(countData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockTakeService.RecordCounts(id, userID.(string), &countData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RecordScan handles one scan of an item's label during a stock take
+func (h *StockTakeHandler) RecordScan(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock take ID"))
+		return
+	}
+
+	var scanData models.StockTakeScan
+	if err := c.ShouldBindJSON(&scanData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(scanData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.stockTakeService.RecordScan(id, userID.(string), &scanData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveStockTake handles approving a stock take and posting its variances
+func (h *StockTakeHandler) ApproveStockTake(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock take ID"))
+		return
+	}
+
+	response, err := h.stockTakeService.ApproveStockTake(id, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelStockTake handles abandoning a stock take
+func (h *StockTakeHandler) CancelStockTake(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid stock take ID"))
+		return
+	}
+
+	response, err := h.stockTakeService.CancelStockTake(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
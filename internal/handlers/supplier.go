@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// SupplierHandler handles supplier HTTP requests
+type SupplierHandler struct {
+	supplierService *services.SupplierService
+	validate        *validator.Validate
+}
+
+// NewSupplierHandler creates a new supplier handler
+func NewSupplierHandler(supplierService *services.SupplierService) *SupplierHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &SupplierHandler{
+		supplierService: supplierService,
+		validate:        validate,
+	}
+}
+
+// CreateSupplier handles creating a supplier
+func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
+	var supplierData models.SupplierCreate
+	if err := c.ShouldBindJSON(&supplierData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(supplierData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.supplierService.CreateSupplier(&supplierData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetSupplier handles retrieving a supplier
+func (h *SupplierHandler) GetSupplier(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid supplier ID"))
+		return
+	}
+
+	response, err := h.supplierService.GetSupplier(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListSuppliers handles listing suppliers
+func (h *SupplierHandler) ListSuppliers(c *gin.Context) {
+	var filter models.SupplierFilter
+
+	isActive, ok := parseIsActive(c)
+	if !ok {
+		return
+	}
+	filter.IsActive = isActive
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.supplierService.ListSuppliers(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSupplier handles updating a supplier's details
+func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid supplier ID"))
+		return
+	}
+
+	var supplierData models.SupplierUpdate
+	if err := c.ShouldBindJSON(&supplierData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(supplierData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.supplierService.UpdateSupplier(id, &supplierData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// TableHandler handles floor area, dining table and floor plan HTTP requests
+type TableHandler struct {
+	tableService *services.TableService
+	validate     *validator.Validate
+}
+
+// NewTableHandler creates a new table handler
+func NewTableHandler(tableService *services.TableService) *TableHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &TableHandler{
+		tableService: tableService,
+		validate:     validate,
+	}
+}
+
+// parseIsActive reads the optional is_active query parameter
+func parseIsActive(c *gin.Context) (*bool, bool) {
+	isActiveStr := c.Query("is_active")
+	if isActiveStr == "" {
+		return nil, true
+	}
+
+	active, err := strconv.ParseBool(isActiveStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid is_active value, expected true or false"))
+		return nil, false
+	}
+	return &active, true
+}
+
+// GetFloorPlan handles retrieving the tables with the orders open on them
+func (h *TableHandler) GetFloorPlan(c *gin.Context) {
+	var floorAreaID *string
+	if areaID := c.Query("floor_area_id"); areaID != "" {
+		floorAreaID = &areaID
+	}
+
+	response, err := h.tableService.GetFloorPlan(floorAreaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateFloorArea handles creating a floor area
+func (h *TableHandler) CreateFloorArea(c *gin.Context) {
+	var areaData models.FloorAreaCreate
+	if err := c.ShouldBindJSON(&areaData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(areaData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.tableService.CreateFloorArea(&areaData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetFloorArea handles retrieving a floor area by ID
+func (h *TableHandler) GetFloorArea(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid floor area ID"))
+		return
+	}
+
+	response, err := h.tableService.GetFloorArea(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListFloorAreas handles retrieving the floor areas
+func (h *TableHandler) ListFloorAreas(c *gin.Context) {
+	isActive, ok := parseIsActive(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.tableService.ListFloorAreas(isActive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateFloorArea handles updating a floor area
+func (h *TableHandler) UpdateFloorArea(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid floor area ID"))
+		return
+	}
+
+	var areaData models.FloorAreaUpdate
+	if err := c.ShouldBindJSON(&areaData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(areaData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.tableService.UpdateFloorArea(id, &areaData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteFloorArea handles deleting a floor area
+func (h *TableHandler) DeleteFloorArea(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid floor area ID"))
+		return
+	}
+
+	response, err := h.tableService.DeleteFloorArea(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTable handles creating a dining table
+func (h *TableHandler) CreateTable(c *gin.Context) {
+	var tableData models.DiningTableCreate
+	if err := c.ShouldBindJSON(&tableData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(tableData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.tableService.CreateTable(&tableData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetTable handles retrieving a dining table by ID
+func (h *TableHandler) GetTable(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid table ID"))
+		return
+	}
+
+	response, err := h.tableService.GetTable(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListTables handles retrieving the dining tables
+func (h *TableHandler) ListTables(c *gin.Context) {
+	var filter models.DiningTableFilter
+
+	if areaID := c.Query("floor_area_id"); areaID != "" {
+		filter.FloorAreaID = &areaID
+	}
+
+	isActive, ok := parseIsActive(c)
+	if !ok {
+		return
+	}
+	filter.IsActive = isActive
+
+	response, err := h.tableService.ListTables(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTable handles updating a dining table
+func (h *TableHandler) UpdateTable(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid table ID"))
+		return
+	}
+
+	var tableData models.DiningTableUpdate
+	if err := c.ShouldBindJSON(&tableData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(tableData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.tableService.UpdateTable(id, &tableData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTable handles deleting a dining table
+func (h *TableHandler) DeleteTable(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid table ID"))
+		return
+	}
+
+	response, err := h.tableService.DeleteTable(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// UnitHandler handles unit of measure HTTP requests
+type UnitHandler struct {
+	unitService *services.UnitService
+	validate    *validator.Validate
+}
+
+// NewUnitHandler creates a new unit of measure handler
+func NewUnitHandler(unitService *services.UnitService) *UnitHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &UnitHandler{
+		unitService: unitService,
+		validate:    validate,
+	}
+}
+
+// ListUnits handles listing the units of measure stock can be counted in
+func (h *UnitHandler) ListUnits(c *gin.Context) {
+	response, err := h.unitService.ListUnits()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateUnit handles registering a unit of measure
+func (h *UnitHandler) CreateUnit(c *gin.Context) {
+	var unitData models.UnitOfMeasureCreate
+	if err := c.ShouldBindJSON(&unitData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(unitData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.unitService.CreateUnit(&unitData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// WasteHandler handles waste and spoilage HTTP requests
+type WasteHandler struct {
+	wasteService *services.WasteService
+	validate     *validator.Validate
+}
+
+// NewWasteHandler creates a new waste handler
+func NewWasteHandler(wasteService *services.WasteService) *WasteHandler {
+	validate := validator.New()
+	types.RegisterValidatorRegistrations(validate)
+
+	return &WasteHandler{
+		wasteService: wasteService,
+		validate:     validate,
+	}
+}
+
+// RecordWaste handles writing stock off as waste
+func (h *WasteHandler) RecordWaste(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	var wasteData models.WasteCreate
+	if err := c.ShouldBindJSON(&wasteData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid request data: "+err.Error()))
+		return
+	}
+
+	if err := h.validate.Struct(wasteData); err != nil {
+		c.JSON(http.StatusBadRequest, types.APIResponseWithError("Validation error: "+err.Error()))
+		return
+	}
+
+	response, err := h.wasteService.RecordWaste(userID.(string), &wasteData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// WriteOffExpiredLots handles writing off every expired stock lot as waste
+func (h *WasteHandler) WriteOffExpiredLots(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, types.APIResponseWithError("Unauthorized"))
+		return
+	}
+
+	response, err := h.wasteService.WriteOffExpiredLots(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListWaste handles listing waste records
+func (h *WasteHandler) ListWaste(c *gin.Context) {
+	var filter models.WasteFilter
+
+	if reasonStr := c.Query("reason"); reasonStr != "" {
+		reason := types.WasteReason(reasonStr)
+		switch reason {
+		case types.WasteReasonExpired, types.WasteReasonDamaged, types.WasteReasonStaffMeal, types.WasteReasonComp:
+		default:
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid reason value, expected expired, damaged, staff_meal or comp"))
+			return
+		}
+		filter.Reason = &reason
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			c.JSON(http.StatusBadRequest, types.APIResponseWithError("Invalid user ID"))
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	filter.Limit = limit
+	filter.Offset = offset
+
+	response, err := h.wasteService.ListWaste(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.APIResponseWithError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,115 @@
+package kitchen
+
+import (
+	"sync"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// Event types pushed to kitchen displays
+const (
+	EventTicketsQueued  = "tickets_queued"  // Lines sent to the kitchen
+	EventTicketUpdated  = "ticket_updated"  // A line was bumped or edited
+	EventTicketsRemoved = "tickets_removed" // Lines taken off the display, e.g. the order was cancelled
+)
+
+// subscriberBuffer is how many events a display may fall behind before it
+// starts missing them
+const subscriberBuffer = 32
+
+// Event is a change to the lines shown on kitchen displays
+type Event struct {
+	Type    string                  `json:"type"`
+	Tickets []*models.KitchenTicket `json:"tickets"`
+}
+
+// subscriber is a connected display and the station it shows
+type subscriber struct {
+	stationID string // Empty for a display showing every station
+	events    chan Event
+}
+
+// Broker fans kitchen events out to the displays connected to this server.
+// It keeps no history: a display that connects, reconnects or falls behind
+// should reload its queue from the API.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewBroker creates a new kitchen event broker
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Subscribe registers a display for a station, or for every station when
+// stationID is empty. The returned function unsubscribes and closes the channel.
+func (b *Broker) Subscribe(stationID string) (<-chan Event, func()) {
+	sub := &subscriber{stationID: stationID, events: make(chan Event, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Close ends every subscription, so streams finish when the server shuts down
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Publish sends an event to every display showing at least one of its lines,
+// with only the lines that display shows. Lines without a station show on every
+// display. Publishing on a nil broker does nothing.
+func (b *Broker) Publish(event Event) {
+	if b == nil || len(event.Tickets) == 0 {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		tickets := ticketsFor(sub.stationID, event.Tickets)
+		if len(tickets) == 0 {
+			continue
+		}
+
+		// Never block the request that published: a display that is not
+		// keeping up misses the event and catches up on its next reload
+		select {
+		case sub.events <- Event{Type: event.Type, Tickets: tickets}:
+		default:
+		}
+	}
+}
+
+// ticketsFor returns the lines a display for the station shows
+func ticketsFor(stationID string, tickets []*models.KitchenTicket) []*models.KitchenTicket {
+	if stationID == "" {
+		return tickets
+	}
+
+	var shown []*models.KitchenTicket
+	for _, ticket := range tickets {
+		if ticket.StationID == nil || *ticket.StationID == stationID {
+			shown = append(shown, ticket)
+		}
+	}
+	return shown
+}
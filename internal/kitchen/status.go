@@ -0,0 +1,56 @@
+package kitchen
+
+import (
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// stages lists the kitchen statuses in the order a line moves through them
+var stages = []types.KitchenStatus{
+	types.KitchenStatusQueued,
+	types.KitchenStatusPreparing,
+	types.KitchenStatusReady,
+	types.KitchenStatusServed,
+}
+
+// stageOf returns the position of a status in the kitchen flow, or -1 if it is
+// not a kitchen status
+func stageOf(status types.KitchenStatus) int {
+	for i, stage := range stages {
+		if stage == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// NextStatus returns the stage a line moves to when it is bumped without
+// naming one
+func NextStatus(status types.KitchenStatus) (types.KitchenStatus, error) {
+	stage := stageOf(status)
+	if stage < 0 {
+		return "", fmt.Errorf("unknown kitchen status %q", status)
+	}
+	if stage == len(stages)-1 {
+		return "", fmt.Errorf("line has already been %s", status)
+	}
+	return stages[stage+1], nil
+}
+
+// CheckBump checks that a line can move from one status to another. Lines only
+// move forward, but may skip stages, e.g. a drink made on the spot goes
+// straight from queued to ready.
+func CheckBump(from, to types.KitchenStatus) error {
+	fromStage, toStage := stageOf(from), stageOf(to)
+	if fromStage < 0 {
+		return fmt.Errorf("unknown kitchen status %q", from)
+	}
+	if toStage < 0 {
+		return fmt.Errorf("unknown kitchen status %q", to)
+	}
+	if toStage <= fromStage {
+		return fmt.Errorf("cannot move a %s line back to %s", from, to)
+	}
+	return nil
+}
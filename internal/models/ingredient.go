@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// Ingredient represents stock that goes into menu items, counted in its own
+// stock unit, e.g. coffee beans in grams or milk in millilitres. It may be
+// bought in a larger purchase unit of the same dimension, e.g. beans by the kg.
+type Ingredient struct {
+	ID            string            `json:"id" db:"id"`
+	Name          string            `json:"name" db:"name"`
+	Unit          string            `json:"unit" db:"unit"`
+	PurchaseUnit  *string           `json:"purchase_unit,omitempty" db:"purchase_unit"`
+	CurrentStock  types.DecimalText `json:"current_stock" db:"current_stock"`
+	MinimumStock  types.DecimalText `json:"minimum_stock" db:"minimum_stock"`
+	CostPrice     types.DecimalText `json:"cost_price" db:"cost_price"` // Per stock unit, from the last goods received
+	IsActive      bool              `json:"is_active" db:"is_active"`
+	IsLowStock    bool              `json:"is_low_stock"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+	LastUpdatedBy *string           `json:"last_updated_by,omitempty" db:"last_updated_by"`
+}
+
+// IngredientCreate represents data to create an ingredient. Stock starts at
+// zero and is added through an inventory adjustment.
+type IngredientCreate struct {
+	Name         string             `json:"name" validate:"required,min=1,max=100"`
+	Unit         string             `json:"unit" validate:"required,min=1,max=50"`
+	PurchaseUnit *string            `json:"purchase_unit,omitempty" validate:"omitempty,min=1,max=50"`
+	MinimumStock *types.DecimalText `json:"minimum_stock,omitempty"` // Defaults to zero, in the stock unit
+	IsActive     *bool              `json:"is_active,omitempty"`     // Defaults to true
+}
+
+// IngredientUpdate represents data to update an ingredient
+type IngredientUpdate struct {
+	Name         *string            `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Unit         *string            `json:"unit,omitempty" validate:"omitempty,min=1,max=50"`
+	PurchaseUnit *string            `json:"purchase_unit,omitempty" validate:"omitempty,max=50"` // An empty string clears it
+	MinimumStock *types.DecimalText `json:"minimum_stock,omitempty"`
+	IsActive     *bool              `json:"is_active,omitempty"`
+}
+
+// IngredientFilter represents filter options for listing ingredients
+type IngredientFilter struct {
+	IsActive     *bool `json:"is_active,omitempty"`
+	LowStockOnly bool  `json:"low_stock_only"`
+	Limit        int   `json:"limit"`
+	Offset       int   `json:"offset"`
+}
+
+// RecipeItem represents the quantity of an ingredient used for one unit of a
+// menu item, or for one unit with a modifier option chosen. The quantity is
+// in the ingredient's stock unit.
+type RecipeItem struct {
+	IngredientID   string            `json:"ingredient_id" db:"ingredient_id"`
+	IngredientName string            `json:"ingredient_name" db:"ingredient_name"`
+	Unit           string            `json:"unit" db:"unit"`
+	Quantity       types.DecimalText `json:"quantity" db:"quantity"`
+}
+
+// Recipe represents the ingredients of a menu item or modifier option
+type Recipe struct {
+	MenuItemID       *string      `json:"menu_item_id,omitempty"`
+	ModifierOptionID *string      `json:"modifier_option_id,omitempty"`
+	Items            []RecipeItem `json:"items"`
+}
+
+// RecipeUpdate represents data to replace a recipe. An empty list removes the
+// recipe, so a menu item goes back to being stocked as finished goods.
+type RecipeUpdate struct {
+	Items []RecipeItemInput `json:"items" validate:"dive"`
+}
+
+// RecipeItemInput represents one ingredient line of a recipe update. The
+// quantity may be given in any unit compatible with the ingredient's stock
+// unit and is stored converted to it.
+type RecipeItemInput struct {
+	IngredientID string            `json:"ingredient_id" validate:"required,uuid"`
+	Quantity     types.DecimalText `json:"quantity" validate:"required,gt=0"`
+	Unit         *string           `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+}
@@ -10,8 +10,8 @@ import (
 type Inventory struct {
 	ID             string    `json:"id" db:"id"`
 	MenuItemID     string    `json:"menu_item_id" db:"menu_item_id"`
-	CurrentStock   int       `json:"current_stock" db:"current_stock"`
-	MinimumStock   int       `json:"minimum_stock" db:"minimum_stock"`
+	CurrentStock   types.DecimalText `json:"current_stock" db:"current_stock"`
+	MinimumStock   types.DecimalText `json:"minimum_stock" db:"minimum_stock"`
 	Unit           string    `json:"unit" db:"unit"`
 	LastUpdatedAt  time.Time `json:"last_updated_at" db:"last_updated_at"`
 	LastUpdatedBy  *string   `json:"last_updated_by,omitempty" db:"last_updated_by"`
@@ -20,28 +20,53 @@ type Inventory struct {
 	LastUpdatedByName *string `json:"last_updated_by_name,omitempty"`
 }
 
-// InventoryUpdate represents data to update inventory stock
+// InventoryUpdate represents data to update inventory stock, either a menu
+// item's finished goods or an ingredient. The quantity may be given in any unit
+// compatible with the stock unit and is converted to it.
 type InventoryUpdate struct {
-	MenuItemID    string `json:"menu_item_id" validate:"required,uuid"`
-	Quantity      int    `json:"quantity" validate:"required,ne=0"` // Can be positive or negative
-	Reason        string `json:"reason" validate:"required,min=1,max=255"`
+	MenuItemID    string            `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,excluded_with=IngredientID"`
+	IngredientID  *string           `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Quantity      types.DecimalText `json:"quantity" validate:"required"` // Can be positive or negative
+	Unit          *string           `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+	Reason        string            `json:"reason" validate:"required,min=1,max=255"`
+	LotNumber     *string           `json:"lot_number,omitempty" validate:"omitempty,max=100"` // For stock added
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"`                              // For stock added
 }
 
 // StockTransaction represents a stock transaction record
 type StockTransaction struct {
 	ID              string                    `json:"id" db:"id"`
-	MenuItemID      string                    `json:"menu_item_id" db:"menu_item_id"`
+	MenuItemID      string                    `json:"menu_item_id,omitempty" db:"menu_item_id"` // Empty for ingredient stock
 	MenuItemName    string                    `json:"menu_item_name,omitempty" db:"menu_item_name"`
+	IngredientID    *string                   `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	IngredientName  *string                   `json:"ingredient_name,omitempty"`
 	TransactionType types.TransactionType     `json:"transaction_type" db:"transaction_type"`
-	Quantity        int                       `json:"quantity" db:"quantity"`
-	PreviousStock   int                       `json:"previous_stock" db:"previous_stock"`
-	CurrentStock    int                       `json:"current_stock" db:"current_stock"`
+	Quantity        types.DecimalText         `json:"quantity" db:"quantity"`
+	PreviousStock   types.DecimalText         `json:"previous_stock" db:"previous_stock"`
+	CurrentStock    types.DecimalText         `json:"current_stock" db:"current_stock"`
+	Unit            string                    `json:"unit,omitempty" db:"unit"`
 	Reason          string                    `json:"reason" db:"reason"`
 	ReferenceType   *string                   `json:"reference_type,omitempty" db:"reference_type"`
 	ReferenceID     *string                   `json:"reference_id,omitempty" db:"reference_id"`
 	UserID          *string                   `json:"user_id,omitempty" db:"user_id"`
 	UserName        *string                   `json:"user_name,omitempty"`
+	UnitCost        types.DecimalText         `json:"unit_cost" db:"unit_cost"`       // Per stock unit
+	TotalCost       types.DecimalText         `json:"total_cost" db:"total_cost"`     // Negative for stock going out
+	AverageCost     types.DecimalText         `json:"average_cost" db:"average_cost"` // Of the stock left after the movement
+	ReceivedCost    *types.DecimalText        `json:"-"`                              // Per stock unit of stock coming in, when known
+	LocationID      *string                   `json:"location_id,omitempty" db:"location_id"` // Defaults to the selling location
+	LocationName    *string                   `json:"location_name,omitempty"`
 	CreatedAt       time.Time                 `json:"created_at" db:"created_at"`
+
+	AvailabilityChanges []*MenuItemAvailability `json:"-"` // Menu items the movement sold out or brought back
+}
+
+// StockMoved is the net quantity of a menu item's finished goods or an
+// ingredient moved by the stock transactions of one reference, in its stock unit
+type StockMoved struct {
+	MenuItemID   *string           `json:"menu_item_id,omitempty"`
+	IngredientID *string           `json:"ingredient_id,omitempty"`
+	Quantity     types.DecimalText `json:"quantity"` // Negative when more went out than came back
 }
 
 // InventoryFilter represents filter options for listing inventory
@@ -53,9 +78,11 @@ type InventoryFilter struct {
 
 // StockTransactionFilter represents filter options for listing stock transactions
 type StockTransactionFilter struct {
-	MenuItemID *string    `json:"menu_item_id,omitempty"`
-	StartDate  *time.Time `json:"start_date,omitempty"`
-	EndDate    *time.Time `json:"end_date,omitempty"`
-	Limit      int        `json:"limit"`
-	Offset     int        `json:"offset"`
+	MenuItemID   *string    `json:"menu_item_id,omitempty"`
+	IngredientID *string    `json:"ingredient_id,omitempty"`
+	LocationID   *string    `json:"location_id,omitempty"`
+	StartDate    *time.Time `json:"start_date,omitempty"`
+	EndDate      *time.Time `json:"end_date,omitempty"`
+	Limit        int        `json:"limit"`
+	Offset       int        `json:"offset"`
 }
\ No newline at end of file
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// KitchenStation represents a place where order lines are prepared, such as the
+// bar, the kitchen or the pastry counter
+type KitchenStation struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description,omitempty" db:"description"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	CategoryIDs []string  `json:"category_ids"` // Menu categories whose lines are sent here
+}
+
+// KitchenStationCreate represents data to create a kitchen station
+type KitchenStationCreate struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description,omitempty"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+	CategoryIDs []string `json:"category_ids,omitempty" validate:"dive,uuid"`
+}
+
+// KitchenStationUpdate represents data to update a kitchen station. When
+// category_ids is given it replaces the categories routed to the station.
+type KitchenStationUpdate struct {
+	Name        *string  `json:"name,omitempty" validate:"omitempty,max=100"`
+	Description *string  `json:"description,omitempty"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+	CategoryIDs []string `json:"category_ids,omitempty" validate:"omitempty,dive,uuid"`
+}
+
+// KitchenTicket represents an order line on the kitchen display, with the time
+// it reached each stage
+type KitchenTicket struct {
+	ID           string              `json:"id" db:"id"`
+	OrderID      string              `json:"order_id" db:"order_id"`
+	OrderNumber  string              `json:"order_number" db:"order_number"`
+	OrderItemID  string              `json:"order_item_id" db:"order_item_id"`
+	StationID    *string             `json:"station_id,omitempty" db:"station_id"` // Nil when the line's category is not routed to a station
+	StationName  *string             `json:"station_name,omitempty" db:"station_name"`
+	MenuItemName string              `json:"menu_item_name" db:"menu_item_name"`
+	Quantity     int                 `json:"quantity" db:"quantity"`
+	Notes        *string             `json:"notes,omitempty" db:"notes"`
+	Modifiers    string              `json:"modifiers,omitempty" db:"modifiers"`
+	Status       types.KitchenStatus `json:"status" db:"status"`
+	QueuedAt     time.Time           `json:"queued_at" db:"queued_at"`
+	PreparingAt  *time.Time          `json:"preparing_at,omitempty" db:"preparing_at"`
+	ReadyAt      *time.Time          `json:"ready_at,omitempty" db:"ready_at"`
+	ServedAt     *time.Time          `json:"served_at,omitempty" db:"served_at"`
+	BumpedBy     *string             `json:"bumped_by,omitempty" db:"bumped_by"`
+}
+
+// KitchenTicketFilter represents filter options for listing kitchen tickets
+type KitchenTicketFilter struct {
+	StationID *string              `json:"station_id,omitempty"`
+	OrderID   *string              `json:"order_id,omitempty"`
+	Status    *types.KitchenStatus `json:"status,omitempty"` // Nil lists every line not yet served
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+}
+
+// KitchenTicketBump represents a request to move a line to a later stage. With
+// no status the line moves to the next one.
+type KitchenTicketBump struct {
+	Status *types.KitchenStatus `json:"status,omitempty" validate:"omitempty,oneof=preparing ready served"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// LowStockAlert represents a menu item or an ingredient that a stock movement
+// took to its minimum stock or below. It stays open until the item is
+// restocked above its minimum, and is raised again only after that.
+type LowStockAlert struct {
+	ID                 string            `json:"id" db:"id"`
+	MenuItemID         *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID       *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName           string            `json:"item_name" db:"item_name"`
+	Unit               string            `json:"unit" db:"unit"`
+	CurrentStock       types.DecimalText `json:"current_stock" db:"current_stock"` // When the alert was raised
+	MinimumStock       types.DecimalText `json:"minimum_stock" db:"minimum_stock"`
+	StockTransactionID string            `json:"stock_transaction_id" db:"stock_transaction_id"`
+	RaisedAt           time.Time         `json:"raised_at" db:"raised_at"`
+	ResolvedAt         *time.Time        `json:"resolved_at,omitempty" db:"resolved_at"`
+	ReadAt             *time.Time        `json:"read_at,omitempty" db:"read_at"`
+	ReadBy             *string           `json:"read_by,omitempty" db:"read_by"`
+	DispatchedAt       *time.Time        `json:"dispatched_at,omitempty" db:"dispatched_at"`
+	DispatchAttempts   int               `json:"dispatch_attempts" db:"dispatch_attempts"`
+	LastError          *string           `json:"last_error,omitempty" db:"last_error"`
+}
+
+// LowStockAlertFeed represents a page of the in-app low stock alert feed
+type LowStockAlertFeed struct {
+	Alerts      []*LowStockAlert `json:"alerts"`
+	UnreadCount int64            `json:"unread_count"`
+}
+
+// LowStockAlertFilter represents filters for listing low stock alerts
+type LowStockAlertFilter struct {
+	OpenOnly   bool `json:"open_only"`   // Only alerts for items still at or below their minimum
+	UnreadOnly bool `json:"unread_only"` // Only alerts nobody has read
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+}
@@ -38,10 +38,21 @@ type MenuItem struct {
 	Price       types.DecimalText `json:"price" db:"price" validate:"required,gt=0"`
 	Cost        types.DecimalText `json:"cost" db:"cost" validate:"required,gt=0,ltefield=Price"`
 	IsAvailable bool              `json:"is_available" db:"is_available"`
+	SoldOut     bool              `json:"sold_out" db:"sold_out"` // Off the menu until the selling location is restocked
 	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
 }
 
+// MenuItemAvailability represents a menu item that sold out or came back on
+// the menu as stock moved
+type MenuItemAvailability struct {
+	MenuItemID  string `json:"menu_item_id"`
+	Name        string `json:"name"`
+	CategoryID  string `json:"category_id"`
+	IsAvailable bool   `json:"is_available"`
+	SoldOut     bool   `json:"sold_out"`
+}
+
 // MenuItemCreate represents data to create a menu item
 type MenuItemCreate struct {
 	Name        string            `json:"name" validate:"required,min=1,max=255"`
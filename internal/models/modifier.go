@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// ModifierGroup represents a set of options offered with menu items, such as
+// sizes (choose exactly one) or add-ons (choose up to a few)
+type ModifierGroup struct {
+	ID          string           `json:"id" db:"id"`
+	Name        string           `json:"name" db:"name"`
+	Description string           `json:"description,omitempty" db:"description"`
+	MinSelect   int              `json:"min_select" db:"min_select"`
+	MaxSelect   int              `json:"max_select" db:"max_select"`
+	IsActive    bool             `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+	Options     []ModifierOption `json:"options"`
+}
+
+// ModifierOption represents one choice in a modifier group
+type ModifierOption struct {
+	ID              string            `json:"id" db:"id"`
+	ModifierGroupID string            `json:"modifier_group_id" db:"modifier_group_id"`
+	Name            string            `json:"name" db:"name"`
+	PriceDelta      types.DecimalText `json:"price_delta" db:"price_delta"` // Added to the line's unit price
+	IsAvailable     bool              `json:"is_available" db:"is_available"`
+	SortOrder       int               `json:"sort_order" db:"sort_order"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// ModifierGroupCreate represents data to create a modifier group with its options
+type ModifierGroupCreate struct {
+	Name        string                 `json:"name" validate:"required,max=100"`
+	Description string                 `json:"description,omitempty"`
+	MinSelect   int                    `json:"min_select" validate:"gte=0"`
+	MaxSelect   int                    `json:"max_select" validate:"required,gt=0"`
+	IsActive    *bool                  `json:"is_active,omitempty"`
+	Options     []ModifierOptionCreate `json:"options" validate:"dive"`
+}
+
+// ModifierGroupUpdate represents data to update a modifier group
+type ModifierGroupUpdate struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,max=100"`
+	Description *string `json:"description,omitempty"`
+	MinSelect   *int    `json:"min_select,omitempty" validate:"omitempty,gte=0"`
+	MaxSelect   *int    `json:"max_select,omitempty" validate:"omitempty,gt=0"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// ModifierOptionCreate represents data to add an option to a modifier group
+type ModifierOptionCreate struct {
+	Name        string            `json:"name" validate:"required,max=100"`
+	PriceDelta  types.DecimalText `json:"price_delta"`
+	IsAvailable *bool             `json:"is_available,omitempty"`
+	SortOrder   int               `json:"sort_order"`
+}
+
+// ModifierOptionUpdate represents data to update a modifier option
+type ModifierOptionUpdate struct {
+	Name        *string            `json:"name,omitempty" validate:"omitempty,max=100"`
+	PriceDelta  *types.DecimalText `json:"price_delta,omitempty"`
+	IsAvailable *bool              `json:"is_available,omitempty"`
+	SortOrder   *int               `json:"sort_order,omitempty"`
+}
+
+// ModifierGroupFilter represents filter options for listing modifier groups
+type ModifierGroupFilter struct {
+	IsActive *bool `json:"is_active,omitempty"`
+	Limit    int   `json:"limit"`
+	Offset   int   `json:"offset"`
+}
+
+// MenuItemModifierGroupAttach represents data to offer a modifier group with a menu item
+type MenuItemModifierGroupAttach struct {
+	ModifierGroupID string `json:"modifier_group_id" validate:"required,uuid"`
+	SortOrder       int    `json:"sort_order"`
+}
+
+// OrderItemModifier represents a modifier option chosen for an order line. The
+// names and price are copied from the menu when the line is added.
+type OrderItemModifier struct {
+	ID               string            `json:"id" db:"id"`
+	OrderItemID      string            `json:"order_item_id" db:"order_item_id"`
+	ModifierOptionID *string           `json:"modifier_option_id,omitempty" db:"modifier_option_id"` // Nil once the option is deleted
+	GroupName        string            `json:"group_name" db:"group_name"`
+	OptionName       string            `json:"option_name" db:"option_name"`
+	PriceDelta       types.DecimalText `json:"price_delta" db:"price_delta"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+}
@@ -8,31 +8,81 @@ import (
 
 // Order represents a customer order
 type Order struct {
-	ID             string               `json:"id" db:"id"`
-	OrderNumber    string               `json:"order_number" db:"order_number"`
-	UserID         string               `json:"user_id" db:"user_id"`
-	Status         types.OrderStatus    `json:"status" db:"status"`
-	TotalAmount    types.DecimalText    `json:"total_amount" db:"total_amount"`
-	DiscountAmount types.DecimalText    `json:"discount_amount" db:"discount_amount"`
-	TaxAmount      types.DecimalText    `json:"tax_amount" db:"tax_amount"`
-	PaymentMethod  *types.PaymentMethod `json:"payment_method,omitempty" db:"payment_method"`
-	PaymentStatus  types.PaymentStatus  `json:"payment_status" db:"payment_status"`
-	CompletedAt    *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
-	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+	ID                 string               `json:"id" db:"id"`
+	OrderNumber        string               `json:"order_number" db:"order_number"`
+	UserID             string               `json:"user_id" db:"user_id"`
+	Status             types.OrderStatus    `json:"status" db:"status"`
+	TotalAmount        types.DecimalText    `json:"total_amount" db:"total_amount"`
+	DiscountAmount     types.DecimalText    `json:"discount_amount" db:"discount_amount"`
+	TaxAmount          types.DecimalText    `json:"tax_amount" db:"tax_amount"`
+	PaymentMethod      *types.PaymentMethod `json:"payment_method,omitempty" db:"payment_method"`
+	PaymentStatus      types.PaymentStatus  `json:"payment_status" db:"payment_status"`
+	CompletedAt        *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt          time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time            `json:"updated_at" db:"updated_at"`
+	CancellationReason *string              `json:"cancellation_reason,omitempty" db:"cancellation_reason"`
+	CancelledBy        *string              `json:"cancelled_by,omitempty" db:"cancelled_by"`
+	CancelledAt        *time.Time           `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	// Price breakdown: total = subtotal - discount + service charge + tax + rounding
+	SubtotalAmount      types.DecimalText `json:"subtotal_amount" db:"subtotal_amount"`
+	ServiceChargeAmount types.DecimalText `json:"service_charge_amount" db:"service_charge_amount"`
+	ServiceChargeRate   types.DecimalText `json:"service_charge_rate" db:"service_charge_rate"`
+	TaxRate             types.DecimalText `json:"tax_rate" db:"tax_rate"`
+	RoundingAmount      types.DecimalText `json:"rounding_amount" db:"rounding_amount"`
+	// Service: dine-in orders may be open on a table as a tab
+	OrderType  types.OrderType `json:"order_type" db:"order_type"`
+	TableID    *string         `json:"table_id,omitempty" db:"table_id"`
+	GuestCount *int            `json:"guest_count,omitempty" db:"guest_count"`
+	// Cash drawer shifts the order was completed and, for a voided sale, voided in
+	ShiftID     *string `json:"shift_id,omitempty" db:"shift_id"`
+	VoidShiftID *string `json:"void_shift_id,omitempty" db:"void_shift_id"`
 }
 
-// OrderCreate represents data to create a draft order
+// OrderCreate represents data to create a draft order. Giving a table makes it
+// a dine-in order; otherwise it defaults to takeaway.
 type OrderCreate struct {
-	Items []OrderItemCreate `json:"items" validate:"required,min=1,dive"`
+	Items      []OrderItemCreate `json:"items" validate:"required,min=1,dive"`
+	OrderType  *types.OrderType  `json:"order_type,omitempty" validate:"omitempty,oneof=dine_in takeaway delivery"`
+	TableID    *string           `json:"table_id,omitempty" validate:"omitempty,uuid"`
+	GuestCount *int              `json:"guest_count,omitempty" validate:"omitempty,gt=0"`
+}
+
+// OrderTransfer represents moving an open dine-in order to another table or
+// changing its guest count
+type OrderTransfer struct {
+	TableID    *string `json:"table_id,omitempty" validate:"omitempty,uuid"`
+	GuestCount *int    `json:"guest_count,omitempty" validate:"omitempty,gt=0"`
+}
+
+// OrderMerge represents moving every line of another open order into this one.
+// The other order is closed as merged.
+type OrderMerge struct {
+	SourceOrderID string `json:"source_order_id" validate:"required,uuid"`
+}
+
+// OrderSplit represents moving some lines of an open order to a new order, e.g.
+// so part of a table can pay separately. The new order keeps the type and table
+// unless a table is given.
+type OrderSplit struct {
+	Items      []OrderSplitItem `json:"items" validate:"required,min=1,dive"`
+	TableID    *string          `json:"table_id,omitempty" validate:"omitempty,uuid"`
+	GuestCount *int             `json:"guest_count,omitempty" validate:"omitempty,gt=0"`
+}
+
+// OrderSplitItem represents a line, or part of one, to move to the new order
+type OrderSplitItem struct {
+	OrderItemID string `json:"order_item_id" validate:"required,uuid"`
+	Quantity    *int   `json:"quantity,omitempty" validate:"omitempty,gt=0"` // Defaults to the whole line
 }
 
 // OrderUpdate represents data to update an order
 type OrderUpdate struct {
-	PaymentMethod  *types.PaymentMethod `json:"payment_method,omitempty" validate:"omitempty,oneof=cash card qris transfer"`
-	DiscountAmount *types.DecimalText   `json:"discount_amount,omitempty" validate:"omitempty,gt=0"`
-	TaxAmount      *types.DecimalText   `json:"tax_amount,omitempty" validate:"omitempty,gt=0"`
-	Reason         *string              `json:"reason,omitempty"` // For cancellation
+	PaymentMethod   *types.PaymentMethod `json:"payment_method,omitempty" validate:"omitempty,oneof=cash card qris transfer"` // Single tender for the exact total
+	Payments        []PaymentTender      `json:"payments,omitempty" validate:"omitempty,dive"`
+	DiscountAmount  *types.DecimalText   `json:"discount_amount,omitempty" validate:"omitempty,decimal_gt=0"`
+	DiscountPercent *types.DecimalText   `json:"discount_percent,omitempty" validate:"omitempty,decimal_gt=0,decimal_lte=100"` // Alternative to DiscountAmount
+	VoucherCode     *string              `json:"voucher_code,omitempty"`
+	Reason          *string              `json:"reason,omitempty"` // For cancellation
 }
 
 // OrderItem represents an item in an order
@@ -43,40 +93,76 @@ type OrderItem struct {
 	Quantity   int               `json:"quantity" db:"quantity" validate:"required,gt=0"`
 	UnitPrice  types.DecimalText `json:"unit_price" db:"unit_price"`
 	TotalPrice types.DecimalText `json:"total_price" db:"total_price"`
+	Notes      *string           `json:"notes,omitempty" db:"notes"` // e.g. "less sugar", "no ice"
+}
+
+// OrderItemStockUsage is the stock an order line took when its order was completed,
+// either a menu item's own stock or an ingredient of its recipe
+type OrderItemStockUsage struct {
+	OrderItemID  string            `json:"order_item_id" db:"order_item_id"`
+	MenuItemID   *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	Quantity     types.DecimalText `json:"quantity" db:"quantity"`
 }
 
 // OrderItemCreate represents data to create an order item
 type OrderItemCreate struct {
-	MenuItemID string `json:"menu_item_id" validate:"required,uuid"`
-	Quantity   int    `json:"quantity" validate:"required,gt=0"`
+	MenuItemID        string   `json:"menu_item_id" validate:"required,uuid"`
+	Quantity          int      `json:"quantity" validate:"required,gt=0"`
+	Notes             *string  `json:"notes,omitempty" validate:"omitempty,max=255"`
+	ModifierOptionIDs []string `json:"modifier_option_ids,omitempty" validate:"omitempty,dive,uuid"` // e.g. a size and add-ons
+}
+
+// OrderItemUpdate represents data to change a line of a draft or pending order
+type OrderItemUpdate struct {
+	Quantity *int    `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+	Notes    *string `json:"notes,omitempty" validate:"omitempty,max=255"` // An empty string clears the note
 }
 
 // OrderItemWithDetails represents an order item with menu item details
 type OrderItemWithDetails struct {
-	ID           string            `json:"id"`
-	OrderID      string            `json:"order_id"`
-	MenuItemID   string            `json:"menu_item_id"`
-	MenuItemName string            `json:"menu_item_name"`
-	Quantity     int               `json:"quantity"`
-	UnitPrice    types.DecimalText `json:"unit_price"`
-	TotalPrice   types.DecimalText `json:"total_price"`
+	ID           string              `json:"id"`
+	OrderID      string              `json:"order_id"`
+	MenuItemID   string              `json:"menu_item_id"`
+	MenuItemName string              `json:"menu_item_name"`
+	Quantity     int                 `json:"quantity"`
+	UnitPrice    types.DecimalText   `json:"unit_price"`
+	TotalPrice   types.DecimalText   `json:"total_price"`
+	Notes        *string             `json:"notes,omitempty"`
+	Modifiers    []OrderItemModifier `json:"modifiers,omitempty"`
 }
 
 // OrderWithDetails represents an order with user and item details
 type OrderWithDetails struct {
-	ID             string                 `json:"id"`
-	OrderNumber    string                 `json:"order_number"`
-	UserID         string                 `json:"user_id"`
-	UserName       string                 `json:"user_name"`
-	Status         types.OrderStatus      `json:"status"`
-	TotalAmount    types.DecimalText      `json:"total_amount"`
-	DiscountAmount types.DecimalText      `json:"discount_amount"`
-	TaxAmount      types.DecimalText      `json:"tax_amount"`
-	PaymentMethod  *types.PaymentMethod   `json:"payment_method,omitempty"`
-	PaymentStatus  types.PaymentStatus    `json:"payment_status"`
-	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
-	CreatedAt      time.Time              `json:"created_at"`
-	UpdatedAt      time.Time              `json:"updated_at"`
-	Items          []OrderItemWithDetails `json:"items"`
+	ID                  string                 `json:"id"`
+	OrderNumber         string                 `json:"order_number"`
+	UserID              string                 `json:"user_id"`
+	UserName            string                 `json:"user_name"`
+	Status              types.OrderStatus      `json:"status"`
+	TotalAmount         types.DecimalText      `json:"total_amount"`
+	DiscountAmount      types.DecimalText      `json:"discount_amount"`
+	TaxAmount           types.DecimalText      `json:"tax_amount"`
+	PaymentMethod       *types.PaymentMethod   `json:"payment_method,omitempty"`
+	PaymentStatus       types.PaymentStatus    `json:"payment_status"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	CancellationReason  *string                `json:"cancellation_reason,omitempty"`
+	CancelledBy         *string                `json:"cancelled_by,omitempty"`
+	CancelledAt         *time.Time             `json:"cancelled_at,omitempty"`
+	SubtotalAmount      types.DecimalText      `json:"subtotal_amount"`
+	ServiceChargeAmount types.DecimalText      `json:"service_charge_amount"`
+	ServiceChargeRate   types.DecimalText      `json:"service_charge_rate"`
+	TaxRate             types.DecimalText      `json:"tax_rate"`
+	RoundingAmount      types.DecimalText      `json:"rounding_amount"`
+	OrderType           types.OrderType        `json:"order_type"`
+	TableID             *string                `json:"table_id,omitempty"`
+	TableName           *string                `json:"table_name,omitempty"`
+	GuestCount          *int                   `json:"guest_count,omitempty"`
+	ShiftID             *string                `json:"shift_id,omitempty"`
+	VoidShiftID         *string                `json:"void_shift_id,omitempty"`
+	Items               []OrderItemWithDetails `json:"items"`
+	Promotions          []OrderPromotion       `json:"promotions,omitempty"`
+	Payments            []OrderPayment         `json:"payments,omitempty"`
+	ChangeAmount        *types.DecimalText     `json:"change_amount,omitempty"`
 }
-
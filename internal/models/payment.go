@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// OrderPayment represents one tender used to settle an order
+type OrderPayment struct {
+	ID            string              `json:"id" db:"id"`
+	OrderID       string              `json:"order_id" db:"order_id"`
+	PaymentMethod types.PaymentMethod `json:"payment_method" db:"payment_method"`
+	Amount        types.DecimalText   `json:"amount" db:"amount"`                 // Amount handed over, including any change
+	Reference     *string             `json:"reference,omitempty" db:"reference"` // e.g. card approval code or QRIS transaction ID
+	ChangeAmount  types.DecimalText   `json:"change_amount" db:"change_amount"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+}
+
+// PaymentTender represents a tender offered when completing an order
+type PaymentTender struct {
+	PaymentMethod types.PaymentMethod `json:"payment_method" validate:"required,oneof=cash card qris transfer"`
+	Amount        types.DecimalText   `json:"amount" validate:"required,decimal_gt=0"`
+	Reference     *string             `json:"reference,omitempty" validate:"omitempty,max=100"`
+}
+
+// PaymentMethodTotal represents the net takings of one payment method
+type PaymentMethodTotal struct {
+	PaymentMethod types.PaymentMethod `json:"payment_method"`
+	TotalOrders   int                 `json:"total_orders"`
+	TotalAmount   types.DecimalText   `json:"total_amount"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// Promotion represents a discount rule. Promotions without a voucher code are
+// applied automatically to every order that qualifies.
+type Promotion struct {
+	ID             string                `json:"id" db:"id"`
+	Name           string                `json:"name" db:"name"`
+	Description    string                `json:"description,omitempty" db:"description"`
+	Type           types.PromotionType   `json:"promotion_type" db:"promotion_type"`
+	Value          types.DecimalText     `json:"value" db:"value"` // Percent for percentage promotions, amount for fixed_amount
+	BuyQuantity    *int                  `json:"buy_quantity,omitempty" db:"buy_quantity"`
+	GetQuantity    *int                  `json:"get_quantity,omitempty" db:"get_quantity"`
+	TargetType     types.PromotionTarget `json:"target_type" db:"target_type"`
+	TargetID       *string               `json:"target_id,omitempty" db:"target_id"` // Category or menu item ID
+	VoucherCode    *string               `json:"voucher_code,omitempty" db:"voucher_code"`
+	MinSpend       types.DecimalText     `json:"min_spend" db:"min_spend"`
+	StartsAt       *time.Time            `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt         *time.Time            `json:"ends_at,omitempty" db:"ends_at"`
+	DailyStartTime *string               `json:"daily_start_time,omitempty" db:"daily_start_time"` // HH:MM, e.g. happy hour
+	DailyEndTime   *string               `json:"daily_end_time,omitempty" db:"daily_end_time"`
+	UsageLimit     *int                  `json:"usage_limit,omitempty" db:"usage_limit"`
+	UsageCount     int                   `json:"usage_count" db:"usage_count"`
+	IsStackable    bool                  `json:"is_stackable" db:"is_stackable"`
+	Priority       int                   `json:"priority" db:"priority"`
+	IsActive       bool                  `json:"is_active" db:"is_active"`
+	CreatedBy      *string               `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// PromotionCreate represents data to create a new promotion
+type PromotionCreate struct {
+	Name           string                `json:"name" validate:"required,min=1,max=100"`
+	Description    string                `json:"description,omitempty" validate:"omitempty,max=500"`
+	Type           types.PromotionType   `json:"promotion_type" validate:"required,oneof=percentage fixed_amount buy_x_get_y"`
+	Value          types.DecimalText     `json:"value"`
+	BuyQuantity    *int                  `json:"buy_quantity,omitempty" validate:"omitempty,gt=0"`
+	GetQuantity    *int                  `json:"get_quantity,omitempty" validate:"omitempty,gt=0"`
+	TargetType     types.PromotionTarget `json:"target_type" validate:"omitempty,oneof=order category menu_item"`
+	TargetID       *string               `json:"target_id,omitempty" validate:"omitempty,uuid"`
+	VoucherCode    *string               `json:"voucher_code,omitempty" validate:"omitempty,min=3,max=50"`
+	MinSpend       types.DecimalText     `json:"min_spend"`
+	StartsAt       *time.Time            `json:"starts_at,omitempty"`
+	EndsAt         *time.Time            `json:"ends_at,omitempty"`
+	DailyStartTime *string               `json:"daily_start_time,omitempty"`
+	DailyEndTime   *string               `json:"daily_end_time,omitempty"`
+	UsageLimit     *int                  `json:"usage_limit,omitempty" validate:"omitempty,gt=0"`
+	IsStackable    bool                  `json:"is_stackable"`
+	Priority       int                   `json:"priority"`
+	IsActive       *bool                 `json:"is_active,omitempty"` // Defaults to true
+}
+
+// PromotionUpdate represents data to update an existing promotion
+type PromotionUpdate struct {
+	Name           *string                `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description    *string                `json:"description,omitempty" validate:"omitempty,max=500"`
+	Type           *types.PromotionType   `json:"promotion_type,omitempty" validate:"omitempty,oneof=percentage fixed_amount buy_x_get_y"`
+	Value          *types.DecimalText     `json:"value,omitempty"`
+	BuyQuantity    *int                   `json:"buy_quantity,omitempty" validate:"omitempty,gt=0"`
+	GetQuantity    *int                   `json:"get_quantity,omitempty" validate:"omitempty,gt=0"`
+	TargetType     *types.PromotionTarget `json:"target_type,omitempty" validate:"omitempty,oneof=order category menu_item"`
+	TargetID       *string                `json:"target_id,omitempty" validate:"omitempty,uuid"`
+	VoucherCode    *string                `json:"voucher_code,omitempty" validate:"omitempty,max=50"` // Empty string removes the code
+	MinSpend       *types.DecimalText     `json:"min_spend,omitempty"`
+	StartsAt       *time.Time             `json:"starts_at,omitempty"`
+	EndsAt         *time.Time             `json:"ends_at,omitempty"`
+	DailyStartTime *string                `json:"daily_start_time,omitempty"`
+	DailyEndTime   *string                `json:"daily_end_time,omitempty"`
+	UsageLimit     *int                   `json:"usage_limit,omitempty" validate:"omitempty,gt=0"`
+	IsStackable    *bool                  `json:"is_stackable,omitempty"`
+	Priority       *int                   `json:"priority,omitempty"`
+	IsActive       *bool                  `json:"is_active,omitempty"`
+}
+
+// PromotionFilter represents filter options for listing promotions
+type PromotionFilter struct {
+	IsActive *bool `json:"is_active,omitempty"`
+	Limit    int   `json:"limit"`
+	Offset   int   `json:"offset"`
+}
+
+// OrderPromotion records a promotion applied to an order and the discount it gave
+type OrderPromotion struct {
+	ID             string            `json:"id" db:"id"`
+	OrderID        string            `json:"order_id" db:"order_id"`
+	PromotionID    *string           `json:"promotion_id,omitempty" db:"promotion_id"` // Nil once the promotion is deleted
+	PromotionName  string            `json:"promotion_name" db:"promotion_name"`
+	DiscountAmount types.DecimalText `json:"discount_amount" db:"discount_amount"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+}
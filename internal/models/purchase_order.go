@@ -0,0 +1,159 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// Supplier represents a business the cafe buys stock from
+type Supplier struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	ContactName *string   `json:"contact_name,omitempty" db:"contact_name"`
+	Phone       *string   `json:"phone,omitempty" db:"phone"`
+	Email       *string   `json:"email,omitempty" db:"email"`
+	Address     *string   `json:"address,omitempty" db:"address"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SupplierCreate represents data to create a supplier
+type SupplierCreate struct {
+	Name        string  `json:"name" validate:"required,min=1,max=255"`
+	ContactName *string `json:"contact_name,omitempty" validate:"omitempty,max=255"`
+	Phone       *string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Email       *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Address     *string `json:"address,omitempty" validate:"omitempty,max=500"`
+	IsActive    *bool   `json:"is_active,omitempty"` // Defaults to true
+}
+
+// SupplierUpdate represents data to update a supplier
+type SupplierUpdate struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	ContactName *string `json:"contact_name,omitempty" validate:"omitempty,max=255"`
+	Phone       *string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Email       *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Address     *string `json:"address,omitempty" validate:"omitempty,max=500"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// SupplierFilter represents filter options for listing suppliers
+type SupplierFilter struct {
+	IsActive *bool `json:"is_active,omitempty"`
+	Limit    int   `json:"limit"`
+	Offset   int   `json:"offset"`
+}
+
+// PurchaseOrder represents stock ordered from a supplier. It is drafted, sent
+// to the supplier and then received in one or more deliveries.
+type PurchaseOrder struct {
+	ID           string                    `json:"id" db:"id"`
+	PONumber     string                    `json:"po_number" db:"po_number"`
+	SupplierID   string                    `json:"supplier_id" db:"supplier_id"`
+	SupplierName string                    `json:"supplier_name" db:"supplier_name"`
+	Status       types.PurchaseOrderStatus `json:"status" db:"status"`
+	ExpectedDate *time.Time                `json:"expected_date,omitempty" db:"expected_date"`
+	Notes        *string                   `json:"notes,omitempty" db:"notes"`
+	TotalAmount  types.DecimalText         `json:"total_amount" db:"total_amount"`
+	CreatedBy    string                    `json:"created_by" db:"created_by"`
+	SentAt       *time.Time                `json:"sent_at,omitempty" db:"sent_at"`
+	ReceivedAt   *time.Time                `json:"received_at,omitempty" db:"received_at"` // Set once everything ordered has arrived
+	CreatedAt    time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at" db:"updated_at"`
+	Items        []PurchaseOrderItem       `json:"items,omitempty"`
+	Receipts     []GoodsReceivedNote       `json:"goods_received_notes,omitempty"`
+}
+
+// PurchaseOrderItem represents a menu item bought in as finished goods or an
+// ingredient ordered on a purchase order. Quantities and the unit cost are in
+// the unit the line was ordered in.
+type PurchaseOrderItem struct {
+	ID               string            `json:"id" db:"id"`
+	PurchaseOrderID  string            `json:"purchase_order_id" db:"purchase_order_id"`
+	MenuItemID       *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID     *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName         string            `json:"item_name" db:"item_name"`
+	Unit             string            `json:"unit" db:"unit"`
+	Quantity         types.DecimalText `json:"quantity" db:"quantity"`
+	ReceivedQuantity types.DecimalText `json:"received_quantity" db:"received_quantity"`
+	UnitCost         types.DecimalText `json:"unit_cost" db:"unit_cost"`
+	LineTotal        types.DecimalText `json:"line_total"`
+}
+
+// PurchaseOrderCreate represents data to draft a purchase order
+type PurchaseOrderCreate struct {
+	SupplierID   string                   `json:"supplier_id" validate:"required,uuid"`
+	ExpectedDate *time.Time               `json:"expected_date,omitempty"`
+	Notes        *string                  `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Items        []PurchaseOrderItemInput `json:"items" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderUpdate represents data to update a draft purchase order. When
+// items are given they replace all lines of the order.
+type PurchaseOrderUpdate struct {
+	SupplierID   *string                  `json:"supplier_id,omitempty" validate:"omitempty,uuid"`
+	ExpectedDate *time.Time               `json:"expected_date,omitempty"`
+	Notes        *string                  `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Items        []PurchaseOrderItemInput `json:"items,omitempty" validate:"omitempty,min=1,dive"`
+}
+
+// PurchaseOrderItemInput represents one line of a purchase order, naming
+// either a menu item or an ingredient
+type PurchaseOrderItemInput struct {
+	MenuItemID   *string           `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,omitempty,uuid"`
+	IngredientID *string           `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Unit         *string           `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the purchase unit, else the stock unit
+	Quantity     types.DecimalText `json:"quantity" validate:"required,gt=0"`
+	UnitCost     types.DecimalText `json:"unit_cost"` // Per unit ordered
+}
+
+// PurchaseOrderFilter represents filter options for listing purchase orders
+type PurchaseOrderFilter struct {
+	SupplierID *string                    `json:"supplier_id,omitempty"`
+	Status     *types.PurchaseOrderStatus `json:"status,omitempty"`
+	Limit      int                        `json:"limit"`
+	Offset     int                        `json:"offset"`
+}
+
+// GoodsReceivedNote represents one delivery against a purchase order. The
+// value of the goods is booked as an expense.
+type GoodsReceivedNote struct {
+	ID              string                  `json:"id" db:"id"`
+	GRNNumber       string                  `json:"grn_number" db:"grn_number"`
+	PurchaseOrderID string                  `json:"purchase_order_id" db:"purchase_order_id"`
+	TotalAmount     types.DecimalText       `json:"total_amount" db:"total_amount"`
+	ExpenseID       *string                 `json:"expense_id,omitempty" db:"expense_id"`
+	Notes           *string                 `json:"notes,omitempty" db:"notes"`
+	ReceivedBy      string                  `json:"received_by" db:"received_by"`
+	ReceivedAt      time.Time               `json:"received_at" db:"received_at"`
+	Items           []GoodsReceivedNoteItem `json:"items"`
+}
+
+// GoodsReceivedNoteItem represents the quantity of a purchase order line that
+// arrived in a delivery, in the unit it was ordered in and in its stock unit
+type GoodsReceivedNoteItem struct {
+	ID                  string            `json:"id" db:"id"`
+	GoodsReceivedNoteID string            `json:"goods_received_note_id" db:"goods_received_note_id"`
+	PurchaseOrderItemID string            `json:"purchase_order_item_id" db:"purchase_order_item_id"`
+	Quantity            types.DecimalText `json:"quantity" db:"quantity"`
+	StockQuantity       types.DecimalText `json:"stock_quantity" db:"stock_quantity"`
+	UnitCost            types.DecimalText `json:"unit_cost" db:"unit_cost"`
+}
+
+// GoodsReceiptCreate represents data to receive a delivery against a purchase order
+type GoodsReceiptCreate struct {
+	Items []GoodsReceiptItemInput `json:"items" validate:"required,min=1,dive"`
+	Notes *string                 `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// GoodsReceiptItemInput represents the quantity of a purchase order line that
+// arrived, in the unit it was ordered in
+type GoodsReceiptItemInput struct {
+	PurchaseOrderItemID string             `json:"purchase_order_item_id" validate:"required,uuid"`
+	Quantity            types.DecimalText  `json:"quantity" validate:"required,gt=0"`
+	UnitCost            *types.DecimalText `json:"unit_cost,omitempty"` // Defaults to the ordered unit cost
+	LotNumber           *string            `json:"lot_number,omitempty" validate:"omitempty,max=100"`
+	ExpiresAt           *time.Time         `json:"expires_at,omitempty"`
+}
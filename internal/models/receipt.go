@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ReceiptPrint represents one print of an order's receipt. The first copy is the
+// original; later copies are reprints.
+type ReceiptPrint struct {
+	ID         string    `json:"id" db:"id"`
+	OrderID    string    `json:"order_id" db:"order_id"`
+	PrintedBy  string    `json:"printed_by" db:"printed_by"`
+	Format     string    `json:"format" db:"format"`
+	CopyNumber int       `json:"copy_number" db:"copy_number"`
+	PrintedAt  time.Time `json:"printed_at" db:"printed_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// Refund represents money returned for some or all lines of a completed order
+type Refund struct {
+	ID            string              `json:"id" db:"id"`
+	OrderID       string              `json:"order_id" db:"order_id"`
+	Amount        types.DecimalText   `json:"amount" db:"amount"`
+	TaxAmount     types.DecimalText   `json:"tax_amount" db:"tax_amount"` // Share of the order's tax included in Amount
+	PaymentMethod types.PaymentMethod `json:"payment_method" db:"payment_method"`
+	Reference     *string             `json:"reference,omitempty" db:"reference"`
+	Reason        string              `json:"reason" db:"reason"`
+	Restocked     bool                `json:"restocked" db:"restocked"`
+	RefundedBy    string              `json:"refunded_by" db:"refunded_by"`
+	ApprovedBy    *string             `json:"approved_by,omitempty" db:"approved_by"`
+	ShiftID       *string             `json:"shift_id,omitempty" db:"shift_id"` // Cash drawer shift the refund was paid in
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	Items         []RefundItem        `json:"items"`
+}
+
+// RefundItem represents a returned quantity of one order line
+type RefundItem struct {
+	ID          string            `json:"id" db:"id"`
+	RefundID    string            `json:"refund_id" db:"refund_id"`
+	OrderItemID string            `json:"order_item_id" db:"order_item_id"`
+	MenuItemID  string            `json:"menu_item_id" db:"menu_item_id"`
+	Quantity    int               `json:"quantity" db:"quantity"`
+	Amount      types.DecimalText `json:"amount" db:"amount"`
+}
+
+// RefundCreate represents data to refund lines of a completed order
+type RefundCreate struct {
+	Items         []RefundItemCreate  `json:"items" validate:"required,min=1,dive"`
+	PaymentMethod types.PaymentMethod `json:"payment_method" validate:"required,oneof=cash card qris transfer"`
+	Reference     *string             `json:"reference,omitempty" validate:"omitempty,max=100"`
+	Reason        string              `json:"reason" validate:"required,max=255"`
+	Restock       *bool               `json:"restock,omitempty"` // Defaults to true; false for items that cannot be resold
+	// Manager credentials authorizing a refund above the approval threshold
+	ManagerUsername *string `json:"manager_username,omitempty"`
+	ManagerPassword *string `json:"manager_password,omitempty"`
+}
+
+// RefundItemCreate represents a quantity of an order line to refund
+type RefundItemCreate struct {
+	OrderItemID string `json:"order_item_id" validate:"required,uuid"`
+	Quantity    int    `json:"quantity" validate:"required,gt=0"`
+}
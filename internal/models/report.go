@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// SalesReport represents an X or Z report covering everything since the
+// previous Z report. Only Z reports are stored; they carry an ID and a number.
+type SalesReport struct {
+	ID               string                `json:"id,omitempty" db:"id"`
+	ReportType       types.SalesReportType `json:"report_type"`
+	ReportNumber     *int                  `json:"report_number,omitempty" db:"report_number"`
+	PeriodStart      *time.Time            `json:"period_start,omitempty" db:"period_start"` // Not set before the first Z report
+	PeriodEnd        time.Time             `json:"period_end" db:"period_end"`
+	GeneratedBy      string                `json:"generated_by" db:"generated_by"`
+	GeneratedAt      time.Time             `json:"generated_at" db:"generated_at"`
+	OrderCount       int                   `json:"order_count" db:"order_count"`
+	GrossSales       types.DecimalText     `json:"gross_sales" db:"gross_sales"` // Item subtotal before discounts
+	DiscountAmount   types.DecimalText     `json:"discount_amount" db:"discount_amount"`
+	ServiceCharge    types.DecimalText     `json:"service_charge_amount" db:"service_charge_amount"`
+	TaxAmount        types.DecimalText     `json:"tax_amount" db:"tax_amount"`
+	RoundingAmount   types.DecimalText     `json:"rounding_amount" db:"rounding_amount"`
+	TotalSales       types.DecimalText     `json:"total_sales" db:"total_sales"`
+	RefundCount      int                   `json:"refund_count" db:"refund_count"`
+	RefundAmount     types.DecimalText     `json:"refund_amount" db:"refund_amount"`
+	RefundTax        types.DecimalText     `json:"refund_tax" db:"refund_tax"`
+	CancelledCount   int                   `json:"cancelled_count" db:"cancelled_count"`
+	VoidedCount      int                   `json:"voided_count" db:"voided_count"` // Cancelled after payment
+	VoidedAmount     types.DecimalText     `json:"voided_amount" db:"voided_amount"`
+	EarlierVoidedNet types.DecimalText     `json:"-"`                        // Voided sales completed in an earlier period, net of tax; not stored
+	NetSales         types.DecimalText     `json:"net_sales" db:"net_sales"` // Total sales less tax, refunds and earlier sales voided, net of their tax
+	FirstOrderNumber *string               `json:"first_order_number,omitempty" db:"first_order_number"`
+	LastOrderNumber  *string               `json:"last_order_number,omitempty" db:"last_order_number"`
+	PaymentMethods   []PaymentMethodTotal  `json:"payment_methods"`
+	Cashiers         []CashierTotal        `json:"cashiers"`
+}
+
+// CashierTotal represents the completed sales of one cashier in a report
+type CashierTotal struct {
+	UserID      string            `json:"user_id" db:"user_id"`
+	CashierName string            `json:"cashier_name" db:"cashier_name"`
+	OrderCount  int               `json:"order_count" db:"order_count"`
+	TotalSales  types.DecimalText `json:"total_sales" db:"total_sales"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// CashShift represents a cashier's shift on the cash drawer, from the opening
+// float to the counted close-out
+type CashShift struct {
+	ID           string             `json:"id" db:"id"`
+	UserID       string             `json:"user_id" db:"user_id"`
+	Status       types.ShiftStatus  `json:"status" db:"status"`
+	OpeningFloat types.DecimalText  `json:"opening_float" db:"opening_float"`
+	ExpectedCash *types.DecimalText `json:"expected_cash,omitempty" db:"expected_cash"` // Set at close-out
+	CountedCash  *types.DecimalText `json:"counted_cash,omitempty" db:"counted_cash"`
+	Variance     *types.DecimalText `json:"variance,omitempty" db:"variance"` // Counted minus expected; negative when cash is short
+	Notes        *string            `json:"notes,omitempty" db:"notes"`
+	OpenedAt     time.Time          `json:"opened_at" db:"opened_at"`
+	ClosedAt     *time.Time         `json:"closed_at,omitempty" db:"closed_at"`
+	ClosedBy     *string            `json:"closed_by,omitempty" db:"closed_by"`
+}
+
+// CashMovement represents cash taken out of the drawer during a shift
+type CashMovement struct {
+	ID           string                 `json:"id" db:"id"`
+	ShiftID      string                 `json:"shift_id" db:"shift_id"`
+	MovementType types.CashMovementType `json:"movement_type" db:"movement_type"`
+	Amount       types.DecimalText      `json:"amount" db:"amount"`
+	Reason       string                 `json:"reason" db:"reason"`
+	CreatedBy    string                 `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
+}
+
+// CashCount represents the number of notes or coins of one denomination
+// counted in the drawer at close-out
+type CashCount struct {
+	Denomination types.DecimalText `json:"denomination" db:"denomination"` // e.g. 100000 or 500
+	Quantity     int               `json:"quantity" db:"quantity" validate:"gte=0"`
+}
+
+// CashShiftOpen represents data to open a shift
+type CashShiftOpen struct {
+	OpeningFloat types.DecimalText `json:"opening_float"`
+}
+
+// CashMovementCreate represents data to record a cash drop or payout
+type CashMovementCreate struct {
+	MovementType types.CashMovementType `json:"movement_type" validate:"required,oneof=drop payout"`
+	Amount       types.DecimalText      `json:"amount"`
+	Reason       string                 `json:"reason" validate:"required,max=255"`
+}
+
+// CashShiftClose represents the counted drawer used to close a shift
+type CashShiftClose struct {
+	Counts []CashCount `json:"counts" validate:"required,min=1,dive"`
+	Notes  *string     `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// CashShiftFilter represents filter options for listing shifts
+type CashShiftFilter struct {
+	UserID *string            `json:"user_id,omitempty"`
+	Status *types.ShiftStatus `json:"status,omitempty"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// CashShiftSummary represents a shift with the cash it should hold: the float
+// plus cash sales, less cash refunds and voids, drops and payouts. For an open
+// shift the expected cash is worked out as of now.
+type CashShiftSummary struct {
+	CashShift
+	CashOrders  int               `json:"cash_orders"`
+	CashSales   types.DecimalText `json:"cash_sales"`
+	CashRefunds types.DecimalText `json:"cash_refunds"`
+	CashVoids   types.DecimalText `json:"cash_voids"`
+	CashDrops   types.DecimalText `json:"cash_drops"`
+	CashPayouts types.DecimalText `json:"cash_payouts"`
+	CurrentCash types.DecimalText `json:"current_expected_cash"`
+	Movements   []CashMovement    `json:"movements"`
+	Counts      []CashCount       `json:"counts"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// StockLocation represents a place stock is kept in, such as a store room, a
+// bar fridge or another outlet's warehouse. Sales are made from the one
+// selling location.
+type StockLocation struct {
+	ID          string    `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	IsSelling   bool      `json:"is_selling" db:"is_selling"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StockLocationCreate represents data to add a stock location
+type StockLocationCreate struct {
+	Code        string  `json:"code" validate:"required,min=1,max=20"`
+	Name        string  `json:"name" validate:"required,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	IsSelling   bool    `json:"is_selling"` // Takes over selling from the current selling location
+}
+
+// StockLocationUpdate represents data to update a stock location
+type StockLocationUpdate struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	IsSelling   *bool   `json:"is_selling,omitempty"` // Only true is accepted; make another location the selling one instead
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// StockBalance represents the stock of a menu item or an ingredient held at a
+// location, in its stock unit
+type StockBalance struct {
+	LocationID   string            `json:"location_id" db:"location_id"`
+	MenuItemID   *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName     string            `json:"item_name" db:"item_name"`
+	ItemType     string            `json:"item_type" db:"item_type"` // menu_item or ingredient
+	Unit         string            `json:"unit" db:"unit"`
+	Quantity     types.DecimalText `json:"quantity" db:"quantity"`
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// StockTransfer represents stock moved from one location to another. It is
+// posted when it is made, as an `out` stock transaction at the location the
+// stock leaves and an `in` one at the location it arrives at.
+type StockTransfer struct {
+	ID               string    `json:"id" db:"id"`
+	TransferNumber   string    `json:"transfer_number" db:"transfer_number"`
+	FromLocationID   string    `json:"from_location_id" db:"from_location_id"`
+	FromLocationName string    `json:"from_location_name" db:"from_location_name"`
+	ToLocationID     string    `json:"to_location_id" db:"to_location_id"`
+	ToLocationName   string    `json:"to_location_name" db:"to_location_name"`
+	Notes            *string   `json:"notes,omitempty" db:"notes"`
+	CreatedBy        string    `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// StockTransferLine represents the quantity of one item moved, in its stock unit
+type StockTransferLine struct {
+	ID              string            `json:"id" db:"id"`
+	StockTransferID string            `json:"stock_transfer_id" db:"stock_transfer_id"`
+	MenuItemID      *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID    *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName        string            `json:"item_name" db:"item_name"`
+	Unit            string            `json:"unit" db:"unit"`
+	Quantity        types.DecimalText `json:"quantity" db:"quantity"`
+}
+
+// StockTransferSummary represents a stock transfer with its lines
+type StockTransferSummary struct {
+	StockTransfer
+	Lines []StockTransferLine `json:"lines"`
+}
+
+// StockTransferItemInput represents the quantity of one item to move, naming
+// either a menu item stocked as finished goods or an ingredient. The quantity
+// may be given in any unit compatible with the stock unit.
+type StockTransferItemInput struct {
+	MenuItemID   *string           `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,omitempty,uuid"`
+	IngredientID *string           `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Quantity     types.DecimalText `json:"quantity" validate:"required"`
+	Unit         *string           `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+}
+
+// StockTransferCreate represents data to move stock between locations
+type StockTransferCreate struct {
+	FromLocationID string                   `json:"from_location_id" validate:"required,uuid"`
+	ToLocationID   string                   `json:"to_location_id" validate:"required,uuid"`
+	Items          []StockTransferItemInput `json:"items" validate:"required,min=1,dive"`
+	Notes          *string                  `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// StockTransferFilter represents filter options for listing stock transfers
+type StockTransferFilter struct {
+	LocationID *string `json:"location_id,omitempty"` // Transfers out of or into the location
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// StockLot represents a quantity of a menu item or ingredient received at one
+// time, counted in its stock unit. Stock is taken out of lots first-expiring
+// first, then first-received first.
+type StockLot struct {
+	ID                  string            `json:"id" db:"id"`
+	MenuItemID          *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID        *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName            string            `json:"item_name" db:"item_name"`
+	LotNumber           *string           `json:"lot_number,omitempty" db:"lot_number"`
+	Unit                string            `json:"unit" db:"unit"`
+	QuantityReceived    types.DecimalText `json:"quantity_received" db:"quantity_received"`
+	QuantityRemaining   types.DecimalText `json:"quantity_remaining" db:"quantity_remaining"`
+	UnitCost            types.DecimalText `json:"unit_cost" db:"unit_cost"` // Per stock unit
+	ReceivedAt          time.Time         `json:"received_at" db:"received_at"`
+	ExpiresAt           *time.Time        `json:"expires_at,omitempty" db:"expires_at"`
+	GoodsReceivedNoteID *string           `json:"goods_received_note_id,omitempty" db:"goods_received_note_id"`
+	ReceivedBy          *string           `json:"received_by,omitempty" db:"received_by"`
+}
+
+// StockLotFilter represents filter options for listing stock lots
+type StockLotFilter struct {
+	MenuItemID   *string `json:"menu_item_id,omitempty"`
+	IngredientID *string `json:"ingredient_id,omitempty"`
+	Limit        int     `json:"limit"`
+	Offset       int     `json:"offset"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// StockTake represents a physical count of the shelf, reconciled against the
+// stock the system held when it started. It covers every item, or is a cycle
+// count of one menu category or of menu items or ingredients only.
+type StockTake struct {
+	ID                 string                `json:"id" db:"id"`
+	StockTakeNumber    string                `json:"stock_take_number" db:"stock_take_number"`
+	Status             types.StockTakeStatus `json:"status" db:"status"`
+	CategoryID         *string               `json:"category_id,omitempty" db:"category_id"`
+	IncludeMenuItems   bool                  `json:"include_menu_items" db:"include_menu_items"`
+	IncludeIngredients bool                  `json:"include_ingredients" db:"include_ingredients"`
+	Notes              *string               `json:"notes,omitempty" db:"notes"`
+	CreatedBy          string                `json:"created_by" db:"created_by"`
+	ApprovedBy         *string               `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt         *time.Time            `json:"approved_at,omitempty" db:"approved_at"`
+	CreatedAt          time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// StockTakeLine represents one item of a stock take: the system quantity and
+// cost when the stock take started and the quantity counted, in the stock unit
+type StockTakeLine struct {
+	ID              string             `json:"id" db:"id"`
+	StockTakeID     string             `json:"stock_take_id" db:"stock_take_id"`
+	MenuItemID      *string            `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID    *string            `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName        string             `json:"item_name" db:"item_name"`
+	Unit            string             `json:"unit" db:"unit"`
+	SystemQuantity  types.DecimalText  `json:"system_quantity" db:"system_quantity"`
+	CountedQuantity *types.DecimalText `json:"counted_quantity,omitempty" db:"counted_quantity"` // Not set until the item is counted
+	UnitCost        types.DecimalText  `json:"unit_cost" db:"unit_cost"`
+	Variance        *types.DecimalText `json:"variance,omitempty"`       // Counted minus system; negative when stock is missing
+	VarianceValue   *types.DecimalText `json:"variance_value,omitempty"` // Variance at unit cost
+	CountedBy       *string            `json:"counted_by,omitempty" db:"counted_by"`
+	CountedAt       *time.Time         `json:"counted_at,omitempty" db:"counted_at"`
+}
+
+// StockTakeSummary represents a stock take with its lines and the variances
+// counted so far
+type StockTakeSummary struct {
+	StockTake
+	TotalLines    int               `json:"total_lines"`
+	CountedLines  int               `json:"counted_lines"`
+	VarianceValue types.DecimalText `json:"variance_value"` // Net value of all variances counted
+	Lines         []StockTakeLine   `json:"lines"`
+}
+
+// StockTakeCreate represents data to start a stock take. Without a category
+// every item is counted; with one only the menu items of that category are.
+type StockTakeCreate struct {
+	CategoryID         *string `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	IncludeMenuItems   *bool   `json:"include_menu_items,omitempty"`  // Defaults to true
+	IncludeIngredients *bool   `json:"include_ingredients,omitempty"` // Defaults to true without a category
+	Notes              *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// StockTakeCountInput represents the quantity of one item counted, naming
+// either a menu item or an ingredient. The quantity may be given in any unit
+// compatible with the stock unit.
+type StockTakeCountInput struct {
+	MenuItemID   *string            `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,omitempty,uuid"`
+	IngredientID *string            `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Quantity     *types.DecimalText `json:"quantity" validate:"required"`                     // Zero when none is left
+	Unit         *string            `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+}
+
+// StockTakeCounts represents counted quantities entered in bulk, each
+// replacing what was counted for the item before
+type StockTakeCounts struct {
+	Counts []StockTakeCountInput `json:"counts" validate:"required,min=1,dive"`
+}
+
+// StockTakeScan represents one scan of an item's label, adding to what was
+// counted for it so far
+type StockTakeScan struct {
+	MenuItemID   *string            `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,omitempty,uuid"`
+	IngredientID *string            `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Quantity     *types.DecimalText `json:"quantity,omitempty"`                               // Defaults to 1
+	Unit         *string            `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+}
+
+// StockTakeFilter represents filter options for listing stock takes
+type StockTakeFilter struct {
+	Status *types.StockTakeStatus `json:"status,omitempty"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
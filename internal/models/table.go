@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// FloorArea represents a part of the cafe that tables are grouped by, such as
+// "Indoor" or "Terrace"
+type FloorArea struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	SortOrder int       `json:"sort_order" db:"sort_order"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FloorAreaCreate represents data to create a floor area
+type FloorAreaCreate struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	SortOrder int    `json:"sort_order,omitempty"`
+	IsActive  *bool  `json:"is_active,omitempty"`
+}
+
+// FloorAreaUpdate represents data to update a floor area
+type FloorAreaUpdate struct {
+	Name      *string `json:"name,omitempty" validate:"omitempty,max=100"`
+	SortOrder *int    `json:"sort_order,omitempty"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+}
+
+// DiningTable represents a table guests can be seated at
+type DiningTable struct {
+	ID          string    `json:"id" db:"id"`
+	FloorAreaID string    `json:"floor_area_id" db:"floor_area_id"`
+	Name        string    `json:"name" db:"name"` // Unique within the floor area, e.g. "T4"
+	Seats       int       `json:"seats" db:"seats"`
+	SortOrder   int       `json:"sort_order" db:"sort_order"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DiningTableCreate represents data to create a dining table
+type DiningTableCreate struct {
+	FloorAreaID string `json:"floor_area_id" validate:"required,uuid"`
+	Name        string `json:"name" validate:"required,max=50"`
+	Seats       *int   `json:"seats,omitempty" validate:"omitempty,gt=0"`
+	SortOrder   int    `json:"sort_order,omitempty"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+// DiningTableUpdate represents data to update a dining table
+type DiningTableUpdate struct {
+	FloorAreaID *string `json:"floor_area_id,omitempty" validate:"omitempty,uuid"`
+	Name        *string `json:"name,omitempty" validate:"omitempty,max=50"`
+	Seats       *int    `json:"seats,omitempty" validate:"omitempty,gt=0"`
+	SortOrder   *int    `json:"sort_order,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// DiningTableFilter represents filter options for listing dining tables
+type DiningTableFilter struct {
+	FloorAreaID *string `json:"floor_area_id,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// TableOrder represents an order open on a table
+type TableOrder struct {
+	ID          string            `json:"id"`
+	OrderNumber string            `json:"order_number"`
+	TableID     string            `json:"table_id"`
+	Status      types.OrderStatus `json:"status"`
+	GuestCount  *int              `json:"guest_count,omitempty"`
+	TotalAmount types.DecimalText `json:"total_amount"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// TableStatus represents a table on the floor plan with the orders open on it
+type TableStatus struct {
+	DiningTable
+	Occupied   bool         `json:"occupied"`
+	GuestCount int          `json:"guest_count"` // Guests across the open orders
+	OpenOrders []TableOrder `json:"open_orders"`
+}
+
+// FloorAreaWithTables represents a floor area on the floor plan
+type FloorAreaWithTables struct {
+	FloorArea
+	Tables []TableStatus `json:"tables"`
+}
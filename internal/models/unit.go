@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// UnitOfMeasure represents a unit stock can be counted in. Units of the same
+// dimension convert into each other through their factors, e.g. 1 kg is
+// 1000 g because kg has a factor of 1000 and g a factor of 1.
+type UnitOfMeasure struct {
+	Code      string            `json:"code" db:"code"`
+	Name      string            `json:"name" db:"name"`
+	Dimension string            `json:"dimension" db:"dimension"`
+	Factor    types.DecimalText `json:"factor" db:"factor"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// UnitOfMeasureCreate represents data to register a unit of measure
+type UnitOfMeasureCreate struct {
+	Code      string            `json:"code" validate:"required,min=1,max=50"`
+	Name      string            `json:"name" validate:"required,min=1,max=100"`
+	Dimension string            `json:"dimension" validate:"required,min=1,max=50"`
+	Factor    types.DecimalText `json:"factor" validate:"required,gt=0"`
+}
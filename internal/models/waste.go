@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+)
+
+// WasteRecord represents stock written off as waste, valued at what it cost
+type WasteRecord struct {
+	ID           string            `json:"id" db:"id"`
+	MenuItemID   *string           `json:"menu_item_id,omitempty" db:"menu_item_id"`
+	IngredientID *string           `json:"ingredient_id,omitempty" db:"ingredient_id"`
+	ItemName     string            `json:"item_name" db:"item_name"`
+	Reason       types.WasteReason `json:"reason" db:"reason"`
+	Quantity     types.DecimalText `json:"quantity" db:"quantity"`
+	Unit         string            `json:"unit" db:"unit"`
+	UnitCost     types.DecimalText `json:"unit_cost" db:"unit_cost"`
+	TotalCost    types.DecimalText `json:"total_cost" db:"total_cost"`
+	Notes        *string           `json:"notes,omitempty" db:"notes"`
+	UserID       string            `json:"user_id" db:"user_id"`
+	Username     string            `json:"username,omitempty" db:"username"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+}
+
+// WasteCreate represents data to write stock off as waste. A menu item made
+// from a recipe is wasted in portions and uses up its ingredients; anything
+// else may be given in any unit compatible with its stock unit.
+type WasteCreate struct {
+	MenuItemID   *string           `json:"menu_item_id,omitempty" validate:"required_without=IngredientID,omitempty,uuid"`
+	IngredientID *string           `json:"ingredient_id,omitempty" validate:"omitempty,uuid"`
+	Reason       types.WasteReason `json:"reason" validate:"required,oneof=expired damaged staff_meal comp"`
+	Quantity     types.DecimalText `json:"quantity" validate:"required"`
+	Unit         *string           `json:"unit,omitempty" validate:"omitempty,min=1,max=50"` // Defaults to the stock unit
+	Notes        *string           `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// WasteFilter represents filter options for listing waste records
+type WasteFilter struct {
+	Reason *types.WasteReason `json:"reason,omitempty"`
+	UserID *string            `json:"user_id,omitempty"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// EmailChannel sends low stock alerts by email over SMTP
+type EmailChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailChannel creates an email channel. It authenticates with the server
+// only when a username is given.
+func NewEmailChannel(host string, port int, username, password, from string, to []string) *EmailChannel {
+	return &EmailChannel{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+// Name implements Channel
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+// Send implements Channel. net/smtp takes no context, so the context is not
+// honoured once the message is being sent.
+func (c *EmailChannel) Send(ctx context.Context, alert *models.LowStockAlert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if c.username != "" {
+		auth = smtp.PlainAuth("", c.username, c.password, c.host)
+	}
+
+	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	if err := smtp.SendMail(addr, auth, c.from, c.to, c.message(alert)); err != nil {
+		return fmt.Errorf("failed to send low stock alert email: %w", err)
+	}
+
+	return nil
+}
+
+// message builds the email for a low stock alert
+func (c *EmailChannel) message(alert *models.LowStockAlert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", Subject(alert)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(Body(alert), "\n", "\r\n"))
+	return []byte(b.String())
+}
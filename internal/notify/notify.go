@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// Channel delivers low stock alerts outside the app. The in-app feed needs no
+// channel: it reads the alerts straight from the database.
+type Channel interface {
+	// Name identifies the channel in the deliveries recorded for an alert, so
+	// it must not change between releases
+	Name() string
+	Send(ctx context.Context, alert *models.LowStockAlert) error
+}
+
+// Subject is the one-line summary of a low stock alert. Line breaks in the
+// item name are dropped so it stays on one line, e.g. as an email header.
+func Subject(alert *models.LowStockAlert) string {
+	itemName := strings.NewReplacer("\r", " ", "\n", " ").Replace(alert.ItemName)
+	return fmt.Sprintf("Low stock: %s", itemName)
+}
+
+// Body describes a low stock alert in plain text
+func Body(alert *models.LowStockAlert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is running low.\n\n", alert.ItemName)
+	fmt.Fprintf(&b, "Current stock: %s\n", quantity(alert.CurrentStock.String(), alert.Unit))
+	fmt.Fprintf(&b, "Minimum stock: %s\n", quantity(alert.MinimumStock.String(), alert.Unit))
+	fmt.Fprintf(&b, "Raised at: %s\n", alert.RaisedAt.Format("2006-01-02 15:04"))
+	return b.String()
+}
+
+// quantity formats a stock quantity with its unit, if it has one
+func quantity(amount, unit string) string {
+	if unit == "" {
+		return amount
+	}
+	return amount + " " + unit
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// EventLowStock is the event type of the webhook payload
+const EventLowStock = "low_stock"
+
+// SignatureHeader carries the hex HMAC-SHA256 of the webhook body, keyed with
+// the webhook secret, so receivers can check the request came from this server
+const SignatureHeader = "X-POS-Signature"
+
+// webhookTimeout bounds a single webhook call; a failed call is retried later
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body posted to the webhook
+type WebhookPayload struct {
+	Event   string                `json:"event"`
+	Subject string                `json:"subject"`
+	Text    string                `json:"text"`
+	Alert   *models.LowStockAlert `json:"alert"`
+}
+
+// WebhookChannel posts low stock alerts as JSON to a URL
+type WebhookChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookChannel creates a webhook channel. Bodies are signed when a secret
+// is given.
+func NewWebhookChannel(url, secret string) *WebhookChannel {
+	return &WebhookChannel{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Name implements Channel
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// Send implements Channel. Any response other than 2xx is a failure.
+func (c *WebhookChannel) Send(ctx context.Context, alert *models.LowStockAlert) error {
+	body, err := json.Marshal(WebhookPayload{
+		Event:   EventLowStock,
+		Subject: Subject(alert),
+		Text:    Body(alert),
+		Alert:   alert,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode low stock alert webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create low stock alert webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(c.secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call low stock alert webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("low stock alert webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of a webhook body keyed with the secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,87 @@
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ModifierGroup is a set of options offered with a menu item, such as sizes or
+// add-ons, and how many of them a customer must and may choose
+type ModifierGroup struct {
+	ID        string
+	Name      string
+	MinSelect int
+	MaxSelect int
+	Options   []ModifierOption
+}
+
+// ModifierOption is one choice in a modifier group and what it adds to the
+// line's unit price
+type ModifierOption struct {
+	ID          string
+	Name        string
+	PriceDelta  decimal.Decimal
+	IsAvailable bool
+}
+
+// SelectedModifier is an option chosen for an order line
+type SelectedModifier struct {
+	GroupName  string
+	OptionID   string
+	OptionName string
+	PriceDelta decimal.Decimal
+}
+
+// SelectModifiers checks the chosen option IDs against the groups offered with
+// a menu item and returns the selected modifiers, in group order, with the total
+// they add to the unit price. Every option must belong to one of the groups and
+// be available, and each group's min/max selection rule must hold.
+func SelectModifiers(groups []ModifierGroup, optionIDs []string) ([]SelectedModifier, decimal.Decimal, error) {
+	chosen := make(map[string]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if chosen[optionID] {
+			return nil, decimal.Zero, fmt.Errorf("modifier option %s is selected more than once", optionID)
+		}
+		chosen[optionID] = true
+	}
+
+	var selected []SelectedModifier
+	delta := decimal.Zero
+	for _, group := range groups {
+		count := 0
+		for _, option := range group.Options {
+			if !chosen[option.ID] {
+				continue
+			}
+			delete(chosen, option.ID)
+
+			if !option.IsAvailable {
+				return nil, decimal.Zero, fmt.Errorf("modifier option is not available: %s", option.Name)
+			}
+
+			selected = append(selected, SelectedModifier{
+				GroupName:  group.Name,
+				OptionID:   option.ID,
+				OptionName: option.Name,
+				PriceDelta: option.PriceDelta,
+			})
+			delta = delta.Add(option.PriceDelta)
+			count++
+		}
+
+		if count < group.MinSelect {
+			return nil, decimal.Zero, fmt.Errorf("choose at least %d from %s", group.MinSelect, group.Name)
+		}
+		if count > group.MaxSelect {
+			return nil, decimal.Zero, fmt.Errorf("choose at most %d from %s", group.MaxSelect, group.Name)
+		}
+	}
+
+	// Anything left over is not offered with this menu item
+	for optionID := range chosen {
+		return nil, decimal.Zero, fmt.Errorf("modifier option %s is not offered with this menu item", optionID)
+	}
+
+	return selected, delta, nil
+}
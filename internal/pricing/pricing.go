@@ -0,0 +1,191 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode controls how the payable total is rounded to the configured increment
+type RoundingMode string
+
+const (
+	RoundingNearest RoundingMode = "nearest"
+	RoundingUp      RoundingMode = "up"
+	RoundingDown    RoundingMode = "down"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Rules holds the tax, service charge and rounding settings applied at checkout.
+// Rates are percentages, e.g. 11 for 11% PPN.
+type Rules struct {
+	TaxRate           decimal.Decimal
+	ServiceChargeRate decimal.Decimal
+	// RoundingIncrement is the smallest payable unit, e.g. 100 for Rp100; zero disables rounding
+	RoundingIncrement decimal.Decimal
+	RoundingMode      RoundingMode
+}
+
+// Discount is either a percentage of the subtotal or a fixed amount, never both.
+// Promotion is the discount already given by promotions; a manual percentage
+// discount is taken from what is left after it.
+type Discount struct {
+	Percent   decimal.Decimal
+	Amount    decimal.Decimal
+	Promotion decimal.Decimal
+}
+
+// Breakdown is the result of pricing an order.
+// Total = Subtotal - Discount + ServiceCharge + Tax + Rounding
+type Breakdown struct {
+	Subtotal          decimal.Decimal
+	Discount          decimal.Decimal
+	ServiceCharge     decimal.Decimal
+	ServiceChargeRate decimal.Decimal
+	Tax               decimal.Decimal
+	TaxRate           decimal.Decimal
+	Rounding          decimal.Decimal
+	Total             decimal.Decimal
+}
+
+// ParseRules builds Rules from their textual configuration values
+func ParseRules(taxRate, serviceChargeRate, roundingIncrement, roundingMode string) (Rules, error) {
+	var rules Rules
+	var err error
+
+	if rules.TaxRate, err = parseAmount(taxRate); err != nil {
+		return Rules{}, fmt.Errorf("invalid tax rate: %v", err)
+	}
+	if rules.ServiceChargeRate, err = parseAmount(serviceChargeRate); err != nil {
+		return Rules{}, fmt.Errorf("invalid service charge rate: %v", err)
+	}
+	if rules.RoundingIncrement, err = parseAmount(roundingIncrement); err != nil {
+		return Rules{}, fmt.Errorf("invalid rounding increment: %v", err)
+	}
+
+	rules.RoundingMode = RoundingMode(strings.ToLower(strings.TrimSpace(roundingMode)))
+	if rules.RoundingMode == "" {
+		rules.RoundingMode = RoundingNearest
+	}
+
+	return rules, rules.Validate()
+}
+
+// Validate checks that the rules are usable
+func (r Rules) Validate() error {
+	if r.TaxRate.IsNegative() || r.TaxRate.GreaterThan(hundred) {
+		return errors.New("tax rate must be between 0 and 100")
+	}
+	if r.ServiceChargeRate.IsNegative() || r.ServiceChargeRate.GreaterThan(hundred) {
+		return errors.New("service charge rate must be between 0 and 100")
+	}
+	if r.RoundingIncrement.IsNegative() {
+		return errors.New("rounding increment cannot be negative")
+	}
+
+	switch r.RoundingMode {
+	case RoundingNearest, RoundingUp, RoundingDown, "":
+	default:
+		return fmt.Errorf("unknown rounding mode %q", r.RoundingMode)
+	}
+
+	return nil
+}
+
+// Apply prices an order. The discount comes off the subtotal first, the service
+// charge is levied on the discounted amount and tax on the discounted amount plus
+// service charge. The resulting total is then rounded to the configured increment.
+func (r Rules) Apply(subtotal decimal.Decimal, discount Discount) (*Breakdown, error) {
+	if subtotal.IsNegative() {
+		return nil, errors.New("subtotal cannot be negative")
+	}
+
+	discountAmount, err := discount.amountOf(subtotal)
+	if err != nil {
+		return nil, err
+	}
+
+	net := subtotal.Sub(discountAmount)
+	serviceCharge := percentOf(net, r.ServiceChargeRate)
+	tax := percentOf(net.Add(serviceCharge), r.TaxRate)
+
+	unrounded := net.Add(serviceCharge).Add(tax)
+	total := r.round(unrounded)
+
+	return &Breakdown{
+		Subtotal:          subtotal,
+		Discount:          discountAmount,
+		ServiceCharge:     serviceCharge,
+		ServiceChargeRate: r.ServiceChargeRate,
+		Tax:               tax,
+		TaxRate:           r.TaxRate,
+		Rounding:          total.Sub(unrounded),
+		Total:             total,
+	}, nil
+}
+
+// round rounds amount to the configured increment
+func (r Rules) round(amount decimal.Decimal) decimal.Decimal {
+	if !r.RoundingIncrement.IsPositive() {
+		return amount
+	}
+
+	units := amount.Div(r.RoundingIncrement)
+	switch r.RoundingMode {
+	case RoundingUp:
+		units = units.Ceil()
+	case RoundingDown:
+		units = units.Floor()
+	default:
+		units = units.Round(0)
+	}
+
+	return units.Mul(r.RoundingIncrement).Round(2)
+}
+
+// amountOf resolves the discount to an amount for the given subtotal
+func (d Discount) amountOf(subtotal decimal.Decimal) (decimal.Decimal, error) {
+	if d.Percent.IsNegative() || d.Amount.IsNegative() || d.Promotion.IsNegative() {
+		return decimal.Zero, errors.New("discount cannot be negative")
+	}
+	if !d.Percent.IsZero() && !d.Amount.IsZero() {
+		return decimal.Zero, errors.New("discount must be either a percentage or an amount, not both")
+	}
+	if d.Percent.GreaterThan(hundred) {
+		return decimal.Zero, errors.New("discount percentage cannot exceed 100")
+	}
+
+	promotion := d.Promotion.Round(2)
+	if promotion.GreaterThan(subtotal) {
+		return decimal.Zero, errors.New("promotion discount cannot exceed the order subtotal")
+	}
+
+	amount := d.Amount.Round(2)
+	if !d.Percent.IsZero() {
+		amount = percentOf(subtotal.Sub(promotion), d.Percent)
+	}
+	amount = amount.Add(promotion)
+
+	if amount.GreaterThan(subtotal) {
+		return decimal.Zero, errors.New("discount cannot exceed the order subtotal")
+	}
+
+	return amount, nil
+}
+
+// percentOf returns rate percent of amount, rounded to whole cents
+func percentOf(amount, rate decimal.Decimal) decimal.Decimal {
+	return amount.Mul(rate).Div(hundred).Round(2)
+}
+
+// parseAmount parses a decimal configuration value, treating an empty string as zero
+func parseAmount(value string) (decimal.Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
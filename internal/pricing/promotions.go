@@ -0,0 +1,196 @@
+package pricing
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Promotion is the part of a promotion rule needed to evaluate it against an order
+type Promotion struct {
+	ID          string
+	Name        string
+	Type        types.PromotionType
+	Value       decimal.Decimal
+	BuyQuantity int
+	GetQuantity int
+	TargetType  types.PromotionTarget
+	TargetID    string
+	MinSpend    decimal.Decimal
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	// DailyStartTime and DailyEndTime are HH:MM in local time; a window whose end
+	// is before its start runs past midnight
+	DailyStartTime string
+	DailyEndTime   string
+	IsStackable    bool
+	Priority       int
+}
+
+// Line is an order line as seen by the promotion engine
+type Line struct {
+	MenuItemID string
+	CategoryID string
+	Quantity   int
+	UnitPrice  decimal.Decimal
+}
+
+// AppliedPromotion is a promotion that discounted the order and by how much
+type AppliedPromotion struct {
+	PromotionID string
+	Name        string
+	Amount      decimal.Decimal
+}
+
+// EvaluatePromotions works out which promotions apply to the order lines at the
+// given time. All qualifying stackable promotions may combine; a non-stackable
+// promotion applies on its own. Whichever option gives the customer the biggest
+// discount wins, with ties going to the higher priority.
+func EvaluatePromotions(promotions []Promotion, lines []Line, at time.Time) []AppliedPromotion {
+	subtotal := decimal.Zero
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.UnitPrice.Mul(decimal.NewFromInt(int64(line.Quantity))))
+	}
+
+	type option struct {
+		applied  []AppliedPromotion
+		total    decimal.Decimal
+		priority int
+	}
+
+	var options []option
+	var stacked option
+	for _, promotion := range promotions {
+		if !promotion.isRunning(at) || subtotal.LessThan(promotion.MinSpend) {
+			continue
+		}
+
+		amount := promotion.discountFor(lines)
+		if !amount.IsPositive() {
+			continue
+		}
+
+		applied := AppliedPromotion{PromotionID: promotion.ID, Name: promotion.Name, Amount: amount}
+		if !promotion.IsStackable {
+			options = append(options, option{applied: []AppliedPromotion{applied}, total: amount, priority: promotion.Priority})
+			continue
+		}
+
+		// Cap the combined discount at the subtotal by trimming the last promotion
+		remaining := subtotal.Sub(stacked.total)
+		if !remaining.IsPositive() {
+			continue
+		}
+		if applied.Amount.GreaterThan(remaining) {
+			applied.Amount = remaining
+		}
+		if len(stacked.applied) == 0 || promotion.Priority > stacked.priority {
+			stacked.priority = promotion.Priority
+		}
+		stacked.applied = append(stacked.applied, applied)
+		stacked.total = stacked.total.Add(applied.Amount)
+	}
+	if len(stacked.applied) > 0 {
+		options = append(options, stacked)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].priority > options[j].priority
+	})
+
+	var best *option
+	for i := range options {
+		if best == nil || options[i].total.GreaterThan(best.total) {
+			best = &options[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	return best.applied
+}
+
+// TotalDiscount sums the discount of the applied promotions
+func TotalDiscount(applied []AppliedPromotion) decimal.Decimal {
+	total := decimal.Zero
+	for _, promotion := range applied {
+		total = total.Add(promotion.Amount)
+	}
+	return total
+}
+
+// isRunning reports whether the promotion's date range and daily window include at
+func (p Promotion) isRunning(at time.Time) bool {
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+
+	if p.DailyStartTime == "" || p.DailyEndTime == "" {
+		return true
+	}
+
+	// HH:MM strings compare correctly as text
+	clock := at.Format("15:04")
+	if p.DailyStartTime <= p.DailyEndTime {
+		return clock >= p.DailyStartTime && clock < p.DailyEndTime
+	}
+	return clock >= p.DailyStartTime || clock < p.DailyEndTime
+}
+
+// appliesTo reports whether the promotion targets the line
+func (p Promotion) appliesTo(line Line) bool {
+	switch p.TargetType {
+	case types.PromotionTargetCategory:
+		return line.CategoryID != "" && line.CategoryID == p.TargetID
+	case types.PromotionTargetMenuItem:
+		return line.MenuItemID == p.TargetID
+	default:
+		return true
+	}
+}
+
+// discountFor calculates the discount the promotion gives on the targeted lines
+func (p Promotion) discountFor(lines []Line) decimal.Decimal {
+	base := decimal.Zero
+	var unitPrices []decimal.Decimal
+	for _, line := range lines {
+		if line.Quantity <= 0 || !p.appliesTo(line) {
+			continue
+		}
+		base = base.Add(line.UnitPrice.Mul(decimal.NewFromInt(int64(line.Quantity))))
+		for i := 0; i < line.Quantity; i++ {
+			unitPrices = append(unitPrices, line.UnitPrice)
+		}
+	}
+
+	switch p.Type {
+	case types.PromotionTypePercentage:
+		return percentOf(base, p.Value)
+	case types.PromotionTypeFixedAmount:
+		return decimal.Min(p.Value.Round(2), base)
+	case types.PromotionTypeBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return decimal.Zero
+		}
+
+		// The cheapest qualifying units are the free ones
+		free := len(unitPrices) / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+		sort.Slice(unitPrices, func(i, j int) bool {
+			return unitPrices[i].LessThan(unitPrices[j])
+		})
+
+		amount := decimal.Zero
+		for _, price := range unitPrices[:free] {
+			amount = amount.Add(price)
+		}
+		return amount
+	default:
+		return decimal.Zero
+	}
+}
@@ -0,0 +1,13 @@
+package pricing
+
+import "github.com/shopspring/decimal"
+
+// Prorate returns the part/whole share of amount, rounded to whole cents. It is
+// used to refund a returned line's share of what was actually paid, so that
+// discounts, service charge, tax and rounding are given back in proportion.
+func Prorate(amount, part, whole decimal.Decimal) decimal.Decimal {
+	if !whole.IsPositive() {
+		return decimal.Zero
+	}
+	return amount.Mul(part).Div(whole).Round(2)
+}
@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Tender is one payment handed over towards an order
+type Tender struct {
+	Method    types.PaymentMethod
+	Amount    decimal.Decimal
+	Reference string
+}
+
+// SettledTender is a tender together with the change given back from it
+type SettledTender struct {
+	Tender
+	Change decimal.Decimal
+}
+
+// SettleTenders checks that the tenders cover the order total and works out the
+// change. Only cash can be overpaid: card, QRIS and transfer payments are for an
+// exact amount, so together they may not exceed the total. The change is handed
+// back from the cash tenders, last one first.
+func SettleTenders(total decimal.Decimal, tenders []Tender) ([]SettledTender, decimal.Decimal, error) {
+	if len(tenders) == 0 {
+		if total.IsPositive() {
+			return nil, decimal.Zero, errors.New("at least one payment is required")
+		}
+		return nil, decimal.Zero, nil
+	}
+
+	paid := decimal.Zero
+	nonCash := decimal.Zero
+	settled := make([]SettledTender, 0, len(tenders))
+	for _, tender := range tenders {
+		switch tender.Method {
+		case types.PaymentMethodCash, types.PaymentMethodCard, types.PaymentMethodQris, types.PaymentMethodTransfer:
+		default:
+			return nil, decimal.Zero, fmt.Errorf("invalid payment method: %s", tender.Method)
+		}
+
+		tender.Amount = tender.Amount.Round(2)
+		if !tender.Amount.IsPositive() {
+			return nil, decimal.Zero, errors.New("payment amount must be greater than zero")
+		}
+
+		paid = paid.Add(tender.Amount)
+		if tender.Method != types.PaymentMethodCash {
+			nonCash = nonCash.Add(tender.Amount)
+		}
+		settled = append(settled, SettledTender{Tender: tender, Change: decimal.Zero})
+	}
+
+	if paid.LessThan(total) {
+		return nil, decimal.Zero, fmt.Errorf("payments of %s do not cover the order total of %s", paid.StringFixed(2), total.StringFixed(2))
+	}
+	if nonCash.GreaterThan(total) {
+		return nil, decimal.Zero, errors.New("non-cash payments cannot exceed the order total")
+	}
+
+	change := paid.Sub(total)
+	remaining := change
+	for i := len(settled) - 1; i >= 0 && remaining.IsPositive(); i-- {
+		if settled[i].Method != types.PaymentMethodCash {
+			continue
+		}
+		settled[i].Change = decimal.Min(settled[i].Amount, remaining)
+		remaining = remaining.Sub(settled[i].Change)
+	}
+
+	return settled, change, nil
+}
+
+// PaymentMethodOf is the payment method recorded on the order: the tenders'
+// method when they all share one, otherwise split
+func PaymentMethodOf(tenders []SettledTender) types.PaymentMethod {
+	if len(tenders) == 0 {
+		return ""
+	}
+
+	method := tenders[0].Method
+	for _, tender := range tenders[1:] {
+		if tender.Method != method {
+			return types.PaymentMethodSplit
+		}
+	}
+	return method
+}
@@ -0,0 +1,53 @@
+package receipt
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ESC/POS commands understood by common 58mm and 80mm thermal printers
+var (
+	escInitialize   = []byte{0x1b, 0x40}       // ESC @
+	escBoldOn       = []byte{0x1b, 0x45, 0x01} // ESC E 1
+	escBoldOff      = []byte{0x1b, 0x45, 0x00} // ESC E 0
+	escDoubleHeight = []byte{0x1d, 0x21, 0x01} // GS ! 1
+	escNormalSize   = []byte{0x1d, 0x21, 0x00} // GS ! 0
+	escFeedLines    = []byte{0x1b, 0x64, 0x04} // ESC d 4, clears the cutter
+	escPartialCut   = []byte{0x1d, 0x56, 0x01} // GS V 1
+)
+
+// encodeESCPOS encodes receipt rows as an ESC/POS byte stream ending with a
+// paper cut. Printers use a single-byte code page, so characters outside ASCII
+// print as "?".
+func encodeESCPOS(lines []line) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInitialize)
+
+	for _, l := range lines {
+		if l.bold {
+			buf.Write(escBoldOn)
+		}
+		if l.tall {
+			buf.Write(escDoubleHeight)
+		}
+
+		for _, r := range strings.TrimRight(l.text, " ") {
+			if r < 0x20 || r > 0x7e {
+				r = '?'
+			}
+			buf.WriteByte(byte(r))
+		}
+		buf.WriteByte('\n')
+
+		if l.tall {
+			buf.Write(escNormalSize)
+		}
+		if l.bold {
+			buf.Write(escBoldOff)
+		}
+	}
+
+	buf.Write(escFeedLines)
+	buf.Write(escPartialCut)
+	return buf.Bytes()
+}
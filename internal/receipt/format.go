@@ -0,0 +1,93 @@
+package receipt
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// timeLayout is how dates are printed on receipts
+const timeLayout = "02/01/2006 15:04"
+
+func formatTime(t time.Time) string {
+	return t.Format(timeLayout)
+}
+
+// formatAmount prints an amount the Indonesian way: "." between thousands and
+// "," before any cents, e.g. 1.250.000 or 12.500,50
+func formatAmount(amount types.DecimalText) string {
+	value := decimal.Decimal(amount)
+
+	sign := ""
+	if value.IsNegative() {
+		sign = "-"
+		value = value.Neg()
+	}
+
+	whole := value.Truncate(0)
+	digits := whole.String()
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	cents := ""
+	if fraction := value.Sub(whole); !fraction.IsZero() {
+		cents = "," + fraction.StringFixed(2)[2:]
+	}
+
+	return sign + grouped.String() + cents
+}
+
+// formatNegative prints a deduction such as a discount
+func formatNegative(amount types.DecimalText) string {
+	return formatAmount(types.FromDecimal(decimal.Decimal(amount).Neg()))
+}
+
+// formatRate prints a percentage rate without trailing zeros, e.g. 11 or 2,5
+func formatRate(rate types.DecimalText) string {
+	return strings.Replace(decimal.Decimal(rate).String(), ".", ",", 1)
+}
+
+// textWidth returns how many columns text takes on a printer
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// wrap breaks text into rows of at most width columns, splitting at spaces and
+// cutting words longer than a whole row
+func wrap(text string, width int) []string {
+	var rows []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for textWidth(word) > width {
+			if current != "" {
+				rows = append(rows, current)
+				current = ""
+			}
+			runes := []rune(word)
+			rows = append(rows, string(runes[:width]))
+			word = string(runes[width:])
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case textWidth(current)+1+textWidth(word) <= width:
+			current += " " + word
+		default:
+			rows = append(rows, current)
+			current = word
+		}
+	}
+	if current != "" || len(rows) == 0 {
+		rows = append(rows, current)
+	}
+	return rows
+}
@@ -0,0 +1,84 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDF receipts are a single page as wide as an 80mm roll and as long as the
+// receipt, set in the built-in Courier fonts so no font needs embedding
+const (
+	pdfPageWidth = 226.77 // 80mm in points
+	pdfMargin    = 12.0
+	pdfLeading   = 9.0
+)
+
+// encodePDF encodes receipt rows as a one-page PDF document
+func encodePDF(lines []line, columns int) []byte {
+	// Courier glyphs are 0.6em wide; pick the size that fits the columns
+	fontSize := (pdfPageWidth - 2*pdfMargin) / (0.6 * float64(columns))
+	pageHeight := 2*pdfMargin + pdfLeading*float64(len(lines))
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n%.2f TL\n%.2f %.2f Td\n", pdfLeading, pdfMargin, pageHeight-pdfMargin-fontSize)
+	font := ""
+	for _, l := range lines {
+		lineFont := "F1"
+		if l.bold || l.tall {
+			lineFont = "F2"
+		}
+		if lineFont != font {
+			fmt.Fprintf(&content, "/%s %.2f Tf\n", lineFont, fontSize)
+			font = lineFont
+		}
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfString(strings.TrimRight(l.text, " ")))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>", pdfPageWidth, pageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfString escapes text for a PDF string literal. Characters outside Latin-1
+// print as "?".
+func pdfString(text string) string {
+	var buf strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r >= 0x20 && r <= 0x7e:
+			buf.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&buf, "\\%03o", r)
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	return buf.String()
+}
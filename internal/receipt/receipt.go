@@ -0,0 +1,326 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Format is an output a receipt can be rendered in
+type Format string
+
+const (
+	FormatESCPOS58 Format = "escpos58" // 58mm thermal printer, 32 columns
+	FormatESCPOS80 Format = "escpos80" // 80mm thermal printer, 48 columns
+	FormatText     Format = "text"
+	FormatPDF      Format = "pdf"
+)
+
+// ParseFormat returns the format named by value
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatESCPOS58, FormatESCPOS80, FormatText, FormatPDF:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown receipt format %q, expected escpos58, escpos80, text or pdf", value)
+}
+
+// columns returns how many characters fit on one line of the format. Text and
+// PDF receipts use the 80mm layout.
+func (f Format) columns() int {
+	if f == FormatESCPOS58 {
+		return 32
+	}
+	return 48
+}
+
+// Settings holds the store details printed on every receipt
+type Settings struct {
+	StoreName string
+	Address   string
+	NPWP      string // Tax ID of the store, shown when set
+	Footer    string // e.g. a thank-you note or the wifi password
+}
+
+// Options holds the details of one print of a receipt
+type Options struct {
+	// Copy counts the prints of the order's receipt; anything after the first
+	// is marked as a reprint
+	Copy      int
+	PrintedAt time.Time
+}
+
+// Document is a rendered receipt
+type Document struct {
+	Format      Format
+	Copy        int
+	ContentType string
+	Filename    string
+	Body        []byte
+}
+
+// Render lays out a completed order as a receipt and encodes it in the given format
+func Render(order *models.OrderWithDetails, settings Settings, format Format, opts Options) (*Document, error) {
+	if _, err := ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+
+	lines := layout(order, settings, format.columns(), opts)
+
+	document := encode(lines, format, order.OrderNumber)
+	document.Copy = opts.Copy
+	return document, nil
+}
+
+// encode encodes laid out rows in the given format as a document named name
+func encode(lines []line, format Format, name string) *Document {
+	document := &Document{Format: format}
+	switch format {
+	case FormatESCPOS58, FormatESCPOS80:
+		document.ContentType = "application/octet-stream"
+		document.Filename = name + ".bin"
+		document.Body = encodeESCPOS(lines)
+	case FormatText:
+		document.ContentType = "text/plain; charset=utf-8"
+		document.Filename = name + ".txt"
+		document.Body = encodeText(lines)
+	case FormatPDF:
+		document.ContentType = "application/pdf"
+		document.Filename = name + ".pdf"
+		document.Body = encodePDF(lines, format.columns())
+	}
+	return document
+}
+
+// line is one printed row of a receipt, already padded to the paper width
+type line struct {
+	text string
+	bold bool
+	tall bool // Double height on thermal printers
+}
+
+// builder collects the rows of a receipt for a given paper width
+type builder struct {
+	width int
+	lines []line
+}
+
+func (b *builder) add(text string) {
+	b.lines = append(b.lines, line{text: text})
+}
+
+func (b *builder) rule() {
+	b.add(strings.Repeat("-", b.width))
+}
+
+// center adds text centered on the paper, wrapping it onto more rows if needed
+func (b *builder) center(text string, bold, tall bool) {
+	for _, row := range wrap(text, b.width) {
+		padding := (b.width - textWidth(row)) / 2
+		b.lines = append(b.lines, line{text: strings.Repeat(" ", padding) + row, bold: bold, tall: tall})
+	}
+}
+
+// wrapped adds text on the left, wrapping it onto more rows indented by indent
+func (b *builder) wrapped(text string, indent int) {
+	prefix := strings.Repeat(" ", indent)
+	for _, row := range wrap(text, b.width-indent) {
+		b.add(prefix + row)
+	}
+}
+
+// pair adds a label on the left and a value on the right. A label too long to
+// share its row is wrapped and the value goes on the row below.
+func (b *builder) pair(label, value string, bold, tall bool) {
+	gap := b.width - textWidth(label) - textWidth(value)
+	if gap >= 1 {
+		b.lines = append(b.lines, line{text: label + strings.Repeat(" ", gap) + value, bold: bold, tall: tall})
+		return
+	}
+
+	indent := len(label) - len(strings.TrimLeft(label, " "))
+	rows := wrap(strings.TrimLeft(label, " "), b.width-indent)
+	for _, row := range rows[:len(rows)-1] {
+		b.lines = append(b.lines, line{text: strings.Repeat(" ", indent) + row, bold: bold, tall: tall})
+	}
+	last := strings.Repeat(" ", indent) + rows[len(rows)-1]
+	if gap = b.width - textWidth(last) - textWidth(value); gap >= 1 {
+		b.lines = append(b.lines, line{text: last + strings.Repeat(" ", gap) + value, bold: bold, tall: tall})
+		return
+	}
+	b.lines = append(b.lines, line{text: last, bold: bold, tall: tall})
+	b.lines = append(b.lines, line{text: strings.Repeat(" ", b.width-textWidth(value)) + value, bold: bold, tall: tall})
+}
+
+// field adds a "Label   : value" row of the order header, wrapping the value
+// under itself
+func (b *builder) field(label, value string) {
+	prefix := fmt.Sprintf("%-8s: ", label)
+	for i, row := range wrap(value, b.width-len(prefix)) {
+		if i > 0 {
+			b.add(strings.Repeat(" ", len(prefix)) + row)
+			continue
+		}
+		b.add(prefix + row)
+	}
+}
+
+// storeHeader adds the store name, address and tax ID followed by a rule, if
+// any of them are set
+func (b *builder) storeHeader(settings Settings) {
+	if settings.StoreName != "" {
+		b.center(settings.StoreName, true, true)
+	}
+	for _, addressLine := range strings.Split(settings.Address, "\n") {
+		if addressLine = strings.TrimSpace(addressLine); addressLine != "" {
+			b.center(addressLine, false, false)
+		}
+	}
+	if settings.NPWP != "" {
+		b.center("NPWP: "+settings.NPWP, false, false)
+	}
+	if len(b.lines) > 0 {
+		b.rule()
+	}
+}
+
+// layout lays out the store header, order details, lines, totals, payments and
+// footer of a receipt
+func layout(order *models.OrderWithDetails, settings Settings, width int, opts Options) []line {
+	b := &builder{width: width}
+	b.storeHeader(settings)
+
+	if opts.Copy > 1 {
+		b.center(fmt.Sprintf("*** REPRINT #%d ***", opts.Copy-1), true, false)
+		if !opts.PrintedAt.IsZero() {
+			b.center(formatTime(opts.PrintedAt), false, false)
+		}
+		b.rule()
+	}
+
+	// Order details
+	b.field("Order", order.OrderNumber)
+	date := order.CreatedAt
+	if order.CompletedAt != nil {
+		date = *order.CompletedAt
+	}
+	b.field("Date", formatTime(date))
+	if order.UserName != "" {
+		b.field("Cashier", order.UserName)
+	}
+	b.field("Type", serviceLabel(order))
+	b.rule()
+
+	// Order lines
+	for _, item := range order.Items {
+		b.wrapped(item.MenuItemName, 0)
+		for _, modifier := range item.Modifiers {
+			label := fmt.Sprintf("  + %s: %s", modifier.GroupName, modifier.OptionName)
+			if decimal.Decimal(modifier.PriceDelta).IsZero() {
+				b.wrapped(label[2:], 2)
+				continue
+			}
+			b.pair(label, formatAmount(modifier.PriceDelta), false, false)
+		}
+		if item.Notes != nil && *item.Notes != "" {
+			b.wrapped("Note: "+*item.Notes, 2)
+		}
+		b.pair(fmt.Sprintf("  %d x %s", item.Quantity, formatAmount(item.UnitPrice)), formatAmount(item.TotalPrice), false, false)
+	}
+	b.rule()
+
+	// Totals
+	b.pair("Subtotal", formatAmount(order.SubtotalAmount), false, false)
+
+	promotionTotal := decimal.Zero
+	for _, promotion := range order.Promotions {
+		promotionTotal = promotionTotal.Add(decimal.Decimal(promotion.DiscountAmount))
+		b.pair(promotion.PromotionName, formatNegative(promotion.DiscountAmount), false, false)
+	}
+	if otherDiscount := decimal.Decimal(order.DiscountAmount).Sub(promotionTotal); otherDiscount.IsPositive() {
+		b.pair("Discount", formatNegative(types.FromDecimal(otherDiscount)), false, false)
+	}
+
+	if !decimal.Decimal(order.ServiceChargeAmount).IsZero() {
+		b.pair(fmt.Sprintf("Service %s%%", formatRate(order.ServiceChargeRate)), formatAmount(order.ServiceChargeAmount), false, false)
+	}
+	if !decimal.Decimal(order.TaxAmount).IsZero() {
+		b.pair(fmt.Sprintf("PPN %s%%", formatRate(order.TaxRate)), formatAmount(order.TaxAmount), false, false)
+	}
+	if !decimal.Decimal(order.RoundingAmount).IsZero() {
+		b.pair("Rounding", formatAmount(order.RoundingAmount), false, false)
+	}
+	b.pair("TOTAL", formatAmount(order.TotalAmount), true, true)
+	b.rule()
+
+	// Payments
+	if len(order.Payments) > 0 {
+		for _, payment := range order.Payments {
+			b.pair(paymentLabel(payment.PaymentMethod), formatAmount(payment.Amount), false, false)
+			if payment.Reference != nil && *payment.Reference != "" {
+				b.wrapped("Ref: "+*payment.Reference, 2)
+			}
+		}
+		if order.ChangeAmount != nil {
+			b.pair("Change", formatAmount(*order.ChangeAmount), false, false)
+		}
+		b.rule()
+	} else if order.PaymentMethod != nil {
+		b.pair(paymentLabel(*order.PaymentMethod), formatAmount(order.TotalAmount), false, false)
+		b.rule()
+	}
+
+	// Footer
+	for _, footerLine := range strings.Split(settings.Footer, "\n") {
+		if footerLine = strings.TrimSpace(footerLine); footerLine != "" {
+			b.center(footerLine, false, false)
+		}
+	}
+
+	return b.lines
+}
+
+// serviceLabel describes how the order was served, e.g. "Dine-in, T4, 3 guests"
+func serviceLabel(order *models.OrderWithDetails) string {
+	var parts []string
+	switch order.OrderType {
+	case types.OrderTypeDineIn:
+		parts = append(parts, "Dine-in")
+	case types.OrderTypeDelivery:
+		parts = append(parts, "Delivery")
+	default:
+		parts = append(parts, "Takeaway")
+	}
+	if order.TableName != nil {
+		parts = append(parts, *order.TableName)
+	}
+	if order.GuestCount != nil {
+		if *order.GuestCount == 1 {
+			parts = append(parts, "1 guest")
+		} else {
+			parts = append(parts, fmt.Sprintf("%d guests", *order.GuestCount))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paymentLabel returns the printed name of a payment method
+func paymentLabel(method types.PaymentMethod) string {
+	switch method {
+	case types.PaymentMethodCash:
+		return "Cash"
+	case types.PaymentMethodCard:
+		return "Card"
+	case types.PaymentMethodQris:
+		return "QRIS"
+	case types.PaymentMethodTransfer:
+		return "Transfer"
+	case types.PaymentMethodSplit:
+		return "Split"
+	}
+	return string(method)
+}
@@ -0,0 +1,102 @@
+package receipt
+
+import (
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// RenderSalesReport lays out an X or Z report and encodes it in the given format
+func RenderSalesReport(report *models.SalesReport, settings Settings, format Format) (*Document, error) {
+	if _, err := ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+
+	name := "x-report-" + report.PeriodEnd.Format("20060102-150405")
+	if report.ReportNumber != nil {
+		name = fmt.Sprintf("z-report-%04d", *report.ReportNumber)
+	}
+
+	return encode(layoutSalesReport(report, settings, format.columns()), format, name), nil
+}
+
+// layoutSalesReport lays out the store header, period, sales totals and the
+// payment method and cashier breakdowns of a report
+func layoutSalesReport(report *models.SalesReport, settings Settings, width int) []line {
+	b := &builder{width: width}
+	b.storeHeader(settings)
+
+	// Report details
+	if report.ReportType == types.SalesReportTypeZ && report.ReportNumber != nil {
+		b.center(fmt.Sprintf("Z REPORT #%04d", *report.ReportNumber), true, true)
+	} else {
+		b.center("X REPORT", true, true)
+		b.center("Not a closing report", false, false)
+	}
+	if report.PeriodStart != nil {
+		b.field("From", formatTime(*report.PeriodStart))
+	} else {
+		b.field("From", "First sale")
+	}
+	b.field("To", formatTime(report.PeriodEnd))
+	b.field("Printed", formatTime(report.GeneratedAt))
+	b.rule()
+
+	// Sales
+	b.pair("Orders", fmt.Sprintf("%d", report.OrderCount), false, false)
+	if report.FirstOrderNumber != nil {
+		b.pair("First order", *report.FirstOrderNumber, false, false)
+	}
+	if report.LastOrderNumber != nil {
+		b.pair("Last order", *report.LastOrderNumber, false, false)
+	}
+	b.rule()
+
+	b.pair("Gross sales", formatAmount(report.GrossSales), false, false)
+	b.pair("Discounts", formatNegative(report.DiscountAmount), false, false)
+	if !decimal.Decimal(report.ServiceCharge).IsZero() {
+		b.pair("Service charge", formatAmount(report.ServiceCharge), false, false)
+	}
+	b.pair("PPN", formatAmount(report.TaxAmount), false, false)
+	if !decimal.Decimal(report.RoundingAmount).IsZero() {
+		b.pair("Rounding", formatAmount(report.RoundingAmount), false, false)
+	}
+	b.pair("TOTAL SALES", formatAmount(report.TotalSales), true, false)
+	b.pair(fmt.Sprintf("Refunds (%d)", report.RefundCount), formatNegative(report.RefundAmount), false, false)
+	b.pair("NET SALES", formatAmount(report.NetSales), true, false)
+	b.wrapped("Net sales exclude tax and refunds", 0)
+	b.rule()
+
+	// Cancellations
+	b.pair("Cancelled orders", fmt.Sprintf("%d", report.CancelledCount), false, false)
+	b.pair(fmt.Sprintf("Voided after payment (%d)", report.VoidedCount), formatAmount(report.VoidedAmount), false, false)
+	b.rule()
+
+	// Payment methods
+	if len(report.PaymentMethods) > 0 {
+		b.add("Payments")
+		for _, paymentMethod := range report.PaymentMethods {
+			label := fmt.Sprintf("  %s (%d)", paymentLabel(paymentMethod.PaymentMethod), paymentMethod.TotalOrders)
+			b.pair(label, formatAmount(paymentMethod.TotalAmount), false, false)
+		}
+		b.rule()
+	}
+
+	// Cashiers
+	if len(report.Cashiers) > 0 {
+		b.add("Cashiers")
+		for _, cashier := range report.Cashiers {
+			label := fmt.Sprintf("  %s (%d)", cashier.CashierName, cashier.OrderCount)
+			b.pair(label, formatAmount(cashier.TotalSales), false, false)
+		}
+		b.rule()
+	}
+
+	if report.ReportType == types.SalesReportTypeZ {
+		b.center("*** END OF DAY ***", true, false)
+	}
+
+	return b.lines
+}
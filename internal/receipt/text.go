@@ -0,0 +1,15 @@
+package receipt
+
+import (
+	"strings"
+)
+
+// encodeText encodes receipt rows as plain UTF-8 text, one row per line
+func encodeText(lines []line) []byte {
+	var buf strings.Builder
+	for _, l := range lines {
+		buf.WriteString(strings.TrimRight(l.text, " "))
+		buf.WriteByte('\n')
+	}
+	return []byte(buf.String())
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ingredientRepo implements the IngredientRepo interface
+type ingredientRepo struct {
+	queries *db.Queries
+}
+
+// toIngredientModel converts a sqlc ingredient row into the domain model
+func toIngredientModel(dbIngredient db.Ingredient) (*models.Ingredient, error) {
+	currentStock, err := parseStockQuantity(dbIngredient.CurrentStock)
+	if err != nil {
+		return nil, err
+	}
+	minimumStock, err := parseStockQuantity(dbIngredient.MinimumStock)
+	if err != nil {
+		return nil, err
+	}
+	costPrice, err := decimal.NewFromString(dbIngredient.CostPrice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ingredient cost price %s: %w", dbIngredient.CostPrice, err)
+	}
+
+	return &models.Ingredient{
+		ID:            dbIngredient.ID.String(),
+		Name:          dbIngredient.Name,
+		Unit:          dbIngredient.Unit,
+		PurchaseUnit:  nullStringToPtr(dbIngredient.PurchaseUnit),
+		CurrentStock:  currentStock,
+		MinimumStock:  minimumStock,
+		CostPrice:     types.DecimalText(costPrice),
+		IsActive:      dbIngredient.IsActive,
+		IsLowStock:    currentStock.Cmp(minimumStock) <= 0,
+		CreatedAt:     dbIngredient.CreatedAt,
+		UpdatedAt:     dbIngredient.UpdatedAt,
+		LastUpdatedBy: nullUUIDToStringPtr(dbIngredient.LastUpdatedBy),
+	}, nil
+}
+
+// CreateIngredient creates a new ingredient with no stock
+func (r *ingredientRepo) CreateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error) {
+	dbIngredient, err := r.queries.CreateIngredient(context.Background(), db.CreateIngredientParams{
+		Name:         ingredient.Name,
+		Unit:         ingredient.Unit,
+		MinimumStock: ingredient.MinimumStock.String(),
+		IsActive:     ingredient.IsActive,
+		PurchaseUnit: ptrToNullString(ingredient.PurchaseUnit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingredient in database: %w", err)
+	}
+
+	return toIngredientModel(dbIngredient)
+}
+
+// GetIngredient retrieves an ingredient by ID
+func (r *ingredientRepo) GetIngredient(id string) (*models.Ingredient, error) {
+	ingredientID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	dbIngredient, err := r.queries.GetIngredient(context.Background(), ingredientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("ingredient not found")
+		}
+		return nil, fmt.Errorf("failed to fetch ingredient from database: %w", err)
+	}
+
+	return toIngredientModel(dbIngredient)
+}
+
+// ListIngredients retrieves ingredients by name based on filter criteria
+func (r *ingredientRepo) ListIngredients(filter models.IngredientFilter) ([]*models.Ingredient, error) {
+	var isActive sql.NullBool
+	if filter.IsActive != nil {
+		isActive = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+
+	dbIngredients, err := r.queries.ListIngredients(context.Background(), db.ListIngredientsParams{
+		IsActive:     isActive,
+		LowStockOnly: filter.LowStockOnly,
+		Limit:        int32(filter.Limit),
+		Offset:       int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ingredients from database: %w", err)
+	}
+
+	ingredients := make([]*models.Ingredient, 0, len(dbIngredients))
+	for _, dbIngredient := range dbIngredients {
+		ingredient, err := toIngredientModel(dbIngredient)
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, nil
+}
+
+// UpdateIngredient updates an ingredient's details. Stock is only changed
+// through AdjustIngredientStock.
+func (r *ingredientRepo) UpdateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error) {
+	ingredientID, err := uuid.Parse(ingredient.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	dbIngredient, err := r.queries.UpdateIngredient(context.Background(), db.UpdateIngredientParams{
+		ID:           ingredientID,
+		Name:         ingredient.Name,
+		Unit:         ingredient.Unit,
+		MinimumStock: ingredient.MinimumStock.String(),
+		IsActive:     ingredient.IsActive,
+		PurchaseUnit: ptrToNullString(ingredient.PurchaseUnit),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("ingredient not found")
+		}
+		return nil, fmt.Errorf("failed to update ingredient in database: %w", err)
+	}
+
+	return toIngredientModel(dbIngredient)
+}
+
+// AdjustIngredientStock applies a relative stock change in a single conditional
+// UPDATE, so concurrent sales never overwrite each other. It returns the stock
+// before and after the change, or ErrInsufficientStock if it would go negative.
+// The ingredient row stays locked until the transaction ends, so concurrent
+// adjustments consume lots one at a time.
+func (r *ingredientRepo) AdjustIngredientStock(id string, quantity types.DecimalText, userID string) (previousStock, currentStock types.DecimalText, err error) {
+	ingredientID, err := uuid.Parse(id)
+	if err != nil {
+		return previousStock, currentStock, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	parsedUserUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return previousStock, currentStock, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbIngredient, err := r.queries.AdjustIngredientStock(context.Background(), db.AdjustIngredientStockParams{
+		Quantity:      quantity.String(),
+		LastUpdatedBy: uuid.NullUUID{UUID: parsedUserUUID, Valid: true},
+		ID:            ingredientID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return previousStock, currentStock, ErrInsufficientStock
+		}
+		return previousStock, currentStock, fmt.Errorf("failed to adjust ingredient stock in database: %w", err)
+	}
+
+	currentStock, err = parseStockQuantity(dbIngredient.CurrentStock)
+	if err != nil {
+		return previousStock, currentStock, err
+	}
+	return currentStock.Sub(quantity), currentStock, nil
+}
+
+// UpdateIngredientCostPrice sets the cost of one stock unit of an ingredient
+func (r *ingredientRepo) UpdateIngredientCostPrice(id string, costPrice types.DecimalText) error {
+	ingredientID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	_, err = r.queries.UpdateIngredientCostPrice(context.Background(), db.UpdateIngredientCostPriceParams{
+		ID:        ingredientID,
+		CostPrice: costPrice.String(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("ingredient not found")
+		}
+		return fmt.Errorf("failed to update ingredient cost price in database: %w", err)
+	}
+
+	return nil
+}
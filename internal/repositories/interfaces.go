@@ -36,31 +36,71 @@ type MenuRepo interface {
 	CreateMenuItem(item *models.MenuItem) (*models.MenuItem, error)
 	UpdateMenuItem(item *models.MenuItem) (*models.MenuItem, error)
 	DeleteMenuItem(id string) error
+	UpdateMenuItemCost(id string, cost types.DecimalText) error
+	SyncMenuItemAvailability(menuItemID string, ingredientID *string) ([]*models.MenuItemAvailability, error)
 }
 
 // OrderRepo defines the interface for order-related database operations
 type OrderRepo interface {
 	GetOrder(id string) (*models.Order, error)
+	GetOrderForUpdate(id string) (*models.Order, error)
+	HoldOffZReports() error
 	GetOrderByNumber(orderNumber string) (*models.Order, error)
 	ListOrders(filter types.OrderFilter) ([]*models.Order, error)
 	CreateOrder(order *models.Order) (*models.Order, error)
+	NextOrderNumber(prefix string, businessDate time.Time) (int, error)
 	UpdateOrderStatus(orderID string, status string) error
 	UpdateOrderPayment(orderID string, paymentMethod, paymentStatus string, completedAt *string) error
-	UpdateOrderTotal(orderID string, totalAmount, discountAmount, taxAmount string) error
+	UpdateOrderPaymentStatus(orderID string, paymentStatus string) error
+	UpdateOrderTotal(order *models.Order) error
+	UpdateOrderTable(order *models.Order) error
+	UpdateOrderShift(orderID, shiftID string) error
+	CancelOrder(orderID string, paymentStatus string, reason *string, cancelledBy string, voidShiftID *string) error
+	MergeOrder(orderID string, reason string, mergedBy string) error
 }
 
 // InventoryRepo defines the interface for inventory-related database operations
 type InventoryRepo interface {
 	GetInventoryByMenuItem(menuItemID string) (*models.Inventory, error)
 	ListInventory(filter models.InventoryFilter) ([]*models.Inventory, error)
-	UpdateInventoryStock(menuItemID string, stock int, userID string) error
+	UpdateInventoryStock(menuItemID string, stock types.DecimalText, userID string) error
+	AdjustInventoryStock(menuItemID string, quantity types.DecimalText, userID string) (previousStock, currentStock types.DecimalText, err error)
 	CreateInventoryRecord(menuItemID string) error
 }
 
+// IngredientRepo defines the interface for ingredient-related database operations
+type IngredientRepo interface {
+	CreateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error)
+	GetIngredient(id string) (*models.Ingredient, error)
+	ListIngredients(filter models.IngredientFilter) ([]*models.Ingredient, error)
+	UpdateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error)
+	AdjustIngredientStock(id string, quantity types.DecimalText, userID string) (previousStock, currentStock types.DecimalText, err error)
+	UpdateIngredientCostPrice(id string, costPrice types.DecimalText) error
+}
+
+// UnitRepo defines the interface for unit of measure database operations
+type UnitRepo interface {
+	ListUnits() ([]*models.UnitOfMeasure, error)
+	GetUnit(code string) (*models.UnitOfMeasure, error)
+	CreateUnit(unit *models.UnitOfMeasure) (*models.UnitOfMeasure, error)
+}
+
+// RecipeRepo defines the interface for recipe-related database operations
+type RecipeRepo interface {
+	GetMenuItemRecipe(menuItemID string) ([]models.RecipeItem, error)
+	SetMenuItemRecipe(menuItemID string, items []models.RecipeItemInput) error
+	GetModifierOptionRecipe(optionID string) ([]models.RecipeItem, error)
+	SetModifierOptionRecipe(optionID string, items []models.RecipeItemInput) error
+	CountRecipesByIngredient(ingredientID string) (int, error)
+}
+
 // StockTransactionRepo defines the interface for stock transaction-related database operations
 type StockTransactionRepo interface {
 	CreateStockTransaction(transaction *models.StockTransaction) (*models.StockTransaction, error)
+	GetStockAverageCost(menuItemID string, ingredientID *string) (types.DecimalText, error)
+	SetStockAverageCost(menuItemID string, ingredientID *string, averageCost types.DecimalText) error
 	ListStockTransactions(filter models.StockTransactionFilter) ([]*models.StockTransaction, error)
+	ListStockMovedByReference(referenceType, referenceID string) ([]*models.StockMoved, error)
 }
 
 // ExpenseRepo defines the interface for expense-related database operations
@@ -80,7 +120,230 @@ type OrderItemRepo interface {
 	CreateOrderItem(orderItem *models.OrderItem) (*models.OrderItem, error)
 	UpdateOrderItem(orderItem *models.OrderItem) (*models.OrderItem, error)
 	DeleteOrderItem(id string) error
+	MoveOrderItem(id, orderID string) error
 	GetOrderItemsWithDetails(orderID string) ([]*models.OrderItemWithDetails, error)
+	CreateOrderItemStockUsage(usage *models.OrderItemStockUsage) error
+	ListOrderItemStockUsage(orderID string) (map[string][]*models.OrderItemStockUsage, error)
+}
+
+// PromotionRepo defines the interface for promotion-related database operations
+type PromotionRepo interface {
+	CreatePromotion(promotion *models.Promotion) (*models.Promotion, error)
+	GetPromotion(id string) (*models.Promotion, error)
+	GetPromotionByVoucherCode(code string) (*models.Promotion, error)
+	ListPromotions(filter models.PromotionFilter) ([]*models.Promotion, error)
+	ListAutomaticPromotions(at time.Time) ([]*models.Promotion, error)
+	UpdatePromotion(promotion *models.Promotion) (*models.Promotion, error)
+	DeletePromotion(id string) error
+	ClaimPromotionUsage(id string) error
+	ReleasePromotionUsage(id string) error
+
+	CreateOrderPromotion(orderPromotion *models.OrderPromotion) (*models.OrderPromotion, error)
+	ListOrderPromotions(orderID string) ([]*models.OrderPromotion, error)
+	DeleteOrderPromotions(orderID string) error
+}
+
+// OrderPaymentRepo defines the interface for order payment-related database operations
+type OrderPaymentRepo interface {
+	CreateOrderPayment(payment *models.OrderPayment) (*models.OrderPayment, error)
+	ListOrderPayments(orderID string) ([]*models.OrderPayment, error)
+}
+
+// RefundRepo defines the interface for refund-related database operations
+type RefundRepo interface {
+	CreateRefund(refund *models.Refund) (*models.Refund, error)
+	CreateRefundItem(item *models.RefundItem) (*models.RefundItem, error)
+	ListRefundsByOrderID(orderID string) ([]*models.Refund, error)
+	GetRefundedQuantities(orderID string) (map[string]int, error)
+}
+
+// ModifierRepo defines the interface for menu modifier-related database operations
+type ModifierRepo interface {
+	CreateModifierGroup(group *models.ModifierGroup) (*models.ModifierGroup, error)
+	GetModifierGroup(id string) (*models.ModifierGroup, error)
+	ListModifierGroups(filter models.ModifierGroupFilter) ([]*models.ModifierGroup, error)
+	UpdateModifierGroup(group *models.ModifierGroup) (*models.ModifierGroup, error)
+	DeleteModifierGroup(id string) error
+
+	CreateModifierOption(option *models.ModifierOption) (*models.ModifierOption, error)
+	GetModifierOption(id string) (*models.ModifierOption, error)
+	UpdateModifierOption(option *models.ModifierOption) (*models.ModifierOption, error)
+	DeleteModifierOption(id string) error
+
+	AttachModifierGroup(menuItemID, groupID string, sortOrder int) error
+	DetachModifierGroup(menuItemID, groupID string) error
+	ListMenuItemModifierGroups(menuItemID string) ([]*models.ModifierGroup, error)
+
+	CreateOrderItemModifier(modifier *models.OrderItemModifier) (*models.OrderItemModifier, error)
+	ListOrderItemModifiers(orderID string) ([]*models.OrderItemModifier, error)
+	CopyOrderItemModifiers(sourceOrderItemID, orderItemID string) error
+}
+
+// KitchenRepo defines the interface for kitchen display-related database operations
+type KitchenRepo interface {
+	CreateStation(station *models.KitchenStation) (*models.KitchenStation, error)
+	GetStation(id string) (*models.KitchenStation, error)
+	ListStations(isActive *bool) ([]*models.KitchenStation, error)
+	UpdateStation(station *models.KitchenStation) (*models.KitchenStation, error)
+	DeleteStation(id string) error
+	SetStationCategories(stationID string, categoryIDs []string) error
+
+	CreateTicketsForOrder(orderID string) ([]string, error)
+	GetTicket(id string) (*models.KitchenTicket, error)
+	ListTickets(filter models.KitchenTicketFilter) ([]*models.KitchenTicket, error)
+	BumpTicket(id string, from, to types.KitchenStatus, userID string) error
+	DeleteOpenTicketsByOrderID(orderID string) error
+	MoveTicket(orderItemID, orderID string) error
+	CopyTicket(sourceOrderItemID, orderItemID string) error
+}
+
+// TableRepo defines the interface for floor area and dining table-related database operations
+type TableRepo interface {
+	CreateFloorArea(area *models.FloorArea) (*models.FloorArea, error)
+	GetFloorArea(id string) (*models.FloorArea, error)
+	ListFloorAreas(isActive *bool) ([]*models.FloorArea, error)
+	UpdateFloorArea(area *models.FloorArea) (*models.FloorArea, error)
+	DeleteFloorArea(id string) error
+	CountTablesByFloorArea(floorAreaID string) (int, error)
+
+	CreateTable(table *models.DiningTable) (*models.DiningTable, error)
+	GetTable(id string) (*models.DiningTable, error)
+	ListTables(filter models.DiningTableFilter) ([]*models.DiningTable, error)
+	UpdateTable(table *models.DiningTable) (*models.DiningTable, error)
+	DeleteTable(id string) error
+	CountOpenOrdersByTable(tableID string) (int, error)
+	ListOpenTableOrders() ([]*models.TableOrder, error)
+}
+
+// ReceiptRepo defines the interface for receipt print-related database operations
+type ReceiptRepo interface {
+	CreateReceiptPrint(orderID, printedBy, format string) (*models.ReceiptPrint, error)
+	ListReceiptPrints(orderID string) ([]*models.ReceiptPrint, error)
+}
+
+// ShiftRepo defines the interface for cash drawer shift-related database operations
+type ShiftRepo interface {
+	CreateShift(shift *models.CashShift) (*models.CashShift, error)
+	GetShift(id string) (*models.CashShift, error)
+	GetShiftForUpdate(id string) (*models.CashShift, error)
+	GetOpenShiftByUser(userID string) (*models.CashShift, error)
+	ListShifts(filter models.CashShiftFilter) ([]*models.CashShift, error)
+	CloseShift(shift *models.CashShift) (*models.CashShift, error)
+
+	CreateMovement(movement *models.CashMovement) (*models.CashMovement, error)
+	ListMovements(shiftID string) ([]*models.CashMovement, error)
+	CreateCount(shiftID string, count models.CashCount) error
+	ListCounts(shiftID string) ([]models.CashCount, error)
+	GetCashSales(shiftID string) (orders int, sales types.DecimalText, err error)
+	GetCashRefunds(shiftID string) (types.DecimalText, error)
+	GetCashVoids(shiftID string) (types.DecimalText, error)
+}
+
+// SalesReportRepo defines the interface for X and Z sales report-related database operations
+type SalesReportRepo interface {
+	SummarizeSales(periodStart *time.Time, periodEnd time.Time) (*models.SalesReport, error)
+
+	LockZReports() error
+	GetDatabaseTime() (time.Time, error)
+	GetLatestZReport() (*models.SalesReport, error)
+	GetZReport(id string) (*models.SalesReport, error)
+	ListZReports(limit, offset int) ([]*models.SalesReport, error)
+	CreateZReport(report *models.SalesReport) (*models.SalesReport, error)
+}
+
+// SupplierRepo defines the interface for supplier-related database operations
+type SupplierRepo interface {
+	CreateSupplier(supplier *models.Supplier) (*models.Supplier, error)
+	GetSupplier(id string) (*models.Supplier, error)
+	ListSuppliers(filter models.SupplierFilter) ([]*models.Supplier, error)
+	UpdateSupplier(supplier *models.Supplier) (*models.Supplier, error)
+}
+
+// PurchaseOrderRepo defines the interface for purchase order and goods receiving database operations
+type PurchaseOrderRepo interface {
+	CreatePurchaseOrder(order *models.PurchaseOrder) (*models.PurchaseOrder, error)
+	GetPurchaseOrder(id string) (*models.PurchaseOrder, error)
+	GetPurchaseOrderForUpdate(id string) (*models.PurchaseOrder, error)
+	ListPurchaseOrders(filter models.PurchaseOrderFilter) ([]*models.PurchaseOrder, error)
+	UpdatePurchaseOrder(order *models.PurchaseOrder) error
+	UpdatePurchaseOrderStatus(id string, status types.PurchaseOrderStatus) error
+	SetPurchaseOrderItems(id string, items []models.PurchaseOrderItem) error
+	ListPurchaseOrderItems(id string) ([]models.PurchaseOrderItem, error)
+	ReceivePurchaseOrderItem(itemID string, quantity types.DecimalText) error
+
+	CreateGoodsReceivedNote(note *models.GoodsReceivedNote) (*models.GoodsReceivedNote, error)
+	CreateGoodsReceivedNoteItem(item *models.GoodsReceivedNoteItem) error
+	ListGoodsReceivedNotes(purchaseOrderID string) ([]models.GoodsReceivedNote, error)
+}
+
+// StockTakeRepo defines the interface for stock take-related database operations
+type StockTakeRepo interface {
+	CreateStockTake(stockTake *models.StockTake) (*models.StockTake, error)
+	SnapshotStockTakeMenuItems(id string, categoryID *string) error
+	SnapshotStockTakeIngredients(id string) error
+	GetStockTake(id string) (*models.StockTake, error)
+	GetStockTakeForUpdate(id string) (*models.StockTake, error)
+	ListStockTakes(filter models.StockTakeFilter) ([]*models.StockTake, error)
+	ListStockTakeLines(id string) ([]models.StockTakeLine, error)
+	SetStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error
+	AddStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error
+	UpdateStockTakeStatus(id string, status types.StockTakeStatus, approvedBy *string) error
+}
+
+// WasteRepo defines the interface for waste-related database operations
+type WasteRepo interface {
+	CreateWasteRecord(record *models.WasteRecord) (*models.WasteRecord, error)
+	ListWasteRecords(filter models.WasteFilter) ([]*models.WasteRecord, error)
+}
+
+// StockLotRepo defines the interface for stock lot-related database operations.
+// Lots are used up by ConsumeStockLots, after the stock is taken out with
+// InventoryRepo.AdjustInventoryStock or IngredientRepo.AdjustIngredientStock
+// in the same database transaction.
+type StockLotRepo interface {
+	CreateStockLot(lot *models.StockLot) (*models.StockLot, error)
+	ListStockLots(filter models.StockLotFilter) ([]*models.StockLot, error)
+	ListStockLotsExpiringBefore(before time.Time) ([]*models.StockLot, error)
+	GetExpiredStock(menuItemID string, ingredientID *string) (types.DecimalText, error)
+	ConsumeStockLots(menuItemID string, ingredientID *string, quantity types.DecimalText) error
+	EmptyStockLot(id string) error
+}
+
+// StockLocationRepo defines the interface for stock location, balance and
+// transfer database operations. Balances are kept by
+// StockTransactionRepo.CreateStockTransaction at the location of every
+// transaction it records.
+type StockLocationRepo interface {
+	CreateStockLocation(location *models.StockLocation) (*models.StockLocation, error)
+	GetStockLocation(id string) (*models.StockLocation, error)
+	GetSellingStockLocation() (*models.StockLocation, error)
+	ListStockLocations(includeInactive bool) ([]*models.StockLocation, error)
+	UpdateStockLocation(location *models.StockLocation) (*models.StockLocation, error)
+	ClearSellingStockLocation(exceptID string) error
+	ListStockBalances(locationID string) ([]*models.StockBalance, error)
+	LockItemStock(menuItemID, ingredientID *string) (types.DecimalText, error)
+	AdjustStockBalance(locationID, menuItemID string, ingredientID *string, change types.DecimalText) error
+
+	CreateStockTransfer(transfer *models.StockTransfer) (*models.StockTransfer, error)
+	CreateStockTransferLine(line *models.StockTransferLine) error
+	GetStockTransfer(id string) (*models.StockTransfer, error)
+	ListStockTransfers(filter models.StockTransferFilter) ([]*models.StockTransfer, error)
+	ListStockTransferLines(id string) ([]models.StockTransferLine, error)
+}
+
+// LowStockAlertRepo defines the interface for low stock alert database
+// operations. Alerts are raised and resolved by
+// StockTransactionRepo.CreateStockTransaction as stock moves.
+type LowStockAlertRepo interface {
+	ListLowStockAlerts(filter models.LowStockAlertFilter) ([]*models.LowStockAlert, error)
+	CountUnreadLowStockAlerts() (int64, error)
+	MarkLowStockAlertRead(id, userID string) (*models.LowStockAlert, error)
+	ClaimLowStockAlerts(maxAttempts, batchSize int) ([]*models.LowStockAlert, error)
+	ListLowStockAlertDeliveries(alertID string) ([]string, error)
+	CreateLowStockAlertDelivery(alertID, channel string) error
+	MarkLowStockAlertDispatched(id string) error
+	SetLowStockAlertError(id, message string) error
+	SyncLowStockAlert(stockTransactionID, menuItemID string, ingredientID *string, previousStock types.DecimalText) error
 }
 
 // Repository holds all repository interfaces
@@ -89,25 +352,69 @@ type Repository struct {
 	MenuRepo             MenuRepo
 	OrderRepo            OrderRepo
 	OrderItemRepo        OrderItemRepo
+	OrderPaymentRepo     OrderPaymentRepo
 	InventoryRepo        InventoryRepo
 	StockTransactionRepo StockTransactionRepo
+	IngredientRepo       IngredientRepo
+	RecipeRepo           RecipeRepo
+	UnitRepo             UnitRepo
+	SupplierRepo         SupplierRepo
+	PurchaseOrderRepo    PurchaseOrderRepo
+	StockTakeRepo        StockTakeRepo
+	WasteRepo            WasteRepo
+	StockLotRepo         StockLotRepo
+	StockLocationRepo    StockLocationRepo
+	LowStockAlertRepo    LowStockAlertRepo
 	ExpenseRepo          ExpenseRepo
+	PromotionRepo        PromotionRepo
+	RefundRepo           RefundRepo
+	ModifierRepo         ModifierRepo
+	KitchenRepo          KitchenRepo
+	TableRepo            TableRepo
+	ReceiptRepo          ReceiptRepo
+	ShiftRepo            ShiftRepo
+	SalesReportRepo      SalesReportRepo
 	Queries              *db.Queries
+	UnitOfWork           UnitOfWork // nil on a Repository that is already bound to a transaction
 }
 
 // NewRepository creates a new Repository instance with concrete implementations
 func NewRepository(dbConn *sql.DB) *Repository {
-	queries := db.New(dbConn)
+	repo := newRepository(db.New(dbConn))
+	repo.UnitOfWork = NewUnitOfWork(dbConn)
+	return repo
+}
 
+// newRepository wires every concrete repository to the same set of queries,
+// which may be bound either to the connection pool or to a single transaction
+func newRepository(queries *db.Queries) *Repository {
 	return &Repository{
-		UserRepo:             &userRepo{queries: queries},  // This is defined in user_repository.go
-		MenuRepo:             &menuRepo{queries: queries},  // This is defined in menu_repository.go
-		OrderRepo:            &orderRepo{queries: queries}, // This is defined in order_repository.go
-		OrderItemRepo:        &orderItemRepo{queries: queries}, // This is defined in order_item_repository.go
-		InventoryRepo:        &inventoryRepo{queries: queries}, // This is defined in inventory_repository.go
+		UserRepo:             &userRepo{queries: queries},             // This is defined in user_repository.go
+		MenuRepo:             &menuRepo{queries: queries},             // This is defined in menu_repository.go
+		OrderRepo:            &orderRepo{queries: queries},            // This is defined in order_repository.go
+		OrderItemRepo:        &orderItemRepo{queries: queries},        // This is defined in order_item_repository.go
+		OrderPaymentRepo:     &orderPaymentRepo{queries: queries},     // This is defined in order_payment_repository.go
+		InventoryRepo:        &inventoryRepo{queries: queries},        // This is defined in inventory_repository.go
 		StockTransactionRepo: &stockTransactionRepo{queries: queries}, // This is defined in stock_transaction_repository.go
-		ExpenseRepo:          &expenseRepo{queries: queries}, // This is defined in expense_repository.go
+		IngredientRepo:       &ingredientRepo{queries: queries},       // This is defined in ingredient_repository.go
+		RecipeRepo:           &recipeRepo{queries: queries},           // This is defined in recipe_repository.go
+		UnitRepo:             &unitRepo{queries: queries},             // This is defined in unit_repository.go
+		SupplierRepo:         &supplierRepo{queries: queries},         // This is defined in supplier_repository.go
+		PurchaseOrderRepo:    &purchaseOrderRepo{queries: queries},    // This is defined in purchase_order_repository.go
+		StockTakeRepo:        &stockTakeRepo{queries: queries},        // This is defined in stock_take_repository.go
+		WasteRepo:            &wasteRepo{queries: queries},            // This is defined in waste_repository.go
+		StockLotRepo:         &stockLotRepo{queries: queries},         // This is defined in stock_lot_repository.go
+		StockLocationRepo:    &stockLocationRepo{queries: queries},    // This is defined in stock_location_repository.go
+		LowStockAlertRepo:    &lowStockAlertRepo{queries: queries},    // This is defined in low_stock_alert_repository.go
+		ExpenseRepo:          &expenseRepo{queries: queries},          // This is defined in expense_repository.go
+		PromotionRepo:        &promotionRepo{queries: queries},        // This is defined in promotion_repository.go
+		RefundRepo:           &refundRepo{queries: queries},           // This is defined in refund_repository.go
+		ModifierRepo:         &modifierRepo{queries: queries},         // This is defined in modifier_repository.go
+		KitchenRepo:          &kitchenRepo{queries: queries},          // This is defined in kitchen_repository.go
+		TableRepo:            &tableRepo{queries: queries},            // This is defined in table_repository.go
+		ReceiptRepo:          &receiptRepo{queries: queries},          // This is defined in receipt_repository.go
+		ShiftRepo:            &shiftRepo{queries: queries},            // This is defined in shift_repository.go
+		SalesReportRepo:      &salesReportRepo{queries: queries},      // This is defined in sales_report_repository.go
 		Queries:              queries,
 	}
 }
-
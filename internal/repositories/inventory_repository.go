@@ -4,17 +4,32 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/db"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
+// ErrInsufficientStock is returned when a stock adjustment would take an item below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 // inventoryRepo implements the InventoryRepo interface
 type inventoryRepo struct {
 	queries *db.Queries
 }
 
+// parseStockQuantity parses a numeric stock column
+func parseStockQuantity(value string) (types.DecimalText, error) {
+	quantity, err := decimal.NewFromString(value)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to parse stock quantity %s: %w", value, err)
+	}
+	return types.DecimalText(quantity), nil
+}
+
 // GetInventoryByMenuItem retrieves inventory by menu item ID
 func (r *inventoryRepo) GetInventoryByMenuItem(menuItemID string) (*models.Inventory, error) {
 	menuItemUUID, err := uuid.Parse(menuItemID)
@@ -30,11 +45,20 @@ func (r *inventoryRepo) GetInventoryByMenuItem(menuItemID string) (*models.Inven
 		return nil, err
 	}
 
+	currentStock, err := parseStockQuantity(dbInventory.CurrentStock)
+	if err != nil {
+		return nil, err
+	}
+	minimumStock, err := parseStockQuantity(dbInventory.MinimumStock)
+	if err != nil {
+		return nil, err
+	}
+
 	inventory := &models.Inventory{
 		ID:            dbInventory.ID.String(),
 		MenuItemID:    dbInventory.MenuItemID.String(),
-		CurrentStock:  int(dbInventory.CurrentStock),
-		MinimumStock:  int(dbInventory.MinimumStock),
+		CurrentStock:  currentStock,
+		MinimumStock:  minimumStock,
 		Unit:          dbInventory.Unit,
 		LastUpdatedAt: dbInventory.LastUpdatedAt,
 	}
@@ -67,15 +91,24 @@ func (r *inventoryRepo) ListInventory(filter models.InventoryFilter) ([]*models.
 
 	var inventories []*models.Inventory
 	for _, dbInventory := range dbInventories {
+		currentStock, err := parseStockQuantity(dbInventory.CurrentStock)
+		if err != nil {
+			return nil, err
+		}
+		minimumStock, err := parseStockQuantity(dbInventory.MinimumStock)
+		if err != nil {
+			return nil, err
+		}
+
 		inventory := &models.Inventory{
 			ID:             dbInventory.ID.String(),
 			MenuItemID:     dbInventory.MenuItemID.String(),
 			MenuItemName:   dbInventory.MenuItemName,
-			CurrentStock:   int(dbInventory.CurrentStock),
-			MinimumStock:   int(dbInventory.MinimumStock),
+			CurrentStock:   currentStock,
+			MinimumStock:   minimumStock,
 			Unit:           dbInventory.Unit,
 			LastUpdatedAt:  dbInventory.LastUpdatedAt,
-			IsLowStock:     dbInventory.CurrentStock <= dbInventory.MinimumStock,
+			IsLowStock:     currentStock.Cmp(minimumStock) <= 0,
 		}
 
 		if dbInventory.LastUpdatedBy.Valid {
@@ -88,7 +121,7 @@ func (r *inventoryRepo) ListInventory(filter models.InventoryFilter) ([]*models.
 }
 
 // UpdateInventoryStock updates the stock for a menu item
-func (r *inventoryRepo) UpdateInventoryStock(menuItemID string, stock int, userID string) error {
+func (r *inventoryRepo) UpdateInventoryStock(menuItemID string, stock types.DecimalText, userID string) error {
 	menuItemUUID, err := uuid.Parse(menuItemID)
 	if err != nil {
 		return err
@@ -106,7 +139,7 @@ func (r *inventoryRepo) UpdateInventoryStock(menuItemID string, stock int, userI
 
 	err = r.queries.UpdateInventoryStock(context.Background(), db.UpdateInventoryStockParams{
 		MenuItemID:    menuItemUUID,
-		CurrentStock:  int32(stock),
+		CurrentStock:  stock.String(),
 		LastUpdatedBy: userUUID,
 	})
 	if err != nil {
@@ -116,6 +149,41 @@ func (r *inventoryRepo) UpdateInventoryStock(menuItemID string, stock int, userI
 	return nil
 }
 
+// AdjustInventoryStock applies a relative stock change in a single conditional UPDATE,
+// so concurrent adjustments never overwrite each other. It returns the inventory as it
+// was before and after the change, or ErrInsufficientStock if stock would go negative
+// (or the item has no inventory record yet). The inventory row stays locked until
+// the transaction ends, so concurrent adjustments consume lots one at a time.
+func (r *inventoryRepo) AdjustInventoryStock(menuItemID string, quantity types.DecimalText, userID string) (previousStock, currentStock types.DecimalText, err error) {
+	menuItemUUID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return previousStock, currentStock, err
+	}
+
+	parsedUserUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return previousStock, currentStock, err
+	}
+
+	dbInventory, err := r.queries.AdjustInventoryStock(context.Background(), db.AdjustInventoryStockParams{
+		Quantity:      quantity.String(),
+		LastUpdatedBy: uuid.NullUUID{UUID: parsedUserUUID, Valid: true},
+		MenuItemID:    menuItemUUID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return previousStock, currentStock, ErrInsufficientStock
+		}
+		return previousStock, currentStock, err
+	}
+
+	currentStock, err = parseStockQuantity(dbInventory.CurrentStock)
+	if err != nil {
+		return previousStock, currentStock, err
+	}
+	return currentStock.Sub(quantity), currentStock, nil
+}
+
 // CreateInventoryRecord creates a new inventory record
 func (r *inventoryRepo) CreateInventoryRecord(menuItemID string) error {
 	menuItemUUID, err := uuid.Parse(menuItemID)
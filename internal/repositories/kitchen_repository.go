@@ -0,0 +1,365 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// ErrKitchenTicketChanged is returned when a line was bumped by someone else
+// after it was read
+var ErrKitchenTicketChanged = errors.New("kitchen ticket was changed by someone else")
+
+// kitchenRepo implements the KitchenRepo interface
+type kitchenRepo struct {
+	queries *db.Queries
+}
+
+// toKitchenStationModel converts a sqlc kitchen station row into the domain model
+func toKitchenStationModel(dbStation db.KitchenStation) *models.KitchenStation {
+	return &models.KitchenStation{
+		ID:          dbStation.ID.String(),
+		Name:        dbStation.Name,
+		Description: dbStation.Description.String,
+		IsActive:    dbStation.IsActive,
+		CreatedAt:   dbStation.CreatedAt,
+		UpdatedAt:   dbStation.UpdatedAt,
+		CategoryIDs: []string{},
+	}
+}
+
+// withCategories loads the categories routed to each station
+func (r *kitchenRepo) withCategories(dbStations []db.KitchenStation) ([]*models.KitchenStation, error) {
+	stations := make([]*models.KitchenStation, 0, len(dbStations))
+	for _, dbStation := range dbStations {
+		station := toKitchenStationModel(dbStation)
+
+		categoryIDs, err := r.queries.ListCategoryIDsByStationID(context.Background(), dbStation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch station categories from database: %w", err)
+		}
+
+		for _, categoryID := range categoryIDs {
+			station.CategoryIDs = append(station.CategoryIDs, categoryID.String())
+		}
+
+		stations = append(stations, station)
+	}
+
+	return stations, nil
+}
+
+// CreateStation creates a new kitchen station
+func (r *kitchenRepo) CreateStation(station *models.KitchenStation) (*models.KitchenStation, error) {
+	dbStation, err := r.queries.CreateKitchenStation(context.Background(), db.CreateKitchenStationParams{
+		Name:        station.Name,
+		Description: sql.NullString{String: station.Description, Valid: station.Description != ""},
+		IsActive:    station.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kitchen station in database: %w", err)
+	}
+
+	return toKitchenStationModel(dbStation), nil
+}
+
+// GetStation retrieves a kitchen station with its categories
+func (r *kitchenRepo) GetStation(id string) (*models.KitchenStation, error) {
+	stationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kitchen station ID: %w", err)
+	}
+
+	dbStation, err := r.queries.GetKitchenStation(context.Background(), stationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("kitchen station not found")
+		}
+		return nil, fmt.Errorf("failed to fetch kitchen station from database: %w", err)
+	}
+
+	stations, err := r.withCategories([]db.KitchenStation{dbStation})
+	if err != nil {
+		return nil, err
+	}
+
+	return stations[0], nil
+}
+
+// ListStations retrieves the kitchen stations with their categories
+func (r *kitchenRepo) ListStations(isActive *bool) ([]*models.KitchenStation, error) {
+	var active sql.NullBool
+	if isActive != nil {
+		active = sql.NullBool{Bool: *isActive, Valid: true}
+	}
+
+	dbStations, err := r.queries.ListKitchenStations(context.Background(), active)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kitchen stations from database: %w", err)
+	}
+
+	return r.withCategories(dbStations)
+}
+
+// UpdateStation updates an existing kitchen station
+func (r *kitchenRepo) UpdateStation(station *models.KitchenStation) (*models.KitchenStation, error) {
+	stationID, err := uuid.Parse(station.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kitchen station ID: %w", err)
+	}
+
+	dbStation, err := r.queries.UpdateKitchenStation(context.Background(), db.UpdateKitchenStationParams{
+		ID:          stationID,
+		Name:        station.Name,
+		Description: sql.NullString{String: station.Description, Valid: station.Description != ""},
+		IsActive:    station.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("kitchen station not found")
+		}
+		return nil, fmt.Errorf("failed to update kitchen station in database: %w", err)
+	}
+
+	return toKitchenStationModel(dbStation), nil
+}
+
+// DeleteStation deletes a kitchen station. Its lines stay on the display with no station.
+func (r *kitchenRepo) DeleteStation(id string) error {
+	stationID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid kitchen station ID: %w", err)
+	}
+
+	if err := r.queries.DeleteKitchenStation(context.Background(), stationID); err != nil {
+		return fmt.Errorf("failed to delete kitchen station from database: %w", err)
+	}
+
+	return nil
+}
+
+// SetStationCategories replaces the categories routed to a station. Categories
+// routed to another station are moved to this one.
+func (r *kitchenRepo) SetStationCategories(stationID string, categoryIDs []string) error {
+	stationUUID, err := uuid.Parse(stationID)
+	if err != nil {
+		return fmt.Errorf("invalid kitchen station ID: %w", err)
+	}
+
+	if err := r.queries.ClearStationCategories(context.Background(), stationUUID); err != nil {
+		return fmt.Errorf("failed to clear station categories in database: %w", err)
+	}
+
+	for _, categoryID := range categoryIDs {
+		categoryUUID, err := uuid.Parse(categoryID)
+		if err != nil {
+			return fmt.Errorf("invalid category ID: %w", err)
+		}
+
+		err = r.queries.AssignCategoryToStation(context.Background(), db.AssignCategoryToStationParams{
+			CategoryID: categoryUUID,
+			StationID:  stationUUID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to assign category to station in database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateTicketsForOrder sends the order's lines that are not in the kitchen yet
+// to their stations and returns the IDs of the new tickets
+func (r *kitchenRepo) CreateTicketsForOrder(orderID string) ([]string, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	ticketIDs, err := r.queries.CreateKitchenTicketsForOrder(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kitchen tickets in database: %w", err)
+	}
+
+	ids := make([]string, 0, len(ticketIDs))
+	for _, ticketID := range ticketIDs {
+		ids = append(ids, ticketID.String())
+	}
+
+	return ids, nil
+}
+
+// toKitchenTicketModel converts a sqlc kitchen ticket row into the domain model
+func toKitchenTicketModel(dbTicket db.ListKitchenTicketsRow) *models.KitchenTicket {
+	return &models.KitchenTicket{
+		ID:           dbTicket.ID.String(),
+		OrderID:      dbTicket.OrderID.String(),
+		OrderNumber:  dbTicket.OrderNumber,
+		OrderItemID:  dbTicket.OrderItemID.String(),
+		StationID:    nullUUIDToStringPtr(dbTicket.StationID),
+		StationName:  nullStringToPtr(dbTicket.StationName),
+		MenuItemName: dbTicket.MenuItemName,
+		Quantity:     int(dbTicket.Quantity),
+		Notes:        nullStringToPtr(dbTicket.Notes),
+		Modifiers:    dbTicket.Modifiers,
+		Status:       types.KitchenStatus(dbTicket.Status),
+		QueuedAt:     dbTicket.QueuedAt,
+		PreparingAt:  nullTimeToPtr(dbTicket.PreparingAt),
+		ReadyAt:      nullTimeToPtr(dbTicket.ReadyAt),
+		ServedAt:     nullTimeToPtr(dbTicket.ServedAt),
+		BumpedBy:     nullUUIDToStringPtr(dbTicket.BumpedBy),
+	}
+}
+
+// GetTicket retrieves a kitchen ticket by ID
+func (r *kitchenRepo) GetTicket(id string) (*models.KitchenTicket, error) {
+	ticketID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kitchen ticket ID: %w", err)
+	}
+
+	dbTicket, err := r.queries.GetKitchenTicket(context.Background(), ticketID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("kitchen ticket not found")
+		}
+		return nil, fmt.Errorf("failed to fetch kitchen ticket from database: %w", err)
+	}
+
+	return toKitchenTicketModel(db.ListKitchenTicketsRow(dbTicket)), nil
+}
+
+// ListTickets retrieves kitchen tickets in the order they reached the kitchen
+func (r *kitchenRepo) ListTickets(filter models.KitchenTicketFilter) ([]*models.KitchenTicket, error) {
+	stationID, err := stringPtrToNullUUID(filter.StationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kitchen station ID: %w", err)
+	}
+
+	orderID, err := stringPtrToNullUUID(filter.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	var status sql.NullString
+	if filter.Status != nil {
+		status = sql.NullString{String: string(*filter.Status), Valid: true}
+	}
+
+	dbTickets, err := r.queries.ListKitchenTickets(context.Background(), db.ListKitchenTicketsParams{
+		StationID: stationID,
+		OrderID:   orderID,
+		Status:    status,
+		Limit:     int32(filter.Limit),
+		Offset:    int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch kitchen tickets from database: %w", err)
+	}
+
+	tickets := make([]*models.KitchenTicket, 0, len(dbTickets))
+	for _, dbTicket := range dbTickets {
+		tickets = append(tickets, toKitchenTicketModel(dbTicket))
+	}
+
+	return tickets, nil
+}
+
+// BumpTicket moves a line from its current status to a later one, stamping the
+// time. It returns ErrKitchenTicketChanged if the line is no longer in the
+// status it was read in.
+func (r *kitchenRepo) BumpTicket(id string, from, to types.KitchenStatus, userID string) error {
+	ticketID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid kitchen ticket ID: %w", err)
+	}
+
+	bumpedBy, err := stringPtrToNullUUID(&userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	_, err = r.queries.BumpKitchenTicket(context.Background(), db.BumpKitchenTicketParams{
+		ID:            ticketID,
+		Status:        string(to),
+		BumpedBy:      bumpedBy,
+		CurrentStatus: string(from),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrKitchenTicketChanged
+		}
+		return fmt.Errorf("failed to bump kitchen ticket in database: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteOpenTicketsByOrderID takes the lines of an order that have not been
+// served off the kitchen display
+func (r *kitchenRepo) DeleteOpenTicketsByOrderID(orderID string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	if err := r.queries.DeleteOpenKitchenTicketsByOrderID(context.Background(), orderUUID); err != nil {
+		return fmt.Errorf("failed to delete kitchen tickets from database: %w", err)
+	}
+
+	return nil
+}
+
+// MoveTicket keeps a line's kitchen ticket with the line when it moves to
+// another order
+func (r *kitchenRepo) MoveTicket(orderItemID, orderID string) error {
+	orderItemUUID, err := uuid.Parse(orderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	err = r.queries.MoveKitchenTicket(context.Background(), db.MoveKitchenTicketParams{
+		OrderItemID: orderItemUUID,
+		OrderID:     orderUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move kitchen ticket in database: %w", err)
+	}
+
+	return nil
+}
+
+// CopyTicket gives a line split off another a ticket at the same stage as the
+// original's. Nothing is created if the original is not in the kitchen.
+func (r *kitchenRepo) CopyTicket(sourceOrderItemID, orderItemID string) error {
+	sourceUUID, err := uuid.Parse(sourceOrderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	orderItemUUID, err := uuid.Parse(orderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	err = r.queries.CopyKitchenTicket(context.Background(), db.CopyKitchenTicketParams{
+		OrderItemID:       orderItemUUID,
+		SourceOrderItemID: sourceUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy kitchen ticket in database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// lowStockAlertRepo implements the LowStockAlertRepo interface
+type lowStockAlertRepo struct {
+	queries *db.Queries
+}
+
+// toLowStockAlertModel converts a sqlc low stock alert row into the domain model
+func toLowStockAlertModel(dbAlert db.LowStockAlert) (*models.LowStockAlert, error) {
+	currentStock, err := parseStockQuantity(dbAlert.CurrentStock)
+	if err != nil {
+		return nil, err
+	}
+	minimumStock, err := parseStockQuantity(dbAlert.MinimumStock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LowStockAlert{
+		ID:                 dbAlert.ID.String(),
+		MenuItemID:         nullUUIDToStringPtr(dbAlert.MenuItemID),
+		IngredientID:       nullUUIDToStringPtr(dbAlert.IngredientID),
+		ItemName:           dbAlert.ItemName,
+		Unit:               dbAlert.Unit,
+		CurrentStock:       currentStock,
+		MinimumStock:       minimumStock,
+		StockTransactionID: dbAlert.StockTransactionID.String(),
+		RaisedAt:           dbAlert.RaisedAt,
+		ResolvedAt:         nullTimeToPtr(dbAlert.ResolvedAt),
+		ReadAt:             nullTimeToPtr(dbAlert.ReadAt),
+		ReadBy:             nullUUIDToStringPtr(dbAlert.ReadBy),
+		DispatchedAt:       nullTimeToPtr(dbAlert.DispatchedAt),
+		DispatchAttempts:   int(dbAlert.DispatchAttempts),
+		LastError:          nullStringToPtr(dbAlert.LastError),
+	}, nil
+}
+
+// toLowStockAlertModels converts sqlc low stock alert rows into domain models
+func toLowStockAlertModels(dbAlerts []db.LowStockAlert) ([]*models.LowStockAlert, error) {
+	alerts := make([]*models.LowStockAlert, 0, len(dbAlerts))
+	for _, dbAlert := range dbAlerts {
+		alert, err := toLowStockAlertModel(dbAlert)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// SyncLowStockAlert closes the open alert of a menu item or an ingredient that
+// a stock movement took back above its minimum stock, or raises one when the
+// movement took it from above its minimum to it or below
+func (r *lowStockAlertRepo) SyncLowStockAlert(stockTransactionID, menuItemID string, ingredientID *string, previousStock types.DecimalText) error {
+	transactionID, err := uuid.Parse(stockTransactionID)
+	if err != nil {
+		return fmt.Errorf("invalid stock transaction ID: %w", err)
+	}
+
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.ResolveLowStockAlerts(context.Background(), db.ResolveLowStockAlertsParams{
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve low stock alerts in database: %w", err)
+	}
+
+	_, err = r.queries.RaiseLowStockAlert(context.Background(), db.RaiseLowStockAlertParams{
+		StockTransactionID: transactionID,
+		MenuItemID:         menuItemUUID,
+		PreviousStock:      previousStock.String(),
+		IngredientID:       ingredientUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to raise low stock alert in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListLowStockAlerts retrieves low stock alerts, most recent first
+func (r *lowStockAlertRepo) ListLowStockAlerts(filter models.LowStockAlertFilter) ([]*models.LowStockAlert, error) {
+	dbAlerts, err := r.queries.ListLowStockAlerts(context.Background(), db.ListLowStockAlertsParams{
+		OpenOnly:   filter.OpenOnly,
+		UnreadOnly: filter.UnreadOnly,
+		Limit:      int32(filter.Limit),
+		Offset:     int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low stock alerts from database: %w", err)
+	}
+
+	return toLowStockAlertModels(dbAlerts)
+}
+
+// CountUnreadLowStockAlerts counts the low stock alerts nobody has read
+func (r *lowStockAlertRepo) CountUnreadLowStockAlerts() (int64, error) {
+	count, err := r.queries.CountUnreadLowStockAlerts(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread low stock alerts in database: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkLowStockAlertRead marks a low stock alert read by a user
+func (r *lowStockAlertRepo) MarkLowStockAlertRead(id, userID string) (*models.LowStockAlert, error) {
+	alertID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid low stock alert ID: %w", err)
+	}
+	readBy, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbAlert, err := r.queries.MarkLowStockAlertRead(context.Background(), db.MarkLowStockAlertReadParams{
+		ID:     alertID,
+		ReadBy: uuid.NullUUID{UUID: readBy, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("low stock alert not found")
+		}
+		return nil, fmt.Errorf("failed to mark low stock alert read in database: %w", err)
+	}
+
+	return toLowStockAlertModel(dbAlert)
+}
+
+// ClaimLowStockAlerts claims up to batchSize open alerts due to be delivered
+// that have been tried fewer than maxAttempts times
+func (r *lowStockAlertRepo) ClaimLowStockAlerts(maxAttempts, batchSize int) ([]*models.LowStockAlert, error) {
+	dbAlerts, err := r.queries.ClaimLowStockAlerts(context.Background(), db.ClaimLowStockAlertsParams{
+		MaxAttempts: int32(maxAttempts),
+		BatchSize:   int32(batchSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim low stock alerts in database: %w", err)
+	}
+
+	return toLowStockAlertModels(dbAlerts)
+}
+
+// ListLowStockAlertDeliveries retrieves the channels a low stock alert has
+// been delivered through
+func (r *lowStockAlertRepo) ListLowStockAlertDeliveries(alertID string) ([]string, error) {
+	parsedID, err := uuid.Parse(alertID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid low stock alert ID: %w", err)
+	}
+
+	channels, err := r.queries.ListLowStockAlertDeliveries(context.Background(), parsedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low stock alert deliveries from database: %w", err)
+	}
+
+	return channels, nil
+}
+
+// CreateLowStockAlertDelivery records that a low stock alert was delivered
+// through a channel
+func (r *lowStockAlertRepo) CreateLowStockAlertDelivery(alertID, channel string) error {
+	parsedID, err := uuid.Parse(alertID)
+	if err != nil {
+		return fmt.Errorf("invalid low stock alert ID: %w", err)
+	}
+
+	err = r.queries.CreateLowStockAlertDelivery(context.Background(), db.CreateLowStockAlertDeliveryParams{
+		AlertID: parsedID,
+		Channel: channel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create low stock alert delivery in database: %w", err)
+	}
+
+	return nil
+}
+
+// MarkLowStockAlertDispatched records that a low stock alert needs no more
+// delivery attempts
+func (r *lowStockAlertRepo) MarkLowStockAlertDispatched(id string) error {
+	alertID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid low stock alert ID: %w", err)
+	}
+
+	if err := r.queries.MarkLowStockAlertDispatched(context.Background(), alertID); err != nil {
+		return fmt.Errorf("failed to mark low stock alert dispatched in database: %w", err)
+	}
+
+	return nil
+}
+
+// SetLowStockAlertError records why the last delivery attempt of a low stock
+// alert failed
+func (r *lowStockAlertRepo) SetLowStockAlertError(id, message string) error {
+	alertID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid low stock alert ID: %w", err)
+	}
+
+	err = r.queries.SetLowStockAlertError(context.Background(), db.SetLowStockAlertErrorParams{
+		ID:        alertID,
+		LastError: sql.NullString{String: message, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record low stock alert error in database: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/AndikaPrasetia/pos-cafee/internal/db"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
@@ -206,6 +207,7 @@ func (r *menuRepo) GetMenuItem(id string) (*models.MenuItem, error) {
 		Name:        dbMenuItem.Name,
 		CategoryID:  dbMenuItem.CategoryID.String(),
 		IsAvailable: dbMenuItem.IsAvailable,
+		SoldOut:     dbMenuItem.SoldOut,
 		CreatedAt:   dbMenuItem.CreatedAt,
 		UpdatedAt:   dbMenuItem.UpdatedAt,
 		Price:       types.DecimalText(price),
@@ -248,6 +250,7 @@ func (r *menuRepo) ListMenuItems(isAvailable bool, limit, offset int) ([]*models
 			Name:        dbMenuItem.Name,
 			CategoryID:  dbMenuItem.CategoryID.String(),
 			IsAvailable: dbMenuItem.IsAvailable,
+			SoldOut:     dbMenuItem.SoldOut,
 			CreatedAt:   dbMenuItem.CreatedAt,
 			UpdatedAt:   dbMenuItem.UpdatedAt,
 			Price:       types.DecimalText(price),
@@ -298,6 +301,7 @@ func (r *menuRepo) ListMenuItemsByCategory(categoryID string, limit, offset int)
 			Name:        dbMenuItem.Name,
 			CategoryID:  dbMenuItem.CategoryID.String(),
 			IsAvailable: dbMenuItem.IsAvailable,
+			SoldOut:     dbMenuItem.SoldOut,
 			CreatedAt:   dbMenuItem.CreatedAt,
 			UpdatedAt:   dbMenuItem.UpdatedAt,
 			Price:       types.DecimalText(price),
@@ -346,6 +350,7 @@ func (r *menuRepo) CreateMenuItem(item *models.MenuItem) (*models.MenuItem, erro
 		Name:        dbMenuItem.Name,
 		CategoryID:  dbMenuItem.CategoryID.String(),
 		IsAvailable: dbMenuItem.IsAvailable,
+		SoldOut:     dbMenuItem.SoldOut,
 		CreatedAt:   dbMenuItem.CreatedAt,
 		UpdatedAt:   dbMenuItem.UpdatedAt,
 		Price:       types.DecimalText(decimal.RequireFromString(dbMenuItem.Price)),
@@ -393,6 +398,7 @@ func (r *menuRepo) UpdateMenuItem(item *models.MenuItem) (*models.MenuItem, erro
 		Price:       item.Price.String(),
 		Cost:        item.Cost.String(),
 		IsAvailable: item.IsAvailable,
+		SoldOut:     item.SoldOut,
 	})
 	if err != nil {
 		return nil, err
@@ -403,6 +409,7 @@ func (r *menuRepo) UpdateMenuItem(item *models.MenuItem) (*models.MenuItem, erro
 		Name:        dbMenuItem.Name,
 		CategoryID:  dbMenuItem.CategoryID.String(),
 		IsAvailable: dbMenuItem.IsAvailable,
+		SoldOut:     dbMenuItem.SoldOut,
 		CreatedAt:   dbMenuItem.CreatedAt,
 		UpdatedAt:   dbMenuItem.UpdatedAt,
 		Price:       types.DecimalText(decimal.RequireFromString(dbMenuItem.Price)),
@@ -417,6 +424,58 @@ func (r *menuRepo) UpdateMenuItem(item *models.MenuItem) (*models.MenuItem, erro
 	return updatedMenuItem, nil
 }
 
+// UpdateMenuItemCost sets the cost of a menu item bought in as finished goods
+func (r *menuRepo) UpdateMenuItemCost(id string, cost types.DecimalText) error {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	_, err = r.queries.UpdateMenuItemCost(context.Background(), db.UpdateMenuItemCostParams{
+		ID:   itemID,
+		Cost: cost.String(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("menu item not found")
+		}
+		return fmt.Errorf("failed to update menu item cost in database: %w", err)
+	}
+
+	return nil
+}
+
+// SyncMenuItemAvailability sells out the menu items made from the stock of a
+// menu item or an ingredient, or puts them back on the menu, to match what the
+// selling location holds. It returns the items whose availability changed.
+func (r *menuRepo) SyncMenuItemAvailability(menuItemID string, ingredientID *string) ([]*models.MenuItemAvailability, error) {
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbAvailability, err := r.queries.SyncMenuItemAvailability(context.Background(), db.SyncMenuItemAvailabilityParams{
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update menu item availability in database: %w", err)
+	}
+
+	var changes []*models.MenuItemAvailability
+	for _, item := range dbAvailability {
+		changes = append(changes, &models.MenuItemAvailability{
+			MenuItemID:  item.ID.String(),
+			Name:        item.Name,
+			CategoryID:  item.CategoryID.String(),
+			IsAvailable: item.IsAvailable,
+			SoldOut:     item.SoldOut,
+		})
+	}
+
+	return changes, nil
+}
+
 // DeleteMenuItem deletes a menu item by ID
 func (r *menuRepo) DeleteMenuItem(id string) error {
 	itemID, err := uuid.Parse(id)
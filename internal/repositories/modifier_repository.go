@@ -0,0 +1,413 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// modifierRepo implements the ModifierRepo interface
+type modifierRepo struct {
+	queries *db.Queries
+}
+
+// toModifierGroupModel converts a sqlc modifier group row into the domain model
+func toModifierGroupModel(dbGroup db.ModifierGroup) *models.ModifierGroup {
+	return &models.ModifierGroup{
+		ID:          dbGroup.ID.String(),
+		Name:        dbGroup.Name,
+		Description: dbGroup.Description.String,
+		MinSelect:   int(dbGroup.MinSelect),
+		MaxSelect:   int(dbGroup.MaxSelect),
+		IsActive:    dbGroup.IsActive,
+		CreatedAt:   dbGroup.CreatedAt,
+		UpdatedAt:   dbGroup.UpdatedAt,
+		Options:     []models.ModifierOption{},
+	}
+}
+
+// toModifierOptionModel converts a sqlc modifier option row into the domain model
+func toModifierOptionModel(dbOption db.ModifierOption) (*models.ModifierOption, error) {
+	priceDelta, err := decimal.NewFromString(dbOption.PriceDelta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse modifier option price %s: %w", dbOption.PriceDelta, err)
+	}
+
+	return &models.ModifierOption{
+		ID:              dbOption.ID.String(),
+		ModifierGroupID: dbOption.ModifierGroupID.String(),
+		Name:            dbOption.Name,
+		PriceDelta:      types.DecimalText(priceDelta),
+		IsAvailable:     dbOption.IsAvailable,
+		SortOrder:       int(dbOption.SortOrder),
+		CreatedAt:       dbOption.CreatedAt,
+		UpdatedAt:       dbOption.UpdatedAt,
+	}, nil
+}
+
+// withOptions loads the options of each modifier group
+func (r *modifierRepo) withOptions(dbGroups []db.ModifierGroup) ([]*models.ModifierGroup, error) {
+	groups := make([]*models.ModifierGroup, 0, len(dbGroups))
+	for _, dbGroup := range dbGroups {
+		group := toModifierGroupModel(dbGroup)
+
+		dbOptions, err := r.queries.ListModifierOptionsByGroupID(context.Background(), dbGroup.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch modifier options from database: %w", err)
+		}
+
+		for _, dbOption := range dbOptions {
+			option, err := toModifierOptionModel(dbOption)
+			if err != nil {
+				return nil, err
+			}
+			group.Options = append(group.Options, *option)
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// CreateModifierGroup creates a new modifier group
+func (r *modifierRepo) CreateModifierGroup(group *models.ModifierGroup) (*models.ModifierGroup, error) {
+	dbGroup, err := r.queries.CreateModifierGroup(context.Background(), db.CreateModifierGroupParams{
+		Name:        group.Name,
+		Description: sql.NullString{String: group.Description, Valid: group.Description != ""},
+		MinSelect:   int32(group.MinSelect),
+		MaxSelect:   int32(group.MaxSelect),
+		IsActive:    group.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create modifier group in database: %w", err)
+	}
+
+	return toModifierGroupModel(dbGroup), nil
+}
+
+// GetModifierGroup retrieves a modifier group with its options
+func (r *modifierRepo) GetModifierGroup(id string) (*models.ModifierGroup, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	dbGroup, err := r.queries.GetModifierGroup(context.Background(), groupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("modifier group not found")
+		}
+		return nil, fmt.Errorf("failed to fetch modifier group from database: %w", err)
+	}
+
+	groups, err := r.withOptions([]db.ModifierGroup{dbGroup})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups[0], nil
+}
+
+// ListModifierGroups retrieves a list of modifier groups with their options
+func (r *modifierRepo) ListModifierGroups(filter models.ModifierGroupFilter) ([]*models.ModifierGroup, error) {
+	var isActive sql.NullBool
+	if filter.IsActive != nil {
+		isActive = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+
+	dbGroups, err := r.queries.ListModifierGroups(context.Background(), db.ListModifierGroupsParams{
+		IsActive: isActive,
+		Limit:    int32(filter.Limit),
+		Offset:   int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch modifier groups from database: %w", err)
+	}
+
+	return r.withOptions(dbGroups)
+}
+
+// UpdateModifierGroup updates an existing modifier group
+func (r *modifierRepo) UpdateModifierGroup(group *models.ModifierGroup) (*models.ModifierGroup, error) {
+	groupID, err := uuid.Parse(group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	dbGroup, err := r.queries.UpdateModifierGroup(context.Background(), db.UpdateModifierGroupParams{
+		ID:          groupID,
+		Name:        group.Name,
+		Description: sql.NullString{String: group.Description, Valid: group.Description != ""},
+		MinSelect:   int32(group.MinSelect),
+		MaxSelect:   int32(group.MaxSelect),
+		IsActive:    group.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("modifier group not found")
+		}
+		return nil, fmt.Errorf("failed to update modifier group in database: %w", err)
+	}
+
+	groups, err := r.withOptions([]db.ModifierGroup{dbGroup})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups[0], nil
+}
+
+// DeleteModifierGroup deletes a modifier group and its options. Order lines keep
+// the names and prices of the options they used.
+func (r *modifierRepo) DeleteModifierGroup(id string) error {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	if err := r.queries.DeleteModifierGroup(context.Background(), groupID); err != nil {
+		return fmt.Errorf("failed to delete modifier group from database: %w", err)
+	}
+
+	return nil
+}
+
+// CreateModifierOption adds an option to a modifier group
+func (r *modifierRepo) CreateModifierOption(option *models.ModifierOption) (*models.ModifierOption, error) {
+	groupID, err := uuid.Parse(option.ModifierGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	dbOption, err := r.queries.CreateModifierOption(context.Background(), db.CreateModifierOptionParams{
+		ModifierGroupID: groupID,
+		Name:            option.Name,
+		PriceDelta:      option.PriceDelta.String(),
+		IsAvailable:     option.IsAvailable,
+		SortOrder:       int32(option.SortOrder),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create modifier option in database: %w", err)
+	}
+
+	return toModifierOptionModel(dbOption)
+}
+
+// GetModifierOption retrieves a modifier option by ID
+func (r *modifierRepo) GetModifierOption(id string) (*models.ModifierOption, error) {
+	optionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+
+	dbOption, err := r.queries.GetModifierOption(context.Background(), optionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("modifier option not found")
+		}
+		return nil, fmt.Errorf("failed to fetch modifier option from database: %w", err)
+	}
+
+	return toModifierOptionModel(dbOption)
+}
+
+// UpdateModifierOption updates an existing modifier option
+func (r *modifierRepo) UpdateModifierOption(option *models.ModifierOption) (*models.ModifierOption, error) {
+	optionID, err := uuid.Parse(option.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+
+	dbOption, err := r.queries.UpdateModifierOption(context.Background(), db.UpdateModifierOptionParams{
+		ID:          optionID,
+		Name:        option.Name,
+		PriceDelta:  option.PriceDelta.String(),
+		IsAvailable: option.IsAvailable,
+		SortOrder:   int32(option.SortOrder),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("modifier option not found")
+		}
+		return nil, fmt.Errorf("failed to update modifier option in database: %w", err)
+	}
+
+	return toModifierOptionModel(dbOption)
+}
+
+// DeleteModifierOption deletes a modifier option
+func (r *modifierRepo) DeleteModifierOption(id string) error {
+	optionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+
+	if err := r.queries.DeleteModifierOption(context.Background(), optionID); err != nil {
+		return fmt.Errorf("failed to delete modifier option from database: %w", err)
+	}
+
+	return nil
+}
+
+// AttachModifierGroup offers a modifier group with a menu item, or moves it if
+// it is already offered
+func (r *modifierRepo) AttachModifierGroup(menuItemID, groupID string, sortOrder int) error {
+	menuItemUUID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	groupUUID, err := uuid.Parse(groupID)
+	if err != nil {
+		return fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	err = r.queries.AttachModifierGroup(context.Background(), db.AttachModifierGroupParams{
+		MenuItemID:      menuItemUUID,
+		ModifierGroupID: groupUUID,
+		SortOrder:       int32(sortOrder),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach modifier group in database: %w", err)
+	}
+
+	return nil
+}
+
+// DetachModifierGroup stops offering a modifier group with a menu item
+func (r *modifierRepo) DetachModifierGroup(menuItemID, groupID string) error {
+	menuItemUUID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	groupUUID, err := uuid.Parse(groupID)
+	if err != nil {
+		return fmt.Errorf("invalid modifier group ID: %w", err)
+	}
+
+	err = r.queries.DetachModifierGroup(context.Background(), db.DetachModifierGroupParams{
+		MenuItemID:      menuItemUUID,
+		ModifierGroupID: groupUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach modifier group in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListMenuItemModifierGroups retrieves the active modifier groups offered with a
+// menu item, with their options, in display order
+func (r *modifierRepo) ListMenuItemModifierGroups(menuItemID string) ([]*models.ModifierGroup, error) {
+	menuItemUUID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	dbGroups, err := r.queries.ListModifierGroupsByMenuItemID(context.Background(), menuItemUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch menu item modifier groups from database: %w", err)
+	}
+
+	return r.withOptions(dbGroups)
+}
+
+// toOrderItemModifierModel converts a sqlc order item modifier row into the domain model
+func toOrderItemModifierModel(dbModifier db.OrderItemModifier) (*models.OrderItemModifier, error) {
+	priceDelta, err := decimal.NewFromString(dbModifier.PriceDelta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse modifier price %s: %w", dbModifier.PriceDelta, err)
+	}
+
+	return &models.OrderItemModifier{
+		ID:               dbModifier.ID.String(),
+		OrderItemID:      dbModifier.OrderItemID.String(),
+		ModifierOptionID: nullUUIDToStringPtr(dbModifier.ModifierOptionID),
+		GroupName:        dbModifier.GroupName,
+		OptionName:       dbModifier.OptionName,
+		PriceDelta:       types.DecimalText(priceDelta),
+		CreatedAt:        dbModifier.CreatedAt,
+	}, nil
+}
+
+// CreateOrderItemModifier records a modifier option chosen for an order line
+func (r *modifierRepo) CreateOrderItemModifier(modifier *models.OrderItemModifier) (*models.OrderItemModifier, error) {
+	orderItemID, err := uuid.Parse(modifier.OrderItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	optionID, err := stringPtrToNullUUID(modifier.ModifierOptionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+
+	dbModifier, err := r.queries.CreateOrderItemModifier(context.Background(), db.CreateOrderItemModifierParams{
+		OrderItemID:      orderItemID,
+		ModifierOptionID: optionID,
+		GroupName:        modifier.GroupName,
+		OptionName:       modifier.OptionName,
+		PriceDelta:       modifier.PriceDelta.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order item modifier in database: %w", err)
+	}
+
+	return toOrderItemModifierModel(dbModifier)
+}
+
+// ListOrderItemModifiers retrieves the modifiers chosen for every line of an order
+func (r *modifierRepo) ListOrderItemModifiers(orderID string) ([]*models.OrderItemModifier, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbModifiers, err := r.queries.ListOrderItemModifiersByOrderID(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order item modifiers from database: %w", err)
+	}
+
+	modifiers := make([]*models.OrderItemModifier, 0, len(dbModifiers))
+	for _, dbModifier := range dbModifiers {
+		modifier, err := toOrderItemModifierModel(dbModifier)
+		if err != nil {
+			return nil, err
+		}
+		modifiers = append(modifiers, modifier)
+	}
+
+	return modifiers, nil
+}
+
+// CopyOrderItemModifiers gives a line the modifiers chosen for another
+func (r *modifierRepo) CopyOrderItemModifiers(sourceOrderItemID, orderItemID string) error {
+	sourceUUID, err := uuid.Parse(sourceOrderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	orderItemUUID, err := uuid.Parse(orderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	err = r.queries.CopyOrderItemModifiers(context.Background(), db.CopyOrderItemModifiersParams{
+		OrderItemID:       orderItemUUID,
+		SourceOrderItemID: sourceUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy order item modifiers in database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Helpers for converting between optional model fields and sqlc nullable columns
+
+func ptrToNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func nullStringToPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func intPtrToNullInt32(value *int) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*value), Valid: true}
+}
+
+func nullInt32ToIntPtr(value sql.NullInt32) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int32)
+	return &i
+}
+
+func timePtrToNullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+func nullTimeToPtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func stringPtrToNullUUID(value *string) (uuid.NullUUID, error) {
+	if value == nil || *value == "" {
+		return uuid.NullUUID{}, nil
+	}
+	parsed, err := uuid.Parse(*value)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: parsed, Valid: true}, nil
+}
+
+func nullUUIDToStringPtr(value uuid.NullUUID) *string {
+	if !value.Valid {
+		return nil
+	}
+	s := value.UUID.String()
+	return &s
+}
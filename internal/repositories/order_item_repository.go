@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/db"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
@@ -49,6 +50,7 @@ func (r *orderItemRepo) GetOrderItem(id string) (*models.OrderItem, error) {
 		Quantity:   int(dbOrderItem.Quantity),
 		UnitPrice:  types.DecimalText(unitPrice),
 		TotalPrice: types.DecimalText(totalPrice),
+		Notes:      nullStringToPtr(dbOrderItem.Notes),
 	}
 
 	return orderItem, nil
@@ -85,6 +87,7 @@ func (r *orderItemRepo) GetOrderItemsByOrderID(orderID string) ([]*models.OrderI
 			Quantity:   int(dbOrderItem.Quantity),
 			UnitPrice:  types.DecimalText(unitPrice),
 			TotalPrice: types.DecimalText(totalPrice),
+			Notes:      nullStringToPtr(dbOrderItem.Notes),
 		}
 		orderItems = append(orderItems, orderItem)
 	}
@@ -110,6 +113,7 @@ func (r *orderItemRepo) CreateOrderItem(orderItem *models.OrderItem) (*models.Or
 		Quantity:   int32(orderItem.Quantity),
 		UnitPrice:  orderItem.UnitPrice.String(),
 		TotalPrice: orderItem.TotalPrice.String(),
+		Notes:      ptrToNullString(orderItem.Notes),
 	})
 	if err != nil {
 		return nil, err
@@ -122,6 +126,7 @@ func (r *orderItemRepo) CreateOrderItem(orderItem *models.OrderItem) (*models.Or
 		Quantity:   int(dbOrderItem.Quantity),
 		UnitPrice:  types.DecimalText(decimal.RequireFromString(dbOrderItem.UnitPrice)),
 		TotalPrice: types.DecimalText(decimal.RequireFromString(dbOrderItem.TotalPrice)),
+		Notes:      nullStringToPtr(dbOrderItem.Notes),
 	}
 
 	return createdOrderItem, nil
@@ -139,6 +144,7 @@ func (r *orderItemRepo) UpdateOrderItem(orderItem *models.OrderItem) (*models.Or
 		Quantity:   int32(orderItem.Quantity),
 		UnitPrice:  orderItem.UnitPrice.String(),
 		TotalPrice: orderItem.TotalPrice.String(),
+		Notes:      ptrToNullString(orderItem.Notes),
 	})
 	if err != nil {
 		return nil, err
@@ -151,6 +157,7 @@ func (r *orderItemRepo) UpdateOrderItem(orderItem *models.OrderItem) (*models.Or
 		Quantity:   int(dbOrderItem.Quantity),
 		UnitPrice:  types.DecimalText(decimal.RequireFromString(dbOrderItem.UnitPrice)),
 		TotalPrice: types.DecimalText(decimal.RequireFromString(dbOrderItem.TotalPrice)),
+		Notes:      nullStringToPtr(dbOrderItem.Notes),
 	}
 
 	return updatedOrderItem, nil
@@ -171,6 +178,24 @@ func (r *orderItemRepo) DeleteOrderItem(id string) error {
 	return nil
 }
 
+// MoveOrderItem moves a line to another order
+func (r *orderItemRepo) MoveOrderItem(id, orderID string) error {
+	orderItemID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.MoveOrderItem(context.Background(), db.MoveOrderItemParams{
+		ID:      orderItemID,
+		OrderID: orderUUID,
+	})
+}
+
 // GetOrderItemsWithDetails retrieves order items with menu item details
 func (r *orderItemRepo) GetOrderItemsWithDetails(orderID string) ([]*models.OrderItemWithDetails, error) {
 	orderUUID, err := uuid.Parse(orderID)
@@ -203,9 +228,71 @@ func (r *orderItemRepo) GetOrderItemsWithDetails(orderID string) ([]*models.Orde
 			Quantity:     int(dbItem.Quantity),
 			UnitPrice:    types.DecimalText(unitPrice),
 			TotalPrice:   types.DecimalText(totalPrice),
+			Notes:        nullStringToPtr(dbItem.Notes),
 		}
 		orderItemDetails = append(orderItemDetails, itemDetail)
 	}
 
 	return orderItemDetails, nil
-}
\ No newline at end of file
+}
+
+// CreateOrderItemStockUsage records stock an order line took when its order was completed
+func (r *orderItemRepo) CreateOrderItemStockUsage(usage *models.OrderItemStockUsage) error {
+	orderItemID, err := uuid.Parse(usage.OrderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	menuItemID, err := stringPtrToNullUUID(usage.MenuItemID)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	ingredientID, err := stringPtrToNullUUID(usage.IngredientID)
+	if err != nil {
+		return fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	err = r.queries.CreateOrderItemStockUsage(context.Background(), db.CreateOrderItemStockUsageParams{
+		OrderItemID:  orderItemID,
+		MenuItemID:   menuItemID,
+		IngredientID: ingredientID,
+		Quantity:     decimal.Decimal(usage.Quantity).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record order item stock usage: %w", err)
+	}
+
+	return nil
+}
+
+// ListOrderItemStockUsage returns the stock each line of an order took, keyed by order item ID
+func (r *orderItemRepo) ListOrderItemStockUsage(orderID string) (map[string][]*models.OrderItemStockUsage, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	rows, err := r.queries.ListOrderItemStockUsage(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order item stock usage from database: %w", err)
+	}
+
+	usage := make(map[string][]*models.OrderItemStockUsage)
+	for _, row := range rows {
+		quantity, err := decimal.NewFromString(row.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stock usage quantity: %w", err)
+		}
+
+		orderItemID := row.OrderItemID.String()
+		usage[orderItemID] = append(usage[orderItemID], &models.OrderItemStockUsage{
+			OrderItemID:  orderItemID,
+			MenuItemID:   nullUUIDToStringPtr(row.MenuItemID),
+			IngredientID: nullUUIDToStringPtr(row.IngredientID),
+			Quantity:     types.DecimalText(quantity),
+		})
+	}
+
+	return usage, nil
+}
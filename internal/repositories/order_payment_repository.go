@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// orderPaymentRepo implements the OrderPaymentRepo interface
+type orderPaymentRepo struct {
+	queries *db.Queries
+}
+
+// toOrderPaymentModel converts a sqlc order payment row into the domain model
+func toOrderPaymentModel(dbPayment db.OrderPayment) (*models.OrderPayment, error) {
+	amount, err := decimal.NewFromString(dbPayment.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payment amount %s: %w", dbPayment.Amount, err)
+	}
+
+	changeAmount, err := decimal.NewFromString(dbPayment.ChangeAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse change amount %s: %w", dbPayment.ChangeAmount, err)
+	}
+
+	return &models.OrderPayment{
+		ID:            dbPayment.ID.String(),
+		OrderID:       dbPayment.OrderID.String(),
+		PaymentMethod: types.PaymentMethod(dbPayment.PaymentMethod),
+		Amount:        types.DecimalText(amount),
+		Reference:     nullStringToPtr(dbPayment.Reference),
+		ChangeAmount:  types.DecimalText(changeAmount),
+		CreatedAt:     dbPayment.CreatedAt,
+	}, nil
+}
+
+// CreateOrderPayment records a tender used to settle an order
+func (r *orderPaymentRepo) CreateOrderPayment(payment *models.OrderPayment) (*models.OrderPayment, error) {
+	orderID, err := uuid.Parse(payment.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbPayment, err := r.queries.CreateOrderPayment(context.Background(), db.CreateOrderPaymentParams{
+		OrderID:       orderID,
+		PaymentMethod: string(payment.PaymentMethod),
+		Amount:        payment.Amount.String(),
+		Reference:     ptrToNullString(payment.Reference),
+		ChangeAmount:  payment.ChangeAmount.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order payment in database: %w", err)
+	}
+
+	return toOrderPaymentModel(dbPayment)
+}
+
+// ListOrderPayments retrieves the tenders used to settle an order
+func (r *orderPaymentRepo) ListOrderPayments(orderID string) ([]*models.OrderPayment, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbPayments, err := r.queries.ListOrderPayments(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order payments from database: %w", err)
+	}
+
+	payments := make([]*models.OrderPayment, 0, len(dbPayments))
+	for _, dbPayment := range dbPayments {
+		payment, err := toOrderPaymentModel(dbPayment)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
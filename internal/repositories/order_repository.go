@@ -18,32 +18,44 @@ type orderRepo struct {
 	queries *db.Queries
 }
 
-// GetOrder retrieves an order by ID
-func (r *orderRepo) GetOrder(id string) (*models.Order, error) {
-	orderID, err := uuid.Parse(id)
+// toOrderModel converts a sqlc order row into the domain model
+func toOrderModel(dbOrder db.Order) (*models.Order, error) {
+	totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
 	if err != nil {
 		return nil, err
 	}
 
-	dbOrder, err := r.queries.GetOrder(context.Background(), orderID)
+	discountAmount, err := decimal.NewFromString(dbOrder.DiscountAmount)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("order not found")
-		}
 		return nil, err
 	}
 
-	totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
+	taxAmount, err := decimal.NewFromString(dbOrder.TaxAmount)
 	if err != nil {
 		return nil, err
 	}
-	
-	discountAmount, err := decimal.NewFromString(dbOrder.DiscountAmount)
+
+	subtotalAmount, err := decimal.NewFromString(dbOrder.SubtotalAmount)
 	if err != nil {
 		return nil, err
 	}
-	
-	taxAmount, err := decimal.NewFromString(dbOrder.TaxAmount)
+
+	serviceChargeAmount, err := decimal.NewFromString(dbOrder.ServiceChargeAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceChargeRate, err := decimal.NewFromString(dbOrder.ServiceChargeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	taxRate, err := decimal.NewFromString(dbOrder.TaxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	roundingAmount, err := decimal.NewFromString(dbOrder.RoundingAmount)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +71,18 @@ func (r *orderRepo) GetOrder(id string) (*models.Order, error) {
 		PaymentStatus:  types.PaymentStatus(dbOrder.PaymentStatus),
 		CreatedAt:      dbOrder.CreatedAt,
 		UpdatedAt:      dbOrder.UpdatedAt,
+
+		SubtotalAmount:      types.DecimalText(subtotalAmount),
+		ServiceChargeAmount: types.DecimalText(serviceChargeAmount),
+		ServiceChargeRate:   types.DecimalText(serviceChargeRate),
+		TaxRate:             types.DecimalText(taxRate),
+		RoundingAmount:      types.DecimalText(roundingAmount),
+
+		OrderType:  types.OrderType(dbOrder.OrderType),
+		TableID:    nullUUIDToStringPtr(dbOrder.TableID),
+		GuestCount: nullInt32ToIntPtr(dbOrder.GuestCount),
+		ShiftID:     nullUUIDToStringPtr(dbOrder.ShiftID),
+		VoidShiftID: nullUUIDToStringPtr(dbOrder.VoidShiftID),
 	}
 
 	if dbOrder.PaymentMethod.Valid {
@@ -70,57 +94,78 @@ func (r *orderRepo) GetOrder(id string) (*models.Order, error) {
 		order.CompletedAt = &dbOrder.CompletedAt.Time
 	}
 
+	if dbOrder.CancellationReason.Valid {
+		order.CancellationReason = &dbOrder.CancellationReason.String
+	}
+
+	if dbOrder.CancelledBy.Valid {
+		cancelledBy := dbOrder.CancelledBy.UUID.String()
+		order.CancelledBy = &cancelledBy
+	}
+
+	if dbOrder.CancelledAt.Valid {
+		order.CancelledAt = &dbOrder.CancelledAt.Time
+	}
+
 	return order, nil
 }
 
-// GetOrderByNumber retrieves an order by order number
-func (r *orderRepo) GetOrderByNumber(orderNumber string) (*models.Order, error) {
-	dbOrder, err := r.queries.GetOrderByNumber(context.Background(), orderNumber)
+// GetOrder retrieves an order by ID
+func (r *orderRepo) GetOrder(id string) (*models.Order, error) {
+	orderID, err := uuid.Parse(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("order not found")
-		}
 		return nil, err
 	}
 
-	totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
+	dbOrder, err := r.queries.GetOrder(context.Background(), orderID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("order not found")
+		}
 		return nil, err
 	}
-	
-	discountAmount, err := decimal.NewFromString(dbOrder.DiscountAmount)
+
+	return toOrderModel(dbOrder)
+}
+
+// GetOrderForUpdate retrieves an order by ID and locks its row until the surrounding
+// transaction ends, so concurrent completions or cancellations are serialised
+func (r *orderRepo) GetOrderForUpdate(id string) (*models.Order, error) {
+	orderID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, err
 	}
-	
-	taxAmount, err := decimal.NewFromString(dbOrder.TaxAmount)
+
+	dbOrder, err := r.queries.GetOrderForUpdate(context.Background(), orderID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("order not found")
+		}
 		return nil, err
 	}
 
-	order := &models.Order{
-		ID:             dbOrder.ID.String(),
-		OrderNumber:    dbOrder.OrderNumber,
-		UserID:         dbOrder.UserID.String(),
-		Status:         types.OrderStatus(dbOrder.Status),
-		TotalAmount:    types.DecimalText(totalAmount),
-		DiscountAmount: types.DecimalText(discountAmount),
-		TaxAmount:      types.DecimalText(taxAmount),
-		PaymentStatus:  types.PaymentStatus(dbOrder.PaymentStatus),
-		CreatedAt:      dbOrder.CreatedAt,
-		UpdatedAt:      dbOrder.UpdatedAt,
-	}
+	return toOrderModel(dbOrder)
+}
 
-	if dbOrder.PaymentMethod.Valid {
-		pm := types.PaymentMethod(dbOrder.PaymentMethod.String)
-		order.PaymentMethod = &pm
-	}
+// HoldOffZReports keeps Z reports from being generated until the surrounding
+// transaction ends, so an order completed, voided or refunded in it is not left
+// out of both the period being closed and the next one. Other orders can still
+// change meanwhile.
+func (r *orderRepo) HoldOffZReports() error {
+	return r.queries.HoldOffZReports(context.Background())
+}
 
-	if dbOrder.CompletedAt.Valid {
-		order.CompletedAt = &dbOrder.CompletedAt.Time
+// GetOrderByNumber retrieves an order by order number
+func (r *orderRepo) GetOrderByNumber(orderNumber string) (*models.Order, error) {
+	dbOrder, err := r.queries.GetOrderByNumber(context.Background(), orderNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
 	}
 
-	return order, nil
+	return toOrderModel(dbOrder)
 }
 
 // ListOrders retrieves a list of orders based on filter
@@ -171,43 +216,11 @@ func (r *orderRepo) ListOrders(filter types.OrderFilter) ([]*models.Order, error
 
 	var orders []*models.Order
 	for _, dbOrder := range dbOrders {
-		totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
-		if err != nil {
-			return nil, err
-		}
-		
-		discountAmount, err := decimal.NewFromString(dbOrder.DiscountAmount)
-		if err != nil {
-			return nil, err
-		}
-		
-		taxAmount, err := decimal.NewFromString(dbOrder.TaxAmount)
+		order, err := toOrderModel(dbOrder)
 		if err != nil {
 			return nil, err
 		}
 
-		order := &models.Order{
-			ID:             dbOrder.ID.String(),
-			OrderNumber:    dbOrder.OrderNumber,
-			UserID:         dbOrder.UserID.String(),
-			Status:         types.OrderStatus(dbOrder.Status),
-			TotalAmount:    types.DecimalText(totalAmount),
-			DiscountAmount: types.DecimalText(discountAmount),
-			TaxAmount:      types.DecimalText(taxAmount),
-			PaymentStatus:  types.PaymentStatus(dbOrder.PaymentStatus),
-			CreatedAt:      dbOrder.CreatedAt,
-			UpdatedAt:      dbOrder.UpdatedAt,
-		}
-
-		if dbOrder.PaymentMethod.Valid {
-			pm := types.PaymentMethod(dbOrder.PaymentMethod.String)
-			order.PaymentMethod = &pm
-		}
-
-		if dbOrder.CompletedAt.Valid {
-			order.CompletedAt = &dbOrder.CompletedAt.Time
-		}
-
 		orders = append(orders, order)
 	}
 
@@ -221,40 +234,47 @@ func (r *orderRepo) CreateOrder(order *models.Order) (*models.Order, error) {
 		return nil, err
 	}
 
+	tableID, err := stringPtrToNullUUID(order.TableID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType := order.OrderType
+	if orderType == "" {
+		orderType = types.OrderTypeTakeaway
+	}
+
 	dbOrder, err := r.queries.CreateOrder(context.Background(), db.CreateOrderParams{
 		OrderNumber:    order.OrderNumber,
 		UserID:         userID,
 		TotalAmount:    order.TotalAmount.String(),
 		DiscountAmount: order.DiscountAmount.String(),
 		TaxAmount:      order.TaxAmount.String(),
+		SubtotalAmount: order.SubtotalAmount.String(),
+		OrderType:      string(orderType),
+		TableID:        tableID,
+		GuestCount:     intPtrToNullInt32(order.GuestCount),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	createdOrder := &models.Order{
-		ID:             dbOrder.ID.String(),
-		OrderNumber:    dbOrder.OrderNumber,
-		UserID:         dbOrder.UserID.String(),
-		Status:         types.OrderStatus(dbOrder.Status),
-		TotalAmount:    types.DecimalText(decimal.RequireFromString(dbOrder.TotalAmount)),
-		DiscountAmount: types.DecimalText(decimal.RequireFromString(dbOrder.DiscountAmount)),
-		TaxAmount:      types.DecimalText(decimal.RequireFromString(dbOrder.TaxAmount)),
-		PaymentStatus:  types.PaymentStatus(dbOrder.PaymentStatus),
-		CreatedAt:      dbOrder.CreatedAt,
-		UpdatedAt:      dbOrder.UpdatedAt,
-	}
-
-	if dbOrder.PaymentMethod.Valid {
-		pm := types.PaymentMethod(dbOrder.PaymentMethod.String)
-		createdOrder.PaymentMethod = &pm
-	}
+	return toOrderModel(dbOrder)
+}
 
-	if dbOrder.CompletedAt.Valid {
-		createdOrder.CompletedAt = &dbOrder.CompletedAt.Time
+// NextOrderNumber claims the next sequence number for the prefix on the given
+// business day. Call it inside the transaction that creates the order so that the
+// number is released again if the order is rolled back.
+func (r *orderRepo) NextOrderNumber(prefix string, businessDate time.Time) (int, error) {
+	number, err := r.queries.NextOrderNumber(context.Background(), db.NextOrderNumberParams{
+		Prefix:       prefix,
+		BusinessDate: businessDate,
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return createdOrder, nil
+	return int(number), nil
 }
 
 // UpdateOrderStatus updates the status of an order
@@ -275,6 +295,61 @@ func (r *orderRepo) UpdateOrderStatus(orderID string, status string) error {
 	return nil
 }
 
+// CancelOrder marks an order as cancelled and records who cancelled it and why,
+// and for a voided sale the cash drawer shift it was voided in
+func (r *orderRepo) CancelOrder(orderID string, paymentStatus string, reason *string, cancelledBy string, voidShiftID *string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(cancelledBy)
+	if err != nil {
+		return err
+	}
+
+	shiftUUID, err := stringPtrToNullUUID(voidShiftID)
+	if err != nil {
+		return err
+	}
+
+	var reasonNull sql.NullString
+	if reason != nil {
+		reasonNull = sql.NullString{
+			String: *reason,
+			Valid:  true,
+		}
+	}
+
+	return r.queries.CancelOrder(context.Background(), db.CancelOrderParams{
+		ID:                 orderUUID,
+		PaymentStatus:      paymentStatus,
+		CancellationReason: reasonNull,
+		CancelledBy:        uuid.NullUUID{UUID: userUUID, Valid: true},
+		VoidShiftID:        shiftUUID,
+	})
+}
+
+// MergeOrder closes an order whose lines were moved into another order,
+// recording who merged it and why
+func (r *orderRepo) MergeOrder(orderID string, reason string, mergedBy string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+
+	userUUID, err := uuid.Parse(mergedBy)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.MergeOrder(context.Background(), db.MergeOrderParams{
+		ID:                 orderUUID,
+		CancellationReason: sql.NullString{String: reason, Valid: true},
+		CancelledBy:        uuid.NullUUID{UUID: userUUID, Valid: true},
+	})
+}
+
 // UpdateOrderPayment updates payment information for an order
 func (r *orderRepo) UpdateOrderPayment(orderID string, paymentMethod, paymentStatus string, completedAt *string) error {
 	orderUUID, err := uuid.Parse(orderID)
@@ -307,22 +382,79 @@ func (r *orderRepo) UpdateOrderPayment(orderID string, paymentMethod, paymentSta
 	return nil
 }
 
-// UpdateOrderTotal updates total amounts for an order
-func (r *orderRepo) UpdateOrderTotal(orderID string, totalAmount, discountAmount, taxAmount string) error {
-	orderUUID, err := uuid.Parse(orderID)
+// UpdateOrderTotal stores the price breakdown of an order
+func (r *orderRepo) UpdateOrderTotal(order *models.Order) error {
+	orderUUID, err := uuid.Parse(order.ID)
 	if err != nil {
 		return err
 	}
 
 	err = r.queries.UpdateOrderTotal(context.Background(), db.UpdateOrderTotalParams{
-		ID:             orderUUID,
-		TotalAmount:    totalAmount,
-		DiscountAmount: discountAmount,
-		TaxAmount:      taxAmount,
+		ID:                  orderUUID,
+		TotalAmount:         order.TotalAmount.String(),
+		DiscountAmount:      order.DiscountAmount.String(),
+		TaxAmount:           order.TaxAmount.String(),
+		SubtotalAmount:      order.SubtotalAmount.String(),
+		ServiceChargeAmount: order.ServiceChargeAmount.String(),
+		RoundingAmount:      order.RoundingAmount.String(),
+		ServiceChargeRate:   order.ServiceChargeRate.String(),
+		TaxRate:             order.TaxRate.String(),
 	})
 	if err != nil {
 		return err
 	}
 
 	return nil
+}
+
+// UpdateOrderTable stores how an order is served, the table it is open on and
+// its guest count
+func (r *orderRepo) UpdateOrderTable(order *models.Order) error {
+	orderUUID, err := uuid.Parse(order.ID)
+	if err != nil {
+		return err
+	}
+
+	tableID, err := stringPtrToNullUUID(order.TableID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpdateOrderTable(context.Background(), db.UpdateOrderTableParams{
+		ID:         orderUUID,
+		OrderType:  string(order.OrderType),
+		TableID:    tableID,
+		GuestCount: intPtrToNullInt32(order.GuestCount),
+	})
+}
+
+// UpdateOrderShift links an order to the cash drawer shift it was completed in
+func (r *orderRepo) UpdateOrderShift(orderID, shiftID string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpdateOrderShift(context.Background(), db.UpdateOrderShiftParams{
+		ID:      orderUUID,
+		ShiftID: uuid.NullUUID{UUID: shiftUUID, Valid: true},
+	})
+}
+
+// UpdateOrderPaymentStatus updates only the payment status of an order, e.g. after a refund
+func (r *orderRepo) UpdateOrderPaymentStatus(orderID string, paymentStatus string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpdateOrderPaymentStatus(context.Background(), db.UpdateOrderPaymentStatusParams{
+		ID:            orderUUID,
+		PaymentStatus: paymentStatus,
+	})
 }
\ No newline at end of file
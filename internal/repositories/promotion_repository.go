@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrPromotionUsageLimitReached is returned when a promotion has no redemptions left
+var ErrPromotionUsageLimitReached = errors.New("promotion usage limit reached")
+
+// promotionRepo implements the PromotionRepo interface
+type promotionRepo struct {
+	queries *db.Queries
+}
+
+// toPromotionModel converts a sqlc promotion row into the domain model
+func toPromotionModel(dbPromotion db.Promotion) (*models.Promotion, error) {
+	value, err := decimal.NewFromString(dbPromotion.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse promotion value %s: %w", dbPromotion.Value, err)
+	}
+
+	minSpend, err := decimal.NewFromString(dbPromotion.MinSpend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse promotion minimum spend %s: %w", dbPromotion.MinSpend, err)
+	}
+
+	promotion := &models.Promotion{
+		ID:             dbPromotion.ID.String(),
+		Name:           dbPromotion.Name,
+		Description:    dbPromotion.Description.String,
+		Type:           types.PromotionType(dbPromotion.PromotionType),
+		Value:          types.DecimalText(value),
+		BuyQuantity:    nullInt32ToIntPtr(dbPromotion.BuyQuantity),
+		GetQuantity:    nullInt32ToIntPtr(dbPromotion.GetQuantity),
+		TargetType:     types.PromotionTarget(dbPromotion.TargetType),
+		TargetID:       nullUUIDToStringPtr(dbPromotion.TargetID),
+		VoucherCode:    nullStringToPtr(dbPromotion.VoucherCode),
+		MinSpend:       types.DecimalText(minSpend),
+		DailyStartTime: nullStringToPtr(dbPromotion.DailyStartTime),
+		DailyEndTime:   nullStringToPtr(dbPromotion.DailyEndTime),
+		UsageLimit:     nullInt32ToIntPtr(dbPromotion.UsageLimit),
+		UsageCount:     int(dbPromotion.UsageCount),
+		IsStackable:    dbPromotion.IsStackable,
+		Priority:       int(dbPromotion.Priority),
+		IsActive:       dbPromotion.IsActive,
+		CreatedBy:      nullUUIDToStringPtr(dbPromotion.CreatedBy),
+		CreatedAt:      dbPromotion.CreatedAt,
+		UpdatedAt:      dbPromotion.UpdatedAt,
+	}
+
+	if dbPromotion.StartsAt.Valid {
+		promotion.StartsAt = &dbPromotion.StartsAt.Time
+	}
+
+	if dbPromotion.EndsAt.Valid {
+		promotion.EndsAt = &dbPromotion.EndsAt.Time
+	}
+
+	return promotion, nil
+}
+
+// CreatePromotion creates a new promotion
+func (r *promotionRepo) CreatePromotion(promotion *models.Promotion) (*models.Promotion, error) {
+	targetID, err := stringPtrToNullUUID(promotion.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target ID: %w", err)
+	}
+
+	createdBy, err := stringPtrToNullUUID(promotion.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbPromotion, err := r.queries.CreatePromotion(context.Background(), db.CreatePromotionParams{
+		Name:           promotion.Name,
+		Description:    sql.NullString{String: promotion.Description, Valid: promotion.Description != ""},
+		PromotionType:  string(promotion.Type),
+		Value:          promotion.Value.String(),
+		BuyQuantity:    intPtrToNullInt32(promotion.BuyQuantity),
+		GetQuantity:    intPtrToNullInt32(promotion.GetQuantity),
+		TargetType:     string(promotion.TargetType),
+		TargetID:       targetID,
+		VoucherCode:    ptrToNullString(promotion.VoucherCode),
+		MinSpend:       promotion.MinSpend.String(),
+		StartsAt:       timePtrToNullTime(promotion.StartsAt),
+		EndsAt:         timePtrToNullTime(promotion.EndsAt),
+		DailyStartTime: ptrToNullString(promotion.DailyStartTime),
+		DailyEndTime:   ptrToNullString(promotion.DailyEndTime),
+		UsageLimit:     intPtrToNullInt32(promotion.UsageLimit),
+		IsStackable:    promotion.IsStackable,
+		Priority:       int32(promotion.Priority),
+		IsActive:       promotion.IsActive,
+		CreatedBy:      createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create promotion in database: %w", err)
+	}
+
+	return toPromotionModel(dbPromotion)
+}
+
+// GetPromotion retrieves a promotion by ID
+func (r *promotionRepo) GetPromotion(id string) (*models.Promotion, error) {
+	promotionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	dbPromotion, err := r.queries.GetPromotion(context.Background(), promotionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to fetch promotion from database: %w", err)
+	}
+
+	return toPromotionModel(dbPromotion)
+}
+
+// GetPromotionByVoucherCode retrieves a promotion by its voucher code
+func (r *promotionRepo) GetPromotionByVoucherCode(code string) (*models.Promotion, error) {
+	dbPromotion, err := r.queries.GetPromotionByVoucherCode(context.Background(), sql.NullString{String: code, Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("voucher not found")
+		}
+		return nil, fmt.Errorf("failed to fetch voucher from database: %w", err)
+	}
+
+	return toPromotionModel(dbPromotion)
+}
+
+// ListPromotions retrieves a list of promotions based on filter
+func (r *promotionRepo) ListPromotions(filter models.PromotionFilter) ([]*models.Promotion, error) {
+	var isActive sql.NullBool
+	if filter.IsActive != nil {
+		isActive = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+
+	dbPromotions, err := r.queries.ListPromotions(context.Background(), db.ListPromotionsParams{
+		IsActive: isActive,
+		Limit:    int32(filter.Limit),
+		Offset:   int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions from database: %w", err)
+	}
+
+	promotions := make([]*models.Promotion, 0, len(dbPromotions))
+	for _, dbPromotion := range dbPromotions {
+		promotion, err := toPromotionModel(dbPromotion)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, nil
+}
+
+// ListAutomaticPromotions retrieves the active promotions without a voucher code
+// that are running at the given time and still have redemptions left
+func (r *promotionRepo) ListAutomaticPromotions(at time.Time) ([]*models.Promotion, error) {
+	dbPromotions, err := r.queries.ListAutomaticPromotions(context.Background(), at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch automatic promotions from database: %w", err)
+	}
+
+	promotions := make([]*models.Promotion, 0, len(dbPromotions))
+	for _, dbPromotion := range dbPromotions {
+		promotion, err := toPromotionModel(dbPromotion)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, nil
+}
+
+// UpdatePromotion updates an existing promotion
+func (r *promotionRepo) UpdatePromotion(promotion *models.Promotion) (*models.Promotion, error) {
+	promotionID, err := uuid.Parse(promotion.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	targetID, err := stringPtrToNullUUID(promotion.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target ID: %w", err)
+	}
+
+	dbPromotion, err := r.queries.UpdatePromotion(context.Background(), db.UpdatePromotionParams{
+		ID:             promotionID,
+		Name:           promotion.Name,
+		Description:    sql.NullString{String: promotion.Description, Valid: promotion.Description != ""},
+		PromotionType:  string(promotion.Type),
+		Value:          promotion.Value.String(),
+		BuyQuantity:    intPtrToNullInt32(promotion.BuyQuantity),
+		GetQuantity:    intPtrToNullInt32(promotion.GetQuantity),
+		TargetType:     string(promotion.TargetType),
+		TargetID:       targetID,
+		VoucherCode:    ptrToNullString(promotion.VoucherCode),
+		MinSpend:       promotion.MinSpend.String(),
+		StartsAt:       timePtrToNullTime(promotion.StartsAt),
+		EndsAt:         timePtrToNullTime(promotion.EndsAt),
+		DailyStartTime: ptrToNullString(promotion.DailyStartTime),
+		DailyEndTime:   ptrToNullString(promotion.DailyEndTime),
+		UsageLimit:     intPtrToNullInt32(promotion.UsageLimit),
+		IsStackable:    promotion.IsStackable,
+		Priority:       int32(promotion.Priority),
+		IsActive:       promotion.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to update promotion in database: %w", err)
+	}
+
+	return toPromotionModel(dbPromotion)
+}
+
+// DeletePromotion deletes a promotion. Orders it was applied to keep its name and discount.
+func (r *promotionRepo) DeletePromotion(id string) error {
+	promotionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	if err := r.queries.DeletePromotion(context.Background(), promotionID); err != nil {
+		return fmt.Errorf("failed to delete promotion from database: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimPromotionUsage uses up one redemption of a promotion, returning
+// ErrPromotionUsageLimitReached when none are left
+func (r *promotionRepo) ClaimPromotionUsage(id string) error {
+	promotionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	_, err = r.queries.ClaimPromotionUsage(context.Background(), promotionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPromotionUsageLimitReached
+		}
+		return fmt.Errorf("failed to claim promotion usage: %w", err)
+	}
+
+	return nil
+}
+
+// ReleasePromotionUsage gives back a redemption, e.g. when the order is cancelled
+func (r *promotionRepo) ReleasePromotionUsage(id string) error {
+	promotionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	if err := r.queries.ReleasePromotionUsage(context.Background(), promotionID); err != nil {
+		return fmt.Errorf("failed to release promotion usage: %w", err)
+	}
+
+	return nil
+}
+
+// CreateOrderPromotion records a promotion applied to an order
+func (r *promotionRepo) CreateOrderPromotion(orderPromotion *models.OrderPromotion) (*models.OrderPromotion, error) {
+	orderID, err := uuid.Parse(orderPromotion.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	promotionID, err := stringPtrToNullUUID(orderPromotion.PromotionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid promotion ID: %w", err)
+	}
+
+	dbOrderPromotion, err := r.queries.CreateOrderPromotion(context.Background(), db.CreateOrderPromotionParams{
+		OrderID:        orderID,
+		PromotionID:    promotionID,
+		PromotionName:  orderPromotion.PromotionName,
+		DiscountAmount: orderPromotion.DiscountAmount.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order promotion in database: %w", err)
+	}
+
+	return toOrderPromotionModel(dbOrderPromotion)
+}
+
+// ListOrderPromotions retrieves the promotions applied to an order
+func (r *promotionRepo) ListOrderPromotions(orderID string) ([]*models.OrderPromotion, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbOrderPromotions, err := r.queries.ListOrderPromotions(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order promotions from database: %w", err)
+	}
+
+	orderPromotions := make([]*models.OrderPromotion, 0, len(dbOrderPromotions))
+	for _, dbOrderPromotion := range dbOrderPromotions {
+		orderPromotion, err := toOrderPromotionModel(dbOrderPromotion)
+		if err != nil {
+			return nil, err
+		}
+		orderPromotions = append(orderPromotions, orderPromotion)
+	}
+
+	return orderPromotions, nil
+}
+
+// DeleteOrderPromotions removes every promotion recorded against an order
+func (r *promotionRepo) DeleteOrderPromotions(orderID string) error {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	if err := r.queries.DeleteOrderPromotionsByOrderID(context.Background(), orderUUID); err != nil {
+		return fmt.Errorf("failed to delete order promotions from database: %w", err)
+	}
+
+	return nil
+}
+
+// toOrderPromotionModel converts a sqlc order promotion row into the domain model
+func toOrderPromotionModel(dbOrderPromotion db.OrderPromotion) (*models.OrderPromotion, error) {
+	discountAmount, err := decimal.NewFromString(dbOrderPromotion.DiscountAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order promotion discount %s: %w", dbOrderPromotion.DiscountAmount, err)
+	}
+
+	return &models.OrderPromotion{
+		ID:             dbOrderPromotion.ID.String(),
+		OrderID:        dbOrderPromotion.OrderID.String(),
+		PromotionID:    nullUUIDToStringPtr(dbOrderPromotion.PromotionID),
+		PromotionName:  dbOrderPromotion.PromotionName,
+		DiscountAmount: types.DecimalText(discountAmount),
+		CreatedAt:      dbOrderPromotion.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,452 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrOverReceipt is returned when a delivery would take a purchase order line
+// past the quantity ordered
+var ErrOverReceipt = errors.New("received quantity exceeds the quantity ordered")
+
+// purchaseOrderRepo implements the PurchaseOrderRepo interface
+type purchaseOrderRepo struct {
+	queries *db.Queries
+}
+
+// toPurchaseOrderModel converts a sqlc purchase order row into the domain model.
+// The get, locking get and list queries return the same columns.
+func toPurchaseOrderModel(dbOrder db.GetPurchaseOrderRow) (*models.PurchaseOrder, error) {
+	totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse purchase order total %s: %w", dbOrder.TotalAmount, err)
+	}
+
+	return &models.PurchaseOrder{
+		ID:           dbOrder.ID.String(),
+		PONumber:     dbOrder.PoNumber,
+		SupplierID:   dbOrder.SupplierID.String(),
+		SupplierName: dbOrder.SupplierName,
+		Status:       types.PurchaseOrderStatus(dbOrder.Status),
+		ExpectedDate: nullTimeToPtr(dbOrder.ExpectedDate),
+		Notes:        nullStringToPtr(dbOrder.Notes),
+		TotalAmount:  types.DecimalText(totalAmount),
+		CreatedBy:    dbOrder.CreatedBy.String(),
+		SentAt:       nullTimeToPtr(dbOrder.SentAt),
+		ReceivedAt:   nullTimeToPtr(dbOrder.ReceivedAt),
+		CreatedAt:    dbOrder.CreatedAt,
+		UpdatedAt:    dbOrder.UpdatedAt,
+	}, nil
+}
+
+// toPurchaseOrderItemModel converts a sqlc purchase order line into the domain model
+func toPurchaseOrderItemModel(dbItem db.ListPurchaseOrderItemsRow) (models.PurchaseOrderItem, error) {
+	quantity, err := parseStockQuantity(dbItem.Quantity)
+	if err != nil {
+		return models.PurchaseOrderItem{}, err
+	}
+	receivedQuantity, err := parseStockQuantity(dbItem.ReceivedQuantity)
+	if err != nil {
+		return models.PurchaseOrderItem{}, err
+	}
+	unitCost, err := decimal.NewFromString(dbItem.UnitCost)
+	if err != nil {
+		return models.PurchaseOrderItem{}, fmt.Errorf("failed to parse purchase order unit cost %s: %w", dbItem.UnitCost, err)
+	}
+
+	return models.PurchaseOrderItem{
+		ID:               dbItem.ID.String(),
+		PurchaseOrderID:  dbItem.PurchaseOrderID.String(),
+		MenuItemID:       nullUUIDToStringPtr(dbItem.MenuItemID),
+		IngredientID:     nullUUIDToStringPtr(dbItem.IngredientID),
+		ItemName:         dbItem.ItemName,
+		Unit:             dbItem.Unit,
+		Quantity:         quantity,
+		ReceivedQuantity: receivedQuantity,
+		UnitCost:         types.DecimalText(unitCost),
+		LineTotal:        types.FromDecimal(decimal.Decimal(quantity).Mul(unitCost).Round(2)),
+	}, nil
+}
+
+// toGoodsReceivedNoteModel converts a sqlc goods received note row into the domain model
+func toGoodsReceivedNoteModel(dbNote db.GoodsReceivedNote) (*models.GoodsReceivedNote, error) {
+	totalAmount, err := decimal.NewFromString(dbNote.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse goods received note total %s: %w", dbNote.TotalAmount, err)
+	}
+
+	return &models.GoodsReceivedNote{
+		ID:              dbNote.ID.String(),
+		GRNNumber:       dbNote.GrnNumber,
+		PurchaseOrderID: dbNote.PurchaseOrderID.String(),
+		TotalAmount:     types.DecimalText(totalAmount),
+		ExpenseID:       nullUUIDToStringPtr(dbNote.ExpenseID),
+		Notes:           nullStringToPtr(dbNote.Notes),
+		ReceivedBy:      dbNote.ReceivedBy.String(),
+		ReceivedAt:      dbNote.ReceivedAt,
+		Items:           []models.GoodsReceivedNoteItem{},
+	}, nil
+}
+
+// CreatePurchaseOrder creates a draft purchase order without lines
+func (r *purchaseOrderRepo) CreatePurchaseOrder(order *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	supplierID, err := uuid.Parse(order.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supplier ID: %w", err)
+	}
+
+	createdBy, err := uuid.Parse(order.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbOrder, err := r.queries.CreatePurchaseOrder(context.Background(), db.CreatePurchaseOrderParams{
+		SupplierID:   supplierID,
+		ExpectedDate: timePtrToNullTime(order.ExpectedDate),
+		Notes:        ptrToNullString(order.Notes),
+		CreatedBy:    createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create purchase order in database: %w", err)
+	}
+
+	return toPurchaseOrderModel(db.GetPurchaseOrderRow{
+		ID:           dbOrder.ID,
+		PoNumber:     dbOrder.PoNumber,
+		SupplierID:   dbOrder.SupplierID,
+		SupplierName: order.SupplierName,
+		Status:       dbOrder.Status,
+		ExpectedDate: dbOrder.ExpectedDate,
+		Notes:        dbOrder.Notes,
+		TotalAmount:  dbOrder.TotalAmount,
+		CreatedBy:    dbOrder.CreatedBy,
+		SentAt:       dbOrder.SentAt,
+		ReceivedAt:   dbOrder.ReceivedAt,
+		CreatedAt:    dbOrder.CreatedAt,
+		UpdatedAt:    dbOrder.UpdatedAt,
+	})
+}
+
+// GetPurchaseOrder retrieves a purchase order by ID, without its lines
+func (r *purchaseOrderRepo) GetPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	dbOrder, err := r.queries.GetPurchaseOrder(context.Background(), orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, fmt.Errorf("failed to fetch purchase order from database: %w", err)
+	}
+
+	return toPurchaseOrderModel(dbOrder)
+}
+
+// GetPurchaseOrderForUpdate retrieves a purchase order and locks it until the
+// surrounding transaction ends
+func (r *purchaseOrderRepo) GetPurchaseOrderForUpdate(id string) (*models.PurchaseOrder, error) {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	dbOrder, err := r.queries.GetPurchaseOrderForUpdate(context.Background(), orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, fmt.Errorf("failed to fetch purchase order from database: %w", err)
+	}
+
+	return toPurchaseOrderModel(db.GetPurchaseOrderRow(dbOrder))
+}
+
+// ListPurchaseOrders retrieves purchase orders, newest first, based on filter criteria
+func (r *purchaseOrderRepo) ListPurchaseOrders(filter models.PurchaseOrderFilter) ([]*models.PurchaseOrder, error) {
+	supplierID, err := stringPtrToNullUUID(filter.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supplier ID: %w", err)
+	}
+
+	var status sql.NullString
+	if filter.Status != nil {
+		status = sql.NullString{String: string(*filter.Status), Valid: true}
+	}
+
+	dbOrders, err := r.queries.ListPurchaseOrders(context.Background(), db.ListPurchaseOrdersParams{
+		SupplierID: supplierID,
+		Status:     status,
+		Limit:      int32(filter.Limit),
+		Offset:     int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase orders from database: %w", err)
+	}
+
+	orders := make([]*models.PurchaseOrder, 0, len(dbOrders))
+	for _, dbOrder := range dbOrders {
+		order, err := toPurchaseOrderModel(db.GetPurchaseOrderRow(dbOrder))
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// UpdatePurchaseOrder updates the supplier, expected date and notes of a purchase order
+func (r *purchaseOrderRepo) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	orderID, err := uuid.Parse(order.ID)
+	if err != nil {
+		return fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	supplierID, err := uuid.Parse(order.SupplierID)
+	if err != nil {
+		return fmt.Errorf("invalid supplier ID: %w", err)
+	}
+
+	err = r.queries.UpdatePurchaseOrder(context.Background(), db.UpdatePurchaseOrderParams{
+		ID:           orderID,
+		SupplierID:   supplierID,
+		ExpectedDate: timePtrToNullTime(order.ExpectedDate),
+		Notes:        ptrToNullString(order.Notes),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order in database: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePurchaseOrderStatus moves a purchase order to a new status
+func (r *purchaseOrderRepo) UpdatePurchaseOrderStatus(id string, status types.PurchaseOrderStatus) error {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	err = r.queries.UpdatePurchaseOrderStatus(context.Background(), db.UpdatePurchaseOrderStatusParams{
+		Status: string(status),
+		ID:     orderID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order status in database: %w", err)
+	}
+
+	return nil
+}
+
+// SetPurchaseOrderItems replaces the lines of a purchase order and recalculates its total
+func (r *purchaseOrderRepo) SetPurchaseOrderItems(id string, items []models.PurchaseOrderItem) error {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	if err := r.queries.DeletePurchaseOrderItems(context.Background(), orderID); err != nil {
+		return fmt.Errorf("failed to delete purchase order items in database: %w", err)
+	}
+
+	for _, item := range items {
+		menuItemID, err := stringPtrToNullUUID(item.MenuItemID)
+		if err != nil {
+			return fmt.Errorf("invalid menu item ID: %w", err)
+		}
+		ingredientID, err := stringPtrToNullUUID(item.IngredientID)
+		if err != nil {
+			return fmt.Errorf("invalid ingredient ID: %w", err)
+		}
+
+		err = r.queries.CreatePurchaseOrderItem(context.Background(), db.CreatePurchaseOrderItemParams{
+			PurchaseOrderID: orderID,
+			MenuItemID:      menuItemID,
+			IngredientID:    ingredientID,
+			Unit:            item.Unit,
+			Quantity:        item.Quantity.String(),
+			UnitCost:        item.UnitCost.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order item in database: %w", err)
+		}
+	}
+
+	if err := r.queries.RefreshPurchaseOrderTotal(context.Background(), orderID); err != nil {
+		return fmt.Errorf("failed to update purchase order total in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListPurchaseOrderItems retrieves the lines of a purchase order
+func (r *purchaseOrderRepo) ListPurchaseOrderItems(id string) ([]models.PurchaseOrderItem, error) {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	dbItems, err := r.queries.ListPurchaseOrderItems(context.Background(), orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase order items from database: %w", err)
+	}
+
+	items := make([]models.PurchaseOrderItem, 0, len(dbItems))
+	for _, dbItem := range dbItems {
+		item, err := toPurchaseOrderItemModel(dbItem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ReceivePurchaseOrderItem adds a delivered quantity to a purchase order line
+// in a single conditional UPDATE. It returns ErrOverReceipt if the line would
+// then have received more than was ordered.
+func (r *purchaseOrderRepo) ReceivePurchaseOrderItem(itemID string, quantity types.DecimalText) error {
+	parsedItemID, err := uuid.Parse(itemID)
+	if err != nil {
+		return fmt.Errorf("invalid purchase order item ID: %w", err)
+	}
+
+	_, err = r.queries.ReceivePurchaseOrderItem(context.Background(), db.ReceivePurchaseOrderItemParams{
+		Quantity: quantity.String(),
+		ID:       parsedItemID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrOverReceipt
+		}
+		return fmt.Errorf("failed to receive purchase order item in database: %w", err)
+	}
+
+	return nil
+}
+
+// CreateGoodsReceivedNote records a delivery against a purchase order
+func (r *purchaseOrderRepo) CreateGoodsReceivedNote(note *models.GoodsReceivedNote) (*models.GoodsReceivedNote, error) {
+	purchaseOrderID, err := uuid.Parse(note.PurchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	expenseID, err := stringPtrToNullUUID(note.ExpenseID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expense ID: %w", err)
+	}
+
+	receivedBy, err := uuid.Parse(note.ReceivedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbNote, err := r.queries.CreateGoodsReceivedNote(context.Background(), db.CreateGoodsReceivedNoteParams{
+		PurchaseOrderID: purchaseOrderID,
+		TotalAmount:     note.TotalAmount.String(),
+		ExpenseID:       expenseID,
+		Notes:           ptrToNullString(note.Notes),
+		ReceivedBy:      receivedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goods received note in database: %w", err)
+	}
+
+	return toGoodsReceivedNoteModel(dbNote)
+}
+
+// CreateGoodsReceivedNoteItem records the quantity of a line that arrived in a delivery
+func (r *purchaseOrderRepo) CreateGoodsReceivedNoteItem(item *models.GoodsReceivedNoteItem) error {
+	noteID, err := uuid.Parse(item.GoodsReceivedNoteID)
+	if err != nil {
+		return fmt.Errorf("invalid goods received note ID: %w", err)
+	}
+
+	purchaseOrderItemID, err := uuid.Parse(item.PurchaseOrderItemID)
+	if err != nil {
+		return fmt.Errorf("invalid purchase order item ID: %w", err)
+	}
+
+	err = r.queries.CreateGoodsReceivedNoteItem(context.Background(), db.CreateGoodsReceivedNoteItemParams{
+		GoodsReceivedNoteID: noteID,
+		PurchaseOrderItemID: purchaseOrderItemID,
+		Quantity:            item.Quantity.String(),
+		StockQuantity:       item.StockQuantity.String(),
+		UnitCost:            item.UnitCost.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create goods received note item in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListGoodsReceivedNotes retrieves the deliveries against a purchase order with their lines
+func (r *purchaseOrderRepo) ListGoodsReceivedNotes(purchaseOrderID string) ([]models.GoodsReceivedNote, error) {
+	orderID, err := uuid.Parse(purchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase order ID: %w", err)
+	}
+
+	dbNotes, err := r.queries.ListGoodsReceivedNotes(context.Background(), orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch goods received notes from database: %w", err)
+	}
+
+	dbItems, err := r.queries.ListGoodsReceivedNoteItems(context.Background(), orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch goods received note items from database: %w", err)
+	}
+
+	itemsByNote := make(map[uuid.UUID][]models.GoodsReceivedNoteItem)
+	for _, dbItem := range dbItems {
+		quantity, err := parseStockQuantity(dbItem.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		stockQuantity, err := parseStockQuantity(dbItem.StockQuantity)
+		if err != nil {
+			return nil, err
+		}
+		unitCost, err := decimal.NewFromString(dbItem.UnitCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse goods received unit cost %s: %w", dbItem.UnitCost, err)
+		}
+
+		itemsByNote[dbItem.GoodsReceivedNoteID] = append(itemsByNote[dbItem.GoodsReceivedNoteID], models.GoodsReceivedNoteItem{
+			ID:                  dbItem.ID.String(),
+			GoodsReceivedNoteID: dbItem.GoodsReceivedNoteID.String(),
+			PurchaseOrderItemID: dbItem.PurchaseOrderItemID.String(),
+			Quantity:            quantity,
+			StockQuantity:       stockQuantity,
+			UnitCost:            types.DecimalText(unitCost),
+		})
+	}
+
+	notes := make([]models.GoodsReceivedNote, 0, len(dbNotes))
+	for _, dbNote := range dbNotes {
+		note, err := toGoodsReceivedNoteModel(dbNote)
+		if err != nil {
+			return nil, err
+		}
+		if items, ok := itemsByNote[dbNote.ID]; ok {
+			note.Items = items
+		}
+		notes = append(notes, *note)
+	}
+
+	return notes, nil
+}
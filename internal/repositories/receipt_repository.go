@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/google/uuid"
+)
+
+// receiptRepo implements the ReceiptRepo interface
+type receiptRepo struct {
+	queries *db.Queries
+}
+
+// toReceiptPrintModel converts a sqlc receipt print row into the domain model
+func toReceiptPrintModel(dbPrint db.ReceiptPrint) *models.ReceiptPrint {
+	return &models.ReceiptPrint{
+		ID:         dbPrint.ID.String(),
+		OrderID:    dbPrint.OrderID.String(),
+		PrintedBy:  dbPrint.PrintedBy.String(),
+		Format:     dbPrint.Format,
+		CopyNumber: int(dbPrint.CopyNumber),
+		PrintedAt:  dbPrint.PrintedAt,
+	}
+}
+
+// CreateReceiptPrint records a print of an order's receipt with the next copy
+// number. Callers lock the order first so two prints cannot take the same number.
+func (r *receiptRepo) CreateReceiptPrint(orderID, printedBy, format string) (*models.ReceiptPrint, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	printedByUUID, err := uuid.Parse(printedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbPrint, err := r.queries.CreateReceiptPrint(context.Background(), db.CreateReceiptPrintParams{
+		OrderID:   orderUUID,
+		PrintedBy: printedByUUID,
+		Format:    format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receipt print in database: %w", err)
+	}
+
+	return toReceiptPrintModel(dbPrint), nil
+}
+
+// ListReceiptPrints retrieves the prints of an order's receipt, oldest first
+func (r *receiptRepo) ListReceiptPrints(orderID string) ([]*models.ReceiptPrint, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbPrints, err := r.queries.ListReceiptPrintsByOrderID(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipt prints from database: %w", err)
+	}
+
+	prints := make([]*models.ReceiptPrint, len(dbPrints))
+	for i, dbPrint := range dbPrints {
+		prints[i] = toReceiptPrintModel(dbPrint)
+	}
+
+	return prints, nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/google/uuid"
+)
+
+// recipeRepo implements the RecipeRepo interface
+type recipeRepo struct {
+	queries *db.Queries
+}
+
+// toRecipeItemModel converts a sqlc recipe row into the domain model
+func toRecipeItemModel(ingredientID uuid.UUID, ingredientName, unit, quantity string) (models.RecipeItem, error) {
+	parsedQuantity, err := parseStockQuantity(quantity)
+	if err != nil {
+		return models.RecipeItem{}, err
+	}
+
+	return models.RecipeItem{
+		IngredientID:   ingredientID.String(),
+		IngredientName: ingredientName,
+		Unit:           unit,
+		Quantity:       parsedQuantity,
+	}, nil
+}
+
+// createRecipeItems inserts the lines of a recipe owned by either a menu item
+// or a modifier option
+func (r *recipeRepo) createRecipeItems(menuItemID, modifierOptionID uuid.NullUUID, items []models.RecipeItemInput) error {
+	for _, item := range items {
+		ingredientID, err := uuid.Parse(item.IngredientID)
+		if err != nil {
+			return fmt.Errorf("invalid ingredient ID: %w", err)
+		}
+
+		err = r.queries.CreateRecipeItem(context.Background(), db.CreateRecipeItemParams{
+			MenuItemID:       menuItemID,
+			ModifierOptionID: modifierOptionID,
+			IngredientID:     ingredientID,
+			Quantity:         item.Quantity.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create recipe item in database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetMenuItemRecipe retrieves the ingredients used for one unit of a menu item.
+// A menu item without a recipe is stocked as finished goods.
+func (r *recipeRepo) GetMenuItemRecipe(menuItemID string) ([]models.RecipeItem, error) {
+	itemID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	dbItems, err := r.queries.ListMenuItemRecipeItems(context.Background(), uuid.NullUUID{UUID: itemID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch menu item recipe from database: %w", err)
+	}
+
+	items := make([]models.RecipeItem, 0, len(dbItems))
+	for _, dbItem := range dbItems {
+		item, err := toRecipeItemModel(dbItem.IngredientID, dbItem.IngredientName, dbItem.Unit, dbItem.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// SetMenuItemRecipe replaces the recipe of a menu item
+func (r *recipeRepo) SetMenuItemRecipe(menuItemID string, items []models.RecipeItemInput) error {
+	itemID, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+	owner := uuid.NullUUID{UUID: itemID, Valid: true}
+
+	if err := r.queries.DeleteMenuItemRecipeItems(context.Background(), owner); err != nil {
+		return fmt.Errorf("failed to clear menu item recipe in database: %w", err)
+	}
+
+	return r.createRecipeItems(owner, uuid.NullUUID{}, items)
+}
+
+// GetModifierOptionRecipe retrieves the ingredients a modifier option adds to
+// one unit of the menu item it is chosen for
+func (r *recipeRepo) GetModifierOptionRecipe(optionID string) ([]models.RecipeItem, error) {
+	parsedOptionID, err := uuid.Parse(optionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+
+	dbItems, err := r.queries.ListModifierOptionRecipeItems(context.Background(), uuid.NullUUID{UUID: parsedOptionID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch modifier option recipe from database: %w", err)
+	}
+
+	items := make([]models.RecipeItem, 0, len(dbItems))
+	for _, dbItem := range dbItems {
+		item, err := toRecipeItemModel(dbItem.IngredientID, dbItem.IngredientName, dbItem.Unit, dbItem.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// SetModifierOptionRecipe replaces the recipe of a modifier option
+func (r *recipeRepo) SetModifierOptionRecipe(optionID string, items []models.RecipeItemInput) error {
+	parsedOptionID, err := uuid.Parse(optionID)
+	if err != nil {
+		return fmt.Errorf("invalid modifier option ID: %w", err)
+	}
+	owner := uuid.NullUUID{UUID: parsedOptionID, Valid: true}
+
+	if err := r.queries.DeleteModifierOptionRecipeItems(context.Background(), owner); err != nil {
+		return fmt.Errorf("failed to clear modifier option recipe in database: %w", err)
+	}
+
+	return r.createRecipeItems(uuid.NullUUID{}, owner, items)
+}
+
+// CountRecipesByIngredient counts the recipe lines that use an ingredient
+func (r *recipeRepo) CountRecipesByIngredient(ingredientID string) (int, error) {
+	parsedIngredientID, err := uuid.Parse(ingredientID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	count, err := r.queries.CountRecipeItemsByIngredient(context.Background(), parsedIngredientID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recipes in database: %w", err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// refundRepo implements the RefundRepo interface
+type refundRepo struct {
+	queries *db.Queries
+}
+
+// toRefundModel converts a sqlc refund row into the domain model
+func toRefundModel(dbRefund db.Refund) (*models.Refund, error) {
+	amount, err := decimal.NewFromString(dbRefund.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund amount %s: %w", dbRefund.Amount, err)
+	}
+
+	taxAmount, err := decimal.NewFromString(dbRefund.TaxAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund tax amount %s: %w", dbRefund.TaxAmount, err)
+	}
+
+	return &models.Refund{
+		ID:            dbRefund.ID.String(),
+		OrderID:       dbRefund.OrderID.String(),
+		Amount:        types.DecimalText(amount),
+		TaxAmount:     types.DecimalText(taxAmount),
+		PaymentMethod: types.PaymentMethod(dbRefund.PaymentMethod),
+		Reference:     nullStringToPtr(dbRefund.Reference),
+		Reason:        dbRefund.Reason,
+		Restocked:     dbRefund.Restocked,
+		RefundedBy:    dbRefund.RefundedBy.String(),
+		ApprovedBy:    nullUUIDToStringPtr(dbRefund.ApprovedBy),
+		ShiftID:       nullUUIDToStringPtr(dbRefund.ShiftID),
+		CreatedAt:     dbRefund.CreatedAt,
+	}, nil
+}
+
+// toRefundItemModel converts a sqlc refund item row into the domain model
+func toRefundItemModel(dbItem db.RefundItem) (*models.RefundItem, error) {
+	amount, err := decimal.NewFromString(dbItem.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund item amount %s: %w", dbItem.Amount, err)
+	}
+
+	return &models.RefundItem{
+		ID:          dbItem.ID.String(),
+		RefundID:    dbItem.RefundID.String(),
+		OrderItemID: dbItem.OrderItemID.String(),
+		MenuItemID:  dbItem.MenuItemID.String(),
+		Quantity:    int(dbItem.Quantity),
+		Amount:      types.DecimalText(amount),
+	}, nil
+}
+
+// CreateRefund records a refund header; its lines are added with CreateRefundItem
+func (r *refundRepo) CreateRefund(refund *models.Refund) (*models.Refund, error) {
+	orderID, err := uuid.Parse(refund.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	refundedBy, err := uuid.Parse(refund.RefundedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	approvedBy, err := stringPtrToNullUUID(refund.ApprovedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approver ID: %w", err)
+	}
+
+	shiftID, err := stringPtrToNullUUID(refund.ShiftID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	dbRefund, err := r.queries.CreateRefund(context.Background(), db.CreateRefundParams{
+		OrderID:       orderID,
+		Amount:        refund.Amount.String(),
+		TaxAmount:     refund.TaxAmount.String(),
+		PaymentMethod: string(refund.PaymentMethod),
+		Reference:     ptrToNullString(refund.Reference),
+		Reason:        refund.Reason,
+		Restocked:     refund.Restocked,
+		RefundedBy:    refundedBy,
+		ApprovedBy:    approvedBy,
+		ShiftID:       shiftID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refund in database: %w", err)
+	}
+
+	return toRefundModel(dbRefund)
+}
+
+// CreateRefundItem records a returned quantity of an order line
+func (r *refundRepo) CreateRefundItem(item *models.RefundItem) (*models.RefundItem, error) {
+	refundID, err := uuid.Parse(item.RefundID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refund ID: %w", err)
+	}
+
+	orderItemID, err := uuid.Parse(item.OrderItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order item ID: %w", err)
+	}
+
+	menuItemID, err := uuid.Parse(item.MenuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+
+	dbItem, err := r.queries.CreateRefundItem(context.Background(), db.CreateRefundItemParams{
+		RefundID:    refundID,
+		OrderItemID: orderItemID,
+		MenuItemID:  menuItemID,
+		Quantity:    int32(item.Quantity),
+		Amount:      item.Amount.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refund item in database: %w", err)
+	}
+
+	return toRefundItemModel(dbItem)
+}
+
+// ListRefundsByOrderID retrieves the refunds of an order together with their lines
+func (r *refundRepo) ListRefundsByOrderID(orderID string) ([]*models.Refund, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	dbRefunds, err := r.queries.ListRefundsByOrderID(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch refunds from database: %w", err)
+	}
+
+	refunds := make([]*models.Refund, 0, len(dbRefunds))
+	for _, dbRefund := range dbRefunds {
+		refund, err := toRefundModel(dbRefund)
+		if err != nil {
+			return nil, err
+		}
+
+		dbItems, err := r.queries.ListRefundItemsByRefundID(context.Background(), dbRefund.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch refund items from database: %w", err)
+		}
+
+		refund.Items = make([]models.RefundItem, 0, len(dbItems))
+		for _, dbItem := range dbItems {
+			item, err := toRefundItemModel(dbItem)
+			if err != nil {
+				return nil, err
+			}
+			refund.Items = append(refund.Items, *item)
+		}
+
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, nil
+}
+
+// GetRefundedQuantities returns how much of each order line has been refunded so far
+func (r *refundRepo) GetRefundedQuantities(orderID string) (map[string]int, error) {
+	orderUUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	rows, err := r.queries.GetRefundedQuantitiesByOrderID(context.Background(), orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch refunded quantities from database: %w", err)
+	}
+
+	quantities := make(map[string]int, len(rows))
+	for _, row := range rows {
+		quantities[row.OrderItemID.String()] = int(row.RefundedQuantity)
+	}
+
+	return quantities, nil
+}
@@ -0,0 +1,387 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoZReport is returned when no Z report has been generated yet
+var ErrNoZReport = errors.New("no Z report generated yet")
+
+// salesReportRepo implements the SalesReportRepo interface
+type salesReportRepo struct {
+	queries *db.Queries
+}
+
+// amountParser parses numeric columns, keeping the first error so a row's
+// amounts can be read one after another and checked once
+type amountParser struct {
+	err error
+}
+
+func (p *amountParser) parse(name, value string) types.DecimalText {
+	amount, err := decimal.NewFromString(value)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("failed to parse %s %s: %w", name, value, err)
+	}
+	return types.DecimalText(amount)
+}
+
+// toZReportModel converts a sqlc Z report row into the domain model, without
+// its payment method and cashier totals
+func toZReportModel(dbReport db.ZReport) (*models.SalesReport, error) {
+	reportNumber := int(dbReport.ReportNumber)
+	report := &models.SalesReport{
+		ID:               dbReport.ID.String(),
+		ReportType:       types.SalesReportTypeZ,
+		ReportNumber:     &reportNumber,
+		PeriodStart:      nullTimeToPtr(dbReport.PeriodStart),
+		PeriodEnd:        dbReport.PeriodEnd,
+		GeneratedBy:      dbReport.GeneratedBy.String(),
+		GeneratedAt:      dbReport.GeneratedAt,
+		OrderCount:       int(dbReport.OrderCount),
+		RefundCount:      int(dbReport.RefundCount),
+		CancelledCount:   int(dbReport.CancelledCount),
+		VoidedCount:      int(dbReport.VoidedCount),
+		FirstOrderNumber: nullStringToPtr(dbReport.FirstOrderNumber),
+		LastOrderNumber:  nullStringToPtr(dbReport.LastOrderNumber),
+	}
+
+	var amounts amountParser
+	report.GrossSales = amounts.parse("gross sales", dbReport.GrossSales)
+	report.DiscountAmount = amounts.parse("discount amount", dbReport.DiscountAmount)
+	report.ServiceCharge = amounts.parse("service charge", dbReport.ServiceChargeAmount)
+	report.TaxAmount = amounts.parse("tax amount", dbReport.TaxAmount)
+	report.RoundingAmount = amounts.parse("rounding amount", dbReport.RoundingAmount)
+	report.TotalSales = amounts.parse("total sales", dbReport.TotalSales)
+	report.RefundAmount = amounts.parse("refund amount", dbReport.RefundAmount)
+	report.RefundTax = amounts.parse("refund tax", dbReport.RefundTax)
+	report.VoidedAmount = amounts.parse("voided amount", dbReport.VoidedAmount)
+	report.NetSales = amounts.parse("net sales", dbReport.NetSales)
+	if amounts.err != nil {
+		return nil, amounts.err
+	}
+
+	return report, nil
+}
+
+// withTotals loads the payment method and cashier totals of a stored Z report
+func (r *salesReportRepo) withTotals(report *models.SalesReport) (*models.SalesReport, error) {
+	reportID, err := uuid.Parse(report.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Z report ID: %w", err)
+	}
+
+	dbPaymentMethods, err := r.queries.ListZReportPaymentMethods(context.Background(), reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Z report payment methods from database: %w", err)
+	}
+
+	report.PaymentMethods = make([]models.PaymentMethodTotal, len(dbPaymentMethods))
+	for i, dbPaymentMethod := range dbPaymentMethods {
+		totalAmount, err := decimal.NewFromString(dbPaymentMethod.TotalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payment method total %s: %w", dbPaymentMethod.TotalAmount, err)
+		}
+		report.PaymentMethods[i] = models.PaymentMethodTotal{
+			PaymentMethod: types.PaymentMethod(dbPaymentMethod.PaymentMethod),
+			TotalOrders:   int(dbPaymentMethod.OrderCount),
+			TotalAmount:   types.DecimalText(totalAmount),
+		}
+	}
+
+	dbCashiers, err := r.queries.ListZReportCashiers(context.Background(), reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Z report cashiers from database: %w", err)
+	}
+
+	report.Cashiers = make([]models.CashierTotal, len(dbCashiers))
+	for i, dbCashier := range dbCashiers {
+		totalSales, err := decimal.NewFromString(dbCashier.TotalSales)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cashier total %s: %w", dbCashier.TotalSales, err)
+		}
+		report.Cashiers[i] = models.CashierTotal{
+			UserID:      dbCashier.UserID.String(),
+			CashierName: dbCashier.CashierName,
+			OrderCount:  int(dbCashier.OrderCount),
+			TotalSales:  types.DecimalText(totalSales),
+		}
+	}
+
+	return report, nil
+}
+
+// LockZReports blocks other Z reports from being generated, and orders from
+// being completed, voided or refunded, until the surrounding transaction ends.
+// It waits for those already in progress to commit first.
+func (r *salesReportRepo) LockZReports() error {
+	if err := r.queries.LockZReports(context.Background()); err != nil {
+		return fmt.Errorf("failed to lock Z reports in database: %w", err)
+	}
+	return nil
+}
+
+// GetDatabaseTime returns the database's clock, which completed orders, voids
+// and refunds are stamped with, so a report period can end on the same clock
+func (r *salesReportRepo) GetDatabaseTime() (time.Time, error) {
+	now, err := r.queries.GetDatabaseTime(context.Background())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get database time: %w", err)
+	}
+	return now, nil
+}
+
+// GetLatestZReport retrieves the most recent Z report, or ErrNoZReport if none
+// has been generated
+func (r *salesReportRepo) GetLatestZReport() (*models.SalesReport, error) {
+	dbReport, err := r.queries.GetLatestZReport(context.Background())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoZReport
+		}
+		return nil, fmt.Errorf("failed to fetch latest Z report from database: %w", err)
+	}
+
+	return toZReportModel(dbReport)
+}
+
+// GetZReport retrieves a Z report with its payment method and cashier totals
+func (r *salesReportRepo) GetZReport(id string) (*models.SalesReport, error) {
+	reportID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Z report ID: %w", err)
+	}
+
+	dbReport, err := r.queries.GetZReport(context.Background(), reportID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("Z report not found")
+		}
+		return nil, fmt.Errorf("failed to fetch Z report from database: %w", err)
+	}
+
+	report, err := toZReportModel(dbReport)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withTotals(report)
+}
+
+// ListZReports retrieves Z reports with their totals, newest first
+func (r *salesReportRepo) ListZReports(limit, offset int) ([]*models.SalesReport, error) {
+	dbReports, err := r.queries.ListZReports(context.Background(), db.ListZReportsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Z reports from database: %w", err)
+	}
+
+	reports := make([]*models.SalesReport, len(dbReports))
+	for i, dbReport := range dbReports {
+		report, err := toZReportModel(dbReport)
+		if err != nil {
+			return nil, err
+		}
+		if reports[i], err = r.withTotals(report); err != nil {
+			return nil, err
+		}
+	}
+
+	return reports, nil
+}
+
+// CreateZReport stores a Z report with its payment method and cashier totals
+func (r *salesReportRepo) CreateZReport(report *models.SalesReport) (*models.SalesReport, error) {
+	if report.ReportNumber == nil {
+		return nil, errors.New("Z report number is required")
+	}
+
+	generatedBy, err := uuid.Parse(report.GeneratedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbReport, err := r.queries.CreateZReport(context.Background(), db.CreateZReportParams{
+		ReportNumber:        int32(*report.ReportNumber),
+		PeriodStart:         timePtrToNullTime(report.PeriodStart),
+		PeriodEnd:           report.PeriodEnd,
+		GeneratedBy:         generatedBy,
+		OrderCount:          int32(report.OrderCount),
+		GrossSales:          report.GrossSales.String(),
+		DiscountAmount:      report.DiscountAmount.String(),
+		ServiceChargeAmount: report.ServiceCharge.String(),
+		TaxAmount:           report.TaxAmount.String(),
+		RoundingAmount:      report.RoundingAmount.String(),
+		TotalSales:          report.TotalSales.String(),
+		RefundCount:         int32(report.RefundCount),
+		RefundAmount:        report.RefundAmount.String(),
+		RefundTax:           report.RefundTax.String(),
+		CancelledCount:      int32(report.CancelledCount),
+		VoidedCount:         int32(report.VoidedCount),
+		VoidedAmount:        report.VoidedAmount.String(),
+		NetSales:            report.NetSales.String(),
+		FirstOrderNumber:    ptrToNullString(report.FirstOrderNumber),
+		LastOrderNumber:     ptrToNullString(report.LastOrderNumber),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Z report in database: %w", err)
+	}
+
+	for _, paymentMethod := range report.PaymentMethods {
+		err := r.queries.CreateZReportPaymentMethod(context.Background(), db.CreateZReportPaymentMethodParams{
+			ZReportID:     dbReport.ID,
+			PaymentMethod: string(paymentMethod.PaymentMethod),
+			OrderCount:    int32(paymentMethod.TotalOrders),
+			TotalAmount:   paymentMethod.TotalAmount.String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Z report payment method in database: %w", err)
+		}
+	}
+
+	for _, cashier := range report.Cashiers {
+		userID, err := uuid.Parse(cashier.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cashier ID: %w", err)
+		}
+
+		err = r.queries.CreateZReportCashier(context.Background(), db.CreateZReportCashierParams{
+			ZReportID:   dbReport.ID,
+			UserID:      userID,
+			CashierName: cashier.CashierName,
+			OrderCount:  int32(cashier.OrderCount),
+			TotalSales:  cashier.TotalSales.String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Z report cashier in database: %w", err)
+		}
+	}
+
+	created, err := toZReportModel(dbReport)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withTotals(created)
+}
+
+// SummarizeSales adds up the completed orders, payments, refunds and
+// cancellations after periodStart, or since the beginning when it is nil, up to
+// and including periodEnd. Net sales are left for the caller to work out.
+func (r *salesReportRepo) SummarizeSales(periodStart *time.Time, periodEnd time.Time) (*models.SalesReport, error) {
+	start := timePtrToNullTime(periodStart)
+	report := &models.SalesReport{
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+
+	sales, err := r.queries.GetSalesTotalsForPeriod(context.Background(), db.GetSalesTotalsForPeriodParams{
+		PeriodStart: start,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sales totals from database: %w", err)
+	}
+
+	refunds, err := r.queries.GetRefundTotalsForPeriod(context.Background(), db.GetRefundTotalsForPeriodParams{
+		PeriodStart: start,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch refund totals from database: %w", err)
+	}
+
+	cancellations, err := r.queries.GetCancellationTotalsForPeriod(context.Background(), db.GetCancellationTotalsForPeriodParams{
+		PeriodStart: start,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cancellation totals from database: %w", err)
+	}
+
+	report.OrderCount = int(sales.OrderCount)
+	report.RefundCount = int(refunds.RefundCount)
+	report.CancelledCount = int(cancellations.CancelledCount)
+	report.VoidedCount = int(cancellations.VoidedCount)
+	if sales.OrderCount > 0 {
+		report.FirstOrderNumber = &sales.FirstOrderNumber
+		report.LastOrderNumber = &sales.LastOrderNumber
+	}
+
+	var amounts amountParser
+	report.GrossSales = amounts.parse("gross sales", sales.GrossSales)
+	report.DiscountAmount = amounts.parse("discount amount", sales.DiscountAmount)
+	report.ServiceCharge = amounts.parse("service charge", sales.ServiceChargeAmount)
+	report.TaxAmount = amounts.parse("tax amount", sales.TaxAmount)
+	report.RoundingAmount = amounts.parse("rounding amount", sales.RoundingAmount)
+	report.TotalSales = amounts.parse("total sales", sales.TotalSales)
+	report.RefundAmount = amounts.parse("refund amount", refunds.RefundAmount)
+	report.RefundTax = amounts.parse("refund tax", refunds.RefundTax)
+	report.VoidedAmount = amounts.parse("voided amount", cancellations.VoidedAmount)
+	report.EarlierVoidedNet = amounts.parse("earlier voided net", cancellations.EarlierVoidedNet)
+	if amounts.err != nil {
+		return nil, amounts.err
+	}
+
+	paymentMethods, err := r.queries.GetPaymentMethodTotalsForPeriod(context.Background(), db.GetPaymentMethodTotalsForPeriodParams{
+		PeriodStart: start,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment method totals from database: %w", err)
+	}
+
+	report.PaymentMethods = make([]models.PaymentMethodTotal, len(paymentMethods))
+	for i, paymentMethod := range paymentMethods {
+		totalAmount, err := decimal.NewFromString(paymentMethod.TotalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payment method total %s: %w", paymentMethod.TotalAmount, err)
+		}
+		report.PaymentMethods[i] = models.PaymentMethodTotal{
+			PaymentMethod: types.PaymentMethod(paymentMethod.PaymentMethod),
+			TotalOrders:   int(paymentMethod.OrderCount),
+			TotalAmount:   types.DecimalText(totalAmount),
+		}
+	}
+
+	cashiers, err := r.queries.GetCashierTotalsForPeriod(context.Background(), db.GetCashierTotalsForPeriodParams{
+		PeriodStart: start,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cashier totals from database: %w", err)
+	}
+
+	report.Cashiers = make([]models.CashierTotal, len(cashiers))
+	for i, cashier := range cashiers {
+		totalSales, err := decimal.NewFromString(cashier.TotalSales)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cashier total %s: %w", cashier.TotalSales, err)
+		}
+		name := strings.TrimSpace(cashier.FirstName + " " + cashier.LastName)
+		if name == "" {
+			name = cashier.Username
+		}
+		report.Cashiers[i] = models.CashierTotal{
+			UserID:      cashier.UserID.String(),
+			CashierName: name,
+			OrderCount:  int(cashier.OrderCount),
+			TotalSales:  types.DecimalText(totalSales),
+		}
+	}
+
+	return report, nil
+}
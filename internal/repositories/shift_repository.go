@@ -0,0 +1,403 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoOpenShift is returned when a cashier has no open cash drawer shift
+var ErrNoOpenShift = errors.New("no open cash drawer shift")
+
+// ErrShiftNotOpen is returned when closing a shift that was closed in the meantime
+var ErrShiftNotOpen = errors.New("cash drawer shift is not open")
+
+// shiftRepo implements the ShiftRepo interface
+type shiftRepo struct {
+	queries *db.Queries
+}
+
+// nullDecimalToPtr parses a nullable numeric column
+func nullDecimalToPtr(value sql.NullString) (*types.DecimalText, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	amount, err := decimal.NewFromString(value.String)
+	if err != nil {
+		return nil, err
+	}
+	result := types.DecimalText(amount)
+	return &result, nil
+}
+
+// decimalPtrToNullString formats an optional amount for a nullable numeric column
+func decimalPtrToNullString(value *types.DecimalText) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: value.String(), Valid: true}
+}
+
+// toCashShiftModel converts a sqlc cash shift row into the domain model
+func toCashShiftModel(dbShift db.CashShift) (*models.CashShift, error) {
+	openingFloat, err := decimal.NewFromString(dbShift.OpeningFloat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse opening float %s: %w", dbShift.OpeningFloat, err)
+	}
+
+	expectedCash, err := nullDecimalToPtr(dbShift.ExpectedCash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected cash %s: %w", dbShift.ExpectedCash.String, err)
+	}
+
+	countedCash, err := nullDecimalToPtr(dbShift.CountedCash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse counted cash %s: %w", dbShift.CountedCash.String, err)
+	}
+
+	variance, err := nullDecimalToPtr(dbShift.Variance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse variance %s: %w", dbShift.Variance.String, err)
+	}
+
+	return &models.CashShift{
+		ID:           dbShift.ID.String(),
+		UserID:       dbShift.UserID.String(),
+		Status:       types.ShiftStatus(dbShift.Status),
+		OpeningFloat: types.DecimalText(openingFloat),
+		ExpectedCash: expectedCash,
+		CountedCash:  countedCash,
+		Variance:     variance,
+		Notes:        nullStringToPtr(dbShift.Notes),
+		OpenedAt:     dbShift.OpenedAt,
+		ClosedAt:     nullTimeToPtr(dbShift.ClosedAt),
+		ClosedBy:     nullUUIDToStringPtr(dbShift.ClosedBy),
+	}, nil
+}
+
+// toCashMovementModel converts a sqlc cash movement row into the domain model
+func toCashMovementModel(dbMovement db.CashMovement) (*models.CashMovement, error) {
+	amount, err := decimal.NewFromString(dbMovement.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cash movement amount %s: %w", dbMovement.Amount, err)
+	}
+
+	return &models.CashMovement{
+		ID:           dbMovement.ID.String(),
+		ShiftID:      dbMovement.ShiftID.String(),
+		MovementType: types.CashMovementType(dbMovement.MovementType),
+		Amount:       types.DecimalText(amount),
+		Reason:       dbMovement.Reason,
+		CreatedBy:    dbMovement.CreatedBy.String(),
+		CreatedAt:    dbMovement.CreatedAt,
+	}, nil
+}
+
+// CreateShift opens a new cash drawer shift
+func (r *shiftRepo) CreateShift(shift *models.CashShift) (*models.CashShift, error) {
+	userID, err := uuid.Parse(shift.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbShift, err := r.queries.CreateCashShift(context.Background(), db.CreateCashShiftParams{
+		UserID:       userID,
+		OpeningFloat: shift.OpeningFloat.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cash shift in database: %w", err)
+	}
+
+	return toCashShiftModel(dbShift)
+}
+
+// GetShift retrieves a cash drawer shift by ID
+func (r *shiftRepo) GetShift(id string) (*models.CashShift, error) {
+	shiftID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	dbShift, err := r.queries.GetCashShift(context.Background(), shiftID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("cash shift not found")
+		}
+		return nil, fmt.Errorf("failed to fetch cash shift from database: %w", err)
+	}
+
+	return toCashShiftModel(dbShift)
+}
+
+// GetShiftForUpdate retrieves a cash drawer shift and locks its row until the
+// surrounding transaction ends
+func (r *shiftRepo) GetShiftForUpdate(id string) (*models.CashShift, error) {
+	shiftID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	dbShift, err := r.queries.GetCashShiftForUpdate(context.Background(), shiftID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("cash shift not found")
+		}
+		return nil, fmt.Errorf("failed to fetch cash shift from database: %w", err)
+	}
+
+	return toCashShiftModel(dbShift)
+}
+
+// GetOpenShiftByUser retrieves the cashier's open shift, or ErrNoOpenShift if
+// they have none. The shift cannot be closed until the surrounding transaction
+// ends, so an order completed in it is counted at close-out.
+func (r *shiftRepo) GetOpenShiftByUser(userID string) (*models.CashShift, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbShift, err := r.queries.GetOpenCashShiftByUserID(context.Background(), userUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoOpenShift
+		}
+		return nil, fmt.Errorf("failed to fetch open cash shift from database: %w", err)
+	}
+
+	return toCashShiftModel(dbShift)
+}
+
+// ListShifts retrieves cash drawer shifts, newest first
+func (r *shiftRepo) ListShifts(filter models.CashShiftFilter) ([]*models.CashShift, error) {
+	userID, err := stringPtrToNullUUID(filter.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var status sql.NullString
+	if filter.Status != nil {
+		status = sql.NullString{String: string(*filter.Status), Valid: true}
+	}
+
+	dbShifts, err := r.queries.ListCashShifts(context.Background(), db.ListCashShiftsParams{
+		UserID: userID,
+		Status: status,
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash shifts from database: %w", err)
+	}
+
+	shifts := make([]*models.CashShift, len(dbShifts))
+	for i, dbShift := range dbShifts {
+		shift, err := toCashShiftModel(dbShift)
+		if err != nil {
+			return nil, err
+		}
+		shifts[i] = shift
+	}
+
+	return shifts, nil
+}
+
+// CloseShift stores the close-out of an open shift. It returns ErrShiftNotOpen
+// if the shift was already closed.
+func (r *shiftRepo) CloseShift(shift *models.CashShift) (*models.CashShift, error) {
+	shiftID, err := uuid.Parse(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	closedBy, err := stringPtrToNullUUID(shift.ClosedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbShift, err := r.queries.CloseCashShift(context.Background(), db.CloseCashShiftParams{
+		ID:           shiftID,
+		ExpectedCash: decimalPtrToNullString(shift.ExpectedCash),
+		CountedCash:  decimalPtrToNullString(shift.CountedCash),
+		Variance:     decimalPtrToNullString(shift.Variance),
+		Notes:        ptrToNullString(shift.Notes),
+		ClosedBy:     closedBy,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrShiftNotOpen
+		}
+		return nil, fmt.Errorf("failed to close cash shift in database: %w", err)
+	}
+
+	return toCashShiftModel(dbShift)
+}
+
+// CreateMovement records a cash drop or payout against a shift
+func (r *shiftRepo) CreateMovement(movement *models.CashMovement) (*models.CashMovement, error) {
+	shiftID, err := uuid.Parse(movement.ShiftID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	createdBy, err := uuid.Parse(movement.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbMovement, err := r.queries.CreateCashMovement(context.Background(), db.CreateCashMovementParams{
+		ShiftID:      shiftID,
+		MovementType: string(movement.MovementType),
+		Amount:       movement.Amount.String(),
+		Reason:       movement.Reason,
+		CreatedBy:    createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cash movement in database: %w", err)
+	}
+
+	return toCashMovementModel(dbMovement)
+}
+
+// ListMovements retrieves the drops and payouts of a shift, oldest first
+func (r *shiftRepo) ListMovements(shiftID string) ([]*models.CashMovement, error) {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	dbMovements, err := r.queries.ListCashMovementsByShiftID(context.Background(), shiftUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash movements from database: %w", err)
+	}
+
+	movements := make([]*models.CashMovement, len(dbMovements))
+	for i, dbMovement := range dbMovements {
+		movement, err := toCashMovementModel(dbMovement)
+		if err != nil {
+			return nil, err
+		}
+		movements[i] = movement
+	}
+
+	return movements, nil
+}
+
+// CreateCount records how many notes or coins of one denomination were counted
+// at close-out
+func (r *shiftRepo) CreateCount(shiftID string, count models.CashCount) error {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	err = r.queries.CreateCashShiftCount(context.Background(), db.CreateCashShiftCountParams{
+		ShiftID:      shiftUUID,
+		Denomination: count.Denomination.String(),
+		Quantity:     int32(count.Quantity),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cash count in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListCounts retrieves the denomination breakdown counted at close-out,
+// largest denomination first
+func (r *shiftRepo) ListCounts(shiftID string) ([]models.CashCount, error) {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	dbCounts, err := r.queries.ListCashShiftCountsByShiftID(context.Background(), shiftUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash counts from database: %w", err)
+	}
+
+	counts := make([]models.CashCount, len(dbCounts))
+	for i, dbCount := range dbCounts {
+		denomination, err := decimal.NewFromString(dbCount.Denomination)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse denomination %s: %w", dbCount.Denomination, err)
+		}
+		counts[i] = models.CashCount{
+			Denomination: types.DecimalText(denomination),
+			Quantity:     int(dbCount.Quantity),
+		}
+	}
+
+	return counts, nil
+}
+
+// GetCashSales returns how many orders completed in a shift took cash and the
+// cash they kept in the drawer, net of change given, whether or not they were
+// voided later
+func (r *shiftRepo) GetCashSales(shiftID string) (int, types.DecimalText, error) {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return 0, types.DecimalText{}, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	row, err := r.queries.GetShiftCashSales(context.Background(), uuid.NullUUID{UUID: shiftUUID, Valid: true})
+	if err != nil {
+		return 0, types.DecimalText{}, fmt.Errorf("failed to fetch shift cash sales from database: %w", err)
+	}
+
+	sales, err := decimal.NewFromString(row.CashSales)
+	if err != nil {
+		return 0, types.DecimalText{}, fmt.Errorf("failed to parse cash sales %s: %w", row.CashSales, err)
+	}
+
+	return int(row.CashOrders), types.DecimalText(sales), nil
+}
+
+// GetCashRefunds totals the cash handed back from the drawer for refunds paid
+// in a shift
+func (r *shiftRepo) GetCashRefunds(shiftID string) (types.DecimalText, error) {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	cashRefunds, err := r.queries.GetShiftCashRefunds(context.Background(), uuid.NullUUID{UUID: shiftUUID, Valid: true})
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to fetch shift cash refunds from database: %w", err)
+	}
+
+	refunds, err := decimal.NewFromString(cashRefunds)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to parse cash refunds %s: %w", cashRefunds, err)
+	}
+
+	return types.DecimalText(refunds), nil
+}
+
+// GetCashVoids totals the cash handed back from the drawer for completed orders
+// voided in a shift
+func (r *shiftRepo) GetCashVoids(shiftID string) (types.DecimalText, error) {
+	shiftUUID, err := uuid.Parse(shiftID)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("invalid shift ID: %w", err)
+	}
+
+	cashVoids, err := r.queries.GetShiftCashVoids(context.Background(), uuid.NullUUID{UUID: shiftUUID, Valid: true})
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to fetch shift cash voids from database: %w", err)
+	}
+
+	voids, err := decimal.NewFromString(cashVoids)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to parse cash voids %s: %w", cashVoids, err)
+	}
+
+	return types.DecimalText(voids), nil
+}
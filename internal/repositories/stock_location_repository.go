@@ -0,0 +1,414 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInsufficientLocationStock is returned when stock would be taken out of a
+// location that does not hold enough of it, even if other locations do
+var ErrInsufficientLocationStock = errors.New("insufficient stock at location")
+
+// stockLocationRepo implements the StockLocationRepo interface
+type stockLocationRepo struct {
+	queries *db.Queries
+}
+
+// toStockLocationModel converts a sqlc stock location row into the domain model
+func toStockLocationModel(dbLocation db.StockLocation) *models.StockLocation {
+	return &models.StockLocation{
+		ID:          dbLocation.ID.String(),
+		Code:        dbLocation.Code,
+		Name:        dbLocation.Name,
+		Description: nullStringToPtr(dbLocation.Description),
+		IsSelling:   dbLocation.IsSelling,
+		IsActive:    dbLocation.IsActive,
+		CreatedAt:   dbLocation.CreatedAt,
+		UpdatedAt:   dbLocation.UpdatedAt,
+	}
+}
+
+// toStockTransferModel converts a sqlc stock transfer row into the domain model.
+// The create query returns the same columns without the location names.
+func toStockTransferModel(dbTransfer db.GetStockTransferRow) *models.StockTransfer {
+	return &models.StockTransfer{
+		ID:               dbTransfer.ID.String(),
+		TransferNumber:   dbTransfer.TransferNumber,
+		FromLocationID:   dbTransfer.FromLocationID.String(),
+		FromLocationName: dbTransfer.FromLocationName,
+		ToLocationID:     dbTransfer.ToLocationID.String(),
+		ToLocationName:   dbTransfer.ToLocationName,
+		Notes:            nullStringToPtr(dbTransfer.Notes),
+		CreatedBy:        dbTransfer.CreatedBy.String(),
+		CreatedAt:        dbTransfer.CreatedAt,
+	}
+}
+
+// AdjustStockBalance applies a change to the stock of a menu item or an
+// ingredient at a location, recording the location's first stock of it. It
+// fails with ErrInsufficientLocationStock when the location holds less than
+// is taken out.
+func (r *stockLocationRepo) AdjustStockBalance(locationID, menuItemID string, ingredientID *string, change types.DecimalText) error {
+	quantity := decimal.Decimal(change)
+	if quantity.IsZero() {
+		return nil
+	}
+
+	locationUUID, err := uuid.Parse(locationID)
+	if err != nil {
+		return fmt.Errorf("invalid stock location ID: %w", err)
+	}
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.AdjustStockBalance(context.Background(), db.AdjustStockBalanceParams{
+		Quantity:     quantity.String(),
+		LocationID:   locationUUID,
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to update stock balance in database: %w", err)
+	}
+
+	// No row was updated: either the location has never held the item, or it
+	// does not hold enough of it for the stock to be taken out
+	if quantity.IsNegative() {
+		location, err := r.queries.GetStockLocation(context.Background(), locationUUID)
+		if err != nil {
+			return ErrInsufficientLocationStock
+		}
+		return fmt.Errorf("%w %s", ErrInsufficientLocationStock, location.Name)
+	}
+
+	_, err = r.queries.CreateStockBalance(context.Background(), db.CreateStockBalanceParams{
+		LocationID:   locationUUID,
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+		Quantity:     quantity.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stock balance in database: %w", err)
+	}
+
+	return nil
+}
+
+// CreateStockLocation creates a stock location
+func (r *stockLocationRepo) CreateStockLocation(location *models.StockLocation) (*models.StockLocation, error) {
+	dbLocation, err := r.queries.CreateStockLocation(context.Background(), db.CreateStockLocationParams{
+		Code:        location.Code,
+		Name:        location.Name,
+		Description: ptrToNullString(location.Description),
+		IsSelling:   location.IsSelling,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stock location in database: %w", err)
+	}
+
+	return toStockLocationModel(dbLocation), nil
+}
+
+// GetStockLocation retrieves a stock location by ID
+func (r *stockLocationRepo) GetStockLocation(id string) (*models.StockLocation, error) {
+	locationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
+	dbLocation, err := r.queries.GetStockLocation(context.Background(), locationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("stock location not found")
+		}
+		return nil, fmt.Errorf("failed to fetch stock location from database: %w", err)
+	}
+
+	return toStockLocationModel(dbLocation), nil
+}
+
+// GetSellingStockLocation retrieves the location sales are made from
+func (r *stockLocationRepo) GetSellingStockLocation() (*models.StockLocation, error) {
+	dbLocation, err := r.queries.GetSellingStockLocation(context.Background())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("selling stock location not found")
+		}
+		return nil, fmt.Errorf("failed to fetch selling stock location from database: %w", err)
+	}
+
+	return toStockLocationModel(dbLocation), nil
+}
+
+// ListStockLocations retrieves the stock locations, the selling location first
+func (r *stockLocationRepo) ListStockLocations(includeInactive bool) ([]*models.StockLocation, error) {
+	dbLocations, err := r.queries.ListStockLocations(context.Background(), includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock locations from database: %w", err)
+	}
+
+	locations := make([]*models.StockLocation, 0, len(dbLocations))
+	for _, dbLocation := range dbLocations {
+		locations = append(locations, toStockLocationModel(dbLocation))
+	}
+
+	return locations, nil
+}
+
+// UpdateStockLocation updates the name, description and flags of a stock location
+func (r *stockLocationRepo) UpdateStockLocation(location *models.StockLocation) (*models.StockLocation, error) {
+	locationID, err := uuid.Parse(location.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
+	dbLocation, err := r.queries.UpdateStockLocation(context.Background(), db.UpdateStockLocationParams{
+		ID:          locationID,
+		Name:        location.Name,
+		Description: ptrToNullString(location.Description),
+		IsSelling:   location.IsSelling,
+		IsActive:    location.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("stock location not found")
+		}
+		return nil, fmt.Errorf("failed to update stock location in database: %w", err)
+	}
+
+	return toStockLocationModel(dbLocation), nil
+}
+
+// ClearSellingStockLocation stops selling from every location other than the
+// one given, which is about to become the selling location
+func (r *stockLocationRepo) ClearSellingStockLocation(exceptID string) error {
+	locationID, err := uuid.Parse(exceptID)
+	if err != nil {
+		return fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
+	if err := r.queries.ClearSellingStockLocation(context.Background(), locationID); err != nil {
+		return fmt.Errorf("failed to clear selling stock location in database: %w", err)
+	}
+
+	return nil
+}
+
+// ListStockBalances retrieves the stock held at a location
+func (r *stockLocationRepo) ListStockBalances(locationID string) ([]*models.StockBalance, error) {
+	locationUUID, err := uuid.Parse(locationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
+	dbBalances, err := r.queries.ListStockBalances(context.Background(), locationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock balances from database: %w", err)
+	}
+
+	balances := make([]*models.StockBalance, 0, len(dbBalances))
+	for _, dbBalance := range dbBalances {
+		quantity, err := parseStockQuantity(dbBalance.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, &models.StockBalance{
+			LocationID:   dbBalance.LocationID.String(),
+			MenuItemID:   nullUUIDToStringPtr(dbBalance.MenuItemID),
+			IngredientID: nullUUIDToStringPtr(dbBalance.IngredientID),
+			ItemName:     dbBalance.ItemName,
+			ItemType:     dbBalance.ItemType,
+			Unit:         dbBalance.Unit,
+			Quantity:     quantity,
+			UpdatedAt:    dbBalance.UpdatedAt,
+		})
+	}
+
+	return balances, nil
+}
+
+// LockItemStock locks the stock of a menu item or an ingredient until the
+// surrounding transaction ends and returns it, across all locations
+func (r *stockLocationRepo) LockItemStock(menuItemID, ingredientID *string) (types.DecimalText, error) {
+	var currentStock string
+	var err error
+	if ingredientID != nil {
+		id, parseErr := uuid.Parse(*ingredientID)
+		if parseErr != nil {
+			return types.DecimalText{}, fmt.Errorf("invalid ingredient ID: %w", parseErr)
+		}
+		currentStock, err = r.queries.LockIngredientStock(context.Background(), id)
+	} else if menuItemID != nil {
+		id, parseErr := uuid.Parse(*menuItemID)
+		if parseErr != nil {
+			return types.DecimalText{}, fmt.Errorf("invalid menu item ID: %w", parseErr)
+		}
+		currentStock, err = r.queries.LockMenuItemStock(context.Background(), id)
+	} else {
+		return types.DecimalText{}, errors.New("stock must belong to either a menu item or an ingredient")
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return types.DecimalText{}, errors.New("stock not found")
+		}
+		return types.DecimalText{}, fmt.Errorf("failed to lock stock in database: %w", err)
+	}
+
+	return parseStockQuantity(currentStock)
+}
+
+// CreateStockTransfer creates a stock transfer without lines
+func (r *stockLocationRepo) CreateStockTransfer(transfer *models.StockTransfer) (*models.StockTransfer, error) {
+	fromLocationID, err := uuid.Parse(transfer.FromLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+	toLocationID, err := uuid.Parse(transfer.ToLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+	createdBy, err := uuid.Parse(transfer.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbTransfer, err := r.queries.CreateStockTransfer(context.Background(), db.CreateStockTransferParams{
+		FromLocationID: fromLocationID,
+		ToLocationID:   toLocationID,
+		Notes:          ptrToNullString(transfer.Notes),
+		CreatedBy:      createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stock transfer in database: %w", err)
+	}
+
+	return toStockTransferModel(db.GetStockTransferRow{
+		ID:               dbTransfer.ID,
+		TransferNumber:   dbTransfer.TransferNumber,
+		FromLocationID:   dbTransfer.FromLocationID,
+		FromLocationName: transfer.FromLocationName,
+		ToLocationID:     dbTransfer.ToLocationID,
+		ToLocationName:   transfer.ToLocationName,
+		Notes:            dbTransfer.Notes,
+		CreatedBy:        dbTransfer.CreatedBy,
+		CreatedAt:        dbTransfer.CreatedAt,
+	}), nil
+}
+
+// CreateStockTransferLine records the quantity of an item moved by a transfer
+func (r *stockLocationRepo) CreateStockTransferLine(line *models.StockTransferLine) error {
+	transferID, err := uuid.Parse(line.StockTransferID)
+	if err != nil {
+		return fmt.Errorf("invalid stock transfer ID: %w", err)
+	}
+	menuItemID, err := stringPtrToNullUUID(line.MenuItemID)
+	if err != nil {
+		return fmt.Errorf("invalid menu item ID: %w", err)
+	}
+	ingredientID, err := stringPtrToNullUUID(line.IngredientID)
+	if err != nil {
+		return fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	err = r.queries.CreateStockTransferLine(context.Background(), db.CreateStockTransferLineParams{
+		StockTransferID: transferID,
+		MenuItemID:      menuItemID,
+		IngredientID:    ingredientID,
+		Unit:            line.Unit,
+		Quantity:        line.Quantity.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stock transfer line in database: %w", err)
+	}
+
+	return nil
+}
+
+// GetStockTransfer retrieves a stock transfer by ID, without its lines
+func (r *stockLocationRepo) GetStockTransfer(id string) (*models.StockTransfer, error) {
+	transferID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock transfer ID: %w", err)
+	}
+
+	dbTransfer, err := r.queries.GetStockTransfer(context.Background(), transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("stock transfer not found")
+		}
+		return nil, fmt.Errorf("failed to fetch stock transfer from database: %w", err)
+	}
+
+	return toStockTransferModel(dbTransfer), nil
+}
+
+// ListStockTransfers retrieves stock transfers, newest first
+func (r *stockLocationRepo) ListStockTransfers(filter models.StockTransferFilter) ([]*models.StockTransfer, error) {
+	locationID, err := stringPtrToNullUUID(filter.LocationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
+	dbTransfers, err := r.queries.ListStockTransfers(context.Background(), db.ListStockTransfersParams{
+		LocationID: locationID,
+		Limit:      int32(filter.Limit),
+		Offset:     int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock transfers from database: %w", err)
+	}
+
+	transfers := make([]*models.StockTransfer, 0, len(dbTransfers))
+	for _, dbTransfer := range dbTransfers {
+		transfers = append(transfers, toStockTransferModel(db.GetStockTransferRow(dbTransfer)))
+	}
+
+	return transfers, nil
+}
+
+// ListStockTransferLines retrieves the lines of a stock transfer
+func (r *stockLocationRepo) ListStockTransferLines(id string) ([]models.StockTransferLine, error) {
+	transferID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock transfer ID: %w", err)
+	}
+
+	dbLines, err := r.queries.ListStockTransferLines(context.Background(), transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock transfer lines from database: %w", err)
+	}
+
+	lines := make([]models.StockTransferLine, 0, len(dbLines))
+	for _, dbLine := range dbLines {
+		quantity, err := parseStockQuantity(dbLine.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, models.StockTransferLine{
+			ID:              dbLine.ID.String(),
+			StockTransferID: dbLine.StockTransferID.String(),
+			MenuItemID:      nullUUIDToStringPtr(dbLine.MenuItemID),
+			IngredientID:    nullUUIDToStringPtr(dbLine.IngredientID),
+			ItemName:        dbLine.ItemName,
+			Unit:            dbLine.Unit,
+			Quantity:        quantity,
+		})
+	}
+
+	return lines, nil
+}
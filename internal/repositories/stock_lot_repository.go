@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// stockLotRepo implements the StockLotRepo interface
+type stockLotRepo struct {
+	queries *db.Queries
+}
+
+// toStockLotModel converts a sqlc stock lot row into the domain model. The
+// create query returns the same columns without the item name.
+func toStockLotModel(dbLot db.ListStockLotsRow) (*models.StockLot, error) {
+	quantityReceived, err := parseStockQuantity(dbLot.QuantityReceived)
+	if err != nil {
+		return nil, err
+	}
+	quantityRemaining, err := parseStockQuantity(dbLot.QuantityRemaining)
+	if err != nil {
+		return nil, err
+	}
+	unitCost, err := decimal.NewFromString(dbLot.UnitCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lot unit cost %s: %w", dbLot.UnitCost, err)
+	}
+
+	return &models.StockLot{
+		ID:                  dbLot.ID.String(),
+		MenuItemID:          nullUUIDToStringPtr(dbLot.MenuItemID),
+		IngredientID:        nullUUIDToStringPtr(dbLot.IngredientID),
+		ItemName:            dbLot.ItemName,
+		LotNumber:           nullStringToPtr(dbLot.LotNumber),
+		Unit:                dbLot.Unit,
+		QuantityReceived:    quantityReceived,
+		QuantityRemaining:   quantityRemaining,
+		UnitCost:            types.DecimalText(unitCost),
+		ReceivedAt:          dbLot.ReceivedAt,
+		ExpiresAt:           nullTimeToPtr(dbLot.ExpiresAt),
+		GoodsReceivedNoteID: nullUUIDToStringPtr(dbLot.GoodsReceivedNoteID),
+		ReceivedBy:          nullUUIDToStringPtr(dbLot.ReceivedBy),
+	}, nil
+}
+
+// toStockLotModels converts sqlc stock lot rows into domain models
+func toStockLotModels(dbLots []db.ListStockLotsRow) ([]*models.StockLot, error) {
+	lots := make([]*models.StockLot, 0, len(dbLots))
+	for _, dbLot := range dbLots {
+		lot, err := toStockLotModel(dbLot)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, nil
+}
+
+// CreateStockLot puts a newly received quantity into stock as a lot
+func (r *stockLotRepo) CreateStockLot(lot *models.StockLot) (*models.StockLot, error) {
+	menuItemID, err := stringPtrToNullUUID(lot.MenuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+	ingredientID, err := stringPtrToNullUUID(lot.IngredientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+	noteID, err := stringPtrToNullUUID(lot.GoodsReceivedNoteID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid goods received note ID: %w", err)
+	}
+	receivedBy, err := stringPtrToNullUUID(lot.ReceivedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbLot, err := r.queries.CreateStockLot(context.Background(), db.CreateStockLotParams{
+		MenuItemID:          menuItemID,
+		IngredientID:        ingredientID,
+		LotNumber:           ptrToNullString(lot.LotNumber),
+		Unit:                lot.Unit,
+		QuantityReceived:    lot.QuantityReceived.String(),
+		UnitCost:            lot.UnitCost.String(),
+		ExpiresAt:           timePtrToNullTime(lot.ExpiresAt),
+		GoodsReceivedNoteID: noteID,
+		ReceivedBy:          receivedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stock lot in database: %w", err)
+	}
+
+	return toStockLotModel(db.ListStockLotsRow{
+		ID:                  dbLot.ID,
+		MenuItemID:          dbLot.MenuItemID,
+		IngredientID:        dbLot.IngredientID,
+		ItemName:            lot.ItemName,
+		LotNumber:           dbLot.LotNumber,
+		Unit:                dbLot.Unit,
+		QuantityReceived:    dbLot.QuantityReceived,
+		QuantityRemaining:   dbLot.QuantityRemaining,
+		UnitCost:            dbLot.UnitCost,
+		ReceivedAt:          dbLot.ReceivedAt,
+		ExpiresAt:           dbLot.ExpiresAt,
+		GoodsReceivedNoteID: dbLot.GoodsReceivedNoteID,
+		ReceivedBy:          dbLot.ReceivedBy,
+	})
+}
+
+// ListStockLots retrieves the lots with stock left, in the order they are consumed
+func (r *stockLotRepo) ListStockLots(filter models.StockLotFilter) ([]*models.StockLot, error) {
+	menuItemID, err := stringPtrToNullUUID(filter.MenuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+	ingredientID, err := stringPtrToNullUUID(filter.IngredientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+
+	dbLots, err := r.queries.ListStockLots(context.Background(), db.ListStockLotsParams{
+		MenuItemID:   menuItemID,
+		IngredientID: ingredientID,
+		Limit:        int32(filter.Limit),
+		Offset:       int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock lots from database: %w", err)
+	}
+
+	return toStockLotModels(dbLots)
+}
+
+// ListStockLotsExpiringBefore retrieves the lots with stock left that expire
+// at or before the given time, soonest first
+func (r *stockLotRepo) ListStockLotsExpiringBefore(before time.Time) ([]*models.StockLot, error) {
+	dbRows, err := r.queries.ListStockLotsExpiringBefore(context.Background(), sql.NullTime{Time: before, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expiring stock lots from database: %w", err)
+	}
+
+	dbLots := make([]db.ListStockLotsRow, 0, len(dbRows))
+	for _, dbRow := range dbRows {
+		dbLots = append(dbLots, db.ListStockLotsRow(dbRow))
+	}
+	return toStockLotModels(dbLots)
+}
+
+// GetExpiredStock retrieves what is left in the expired lots of a menu item or
+// an ingredient. It is part of the item's stock until it is written off, but
+// cannot be sold or used.
+func (r *stockLotRepo) GetExpiredStock(menuItemID string, ingredientID *string) (types.DecimalText, error) {
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return types.DecimalText{}, err
+	}
+
+	dbExpired, err := r.queries.GetExpiredStock(context.Background(), db.GetExpiredStockParams{
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to fetch expired stock from database: %w", err)
+	}
+
+	return parseStockQuantity(dbExpired)
+}
+
+// ConsumeStockLots takes a quantity taken out of the stock of a menu item or
+// an ingredient out of its lots that have not expired, first-expiring first
+func (r *stockLotRepo) ConsumeStockLots(menuItemID string, ingredientID *string, quantity types.DecimalText) error {
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return err
+	}
+
+	if ingredientUUID.Valid {
+		err = r.queries.ConsumeIngredientStockLots(context.Background(), db.ConsumeIngredientStockLotsParams{
+			IngredientID: ingredientUUID.UUID,
+			Quantity:     quantity.String(),
+		})
+	} else {
+		err = r.queries.ConsumeMenuItemStockLots(context.Background(), db.ConsumeMenuItemStockLotsParams{
+			MenuItemID: menuItemUUID.UUID,
+			Quantity:   quantity.String(),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to consume stock lots in database: %w", err)
+	}
+	return nil
+}
+
+// EmptyStockLot marks whatever is left of a lot as gone, e.g. once it has
+// been written off
+func (r *stockLotRepo) EmptyStockLot(id string) error {
+	lotID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid stock lot ID: %w", err)
+	}
+
+	if err := r.queries.EmptyStockLot(context.Background(), lotID); err != nil {
+		return fmt.Errorf("failed to empty stock lot in database: %w", err)
+	}
+	return nil
+}
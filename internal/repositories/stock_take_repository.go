@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// stockTakeRepo implements the StockTakeRepo interface
+type stockTakeRepo struct {
+	queries *db.Queries
+}
+
+// toStockTakeModel converts a sqlc stock take row into the domain model
+func toStockTakeModel(dbStockTake db.StockTake) *models.StockTake {
+	return &models.StockTake{
+		ID:                 dbStockTake.ID.String(),
+		StockTakeNumber:    dbStockTake.StockTakeNumber,
+		Status:             types.StockTakeStatus(dbStockTake.Status),
+		CategoryID:         nullUUIDToStringPtr(dbStockTake.CategoryID),
+		IncludeMenuItems:   dbStockTake.IncludeMenuItems,
+		IncludeIngredients: dbStockTake.IncludeIngredients,
+		Notes:              nullStringToPtr(dbStockTake.Notes),
+		CreatedBy:          dbStockTake.CreatedBy.String(),
+		ApprovedBy:         nullUUIDToStringPtr(dbStockTake.ApprovedBy),
+		ApprovedAt:         nullTimeToPtr(dbStockTake.ApprovedAt),
+		CreatedAt:          dbStockTake.CreatedAt,
+		UpdatedAt:          dbStockTake.UpdatedAt,
+	}
+}
+
+// toStockTakeLineModel converts a sqlc stock take line into the domain model,
+// working out the variance of lines that have been counted
+func toStockTakeLineModel(dbLine db.ListStockTakeLinesRow) (models.StockTakeLine, error) {
+	systemQuantity, err := parseStockQuantity(dbLine.SystemQuantity)
+	if err != nil {
+		return models.StockTakeLine{}, err
+	}
+	countedQuantity, err := nullDecimalToPtr(dbLine.CountedQuantity)
+	if err != nil {
+		return models.StockTakeLine{}, fmt.Errorf("failed to parse counted quantity %s: %w", dbLine.CountedQuantity.String, err)
+	}
+	unitCost, err := decimal.NewFromString(dbLine.UnitCost)
+	if err != nil {
+		return models.StockTakeLine{}, fmt.Errorf("failed to parse stock take unit cost %s: %w", dbLine.UnitCost, err)
+	}
+
+	line := models.StockTakeLine{
+		ID:              dbLine.ID.String(),
+		StockTakeID:     dbLine.StockTakeID.String(),
+		MenuItemID:      nullUUIDToStringPtr(dbLine.MenuItemID),
+		IngredientID:    nullUUIDToStringPtr(dbLine.IngredientID),
+		ItemName:        dbLine.ItemName,
+		Unit:            dbLine.Unit,
+		SystemQuantity:  systemQuantity,
+		CountedQuantity: countedQuantity,
+		UnitCost:        types.DecimalText(unitCost),
+		CountedBy:       nullUUIDToStringPtr(dbLine.CountedBy),
+		CountedAt:       nullTimeToPtr(dbLine.CountedAt),
+	}
+	if countedQuantity != nil {
+		variance := countedQuantity.Sub(systemQuantity)
+		varianceValue := types.FromDecimal(decimal.Decimal(variance).Mul(unitCost).Round(2))
+		line.Variance = &variance
+		line.VarianceValue = &varianceValue
+	}
+
+	return line, nil
+}
+
+// CreateStockTake creates an open stock take without lines
+func (r *stockTakeRepo) CreateStockTake(stockTake *models.StockTake) (*models.StockTake, error) {
+	createdBy, err := uuid.Parse(stockTake.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	categoryID, err := stringPtrToNullUUID(stockTake.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	dbStockTake, err := r.queries.CreateStockTake(context.Background(), db.CreateStockTakeParams{
+		CategoryID:         categoryID,
+		IncludeMenuItems:   stockTake.IncludeMenuItems,
+		IncludeIngredients: stockTake.IncludeIngredients,
+		Notes:              ptrToNullString(stockTake.Notes),
+		CreatedBy:          createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stock take in database: %w", err)
+	}
+
+	return toStockTakeModel(dbStockTake), nil
+}
+
+// SnapshotStockTakeMenuItems adds a line for every menu item with inventory,
+// or only those of one category, holding its current stock and cost
+func (r *stockTakeRepo) SnapshotStockTakeMenuItems(id string, categoryID *string) error {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	categoryUUID, err := stringPtrToNullUUID(categoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	err = r.queries.SnapshotStockTakeMenuItems(context.Background(), db.SnapshotStockTakeMenuItemsParams{
+		StockTakeID: stockTakeID,
+		CategoryID:  categoryUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record menu item stock for stock take in database: %w", err)
+	}
+
+	return nil
+}
+
+// SnapshotStockTakeIngredients adds a line for every active ingredient
+// holding its current stock and cost
+func (r *stockTakeRepo) SnapshotStockTakeIngredients(id string) error {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	if err := r.queries.SnapshotStockTakeIngredients(context.Background(), stockTakeID); err != nil {
+		return fmt.Errorf("failed to record ingredient stock for stock take in database: %w", err)
+	}
+
+	return nil
+}
+
+// GetStockTake retrieves a stock take by ID, without its lines
+func (r *stockTakeRepo) GetStockTake(id string) (*models.StockTake, error) {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	dbStockTake, err := r.queries.GetStockTake(context.Background(), stockTakeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("stock take not found")
+		}
+		return nil, fmt.Errorf("failed to fetch stock take from database: %w", err)
+	}
+
+	return toStockTakeModel(dbStockTake), nil
+}
+
+// GetStockTakeForUpdate retrieves a stock take and locks it until the
+// surrounding transaction ends
+func (r *stockTakeRepo) GetStockTakeForUpdate(id string) (*models.StockTake, error) {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	dbStockTake, err := r.queries.GetStockTakeForUpdate(context.Background(), stockTakeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("stock take not found")
+		}
+		return nil, fmt.Errorf("failed to fetch stock take from database: %w", err)
+	}
+
+	return toStockTakeModel(dbStockTake), nil
+}
+
+// ListStockTakes retrieves stock takes, newest first
+func (r *stockTakeRepo) ListStockTakes(filter models.StockTakeFilter) ([]*models.StockTake, error) {
+	var status sql.NullString
+	if filter.Status != nil {
+		status = sql.NullString{String: string(*filter.Status), Valid: true}
+	}
+
+	dbStockTakes, err := r.queries.ListStockTakes(context.Background(), db.ListStockTakesParams{
+		Status: status,
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock takes from database: %w", err)
+	}
+
+	stockTakes := make([]*models.StockTake, 0, len(dbStockTakes))
+	for _, dbStockTake := range dbStockTakes {
+		stockTakes = append(stockTakes, toStockTakeModel(dbStockTake))
+	}
+
+	return stockTakes, nil
+}
+
+// ListStockTakeLines retrieves the lines of a stock take by item name
+func (r *stockTakeRepo) ListStockTakeLines(id string) ([]models.StockTakeLine, error) {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	dbLines, err := r.queries.ListStockTakeLines(context.Background(), stockTakeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock take lines from database: %w", err)
+	}
+
+	lines := make([]models.StockTakeLine, 0, len(dbLines))
+	for _, dbLine := range dbLines {
+		line, err := toStockTakeLineModel(dbLine)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// SetStockTakeLineCount replaces the counted quantity of a line
+func (r *stockTakeRepo) SetStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error {
+	lineUUID, err := uuid.Parse(lineID)
+	if err != nil {
+		return fmt.Errorf("invalid stock take line ID: %w", err)
+	}
+
+	userID, err := uuid.Parse(countedBy)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	err = r.queries.SetStockTakeLineCount(context.Background(), db.SetStockTakeLineCountParams{
+		ID:              lineUUID,
+		CountedQuantity: sql.NullString{String: quantity.String(), Valid: true},
+		CountedBy:       uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record stock take count in database: %w", err)
+	}
+
+	return nil
+}
+
+// AddStockTakeLineCount adds to the counted quantity of a line
+func (r *stockTakeRepo) AddStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error {
+	lineUUID, err := uuid.Parse(lineID)
+	if err != nil {
+		return fmt.Errorf("invalid stock take line ID: %w", err)
+	}
+
+	userID, err := uuid.Parse(countedBy)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	err = r.queries.AddStockTakeLineCount(context.Background(), db.AddStockTakeLineCountParams{
+		Quantity:  quantity.String(),
+		CountedBy: uuid.NullUUID{UUID: userID, Valid: true},
+		ID:        lineUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record stock take count in database: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStockTakeStatus closes a stock take, recording who approved it
+func (r *stockTakeRepo) UpdateStockTakeStatus(id string, status types.StockTakeStatus, approvedBy *string) error {
+	stockTakeID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid stock take ID: %w", err)
+	}
+
+	approvedByID, err := stringPtrToNullUUID(approvedBy)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	err = r.queries.UpdateStockTakeStatus(context.Background(), db.UpdateStockTakeStatusParams{
+		Status:     string(status),
+		ApprovedBy: approvedByID,
+		ID:         stockTakeID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update stock take status in database: %w", err)
+	}
+
+	return nil
+}
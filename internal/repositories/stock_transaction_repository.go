@@ -3,12 +3,14 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/db"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // stockTransactionRepo implements the StockTransactionRepo interface
@@ -16,9 +18,42 @@ type stockTransactionRepo struct {
 	queries *db.Queries
 }
 
-// CreateStockTransaction creates a new stock transaction
+// parseTransactionQuantities parses the numeric quantity columns of a stock transaction
+func parseTransactionQuantities(quantity, previousStock, currentStock string) (types.DecimalText, types.DecimalText, types.DecimalText, error) {
+	parsedQuantity, err := parseStockQuantity(quantity)
+	if err != nil {
+		return types.DecimalText{}, types.DecimalText{}, types.DecimalText{}, err
+	}
+	parsedPrevious, err := parseStockQuantity(previousStock)
+	if err != nil {
+		return types.DecimalText{}, types.DecimalText{}, types.DecimalText{}, err
+	}
+	parsedCurrent, err := parseStockQuantity(currentStock)
+	if err != nil {
+		return types.DecimalText{}, types.DecimalText{}, types.DecimalText{}, err
+	}
+	return parsedQuantity, parsedPrevious, parsedCurrent, nil
+}
+
+// parseTransactionCosts parses the numeric cost columns of a stock transaction
+func parseTransactionCosts(unitCost, totalCost, averageCost string) (types.DecimalText, types.DecimalText, types.DecimalText, error) {
+	var costs [3]decimal.Decimal
+	for i, value := range []string{unitCost, totalCost, averageCost} {
+		cost, err := decimal.NewFromString(value)
+		if err != nil {
+			return types.DecimalText{}, types.DecimalText{}, types.DecimalText{}, fmt.Errorf("failed to parse stock cost %s: %w", value, err)
+		}
+		costs[i] = cost
+	}
+	return types.DecimalText(costs[0]), types.DecimalText(costs[1]), types.DecimalText(costs[2]), nil
+}
+
+// CreateStockTransaction records a stock movement valued at the unit, total
+// and average cost set on it, at the location set on it. It must follow the
+// stock adjustment it records in the same database transaction, which keeps
+// the item locked until both are written.
 func (r *stockTransactionRepo) CreateStockTransaction(transaction *models.StockTransaction) (*models.StockTransaction, error) {
-	menuItemUUID, err := uuid.Parse(transaction.MenuItemID)
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(transaction.MenuItemID, transaction.IngredientID)
 	if err != nil {
 		return nil, err
 	}
@@ -70,32 +105,60 @@ func (r *stockTransactionRepo) CreateStockTransaction(transaction *models.StockT
 		}
 	}
 
+	locationID, err := stringPtrToNullUUID(transaction.LocationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stock location ID: %w", err)
+	}
+
 	dbTransaction, err := r.queries.CreateStockTransaction(context.Background(), db.CreateStockTransactionParams{
 		MenuItemID:      menuItemUUID,
 		TransactionType: string(transaction.TransactionType),
-		Quantity:        int32(transaction.Quantity),
-		PreviousStock:   int32(transaction.PreviousStock),
-		CurrentStock:    int32(transaction.CurrentStock),
+		Quantity:        transaction.Quantity.String(),
+		PreviousStock:   transaction.PreviousStock.String(),
+		CurrentStock:    transaction.CurrentStock.String(),
 		Reason:          transaction.Reason,
 		ReferenceType:   refType,
 		ReferenceID:     refUUID,
 		UserID:          userID,
+		IngredientID:    ingredientUUID,
+		UnitCost:        transaction.UnitCost.String(),
+		TotalCost:       transaction.TotalCost.String(),
+		AverageCost:     transaction.AverageCost.String(),
+		LocationID:      locationID,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	quantity, previousStock, currentStock, err := parseTransactionQuantities(dbTransaction.Quantity, dbTransaction.PreviousStock, dbTransaction.CurrentStock)
+	if err != nil {
+		return nil, err
+	}
+	unitCost, totalCost, transactionAverageCost, err := parseTransactionCosts(dbTransaction.UnitCost, dbTransaction.TotalCost, dbTransaction.AverageCost)
+	if err != nil {
+		return nil, err
+	}
+
 	createdTransaction := &models.StockTransaction{
 		ID:              dbTransaction.ID.String(),
-		MenuItemID:      dbTransaction.MenuItemID.String(),
 		TransactionType: types.TransactionType(dbTransaction.TransactionType),
-		Quantity:        int(dbTransaction.Quantity),
-		PreviousStock:   int(dbTransaction.PreviousStock),
-		CurrentStock:    int(dbTransaction.CurrentStock),
+		Quantity:        quantity,
+		PreviousStock:   previousStock,
+		CurrentStock:    currentStock,
+		Unit:            dbTransaction.Unit.String,
 		Reason:          dbTransaction.Reason,
+		IngredientID:    nullUUIDToStringPtr(dbTransaction.IngredientID),
+		UnitCost:        unitCost,
+		TotalCost:       totalCost,
+		AverageCost:     transactionAverageCost,
+		LocationID:      nullUUIDToStringPtr(dbTransaction.LocationID),
 		CreatedAt:       dbTransaction.CreatedAt,
 	}
 
+	if dbTransaction.MenuItemID.Valid {
+		createdTransaction.MenuItemID = dbTransaction.MenuItemID.UUID.String()
+	}
+
 	if dbTransaction.ReferenceType.Valid {
 		createdTransaction.ReferenceType = &dbTransaction.ReferenceType.String
 	}
@@ -113,9 +176,75 @@ func (r *stockTransactionRepo) CreateStockTransaction(transaction *models.StockT
 	return createdTransaction, nil
 }
 
+// stockItemUUIDs parses the menu item or ingredient a stock movement is of
+func stockItemUUIDs(menuItemID string, ingredientID *string) (uuid.NullUUID, uuid.NullUUID, error) {
+	// A transaction moves either a menu item's finished goods or an ingredient
+	var menuItemUUID uuid.NullUUID
+	if menuItemID != "" {
+		parsedUUID, err := uuid.Parse(menuItemID)
+		if err != nil {
+			return uuid.NullUUID{}, uuid.NullUUID{}, err
+		}
+		menuItemUUID = uuid.NullUUID{UUID: parsedUUID, Valid: true}
+	}
+
+	ingredientUUID, err := stringPtrToNullUUID(ingredientID)
+	if err != nil {
+		return uuid.NullUUID{}, uuid.NullUUID{}, err
+	}
+
+	return menuItemUUID, ingredientUUID, nil
+}
+
+// GetStockAverageCost retrieves the weighted average cost of one stock unit of
+// a menu item's finished goods or an ingredient
+func (r *stockTransactionRepo) GetStockAverageCost(menuItemID string, ingredientID *string) (types.DecimalText, error) {
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return types.DecimalText{}, err
+	}
+
+	dbAverageCost, err := r.queries.GetStockAverageCost(context.Background(), db.GetStockAverageCostParams{
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to fetch average cost from database: %w", err)
+	}
+
+	averageCost, err := decimal.NewFromString(dbAverageCost)
+	if err != nil {
+		return types.DecimalText{}, fmt.Errorf("failed to parse average cost %s: %w", dbAverageCost, err)
+	}
+
+	return types.DecimalText(averageCost), nil
+}
+
+// SetStockAverageCost sets the weighted average cost of a menu item's finished
+// goods or an ingredient
+func (r *stockTransactionRepo) SetStockAverageCost(menuItemID string, ingredientID *string, averageCost types.DecimalText) error {
+	menuItemUUID, ingredientUUID, err := stockItemUUIDs(menuItemID, ingredientID)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.SetStockAverageCost(context.Background(), db.SetStockAverageCostParams{
+		AverageCost:  averageCost.String(),
+		MenuItemID:   menuItemUUID,
+		IngredientID: ingredientUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update average cost in database: %w", err)
+	}
+
+	return nil
+}
+
 // ListStockTransactions retrieves a list of stock transactions based on filter
 func (r *stockTransactionRepo) ListStockTransactions(filter models.StockTransactionFilter) ([]*models.StockTransaction, error) {
 	var menuItemID uuid.UUID
+	var ingredientID uuid.UUID
+	var locationID uuid.UUID
 	var startDate time.Time
 	var endDate time.Time
 
@@ -129,6 +258,26 @@ func (r *stockTransactionRepo) ListStockTransactions(filter models.StockTransact
 		menuItemID = uuid.Nil // Use Nil for null case
 	}
 
+	if filter.IngredientID != nil {
+		parsedUUID, err := uuid.Parse(*filter.IngredientID)
+		if err != nil {
+			return nil, err
+		}
+		ingredientID = parsedUUID
+	} else {
+		ingredientID = uuid.Nil
+	}
+
+	if filter.LocationID != nil {
+		parsedUUID, err := uuid.Parse(*filter.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		locationID = parsedUUID
+	} else {
+		locationID = uuid.Nil
+	}
+
 	if filter.StartDate != nil {
 		startDate = *filter.StartDate
 	} else {
@@ -147,6 +296,8 @@ func (r *stockTransactionRepo) ListStockTransactions(filter models.StockTransact
 		Column3: endDate,
 		Limit:   int32(filter.Limit),
 		Offset:  int32(filter.Offset),
+		Column6: ingredientID,
+		Column7: locationID,
 	})
 	if err != nil {
 		return nil, err
@@ -154,17 +305,37 @@ func (r *stockTransactionRepo) ListStockTransactions(filter models.StockTransact
 
 	var transactions []*models.StockTransaction
 	for _, dbTransaction := range dbTransactions {
+		quantity, previousStock, currentStock, err := parseTransactionQuantities(dbTransaction.Quantity, dbTransaction.PreviousStock, dbTransaction.CurrentStock)
+		if err != nil {
+			return nil, err
+		}
+		unitCost, totalCost, averageCost, err := parseTransactionCosts(dbTransaction.UnitCost, dbTransaction.TotalCost, dbTransaction.AverageCost)
+		if err != nil {
+			return nil, err
+		}
+
 		transaction := &models.StockTransaction{
 			ID:              dbTransaction.ID.String(),
-			MenuItemID:      dbTransaction.MenuItemID.String(),
 			TransactionType: types.TransactionType(dbTransaction.TransactionType),
-			Quantity:        int(dbTransaction.Quantity),
-			PreviousStock:   int(dbTransaction.PreviousStock),
-			CurrentStock:    int(dbTransaction.CurrentStock),
+			Quantity:        quantity,
+			PreviousStock:   previousStock,
+			CurrentStock:    currentStock,
+			Unit:            dbTransaction.Unit.String,
 			Reason:          dbTransaction.Reason,
+			IngredientID:    nullUUIDToStringPtr(dbTransaction.IngredientID),
+			IngredientName:  nullStringToPtr(dbTransaction.IngredientName),
+			UnitCost:        unitCost,
+			TotalCost:       totalCost,
+			AverageCost:     averageCost,
+			LocationID:      nullUUIDToStringPtr(dbTransaction.LocationID),
+			LocationName:    nullStringToPtr(dbTransaction.LocationName),
 			CreatedAt:       dbTransaction.CreatedAt,
 		}
 
+		if dbTransaction.MenuItemID.Valid {
+			transaction.MenuItemID = dbTransaction.MenuItemID.UUID.String()
+		}
+
 		if dbTransaction.MenuItemName.Valid {
 			transaction.MenuItemName = dbTransaction.MenuItemName.String
 		}
@@ -196,4 +367,37 @@ func (r *stockTransactionRepo) ListStockTransactions(filter models.StockTransact
 	}
 
 	return transactions, nil
-}
\ No newline at end of file
+}
+
+// ListStockMovedByReference retrieves the net quantity of each menu item and
+// ingredient moved by the stock transactions of a reference
+func (r *stockTransactionRepo) ListStockMovedByReference(referenceType, referenceID string) ([]*models.StockMoved, error) {
+	refUUID, err := uuid.Parse(referenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMoved, err := r.queries.ListStockMovedByReference(context.Background(), db.ListStockMovedByReferenceParams{
+		ReferenceType: sql.NullString{String: referenceType, Valid: true},
+		ReferenceID:   uuid.NullUUID{UUID: refUUID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock moved by reference from database: %w", err)
+	}
+
+	moved := make([]*models.StockMoved, 0, len(dbMoved))
+	for _, item := range dbMoved {
+		quantity, err := parseStockQuantity(item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		moved = append(moved, &models.StockMoved{
+			MenuItemID:   nullUUIDToStringPtr(item.MenuItemID),
+			IngredientID: nullUUIDToStringPtr(item.IngredientID),
+			Quantity:     quantity,
+		})
+	}
+
+	return moved, nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/google/uuid"
+)
+
+// supplierRepo implements the SupplierRepo interface
+type supplierRepo struct {
+	queries *db.Queries
+}
+
+// toSupplierModel converts a sqlc supplier row into the domain model
+func toSupplierModel(dbSupplier db.Supplier) *models.Supplier {
+	return &models.Supplier{
+		ID:          dbSupplier.ID.String(),
+		Name:        dbSupplier.Name,
+		ContactName: nullStringToPtr(dbSupplier.ContactName),
+		Phone:       nullStringToPtr(dbSupplier.Phone),
+		Email:       nullStringToPtr(dbSupplier.Email),
+		Address:     nullStringToPtr(dbSupplier.Address),
+		IsActive:    dbSupplier.IsActive,
+		CreatedAt:   dbSupplier.CreatedAt,
+		UpdatedAt:   dbSupplier.UpdatedAt,
+	}
+}
+
+// CreateSupplier creates a new supplier
+func (r *supplierRepo) CreateSupplier(supplier *models.Supplier) (*models.Supplier, error) {
+	dbSupplier, err := r.queries.CreateSupplier(context.Background(), db.CreateSupplierParams{
+		Name:        supplier.Name,
+		ContactName: ptrToNullString(supplier.ContactName),
+		Phone:       ptrToNullString(supplier.Phone),
+		Email:       ptrToNullString(supplier.Email),
+		Address:     ptrToNullString(supplier.Address),
+		IsActive:    supplier.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier in database: %w", err)
+	}
+
+	return toSupplierModel(dbSupplier), nil
+}
+
+// GetSupplier retrieves a supplier by ID
+func (r *supplierRepo) GetSupplier(id string) (*models.Supplier, error) {
+	supplierID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supplier ID: %w", err)
+	}
+
+	dbSupplier, err := r.queries.GetSupplier(context.Background(), supplierID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("supplier not found")
+		}
+		return nil, fmt.Errorf("failed to fetch supplier from database: %w", err)
+	}
+
+	return toSupplierModel(dbSupplier), nil
+}
+
+// ListSuppliers retrieves suppliers by name based on filter criteria
+func (r *supplierRepo) ListSuppliers(filter models.SupplierFilter) ([]*models.Supplier, error) {
+	var isActive sql.NullBool
+	if filter.IsActive != nil {
+		isActive = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+
+	dbSuppliers, err := r.queries.ListSuppliers(context.Background(), db.ListSuppliersParams{
+		IsActive: isActive,
+		Limit:    int32(filter.Limit),
+		Offset:   int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch suppliers from database: %w", err)
+	}
+
+	suppliers := make([]*models.Supplier, 0, len(dbSuppliers))
+	for _, dbSupplier := range dbSuppliers {
+		suppliers = append(suppliers, toSupplierModel(dbSupplier))
+	}
+
+	return suppliers, nil
+}
+
+// UpdateSupplier updates a supplier's details
+func (r *supplierRepo) UpdateSupplier(supplier *models.Supplier) (*models.Supplier, error) {
+	supplierID, err := uuid.Parse(supplier.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid supplier ID: %w", err)
+	}
+
+	dbSupplier, err := r.queries.UpdateSupplier(context.Background(), db.UpdateSupplierParams{
+		ID:          supplierID,
+		Name:        supplier.Name,
+		ContactName: ptrToNullString(supplier.ContactName),
+		Phone:       ptrToNullString(supplier.Phone),
+		Email:       ptrToNullString(supplier.Email),
+		Address:     ptrToNullString(supplier.Address),
+		IsActive:    supplier.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("supplier not found")
+		}
+		return nil, fmt.Errorf("failed to update supplier in database: %w", err)
+	}
+
+	return toSupplierModel(dbSupplier), nil
+}
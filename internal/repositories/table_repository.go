@@ -0,0 +1,304 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// tableRepo implements the TableRepo interface
+type tableRepo struct {
+	queries *db.Queries
+}
+
+// toFloorAreaModel converts a sqlc floor area row into the domain model
+func toFloorAreaModel(dbArea db.FloorArea) *models.FloorArea {
+	return &models.FloorArea{
+		ID:        dbArea.ID.String(),
+		Name:      dbArea.Name,
+		SortOrder: int(dbArea.SortOrder),
+		IsActive:  dbArea.IsActive,
+		CreatedAt: dbArea.CreatedAt,
+		UpdatedAt: dbArea.UpdatedAt,
+	}
+}
+
+// toDiningTableModel converts a sqlc dining table row into the domain model
+func toDiningTableModel(dbTable db.DiningTable) *models.DiningTable {
+	return &models.DiningTable{
+		ID:          dbTable.ID.String(),
+		FloorAreaID: dbTable.FloorAreaID.String(),
+		Name:        dbTable.Name,
+		Seats:       int(dbTable.Seats),
+		SortOrder:   int(dbTable.SortOrder),
+		IsActive:    dbTable.IsActive,
+		CreatedAt:   dbTable.CreatedAt,
+		UpdatedAt:   dbTable.UpdatedAt,
+	}
+}
+
+// CreateFloorArea creates a new floor area
+func (r *tableRepo) CreateFloorArea(area *models.FloorArea) (*models.FloorArea, error) {
+	dbArea, err := r.queries.CreateFloorArea(context.Background(), db.CreateFloorAreaParams{
+		Name:      area.Name,
+		SortOrder: int32(area.SortOrder),
+		IsActive:  area.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create floor area in database: %w", err)
+	}
+
+	return toFloorAreaModel(dbArea), nil
+}
+
+// GetFloorArea retrieves a floor area by ID
+func (r *tableRepo) GetFloorArea(id string) (*models.FloorArea, error) {
+	areaID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	dbArea, err := r.queries.GetFloorArea(context.Background(), areaID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("floor area not found")
+		}
+		return nil, fmt.Errorf("failed to fetch floor area from database: %w", err)
+	}
+
+	return toFloorAreaModel(dbArea), nil
+}
+
+// ListFloorAreas retrieves the floor areas in floor plan order
+func (r *tableRepo) ListFloorAreas(isActive *bool) ([]*models.FloorArea, error) {
+	var active sql.NullBool
+	if isActive != nil {
+		active = sql.NullBool{Bool: *isActive, Valid: true}
+	}
+
+	dbAreas, err := r.queries.ListFloorAreas(context.Background(), active)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch floor areas from database: %w", err)
+	}
+
+	areas := make([]*models.FloorArea, 0, len(dbAreas))
+	for _, dbArea := range dbAreas {
+		areas = append(areas, toFloorAreaModel(dbArea))
+	}
+
+	return areas, nil
+}
+
+// UpdateFloorArea updates an existing floor area
+func (r *tableRepo) UpdateFloorArea(area *models.FloorArea) (*models.FloorArea, error) {
+	areaID, err := uuid.Parse(area.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	dbArea, err := r.queries.UpdateFloorArea(context.Background(), db.UpdateFloorAreaParams{
+		ID:        areaID,
+		Name:      area.Name,
+		SortOrder: int32(area.SortOrder),
+		IsActive:  area.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("floor area not found")
+		}
+		return nil, fmt.Errorf("failed to update floor area in database: %w", err)
+	}
+
+	return toFloorAreaModel(dbArea), nil
+}
+
+// DeleteFloorArea deletes a floor area
+func (r *tableRepo) DeleteFloorArea(id string) error {
+	areaID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	if err := r.queries.DeleteFloorArea(context.Background(), areaID); err != nil {
+		return fmt.Errorf("failed to delete floor area from database: %w", err)
+	}
+
+	return nil
+}
+
+// CountTablesByFloorArea counts the tables in a floor area
+func (r *tableRepo) CountTablesByFloorArea(floorAreaID string) (int, error) {
+	areaID, err := uuid.Parse(floorAreaID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	count, err := r.queries.CountDiningTablesByFloorAreaID(context.Background(), areaID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count dining tables in database: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// CreateTable creates a new dining table
+func (r *tableRepo) CreateTable(table *models.DiningTable) (*models.DiningTable, error) {
+	areaID, err := uuid.Parse(table.FloorAreaID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	dbTable, err := r.queries.CreateDiningTable(context.Background(), db.CreateDiningTableParams{
+		FloorAreaID: areaID,
+		Name:        table.Name,
+		Seats:       int32(table.Seats),
+		SortOrder:   int32(table.SortOrder),
+		IsActive:    table.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dining table in database: %w", err)
+	}
+
+	return toDiningTableModel(dbTable), nil
+}
+
+// GetTable retrieves a dining table by ID
+func (r *tableRepo) GetTable(id string) (*models.DiningTable, error) {
+	tableID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table ID: %w", err)
+	}
+
+	dbTable, err := r.queries.GetDiningTable(context.Background(), tableID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("table not found")
+		}
+		return nil, fmt.Errorf("failed to fetch dining table from database: %w", err)
+	}
+
+	return toDiningTableModel(dbTable), nil
+}
+
+// ListTables retrieves the dining tables in floor plan order
+func (r *tableRepo) ListTables(filter models.DiningTableFilter) ([]*models.DiningTable, error) {
+	areaID, err := stringPtrToNullUUID(filter.FloorAreaID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	var active sql.NullBool
+	if filter.IsActive != nil {
+		active = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+
+	dbTables, err := r.queries.ListDiningTables(context.Background(), db.ListDiningTablesParams{
+		FloorAreaID: areaID,
+		IsActive:    active,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dining tables from database: %w", err)
+	}
+
+	tables := make([]*models.DiningTable, 0, len(dbTables))
+	for _, dbTable := range dbTables {
+		tables = append(tables, toDiningTableModel(dbTable))
+	}
+
+	return tables, nil
+}
+
+// UpdateTable updates an existing dining table
+func (r *tableRepo) UpdateTable(table *models.DiningTable) (*models.DiningTable, error) {
+	tableID, err := uuid.Parse(table.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table ID: %w", err)
+	}
+
+	areaID, err := uuid.Parse(table.FloorAreaID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor area ID: %w", err)
+	}
+
+	dbTable, err := r.queries.UpdateDiningTable(context.Background(), db.UpdateDiningTableParams{
+		ID:          tableID,
+		FloorAreaID: areaID,
+		Name:        table.Name,
+		Seats:       int32(table.Seats),
+		SortOrder:   int32(table.SortOrder),
+		IsActive:    table.IsActive,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("table not found")
+		}
+		return nil, fmt.Errorf("failed to update dining table in database: %w", err)
+	}
+
+	return toDiningTableModel(dbTable), nil
+}
+
+// DeleteTable deletes a dining table. Past orders keep their lines but lose the table.
+func (r *tableRepo) DeleteTable(id string) error {
+	tableID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid table ID: %w", err)
+	}
+
+	if err := r.queries.DeleteDiningTable(context.Background(), tableID); err != nil {
+		return fmt.Errorf("failed to delete dining table from database: %w", err)
+	}
+
+	return nil
+}
+
+// CountOpenOrdersByTable counts the draft and pending orders on a table
+func (r *tableRepo) CountOpenOrdersByTable(tableID string) (int, error) {
+	tableUUID, err := uuid.Parse(tableID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid table ID: %w", err)
+	}
+
+	count, err := r.queries.CountOpenOrdersByTableID(context.Background(), uuid.NullUUID{UUID: tableUUID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count open orders in database: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// ListOpenTableOrders retrieves the draft and pending orders on any table,
+// oldest first
+func (r *tableRepo) ListOpenTableOrders() ([]*models.TableOrder, error) {
+	dbOrders, err := r.queries.ListOpenTableOrders(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open table orders from database: %w", err)
+	}
+
+	orders := make([]*models.TableOrder, 0, len(dbOrders))
+	for _, dbOrder := range dbOrders {
+		totalAmount, err := decimal.NewFromString(dbOrder.TotalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid total amount for order %s: %w", dbOrder.OrderNumber, err)
+		}
+
+		orders = append(orders, &models.TableOrder{
+			ID:          dbOrder.ID.String(),
+			OrderNumber: dbOrder.OrderNumber,
+			TableID:     dbOrder.TableID.UUID.String(),
+			Status:      types.OrderStatus(dbOrder.Status),
+			GuestCount:  nullInt32ToIntPtr(dbOrder.GuestCount),
+			TotalAmount: types.DecimalText(totalAmount),
+			CreatedAt:   dbOrder.CreatedAt,
+		})
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+)
+
+// UnitOfWork runs a group of repository calls inside a single database transaction
+type UnitOfWork interface {
+	// Do begins a transaction, passes fn a Repository bound to it and commits
+	// when fn returns nil. Any error (or panic) from fn rolls the transaction back.
+	Do(fn func(tx *Repository) error) error
+}
+
+// sqlUnitOfWork implements UnitOfWork on top of *sql.DB and sqlc's WithTx
+type sqlUnitOfWork struct {
+	dbConn  *sql.DB
+	queries *db.Queries
+}
+
+// NewUnitOfWork creates a new UnitOfWork backed by the given database connection
+func NewUnitOfWork(dbConn *sql.DB) UnitOfWork {
+	return &sqlUnitOfWork{
+		dbConn:  dbConn,
+		queries: db.New(dbConn),
+	}
+}
+
+// Do executes fn inside a transaction
+func (u *sqlUnitOfWork) Do(fn func(tx *Repository) error) (err error) {
+	tx, err := u.dbConn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(newRepository(u.queries.WithTx(tx))); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// unitRepo implements the UnitRepo interface
+type unitRepo struct {
+	queries *db.Queries
+}
+
+// toUnitModel converts a sqlc unit of measure row into the domain model
+func toUnitModel(dbUnit db.UnitsOfMeasure) (*models.UnitOfMeasure, error) {
+	factor, err := decimal.NewFromString(dbUnit.Factor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse factor of unit %s: %w", dbUnit.Code, err)
+	}
+
+	return &models.UnitOfMeasure{
+		Code:      dbUnit.Code,
+		Name:      dbUnit.Name,
+		Dimension: dbUnit.Dimension,
+		Factor:    types.DecimalText(factor),
+		CreatedAt: dbUnit.CreatedAt,
+	}, nil
+}
+
+// ListUnits retrieves every unit of measure, grouped by dimension
+func (r *unitRepo) ListUnits() ([]*models.UnitOfMeasure, error) {
+	dbUnits, err := r.queries.ListUnitsOfMeasure(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch units of measure from database: %w", err)
+	}
+
+	units := make([]*models.UnitOfMeasure, 0, len(dbUnits))
+	for _, dbUnit := range dbUnits {
+		unit, err := toUnitModel(dbUnit)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+// GetUnit retrieves a unit of measure by code
+func (r *unitRepo) GetUnit(code string) (*models.UnitOfMeasure, error) {
+	dbUnit, err := r.queries.GetUnitOfMeasure(context.Background(), code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("unit of measure not found")
+		}
+		return nil, fmt.Errorf("failed to fetch unit of measure from database: %w", err)
+	}
+
+	return toUnitModel(dbUnit)
+}
+
+// CreateUnit registers a new unit of measure
+func (r *unitRepo) CreateUnit(unit *models.UnitOfMeasure) (*models.UnitOfMeasure, error) {
+	dbUnit, err := r.queries.CreateUnitOfMeasure(context.Background(), db.CreateUnitOfMeasureParams{
+		Code:      unit.Code,
+		Name:      unit.Name,
+		Dimension: unit.Dimension,
+		Factor:    unit.Factor.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create unit of measure in database: %w", err)
+	}
+
+	return toUnitModel(dbUnit)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// wasteRepo implements the WasteRepo interface
+type wasteRepo struct {
+	queries *db.Queries
+}
+
+// toWasteRecordModel converts a sqlc waste record row into the domain model.
+// The create query returns the same columns without the item and user names.
+func toWasteRecordModel(dbRecord db.ListWasteRecordsRow) (*models.WasteRecord, error) {
+	quantity, err := parseStockQuantity(dbRecord.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	unitCost, err := decimal.NewFromString(dbRecord.UnitCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse waste unit cost %s: %w", dbRecord.UnitCost, err)
+	}
+	totalCost, err := decimal.NewFromString(dbRecord.TotalCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse waste total cost %s: %w", dbRecord.TotalCost, err)
+	}
+
+	return &models.WasteRecord{
+		ID:           dbRecord.ID.String(),
+		MenuItemID:   nullUUIDToStringPtr(dbRecord.MenuItemID),
+		IngredientID: nullUUIDToStringPtr(dbRecord.IngredientID),
+		ItemName:     dbRecord.ItemName,
+		Reason:       types.WasteReason(dbRecord.Reason),
+		Quantity:     quantity,
+		Unit:         dbRecord.Unit,
+		UnitCost:     types.DecimalText(unitCost),
+		TotalCost:    types.DecimalText(totalCost),
+		Notes:        nullStringToPtr(dbRecord.Notes),
+		UserID:       dbRecord.UserID.String(),
+		Username:     dbRecord.Username,
+		CreatedAt:    dbRecord.CreatedAt,
+	}, nil
+}
+
+// CreateWasteRecord records stock written off as waste
+func (r *wasteRepo) CreateWasteRecord(record *models.WasteRecord) (*models.WasteRecord, error) {
+	menuItemID, err := stringPtrToNullUUID(record.MenuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid menu item ID: %w", err)
+	}
+	ingredientID, err := stringPtrToNullUUID(record.IngredientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingredient ID: %w", err)
+	}
+	userID, err := uuid.Parse(record.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbRecord, err := r.queries.CreateWasteRecord(context.Background(), db.CreateWasteRecordParams{
+		MenuItemID:   menuItemID,
+		IngredientID: ingredientID,
+		Reason:       string(record.Reason),
+		Quantity:     record.Quantity.String(),
+		Unit:         record.Unit,
+		UnitCost:     record.UnitCost.String(),
+		TotalCost:    record.TotalCost.String(),
+		Notes:        ptrToNullString(record.Notes),
+		UserID:       userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create waste record in database: %w", err)
+	}
+
+	return toWasteRecordModel(db.ListWasteRecordsRow{
+		ID:           dbRecord.ID,
+		MenuItemID:   dbRecord.MenuItemID,
+		IngredientID: dbRecord.IngredientID,
+		ItemName:     record.ItemName,
+		Reason:       dbRecord.Reason,
+		Quantity:     dbRecord.Quantity,
+		Unit:         dbRecord.Unit,
+		UnitCost:     dbRecord.UnitCost,
+		TotalCost:    dbRecord.TotalCost,
+		Notes:        dbRecord.Notes,
+		UserID:       dbRecord.UserID,
+		Username:     record.Username,
+		CreatedAt:    dbRecord.CreatedAt,
+	})
+}
+
+// ListWasteRecords retrieves waste records, newest first
+func (r *wasteRepo) ListWasteRecords(filter models.WasteFilter) ([]*models.WasteRecord, error) {
+	var reason sql.NullString
+	if filter.Reason != nil {
+		reason = sql.NullString{String: string(*filter.Reason), Valid: true}
+	}
+	userID, err := stringPtrToNullUUID(filter.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	dbRecords, err := r.queries.ListWasteRecords(context.Background(), db.ListWasteRecordsParams{
+		Reason: reason,
+		UserID: userID,
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch waste records from database: %w", err)
+	}
+
+	records := make([]*models.WasteRecord, 0, len(dbRecords))
+	for _, dbRecord := range dbRecords {
+		record, err := toWasteRecordModel(dbRecord)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -3,31 +3,31 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // InventoryService handles inventory-related business logic
 type InventoryService struct {
-	inventoryRepo       repositories.InventoryRepo
-	stockTransactionRepo repositories.StockTransactionRepo
-	menuRepo            repositories.MenuRepo
+	repos
+	menuAvailability *MenuAvailability
 }
 
 // NewInventoryService creates a new inventory service
 func NewInventoryService(
-	inventoryRepo repositories.InventoryRepo,
-	stockTransactionRepo repositories.StockTransactionRepo,
-	menuRepo repositories.MenuRepo,
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	menuAvailability *MenuAvailability,
 ) *InventoryService {
 	return &InventoryService{
-		inventoryRepo:       inventoryRepo,
-		stockTransactionRepo: stockTransactionRepo,
-		menuRepo:            menuRepo,
+		repos:            repos{repo: repo, uow: uow},
+		menuAvailability: menuAvailability,
 	}
 }
 
@@ -39,7 +39,7 @@ func (s *InventoryService) GetInventoryByMenuItem(menuItemID string) (*types.API
 		return nil, errors.New("invalid menu item ID")
 	}
 
-	inventory, err := s.inventoryRepo.GetInventoryByMenuItem(menuItemID)
+	inventory, err := s.repo.InventoryRepo.GetInventoryByMenuItem(menuItemID)
 	if err != nil {
 		return nil, fmt.Errorf("inventory not found for item %s: %v", menuItemID, err)
 	}
@@ -52,7 +52,7 @@ func (s *InventoryService) GetInventoryByMenuItem(menuItemID string) (*types.API
 
 // ListInventory retrieves a list of inventory items with optional filtering
 func (s *InventoryService) ListInventory(filter models.InventoryFilter) (*types.APIResponse, error) {
-	inventories, err := s.inventoryRepo.ListInventory(filter)
+	inventories, err := s.repo.InventoryRepo.ListInventory(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list inventory: %v", err)
 	}
@@ -63,7 +63,10 @@ func (s *InventoryService) ListInventory(filter models.InventoryFilter) (*types.
 	}, nil
 }
 
-// UpdateStock manually adjusts inventory stock levels
+// UpdateStock manually adjusts the stock of a menu item or, when an ingredient
+// is given, of that ingredient. The quantity is converted from the unit it is
+// given in to the unit the stock is counted in. Stock added goes into a new lot
+// valued at the item's current cost; stock taken out comes out of its lots.
 func (s *InventoryService) UpdateStock(userID string, updateData *models.InventoryUpdate) (*types.APIResponse, error) {
 	// Validate user ID
 	_, err := uuid.Parse(userID)
@@ -71,71 +74,202 @@ func (s *InventoryService) UpdateStock(userID string, updateData *models.Invento
 		return nil, errors.New("invalid user ID")
 	}
 
+	if updateData.IngredientID != nil {
+		return s.updateIngredientStock(userID, *updateData.IngredientID, updateData)
+	}
+
 	// Validate menu item ID
 	_, err = uuid.Parse(updateData.MenuItemID)
 	if err != nil {
 		return nil, errors.New("invalid menu item ID")
 	}
 
-	// Get current inventory
-	currentInventory, err := s.inventoryRepo.GetInventoryByMenuItem(updateData.MenuItemID)
+	// The stock level and its transaction record are written as one unit
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Get current inventory
+		currentInventory, err := tx.InventoryRepo.GetInventoryByMenuItem(updateData.MenuItemID)
+		if err != nil {
+			return fmt.Errorf("inventory not found for item %s: %v", updateData.MenuItemID, err)
+		}
+
+		quantity, err := toStockUnit(tx.UnitRepo, updateData.Quantity, updateData.Unit, currentInventory.Unit)
+		if err != nil {
+			return err
+		}
+		if quantity.IsZero() {
+			return errors.New("quantity must not be zero")
+		}
+
+		// Apply the change relative to the stock at the time of the update, so
+		// concurrent adjustments and sales are never lost
+		previousStock, newStock, err := tx.InventoryRepo.AdjustInventoryStock(updateData.MenuItemID, types.FromDecimal(quantity), userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientStock) {
+				return fmt.Errorf("insufficient stock for item %s: only %s %s available, %s %s requested", updateData.MenuItemID, currentInventory.CurrentStock, currentInventory.Unit, quantity.Neg(), currentInventory.Unit)
+			}
+			return fmt.Errorf("failed to update inventory stock: %v", err)
+		}
+
+		if quantity.IsPositive() {
+			menuItem, err := tx.MenuRepo.GetMenuItem(updateData.MenuItemID)
+			if err != nil {
+				return fmt.Errorf("failed to get menu item %s: %v", updateData.MenuItemID, err)
+			}
+			if err := receiveStockLot(tx, &models.StockLot{
+				MenuItemID: &updateData.MenuItemID,
+				ItemName:   menuItem.Name,
+				Unit:       currentInventory.Unit,
+				UnitCost:   menuItem.Cost,
+			}, quantity, updateData, userID); err != nil {
+				return err
+			}
+		} else if err := tx.StockLotRepo.ConsumeStockLots(updateData.MenuItemID, nil, types.FromDecimal(quantity.Neg())); err != nil {
+			return fmt.Errorf("failed to consume stock lots: %v", err)
+		}
+
+		// Create a stock transaction record
+		stockTransaction := &models.StockTransaction{
+			ID:              uuid.New().String(),
+			MenuItemID:      updateData.MenuItemID,
+			TransactionType: getTransactionType(quantity),
+			Quantity:        types.FromDecimal(quantity),
+			PreviousStock:   previousStock,
+			CurrentStock:    newStock,
+			Reason:          updateData.Reason,
+			UserID:          &userID,
+			CreatedAt:       time.Now(),
+		}
+
+		createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+		if err != nil {
+			return fmt.Errorf("failed to create stock transaction: %v", err)
+		}
+		changes.add(createdTransaction)
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("inventory not found for item %s: %v", updateData.MenuItemID, err)
+		return nil, err
 	}
+	s.menuAvailability.announce(changes)
 
-	// Calculate new stock level
-	newStock := currentInventory.CurrentStock + updateData.Quantity
+	// For now, we'll just return the updated inventory info
 
-	// Ensure stock doesn't go negative for non-manager users (in a real implementation, this would be handled differently)
-	// For now, we'll allow negative stock as the data model allows it
-	if newStock < 0 {
-		// This might require special authorization in a real system
+	updatedInventory, err := s.repo.InventoryRepo.GetInventoryByMenuItem(updateData.MenuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated inventory: %v", err)
 	}
 
-	// Update the inventory
-	err = s.inventoryRepo.UpdateInventoryStock(updateData.MenuItemID, newStock, userID)
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedInventory,
+	}, nil
+}
+
+// updateIngredientStock manually adjusts the stock of an ingredient, e.g. when a
+// delivery arrives
+func (s *InventoryService) updateIngredientStock(userID, ingredientID string, updateData *models.InventoryUpdate) (*types.APIResponse, error) {
+	// Validate ingredient ID
+	_, err := uuid.Parse(ingredientID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update inventory stock: %v", err)
+		return nil, errors.New("invalid ingredient ID")
 	}
 
-	// Create a stock transaction record
-	stockTransaction := &models.StockTransaction{
-		ID:              uuid.New().String(),
-		MenuItemID:      updateData.MenuItemID,
-		TransactionType: getTransactionType(updateData.Quantity),
-		Quantity:        updateData.Quantity,
-		PreviousStock:   currentInventory.CurrentStock,
-		CurrentStock:    newStock,
-		Reason:          updateData.Reason,
-		UserID:          &userID,
-		CreatedAt:       time.Now(),
-	}
+	// The stock level and its transaction record are written as one unit
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		ingredient, err := tx.IngredientRepo.GetIngredient(ingredientID)
+		if err != nil {
+			return err
+		}
+
+		quantity, err := toStockUnit(tx.UnitRepo, updateData.Quantity, updateData.Unit, ingredient.Unit)
+		if err != nil {
+			return err
+		}
+		if quantity.IsZero() {
+			return errors.New("quantity must not be zero")
+		}
+
+		previousStock, newStock, err := tx.IngredientRepo.AdjustIngredientStock(ingredientID, types.FromDecimal(quantity), userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientStock) {
+				return fmt.Errorf("insufficient stock for ingredient %s: only %s %s available, %s %s requested", ingredient.Name, ingredient.CurrentStock, ingredient.Unit, quantity.Neg(), ingredient.Unit)
+			}
+			return fmt.Errorf("failed to update ingredient stock: %v", err)
+		}
+
+		if quantity.IsPositive() {
+			if err := receiveStockLot(tx, &models.StockLot{
+				IngredientID: &ingredientID,
+				ItemName:     ingredient.Name,
+				Unit:         ingredient.Unit,
+				UnitCost:     ingredient.CostPrice,
+			}, quantity, updateData, userID); err != nil {
+				return err
+			}
+		} else if err := tx.StockLotRepo.ConsumeStockLots("", &ingredientID, types.FromDecimal(quantity.Neg())); err != nil {
+			return fmt.Errorf("failed to consume stock lots: %v", err)
+		}
 
-	// In a complete implementation, we would add the transaction to the database
-	_, err = s.stockTransactionRepo.CreateStockTransaction(stockTransaction)
+		stockTransaction := &models.StockTransaction{
+			ID:              uuid.New().String(),
+			IngredientID:    &ingredientID,
+			TransactionType: getTransactionType(quantity),
+			Quantity:        types.FromDecimal(quantity),
+			PreviousStock:   previousStock,
+			CurrentStock:    newStock,
+			Reason:          updateData.Reason,
+			UserID:          &userID,
+			CreatedAt:       time.Now(),
+		}
+
+		createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+		if err != nil {
+			return fmt.Errorf("failed to create stock transaction: %v", err)
+		}
+		changes.add(createdTransaction)
+
+		return nil
+	})
 	if err != nil {
-		apiResponse := types.APIResponseWithError("Failed to create stock transaction: " + err.Error())
-		return &apiResponse, err
+		return nil, err
 	}
+	s.menuAvailability.announce(changes)
 
-	// For now, we'll just return the updated inventory info
-
-	updatedInventory, err := s.inventoryRepo.GetInventoryByMenuItem(updateData.MenuItemID)
+	updatedIngredient, err := s.repo.IngredientRepo.GetIngredient(ingredientID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated inventory: %v", err)
+		return nil, fmt.Errorf("failed to get updated ingredient: %v", err)
 	}
 
 	return &types.APIResponse{
 		Success: true,
-		Data:    updatedInventory,
+		Data:    updatedIngredient,
 	}, nil
 }
 
+// receiveStockLot puts stock added by hand into a new lot
+func receiveStockLot(tx *repositories.Repository, lot *models.StockLot, quantity decimal.Decimal, updateData *models.InventoryUpdate, userID string) error {
+	if updateData.ExpiresAt != nil && !updateData.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("cannot add stock of %s that has already expired", lot.ItemName)
+	}
+
+	lot.LotNumber = updateData.LotNumber
+	lot.QuantityReceived = types.FromDecimal(quantity)
+	lot.ExpiresAt = updateData.ExpiresAt
+	lot.ReceivedBy = &userID
+	if _, err := tx.StockLotRepo.CreateStockLot(lot); err != nil {
+		return fmt.Errorf("failed to create stock lot for %s: %v", lot.ItemName, err)
+	}
+	return nil
+}
+
 // getTransactionType returns the appropriate transaction type based on the quantity change
-func getTransactionType(quantity int) types.TransactionType {
-	if quantity > 0 {
+func getTransactionType(quantity decimal.Decimal) types.TransactionType {
+	if quantity.IsPositive() {
 		return types.TransactionTypeIn
-	} else if quantity < 0 {
+	} else if quantity.IsNegative() {
 		return types.TransactionTypeOut
 	}
 	return types.TransactionTypeAdjustment
@@ -143,7 +277,7 @@ func getTransactionType(quantity int) types.TransactionType {
 
 // ListStockTransactions retrieves a list of stock transactions with optional filtering
 func (s *InventoryService) ListStockTransactions(filter models.StockTransactionFilter) (*types.APIResponse, error) {
-	transactions, err := s.stockTransactionRepo.ListStockTransactions(filter)
+	transactions, err := s.repo.StockTransactionRepo.ListStockTransactions(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list stock transactions: %v", err)
 	}
@@ -154,76 +288,275 @@ func (s *InventoryService) ListStockTransactions(filter models.StockTransactionF
 	}, nil
 }
 
+// ListStockLots retrieves the lots with stock left, in the order they are consumed
+func (s *InventoryService) ListStockLots(filter models.StockLotFilter) (*types.APIResponse, error) {
+	lots, err := s.repo.StockLotRepo.ListStockLots(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock lots: %v", err)
+	}
 
+	return &types.APIResponse{
+		Success: true,
+		Data:    lots,
+	}, nil
+}
 
+// ListExpiringStockLots retrieves the lots with stock left that expire within
+// the given number of days, including those that have already expired
+func (s *InventoryService) ListExpiringStockLots(days int) (*types.APIResponse, error) {
+	if days < 0 {
+		return nil, errors.New("days cannot be negative")
+	}
 
+	lots, err := s.repo.StockLotRepo.ListStockLotsExpiringBefore(time.Now().AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring stock lots: %v", err)
+	}
 
+	return &types.APIResponse{
+		Success: true,
+		Data:    lots,
+	}, nil
+}
 
+// validateIngredientUnits checks that an ingredient's stock unit is a known
+// unit of measure and that its purchase unit, if any, converts into it
+func validateIngredientUnits(unitRepo repositories.UnitRepo, ingredient *models.Ingredient) error {
+	if ingredient.PurchaseUnit == nil {
+		_, err := getUnit(unitRepo, ingredient.Unit)
+		return err
+	}
+	return checkUnitsCompatible(unitRepo, *ingredient.PurchaseUnit, ingredient.Unit)
+}
 
+// trimmedUnit returns the unit code without surrounding space, or nil when none is given
+func trimmedUnit(unit *string) *string {
+	if unit == nil || strings.TrimSpace(*unit) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*unit)
+	return &trimmed
+}
 
+// CreateIngredient creates an ingredient with no stock
+func (s *InventoryService) CreateIngredient(ingredientData *models.IngredientCreate) (*types.APIResponse, error) {
+	isActive := true
+	if ingredientData.IsActive != nil {
+		isActive = *ingredientData.IsActive
+	}
 
-// ValidateInventoryForOrder checks if there is sufficient inventory for an order
-func (s *InventoryService) ValidateInventoryForOrder(items []models.OrderItemCreate) error {
-	for _, item := range items {
-		// Get current inventory for the menu item
-		inventory, err := s.inventoryRepo.GetInventoryByMenuItem(item.MenuItemID)
+	ingredient := &models.Ingredient{
+		Name:         strings.TrimSpace(ingredientData.Name),
+		Unit:         strings.TrimSpace(ingredientData.Unit),
+		PurchaseUnit: trimmedUnit(ingredientData.PurchaseUnit),
+		IsActive:     isActive,
+	}
+	if ingredientData.MinimumStock != nil {
+		if decimal.Decimal(*ingredientData.MinimumStock).IsNegative() {
+			return nil, errors.New("minimum stock cannot be negative")
+		}
+		ingredient.MinimumStock = *ingredientData.MinimumStock
+	}
+
+	if err := validateIngredientUnits(s.repo.UnitRepo, ingredient); err != nil {
+		return nil, err
+	}
+
+	createdIngredient, err := s.repo.IngredientRepo.CreateIngredient(ingredient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingredient: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Ingredient created successfully",
+		Data:    createdIngredient,
+	}, nil
+}
+
+// GetIngredient retrieves an ingredient with its stock level
+func (s *InventoryService) GetIngredient(id string) (*types.APIResponse, error) {
+	// Validate ingredient ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid ingredient ID")
+	}
+
+	ingredient, err := s.repo.IngredientRepo.GetIngredient(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    ingredient,
+	}, nil
+}
+
+// ListIngredients retrieves ingredients with optional filtering
+func (s *InventoryService) ListIngredients(filter models.IngredientFilter) (*types.APIResponse, error) {
+	ingredients, err := s.repo.IngredientRepo.ListIngredients(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingredients: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    ingredients,
+	}, nil
+}
+
+// UpdateIngredient updates an ingredient's details. Ingredients are never
+// deleted, as stock transactions refer to them; deactivate one instead. The
+// unit can only change while nothing is counted in it yet.
+func (s *InventoryService) UpdateIngredient(id string, ingredientData *models.IngredientUpdate) (*types.APIResponse, error) {
+	// Validate ingredient ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid ingredient ID")
+	}
+
+	ingredient, err := s.repo.IngredientRepo.GetIngredient(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ingredientData.Name != nil {
+		ingredient.Name = strings.TrimSpace(*ingredientData.Name)
+	}
+	if ingredientData.Unit != nil && strings.TrimSpace(*ingredientData.Unit) != ingredient.Unit {
+		if !decimal.Decimal(ingredient.CurrentStock).IsZero() {
+			return nil, errors.New("cannot change the unit of an ingredient that is in stock")
+		}
+
+		recipeCount, err := s.repo.RecipeRepo.CountRecipesByIngredient(id)
 		if err != nil {
-			return fmt.Errorf("inventory not found for item %s: %v", item.MenuItemID, err)
+			return nil, fmt.Errorf("failed to check recipes: %v", err)
+		}
+		if recipeCount > 0 {
+			return nil, errors.New("cannot change the unit of an ingredient used in recipes")
 		}
 
-		// Check if enough stock is available
-		if inventory.CurrentStock < item.Quantity {
-			menuItem, err := s.menuRepo.GetMenuItem(item.MenuItemID)
-			if err != nil {
-				return fmt.Errorf("menu item %s not found", item.MenuItemID)
-			}
-			return fmt.Errorf("insufficient inventory for item %s: required %d, available %d", 
-				menuItem.Name, item.Quantity, inventory.CurrentStock)
+		ingredient.Unit = strings.TrimSpace(*ingredientData.Unit)
+	}
+	if ingredientData.PurchaseUnit != nil {
+		ingredient.PurchaseUnit = trimmedUnit(ingredientData.PurchaseUnit)
+	}
+	if ingredientData.MinimumStock != nil {
+		if decimal.Decimal(*ingredientData.MinimumStock).IsNegative() {
+			return nil, errors.New("minimum stock cannot be negative")
 		}
+		ingredient.MinimumStock = *ingredientData.MinimumStock
+	}
+	if ingredientData.IsActive != nil {
+		ingredient.IsActive = *ingredientData.IsActive
 	}
 
-	return nil
+	if ingredientData.Unit != nil || ingredientData.PurchaseUnit != nil {
+		if err := validateIngredientUnits(s.repo.UnitRepo, ingredient); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedIngredient, err := s.repo.IngredientRepo.UpdateIngredient(ingredient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ingredient: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Ingredient updated successfully",
+		Data:    updatedIngredient,
+	}, nil
 }
 
-// UpdateInventoryAfterOrder updates inventory after an order is completed
-func (s *InventoryService) UpdateInventoryAfterOrder(items []models.OrderItemCreate, userID string) error {
+// orderItemStockLines returns the stock lines of order items not yet placed
+func orderItemStockLines(items []models.OrderItemCreate) []stockLine {
+	lines := make([]stockLine, 0, len(items))
 	for _, item := range items {
+		lines = append(lines, stockLine{
+			menuItemID:        item.MenuItemID,
+			quantity:          item.Quantity,
+			modifierOptionIDs: item.ModifierOptionIDs,
+		})
+	}
+	return lines
+}
+
+// ValidateInventoryForOrder checks if there is sufficient inventory for an
+// order: ingredients for items made to a recipe, finished goods for the rest
+func (s *InventoryService) ValidateInventoryForOrder(items []models.OrderItemCreate) error {
+	usage, err := stockUsageOf(s.repo.RecipeRepo, orderItemStockLines(items))
+	if err != nil {
+		return err
+	}
+
+	for _, menuItemID := range sortedIDs(usage.menuItems) {
+		required := usage.menuItems[menuItemID]
+
 		// Get current inventory for the menu item
-		inventory, err := s.inventoryRepo.GetInventoryByMenuItem(item.MenuItemID)
+		inventory, err := s.repo.InventoryRepo.GetInventoryByMenuItem(menuItemID)
 		if err != nil {
-			return fmt.Errorf("inventory not found for item %s: %v", item.MenuItemID, err)
+			return fmt.Errorf("inventory not found for item %s: %v", menuItemID, err)
 		}
 
-		// Calculate new stock
-		newStock := inventory.CurrentStock - item.Quantity
+		// Check if enough stock is available outside expired lots
+		available, err := sellableStock(s.repo.StockLotRepo, menuItemID, nil, inventory.CurrentStock)
+		if err != nil {
+			return err
+		}
+		if available.LessThan(required) {
+			menuItem, err := s.repo.MenuRepo.GetMenuItem(menuItemID)
+			if err != nil {
+				return fmt.Errorf("menu item %s not found", menuItemID)
+			}
+			return fmt.Errorf("insufficient inventory for item %s: required %s, available %s",
+				menuItem.Name, required, available)
+		}
+	}
+
+	for _, ingredientID := range sortedIDs(usage.ingredients) {
+		required := usage.ingredients[ingredientID]
 
-		// Update the inventory
-		err = s.inventoryRepo.UpdateInventoryStock(item.MenuItemID, newStock, userID)
+		ingredient, err := s.repo.IngredientRepo.GetIngredient(ingredientID)
 		if err != nil {
-			return fmt.Errorf("failed to update inventory stock for item %s: %v", item.MenuItemID, err)
+			return fmt.Errorf("ingredient %s not found: %v", ingredientID, err)
 		}
 
-		// Create a stock transaction record
-		stockTransaction := &models.StockTransaction{
-			ID:              uuid.New().String(),
-			MenuItemID:      item.MenuItemID,
-			TransactionType: types.TransactionTypeOut,
-			Quantity:        -item.Quantity, // Negative because it's going out
-			PreviousStock:   inventory.CurrentStock,
-			CurrentStock:    newStock,
-			Reason:          "Order fulfillment",
-			UserID:          &userID,
-			CreatedAt:       time.Now(),
+		available, err := sellableStock(s.repo.StockLotRepo, "", &ingredientID, ingredient.CurrentStock)
+		if err != nil {
+			return err
+		}
+		if available.LessThan(required) {
+			return fmt.Errorf("insufficient inventory for ingredient %s: required %s %s, available %s %s",
+				ingredient.Name, required, ingredient.Unit, available, ingredient.Unit)
 		}
+	}
 
-		// In a complete implementation, we would store this transaction
-		_, err = s.stockTransactionRepo.CreateStockTransaction(stockTransaction)
+	return nil
+}
+
+// UpdateInventoryAfterOrder updates inventory after an order is completed,
+// deducting finished goods or recipe ingredients. Either everything is
+// deducted and recorded, or nothing is.
+func (s *InventoryService) UpdateInventoryAfterOrder(items []models.OrderItemCreate, userID string) error {
+	var changes availabilityChanges
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		usage, err := stockUsageOf(tx.RecipeRepo, orderItemStockLines(items))
 		if err != nil {
-			// Log the error but don't fail the entire operation
-			fmt.Printf("Failed to create stock transaction: %v\n", err)
+			return err
 		}
-		// For now, we just log it conceptually
+
+		return moveStock(tx, usage, false, stockMovement{
+			userID:  userID,
+			reason:  "Order fulfillment",
+			changes: &changes,
+		})
+	})
+	if err != nil {
+		return err
 	}
 
+	s.menuAvailability.announce(changes)
 	return nil
-}
\ No newline at end of file
+}
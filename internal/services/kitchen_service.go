@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// KitchenService handles kitchen stations and the order lines shown on the
+// kitchen displays
+type KitchenService struct {
+	repos
+	events *kitchen.Broker
+}
+
+// NewKitchenService creates a new kitchen service
+func NewKitchenService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	events *kitchen.Broker,
+) *KitchenService {
+	return &KitchenService{
+		repos:  repos{repo: repo, uow: uow},
+		events: events,
+	}
+}
+
+// setStationCategories checks the categories exist and routes them to the station
+func setStationCategories(tx *repositories.Repository, stationID string, categoryIDs []string) error {
+	for _, categoryID := range categoryIDs {
+		if _, err := tx.MenuRepo.GetCategory(categoryID); err != nil {
+			return fmt.Errorf("category not found: %s", categoryID)
+		}
+	}
+
+	if err := tx.KitchenRepo.SetStationCategories(stationID, categoryIDs); err != nil {
+		return fmt.Errorf("failed to route categories to station: %v", err)
+	}
+
+	return nil
+}
+
+// CreateStation creates a kitchen station and routes the given categories to it
+func (s *KitchenService) CreateStation(stationData *models.KitchenStationCreate) (*types.APIResponse, error) {
+	station := &models.KitchenStation{
+		Name:        strings.TrimSpace(stationData.Name),
+		Description: stationData.Description,
+		IsActive:    true,
+	}
+	if stationData.IsActive != nil {
+		station.IsActive = *stationData.IsActive
+	}
+
+	if station.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	var createdStation *models.KitchenStation
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		created, err := tx.KitchenRepo.CreateStation(station)
+		if err != nil {
+			return fmt.Errorf("failed to create kitchen station: %v", err)
+		}
+
+		if err := setStationCategories(tx, created.ID, stationData.CategoryIDs); err != nil {
+			return err
+		}
+
+		createdStation, err = tx.KitchenRepo.GetStation(created.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch created kitchen station: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdStation,
+	}, nil
+}
+
+// GetStation retrieves a kitchen station by ID
+func (s *KitchenService) GetStation(id string) (*types.APIResponse, error) {
+	// Validate station ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid kitchen station ID")
+	}
+
+	station, err := s.repo.KitchenRepo.GetStation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    station,
+	}, nil
+}
+
+// ListStations retrieves the kitchen stations
+func (s *KitchenService) ListStations(isActive *bool) (*types.APIResponse, error) {
+	stations, err := s.repo.KitchenRepo.ListStations(isActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kitchen stations: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    stations,
+	}, nil
+}
+
+// UpdateStation updates a kitchen station and, when given, the categories routed to it
+func (s *KitchenService) UpdateStation(id string, stationData *models.KitchenStationUpdate) (*types.APIResponse, error) {
+	// Validate station ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid kitchen station ID")
+	}
+
+	var updatedStation *models.KitchenStation
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		station, err := tx.KitchenRepo.GetStation(id)
+		if err != nil {
+			return err
+		}
+
+		if stationData.Name != nil {
+			station.Name = strings.TrimSpace(*stationData.Name)
+			if station.Name == "" {
+				return errors.New("name cannot be empty")
+			}
+		}
+		if stationData.Description != nil {
+			station.Description = *stationData.Description
+		}
+		if stationData.IsActive != nil {
+			station.IsActive = *stationData.IsActive
+		}
+
+		if _, err := tx.KitchenRepo.UpdateStation(station); err != nil {
+			return fmt.Errorf("failed to update kitchen station: %v", err)
+		}
+
+		if stationData.CategoryIDs != nil {
+			if err := setStationCategories(tx, id, stationData.CategoryIDs); err != nil {
+				return err
+			}
+		}
+
+		updatedStation, err = tx.KitchenRepo.GetStation(id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch updated kitchen station: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedStation,
+	}, nil
+}
+
+// DeleteStation deletes a kitchen station. Lines already sent to it stay on the
+// displays without a station.
+func (s *KitchenService) DeleteStation(id string) (*types.APIResponse, error) {
+	// Validate station ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid kitchen station ID")
+	}
+
+	if _, err := s.repo.KitchenRepo.GetStation(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.KitchenRepo.DeleteStation(id); err != nil {
+		return nil, fmt.Errorf("failed to delete kitchen station: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Kitchen station deleted successfully",
+	}, nil
+}
+
+// ListTickets retrieves the lines on a kitchen display, oldest first
+func (s *KitchenService) ListTickets(filter models.KitchenTicketFilter) (*types.APIResponse, error) {
+	if filter.StationID != nil {
+		if _, err := uuid.Parse(*filter.StationID); err != nil {
+			return nil, errors.New("invalid kitchen station ID")
+		}
+	}
+	if filter.OrderID != nil {
+		if _, err := uuid.Parse(*filter.OrderID); err != nil {
+			return nil, errors.New("invalid order ID")
+		}
+	}
+
+	tickets, err := s.repo.KitchenRepo.ListTickets(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kitchen tickets: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    tickets,
+	}, nil
+}
+
+// BumpTicket moves a line to the given stage, or to the next one when none is
+// given, and pushes the change to the kitchen displays
+func (s *KitchenService) BumpTicket(ticketID string, userID string, bumpData *models.KitchenTicketBump) (*types.APIResponse, error) {
+	// Validate ticket ID
+	_, err := uuid.Parse(ticketID)
+	if err != nil {
+		return nil, errors.New("invalid kitchen ticket ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	ticket, err := s.repo.KitchenRepo.GetTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target types.KitchenStatus
+	if bumpData.Status != nil {
+		target = *bumpData.Status
+		if err := kitchen.CheckBump(ticket.Status, target); err != nil {
+			return nil, err
+		}
+	} else {
+		target, err = kitchen.NextStatus(ticket.Status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The update only applies if the line is still where we read it, so two
+	// screens bumping the same line cannot skip a stage between them
+	if err := s.repo.KitchenRepo.BumpTicket(ticketID, ticket.Status, target, userID); err != nil {
+		if errors.Is(err, repositories.ErrKitchenTicketChanged) {
+			return nil, errors.New("kitchen ticket was bumped by someone else; reload the queue")
+		}
+		return nil, fmt.Errorf("failed to bump kitchen ticket: %v", err)
+	}
+
+	bumpedTicket, err := s.repo.KitchenRepo.GetTicket(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bumped kitchen ticket: %v", err)
+	}
+
+	s.events.Publish(kitchen.Event{
+		Type:    kitchen.EventTicketUpdated,
+		Tickets: []*models.KitchenTicket{bumpedTicket},
+	})
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    bumpedTicket,
+	}, nil
+}
+
+// Subscribe registers a kitchen display for live updates of a station's lines,
+// or of every station's when stationID is empty
+func (s *KitchenService) Subscribe(stationID string) (<-chan kitchen.Event, func(), error) {
+	if s.events == nil {
+		return nil, nil, errors.New("live kitchen updates are not enabled")
+	}
+
+	if stationID != "" {
+		if _, err := uuid.Parse(stationID); err != nil {
+			return nil, nil, errors.New("invalid kitchen station ID")
+		}
+		if _, err := s.repo.KitchenRepo.GetStation(stationID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	events, unsubscribe := s.events.Subscribe(stationID)
+	return events, unsubscribe, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/notify"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Low stock alert delivery limits. A failed alert is retried with a backoff
+// that doubles from a minute, and given up on after MaxLowStockAlertAttempts.
+const (
+	MaxLowStockAlertAttempts = 5
+	LowStockAlertBatchSize   = 20
+)
+
+// LowStockAlertService handles the in-app low stock alert feed and delivers
+// alerts through the configured notification channels. Alerts are raised as
+// stock moves, in the same database transaction, so they are delivered even
+// when the server stops before they are sent.
+type LowStockAlertService struct {
+	lowStockAlertRepo repositories.LowStockAlertRepo
+	channels          []notify.Channel
+}
+
+// NewLowStockAlertService creates a new low stock alert service
+func NewLowStockAlertService(lowStockAlertRepo repositories.LowStockAlertRepo, channels []notify.Channel) *LowStockAlertService {
+	return &LowStockAlertService{
+		lowStockAlertRepo: lowStockAlertRepo,
+		channels:          channels,
+	}
+}
+
+// ListLowStockAlerts retrieves the in-app low stock alert feed with the number
+// of unread alerts
+func (s *LowStockAlertService) ListLowStockAlerts(filter models.LowStockAlertFilter) (*types.APIResponse, error) {
+	alerts, err := s.lowStockAlertRepo.ListLowStockAlerts(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low stock alerts: %v", err)
+	}
+
+	unreadCount, err := s.lowStockAlertRepo.CountUnreadLowStockAlerts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread low stock alerts: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data: &models.LowStockAlertFeed{
+			Alerts:      alerts,
+			UnreadCount: unreadCount,
+		},
+	}, nil
+}
+
+// MarkLowStockAlertRead marks a low stock alert read
+func (s *LowStockAlertService) MarkLowStockAlertRead(id, userID string) (*types.APIResponse, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid low stock alert ID")
+	}
+
+	alert, err := s.lowStockAlertRepo.MarkLowStockAlertRead(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark low stock alert read: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    alert,
+		Message: "Low stock alert marked as read",
+	}, nil
+}
+
+// DispatchLowStockAlerts delivers the alerts that are due through every
+// channel that has not had them yet. An alert is claimed before it is sent, so
+// several servers can dispatch at once without sending it twice, and a channel
+// that took it is not sent it again when another channel fails. Alerts for
+// items restocked before they were sent are not claimed. It returns how many
+// alerts were fully delivered.
+func (s *LowStockAlertService) DispatchLowStockAlerts(ctx context.Context) (int, error) {
+	alerts, err := s.lowStockAlertRepo.ClaimLowStockAlerts(MaxLowStockAlertAttempts, LowStockAlertBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim low stock alerts: %v", err)
+	}
+
+	dispatched := 0
+	for _, alert := range alerts {
+		delivered, err := s.lowStockAlertRepo.ListLowStockAlertDeliveries(alert.ID)
+		if err != nil {
+			return dispatched, fmt.Errorf("failed to list low stock alert deliveries: %v", err)
+		}
+
+		var failures []string
+		for _, channel := range s.channels {
+			if slices.Contains(delivered, channel.Name()) {
+				continue
+			}
+			if err := channel.Send(ctx, alert); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", channel.Name(), err))
+				continue
+			}
+			if err := s.lowStockAlertRepo.CreateLowStockAlertDelivery(alert.ID, channel.Name()); err != nil {
+				return dispatched, fmt.Errorf("failed to record low stock alert delivery: %v", err)
+			}
+		}
+
+		if len(failures) > 0 {
+			if err := s.lowStockAlertRepo.SetLowStockAlertError(alert.ID, strings.Join(failures, "; ")); err != nil {
+				return dispatched, fmt.Errorf("failed to record low stock alert error: %v", err)
+			}
+			continue
+		}
+
+		if err := s.lowStockAlertRepo.MarkLowStockAlertDispatched(alert.ID); err != nil {
+			return dispatched, fmt.Errorf("failed to mark low stock alert dispatched: %v", err)
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// Run dispatches low stock alerts every interval until ctx is cancelled
+func (s *LowStockAlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DispatchLowStockAlerts(ctx); err != nil {
+				log.Printf("Failed to dispatch low stock alerts: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/availability"
+	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+)
+
+// MenuAvailability tells clients about menu items going on or off the menu and
+// drops the cached menu items they appear in. Stock movements sell items out
+// and bring them back as they are recorded; this runs once they are committed.
+type MenuAvailability struct {
+	cache  cache.Cache
+	events *availability.Broker
+}
+
+// NewMenuAvailability creates a new menu availability notifier
+func NewMenuAvailability(cache cache.Cache, events *availability.Broker) *MenuAvailability {
+	return &MenuAvailability{
+		cache:  cache,
+		events: events,
+	}
+}
+
+// subscribe registers a client for menu availability changes
+func (a *MenuAvailability) subscribe() (<-chan availability.Event, func(), error) {
+	if a == nil || a.events == nil {
+		return nil, nil, errors.New("live menu updates are not enabled")
+	}
+
+	events, unsubscribe := a.events.Subscribe()
+	return events, unsubscribe, nil
+}
+
+// menuItemAvailability describes whether a menu item can be sold
+func menuItemAvailability(item *models.MenuItem) *models.MenuItemAvailability {
+	return &models.MenuItemAvailability{
+		MenuItemID:  item.ID,
+		Name:        item.Name,
+		CategoryID:  item.CategoryID,
+		IsAvailable: item.IsAvailable,
+		SoldOut:     item.SoldOut,
+	}
+}
+
+// availabilityChanges collects the menu items the stock transactions of one
+// change sold out or brought back, in the order it happened
+type availabilityChanges []*models.MenuItemAvailability
+
+// add records what a stock transaction sold out or brought back. Adding to a
+// nil collection does nothing.
+func (c *availabilityChanges) add(transaction *models.StockTransaction) {
+	if c != nil && transaction != nil {
+		*c = append(*c, transaction.AvailabilityChanges...)
+	}
+}
+
+// net returns where each item ended up, leaving out items that went off the
+// menu and came back, or the other way round, within the change
+func (c availabilityChanges) net() []*models.MenuItemAvailability {
+	first := make(map[string]*models.MenuItemAvailability)
+	last := make(map[string]*models.MenuItemAvailability)
+	var order []string
+	for _, change := range c {
+		if _, ok := first[change.MenuItemID]; !ok {
+			first[change.MenuItemID] = change
+			order = append(order, change.MenuItemID)
+		}
+		last[change.MenuItemID] = change
+	}
+
+	var changed []*models.MenuItemAvailability
+	for _, id := range order {
+		// Every change flips the item, so it ends up changed only when the
+		// last change went the same way as the first
+		if last[id].IsAvailable == first[id].IsAvailable {
+			changed = append(changed, last[id])
+		}
+	}
+	return changed
+}
+
+// announce drops the cached menu items that changed and pushes the change to
+// clients. It runs after the change is committed, so a failure is only logged.
+// Announcing on a nil notifier does nothing.
+func (a *MenuAvailability) announce(changes availabilityChanges) {
+	if a == nil {
+		return
+	}
+
+	items := changes.net()
+	if len(items) == 0 {
+		return
+	}
+
+	if a.cache != nil {
+		ctx := context.Background()
+
+		// Delete cached individual menu items
+		for _, item := range items {
+			a.cache.Delete(ctx, fmt.Sprintf("menu_item:%s", item.MenuItemID))
+		}
+
+		// Delete all cached menu item lists, including those by category
+		menuItemListKeys, err := a.cache.Keys(ctx, "menu_items:*")
+		if err == nil {
+			for _, key := range menuItemListKeys {
+				a.cache.Delete(ctx, key)
+			}
+		} else {
+			fmt.Printf("Warning: Failed to get menu item list cache keys: %v\n", err)
+		}
+	}
+
+	a.events.Publish(availability.Event{Type: availability.EventMenuAvailabilityChanged, Items: items})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/AndikaPrasetia/pos-cafee/internal/availability"
 	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
@@ -15,20 +16,28 @@ import (
 
 // MenuService handles menu-related business logic
 type MenuService struct {
-	menuRepo      repositories.MenuRepo
-	inventoryRepo repositories.InventoryRepo
-	cache         cache.Cache
+	menuRepo         repositories.MenuRepo
+	inventoryRepo    repositories.InventoryRepo
+	cache            cache.Cache
+	menuAvailability *MenuAvailability
 }
 
 // NewMenuService creates a new menu service
-func NewMenuService(menuRepo repositories.MenuRepo, inventoryRepo repositories.InventoryRepo, cache cache.Cache) *MenuService {
+func NewMenuService(menuRepo repositories.MenuRepo, inventoryRepo repositories.InventoryRepo, cache cache.Cache, menuAvailability *MenuAvailability) *MenuService {
 	return &MenuService{
-		menuRepo:      menuRepo,
-		inventoryRepo: inventoryRepo,
-		cache:         cache,
+		menuRepo:         menuRepo,
+		inventoryRepo:    inventoryRepo,
+		cache:            cache,
+		menuAvailability: menuAvailability,
 	}
 }
 
+// SubscribeAvailability registers a client for menu items selling out and
+// coming back on the menu, whether by stock or by hand
+func (s *MenuService) SubscribeAvailability() (<-chan availability.Event, func(), error) {
+	return s.menuAvailability.subscribe()
+}
+
 // CreateCategory creates a new menu category
 func (s *MenuService) CreateCategory(categoryData *models.CategoryCreate) (*types.APIResponse, error) {
 	category := &models.Category{
@@ -410,6 +419,7 @@ func (s *MenuService) UpdateMenuItem(id string, updateData *models.MenuItemUpdat
 
 	// Store original category ID to invalidate old category cache if needed
 	originalCategoryID := item.CategoryID
+	wasAvailable := item.IsAvailable
 
 	// Update fields if provided in updateData
 	if updateData.Name != nil {
@@ -432,7 +442,9 @@ func (s *MenuService) UpdateMenuItem(id string, updateData *models.MenuItemUpdat
 		item.Cost = *updateData.Cost
 	}
 	if updateData.IsAvailable != nil {
+		// Putting an item on or taking it off the menu by hand overrides stock
 		item.IsAvailable = *updateData.IsAvailable
+		item.SoldOut = false
 	}
 
 	updatedItem, err := s.menuRepo.UpdateMenuItem(item)
@@ -482,6 +494,10 @@ func (s *MenuService) UpdateMenuItem(id string, updateData *models.MenuItemUpdat
 		fmt.Printf("Warning: Failed to get menu items by new category cache keys: %v\n", err)
 	}
 
+	if updatedItem.IsAvailable != wasAvailable {
+		s.menuAvailability.announce(availabilityChanges{menuItemAvailability(updatedItem)})
+	}
+
 	return &types.APIResponse{
 		Success: true,
 		Data:    updatedItem,
@@ -527,6 +543,12 @@ func (s *MenuService) DeleteMenuItem(id string) (*types.APIResponse, error) {
 		fmt.Printf("Warning: Failed to get menu items by category cache keys: %v\n", err)
 	}
 
+	if item.IsAvailable {
+		item.IsAvailable = false
+		item.SoldOut = false
+		s.menuAvailability.announce(availabilityChanges{menuItemAvailability(item)})
+	}
+
 	return &types.APIResponse{
 		Success: true,
 		Message: "Menu item deleted successfully",
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// ModifierService handles menu modifier groups, their options and the menu
+// items they are offered with
+type ModifierService struct {
+	repos
+}
+
+// NewModifierService creates a new modifier service
+func NewModifierService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+) *ModifierService {
+	return &ModifierService{
+		repos: repos{repo: repo, uow: uow},
+	}
+}
+
+// validateModifierGroup checks a group's name and selection rule
+func validateModifierGroup(group *models.ModifierGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if group.MinSelect < 0 {
+		return errors.New("min_select cannot be negative")
+	}
+	if group.MaxSelect <= 0 {
+		return errors.New("max_select must be greater than zero")
+	}
+	if group.MinSelect > group.MaxSelect {
+		return errors.New("min_select cannot be greater than max_select")
+	}
+
+	return nil
+}
+
+// validateModifierOption checks an option's name
+func validateModifierOption(option *models.ModifierOption) error {
+	option.Name = strings.TrimSpace(option.Name)
+	if option.Name == "" {
+		return errors.New("option name is required")
+	}
+
+	return nil
+}
+
+// CreateModifierGroup creates a modifier group together with its options
+func (s *ModifierService) CreateModifierGroup(groupData *models.ModifierGroupCreate) (*types.APIResponse, error) {
+	group := &models.ModifierGroup{
+		Name:        groupData.Name,
+		Description: groupData.Description,
+		MinSelect:   groupData.MinSelect,
+		MaxSelect:   groupData.MaxSelect,
+		IsActive:    true,
+	}
+	if groupData.IsActive != nil {
+		group.IsActive = *groupData.IsActive
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	options := make([]*models.ModifierOption, 0, len(groupData.Options))
+	for _, optionData := range groupData.Options {
+		option := newModifierOption(optionData)
+		if err := validateModifierOption(option); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+
+	var createdGroup *models.ModifierGroup
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		var err error
+		createdGroup, err = tx.ModifierRepo.CreateModifierGroup(group)
+		if err != nil {
+			return fmt.Errorf("failed to create modifier group: %v", err)
+		}
+
+		for _, option := range options {
+			option.ModifierGroupID = createdGroup.ID
+			createdOption, err := tx.ModifierRepo.CreateModifierOption(option)
+			if err != nil {
+				return fmt.Errorf("failed to create modifier option %s: %v", option.Name, err)
+			}
+			createdGroup.Options = append(createdGroup.Options, *createdOption)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdGroup,
+	}, nil
+}
+
+// newModifierOption builds an option from request data; options are available unless stated otherwise
+func newModifierOption(optionData models.ModifierOptionCreate) *models.ModifierOption {
+	option := &models.ModifierOption{
+		Name:        optionData.Name,
+		PriceDelta:  optionData.PriceDelta,
+		IsAvailable: true,
+		SortOrder:   optionData.SortOrder,
+	}
+	if optionData.IsAvailable != nil {
+		option.IsAvailable = *optionData.IsAvailable
+	}
+	return option
+}
+
+// GetModifierGroup retrieves a modifier group with its options
+func (s *ModifierService) GetModifierGroup(id string) (*types.APIResponse, error) {
+	// Validate modifier group ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	group, err := s.repo.ModifierRepo.GetModifierGroup(id)
+	if err != nil {
+		return nil, fmt.Errorf("modifier group not found: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    group,
+	}, nil
+}
+
+// ListModifierGroups retrieves a list of modifier groups based on filter criteria
+func (s *ModifierService) ListModifierGroups(filter models.ModifierGroupFilter) (*types.APIResponse, error) {
+	groups, err := s.repo.ModifierRepo.ListModifierGroups(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modifier groups: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    groups,
+	}, nil
+}
+
+// UpdateModifierGroup updates an existing modifier group
+func (s *ModifierService) UpdateModifierGroup(id string, groupData *models.ModifierGroupUpdate) (*types.APIResponse, error) {
+	// Validate modifier group ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	// Get existing modifier group
+	existingGroup, err := s.repo.ModifierRepo.GetModifierGroup(id)
+	if err != nil {
+		return nil, fmt.Errorf("modifier group not found: %v", err)
+	}
+
+	// Update fields if provided
+	if groupData.Name != nil {
+		existingGroup.Name = *groupData.Name
+	}
+	if groupData.Description != nil {
+		existingGroup.Description = *groupData.Description
+	}
+	if groupData.MinSelect != nil {
+		existingGroup.MinSelect = *groupData.MinSelect
+	}
+	if groupData.MaxSelect != nil {
+		existingGroup.MaxSelect = *groupData.MaxSelect
+	}
+	if groupData.IsActive != nil {
+		existingGroup.IsActive = *groupData.IsActive
+	}
+
+	if err := validateModifierGroup(existingGroup); err != nil {
+		return nil, err
+	}
+
+	// Save updated modifier group
+	updatedGroup, err := s.repo.ModifierRepo.UpdateModifierGroup(existingGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update modifier group: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedGroup,
+	}, nil
+}
+
+// DeleteModifierGroup deletes a modifier group and its options
+func (s *ModifierService) DeleteModifierGroup(id string) (*types.APIResponse, error) {
+	// Validate modifier group ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	// Check if modifier group exists
+	_, err = s.repo.ModifierRepo.GetModifierGroup(id)
+	if err != nil {
+		return nil, fmt.Errorf("modifier group not found: %v", err)
+	}
+
+	err = s.repo.ModifierRepo.DeleteModifierGroup(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete modifier group: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Modifier group deleted successfully",
+	}, nil
+}
+
+// AddModifierOption adds an option to an existing modifier group
+func (s *ModifierService) AddModifierOption(groupID string, optionData *models.ModifierOptionCreate) (*types.APIResponse, error) {
+	// Validate modifier group ID
+	_, err := uuid.Parse(groupID)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	// Check if modifier group exists
+	_, err = s.repo.ModifierRepo.GetModifierGroup(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("modifier group not found: %v", err)
+	}
+
+	option := newModifierOption(*optionData)
+	option.ModifierGroupID = groupID
+	if err := validateModifierOption(option); err != nil {
+		return nil, err
+	}
+
+	createdOption, err := s.repo.ModifierRepo.CreateModifierOption(option)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create modifier option: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdOption,
+	}, nil
+}
+
+// UpdateModifierOption updates an existing modifier option
+func (s *ModifierService) UpdateModifierOption(id string, optionData *models.ModifierOptionUpdate) (*types.APIResponse, error) {
+	// Validate modifier option ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid modifier option ID")
+	}
+
+	// Get existing modifier option
+	existingOption, err := s.repo.ModifierRepo.GetModifierOption(id)
+	if err != nil {
+		return nil, fmt.Errorf("modifier option not found: %v", err)
+	}
+
+	// Update fields if provided
+	if optionData.Name != nil {
+		existingOption.Name = *optionData.Name
+	}
+	if optionData.PriceDelta != nil {
+		existingOption.PriceDelta = *optionData.PriceDelta
+	}
+	if optionData.IsAvailable != nil {
+		existingOption.IsAvailable = *optionData.IsAvailable
+	}
+	if optionData.SortOrder != nil {
+		existingOption.SortOrder = *optionData.SortOrder
+	}
+
+	if err := validateModifierOption(existingOption); err != nil {
+		return nil, err
+	}
+
+	// Save updated modifier option
+	updatedOption, err := s.repo.ModifierRepo.UpdateModifierOption(existingOption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update modifier option: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedOption,
+	}, nil
+}
+
+// DeleteModifierOption deletes a modifier option
+func (s *ModifierService) DeleteModifierOption(id string) (*types.APIResponse, error) {
+	// Validate modifier option ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid modifier option ID")
+	}
+
+	// Check if modifier option exists
+	_, err = s.repo.ModifierRepo.GetModifierOption(id)
+	if err != nil {
+		return nil, fmt.Errorf("modifier option not found: %v", err)
+	}
+
+	err = s.repo.ModifierRepo.DeleteModifierOption(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete modifier option: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Modifier option deleted successfully",
+	}, nil
+}
+
+// ListMenuItemModifierGroups retrieves the modifier groups offered with a menu item
+func (s *ModifierService) ListMenuItemModifierGroups(menuItemID string) (*types.APIResponse, error) {
+	// Validate menu item ID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, errors.New("invalid menu item ID")
+	}
+
+	groups, err := s.repo.ModifierRepo.ListMenuItemModifierGroups(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list menu item modifier groups: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    groups,
+	}, nil
+}
+
+// AttachModifierGroup offers a modifier group with a menu item
+func (s *ModifierService) AttachModifierGroup(menuItemID string, attachData *models.MenuItemModifierGroupAttach) (*types.APIResponse, error) {
+	// Validate menu item ID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, errors.New("invalid menu item ID")
+	}
+
+	// Validate modifier group ID
+	_, err = uuid.Parse(attachData.ModifierGroupID)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	// Check that both sides exist
+	_, err = s.repo.MenuRepo.GetMenuItem(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("menu item not found: %v", err)
+	}
+
+	_, err = s.repo.ModifierRepo.GetModifierGroup(attachData.ModifierGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("modifier group not found: %v", err)
+	}
+
+	err = s.repo.ModifierRepo.AttachModifierGroup(menuItemID, attachData.ModifierGroupID, attachData.SortOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach modifier group: %v", err)
+	}
+
+	return s.ListMenuItemModifierGroups(menuItemID)
+}
+
+// DetachModifierGroup stops offering a modifier group with a menu item
+func (s *ModifierService) DetachModifierGroup(menuItemID, groupID string) (*types.APIResponse, error) {
+	// Validate menu item ID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, errors.New("invalid menu item ID")
+	}
+
+	// Validate modifier group ID
+	_, err = uuid.Parse(groupID)
+	if err != nil {
+		return nil, errors.New("invalid modifier group ID")
+	}
+
+	err = s.repo.ModifierRepo.DetachModifierGroup(menuItemID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detach modifier group: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Modifier group detached successfully",
+	}, nil
+}
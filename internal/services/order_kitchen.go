@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+)
+
+// orderTicketLimit bounds how many kitchen lines are read back for one order
+const orderTicketLimit = 500
+
+// openOrderTickets returns the lines of an order that are still on the kitchen display
+func openOrderTickets(kitchenRepo repositories.KitchenRepo, orderID string) ([]*models.KitchenTicket, error) {
+	tickets, err := kitchenRepo.ListTickets(models.KitchenTicketFilter{
+		OrderID: &orderID,
+		Limit:   orderTicketLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kitchen tickets: %v", err)
+	}
+	return tickets, nil
+}
+
+// notifyKitchen pushes the order's open lines picked by keep to the kitchen
+// displays. It runs after the change is committed, so a failure is only logged.
+func (s *OrderService) notifyKitchen(eventType, orderID string, keep func(ticket *models.KitchenTicket) bool) {
+	if s.kitchenEvents == nil {
+		return
+	}
+
+	tickets, err := openOrderTickets(s.repo.KitchenRepo, orderID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to notify kitchen of order %s: %v\n", orderID, err)
+		return
+	}
+
+	var kept []*models.KitchenTicket
+	for _, ticket := range tickets {
+		if keep(ticket) {
+			kept = append(kept, ticket)
+		}
+	}
+
+	s.kitchenEvents.Publish(kitchen.Event{Type: eventType, Tickets: kept})
+}
+
+// notifyKitchenQueued pushes newly sent lines to the kitchen displays
+func (s *OrderService) notifyKitchenQueued(orderID string, ticketIDs []string) {
+	if len(ticketIDs) == 0 {
+		return
+	}
+
+	queued := make(map[string]bool, len(ticketIDs))
+	for _, id := range ticketIDs {
+		queued[id] = true
+	}
+
+	s.notifyKitchen(kitchen.EventTicketsQueued, orderID, func(ticket *models.KitchenTicket) bool {
+		return queued[ticket.ID]
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// selectModifiers checks the modifier options chosen for a menu item against the
+// groups offered with it and returns the selection and the amount it adds to
+// the unit price
+func selectModifiers(tx *repositories.Repository, menuItemID string, optionIDs []string) ([]pricing.SelectedModifier, decimal.Decimal, error) {
+	groups, err := tx.ModifierRepo.ListMenuItemModifierGroups(menuItemID)
+	if err != nil {
+		return nil, decimal.Zero, fmt.Errorf("failed to get modifier groups: %v", err)
+	}
+
+	// Menu items without modifiers need no lookup beyond this
+	if len(groups) == 0 && len(optionIDs) == 0 {
+		return nil, decimal.Zero, nil
+	}
+
+	pricingGroups := make([]pricing.ModifierGroup, 0, len(groups))
+	for _, group := range groups {
+		pricingGroup := pricing.ModifierGroup{
+			ID:        group.ID,
+			Name:      group.Name,
+			MinSelect: group.MinSelect,
+			MaxSelect: group.MaxSelect,
+		}
+		for _, option := range group.Options {
+			pricingGroup.Options = append(pricingGroup.Options, pricing.ModifierOption{
+				ID:          option.ID,
+				Name:        option.Name,
+				PriceDelta:  decimal.Decimal(option.PriceDelta),
+				IsAvailable: option.IsAvailable,
+			})
+		}
+		pricingGroups = append(pricingGroups, pricingGroup)
+	}
+
+	return pricing.SelectModifiers(pricingGroups, optionIDs)
+}
+
+// recordModifiers stores the modifiers chosen for an order line, copying their
+// names and prices so the line reads the same after the menu changes
+func recordModifiers(tx *repositories.Repository, orderItemID string, selected []pricing.SelectedModifier) ([]models.OrderItemModifier, error) {
+	var recorded []models.OrderItemModifier
+	for _, modifier := range selected {
+		optionID := modifier.OptionID
+		created, err := tx.ModifierRepo.CreateOrderItemModifier(&models.OrderItemModifier{
+			OrderItemID:      orderItemID,
+			ModifierOptionID: &optionID,
+			GroupName:        modifier.GroupName,
+			OptionName:       modifier.OptionName,
+			PriceDelta:       types.FromDecimal(modifier.PriceDelta),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record modifier %s: %v", modifier.OptionName, err)
+		}
+		recorded = append(recorded, *created)
+	}
+
+	return recorded, nil
+}
+
+// attachModifiers adds the chosen modifiers to the order lines they belong to
+func attachModifiers(items []*models.OrderItemWithDetails, modifiers []*models.OrderItemModifier) {
+	byItem := make(map[string][]models.OrderItemModifier)
+	for _, modifier := range modifiers {
+		byItem[modifier.OrderItemID] = append(byItem[modifier.OrderItemID], *modifier)
+	}
+
+	for _, item := range items {
+		item.Modifiers = byItem[item.ID]
+	}
+}
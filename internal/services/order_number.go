@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OrderNumberFormat describes how an order number is built from its daily sequence
+type OrderNumberFormat struct {
+	// Prefix identifies the outlet; each prefix has its own daily counter
+	Prefix string
+	// Pattern may contain the placeholders {prefix}, {date} and {seq}
+	Pattern string
+	// DateLayout is the Go time layout used for {date}
+	DateLayout string
+	// SequenceDigits is the minimum, zero-padded width of {seq}
+	SequenceDigits int
+}
+
+// DefaultOrderNumberFormat produces numbers such as ORD-20261016-0001
+var DefaultOrderNumberFormat = OrderNumberFormat{
+	Prefix:         "ORD",
+	Pattern:        "{prefix}-{date}-{seq}",
+	DateLayout:     "20060102",
+	SequenceDigits: 4,
+}
+
+// Format renders the order number for the given business day and sequence number
+func (f OrderNumberFormat) Format(businessDate time.Time, sequence int) string {
+	f = f.withDefaults()
+
+	return strings.NewReplacer(
+		"{prefix}", f.Prefix,
+		"{date}", businessDate.Format(f.DateLayout),
+		"{seq}", fmt.Sprintf("%0*d", f.SequenceDigits, sequence),
+	).Replace(f.Pattern)
+}
+
+// Validate checks that the format yields unique order numbers. The counter
+// restarts every business day and is kept per prefix, and order numbers are
+// unique, so the pattern needs {seq}, {date} and {prefix}, and the date layout
+// must include the year, the month and the day.
+func (f OrderNumberFormat) Validate() error {
+	f = f.withDefaults()
+
+	if !strings.Contains(f.Pattern, "{seq}") {
+		return errors.New("order number pattern must contain {seq}")
+	}
+	if !strings.Contains(f.Pattern, "{date}") {
+		return errors.New("order number pattern must contain {date}, as the sequence restarts every day")
+	}
+	if !strings.Contains(f.Pattern, "{prefix}") {
+		return errors.New("order number pattern must contain {prefix}, as each prefix has its own sequence")
+	}
+
+	// Each pair falls on the same weekday, so a weekday name cannot stand in
+	// for the part that differs: 2001 and 2029 share a calendar, and February
+	// 2001 has exactly four weeks.
+	day := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+	for _, other := range []struct {
+		part string
+		date time.Time
+	}{
+		{"year", day.AddDate(28, 0, 0)},
+		{"month", day.AddDate(0, 1, 0)},
+		{"day", day.AddDate(0, 0, 7)},
+	} {
+		if day.Format(f.DateLayout) == other.date.Format(f.DateLayout) {
+			return fmt.Errorf("order number date layout %q does not include the %s", f.DateLayout, other.part)
+		}
+	}
+
+	return nil
+}
+
+// withDefaults fills any unset field from DefaultOrderNumberFormat
+func (f OrderNumberFormat) withDefaults() OrderNumberFormat {
+	if f.Prefix == "" {
+		f.Prefix = DefaultOrderNumberFormat.Prefix
+	}
+	if f.Pattern == "" {
+		f.Pattern = DefaultOrderNumberFormat.Pattern
+	}
+	if f.DateLayout == "" {
+		f.DateLayout = DefaultOrderNumberFormat.DateLayout
+	}
+	if f.SequenceDigits <= 0 {
+		f.SequenceDigits = DefaultOrderNumberFormat.SequenceDigits
+	}
+	return f
+}
+
+// businessDay returns the calendar day of t as a date-only value, which is the
+// key the order number counter is reset on
+func businessDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
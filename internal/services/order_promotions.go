@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// applyPromotions evaluates the running automatic promotions, plus the voucher if
+// one is given, against the order's lines and records the winning ones on the order
+func (s *OrderService) applyPromotions(tx *repositories.Repository, order *models.Order, orderItems []*models.OrderItem, voucherCode *string) ([]pricing.AppliedPromotion, error) {
+	now := time.Now()
+
+	promotions, err := tx.PromotionRepo.ListAutomaticPromotions(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %v", err)
+	}
+
+	lines, err := promotionLines(tx, orderItems)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]pricing.Promotion, 0, len(promotions)+1)
+	for _, promotion := range promotions {
+		candidates = append(candidates, toPricingPromotion(promotion))
+	}
+
+	if voucherCode != nil && strings.TrimSpace(*voucherCode) != "" {
+		code := normalizeVoucherCode(*voucherCode)
+		voucher, err := tx.PromotionRepo.GetPromotionByVoucherCode(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid voucher code %s: %v", code, err)
+		}
+
+		if !voucher.IsActive || (voucher.UsageLimit != nil && voucher.UsageCount >= *voucher.UsageLimit) {
+			return nil, fmt.Errorf("voucher %s is no longer available", code)
+		}
+
+		// Tell the cashier straight away when the voucher cannot discount this order
+		voucherPromotion := toPricingPromotion(voucher)
+		if len(pricing.EvaluatePromotions([]pricing.Promotion{voucherPromotion}, lines, now)) == 0 {
+			return nil, fmt.Errorf("voucher %s does not apply to this order", code)
+		}
+
+		candidates = append(candidates, voucherPromotion)
+	}
+
+	applied := pricing.EvaluatePromotions(candidates, lines, now)
+
+	// Replace whatever was applied on an earlier evaluation
+	if err := tx.PromotionRepo.DeleteOrderPromotions(order.ID); err != nil {
+		return nil, fmt.Errorf("failed to clear order promotions: %v", err)
+	}
+
+	for _, promotion := range applied {
+		promotionID := promotion.PromotionID
+		_, err := tx.PromotionRepo.CreateOrderPromotion(&models.OrderPromotion{
+			ID:             uuid.New().String(),
+			OrderID:        order.ID,
+			PromotionID:    &promotionID,
+			PromotionName:  promotion.Name,
+			DiscountAmount: types.FromDecimal(promotion.Amount),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record promotion %s: %v", promotion.Name, err)
+		}
+	}
+
+	return applied, nil
+}
+
+// applyDraftPromotions re-evaluates the automatic promotions of a draft order and
+// stores its discounted total. Draft orders are not charged yet, so the total is
+// simply the subtotal less the promotions.
+func (s *OrderService) applyDraftPromotions(tx *repositories.Repository, order *models.Order, orderItems []*models.OrderItem) error {
+	applied, err := s.applyPromotions(tx, order, orderItems, nil)
+	if err != nil {
+		return err
+	}
+
+	discount := pricing.TotalDiscount(applied)
+	order.DiscountAmount = types.FromDecimal(discount)
+	order.TotalAmount = types.FromDecimal(decimal.Decimal(order.SubtotalAmount).Sub(discount))
+
+	if err := tx.OrderRepo.UpdateOrderTotal(order); err != nil {
+		return fmt.Errorf("failed to update order total: %v", err)
+	}
+
+	return nil
+}
+
+// releaseOrderPromotions gives back the redemptions used by a completed order
+func releaseOrderPromotions(tx *repositories.Repository, orderID string) error {
+	orderPromotions, err := tx.PromotionRepo.ListOrderPromotions(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order promotions: %v", err)
+	}
+
+	for _, orderPromotion := range orderPromotions {
+		// The promotion may have been deleted since
+		if orderPromotion.PromotionID == nil {
+			continue
+		}
+
+		if err := tx.PromotionRepo.ReleasePromotionUsage(*orderPromotion.PromotionID); err != nil {
+			return fmt.Errorf("failed to release promotion %s: %v", orderPromotion.PromotionName, err)
+		}
+	}
+
+	return nil
+}
+
+// promotionLines describes the order lines to the promotion engine, looking up
+// the category of each menu item so category promotions can match
+func promotionLines(tx *repositories.Repository, orderItems []*models.OrderItem) ([]pricing.Line, error) {
+	categories := make(map[string]string)
+	lines := make([]pricing.Line, 0, len(orderItems))
+
+	for _, orderItem := range orderItems {
+		categoryID, ok := categories[orderItem.MenuItemID]
+		if !ok {
+			menuItem, err := tx.MenuRepo.GetMenuItem(orderItem.MenuItemID)
+			if err != nil {
+				return nil, fmt.Errorf("menu item not found: %s", orderItem.MenuItemID)
+			}
+			categoryID = menuItem.CategoryID
+			categories[orderItem.MenuItemID] = categoryID
+		}
+
+		lines = append(lines, pricing.Line{
+			MenuItemID: orderItem.MenuItemID,
+			CategoryID: categoryID,
+			Quantity:   orderItem.Quantity,
+			UnitPrice:  decimal.Decimal(orderItem.UnitPrice),
+		})
+	}
+
+	return lines, nil
+}
+
+// toPricingPromotion converts a stored promotion into the promotion engine's rule
+func toPricingPromotion(promotion *models.Promotion) pricing.Promotion {
+	rule := pricing.Promotion{
+		ID:          promotion.ID,
+		Name:        promotion.Name,
+		Type:        promotion.Type,
+		Value:       decimal.Decimal(promotion.Value),
+		TargetType:  promotion.TargetType,
+		MinSpend:    decimal.Decimal(promotion.MinSpend),
+		StartsAt:    promotion.StartsAt,
+		EndsAt:      promotion.EndsAt,
+		IsStackable: promotion.IsStackable,
+		Priority:    promotion.Priority,
+	}
+
+	if promotion.BuyQuantity != nil {
+		rule.BuyQuantity = *promotion.BuyQuantity
+	}
+	if promotion.GetQuantity != nil {
+		rule.GetQuantity = *promotion.GetQuantity
+	}
+	if promotion.TargetID != nil {
+		rule.TargetID = *promotion.TargetID
+	}
+	if promotion.DailyStartTime != nil {
+		rule.DailyStartTime = *promotion.DailyStartTime
+	}
+	if promotion.DailyEndTime != nil {
+		rule.DailyEndTime = *promotion.DailyEndTime
+	}
+
+	return rule
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
 	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/google/uuid"
@@ -16,33 +19,68 @@ import (
 
 // OrderService handles order-related business logic
 type OrderService struct {
-	orderRepo            repositories.OrderRepo
-	orderItemRepo        repositories.OrderItemRepo
-	menuRepo             repositories.MenuRepo
-	inventoryRepo        repositories.InventoryRepo
-	stockTransactionRepo repositories.StockTransactionRepo
-	cache                cache.Cache
+	repos
+	cache            cache.Cache
+	kitchenEvents    *kitchen.Broker
+	orderNumbers     OrderNumberFormat
+	pricingRules     pricing.Rules
+	menuAvailability *MenuAvailability
 }
 
 // NewOrderService creates a new order service
 func NewOrderService(
-	orderRepo repositories.OrderRepo,
-	orderItemRepo repositories.OrderItemRepo,
-	menuRepo repositories.MenuRepo,
-	inventoryRepo repositories.InventoryRepo,
-	stockTransactionRepo repositories.StockTransactionRepo,
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
 	cache cache.Cache,
+	kitchenEvents *kitchen.Broker,
+	orderNumbers OrderNumberFormat,
+	pricingRules pricing.Rules,
+	menuAvailability *MenuAvailability,
 ) *OrderService {
 	return &OrderService{
-		orderRepo:            orderRepo,
-		orderItemRepo:        orderItemRepo,
-		menuRepo:             menuRepo,
-		inventoryRepo:        inventoryRepo,
-		stockTransactionRepo: stockTransactionRepo,
-		cache:                cache,
+		repos:            repos{repo: repo, uow: uow},
+		cache:            cache,
+		kitchenEvents:    kitchenEvents,
+		orderNumbers:     orderNumbers.withDefaults(),
+		pricingRules:     pricingRules,
+		menuAvailability: menuAvailability,
 	}
 }
 
+// getOrCreateInventory fetches the inventory record for a menu item, creating an
+// empty one first if the item has never been stocked
+func getOrCreateInventory(inventoryRepo repositories.InventoryRepo, menuItemID string) (*models.Inventory, error) {
+	inventory, err := inventoryRepo.GetInventoryByMenuItem(menuItemID)
+	if err == nil {
+		return inventory, nil
+	}
+
+	// If no inventory exists for this item, create a new record
+	err = inventoryRepo.CreateInventoryRecord(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory record for menu item %s: %v", menuItemID, err)
+	}
+
+	// Try to fetch again
+	inventory, err = inventoryRepo.GetInventoryByMenuItem(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory for menu item %s: %v", menuItemID, err)
+	}
+
+	return inventory, nil
+}
+
+// claimOrderNumber claims the next number of the day. It is released again if
+// the transaction rolls back.
+func (s *OrderService) claimOrderNumber(tx *repositories.Repository) (string, error) {
+	now := time.Now()
+	sequence, err := tx.OrderRepo.NextOrderNumber(s.orderNumbers.Prefix, businessDay(now))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate order number: %v", err)
+	}
+	return s.orderNumbers.Format(now, sequence), nil
+}
+
 // CreateOrder creates a new draft order
 func (s *OrderService) CreateOrder(userID string, orderData *models.OrderCreate) (*types.APIResponse, error) {
 	// Validate user ID format
@@ -58,6 +96,7 @@ func (s *OrderService) CreateOrder(userID string, orderData *models.OrderCreate)
 
 	// Validate items and calculate totals
 	var itemsWithDetails []models.OrderItemWithDetails
+	var lineModifiers [][]pricing.SelectedModifier
 	var totalAmount types.DecimalText
 
 	for _, itemData := range orderData.Items {
@@ -66,117 +105,133 @@ func (s *OrderService) CreateOrder(userID string, orderData *models.OrderCreate)
 		if err != nil {
 			return nil, fmt.Errorf("invalid menu item ID: %s", itemData.MenuItemID)
 		}
+	}
 
-		// Get menu item to verify availability and get price
-		menuItem, err := s.menuRepo.GetMenuItem(itemData.MenuItemID)
+	// The order header and all of its lines are written as one unit
+	var createdOrder *models.Order
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		orderType, err := seatOrder(tx, orderData.OrderType, orderData.TableID, orderData.GuestCount)
 		if err != nil {
-			return nil, fmt.Errorf("menu item not found: %s", itemData.MenuItemID)
+			return err
 		}
 
-		if !menuItem.IsAvailable {
-			return nil, fmt.Errorf("menu item is not available: %s", menuItem.Name)
-		}
+		for _, itemData := range orderData.Items {
+			// Get menu item to verify availability and get price
+			menuItem, err := tx.MenuRepo.GetMenuItem(itemData.MenuItemID)
+			if err != nil {
+				return fmt.Errorf("menu item not found: %s", itemData.MenuItemID)
+			}
 
-		// Check inventory stock for the menu item
-		inventory, err := s.inventoryRepo.GetInventoryByMenuItem(itemData.MenuItemID)
-		if err != nil {
-			// If no inventory exists for this item, create a new record
-			err = s.inventoryRepo.CreateInventoryRecord(itemData.MenuItemID)
+			if menuItem.SoldOut {
+				return fmt.Errorf("menu item is sold out: %s", menuItem.Name)
+			}
+			if !menuItem.IsAvailable {
+				return fmt.Errorf("menu item is not available: %s", menuItem.Name)
+			}
+
+			// The chosen size and add-ons are part of the unit price
+			modifiers, modifierDelta, err := selectModifiers(tx, itemData.MenuItemID, itemData.ModifierOptionIDs)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create inventory record for menu item %s: %v", itemData.MenuItemID, err)
+				return err
 			}
 
-			// Try to fetch again
-			inventory, err = s.inventoryRepo.GetInventoryByMenuItem(itemData.MenuItemID)
+			// Check there is enough stock, or enough ingredients, to make the item
+			usage, err := stockUsageOf(tx.RecipeRepo, []stockLine{{
+				menuItemID:        itemData.MenuItemID,
+				quantity:          itemData.Quantity,
+				modifierOptionIDs: itemData.ModifierOptionIDs,
+			}})
 			if err != nil {
-				return nil, fmt.Errorf("failed to get inventory for menu item %s: %v", itemData.MenuItemID, err)
+				return err
+			}
+			if err := checkStock(tx, usage); err != nil {
+				return err
 			}
+			unitPrice := menuItem.Price.Add(types.FromDecimal(modifierDelta))
+
+			// Calculate item total
+			itemTotal := unitPrice.Mul(types.FromDecimal(decimal.NewFromInt(int64(itemData.Quantity))))
+
+			// Add to order items
+			orderItemWithDetails := models.OrderItemWithDetails{
+				ID:           uuid.New().String(),
+				OrderID:      "", // Will be set after order is created
+				MenuItemID:   itemData.MenuItemID,
+				MenuItemName: menuItem.Name,
+				Quantity:     itemData.Quantity,
+				UnitPrice:    unitPrice,
+				TotalPrice:   itemTotal,
+				Notes:        normalizeItemNotes(itemData.Notes),
+			}
+
+			itemsWithDetails = append(itemsWithDetails, orderItemWithDetails)
+			lineModifiers = append(lineModifiers, modifiers)
+			totalAmount = totalAmount.Add(itemTotal)
 		}
 
-		// Check if sufficient stock is available
-		if inventory.CurrentStock < itemData.Quantity {
-			return nil, fmt.Errorf("insufficient stock for item %s: only %d available, %d requested", menuItem.Name, inventory.CurrentStock, itemData.Quantity)
+		orderNumber, err := s.claimOrderNumber(tx)
+		if err != nil {
+			return err
 		}
 
-		// Calculate item total
-		itemTotal := menuItem.Price.Mul(types.FromDecimal(decimal.NewFromInt(int64(itemData.Quantity))))
+		// Create the order
+		order := &models.Order{
+			ID:             uuid.New().String(),
+			OrderNumber:    orderNumber,
+			UserID:         userID,
+			Status:         types.OrderStatusDraft,
+			TotalAmount:    totalAmount,
+			SubtotalAmount: totalAmount,
+			DiscountAmount: types.DecimalText(decimal.Zero),
+			TaxAmount:      types.DecimalText(decimal.Zero),
+			PaymentStatus:  types.PaymentStatusPending,
+			OrderType:      orderType,
+			TableID:        orderData.TableID,
+			GuestCount:     orderData.GuestCount,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
 
-		// Add to order items
-		orderItemWithDetails := models.OrderItemWithDetails{
-			ID:           uuid.New().String(),
-			OrderID:      "", // Will be set after order is created
-			MenuItemID:   itemData.MenuItemID,
-			MenuItemName: menuItem.Name,
-			Quantity:     itemData.Quantity,
-			UnitPrice:    menuItem.Price,
-			TotalPrice:   itemTotal,
+		createdOrder, err = tx.OrderRepo.CreateOrder(order)
+		if err != nil {
+			return fmt.Errorf("failed to create order: %v", err)
 		}
 
-		itemsWithDetails = append(itemsWithDetails, orderItemWithDetails)
-		totalAmount = totalAmount.Add(itemTotal)
-	}
+		// Create order items
+		var orderItems []*models.OrderItem
+		for i, itemWithDetails := range itemsWithDetails {
+			orderItem := &models.OrderItem{
+				ID:         uuid.New().String(),
+				OrderID:    createdOrder.ID,
+				MenuItemID: itemWithDetails.MenuItemID,
+				Quantity:   itemWithDetails.Quantity,
+				UnitPrice:  itemWithDetails.UnitPrice,
+				TotalPrice: itemWithDetails.TotalPrice,
+				Notes:      itemWithDetails.Notes,
+			}
 
-	// Generate order number in the format ORD-YYYYMMDD-XXXX
-	orderNumber := fmt.Sprintf("ORD-%s-%04d", 
-		time.Now().Format("20060102"), 
-		time.Now().UnixNano()%10000) // Simple sequential number for demo purposes
+			createdItem, err := tx.OrderItemRepo.CreateOrderItem(orderItem)
+			if err != nil {
+				return fmt.Errorf("failed to create order item: %v", err)
+			}
+			orderItems = append(orderItems, orderItem)
 
-	// Create the order
-	order := &models.Order{
-		ID:             uuid.New().String(),
-		OrderNumber:    orderNumber,
-		UserID:         userID,
-		Status:         types.OrderStatusDraft,
-		TotalAmount:    totalAmount,
-		DiscountAmount: types.DecimalText(decimal.Zero),
-		TaxAmount:      types.DecimalText(decimal.Zero),
-		PaymentStatus:  types.PaymentStatusPending,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-	}
+			if _, err := recordModifiers(tx, createdItem.ID, lineModifiers[i]); err != nil {
+				return err
+			}
+		}
 
-	createdOrder, err := s.orderRepo.CreateOrder(order)
+		// Show the automatic promotions the order already qualifies for
+		return s.applyDraftPromotions(tx, createdOrder, orderItems)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create order: %v", err)
-	}
-
-	// Create order items
-	for _, itemWithDetails := range itemsWithDetails {
-		orderItem := &models.OrderItem{
-			ID:         uuid.New().String(),
-			OrderID:    createdOrder.ID,
-			MenuItemID: itemWithDetails.MenuItemID,
-			Quantity:   itemWithDetails.Quantity,
-			UnitPrice:  itemWithDetails.UnitPrice,
-			TotalPrice: itemWithDetails.TotalPrice,
-		}
-		
-		_, err := s.orderItemRepo.CreateOrderItem(orderItem)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create order item: %v", err)
-		}
+		return nil, err
 	}
 
 	// Retrieve order items with details
-	orderItemDetails, err := s.orderItemRepo.GetOrderItemsWithDetails(createdOrder.ID)
+	createdOrderWithDetails, err := s.orderWithDetails(createdOrder)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order items with details: %v", err)
-	}
-
-	createdOrderWithDetails := models.OrderWithDetails{
-		ID:             createdOrder.ID,
-		OrderNumber:    createdOrder.OrderNumber,
-		UserID:         createdOrder.UserID,
-		Status:         createdOrder.Status,
-		TotalAmount:    createdOrder.TotalAmount,
-		DiscountAmount: createdOrder.DiscountAmount,
-		TaxAmount:      createdOrder.TaxAmount,
-		PaymentMethod:  createdOrder.PaymentMethod,
-		PaymentStatus:  createdOrder.PaymentStatus,
-		CompletedAt:    createdOrder.CompletedAt,
-		CreatedAt:      createdOrder.CreatedAt,
-		UpdatedAt:      createdOrder.UpdatedAt,
-		Items:          convertOrderItemWithDetailsPtrToSlice(orderItemDetails),
+		return nil, err
 	}
 
 	return &types.APIResponse{
@@ -198,33 +253,100 @@ func convertOrderItemWithDetailsPtrToSlice(ptrSlice []*models.OrderItemWithDetai
 	return slice
 }
 
-// GetOrder retrieves an order by ID
-func (s *OrderService) GetOrder(id string) (*types.APIResponse, error) {
-	order, err := s.orderRepo.GetOrder(id)
+// orderWithDetails loads the lines, promotions and payments of an order for API responses
+func (s *OrderService) orderWithDetails(order *models.Order) (*models.OrderWithDetails, error) {
+	orderItemDetails, err := s.repo.OrderItemRepo.GetOrderItemsWithDetails(order.ID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get order items with details: %v", err)
 	}
 
-	// Fetch order items with details
-	orderItemDetails, err := s.orderItemRepo.GetOrderItemsWithDetails(id)
+	orderItemModifiers, err := s.repo.ModifierRepo.ListOrderItemModifiers(order.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order items with details: %v", err)
+		return nil, fmt.Errorf("failed to get order item modifiers: %v", err)
+	}
+	attachModifiers(orderItemDetails, orderItemModifiers)
+
+	orderPromotions, err := s.repo.PromotionRepo.ListOrderPromotions(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order promotions: %v", err)
+	}
+
+	orderPayments, err := s.repo.OrderPaymentRepo.ListOrderPayments(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order payments: %v", err)
+	}
+
+	orderWithDetails := toOrderWithDetails(order, orderItemDetails, orderPromotions, orderPayments)
+
+	if order.TableID != nil {
+		table, err := s.repo.TableRepo.GetTable(*order.TableID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order table: %v", err)
+		}
+		orderWithDetails.TableName = &table.Name
 	}
 
+	return &orderWithDetails, nil
+}
+
+// toOrderWithDetails combines an order with its line details, promotions and payments for API responses
+func toOrderWithDetails(order *models.Order, items []*models.OrderItemWithDetails, promotions []*models.OrderPromotion, payments []*models.OrderPayment) models.OrderWithDetails {
 	orderWithDetails := models.OrderWithDetails{
-		ID:             order.ID,
-		OrderNumber:    order.OrderNumber,
-		UserID:         order.UserID,
-		Status:         order.Status,
-		TotalAmount:    order.TotalAmount,
-		DiscountAmount: order.DiscountAmount,
-		TaxAmount:      order.TaxAmount,
-		PaymentMethod:  order.PaymentMethod,
-		PaymentStatus:  order.PaymentStatus,
-		CompletedAt:    order.CompletedAt,
-		CreatedAt:      order.CreatedAt,
-		UpdatedAt:      order.UpdatedAt,
-		Items:          convertOrderItemWithDetailsPtrToSlice(orderItemDetails),
+		ID:                  order.ID,
+		OrderNumber:         order.OrderNumber,
+		UserID:              order.UserID,
+		Status:              order.Status,
+		TotalAmount:         order.TotalAmount,
+		DiscountAmount:      order.DiscountAmount,
+		TaxAmount:           order.TaxAmount,
+		PaymentMethod:       order.PaymentMethod,
+		PaymentStatus:       order.PaymentStatus,
+		CompletedAt:         order.CompletedAt,
+		CreatedAt:           order.CreatedAt,
+		UpdatedAt:           order.UpdatedAt,
+		CancellationReason:  order.CancellationReason,
+		CancelledBy:         order.CancelledBy,
+		CancelledAt:         order.CancelledAt,
+		SubtotalAmount:      order.SubtotalAmount,
+		ServiceChargeAmount: order.ServiceChargeAmount,
+		ServiceChargeRate:   order.ServiceChargeRate,
+		TaxRate:             order.TaxRate,
+		RoundingAmount:      order.RoundingAmount,
+		OrderType:           order.OrderType,
+		TableID:             order.TableID,
+		GuestCount:          order.GuestCount,
+		ShiftID:             order.ShiftID,
+		VoidShiftID:         order.VoidShiftID,
+		Items:               convertOrderItemWithDetailsPtrToSlice(items),
+	}
+
+	for _, promotion := range promotions {
+		orderWithDetails.Promotions = append(orderWithDetails.Promotions, *promotion)
+	}
+
+	if len(payments) > 0 {
+		change := types.DecimalText(decimal.Zero)
+		for _, payment := range payments {
+			orderWithDetails.Payments = append(orderWithDetails.Payments, *payment)
+			change = change.Add(payment.ChangeAmount)
+		}
+		orderWithDetails.ChangeAmount = &change
+	}
+
+	return orderWithDetails
+}
+
+// GetOrder retrieves an order by ID
+func (s *OrderService) GetOrder(id string) (*types.APIResponse, error) {
+	order, err := s.repo.OrderRepo.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch order items, promotions and payments
+	orderWithDetails, err := s.orderWithDetails(order)
+	if err != nil {
+		return nil, err
 	}
 
 	return &types.APIResponse{
@@ -235,7 +357,7 @@ func (s *OrderService) GetOrder(id string) (*types.APIResponse, error) {
 
 // ListOrders retrieves a list of orders based on filter criteria
 func (s *OrderService) ListOrders(filter types.OrderFilter) (*types.APIResponse, error) {
-	orders, err := s.orderRepo.ListOrders(filter)
+	orders, err := s.repo.OrderRepo.ListOrders(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list orders: %v", err)
 	}
@@ -256,10 +378,11 @@ func (s *OrderService) UpdateOrderStatus(orderID string, status types.OrderStatu
 		return errors.New("invalid order ID")
 	}
 
-	return s.orderRepo.UpdateOrderStatus(orderID, string(status))
+	return s.repo.OrderRepo.UpdateOrderStatus(orderID, string(status))
 }
 
-// AddItemToOrder adds an item to an existing order
+// AddItemToOrder adds an item to a draft order, or to a pending order left open
+// as a tab, in which case the new line goes straight to the kitchen
 func (s *OrderService) AddItemToOrder(orderID string, userID string, itemData *models.OrderItemCreate) (*types.APIResponse, error) {
 	// Validate order ID
 	_, err := uuid.Parse(orderID)
@@ -273,202 +396,610 @@ func (s *OrderService) AddItemToOrder(orderID string, userID string, itemData *m
 		return nil, errors.New("invalid user ID")
 	}
 
-	// Get the existing order
-	order, err := s.orderRepo.GetOrder(orderID)
+	var orderItem *models.OrderItem
+	var modifiers []models.OrderItemModifier
+	var ticketIDs []string
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so the new line cannot race its completion
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		if !isEditableOrder(order) {
+			return errors.New("can only add items to draft or pending orders")
+		}
+
+		// Get menu item to verify availability and get price
+		menuItem, err := tx.MenuRepo.GetMenuItem(itemData.MenuItemID)
+		if err != nil {
+			return fmt.Errorf("menu item not found: %s", itemData.MenuItemID)
+		}
+
+		if menuItem.SoldOut {
+			return fmt.Errorf("menu item is sold out: %s", menuItem.Name)
+		}
+		if !menuItem.IsAvailable {
+			return fmt.Errorf("menu item is not available: %s", menuItem.Name)
+		}
+
+		// The chosen size and add-ons are part of the unit price
+		selected, modifierDelta, err := selectModifiers(tx, itemData.MenuItemID, itemData.ModifierOptionIDs)
+		if err != nil {
+			return err
+		}
+
+		// Check there is enough stock, or enough ingredients, to make the item
+		usage, err := stockUsageOf(tx.RecipeRepo, []stockLine{{
+			menuItemID:        itemData.MenuItemID,
+			quantity:          itemData.Quantity,
+			modifierOptionIDs: itemData.ModifierOptionIDs,
+		}})
+		if err != nil {
+			return err
+		}
+		if err := checkStock(tx, usage); err != nil {
+			return err
+		}
+		unitPrice := menuItem.Price.Add(types.FromDecimal(modifierDelta))
+
+		// Calculate the item total
+		itemTotal := unitPrice.Mul(types.FromDecimal(decimal.NewFromInt(int64(itemData.Quantity))))
+
+		// Create order item
+		orderItem, err = tx.OrderItemRepo.CreateOrderItem(&models.OrderItem{
+			ID:         uuid.New().String(),
+			OrderID:    orderID,
+			MenuItemID: itemData.MenuItemID,
+			Quantity:   itemData.Quantity,
+			UnitPrice:  unitPrice,
+			TotalPrice: itemTotal,
+			Notes:      normalizeItemNotes(itemData.Notes),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create order item: %v", err)
+		}
+
+		modifiers, err = recordModifiers(tx, orderItem.ID, selected)
+		if err != nil {
+			return err
+		}
+
+		order.SubtotalAmount = order.SubtotalAmount.Add(itemTotal)
+
+		// Lines added to an open tab are sent to the kitchen as they are ordered
+		if order.Status == types.OrderStatusPending {
+			ticketIDs, err = tx.KitchenRepo.CreateTicketsForOrder(orderID)
+			if err != nil {
+				return fmt.Errorf("failed to send item to kitchen: %v", err)
+			}
+		}
+
+		// The new line may make the order qualify for a promotion, or a better one
+		orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+
+		return s.applyDraftPromotions(tx, order, orderItems)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyKitchenQueued(orderID, ticketIDs)
+
+	// Fetch the updated order
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
-		return nil, fmt.Errorf("order not found: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
 
-	// Check if order is still in draft status
-	if order.Status != types.OrderStatusDraft {
-		return nil, errors.New("can only add items to draft orders")
+	return &types.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message":             "Item added to order successfully",
+			"updated_order_total": updatedOrder.TotalAmount,
+			"added_item":          orderItem,
+			"modifiers":           modifiers,
+		},
+	}, nil
+}
+
+// normalizeItemNotes trims a line note, treating a blank note as none
+func normalizeItemNotes(notes *string) *string {
+	if notes == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*notes)
+	if trimmed == "" {
+		return nil
 	}
+	return &trimmed
+}
+
+// isEditableOrder reports whether the lines of an order can still be changed
+func isEditableOrder(order *models.Order) bool {
+	return order.Status == types.OrderStatusDraft || order.Status == types.OrderStatusPending
+}
 
-	// Get menu item to verify availability and get price
-	menuItem, err := s.menuRepo.GetMenuItem(itemData.MenuItemID)
+// repriceDraftOrder recomputes the subtotal from the order's current lines and
+// re-evaluates its promotions
+func (s *OrderService) repriceDraftOrder(tx *repositories.Repository, order *models.Order) error {
+	orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(order.ID)
 	if err != nil {
-		return nil, fmt.Errorf("menu item not found: %s", itemData.MenuItemID)
+		return fmt.Errorf("failed to get order items: %v", err)
+	}
+
+	subtotal := decimal.Zero
+	for _, orderItem := range orderItems {
+		subtotal = subtotal.Add(decimal.Decimal(orderItem.TotalPrice))
 	}
+	order.SubtotalAmount = types.FromDecimal(subtotal)
 
-	if !menuItem.IsAvailable {
-		return nil, fmt.Errorf("menu item is not available: %s", menuItem.Name)
+	return s.applyDraftPromotions(tx, order, orderItems)
+}
+
+// orderItemOf fetches a line and checks that it belongs to the order
+func orderItemOf(tx *repositories.Repository, orderID, itemID string) (*models.OrderItem, error) {
+	orderItem, err := tx.OrderItemRepo.GetOrderItem(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("order item not found: %v", err)
 	}
+	if orderItem.OrderID != orderID {
+		return nil, errors.New("order item does not belong to this order")
+	}
+	return orderItem, nil
+}
 
-	// Calculate the item total
-	itemTotal := menuItem.Price.Mul(types.FromDecimal(decimal.NewFromInt(int64(itemData.Quantity))))
+// UpdateOrderItem changes the quantity or notes of a line on a draft or pending
+// order and recomputes the order total
+func (s *OrderService) UpdateOrderItem(orderID string, itemID string, userID string, itemData *models.OrderItemUpdate) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
 
-	// Create order item
-	orderItem := &models.OrderItem{
-		ID:         uuid.New().String(),
-		OrderID:    orderID,
-		MenuItemID: itemData.MenuItemID,
-		Quantity:   itemData.Quantity,
-		UnitPrice:  menuItem.Price,
-		TotalPrice: itemTotal,
+	// Validate order item ID
+	_, err = uuid.Parse(itemID)
+	if err != nil {
+		return nil, errors.New("invalid order item ID")
 	}
 
-	_, err = s.orderItemRepo.CreateOrderItem(orderItem)
+	// Validate user ID
+	_, err = uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create order item: %v", err)
+		return nil, errors.New("invalid user ID")
+	}
+
+	if itemData.Quantity == nil && itemData.Notes == nil {
+		return nil, errors.New("nothing to update: provide a quantity or notes")
+	}
+	if itemData.Quantity != nil && *itemData.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than zero; remove the item instead")
 	}
 
-	// Calculate the new total
-	newTotal := order.TotalAmount.Add(itemTotal)
+	var updatedItem *models.OrderItem
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so the edit cannot race its completion
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		if !isEditableOrder(order) {
+			return errors.New("can only edit items on draft or pending orders")
+		}
+
+		orderItem, err := orderItemOf(tx, orderID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if itemData.Quantity != nil && *itemData.Quantity > orderItem.Quantity {
+			// Check there is enough stock for the larger quantity
+			lines, err := orderStockLines(tx, orderID, []*models.OrderItem{orderItem})
+			if err != nil {
+				return err
+			}
+			lines[0].quantity = *itemData.Quantity
+
+			usage, err := stockUsageOf(tx.RecipeRepo, lines)
+			if err != nil {
+				return err
+			}
+			if err := checkStock(tx, usage); err != nil {
+				return err
+			}
+		}
+
+		// The line keeps the price it was added at
+		if itemData.Quantity != nil {
+			orderItem.Quantity = *itemData.Quantity
+			orderItem.TotalPrice = orderItem.UnitPrice.Mul(types.FromDecimal(decimal.NewFromInt(int64(orderItem.Quantity))))
+		}
+		if itemData.Notes != nil {
+			orderItem.Notes = normalizeItemNotes(itemData.Notes)
+		}
+
+		updatedItem, err = tx.OrderItemRepo.UpdateOrderItem(orderItem)
+		if err != nil {
+			return fmt.Errorf("failed to update order item: %v", err)
+		}
 
-	// Update the order total
-	err = s.orderRepo.UpdateOrderTotal(orderID, newTotal.String(), order.DiscountAmount.String(), order.TaxAmount.String())
+		return s.repriceDraftOrder(tx, order)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order total: %v", err)
+		return nil, err
 	}
 
 	// Fetch the updated order
-	updatedOrder, err := s.orderRepo.GetOrder(orderID)
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
 
+	// Show the new quantity or notes on the kitchen display
+	s.notifyKitchen(kitchen.EventTicketUpdated, orderID, func(ticket *models.KitchenTicket) bool {
+		return ticket.OrderItemID == itemID
+	})
+
 	return &types.APIResponse{
 		Success: true,
 		Data: map[string]interface{}{
-			"message":             "Item added to order successfully",
+			"message":             "Order item updated successfully",
 			"updated_order_total": updatedOrder.TotalAmount,
-			"added_item":          orderItem,
+			"updated_item":        updatedItem,
 		},
 	}, nil
 }
 
-// CompleteOrder processes payment and completes the order, updating inventory
-func (s *OrderService) CompleteOrder(orderID string, userID string, updateData *models.OrderUpdate) (*types.APIResponse, error) {
+// RemoveOrderItem removes a line from a draft or pending order and recomputes
+// the order total. The last line cannot be removed; cancel the order instead.
+func (s *OrderService) RemoveOrderItem(orderID string, itemID string, userID string) (*types.APIResponse, error) {
 	// Validate order ID
 	_, err := uuid.Parse(orderID)
 	if err != nil {
 		return nil, errors.New("invalid order ID")
 	}
 
+	// Validate order item ID
+	_, err = uuid.Parse(itemID)
+	if err != nil {
+		return nil, errors.New("invalid order item ID")
+	}
+
 	// Validate user ID
 	_, err = uuid.Parse(userID)
 	if err != nil {
 		return nil, errors.New("invalid user ID")
 	}
 
-	// Get the order
-	order, err := s.orderRepo.GetOrder(orderID)
+	var removedTickets []*models.KitchenTicket
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so the edit cannot race its completion
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		if !isEditableOrder(order) {
+			return errors.New("can only remove items from draft or pending orders")
+		}
+
+		if _, err := orderItemOf(tx, orderID, itemID); err != nil {
+			return err
+		}
+
+		orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+		if len(orderItems) <= 1 {
+			return errors.New("cannot remove the last item of an order; cancel the order instead")
+		}
+
+		// The line's kitchen ticket is deleted with it
+		tickets, err := openOrderTickets(tx.KitchenRepo, orderID)
+		if err != nil {
+			return err
+		}
+		for _, ticket := range tickets {
+			if ticket.OrderItemID == itemID {
+				removedTickets = append(removedTickets, ticket)
+			}
+		}
+
+		if err := tx.OrderItemRepo.DeleteOrderItem(itemID); err != nil {
+			return fmt.Errorf("failed to remove order item: %v", err)
+		}
+
+		return s.repriceDraftOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the updated order
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
-		return nil, fmt.Errorf("order not found: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
 
-	// Check if order is in a valid state for completion
-	if order.Status != types.OrderStatusDraft && order.Status != types.OrderStatusPending {
-		return nil, errors.New("order is not in a valid state for completion")
+	s.kitchenEvents.Publish(kitchen.Event{Type: kitchen.EventTicketsRemoved, Tickets: removedTickets})
+
+	return &types.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message":             "Order item removed successfully",
+			"updated_order_total": updatedOrder.TotalAmount,
+		},
+	}, nil
+}
+
+// SubmitOrder sends a draft order to the kitchen: the order becomes pending and
+// each of its lines is queued at the station its category is routed to
+func (s *OrderService) SubmitOrder(orderID string, userID string) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
 	}
 
-	// Get order items to check inventory
-	orderItems, err := s.orderItemRepo.GetOrderItemsByOrderID(orderID)
+	// Validate user ID
+	_, err = uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order items: %v", err)
+		return nil, errors.New("invalid user ID")
 	}
 
-	// Check inventory availability for each item before completing the order
-	for _, orderItem := range orderItems {
-		// Fetch current inventory for the menu item
-		inventory, err := s.inventoryRepo.GetInventoryByMenuItem(orderItem.MenuItemID)
+	var ticketIDs []string
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so it cannot be submitted twice concurrently
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
 		if err != nil {
-			// If no inventory exists for this item, create a new record
-			err = s.inventoryRepo.CreateInventoryRecord(orderItem.MenuItemID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create inventory record for menu item %s: %v", orderItem.MenuItemID, err)
-			}
+			return fmt.Errorf("order not found: %v", err)
+		}
 
-			// Try to fetch again
-			inventory, err = s.inventoryRepo.GetInventoryByMenuItem(orderItem.MenuItemID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get inventory for menu item %s: %v", orderItem.MenuItemID, err)
-			}
+		if order.Status != types.OrderStatusDraft {
+			return errors.New("only draft orders can be sent to the kitchen")
 		}
 
-		// Check if sufficient stock is available
-		if inventory.CurrentStock < orderItem.Quantity {
-			return nil, fmt.Errorf("insufficient stock for item %s: only %d available, %d requested", inventory.MenuItemName, inventory.CurrentStock, orderItem.Quantity)
+		if err := tx.OrderRepo.UpdateOrderStatus(orderID, string(types.OrderStatusPending)); err != nil {
+			return fmt.Errorf("failed to update order status: %v", err)
+		}
+
+		ticketIDs, err = tx.KitchenRepo.CreateTicketsForOrder(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to send order to kitchen: %v", err)
 		}
-	}
 
-	// Update order with payment information if provided
-	var paymentMethodStr string
-	if updateData.PaymentMethod != nil {
-		paymentMethodStr = string(*updateData.PaymentMethod)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	completedAt := time.Now().UTC().Format("2006-01-02 15:04:05.999999-07:00")
-	err = s.orderRepo.UpdateOrderPayment(
-		orderID,
-		paymentMethodStr,
-		string(types.PaymentStatusPaid),
-		&completedAt,
-	)
+	s.notifyKitchenQueued(orderID, ticketIDs)
+
+	// Fetch the updated order
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order payment: %v", err)
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
 
-	// Update the order status to completed
-	err = s.UpdateOrderStatus(orderID, types.OrderStatusCompleted)
+	submittedOrder, err := s.orderWithDetails(updatedOrder)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order status: %v", err)
+		return nil, err
 	}
 
+	return &types.APIResponse{
+		Success: true,
+		Data:    submittedOrder,
+	}, nil
+}
+
+// applyPricing recomputes the order's price breakdown from its lines, the
+// promotions applied to it and the requested discount, and stores it on the order
+func (s *OrderService) applyPricing(tx *repositories.Repository, order *models.Order, orderItems []*models.OrderItem, promotionDiscount decimal.Decimal, updateData *models.OrderUpdate) error {
+	subtotal := decimal.Zero
 	for _, orderItem := range orderItems {
-		// Fetch current inventory for the menu item
-		inventory, err := s.inventoryRepo.GetInventoryByMenuItem(orderItem.MenuItemID)
+		subtotal = subtotal.Add(decimal.Decimal(orderItem.TotalPrice))
+	}
+
+	discount := pricing.Discount{Promotion: promotionDiscount}
+	if updateData.DiscountAmount != nil {
+		discount.Amount = decimal.Decimal(*updateData.DiscountAmount)
+	}
+	if updateData.DiscountPercent != nil {
+		discount.Percent = decimal.Decimal(*updateData.DiscountPercent)
+	}
+
+	breakdown, err := s.pricingRules.Apply(subtotal, discount)
+	if err != nil {
+		return fmt.Errorf("failed to price order: %v", err)
+	}
+
+	order.SubtotalAmount = types.FromDecimal(breakdown.Subtotal)
+	order.DiscountAmount = types.FromDecimal(breakdown.Discount)
+	order.ServiceChargeAmount = types.FromDecimal(breakdown.ServiceCharge)
+	order.ServiceChargeRate = types.FromDecimal(breakdown.ServiceChargeRate)
+	order.TaxAmount = types.FromDecimal(breakdown.Tax)
+	order.TaxRate = types.FromDecimal(breakdown.TaxRate)
+	order.RoundingAmount = types.FromDecimal(breakdown.Rounding)
+	order.TotalAmount = types.FromDecimal(breakdown.Total)
+
+	if err := tx.OrderRepo.UpdateOrderTotal(order); err != nil {
+		return fmt.Errorf("failed to update order total: %v", err)
+	}
+
+	return nil
+}
+
+// CompleteOrder processes payment and completes the order, updating inventory.
+// Payment, status and stock changes are committed together or not at all.
+func (s *OrderService) CompleteOrder(orderID string, userID string, updateData *models.OrderUpdate) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	var order *models.Order
+	var ticketIDs []string
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// A Z report waits for the order to commit rather than closing the
+		// period while it completes
+		if err := tx.OrderRepo.HoldOffZReports(); err != nil {
+			return fmt.Errorf("failed to hold off Z reports: %v", err)
+		}
+
+		// Get the order, locking it so it cannot be completed twice concurrently
+		var err error
+		order, err = tx.OrderRepo.GetOrderForUpdate(orderID)
 		if err != nil {
-			// If no inventory exists for this item, create a new record
-			err = s.inventoryRepo.CreateInventoryRecord(orderItem.MenuItemID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create inventory record for menu item %s: %v", orderItem.MenuItemID, err)
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		// Check if order is in a valid state for completion
+		if order.Status != types.OrderStatusDraft && order.Status != types.OrderStatusPending {
+			return errors.New("order is not in a valid state for completion")
+		}
+
+		// The payment goes into the cashier's drawer, so they need an open shift
+		shift, err := tx.ShiftRepo.GetOpenShiftByUser(userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNoOpenShift) {
+				return errors.New("no open cash drawer shift; open a shift before completing orders")
 			}
-			
-			// Try to fetch again
-			inventory, err = s.inventoryRepo.GetInventoryByMenuItem(orderItem.MenuItemID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get inventory for menu item %s: %v", orderItem.MenuItemID, err)
+			return fmt.Errorf("failed to get open shift: %v", err)
+		}
+
+		// Get order items to deduct from inventory
+		orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+
+		// Settle the promotions and voucher for good, using up one redemption of each
+		applied, err := s.applyPromotions(tx, order, orderItems, updateData.VoucherCode)
+		if err != nil {
+			return err
+		}
+		for _, promotion := range applied {
+			if err := tx.PromotionRepo.ClaimPromotionUsage(promotion.PromotionID); err != nil {
+				if errors.Is(err, repositories.ErrPromotionUsageLimitReached) {
+					return fmt.Errorf("promotion %s has reached its usage limit", promotion.Name)
+				}
+				return fmt.Errorf("failed to redeem promotion %s: %v", promotion.Name, err)
+			}
+		}
+
+		// Price the order: discount, service charge, tax and rounding
+		if err := s.applyPricing(tx, order, orderItems, pricing.TotalDiscount(applied), updateData); err != nil {
+			return err
+		}
+
+		// Take payment: the tenders must cover the priced total, with any
+		// overpayment in cash handed back as change
+		tenders, err := paymentTenders(order, updateData)
+		if err != nil {
+			return err
+		}
+
+		settled, _, err := pricing.SettleTenders(decimal.Decimal(order.TotalAmount), tenders)
+		if err != nil {
+			return err
+		}
+
+		for _, tender := range settled {
+			payment := &models.OrderPayment{
+				ID:            uuid.New().String(),
+				OrderID:       orderID,
+				PaymentMethod: tender.Method,
+				Amount:        types.FromDecimal(tender.Amount),
+				ChangeAmount:  types.FromDecimal(tender.Change),
+			}
+			if tender.Reference != "" {
+				reference := tender.Reference
+				payment.Reference = &reference
+			}
+
+			if _, err := tx.OrderPaymentRepo.CreateOrderPayment(payment); err != nil {
+				return fmt.Errorf("failed to record payment: %v", err)
 			}
 		}
+		paymentMethodStr := string(pricing.PaymentMethodOf(settled))
+
+		completedAt := time.Now().UTC().Format("2006-01-02 15:04:05.999999-07:00")
+		err = tx.OrderRepo.UpdateOrderPayment(
+			orderID,
+			paymentMethodStr,
+			string(types.PaymentStatusPaid),
+			&completedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update order payment: %v", err)
+		}
+
+		// Update the order status to completed
+		err = tx.OrderRepo.UpdateOrderStatus(orderID, string(types.OrderStatusCompleted))
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %v", err)
+		}
 
-		// Update inventory stock (reduce by the quantity ordered)
-		newStock := inventory.CurrentStock - orderItem.Quantity
-		if newStock < 0 {
-			newStock = 0 // Ensure we don't have negative stock
+		err = tx.OrderRepo.UpdateOrderShift(orderID, shift.ID)
+		if err != nil {
+			return fmt.Errorf("failed to link order to shift: %v", err)
 		}
 
-		err = s.inventoryRepo.UpdateInventoryStock(orderItem.MenuItemID, newStock, userID)
+		// An order paid for before it was sent to the kitchen goes there now
+		ticketIDs, err = tx.KitchenRepo.CreateTicketsForOrder(orderID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update inventory stock for menu item %s: %v", orderItem.MenuItemID, err)
+			return fmt.Errorf("failed to send order to kitchen: %v", err)
 		}
 
-		// Create a stock transaction record
-		stockTransaction := &models.StockTransaction{
-			ID:              uuid.New().String(),
-			MenuItemID:      orderItem.MenuItemID,
-			TransactionType: types.TransactionTypeOut, // Using the defined transaction type
-			Quantity:        -orderItem.Quantity, // Negative value for reduction (already int, not int32)
-			PreviousStock:   inventory.CurrentStock, // Set the previous stock
-			CurrentStock:    newStock, // Set the current stock after reduction
-			Reason:          fmt.Sprintf("Order %s completion", orderID),
-			// UserID is optional in the model, so setting it to a pointer
-			UserID: &userID,
+		// Deduct the finished goods or the ingredients the order used
+		lines, err := orderStockLines(tx, orderID, orderItems)
+		if err != nil {
+			return err
 		}
 
-		createdTransaction, err := s.stockTransactionRepo.CreateStockTransaction(stockTransaction)
+		usage, err := stockUsageOf(tx.RecipeRepo, lines)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create stock transaction for menu item %s: %v", orderItem.MenuItemID, err)
+			return err
 		}
-		
-		// Verify the transaction was created successfully
-		if createdTransaction == nil {
-			return nil, fmt.Errorf("stock transaction was not created for menu item %s in order %s", orderItem.MenuItemID, orderID)
+
+		referenceType := types.ReferenceTypeOrder
+		err = moveStock(tx, usage, false, stockMovement{
+			userID:        userID,
+			reason:        fmt.Sprintf("Order %s completion", orderID),
+			referenceType: &referenceType,
+			referenceID:   &orderID,
+			changes:       &changes,
+		})
+		if err != nil {
+			return err
 		}
+
+		return recordLineStockUsage(tx, lines)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	s.notifyKitchenQueued(orderID, ticketIDs)
+	s.menuAvailability.announce(changes)
+
 	// Fetch the updated order
-	updatedOrder, err := s.orderRepo.GetOrder(orderID)
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
@@ -506,13 +1037,72 @@ func (s *OrderService) CompleteOrder(orderID string, userID string, updateData *
 		}
 	}
 
+	// Include the tenders and the change to hand back
+	completedOrder, err := s.orderWithDetails(updatedOrder)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.APIResponse{
 		Success: true,
-		Data:    updatedOrder,
+		Data:    completedOrder,
 	}, nil
 }
 
-// CancelOrder cancels an order with authorization checks
+// restockCancelledOrder returns what a cancelled order used to stock, finished
+// goods and ingredients alike, by reversing the stock transactions recorded
+// against the order, so recipes changed since the sale do not change what goes
+// back. A compensating "in" transaction references the order for each item.
+func restockCancelledOrder(tx *repositories.Repository, orderID, userID, reason string, changes *availabilityChanges) error {
+	moved, err := tx.StockTransactionRepo.ListStockMovedByReference(types.ReferenceTypeOrder, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock moved by order: %v", err)
+	}
+
+	usage := &stockUsage{
+		menuItems:   map[string]decimal.Decimal{},
+		ingredients: map[string]decimal.Decimal{},
+	}
+	for _, item := range moved {
+		// What is still out of stock goes back
+		quantity := decimal.Decimal(item.Quantity).Neg()
+		if !quantity.IsPositive() {
+			continue
+		}
+		if item.MenuItemID != nil {
+			usage.menuItems[*item.MenuItemID] = quantity
+		} else if item.IngredientID != nil {
+			usage.ingredients[*item.IngredientID] = quantity
+		}
+	}
+
+	referenceType := types.ReferenceTypeOrder
+	return moveStock(tx, usage, true, stockMovement{
+		userID:        userID,
+		reason:        fmt.Sprintf("Order %s cancelled: %s", orderID, reason),
+		referenceType: &referenceType,
+		referenceID:   &orderID,
+		changes:       changes,
+	})
+}
+
+// orderTookCash reports whether any of an order's payments was made in cash
+func orderTookCash(orderPaymentRepo repositories.OrderPaymentRepo, orderID string) (bool, error) {
+	payments, err := orderPaymentRepo.ListOrderPayments(orderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get order payments: %v", err)
+	}
+	for _, payment := range payments {
+		if payment.PaymentMethod == types.PaymentMethodCash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CancelOrder cancels an order with authorization checks. Cancelling a completed
+// order restocks its items, marks the payment as refunded and, for cash, takes
+// what was paid out of the cashier's open shift.
 func (s *OrderService) CancelOrder(orderID string, userID string, updateData *models.OrderUpdate) (*types.APIResponse, error) {
 	// Validate order ID
 	_, err := uuid.Parse(orderID)
@@ -526,32 +1116,96 @@ func (s *OrderService) CancelOrder(orderID string, userID string, updateData *mo
 		return nil, errors.New("invalid user ID")
 	}
 
-	// Get the order
-	order, err := s.orderRepo.GetOrder(orderID)
-	if err != nil {
-		return nil, fmt.Errorf("order not found: %v", err)
-	}
+	var order *models.Order
+	var voidShiftID *string
+	var removedTickets []*models.KitchenTicket
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// A Z report waits for a void to commit rather than closing the
+		// period while it is recorded
+		if err := tx.OrderRepo.HoldOffZReports(); err != nil {
+			return fmt.Errorf("failed to hold off Z reports: %v", err)
+		}
 
-	// Check if order can be canceled based on its status
-	if order.Status == types.OrderStatusCompleted {
-		// Only managers/admins can cancel completed orders
-		// This authorization check would happen in the middleware, not in the service
-		// For now, we'll just check that it's not already canceled
+		// Get the order, locking it against a concurrent completion
+		var err error
+		order, err = tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		// Check if order can be canceled based on its status
 		if order.Status == types.OrderStatusCancelled {
-			return nil, errors.New("order is already canceled")
+			return errors.New("order is already canceled")
 		}
-	} else if order.Status == types.OrderStatusCancelled {
-		return nil, errors.New("order is already canceled")
-	}
 
-	// Update the order status to cancelled
-	err = s.UpdateOrderStatus(orderID, types.OrderStatusCancelled)
+		paymentStatus := order.PaymentStatus
+		if order.Status == types.OrderStatusCompleted {
+			// Returned items are already back in stock and refunded
+			if paymentStatus == types.PaymentStatusPartiallyRefunded || paymentStatus == types.PaymentStatusRefunded {
+				return errors.New("order has refunds; refund the remaining items instead")
+			}
+
+			// Voiding a sale must be explained for the audit trail
+			if updateData.Reason == nil || strings.TrimSpace(*updateData.Reason) == "" {
+				return errors.New("a reason is required to cancel a completed order")
+			}
+
+			// Cash is handed back from the cashier's drawer, so it comes out of
+			// their open shift; other voids are recorded against it when open
+			tookCash, err := orderTookCash(tx.OrderPaymentRepo, orderID)
+			if err != nil {
+				return err
+			}
+			shift, err := tx.ShiftRepo.GetOpenShiftByUser(userID)
+			switch {
+			case err == nil:
+				voidShiftID = &shift.ID
+			case !errors.Is(err, repositories.ErrNoOpenShift):
+				return fmt.Errorf("failed to get open shift: %v", err)
+			case tookCash:
+				return errors.New("no open cash drawer shift; open a shift before voiding cash orders")
+			}
+
+			// Put back the stock that was deducted when the order was completed
+			if err := restockCancelledOrder(tx, orderID, userID, *updateData.Reason, &changes); err != nil {
+				return err
+			}
+
+			// Give back the promotion redemptions the sale used
+			if err := releaseOrderPromotions(tx, orderID); err != nil {
+				return err
+			}
+
+			paymentStatus = types.PaymentStatusRefunded
+		}
+
+		// Take the lines the kitchen has not served off the display
+		removedTickets, err = openOrderTickets(tx.KitchenRepo, orderID)
+		if err != nil {
+			return err
+		}
+		if err := tx.KitchenRepo.DeleteOpenTicketsByOrderID(orderID); err != nil {
+			return fmt.Errorf("failed to remove order from kitchen: %v", err)
+		}
+
+		// Mark the order as cancelled and record who cancelled it and why
+		err = tx.OrderRepo.CancelOrder(orderID, string(paymentStatus), updateData.Reason, userID, voidShiftID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel order: %v", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel order: %v", err)
+		return nil, err
 	}
 
+	s.kitchenEvents.Publish(kitchen.Event{Type: kitchen.EventTicketsRemoved, Tickets: removedTickets})
+	s.menuAvailability.announce(changes)
+
 	// Fetch the updated order
-	updatedOrder, err := s.orderRepo.GetOrder(orderID)
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
 	}
@@ -593,4 +1247,36 @@ func (s *OrderService) CancelOrder(orderID string, userID string, updateData *mo
 		Success: true,
 		Data:    updatedOrder,
 	}, nil
-}
\ No newline at end of file
+}
+
+// paymentTenders collects the tenders offered for an order. A bare payment
+// method is a single tender for the exact total.
+func paymentTenders(order *models.Order, updateData *models.OrderUpdate) ([]pricing.Tender, error) {
+	if len(updateData.Payments) > 0 {
+		if updateData.PaymentMethod != nil {
+			return nil, errors.New("provide either payment_method or payments, not both")
+		}
+
+		tenders := make([]pricing.Tender, 0, len(updateData.Payments))
+		for _, payment := range updateData.Payments {
+			tender := pricing.Tender{
+				Method: payment.PaymentMethod,
+				Amount: decimal.Decimal(payment.Amount),
+			}
+			if payment.Reference != nil {
+				tender.Reference = strings.TrimSpace(*payment.Reference)
+			}
+			tenders = append(tenders, tender)
+		}
+		return tenders, nil
+	}
+
+	if updateData.PaymentMethod != nil && decimal.Decimal(order.TotalAmount).IsPositive() {
+		return []pricing.Tender{{
+			Method: *updateData.PaymentMethod,
+			Amount: decimal.Decimal(order.TotalAmount),
+		}}, nil
+	}
+
+	return nil, nil
+}
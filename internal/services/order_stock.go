@@ -0,0 +1,383 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// stockLine is an order line as far as stock is concerned: a quantity of a menu
+// item made with the chosen modifier options
+type stockLine struct {
+	orderItemID       string // Set for the lines of an order, to record what each takes
+	menuItemID        string
+	quantity          int
+	modifierOptionIDs []string
+}
+
+// stockUsage is the stock a set of order lines takes, as finished goods per
+// menu item and as ingredients per ingredient, each in its stock unit
+type stockUsage struct {
+	menuItems   map[string]decimal.Decimal
+	ingredients map[string]decimal.Decimal
+}
+
+// stockMovement describes why stock moves, for the transactions it records
+type stockMovement struct {
+	userID        string
+	reason        string
+	referenceType *string
+	referenceID   *string
+	changes       *availabilityChanges // Collects the menu items the movement sold out or brought back
+	expiredLots   bool                 // The stock comes out of expired lots the caller empties itself
+}
+
+// stockUsageOf works out the stock the lines take. A menu item with a recipe
+// uses its ingredients, one without is sold from finished goods, and the recipe
+// of each chosen modifier option adds its ingredients on top.
+func stockUsageOf(recipeRepo repositories.RecipeRepo, lines []stockLine) (*stockUsage, error) {
+	usage := &stockUsage{
+		menuItems:   map[string]decimal.Decimal{},
+		ingredients: map[string]decimal.Decimal{},
+	}
+
+	for _, line := range lines {
+		quantity := decimal.NewFromInt(int64(line.quantity))
+
+		recipe, err := recipeRepo.GetMenuItemRecipe(line.menuItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recipe for menu item %s: %v", line.menuItemID, err)
+		}
+
+		if len(recipe) == 0 {
+			usage.menuItems[line.menuItemID] = usage.menuItems[line.menuItemID].Add(quantity)
+		}
+		for _, item := range recipe {
+			usage.ingredients[item.IngredientID] = usage.ingredients[item.IngredientID].Add(decimal.Decimal(item.Quantity).Mul(quantity))
+		}
+
+		for _, optionID := range line.modifierOptionIDs {
+			recipe, err := recipeRepo.GetModifierOptionRecipe(optionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get recipe for modifier option %s: %v", optionID, err)
+			}
+
+			for _, item := range recipe {
+				usage.ingredients[item.IngredientID] = usage.ingredients[item.IngredientID].Add(decimal.Decimal(item.Quantity).Mul(quantity))
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// orderStockLines returns the stock lines of an order's items, including the
+// modifier options chosen for each
+func orderStockLines(tx *repositories.Repository, orderID string, orderItems []*models.OrderItem) ([]stockLine, error) {
+	modifiers, err := tx.ModifierRepo.ListOrderItemModifiers(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order item modifiers: %v", err)
+	}
+
+	optionIDs := make(map[string][]string)
+	for _, modifier := range modifiers {
+		// A deleted option no longer has a recipe to use
+		if modifier.ModifierOptionID != nil {
+			optionIDs[modifier.OrderItemID] = append(optionIDs[modifier.OrderItemID], *modifier.ModifierOptionID)
+		}
+	}
+
+	lines := make([]stockLine, 0, len(orderItems))
+	for _, orderItem := range orderItems {
+		lines = append(lines, stockLine{
+			orderItemID:       orderItem.ID,
+			menuItemID:        orderItem.MenuItemID,
+			quantity:          orderItem.Quantity,
+			modifierOptionIDs: optionIDs[orderItem.ID],
+		})
+	}
+
+	return lines, nil
+}
+
+// recordLineStockUsage records the stock each order line takes, so that
+// refunding some of a line later puts back its share of exactly that
+func recordLineStockUsage(tx *repositories.Repository, lines []stockLine) error {
+	for _, line := range lines {
+		usage, err := stockUsageOf(tx.RecipeRepo, []stockLine{line})
+		if err != nil {
+			return err
+		}
+
+		records := make([]*models.OrderItemStockUsage, 0, len(usage.menuItems)+len(usage.ingredients))
+		for _, menuItemID := range sortedIDs(usage.menuItems) {
+			records = append(records, &models.OrderItemStockUsage{
+				OrderItemID: line.orderItemID,
+				MenuItemID:  &menuItemID,
+				Quantity:    types.FromDecimal(usage.menuItems[menuItemID]),
+			})
+		}
+		for _, ingredientID := range sortedIDs(usage.ingredients) {
+			records = append(records, &models.OrderItemStockUsage{
+				OrderItemID:  line.orderItemID,
+				IngredientID: &ingredientID,
+				Quantity:     types.FromDecimal(usage.ingredients[ingredientID]),
+			})
+		}
+
+		for _, record := range records {
+			if !decimal.Decimal(record.Quantity).IsPositive() {
+				continue
+			}
+			if err := tx.OrderItemRepo.CreateOrderItemStockUsage(record); err != nil {
+				return fmt.Errorf("failed to record stock used by order item %s: %v", line.orderItemID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedIDs returns the IDs in a fixed order, so that concurrent checkouts lock
+// the same stock rows in the same order and cannot deadlock
+func sortedIDs(quantities map[string]decimal.Decimal) []string {
+	ids := make([]string, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sellableStock returns the part of an item's stock that can be sold or used:
+// its stock less what is left in its expired lots, which stays in the stock
+// until it is written off
+func sellableStock(stockLotRepo repositories.StockLotRepo, menuItemID string, ingredientID *string, stock types.DecimalText) (decimal.Decimal, error) {
+	expired, err := stockLotRepo.GetExpiredStock(menuItemID, ingredientID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get expired stock: %v", err)
+	}
+	return decimal.Max(decimal.Decimal(stock).Sub(decimal.Decimal(expired)), decimal.Zero), nil
+}
+
+// checkStock verifies there is enough sellable stock for the usage without
+// changing it
+func checkStock(tx *repositories.Repository, usage *stockUsage) error {
+	for _, menuItemID := range sortedIDs(usage.menuItems) {
+		inventory, err := getOrCreateInventory(tx.InventoryRepo, menuItemID)
+		if err != nil {
+			return err
+		}
+
+		available, err := sellableStock(tx.StockLotRepo, menuItemID, nil, inventory.CurrentStock)
+		if err != nil {
+			return err
+		}
+		if available.LessThan(usage.menuItems[menuItemID]) {
+			return fmt.Errorf("%w for item %s: only %s available, %s requested", repositories.ErrInsufficientStock, inventory.MenuItemName, available, usage.menuItems[menuItemID])
+		}
+	}
+
+	for _, ingredientID := range sortedIDs(usage.ingredients) {
+		ingredient, err := tx.IngredientRepo.GetIngredient(ingredientID)
+		if err != nil {
+			return fmt.Errorf("failed to get ingredient %s: %v", ingredientID, err)
+		}
+
+		available, err := sellableStock(tx.StockLotRepo, "", &ingredientID, ingredient.CurrentStock)
+		if err != nil {
+			return err
+		}
+		if available.LessThan(usage.ingredients[ingredientID]) {
+			return fmt.Errorf("%w for ingredient %s: only %s %s available, %s %s requested", repositories.ErrInsufficientStock, ingredient.Name, available, ingredient.Unit, usage.ingredients[ingredientID], ingredient.Unit)
+		}
+	}
+
+	return nil
+}
+
+// moveStock takes the usage out of stock, or puts it back when restock is set,
+// recording a stock transaction for every menu item and ingredient it touches
+// at the selling location. Stock taken out comes out of the lots that have not
+// expired, and fails with repositories.ErrInsufficientStock when only expired
+// lots still hold it. Stock put back goes into a new lot of its own, as the lots it was
+// taken from are not tracked; its expiry is not known, so it is used after the
+// lots that have one.
+// Each change is a single conditional update so that concurrent checkouts can
+// neither oversell nor lose a deduction.
+func moveStock(tx *repositories.Repository, usage *stockUsage, restock bool, movement stockMovement) error {
+	direction, transactionType := decimal.NewFromInt(-1), types.TransactionTypeOut
+	if restock {
+		direction, transactionType = decimal.NewFromInt(1), types.TransactionTypeIn
+	}
+
+	for _, menuItemID := range sortedIDs(usage.menuItems) {
+		quantity := usage.menuItems[menuItemID]
+
+		// Make sure the menu item has an inventory record to adjust
+		inventory, err := getOrCreateInventory(tx.InventoryRepo, menuItemID)
+		if err != nil {
+			return err
+		}
+
+		change := types.FromDecimal(quantity.Mul(direction))
+		previousStock, newStock, err := tx.InventoryRepo.AdjustInventoryStock(menuItemID, change, movement.userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientStock) {
+				return fmt.Errorf("%w for item %s: only %s available, %s requested", repositories.ErrInsufficientStock, inventory.MenuItemName, inventory.CurrentStock, quantity)
+			}
+			return fmt.Errorf("failed to update inventory stock for menu item %s: %v", menuItemID, err)
+		}
+		// The adjustment keeps the stock locked, so the stock it started from
+		// is exact. Only writing off expired lots takes stock out of them.
+		if !restock && !movement.expiredLots {
+			available, err := sellableStock(tx.StockLotRepo, menuItemID, nil, previousStock)
+			if err != nil {
+				return err
+			}
+			if available.LessThan(quantity) {
+				return fmt.Errorf("%w for item %s: only %s available, %s requested", repositories.ErrInsufficientStock, inventory.MenuItemName, available, quantity)
+			}
+		}
+		if err := consumeStockLots(tx, menuItemID, nil, change, movement); err != nil {
+			return err
+		}
+
+		stockTransaction := &models.StockTransaction{
+			ID:              uuid.New().String(),
+			MenuItemID:      menuItemID,
+			TransactionType: transactionType,
+			Quantity:        change,
+			PreviousStock:   previousStock,
+			CurrentStock:    newStock,
+			Reason:          movement.reason,
+			ReferenceType:   movement.referenceType,
+			ReferenceID:     movement.referenceID,
+			UserID:          &movement.userID,
+		}
+
+		createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientLocationStock) {
+				return fmt.Errorf("insufficient stock for item %s at the selling location: %s requested", inventory.MenuItemName, quantity)
+			}
+			return fmt.Errorf("failed to create stock transaction for menu item %s: %v", menuItemID, err)
+		}
+		movement.changes.add(createdTransaction)
+
+		if restock {
+			if err := restockLot(tx, &models.StockLot{
+				MenuItemID: &menuItemID,
+				ItemName:   inventory.MenuItemName,
+				Unit:       inventory.Unit,
+			}, stockTransaction, movement); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, ingredientID := range sortedIDs(usage.ingredients) {
+		quantity := usage.ingredients[ingredientID]
+
+		change := types.FromDecimal(quantity.Mul(direction))
+		previousStock, newStock, err := tx.IngredientRepo.AdjustIngredientStock(ingredientID, change, movement.userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientStock) {
+				// Look the ingredient up only to explain the shortage
+				ingredient, getErr := tx.IngredientRepo.GetIngredient(ingredientID)
+				if getErr != nil {
+					return fmt.Errorf("%w for ingredient %s", repositories.ErrInsufficientStock, ingredientID)
+				}
+				return fmt.Errorf("%w for ingredient %s: only %s %s available, %s %s requested", repositories.ErrInsufficientStock, ingredient.Name, ingredient.CurrentStock, ingredient.Unit, quantity, ingredient.Unit)
+			}
+			return fmt.Errorf("failed to update stock for ingredient %s: %v", ingredientID, err)
+		}
+		if !restock && !movement.expiredLots {
+			available, err := sellableStock(tx.StockLotRepo, "", &ingredientID, previousStock)
+			if err != nil {
+				return err
+			}
+			if available.LessThan(quantity) {
+				ingredient, err := tx.IngredientRepo.GetIngredient(ingredientID)
+				if err != nil {
+					return fmt.Errorf("%w for ingredient %s", repositories.ErrInsufficientStock, ingredientID)
+				}
+				return fmt.Errorf("%w for ingredient %s: only %s %s available, %s %s requested", repositories.ErrInsufficientStock, ingredient.Name, available, ingredient.Unit, quantity, ingredient.Unit)
+			}
+		}
+		if err := consumeStockLots(tx, "", &ingredientID, change, movement); err != nil {
+			return err
+		}
+
+		stockTransaction := &models.StockTransaction{
+			ID:              uuid.New().String(),
+			IngredientID:    &ingredientID,
+			TransactionType: transactionType,
+			Quantity:        change,
+			PreviousStock:   previousStock,
+			CurrentStock:    newStock,
+			Reason:          movement.reason,
+			ReferenceType:   movement.referenceType,
+			ReferenceID:     movement.referenceID,
+			UserID:          &movement.userID,
+		}
+
+		createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientLocationStock) {
+				ingredient, getErr := tx.IngredientRepo.GetIngredient(ingredientID)
+				if getErr != nil {
+					return fmt.Errorf("insufficient stock for ingredient %s at the selling location", ingredientID)
+				}
+				return fmt.Errorf("insufficient stock for ingredient %s at the selling location: %s %s requested", ingredient.Name, quantity, ingredient.Unit)
+			}
+			return fmt.Errorf("failed to create stock transaction for ingredient %s: %v", ingredientID, err)
+		}
+		movement.changes.add(createdTransaction)
+
+		if restock {
+			ingredient, err := tx.IngredientRepo.GetIngredient(ingredientID)
+			if err != nil {
+				return fmt.Errorf("failed to get ingredient %s: %v", ingredientID, err)
+			}
+			if err := restockLot(tx, &models.StockLot{
+				IngredientID: &ingredientID,
+				ItemName:     ingredient.Name,
+				Unit:         ingredient.Unit,
+			}, stockTransaction, movement); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// consumeStockLots takes stock taken out by a movement out of the item's lots
+func consumeStockLots(tx *repositories.Repository, menuItemID string, ingredientID *string, change types.DecimalText, movement stockMovement) error {
+	if !decimal.Decimal(change).IsNegative() || movement.expiredLots {
+		return nil
+	}
+	if err := tx.StockLotRepo.ConsumeStockLots(menuItemID, ingredientID, types.FromDecimal(decimal.Decimal(change).Neg())); err != nil {
+		return fmt.Errorf("failed to consume stock lots: %v", err)
+	}
+	return nil
+}
+
+// restockLot puts stock returned by a stock transaction into a new lot, valued
+// at the cost the transaction moved it at
+func restockLot(tx *repositories.Repository, lot *models.StockLot, transaction *models.StockTransaction, movement stockMovement) error {
+	lot.QuantityReceived = transaction.Quantity
+	lot.UnitCost = transaction.UnitCost
+	lot.ReceivedBy = &movement.userID
+	if _, err := tx.StockLotRepo.CreateStockLot(lot); err != nil {
+		return fmt.Errorf("failed to create stock lot for %s: %v", lot.ItemName, err)
+	}
+	return nil
+}
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// seatOrder works out the type of a new or moved order. A table makes it a
+// dine-in order and must be active; guests are only counted when dining in.
+func seatOrder(tx *repositories.Repository, orderType *types.OrderType, tableID *string, guestCount *int) (types.OrderType, error) {
+	resolved := types.OrderTypeTakeaway
+	if orderType != nil {
+		resolved = *orderType
+	}
+
+	if tableID != nil {
+		if orderType != nil && *orderType != types.OrderTypeDineIn {
+			return "", errors.New("only dine-in orders can be seated at a table")
+		}
+		resolved = types.OrderTypeDineIn
+
+		table, err := tx.TableRepo.GetTable(*tableID)
+		if err != nil {
+			return "", err
+		}
+		if !table.IsActive {
+			return "", fmt.Errorf("table %s is not in use", table.Name)
+		}
+	}
+
+	switch resolved {
+	case types.OrderTypeDineIn, types.OrderTypeTakeaway, types.OrderTypeDelivery:
+	default:
+		return "", fmt.Errorf("invalid order type: %s", resolved)
+	}
+
+	if guestCount != nil && resolved != types.OrderTypeDineIn {
+		return "", errors.New("guest count only applies to dine-in orders")
+	}
+
+	return resolved, nil
+}
+
+// lockOpenOrders locks the given orders, always in the same order so that two
+// requests touching the same pair cannot deadlock, and checks they are still open
+func lockOpenOrders(tx *repositories.Repository, orderIDs ...string) (map[string]*models.Order, error) {
+	sorted := append([]string(nil), orderIDs...)
+	sort.Strings(sorted)
+
+	orders := make(map[string]*models.Order, len(sorted))
+	for _, orderID := range sorted {
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return nil, fmt.Errorf("order not found: %v", err)
+		}
+		if !isEditableOrder(order) {
+			return nil, fmt.Errorf("order %s is no longer open", order.OrderNumber)
+		}
+		orders[orderID] = order
+	}
+
+	return orders, nil
+}
+
+// TransferOrder moves an open order to another table or changes its guest
+// count. Seating a takeaway or delivery order makes it a dine-in order.
+func (s *OrderService) TransferOrder(orderID string, userID string, transferData *models.OrderTransfer) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if transferData.TableID == nil && transferData.GuestCount == nil {
+		return nil, errors.New("nothing to update: provide a table or guest count")
+	}
+
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		orders, err := lockOpenOrders(tx, orderID)
+		if err != nil {
+			return err
+		}
+		order := orders[orderID]
+
+		tableID := order.TableID
+		if transferData.TableID != nil {
+			tableID = transferData.TableID
+		}
+		guestCount := order.GuestCount
+		if transferData.GuestCount != nil {
+			guestCount = transferData.GuestCount
+		}
+
+		orderType := order.OrderType
+		if transferData.TableID != nil {
+			orderType = types.OrderTypeDineIn
+		}
+
+		order.OrderType, err = seatOrder(tx, &orderType, tableID, guestCount)
+		if err != nil {
+			return err
+		}
+		order.TableID = tableID
+		order.GuestCount = guestCount
+
+		if err := tx.OrderRepo.UpdateOrderTable(order); err != nil {
+			return fmt.Errorf("failed to transfer order: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the updated order
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
+	}
+
+	transferredOrder, err := s.orderWithDetails(updatedOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    transferredOrder,
+	}, nil
+}
+
+// MergeOrders moves every line of another open order into this one, e.g. when
+// two tables join up, and cancels the other order. Lines already in the kitchen
+// stay where they are on the display. If either order was sent to the kitchen
+// the merged order is too, so no line is left waiting.
+func (s *OrderService) MergeOrders(orderID string, userID string, mergeData *models.OrderMerge) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate source order ID
+	_, err = uuid.Parse(mergeData.SourceOrderID)
+	if err != nil {
+		return nil, errors.New("invalid source order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if mergeData.SourceOrderID == orderID {
+		return nil, errors.New("cannot merge an order into itself")
+	}
+
+	movedItems := make(map[string]bool)
+	var ticketIDs []string
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		orders, err := lockOpenOrders(tx, orderID, mergeData.SourceOrderID)
+		if err != nil {
+			return err
+		}
+		order, source := orders[orderID], orders[mergeData.SourceOrderID]
+
+		sourceItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+
+		// Each line keeps its price, options and place in the kitchen
+		for _, sourceItem := range sourceItems {
+			if err := tx.OrderItemRepo.MoveOrderItem(sourceItem.ID, order.ID); err != nil {
+				return fmt.Errorf("failed to move order item: %v", err)
+			}
+			if err := tx.KitchenRepo.MoveTicket(sourceItem.ID, order.ID); err != nil {
+				return fmt.Errorf("failed to move kitchen ticket: %v", err)
+			}
+			movedItems[sourceItem.ID] = true
+		}
+
+		if order.Status == types.OrderStatusPending || source.Status == types.OrderStatusPending {
+			if order.Status == types.OrderStatusDraft {
+				if err := tx.OrderRepo.UpdateOrderStatus(order.ID, string(types.OrderStatusPending)); err != nil {
+					return fmt.Errorf("failed to update order status: %v", err)
+				}
+			}
+
+			ticketIDs, err = tx.KitchenRepo.CreateTicketsForOrder(order.ID)
+			if err != nil {
+				return fmt.Errorf("failed to send order to kitchen: %v", err)
+			}
+		}
+
+		// The guests of both orders are now at this one
+		if source.GuestCount != nil {
+			guestCount := *source.GuestCount
+			if order.GuestCount != nil {
+				guestCount += *order.GuestCount
+			}
+			if order.OrderType == types.OrderTypeDineIn {
+				order.GuestCount = &guestCount
+				if err := tx.OrderRepo.UpdateOrderTable(order); err != nil {
+					return fmt.Errorf("failed to update guest count: %v", err)
+				}
+			}
+		}
+
+		if err := s.repriceDraftOrder(tx, order); err != nil {
+			return err
+		}
+
+		// Clear the emptied order's totals and promotions before closing it
+		if err := s.repriceDraftOrder(tx, source); err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("Merged into order %s", order.OrderNumber)
+		if err := tx.OrderRepo.MergeOrder(source.ID, reason, userID); err != nil {
+			return fmt.Errorf("failed to close merged order: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The moved lines now show this order's number on the kitchen display
+	queued := make(map[string]bool, len(ticketIDs))
+	for _, id := range ticketIDs {
+		queued[id] = true
+	}
+	s.notifyKitchen(kitchen.EventTicketUpdated, orderID, func(ticket *models.KitchenTicket) bool {
+		return movedItems[ticket.OrderItemID] && !queued[ticket.ID]
+	})
+	s.notifyKitchenQueued(orderID, ticketIDs)
+
+	// Fetch the updated order
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
+	}
+
+	mergedOrder, err := s.orderWithDetails(updatedOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    mergedOrder,
+	}, nil
+}
+
+// SplitOrder moves whole lines, or part of a line's quantity, from an open order
+// to a new one so they can be paid separately. The new order has the same type,
+// table and status as the original unless another table is given, and lines
+// already in the kitchen keep their place there.
+func (s *OrderService) SplitOrder(orderID string, userID string, splitData *models.OrderSplit) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if len(splitData.Items) == 0 {
+		return nil, errors.New("select at least one item to split off")
+	}
+
+	requested := make(map[string]bool, len(splitData.Items))
+	for _, splitItem := range splitData.Items {
+		if _, err := uuid.Parse(splitItem.OrderItemID); err != nil {
+			return nil, fmt.Errorf("invalid order item ID: %s", splitItem.OrderItemID)
+		}
+		if requested[splitItem.OrderItemID] {
+			return nil, fmt.Errorf("order item %s is listed more than once", splitItem.OrderItemID)
+		}
+		requested[splitItem.OrderItemID] = true
+	}
+
+	var newOrderID string
+	movedItems := make(map[string]bool)
+	changedItems := make(map[string]bool)
+	copiedItems := make(map[string]bool)
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		orders, err := lockOpenOrders(tx, orderID)
+		if err != nil {
+			return err
+		}
+		order := orders[orderID]
+
+		orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+
+		lines := make(map[string]*models.OrderItem, len(orderItems))
+		for _, orderItem := range orderItems {
+			lines[orderItem.ID] = orderItem
+		}
+
+		// Something has to stay behind, otherwise this is a transfer
+		remaining := len(orderItems)
+		for _, splitItem := range splitData.Items {
+			orderItem, ok := lines[splitItem.OrderItemID]
+			if !ok {
+				return fmt.Errorf("order item %s does not belong to this order", splitItem.OrderItemID)
+			}
+			if splitItem.Quantity != nil && *splitItem.Quantity > orderItem.Quantity {
+				return fmt.Errorf("cannot split off %d of order item %s: only %d ordered", *splitItem.Quantity, splitItem.OrderItemID, orderItem.Quantity)
+			}
+			if splitItem.Quantity == nil || *splitItem.Quantity == orderItem.Quantity {
+				remaining--
+			}
+		}
+		if remaining == 0 {
+			return errors.New("cannot split off every item; transfer the order instead")
+		}
+
+		tableID := order.TableID
+		orderType := order.OrderType
+		if splitData.TableID != nil {
+			tableID = splitData.TableID
+			orderType = types.OrderTypeDineIn
+		}
+		orderType, err = seatOrder(tx, &orderType, tableID, splitData.GuestCount)
+		if err != nil {
+			return err
+		}
+
+		orderNumber, err := s.claimOrderNumber(tx)
+		if err != nil {
+			return err
+		}
+
+		newOrder, err := tx.OrderRepo.CreateOrder(&models.Order{
+			ID:             uuid.New().String(),
+			OrderNumber:    orderNumber,
+			UserID:         userID,
+			Status:         types.OrderStatusDraft,
+			TotalAmount:    types.DecimalText(decimal.Zero),
+			SubtotalAmount: types.DecimalText(decimal.Zero),
+			DiscountAmount: types.DecimalText(decimal.Zero),
+			TaxAmount:      types.DecimalText(decimal.Zero),
+			PaymentStatus:  types.PaymentStatusPending,
+			OrderType:      orderType,
+			TableID:        tableID,
+			GuestCount:     splitData.GuestCount,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create order: %v", err)
+		}
+		newOrderID = newOrder.ID
+
+		if order.Status == types.OrderStatusPending {
+			if err := tx.OrderRepo.UpdateOrderStatus(newOrder.ID, string(types.OrderStatusPending)); err != nil {
+				return fmt.Errorf("failed to update order status: %v", err)
+			}
+			newOrder.Status = types.OrderStatusPending
+		}
+
+		for _, splitItem := range splitData.Items {
+			orderItem := lines[splitItem.OrderItemID]
+
+			// A whole line moves across with its options and kitchen ticket
+			if splitItem.Quantity == nil || *splitItem.Quantity == orderItem.Quantity {
+				if err := tx.OrderItemRepo.MoveOrderItem(orderItem.ID, newOrder.ID); err != nil {
+					return fmt.Errorf("failed to move order item: %v", err)
+				}
+				if err := tx.KitchenRepo.MoveTicket(orderItem.ID, newOrder.ID); err != nil {
+					return fmt.Errorf("failed to move kitchen ticket: %v", err)
+				}
+				movedItems[orderItem.ID] = true
+				continue
+			}
+
+			// Part of a line becomes a copy of it at the same price on the new order
+			orderItem.Quantity -= *splitItem.Quantity
+			orderItem.TotalPrice = orderItem.UnitPrice.Mul(types.FromDecimal(decimal.NewFromInt(int64(orderItem.Quantity))))
+			if _, err := tx.OrderItemRepo.UpdateOrderItem(orderItem); err != nil {
+				return fmt.Errorf("failed to update order item: %v", err)
+			}
+			changedItems[orderItem.ID] = true
+
+			splitLine, err := tx.OrderItemRepo.CreateOrderItem(&models.OrderItem{
+				ID:         uuid.New().String(),
+				OrderID:    newOrder.ID,
+				MenuItemID: orderItem.MenuItemID,
+				Quantity:   *splitItem.Quantity,
+				UnitPrice:  orderItem.UnitPrice,
+				TotalPrice: orderItem.UnitPrice.Mul(types.FromDecimal(decimal.NewFromInt(int64(*splitItem.Quantity)))),
+				Notes:      orderItem.Notes,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create order item: %v", err)
+			}
+			if err := tx.ModifierRepo.CopyOrderItemModifiers(orderItem.ID, splitLine.ID); err != nil {
+				return fmt.Errorf("failed to copy order item modifiers: %v", err)
+			}
+			if err := tx.KitchenRepo.CopyTicket(orderItem.ID, splitLine.ID); err != nil {
+				return fmt.Errorf("failed to copy kitchen ticket: %v", err)
+			}
+			copiedItems[splitLine.ID] = true
+		}
+
+		if err := s.repriceDraftOrder(tx, order); err != nil {
+			return err
+		}
+
+		return s.repriceDraftOrder(tx, newOrder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Show the reduced quantities and the moved lines, and add the copies of
+	// split lines to the kitchen display
+	s.notifyKitchen(kitchen.EventTicketUpdated, orderID, func(ticket *models.KitchenTicket) bool {
+		return changedItems[ticket.OrderItemID]
+	})
+	s.notifyKitchen(kitchen.EventTicketUpdated, newOrderID, func(ticket *models.KitchenTicket) bool {
+		return movedItems[ticket.OrderItemID]
+	})
+	s.notifyKitchen(kitchen.EventTicketsQueued, newOrderID, func(ticket *models.KitchenTicket) bool {
+		return copiedItems[ticket.OrderItemID]
+	})
+
+	// Fetch both orders
+	updatedOrder, err := s.repo.OrderRepo.GetOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
+	}
+
+	remainingOrder, err := s.orderWithDetails(updatedOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	newOrder, err := s.repo.OrderRepo.GetOrder(newOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch new order: %v", err)
+	}
+
+	splitOrder, err := s.orderWithDetails(newOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message":   "Order split successfully",
+			"order":     remainingOrder,
+			"new_order": splitOrder,
+		},
+	}, nil
+}
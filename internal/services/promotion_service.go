@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// clockPattern matches a HH:MM time of day
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// PromotionService handles promotion and voucher business logic
+type PromotionService struct {
+	promotionRepo repositories.PromotionRepo
+}
+
+// NewPromotionService creates a new promotion service
+func NewPromotionService(
+	promotionRepo repositories.PromotionRepo,
+) *PromotionService {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+	}
+}
+
+// normalizeVoucherCode makes voucher codes case-insensitive
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validatePromotion checks that a promotion rule is complete and consistent
+func validatePromotion(promotion *models.Promotion) error {
+	if strings.TrimSpace(promotion.Name) == "" {
+		return errors.New("name is required")
+	}
+
+	value := decimal.Decimal(promotion.Value)
+	switch promotion.Type {
+	case types.PromotionTypePercentage:
+		if !value.IsPositive() || value.GreaterThan(decimal.NewFromInt(100)) {
+			return errors.New("percentage value must be greater than 0 and at most 100")
+		}
+	case types.PromotionTypeFixedAmount:
+		if !value.IsPositive() {
+			return errors.New("fixed amount value must be greater than zero")
+		}
+	case types.PromotionTypeBuyXGetY:
+		if promotion.BuyQuantity == nil || *promotion.BuyQuantity <= 0 ||
+			promotion.GetQuantity == nil || *promotion.GetQuantity <= 0 {
+			return errors.New("buy_x_get_y promotions require buy_quantity and get_quantity")
+		}
+		if promotion.TargetType == types.PromotionTargetOrder {
+			return errors.New("buy_x_get_y promotions must target a category or menu item")
+		}
+	default:
+		return fmt.Errorf("invalid promotion type: %s", promotion.Type)
+	}
+
+	switch promotion.TargetType {
+	case types.PromotionTargetOrder:
+		promotion.TargetID = nil
+	case types.PromotionTargetCategory, types.PromotionTargetMenuItem:
+		if promotion.TargetID == nil {
+			return fmt.Errorf("target_id is required for %s promotions", promotion.TargetType)
+		}
+		if _, err := uuid.Parse(*promotion.TargetID); err != nil {
+			return errors.New("invalid target ID")
+		}
+	default:
+		return fmt.Errorf("invalid target type: %s", promotion.TargetType)
+	}
+
+	if decimal.Decimal(promotion.MinSpend).IsNegative() {
+		return errors.New("minimum spend cannot be negative")
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	if (promotion.DailyStartTime == nil) != (promotion.DailyEndTime == nil) {
+		return errors.New("daily_start_time and daily_end_time must be set together")
+	}
+	if promotion.DailyStartTime != nil {
+		if !clockPattern.MatchString(*promotion.DailyStartTime) || !clockPattern.MatchString(*promotion.DailyEndTime) {
+			return errors.New("daily times must be in HH:MM format")
+		}
+		if *promotion.DailyStartTime == *promotion.DailyEndTime {
+			return errors.New("daily_start_time and daily_end_time cannot be equal")
+		}
+	}
+
+	if promotion.UsageLimit != nil && *promotion.UsageLimit <= 0 {
+		return errors.New("usage limit must be greater than zero")
+	}
+
+	if promotion.VoucherCode != nil {
+		code := normalizeVoucherCode(*promotion.VoucherCode)
+		if code == "" {
+			promotion.VoucherCode = nil
+		} else {
+			promotion.VoucherCode = &code
+		}
+	}
+
+	return nil
+}
+
+// CreatePromotion creates a new promotion or voucher
+func (s *PromotionService) CreatePromotion(userID string, promotionData *models.PromotionCreate) (*types.APIResponse, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	promotion := &models.Promotion{
+		ID:             uuid.New().String(),
+		Name:           promotionData.Name,
+		Description:    promotionData.Description,
+		Type:           promotionData.Type,
+		Value:          promotionData.Value,
+		BuyQuantity:    promotionData.BuyQuantity,
+		GetQuantity:    promotionData.GetQuantity,
+		TargetType:     promotionData.TargetType,
+		TargetID:       promotionData.TargetID,
+		VoucherCode:    promotionData.VoucherCode,
+		MinSpend:       promotionData.MinSpend,
+		StartsAt:       promotionData.StartsAt,
+		EndsAt:         promotionData.EndsAt,
+		DailyStartTime: promotionData.DailyStartTime,
+		DailyEndTime:   promotionData.DailyEndTime,
+		UsageLimit:     promotionData.UsageLimit,
+		IsStackable:    promotionData.IsStackable,
+		Priority:       promotionData.Priority,
+		IsActive:       true,
+		CreatedBy:      &userID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if promotion.TargetType == "" {
+		promotion.TargetType = types.PromotionTargetOrder
+	}
+	if promotionData.IsActive != nil {
+		promotion.IsActive = *promotionData.IsActive
+	}
+
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	createdPromotion, err := s.promotionRepo.CreatePromotion(promotion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdPromotion,
+	}, nil
+}
+
+// GetPromotion retrieves a promotion by ID
+func (s *PromotionService) GetPromotion(id string) (*types.APIResponse, error) {
+	// Validate promotion ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid promotion ID")
+	}
+
+	promotion, err := s.promotionRepo.GetPromotion(id)
+	if err != nil {
+		return nil, fmt.Errorf("promotion not found: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    promotion,
+	}, nil
+}
+
+// ListPromotions retrieves a list of promotions based on filter criteria
+func (s *PromotionService) ListPromotions(filter models.PromotionFilter) (*types.APIResponse, error) {
+	promotions, err := s.promotionRepo.ListPromotions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    promotions,
+	}, nil
+}
+
+// UpdatePromotion updates an existing promotion
+func (s *PromotionService) UpdatePromotion(id string, promotionData *models.PromotionUpdate) (*types.APIResponse, error) {
+	// Validate promotion ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid promotion ID")
+	}
+
+	// Get existing promotion
+	existingPromotion, err := s.promotionRepo.GetPromotion(id)
+	if err != nil {
+		return nil, fmt.Errorf("promotion not found: %v", err)
+	}
+
+	// Update fields if provided
+	if promotionData.Name != nil {
+		existingPromotion.Name = *promotionData.Name
+	}
+	if promotionData.Description != nil {
+		existingPromotion.Description = *promotionData.Description
+	}
+	if promotionData.Type != nil {
+		existingPromotion.Type = *promotionData.Type
+	}
+	if promotionData.Value != nil {
+		existingPromotion.Value = *promotionData.Value
+	}
+	if promotionData.BuyQuantity != nil {
+		existingPromotion.BuyQuantity = promotionData.BuyQuantity
+	}
+	if promotionData.GetQuantity != nil {
+		existingPromotion.GetQuantity = promotionData.GetQuantity
+	}
+	if promotionData.TargetType != nil {
+		existingPromotion.TargetType = *promotionData.TargetType
+	}
+	if promotionData.TargetID != nil {
+		existingPromotion.TargetID = promotionData.TargetID
+	}
+	if promotionData.VoucherCode != nil {
+		existingPromotion.VoucherCode = promotionData.VoucherCode
+	}
+	if promotionData.MinSpend != nil {
+		existingPromotion.MinSpend = *promotionData.MinSpend
+	}
+	if promotionData.StartsAt != nil {
+		existingPromotion.StartsAt = promotionData.StartsAt
+	}
+	if promotionData.EndsAt != nil {
+		existingPromotion.EndsAt = promotionData.EndsAt
+	}
+	if promotionData.DailyStartTime != nil {
+		existingPromotion.DailyStartTime = promotionData.DailyStartTime
+	}
+	if promotionData.DailyEndTime != nil {
+		existingPromotion.DailyEndTime = promotionData.DailyEndTime
+	}
+	if promotionData.UsageLimit != nil {
+		existingPromotion.UsageLimit = promotionData.UsageLimit
+	}
+	if promotionData.IsStackable != nil {
+		existingPromotion.IsStackable = *promotionData.IsStackable
+	}
+	if promotionData.Priority != nil {
+		existingPromotion.Priority = *promotionData.Priority
+	}
+	if promotionData.IsActive != nil {
+		existingPromotion.IsActive = *promotionData.IsActive
+	}
+
+	if err := validatePromotion(existingPromotion); err != nil {
+		return nil, err
+	}
+
+	// Save updated promotion
+	updatedPromotion, err := s.promotionRepo.UpdatePromotion(existingPromotion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedPromotion,
+	}, nil
+}
+
+// DeletePromotion deletes a promotion by ID
+func (s *PromotionService) DeletePromotion(id string) (*types.APIResponse, error) {
+	// Validate promotion ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid promotion ID")
+	}
+
+	// Check if promotion exists
+	_, err = s.promotionRepo.GetPromotion(id)
+	if err != nil {
+		return nil, fmt.Errorf("promotion not found: %v", err)
+	}
+
+	err = s.promotionRepo.DeletePromotion(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete promotion: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	}, nil
+}
@@ -0,0 +1,642 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/units"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PurchaseExpenseCategory is the expense category goods received are booked under
+const PurchaseExpenseCategory = "Inventory Purchases"
+
+// PurchaseOrderService handles ordering stock from suppliers and receiving it
+type PurchaseOrderService struct {
+	repos
+	menuAvailability *MenuAvailability
+}
+
+// NewPurchaseOrderService creates a new purchase order service
+func NewPurchaseOrderService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	menuAvailability *MenuAvailability,
+) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		repos:            repos{repo: repo, uow: uow},
+		menuAvailability: menuAvailability,
+	}
+}
+
+// stockUnitOf returns the unit a purchase order line is counted in once it is
+// in stock, and the unit it is ordered in when none is given
+func stockUnitOf(tx *repositories.Repository, menuItemID, ingredientID *string) (stockUnit, orderUnit string, err error) {
+	if ingredientID != nil {
+		ingredient, err := tx.IngredientRepo.GetIngredient(*ingredientID)
+		if err != nil {
+			return "", "", fmt.Errorf("ingredient not found: %s", *ingredientID)
+		}
+		if !ingredient.IsActive {
+			return "", "", fmt.Errorf("ingredient is not active: %s", ingredient.Name)
+		}
+		if ingredient.PurchaseUnit != nil {
+			return ingredient.Unit, *ingredient.PurchaseUnit, nil
+		}
+		return ingredient.Unit, ingredient.Unit, nil
+	}
+
+	if _, err := tx.MenuRepo.GetMenuItem(*menuItemID); err != nil {
+		return "", "", fmt.Errorf("menu item not found: %s", *menuItemID)
+	}
+	inventory, err := getOrCreateInventory(tx.InventoryRepo, *menuItemID)
+	if err != nil {
+		return "", "", err
+	}
+	return inventory.Unit, inventory.Unit, nil
+}
+
+// purchaseOrderLines checks the lines of a purchase order and returns them with
+// their units filled in. Each line names exactly one menu item or ingredient,
+// which may appear only once on an order.
+func purchaseOrderLines(tx *repositories.Repository, inputs []models.PurchaseOrderItemInput) ([]models.PurchaseOrderItem, error) {
+	lines := make([]models.PurchaseOrderItem, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		if (input.MenuItemID == nil) == (input.IngredientID == nil) {
+			return nil, errors.New("each line must name either a menu item or an ingredient")
+		}
+
+		itemID := ""
+		if input.MenuItemID != nil {
+			itemID = *input.MenuItemID
+		} else {
+			itemID = *input.IngredientID
+		}
+		if seen[itemID] {
+			return nil, fmt.Errorf("item %s is listed more than once", itemID)
+		}
+		seen[itemID] = true
+
+		quantity := decimal.Decimal(input.Quantity).Round(units.Scale)
+		if !quantity.IsPositive() {
+			return nil, fmt.Errorf("quantity of item %s must be greater than zero", itemID)
+		}
+		if decimal.Decimal(input.UnitCost).IsNegative() {
+			return nil, fmt.Errorf("unit cost of item %s cannot be negative", itemID)
+		}
+
+		stockUnit, unit, err := stockUnitOf(tx, input.MenuItemID, input.IngredientID)
+		if err != nil {
+			return nil, err
+		}
+		if code := trimmedUnit(input.Unit); code != nil {
+			unit = *code
+		}
+		if err := checkUnitsCompatible(tx.UnitRepo, unit, stockUnit); err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, models.PurchaseOrderItem{
+			MenuItemID:   input.MenuItemID,
+			IngredientID: input.IngredientID,
+			Unit:         unit,
+			Quantity:     types.FromDecimal(quantity),
+			UnitCost:     types.FromDecimal(decimal.Decimal(input.UnitCost).Round(2)),
+		})
+	}
+
+	return lines, nil
+}
+
+// getActiveSupplier retrieves a supplier new orders can be placed with
+func getActiveSupplier(supplierRepo repositories.SupplierRepo, id string) (*models.Supplier, error) {
+	supplier, err := supplierRepo.GetSupplier(id)
+	if err != nil {
+		return nil, fmt.Errorf("supplier not found: %s", id)
+	}
+	if !supplier.IsActive {
+		return nil, fmt.Errorf("supplier is not active: %s", supplier.Name)
+	}
+	return supplier, nil
+}
+
+// loadPurchaseOrder retrieves a purchase order with its lines and deliveries
+func loadPurchaseOrder(purchaseOrderRepo repositories.PurchaseOrderRepo, id string) (*models.PurchaseOrder, error) {
+	order, err := purchaseOrderRepo.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Items, err = purchaseOrderRepo.ListPurchaseOrderItems(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %v", err)
+	}
+
+	order.Receipts, err = purchaseOrderRepo.ListGoodsReceivedNotes(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goods received notes: %v", err)
+	}
+
+	return order, nil
+}
+
+// CreatePurchaseOrder drafts a purchase order with a supplier
+func (s *PurchaseOrderService) CreatePurchaseOrder(userID string, orderData *models.PurchaseOrderCreate) (*types.APIResponse, error) {
+	var order *models.PurchaseOrder
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		supplier, err := getActiveSupplier(tx.SupplierRepo, orderData.SupplierID)
+		if err != nil {
+			return err
+		}
+
+		lines, err := purchaseOrderLines(tx, orderData.Items)
+		if err != nil {
+			return err
+		}
+
+		createdOrder, err := tx.PurchaseOrderRepo.CreatePurchaseOrder(&models.PurchaseOrder{
+			SupplierID:   supplier.ID,
+			SupplierName: supplier.Name,
+			ExpectedDate: orderData.ExpectedDate,
+			Notes:        orderData.Notes,
+			CreatedBy:    userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order: %v", err)
+		}
+
+		if err := tx.PurchaseOrderRepo.SetPurchaseOrderItems(createdOrder.ID, lines); err != nil {
+			return fmt.Errorf("failed to create purchase order items: %v", err)
+		}
+
+		order, err = loadPurchaseOrder(tx.PurchaseOrderRepo, createdOrder.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Purchase order created successfully",
+		Data:    order,
+	}, nil
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines and deliveries
+func (s *PurchaseOrderService) GetPurchaseOrder(id string) (*types.APIResponse, error) {
+	// Validate purchase order ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid purchase order ID")
+	}
+
+	order, err := loadPurchaseOrder(s.repo.PurchaseOrderRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    order,
+	}, nil
+}
+
+// ListPurchaseOrders retrieves purchase orders with optional filtering
+func (s *PurchaseOrderService) ListPurchaseOrders(filter models.PurchaseOrderFilter) (*types.APIResponse, error) {
+	orders, err := s.repo.PurchaseOrderRepo.ListPurchaseOrders(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    orders,
+	}, nil
+}
+
+// UpdatePurchaseOrder updates a purchase order that has not been sent yet
+func (s *PurchaseOrderService) UpdatePurchaseOrder(id string, orderData *models.PurchaseOrderUpdate) (*types.APIResponse, error) {
+	// Validate purchase order ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid purchase order ID")
+	}
+
+	var order *models.PurchaseOrder
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		existingOrder, err := tx.PurchaseOrderRepo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if existingOrder.Status != types.PurchaseOrderStatusDraft {
+			return fmt.Errorf("only draft purchase orders can be changed, this one is %s", existingOrder.Status)
+		}
+
+		if orderData.SupplierID != nil && *orderData.SupplierID != existingOrder.SupplierID {
+			supplier, err := getActiveSupplier(tx.SupplierRepo, *orderData.SupplierID)
+			if err != nil {
+				return err
+			}
+			existingOrder.SupplierID = supplier.ID
+		}
+		if orderData.ExpectedDate != nil {
+			existingOrder.ExpectedDate = orderData.ExpectedDate
+		}
+		if orderData.Notes != nil {
+			existingOrder.Notes = orderData.Notes
+		}
+
+		if err := tx.PurchaseOrderRepo.UpdatePurchaseOrder(existingOrder); err != nil {
+			return fmt.Errorf("failed to update purchase order: %v", err)
+		}
+
+		if orderData.Items != nil {
+			lines, err := purchaseOrderLines(tx, orderData.Items)
+			if err != nil {
+				return err
+			}
+			if err := tx.PurchaseOrderRepo.SetPurchaseOrderItems(id, lines); err != nil {
+				return fmt.Errorf("failed to update purchase order items: %v", err)
+			}
+		}
+
+		order, err = loadPurchaseOrder(tx.PurchaseOrderRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Purchase order updated successfully",
+		Data:    order,
+	}, nil
+}
+
+// changePurchaseOrderStatus moves a purchase order to a new status, provided
+// it is currently in one of the allowed ones
+func (s *PurchaseOrderService) changePurchaseOrderStatus(id string, to types.PurchaseOrderStatus, allowed ...types.PurchaseOrderStatus) (*models.PurchaseOrder, error) {
+	// Validate purchase order ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid purchase order ID")
+	}
+
+	var order *models.PurchaseOrder
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		existingOrder, err := tx.PurchaseOrderRepo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		isAllowed := false
+		for _, status := range allowed {
+			if existingOrder.Status == status {
+				isAllowed = true
+				break
+			}
+		}
+		if !isAllowed {
+			return fmt.Errorf("cannot move a %s purchase order to %s", existingOrder.Status, to)
+		}
+
+		if err := tx.PurchaseOrderRepo.UpdatePurchaseOrderStatus(id, to); err != nil {
+			return fmt.Errorf("failed to update purchase order status: %v", err)
+		}
+
+		order, err = loadPurchaseOrder(tx.PurchaseOrderRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// SendPurchaseOrder marks a draft purchase order as sent to the supplier, after
+// which its lines can no longer change
+func (s *PurchaseOrderService) SendPurchaseOrder(id string) (*types.APIResponse, error) {
+	order, err := s.changePurchaseOrderStatus(id, types.PurchaseOrderStatusSent, types.PurchaseOrderStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Purchase order sent successfully",
+		Data:    order,
+	}, nil
+}
+
+// CancelPurchaseOrder cancels a purchase order that is not yet fully received.
+// Goods already received stay in stock.
+func (s *PurchaseOrderService) CancelPurchaseOrder(id string) (*types.APIResponse, error) {
+	order, err := s.changePurchaseOrderStatus(id, types.PurchaseOrderStatusCancelled,
+		types.PurchaseOrderStatusDraft, types.PurchaseOrderStatusSent, types.PurchaseOrderStatusPartiallyReceived)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Purchase order cancelled successfully",
+		Data:    order,
+	}, nil
+}
+
+// receivedLine is a purchase order line as it arrives in a delivery
+type receivedLine struct {
+	item          models.PurchaseOrderItem
+	quantity      decimal.Decimal // In the unit the line was ordered in
+	stockQuantity decimal.Decimal // In the stock unit
+	stockUnit     string
+	unitCost      decimal.Decimal // Per unit ordered
+	value         decimal.Decimal
+	menuItem      *models.MenuItem
+	lotNumber     *string
+	expiresAt     *time.Time
+}
+
+// receivedLines checks a delivery against the lines of a purchase order: every
+// line received must be on the order, be received only once and not exceed
+// the quantity still outstanding
+func receivedLines(tx *repositories.Repository, order *models.PurchaseOrder, inputs []models.GoodsReceiptItemInput) ([]receivedLine, error) {
+	items, err := tx.PurchaseOrderRepo.ListPurchaseOrderItems(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %v", err)
+	}
+	itemsByID := make(map[string]models.PurchaseOrderItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	lines := make([]receivedLine, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		item, ok := itemsByID[input.PurchaseOrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("item %s is not on purchase order %s", input.PurchaseOrderItemID, order.PONumber)
+		}
+		if seen[item.ID] {
+			return nil, fmt.Errorf("item %s is listed more than once", item.ItemName)
+		}
+		seen[item.ID] = true
+
+		quantity := decimal.Decimal(input.Quantity).Round(units.Scale)
+		if !quantity.IsPositive() {
+			return nil, fmt.Errorf("quantity of item %s must be greater than zero", item.ItemName)
+		}
+		outstanding := decimal.Decimal(item.Quantity).Sub(decimal.Decimal(item.ReceivedQuantity))
+		if quantity.GreaterThan(outstanding) {
+			return nil, fmt.Errorf("cannot receive %s %s of %s: only %s %s outstanding", quantity, item.Unit, item.ItemName, outstanding, item.Unit)
+		}
+
+		unitCost := decimal.Decimal(item.UnitCost)
+		if input.UnitCost != nil {
+			if decimal.Decimal(*input.UnitCost).IsNegative() {
+				return nil, fmt.Errorf("unit cost of item %s cannot be negative", item.ItemName)
+			}
+			unitCost = decimal.Decimal(*input.UnitCost).Round(2)
+		}
+
+		if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("item %s has already expired", item.ItemName)
+		}
+
+		line := receivedLine{
+			item:      item,
+			quantity:  quantity,
+			unitCost:  unitCost,
+			value:     quantity.Mul(unitCost).Round(2),
+			lotNumber: input.LotNumber,
+			expiresAt: input.ExpiresAt,
+		}
+
+		var stockUnit string
+		if item.IngredientID != nil {
+			ingredient, err := tx.IngredientRepo.GetIngredient(*item.IngredientID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get ingredient %s: %v", *item.IngredientID, err)
+			}
+			stockUnit = ingredient.Unit
+		} else {
+			line.menuItem, err = tx.MenuRepo.GetMenuItem(*item.MenuItemID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get menu item %s: %v", *item.MenuItemID, err)
+			}
+			inventory, err := getOrCreateInventory(tx.InventoryRepo, *item.MenuItemID)
+			if err != nil {
+				return nil, err
+			}
+			stockUnit = inventory.Unit
+		}
+
+		line.stockQuantity, err = toStockUnit(tx.UnitRepo, types.FromDecimal(quantity), &item.Unit, stockUnit)
+		if err != nil {
+			return nil, err
+		}
+		line.stockUnit = stockUnit
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// receiveLine puts a received line into stock as a new lot, records it on the
+// goods received note and updates the cost of the item, all from what was paid
+// for it
+func receiveLine(tx *repositories.Repository, order *models.PurchaseOrder, note *models.GoodsReceivedNote, line receivedLine, userID string, changes *availabilityChanges) error {
+	if err := tx.PurchaseOrderRepo.ReceivePurchaseOrderItem(line.item.ID, types.FromDecimal(line.quantity)); err != nil {
+		if errors.Is(err, repositories.ErrOverReceipt) {
+			return fmt.Errorf("cannot receive more of %s than was ordered", line.item.ItemName)
+		}
+		return fmt.Errorf("failed to receive purchase order item %s: %v", line.item.ID, err)
+	}
+
+	var previousStock, currentStock types.DecimalText
+	var err error
+	change := types.FromDecimal(line.stockQuantity)
+	if line.item.IngredientID != nil {
+		previousStock, currentStock, err = tx.IngredientRepo.AdjustIngredientStock(*line.item.IngredientID, change, userID)
+	} else {
+		previousStock, currentStock, err = tx.InventoryRepo.AdjustInventoryStock(*line.item.MenuItemID, change, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update stock of %s: %v", line.item.ItemName, err)
+	}
+
+	// The cost of one stock unit is what was paid per unit ordered, spread over
+	// the stock units each of those holds
+	stockUnitCost := line.quantity.Mul(line.unitCost).Div(line.stockQuantity)
+	receivedCost := types.FromDecimal(stockUnitCost)
+
+	referenceType := types.ReferenceTypePurchaseOrder
+	stockTransaction := &models.StockTransaction{
+		ID:              uuid.New().String(),
+		IngredientID:    line.item.IngredientID,
+		TransactionType: types.TransactionTypeIn,
+		Quantity:        change,
+		PreviousStock:   previousStock,
+		CurrentStock:    currentStock,
+		Reason:          fmt.Sprintf("Goods received on %s", note.GRNNumber),
+		ReferenceType:   &referenceType,
+		ReferenceID:     &order.ID,
+		UserID:          &userID,
+		ReceivedCost:    &receivedCost,
+	}
+	if line.item.MenuItemID != nil {
+		stockTransaction.MenuItemID = *line.item.MenuItemID
+	}
+	createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+	if err != nil {
+		return fmt.Errorf("failed to create stock transaction for %s: %v", line.item.ItemName, err)
+	}
+	changes.add(createdTransaction)
+
+	if err := tx.PurchaseOrderRepo.CreateGoodsReceivedNoteItem(&models.GoodsReceivedNoteItem{
+		GoodsReceivedNoteID: note.ID,
+		PurchaseOrderItemID: line.item.ID,
+		Quantity:            types.FromDecimal(line.quantity),
+		StockQuantity:       change,
+		UnitCost:            types.FromDecimal(line.unitCost),
+	}); err != nil {
+		return fmt.Errorf("failed to record goods received for %s: %v", line.item.ItemName, err)
+	}
+
+	if _, err := tx.StockLotRepo.CreateStockLot(&models.StockLot{
+		MenuItemID:          line.item.MenuItemID,
+		IngredientID:        line.item.IngredientID,
+		ItemName:            line.item.ItemName,
+		LotNumber:           line.lotNumber,
+		Unit:                line.stockUnit,
+		QuantityReceived:    change,
+		UnitCost:            types.FromDecimal(stockUnitCost.Round(4)),
+		ExpiresAt:           line.expiresAt,
+		GoodsReceivedNoteID: &note.ID,
+		ReceivedBy:          &userID,
+	}); err != nil {
+		return fmt.Errorf("failed to create stock lot for %s: %v", line.item.ItemName, err)
+	}
+	if line.item.IngredientID != nil {
+		if err := tx.IngredientRepo.UpdateIngredientCostPrice(*line.item.IngredientID, types.FromDecimal(stockUnitCost.Round(4))); err != nil {
+			return fmt.Errorf("failed to update cost price of %s: %v", line.item.ItemName, err)
+		}
+		return nil
+	}
+
+	cost := stockUnitCost.Round(2)
+	if cost.GreaterThan(decimal.Decimal(line.menuItem.Price)) {
+		return fmt.Errorf("cost of %s would be %s, above its price of %s; raise the price first", line.item.ItemName, cost, line.menuItem.Price)
+	}
+	if err := tx.MenuRepo.UpdateMenuItemCost(*line.item.MenuItemID, types.FromDecimal(cost)); err != nil {
+		return fmt.Errorf("failed to update cost of %s: %v", line.item.ItemName, err)
+	}
+
+	return nil
+}
+
+// ReceiveGoods records a delivery against a sent purchase order. The goods go
+// into stock as `in` stock transactions referencing the order and as one lot
+// per line, expiring when the line says. Each item's cost is updated from
+// what was paid for it, and the value of the delivery is booked as an expense.
+func (s *PurchaseOrderService) ReceiveGoods(id, userID string, receiptData *models.GoodsReceiptCreate) (*types.APIResponse, error) {
+	// Validate purchase order ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid purchase order ID")
+	}
+
+	var order *models.PurchaseOrder
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so that concurrent deliveries are received one at a time
+		existingOrder, err := tx.PurchaseOrderRepo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if existingOrder.Status != types.PurchaseOrderStatusSent && existingOrder.Status != types.PurchaseOrderStatusPartiallyReceived {
+			return fmt.Errorf("cannot receive goods against a %s purchase order", existingOrder.Status)
+		}
+
+		lines, err := receivedLines(tx, existingOrder, receiptData.Items)
+		if err != nil {
+			return err
+		}
+
+		total := decimal.Zero
+		for _, line := range lines {
+			total = total.Add(line.value)
+		}
+
+		note := &models.GoodsReceivedNote{
+			PurchaseOrderID: existingOrder.ID,
+			TotalAmount:     types.FromDecimal(total),
+			Notes:           receiptData.Notes,
+			ReceivedBy:      userID,
+		}
+		if total.IsPositive() {
+			expense, err := tx.ExpenseRepo.CreateExpense(&models.Expense{
+				ID:          uuid.New().String(),
+				Category:    PurchaseExpenseCategory,
+				Description: fmt.Sprintf("Goods received against %s from %s", existingOrder.PONumber, existingOrder.SupplierName),
+				Amount:      types.FromDecimal(total),
+				Date:        time.Now(),
+				UserID:      &userID,
+				CreatedAt:   time.Now(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record purchase expense: %v", err)
+			}
+			note.ExpenseID = &expense.ID
+		}
+
+		createdNote, err := tx.PurchaseOrderRepo.CreateGoodsReceivedNote(note)
+		if err != nil {
+			return fmt.Errorf("failed to create goods received note: %v", err)
+		}
+
+		for _, line := range lines {
+			if err := receiveLine(tx, existingOrder, createdNote, line, userID, &changes); err != nil {
+				return err
+			}
+		}
+
+		items, err := tx.PurchaseOrderRepo.ListPurchaseOrderItems(id)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase order items: %v", err)
+		}
+		status := types.PurchaseOrderStatusReceived
+		for _, item := range items {
+			if item.ReceivedQuantity.Cmp(item.Quantity) < 0 {
+				status = types.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+		if err := tx.PurchaseOrderRepo.UpdatePurchaseOrderStatus(id, status); err != nil {
+			return fmt.Errorf("failed to update purchase order status: %v", err)
+		}
+
+		order, err = loadPurchaseOrder(tx.PurchaseOrderRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Goods received successfully",
+		Data:    order,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// ReceiptService handles printing and reprinting receipts of completed orders
+type ReceiptService struct {
+	repos
+	orderService *OrderService // Loads the order lines, promotions and payments
+	settings     receipt.Settings
+}
+
+// NewReceiptService creates a new receipt service
+func NewReceiptService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	orderService *OrderService,
+	settings receipt.Settings,
+) *ReceiptService {
+	return &ReceiptService{
+		repos:        repos{repo: repo, uow: uow},
+		orderService: orderService,
+		settings:     settings,
+	}
+}
+
+// PrintReceipt records a print of a completed order's receipt and renders it in
+// the given format. Every print after the first is marked as a reprint.
+func (s *ReceiptService) PrintReceipt(orderID string, userID string, format receipt.Format) (*receipt.Document, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if _, err := receipt.ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+
+	var order *models.Order
+	var receiptPrint *models.ReceiptPrint
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the order so concurrent prints get consecutive copy numbers
+		var err error
+		order, err = tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != types.OrderStatusCompleted {
+			return fmt.Errorf("order is %s; receipts can only be printed for completed orders", order.Status)
+		}
+
+		receiptPrint, err = tx.ReceiptRepo.CreateReceiptPrint(orderID, userID, string(format))
+		if err != nil {
+			return fmt.Errorf("failed to record receipt print: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orderWithDetails, err := s.orderService.orderWithDetails(order)
+	if err != nil {
+		return nil, err
+	}
+
+	cashier, err := s.repo.UserRepo.GetUser(order.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cashier: %v", err)
+	}
+	orderWithDetails.UserName = strings.TrimSpace(cashier.FirstName + " " + cashier.LastName)
+	if orderWithDetails.UserName == "" {
+		orderWithDetails.UserName = cashier.Username
+	}
+
+	document, err := receipt.Render(orderWithDetails, s.settings, format, receipt.Options{
+		Copy:      receiptPrint.CopyNumber,
+		PrintedAt: receiptPrint.PrintedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %v", err)
+	}
+
+	return document, nil
+}
+
+// ListReceiptPrints retrieves every print of an order's receipt, oldest first
+func (s *ReceiptService) ListReceiptPrints(orderID string) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	if _, err := s.repo.OrderRepo.GetOrder(orderID); err != nil {
+		return nil, err
+	}
+
+	prints, err := s.repo.ReceiptRepo.ListReceiptPrints(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipt prints: %v", err)
+	}
+	if prints == nil {
+		prints = []*models.ReceiptPrint{}
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    prints,
+	}, nil
+}
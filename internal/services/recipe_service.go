@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// RecipeService handles the recipes that say which ingredients go into a menu
+// item, and which extra ingredients a modifier option adds
+type RecipeService struct {
+	repos
+}
+
+// NewRecipeService creates a new recipe service
+func NewRecipeService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+) *RecipeService {
+	return &RecipeService{
+		repos: repos{repo: repo, uow: uow},
+	}
+}
+
+// normalizeRecipeItems checks that every ingredient of a recipe exists, is
+// active and is listed only once, and returns the items with their quantities
+// converted to each ingredient's stock unit
+func normalizeRecipeItems(tx *repositories.Repository, items []models.RecipeItemInput) ([]models.RecipeItemInput, error) {
+	normalized := make([]models.RecipeItemInput, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item.IngredientID] {
+			return nil, fmt.Errorf("ingredient %s is listed more than once", item.IngredientID)
+		}
+		seen[item.IngredientID] = true
+
+		ingredient, err := tx.IngredientRepo.GetIngredient(item.IngredientID)
+		if err != nil {
+			return nil, fmt.Errorf("ingredient not found: %s", item.IngredientID)
+		}
+		if !ingredient.IsActive {
+			return nil, fmt.Errorf("ingredient is not active: %s", ingredient.Name)
+		}
+
+		quantity, err := toStockUnit(tx.UnitRepo, item.Quantity, item.Unit, ingredient.Unit)
+		if err != nil {
+			return nil, err
+		}
+		if !quantity.IsPositive() {
+			return nil, fmt.Errorf("quantity of ingredient %s must be greater than zero", ingredient.Name)
+		}
+
+		normalized = append(normalized, models.RecipeItemInput{
+			IngredientID: item.IngredientID,
+			Quantity:     types.FromDecimal(quantity),
+			Unit:         &ingredient.Unit,
+		})
+	}
+
+	return normalized, nil
+}
+
+// GetMenuItemRecipe retrieves the ingredients that go into one unit of a menu
+// item. An empty recipe means the item is stocked as finished goods.
+func (s *RecipeService) GetMenuItemRecipe(menuItemID string) (*types.APIResponse, error) {
+	// Validate menu item ID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, errors.New("invalid menu item ID")
+	}
+
+	if _, err := s.repo.MenuRepo.GetMenuItem(menuItemID); err != nil {
+		return nil, fmt.Errorf("menu item not found: %s", menuItemID)
+	}
+
+	items, err := s.repo.RecipeRepo.GetMenuItemRecipe(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipe: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data: &models.Recipe{
+			MenuItemID: &menuItemID,
+			Items:      items,
+		},
+	}, nil
+}
+
+// SetMenuItemRecipe replaces the recipe of a menu item
+func (s *RecipeService) SetMenuItemRecipe(menuItemID string, recipeData *models.RecipeUpdate) (*types.APIResponse, error) {
+	// Validate menu item ID
+	_, err := uuid.Parse(menuItemID)
+	if err != nil {
+		return nil, errors.New("invalid menu item ID")
+	}
+
+	var items []models.RecipeItem
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		if _, err := tx.MenuRepo.GetMenuItem(menuItemID); err != nil {
+			return fmt.Errorf("menu item not found: %s", menuItemID)
+		}
+
+		recipeItems, err := normalizeRecipeItems(tx, recipeData.Items)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.RecipeRepo.SetMenuItemRecipe(menuItemID, recipeItems); err != nil {
+			return fmt.Errorf("failed to update recipe: %v", err)
+		}
+
+		items, err = tx.RecipeRepo.GetMenuItemRecipe(menuItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get recipe: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Recipe updated successfully",
+		Data: &models.Recipe{
+			MenuItemID: &menuItemID,
+			Items:      items,
+		},
+	}, nil
+}
+
+// GetModifierOptionRecipe retrieves the ingredients a modifier option adds to
+// one unit of the menu item it is chosen for
+func (s *RecipeService) GetModifierOptionRecipe(optionID string) (*types.APIResponse, error) {
+	// Validate modifier option ID
+	_, err := uuid.Parse(optionID)
+	if err != nil {
+		return nil, errors.New("invalid modifier option ID")
+	}
+
+	if _, err := s.repo.ModifierRepo.GetModifierOption(optionID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.RecipeRepo.GetModifierOptionRecipe(optionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipe: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data: &models.Recipe{
+			ModifierOptionID: &optionID,
+			Items:            items,
+		},
+	}, nil
+}
+
+// SetModifierOptionRecipe replaces the recipe of a modifier option
+func (s *RecipeService) SetModifierOptionRecipe(optionID string, recipeData *models.RecipeUpdate) (*types.APIResponse, error) {
+	// Validate modifier option ID
+	_, err := uuid.Parse(optionID)
+	if err != nil {
+		return nil, errors.New("invalid modifier option ID")
+	}
+
+	var items []models.RecipeItem
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		if _, err := tx.ModifierRepo.GetModifierOption(optionID); err != nil {
+			return err
+		}
+
+		recipeItems, err := normalizeRecipeItems(tx, recipeData.Items)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.RecipeRepo.SetModifierOptionRecipe(optionID, recipeItems); err != nil {
+			return fmt.Errorf("failed to update recipe: %v", err)
+		}
+
+		items, err = tx.RecipeRepo.GetModifierOptionRecipe(optionID)
+		if err != nil {
+			return fmt.Errorf("failed to get recipe: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Recipe updated successfully",
+		Data: &models.Recipe{
+			ModifierOptionID: &optionID,
+			Items:            items,
+		},
+	}, nil
+}
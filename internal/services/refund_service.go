@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// RefundService handles refunds and returns of completed orders
+type RefundService struct {
+	repos
+	cache             cache.Cache
+	approvalThreshold decimal.Decimal
+	menuAvailability  *MenuAvailability
+}
+
+// NewRefundService creates a new refund service. Refunds above approvalThreshold
+// must be authorized by a manager.
+func NewRefundService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	cache cache.Cache,
+	approvalThreshold decimal.Decimal,
+	menuAvailability *MenuAvailability,
+) *RefundService {
+	return &RefundService{
+		repos:             repos{repo: repo, uow: uow},
+		cache:             cache,
+		approvalThreshold: approvalThreshold,
+		menuAvailability:  menuAvailability,
+	}
+}
+
+// isManager reports whether the role may authorize refunds
+func isManager(role string) bool {
+	return role == string(types.UserRoleManager) || role == string(types.UserRoleAdmin)
+}
+
+// authorizeRefund returns the ID of the manager approving the refund, if any. A
+// refund above the threshold requested by a cashier needs a manager's credentials.
+func (s *RefundService) authorizeRefund(userRepo repositories.UserRepo, userID, userRole string, amount decimal.Decimal, refundData *models.RefundCreate) (*string, error) {
+	if isManager(userRole) {
+		return &userID, nil
+	}
+
+	if !amount.GreaterThan(s.approvalThreshold) {
+		return nil, nil
+	}
+
+	if refundData.ManagerUsername == nil || refundData.ManagerPassword == nil {
+		return nil, fmt.Errorf("refunds above %s require manager authorization", s.approvalThreshold.StringFixed(2))
+	}
+
+	manager, err := userRepo.GetUserByUsername(*refundData.ManagerUsername)
+	if err != nil || !manager.IsActive || !isManager(string(manager.Role)) ||
+		!utils.CheckPasswordHash(*refundData.ManagerPassword, manager.Password) {
+		return nil, errors.New("invalid manager authorization")
+	}
+
+	return &manager.ID, nil
+}
+
+// CreateRefund refunds some or all of the lines of a completed order. Each line
+// is refunded at its share of what was actually paid, returned items are put
+// back into stock, and the order's payment status becomes partially_refunded
+// or refunded. A cash refund is paid from the refunding cashier's open shift.
+func (s *RefundService) CreateRefund(orderID, userID, userRole string, refundData *models.RefundCreate) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if len(refundData.Items) == 0 {
+		return nil, errors.New("refund must contain at least one item")
+	}
+
+	reason := strings.TrimSpace(refundData.Reason)
+	if reason == "" {
+		return nil, errors.New("a reason is required for a refund")
+	}
+
+	restock := true
+	if refundData.Restock != nil {
+		restock = *refundData.Restock
+	}
+
+	var createdRefund *models.Refund
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// A Z report waits for the refund to commit rather than closing the
+		// period while it is recorded
+		if err := tx.OrderRepo.HoldOffZReports(); err != nil {
+			return fmt.Errorf("failed to hold off Z reports: %v", err)
+		}
+
+		// Lock the order so two refunds cannot return the same items
+		order, err := tx.OrderRepo.GetOrderForUpdate(orderID)
+		if err != nil {
+			return fmt.Errorf("order not found: %v", err)
+		}
+
+		if order.Status != types.OrderStatusCompleted {
+			return errors.New("only completed orders can be refunded")
+		}
+		if order.PaymentStatus != types.PaymentStatusPaid && order.PaymentStatus != types.PaymentStatusPartiallyRefunded {
+			return errors.New("order has already been fully refunded")
+		}
+
+		orderItems, err := tx.OrderItemRepo.GetOrderItemsByOrderID(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order items: %v", err)
+		}
+
+		refunded, err := tx.RefundRepo.GetRefundedQuantities(orderID)
+		if err != nil {
+			return err
+		}
+		// What was returned before this refund, to restock each line's share
+		refundedBefore := make(map[string]int, len(refunded))
+		for orderItemID, quantity := range refunded {
+			refundedBefore[orderItemID] = quantity
+		}
+
+		previousRefunds, err := tx.RefundRepo.ListRefundsByOrderID(orderID)
+		if err != nil {
+			return err
+		}
+		alreadyRefunded := decimal.Zero
+		for _, previous := range previousRefunds {
+			alreadyRefunded = alreadyRefunded.Add(decimal.Decimal(previous.Amount))
+		}
+
+		itemsByID := make(map[string]*models.OrderItem, len(orderItems))
+		for _, orderItem := range orderItems {
+			itemsByID[orderItem.ID] = orderItem
+		}
+
+		total := decimal.Decimal(order.TotalAmount)
+		subtotal := decimal.Decimal(order.SubtotalAmount)
+
+		// Work out each line's refund at its share of what was paid
+		refundItems := make([]*models.RefundItem, 0, len(refundData.Items))
+		amount := decimal.Zero
+		for _, itemData := range refundData.Items {
+			orderItem, ok := itemsByID[itemData.OrderItemID]
+			if !ok {
+				return fmt.Errorf("order item %s does not belong to this order", itemData.OrderItemID)
+			}
+
+			if itemData.Quantity <= 0 {
+				return errors.New("refund quantity must be greater than zero")
+			}
+
+			remaining := orderItem.Quantity - refunded[orderItem.ID]
+			if itemData.Quantity > remaining {
+				return fmt.Errorf("cannot refund %d of order item %s: only %d left to refund", itemData.Quantity, orderItem.ID, remaining)
+			}
+			refunded[orderItem.ID] += itemData.Quantity
+
+			lineValue := decimal.Decimal(orderItem.UnitPrice).Mul(decimal.NewFromInt(int64(itemData.Quantity)))
+			lineAmount := pricing.Prorate(total, lineValue, subtotal)
+
+			refundItems = append(refundItems, &models.RefundItem{
+				ID:          uuid.New().String(),
+				OrderItemID: orderItem.ID,
+				MenuItemID:  orderItem.MenuItemID,
+				Quantity:    itemData.Quantity,
+				Amount:      types.FromDecimal(lineAmount),
+			})
+			amount = amount.Add(lineAmount)
+		}
+
+		// Once every line is returned, refund exactly what is left so that
+		// rounding never leaves a few cents behind or refunds too much
+		fullyRefunded := true
+		for _, orderItem := range orderItems {
+			if refunded[orderItem.ID] < orderItem.Quantity {
+				fullyRefunded = false
+				break
+			}
+		}
+		if fullyRefunded || amount.GreaterThan(total.Sub(alreadyRefunded)) {
+			remainder := total.Sub(alreadyRefunded)
+			last := refundItems[len(refundItems)-1]
+			last.Amount = types.FromDecimal(decimal.Decimal(last.Amount).Add(remainder.Sub(amount)))
+			amount = remainder
+		}
+
+		approvedBy, err := s.authorizeRefund(tx.UserRepo, userID, userRole, amount, refundData)
+		if err != nil {
+			return err
+		}
+
+		// Cash is handed back from the cashier's drawer, so it comes out of
+		// their open shift; other refunds are recorded against it when open
+		var shiftID *string
+		shift, err := tx.ShiftRepo.GetOpenShiftByUser(userID)
+		switch {
+		case err == nil:
+			shiftID = &shift.ID
+		case !errors.Is(err, repositories.ErrNoOpenShift):
+			return fmt.Errorf("failed to get open shift: %v", err)
+		case refundData.PaymentMethod == types.PaymentMethodCash:
+			return errors.New("no open cash drawer shift; open a shift before refunding cash")
+		}
+
+		refund := &models.Refund{
+			ID:            uuid.New().String(),
+			OrderID:       orderID,
+			Amount:        types.FromDecimal(amount),
+			TaxAmount:     types.FromDecimal(pricing.Prorate(decimal.Decimal(order.TaxAmount), amount, total)),
+			PaymentMethod: refundData.PaymentMethod,
+			Reference:     refundData.Reference,
+			Reason:        reason,
+			Restocked:     restock,
+			RefundedBy:    userID,
+			ApprovedBy:    approvedBy,
+			ShiftID:       shiftID,
+		}
+
+		createdRefund, err = tx.RefundRepo.CreateRefund(refund)
+		if err != nil {
+			return fmt.Errorf("failed to create refund: %v", err)
+		}
+
+		for _, refundItem := range refundItems {
+			refundItem.RefundID = createdRefund.ID
+			createdItem, err := tx.RefundRepo.CreateRefundItem(refundItem)
+			if err != nil {
+				return fmt.Errorf("failed to create refund item: %v", err)
+			}
+			createdRefund.Items = append(createdRefund.Items, *createdItem)
+		}
+
+		if restock {
+			// Put back what the returned items took from stock when sold
+			usage, err := refundStockUsage(tx, orderID, refundItems, itemsByID, refundedBefore)
+			if err != nil {
+				return err
+			}
+
+			referenceType := types.ReferenceTypeRefund
+			if err := moveStock(tx, usage, true, stockMovement{
+				userID:        userID,
+				reason:        fmt.Sprintf("Refund on order %s: %s", orderID, reason),
+				referenceType: &referenceType,
+				referenceID:   &createdRefund.ID,
+				changes:       &changes,
+			}); err != nil {
+				return fmt.Errorf("failed to restock refunded items: %v", err)
+			}
+		}
+
+		paymentStatus := types.PaymentStatusPartiallyRefunded
+		if fullyRefunded {
+			paymentStatus = types.PaymentStatusRefunded
+		}
+		if err := tx.OrderRepo.UpdateOrderPaymentStatus(orderID, string(paymentStatus)); err != nil {
+			return fmt.Errorf("failed to update order payment status: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Refunds change net sales, so cached reports are stale
+	s.invalidateReportCaches()
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdRefund,
+	}, nil
+}
+
+// ListRefunds retrieves the refunds made against an order
+func (s *RefundService) ListRefunds(orderID string) (*types.APIResponse, error) {
+	// Validate order ID
+	_, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
+
+	refunds, err := s.repo.RefundRepo.ListRefundsByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    refunds,
+	}, nil
+}
+
+// invalidateReportCaches drops the cached sales reports
+func (s *RefundService) invalidateReportCaches() {
+	if s.cache == nil {
+		return
+	}
+
+	ctx := context.Background()
+	for _, pattern := range []string{"daily_sales_report:*", "top_selling_items:*"} {
+		keys, err := s.cache.Keys(ctx, pattern)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get report cache keys for %s: %v\n", pattern, err)
+			continue
+		}
+		for _, key := range keys {
+			s.cache.Delete(ctx, key)
+		}
+	}
+}
+
+// refundStockUsage works out what returned items put back in stock: their share
+// of the stock each line took when the order was completed, so recipes changed
+// since the sale do not change what goes back. The share is taken of the running
+// total returned, so returning a whole line in parts puts back exactly what it
+// took; refundedBefore, what was returned of each line before, is advanced as
+// it goes. Lines sold before their usage was recorded fall back to their recipes.
+func refundStockUsage(tx *repositories.Repository, orderID string, refundItems []*models.RefundItem, itemsByID map[string]*models.OrderItem, refundedBefore map[string]int) (*stockUsage, error) {
+	recorded, err := tx.OrderItemRepo.ListOrderItemStockUsage(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock used by order: %v", err)
+	}
+
+	usage := &stockUsage{
+		menuItems:   map[string]decimal.Decimal{},
+		ingredients: map[string]decimal.Decimal{},
+	}
+
+	var unrecorded []*models.OrderItem
+	for _, refundItem := range refundItems {
+		orderItem := itemsByID[refundItem.OrderItemID]
+		taken, ok := recorded[orderItem.ID]
+		if !ok {
+			unrecorded = append(unrecorded, &models.OrderItem{
+				ID:         orderItem.ID,
+				MenuItemID: orderItem.MenuItemID,
+				Quantity:   refundItem.Quantity,
+			})
+			continue
+		}
+
+		sold := decimal.NewFromInt(int64(orderItem.Quantity))
+		before := decimal.NewFromInt(int64(refundedBefore[orderItem.ID]))
+		refundedBefore[orderItem.ID] += refundItem.Quantity
+		after := decimal.NewFromInt(int64(refundedBefore[orderItem.ID]))
+
+		for _, item := range taken {
+			quantity := decimal.Decimal(item.Quantity)
+			share := quantity.Mul(after).Div(sold).Round(3).Sub(quantity.Mul(before).Div(sold).Round(3))
+			if !share.IsPositive() {
+				continue
+			}
+			if item.MenuItemID != nil {
+				usage.menuItems[*item.MenuItemID] = usage.menuItems[*item.MenuItemID].Add(share)
+			} else if item.IngredientID != nil {
+				usage.ingredients[*item.IngredientID] = usage.ingredients[*item.IngredientID].Add(share)
+			}
+		}
+	}
+
+	if len(unrecorded) > 0 {
+		lines, err := orderStockLines(tx, orderID, unrecorded)
+		if err != nil {
+			return nil, err
+		}
+
+		fallback, err := stockUsageOf(tx.RecipeRepo, lines)
+		if err != nil {
+			return nil, err
+		}
+		for menuItemID, quantity := range fallback.menuItems {
+			usage.menuItems[menuItemID] = usage.menuItems[menuItemID].Add(quantity)
+		}
+		for ingredientID, quantity := range fallback.ingredients {
+			usage.ingredients[ingredientID] = usage.ingredients[ingredientID].Add(quantity)
+		}
+	}
+
+	return usage, nil
+}
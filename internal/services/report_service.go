@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/cache"
 	"github.com/AndikaPrasetia/pos-cafee/internal/db"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
 	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/shopspring/decimal"
@@ -76,6 +78,7 @@ func (s *ReportService) GetDailySalesReport(dateStr string) (*types.APIResponse,
 				"total_sales":         types.DecimalText(decimal.Zero),
 				"average_order_value": types.DecimalText(decimal.Zero),
 				"top_selling_items":   []map[string]interface{}{},
+				"payment_methods":     []models.PaymentMethodTotal{},
 			}
 
 			// Cache the results for 1 hour (reports typically don't change frequently)
@@ -135,12 +138,19 @@ func (s *ReportService) GetDailySalesReport(dateStr string) (*types.APIResponse,
 		return nil, fmt.Errorf("failed to parse total sales: %v", err)
 	}
 
+	// Break the takings down by tender so the drawer and terminals can be reconciled
+	paymentMethods, err := s.paymentMethodTotals(startOfDay, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+
 	report := map[string]interface{}{
 		"date":                dateStr,
 		"total_orders":        int(reportData.TotalOrders),
 		"total_sales":         types.FromDecimal(totalSales),
 		"average_order_value": averageOrderValue,
 		"top_selling_items":   topItems,
+		"payment_methods":     paymentMethods,
 	}
 
 	// Cache the results for 1 hour (reports typically don't change frequently)
@@ -156,6 +166,32 @@ func (s *ReportService) GetDailySalesReport(dateStr string) (*types.APIResponse,
 	}, nil
 }
 
+// paymentMethodTotals sums the net takings of completed orders per payment method
+func (s *ReportService) paymentMethodTotals(start, end time.Time) ([]models.PaymentMethodTotal, error) {
+	rows, err := s.queries.GetPaymentMethodTotalsByDateRange(context.Background(), db.GetPaymentMethodTotalsByDateRangeParams{
+		Column1: start,
+		Column2: end,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch payment method totals: %v", err)
+	}
+
+	totals := make([]models.PaymentMethodTotal, 0, len(rows))
+	for _, row := range rows {
+		totalAmount, err := decimal.NewFromString(row.TotalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payment method total: %v", err)
+		}
+		totals = append(totals, models.PaymentMethodTotal{
+			PaymentMethod: types.PaymentMethod(row.PaymentMethod),
+			TotalOrders:   int(row.TotalOrders),
+			TotalAmount:   types.FromDecimal(totalAmount),
+		})
+	}
+
+	return totals, nil
+}
+
 // GetFinancialSummaryReport generates a financial summary report for a date range
 func (s *ReportService) GetFinancialSummaryReport(startDateStr, endDateStr string) (*types.APIResponse, error) {
 	startDate, err := time.Parse("2006-01-02", startDateStr)
@@ -190,6 +226,91 @@ func (s *ReportService) GetFinancialSummaryReport(startDateStr, endDateStr strin
 		return nil, fmt.Errorf("failed to parse total sales: %v", err)
 	}
 
+	grossSales, err := decimal.NewFromString(summary.TotalSubtotal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gross sales: %v", err)
+	}
+
+	totalDiscount, err := decimal.NewFromString(summary.TotalDiscount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total discount: %v", err)
+	}
+
+	totalServiceCharge, err := decimal.NewFromString(summary.TotalServiceCharge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total service charge: %v", err)
+	}
+
+	totalTax, err := decimal.NewFromString(summary.TotalTax)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total tax: %v", err)
+	}
+
+	totalRounding, err := decimal.NewFromString(summary.TotalRounding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total rounding: %v", err)
+	}
+
+	// Refunds made in the period reduce sales, including the tax given back
+	refundTotals, err := s.queries.GetRefundTotalsByDateRange(context.Background(), db.GetRefundTotalsByDateRangeParams{
+		Column1: startDate,
+		Column2: endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch refund totals: %v", err)
+	}
+
+	totalRefunds, err := decimal.NewFromString(refundTotals.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total refunds: %v", err)
+	}
+
+	refundTax, err := decimal.NewFromString(refundTotals.TotalTax)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund tax: %v", err)
+	}
+
+	// Tax is collected on behalf of the government, so it is not part of net sales
+	netSales := totalSales.Sub(totalTax).Sub(totalRefunds.Sub(refundTax))
+
+	// The cost of what was sold is what the stock it used cost, net of stock
+	// put back by cancellations and refunds
+	costOfGoodsSold, err := s.queries.GetCostOfGoodsSoldByItemByDateRange(context.Background(), db.GetCostOfGoodsSoldByItemByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch cost of goods sold: %v", err)
+	}
+
+	totalCostOfGoodsSold := decimal.Zero
+	costOfGoodsSoldList := make([]map[string]interface{}, 0)
+	for _, item := range costOfGoodsSold {
+		quantity, err := decimal.NewFromString(item.Quantity)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		cost, err := decimal.NewFromString(item.Cost)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		totalCostOfGoodsSold = totalCostOfGoodsSold.Add(cost)
+
+		costOfGoodsSoldList = append(costOfGoodsSoldList, map[string]interface{}{
+			"item_name": item.ItemName,
+			"item_type": item.ItemType,
+			"unit":      item.Unit.String,
+			"quantity":  types.FromDecimal(quantity),
+			"cost":      types.FromDecimal(cost),
+		})
+	}
+
+	grossMargin := netSales.Sub(totalCostOfGoodsSold)
+	grossMarginPercent := decimal.Zero
+	if !netSales.IsZero() {
+		grossMarginPercent = grossMargin.Div(netSales).Mul(decimal.NewFromInt(100)).Round(2)
+	}
+
 	// Calculate expenses from expense repository
 	expenses, err := s.expenseRepo.GetExpensesByDateRange(startDate, endOfDay)
 	if err != nil {
@@ -213,7 +334,7 @@ func (s *ReportService) GetFinancialSummaryReport(startDateStr, endDateStr strin
 	}
 
 	// Calculate profit
-	totalProfit :=  totalSales.Sub(totalExpenses)
+	totalProfit := netSales.Sub(totalExpenses)
 
 	// Get sales by  category breakdown
 	salesByCategory, err := s.queries.GetSalesByCategoryByDateRange(context.Background(), db.GetSalesByCategoryByDateRangeParams{
@@ -245,10 +366,25 @@ func (s *ReportService) GetFinancialSummaryReport(startDateStr, endDateStr strin
 			"end_date":   endDateStr,
 		},
 		"total_sales":       types.FromDecimal(totalSales),
-		"total_expenses":    types.FromDecimal(totalExpenses),
-		"total_profit":      types.FromDecimal(totalProfit),
-		"sales_by_category": salesByCategoryList,
-		"expenses":          expensesList,
+		"sales_breakdown": map[string]interface{}{
+			"gross_sales":    types.FromDecimal(grossSales),
+			"discount":       types.FromDecimal(totalDiscount),
+			"service_charge": types.FromDecimal(totalServiceCharge),
+			"tax":            types.FromDecimal(totalTax),
+			"rounding":       types.FromDecimal(totalRounding),
+			"refunds":        types.FromDecimal(totalRefunds),
+			"refund_tax":     types.FromDecimal(refundTax),
+			"refund_count":   int(refundTotals.TotalRefunds),
+		},
+		"net_sales":                  types.FromDecimal(netSales),
+		"cost_of_goods_sold":         types.FromDecimal(totalCostOfGoodsSold),
+		"gross_margin":               types.FromDecimal(grossMargin),
+		"gross_margin_percent":       types.FromDecimal(grossMarginPercent),
+		"total_expenses":             types.FromDecimal(totalExpenses),
+		"total_profit":               types.FromDecimal(totalProfit),
+		"sales_by_category":          salesByCategoryList,
+		"cost_of_goods_sold_by_item": costOfGoodsSoldList,
+		"expenses":                   expensesList,
 	}
 
 	return &types.APIResponse{
@@ -317,6 +453,302 @@ func (s *ReportService) GetSalesByCategoryReport(startDateStr, endDateStr string
 	}, nil
 }
 
+// GetSalesByModifierReport generates a report of how often each modifier option
+// was chosen and the revenue it added for a date range
+func (s *ReportService) GetSalesByModifierReport(startDateStr, endDateStr string) (*types.APIResponse, error) {
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		return nil, errors.New("invalid start date format, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		return nil, errors.New("invalid end date format, expected YYYY-MM-DD")
+	}
+
+	if startDate.After(endDate) {
+		return nil, errors.New("start date cannot be after end date")
+	}
+
+	// Calculate end of the end date (23:59:59)
+	endOfDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+
+	salesByModifier, err := s.queries.GetSalesByModifierByDateRange(context.Background(), db.GetSalesByModifierByDateRangeParams{
+		Column1: startDate,
+		Column2: endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch sales by modifier: %v", err)
+	}
+
+	salesByModifierList := make([]map[string]interface{}, 0)
+	for _, modifier := range salesByModifier {
+		totalRevenue, err := decimal.NewFromString(modifier.TotalRevenue)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+
+		salesByModifierList = append(salesByModifierList, map[string]interface{}{
+			"group_name":     modifier.GroupName,
+			"option_name":    modifier.OptionName,
+			"times_selected": int(modifier.TimesSelected),
+			"total_quantity": int(modifier.TotalQuantity),
+			"total_revenue":  types.FromDecimal(totalRevenue),
+		})
+	}
+
+	report := map[string]interface{}{
+		"period": map[string]string{
+			"start_date": startDateStr,
+			"end_date":   endDateStr,
+		},
+		"sales_by_modifier": salesByModifierList,
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
+// GetPrepTimeReport generates a report of how long the kitchen takes to prepare
+// each menu item, per station, for lines sent to the kitchen in a date range
+func (s *ReportService) GetPrepTimeReport(startDateStr, endDateStr string) (*types.APIResponse, error) {
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		return nil, errors.New("invalid start date format, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		return nil, errors.New("invalid end date format, expected YYYY-MM-DD")
+	}
+
+	if startDate.After(endDate) {
+		return nil, errors.New("start date cannot be after end date")
+	}
+
+	// Calculate end of the end date (23:59:59)
+	endOfDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+
+	prepTimes, err := s.queries.GetPrepTimesByDateRange(context.Background(), db.GetPrepTimesByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch prep times: %v", err)
+	}
+
+	prepTimeList := make([]map[string]interface{}, 0)
+	for _, prepTime := range prepTimes {
+		prepTimeList = append(prepTimeList, map[string]interface{}{
+			"station_name":      prepTime.StationName,
+			"menu_item_name":    prepTime.MenuItemName,
+			"tickets":           int(prepTime.Tickets),
+			"total_quantity":    int(prepTime.TotalQuantity),
+			"avg_prep_seconds":  math.Round(prepTime.AvgPrepSeconds),
+			"max_prep_seconds":  math.Round(prepTime.MaxPrepSeconds),
+			"avg_serve_seconds": math.Round(prepTime.AvgServeSeconds),
+		})
+	}
+
+	report := map[string]interface{}{
+		"period": map[string]string{
+			"start_date": startDateStr,
+			"end_date":   endDateStr,
+		},
+		"prep_times": prepTimeList,
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
+// GetStockValuationReport generates a report of what the stock on hand was
+// worth at the end of a date, at the weighted average cost of each item then.
+// With no date it values the stock held today.
+func (s *ReportService) GetStockValuationReport(asOfDateStr string) (*types.APIResponse, error) {
+	asOfDate := time.Now()
+	if asOfDateStr != "" {
+		var err error
+		asOfDate, err = time.Parse("2006-01-02", asOfDateStr)
+		if err != nil {
+			return nil, errors.New("invalid date format, expected YYYY-MM-DD")
+		}
+	} else {
+		asOfDateStr = asOfDate.Format("2006-01-02")
+	}
+
+	// Calculate end of the date (23:59:59)
+	endOfDay := time.Date(asOfDate.Year(), asOfDate.Month(), asOfDate.Day(), 23, 59, 59, 0, asOfDate.Location())
+
+	valuation, err := s.queries.GetStockValuationAsOf(context.Background(), endOfDay)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch stock valuation: %v", err)
+	}
+
+	totalStockValue := decimal.Zero
+	items := make([]map[string]interface{}, 0)
+	for _, item := range valuation {
+		currentStock, err := decimal.NewFromString(item.CurrentStock)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		averageCost, err := decimal.NewFromString(item.AverageCost)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		stockValue, err := decimal.NewFromString(item.StockValue)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		totalStockValue = totalStockValue.Add(stockValue)
+
+		// Each item is either a menu item or an ingredient
+		itemID := item.IngredientID.UUID.String()
+		if item.MenuItemID.Valid {
+			itemID = item.MenuItemID.UUID.String()
+		}
+
+		items = append(items, map[string]interface{}{
+			"item_id":       itemID,
+			"item_name":     item.ItemName,
+			"item_type":     item.ItemType,
+			"unit":          item.Unit.String,
+			"current_stock": types.FromDecimal(currentStock),
+			"average_cost":  types.FromDecimal(averageCost),
+			"stock_value":   types.FromDecimal(stockValue),
+		})
+	}
+
+	report := map[string]interface{}{
+		"as_of_date":        asOfDateStr,
+		"total_stock_value": types.FromDecimal(totalStockValue),
+		"items":             items,
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
+// GetWasteReport generates a report of the cost of stock written off as waste
+// for a date range, by item, by reason and by the staff member who recorded it
+func (s *ReportService) GetWasteReport(startDateStr, endDateStr string) (*types.APIResponse, error) {
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		return nil, errors.New("invalid start date format, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		return nil, errors.New("invalid end date format, expected YYYY-MM-DD")
+	}
+
+	if startDate.After(endDate) {
+		return nil, errors.New("start date cannot be after end date")
+	}
+
+	// Calculate end of the end date (23:59:59)
+	endOfDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+
+	wasteByItem, err := s.queries.GetWasteByItemByDateRange(context.Background(), db.GetWasteByItemByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch waste by item: %v", err)
+	}
+
+	wasteByReason, err := s.queries.GetWasteByReasonByDateRange(context.Background(), db.GetWasteByReasonByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch waste by reason: %v", err)
+	}
+
+	wasteByUser, err := s.queries.GetWasteByUserByDateRange(context.Background(), db.GetWasteByUserByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endOfDay,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch waste by staff: %v", err)
+	}
+
+	wasteByItemList := make([]map[string]interface{}, 0)
+	for _, item := range wasteByItem {
+		totalQuantity, err := decimal.NewFromString(item.TotalQuantity)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		totalCost, err := decimal.NewFromString(item.TotalCost)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+
+		wasteByItemList = append(wasteByItemList, map[string]interface{}{
+			"item_name":      item.ItemName,
+			"item_type":      item.ItemType,
+			"unit":           item.Unit,
+			"records":        int(item.Records),
+			"total_quantity": types.FromDecimal(totalQuantity),
+			"total_cost":     types.FromDecimal(totalCost),
+		})
+	}
+
+	// Every waste record has a reason, so the reasons add up to the total
+	totalWasteCost := decimal.Zero
+	wasteByReasonList := make([]map[string]interface{}, 0)
+	for _, reason := range wasteByReason {
+		totalCost, err := decimal.NewFromString(reason.TotalCost)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+		totalWasteCost = totalWasteCost.Add(totalCost)
+
+		wasteByReasonList = append(wasteByReasonList, map[string]interface{}{
+			"reason":     reason.Reason,
+			"records":    int(reason.Records),
+			"total_cost": types.FromDecimal(totalCost),
+		})
+	}
+
+	wasteByStaffList := make([]map[string]interface{}, 0)
+	for _, user := range wasteByUser {
+		totalCost, err := decimal.NewFromString(user.TotalCost)
+		if err != nil {
+			continue // Skip invalid entries
+		}
+
+		wasteByStaffList = append(wasteByStaffList, map[string]interface{}{
+			"user_id":    user.UserID.String(),
+			"username":   user.Username,
+			"records":    int(user.Records),
+			"total_cost": types.FromDecimal(totalCost),
+		})
+	}
+
+	report := map[string]interface{}{
+		"period": map[string]string{
+			"start_date": startDateStr,
+			"end_date":   endDateStr,
+		},
+		"total_waste_cost": types.FromDecimal(totalWasteCost),
+		"waste_by_item":    wasteByItemList,
+		"waste_by_reason":  wasteByReasonList,
+		"waste_by_staff":   wasteByStaffList,
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
 // GetTopSellingItemsReport generates a report of top selling items for a date range
 func (s *ReportService) GetTopSellingItemsReport(startDateStr, endDateStr string, limit int) (*types.APIResponse, error) {
 	// This would fetch the most sold items by quantity in the given date range
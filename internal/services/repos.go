@@ -0,0 +1,21 @@
+package services
+
+import (
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+)
+
+// repos gives a service the repositories it works with and the unit of work to
+// group their calls in a single database transaction
+type repos struct {
+	repo *repositories.Repository
+	uow  repositories.UnitOfWork
+}
+
+// runInTx runs fn inside a single database transaction. When no unit of work is
+// configured (e.g. in unit tests) fn runs directly against the service's repositories.
+func (r repos) runInTx(fn func(tx *repositories.Repository) error) error {
+	if r.uow == nil {
+		return fn(r.repo)
+	}
+	return r.uow.Do(fn)
+}
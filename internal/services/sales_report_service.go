@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SalesReportService handles X and Z reports. Both cover everything since the
+// previous Z report; an X report can be taken at any time and changes nothing,
+// while a Z report closes the period and is stored under the next number.
+type SalesReportService struct {
+	repos
+	settings receipt.Settings
+}
+
+// NewSalesReportService creates a new sales report service
+func NewSalesReportService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	settings receipt.Settings,
+) *SalesReportService {
+	return &SalesReportService{
+		repos:    repos{repo: repo, uow: uow},
+		settings: settings,
+	}
+}
+
+// summarizeSinceLastZ adds up the sales after the latest Z report up to now,
+// returning the report with the number the next Z report would take
+func summarizeSinceLastZ(salesReportRepo repositories.SalesReportRepo) (*models.SalesReport, int, error) {
+	var periodStart *time.Time
+	nextNumber := 1
+
+	latest, err := salesReportRepo.GetLatestZReport()
+	switch {
+	case err == nil:
+		periodStart = &latest.PeriodEnd
+		nextNumber = *latest.ReportNumber + 1
+	case !errors.Is(err, repositories.ErrNoZReport):
+		return nil, 0, fmt.Errorf("failed to get latest Z report: %v", err)
+	}
+
+	// The period ends on the database's clock, which sales, voids and refunds
+	// are stamped with, so an application server's clock running behind cannot
+	// leave a committed change out of the period
+	periodEnd, err := salesReportRepo.GetDatabaseTime()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get period end: %v", err)
+	}
+
+	report, err := salesReportRepo.SummarizeSales(periodStart, periodEnd)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to summarize sales: %v", err)
+	}
+
+	// Net sales match the financial summary: total sales less tax, less refunds
+	// net of the tax they gave back. Sales voided in the period that an earlier
+	// period counted are taken off too; this period's voided sales were never counted.
+	refunds := decimal.Decimal(report.RefundAmount).Sub(decimal.Decimal(report.RefundTax))
+	netSales := decimal.Decimal(report.TotalSales).Sub(decimal.Decimal(report.TaxAmount)).Sub(refunds).Sub(decimal.Decimal(report.EarlierVoidedNet))
+	report.NetSales = types.FromDecimal(netSales)
+
+	if report.PaymentMethods == nil {
+		report.PaymentMethods = []models.PaymentMethodTotal{}
+	}
+	if report.Cashiers == nil {
+		report.Cashiers = []models.CashierTotal{}
+	}
+
+	return report, nextNumber, nil
+}
+
+// xReport works out the running totals since the latest Z report
+func (s *SalesReportService) xReport(userID string) (*models.SalesReport, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	report, _, err := summarizeSinceLastZ(s.repo.SalesReportRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ReportType = types.SalesReportTypeX
+	report.GeneratedBy = userID
+	report.GeneratedAt = report.PeriodEnd
+	return report, nil
+}
+
+// GetXReport retrieves the running totals since the latest Z report without
+// storing anything
+func (s *SalesReportService) GetXReport(userID string) (*types.APIResponse, error) {
+	report, err := s.xReport(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
+// PrintXReport renders the running totals since the latest Z report for printing
+func (s *SalesReportService) PrintXReport(userID string, format receipt.Format) (*receipt.Document, error) {
+	report, err := s.xReport(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.RenderSalesReport(report, s.settings, format)
+}
+
+// GenerateZReport closes the period since the latest Z report, storing its
+// totals under the next report number. Z reports cannot be changed afterwards.
+func (s *SalesReportService) GenerateZReport(userID string) (*types.APIResponse, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	var created *models.SalesReport
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Serialize Z reports so two cannot take the same number or overlap
+		if err := tx.SalesReportRepo.LockZReports(); err != nil {
+			return err
+		}
+
+		report, nextNumber, err := summarizeSinceLastZ(tx.SalesReportRepo)
+		if err != nil {
+			return err
+		}
+
+		report.ReportType = types.SalesReportTypeZ
+		report.ReportNumber = &nextNumber
+		report.GeneratedBy = userID
+
+		created, err = tx.SalesReportRepo.CreateZReport(report)
+		if err != nil {
+			return fmt.Errorf("failed to store Z report: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Z report #%d generated successfully", *created.ReportNumber),
+		Data:    created,
+	}, nil
+}
+
+// GetZReport retrieves a stored Z report
+func (s *SalesReportService) GetZReport(id string) (*types.APIResponse, error) {
+	// Validate report ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid Z report ID")
+	}
+
+	report, err := s.repo.SalesReportRepo.GetZReport(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    report,
+	}, nil
+}
+
+// PrintZReport renders a stored Z report for printing, e.g. to reprint it later
+func (s *SalesReportService) PrintZReport(id string, format receipt.Format) (*receipt.Document, error) {
+	// Validate report ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid Z report ID")
+	}
+
+	report, err := s.repo.SalesReportRepo.GetZReport(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.RenderSalesReport(report, s.settings, format)
+}
+
+// ListZReports retrieves stored Z reports, newest first
+func (s *SalesReportService) ListZReports(limit, offset int) (*types.APIResponse, error) {
+	reports, err := s.repo.SalesReportRepo.ListZReports(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Z reports: %v", err)
+	}
+	if reports == nil {
+		reports = []*models.SalesReport{}
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    reports,
+	}, nil
+}
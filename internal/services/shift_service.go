@@ -0,0 +1,355 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ShiftService handles cash drawer shifts: the opening float, cash drops and
+// payouts taken from the drawer, and the counted close-out
+type ShiftService struct {
+	repos
+}
+
+// NewShiftService creates a new shift service
+func NewShiftService(repo *repositories.Repository, uow repositories.UnitOfWork) *ShiftService {
+	return &ShiftService{
+		repos: repos{repo: repo, uow: uow},
+	}
+}
+
+// canManageShift reports whether the user may record movements on or close the
+// shift: the cashier who opened it, or a manager
+func canManageShift(shift *models.CashShift, userID, userRole string) bool {
+	return shift.UserID == userID || isManager(userRole)
+}
+
+// summarizeShift works out the cash a shift should hold from its float, the
+// cash kept from the orders completed in it and the refunds, voids, drops and
+// payouts taken out
+func summarizeShift(shiftRepo repositories.ShiftRepo, shift *models.CashShift) (*models.CashShiftSummary, error) {
+	cashOrders, cashSales, err := shiftRepo.GetCashSales(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash sales: %v", err)
+	}
+
+	cashRefunds, err := shiftRepo.GetCashRefunds(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash refunds: %v", err)
+	}
+
+	cashVoids, err := shiftRepo.GetCashVoids(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash voids: %v", err)
+	}
+
+	movements, err := shiftRepo.ListMovements(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash movements: %v", err)
+	}
+
+	drops, payouts := decimal.Zero, decimal.Zero
+	summaryMovements := make([]models.CashMovement, len(movements))
+	for i, movement := range movements {
+		switch movement.MovementType {
+		case types.CashMovementDrop:
+			drops = drops.Add(decimal.Decimal(movement.Amount))
+		case types.CashMovementPayout:
+			payouts = payouts.Add(decimal.Decimal(movement.Amount))
+		}
+		summaryMovements[i] = *movement
+	}
+
+	counts, err := shiftRepo.ListCounts(shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash counts: %v", err)
+	}
+	if counts == nil {
+		counts = []models.CashCount{}
+	}
+
+	expected := decimal.Decimal(shift.OpeningFloat).Add(decimal.Decimal(cashSales)).Sub(decimal.Decimal(cashRefunds)).Sub(decimal.Decimal(cashVoids)).Sub(drops).Sub(payouts)
+
+	return &models.CashShiftSummary{
+		CashShift:   *shift,
+		CashOrders:  cashOrders,
+		CashSales:   cashSales,
+		CashRefunds: cashRefunds,
+		CashVoids:   cashVoids,
+		CashDrops:   types.FromDecimal(drops),
+		CashPayouts: types.FromDecimal(payouts),
+		CurrentCash: types.FromDecimal(expected),
+		Movements:   summaryMovements,
+		Counts:      counts,
+	}, nil
+}
+
+// OpenShift opens a cash drawer shift for the cashier with the float counted
+// into the drawer. A cashier can only have one shift open at a time.
+func (s *ShiftService) OpenShift(userID string, shiftData *models.CashShiftOpen) (*types.APIResponse, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if decimal.Decimal(shiftData.OpeningFloat).IsNegative() {
+		return nil, errors.New("opening float cannot be negative")
+	}
+
+	var shift *models.CashShift
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		_, err := tx.ShiftRepo.GetOpenShiftByUser(userID)
+		if err == nil {
+			return errors.New("you already have an open cash drawer shift; close it before opening another")
+		}
+		if !errors.Is(err, repositories.ErrNoOpenShift) {
+			return fmt.Errorf("failed to check for an open shift: %v", err)
+		}
+
+		shift, err = tx.ShiftRepo.CreateShift(&models.CashShift{
+			UserID:       userID,
+			OpeningFloat: shiftData.OpeningFloat,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open shift: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Shift opened successfully",
+		Data:    shift,
+	}, nil
+}
+
+// GetCurrentShift retrieves the cashier's open shift with the cash it should
+// hold right now
+func (s *ShiftService) GetCurrentShift(userID string) (*types.APIResponse, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	shift, err := s.repo.ShiftRepo.GetOpenShiftByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := summarizeShift(s.repo.ShiftRepo, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    summary,
+	}, nil
+}
+
+// GetShift retrieves a shift with its movements, counts and cash totals.
+// Cashiers can only see their own shifts.
+func (s *ShiftService) GetShift(shiftID, userID, userRole string) (*types.APIResponse, error) {
+	// Validate shift ID
+	_, err := uuid.Parse(shiftID)
+	if err != nil {
+		return nil, errors.New("invalid shift ID")
+	}
+
+	shift, err := s.repo.ShiftRepo.GetShift(shiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManageShift(shift, userID, userRole) {
+		return nil, errors.New("cash shift not found")
+	}
+
+	summary, err := summarizeShift(s.repo.ShiftRepo, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    summary,
+	}, nil
+}
+
+// ListShifts retrieves shifts based on filter criteria, newest first
+func (s *ShiftService) ListShifts(filter models.CashShiftFilter) (*types.APIResponse, error) {
+	shifts, err := s.repo.ShiftRepo.ListShifts(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %v", err)
+	}
+	if shifts == nil {
+		shifts = []*models.CashShift{}
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    shifts,
+	}, nil
+}
+
+// AddMovement records cash dropped to the safe or paid out of the drawer
+// during an open shift
+func (s *ShiftService) AddMovement(shiftID, userID, userRole string, movementData *models.CashMovementCreate) (*types.APIResponse, error) {
+	// Validate shift ID
+	_, err := uuid.Parse(shiftID)
+	if err != nil {
+		return nil, errors.New("invalid shift ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if !decimal.Decimal(movementData.Amount).IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	var movement *models.CashMovement
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the shift so it cannot be closed while the movement is recorded
+		shift, err := tx.ShiftRepo.GetShiftForUpdate(shiftID)
+		if err != nil {
+			return err
+		}
+
+		if !canManageShift(shift, userID, userRole) {
+			return errors.New("only the cashier who opened the shift or a manager can record cash movements")
+		}
+		if shift.Status != types.ShiftStatusOpen {
+			return errors.New("cash movements can only be recorded on an open shift")
+		}
+
+		movement, err = tx.ShiftRepo.CreateMovement(&models.CashMovement{
+			ShiftID:      shiftID,
+			MovementType: movementData.MovementType,
+			Amount:       movementData.Amount,
+			Reason:       movementData.Reason,
+			CreatedBy:    userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record cash movement: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Cash movement recorded successfully",
+		Data:    movement,
+	}, nil
+}
+
+// CloseShift closes an open shift with the cash counted in the drawer, storing
+// the expected cash and the variance between the two
+func (s *ShiftService) CloseShift(shiftID, userID, userRole string, closeData *models.CashShiftClose) (*types.APIResponse, error) {
+	// Validate shift ID
+	_, err := uuid.Parse(shiftID)
+	if err != nil {
+		return nil, errors.New("invalid shift ID")
+	}
+
+	// Validate user ID
+	_, err = uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	// Add up the counted notes and coins
+	counted := decimal.Zero
+	seen := make(map[string]bool, len(closeData.Counts))
+	for _, count := range closeData.Counts {
+		denomination := decimal.Decimal(count.Denomination)
+		if !denomination.IsPositive() {
+			return nil, errors.New("denomination must be greater than zero")
+		}
+		if count.Quantity < 0 {
+			return nil, errors.New("quantity cannot be negative")
+		}
+		if seen[denomination.String()] {
+			return nil, fmt.Errorf("denomination %s is counted more than once", denomination.String())
+		}
+		seen[denomination.String()] = true
+		counted = counted.Add(denomination.Mul(decimal.NewFromInt(int64(count.Quantity))))
+	}
+
+	var summary *models.CashShiftSummary
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		// Lock the shift so no movement or second close-out races this one
+		shift, err := tx.ShiftRepo.GetShiftForUpdate(shiftID)
+		if err != nil {
+			return err
+		}
+
+		if !canManageShift(shift, userID, userRole) {
+			return errors.New("only the cashier who opened the shift or a manager can close it")
+		}
+		if shift.Status != types.ShiftStatusOpen {
+			return errors.New("shift is already closed")
+		}
+
+		current, err := summarizeShift(tx.ShiftRepo, shift)
+		if err != nil {
+			return err
+		}
+
+		expectedCash := current.CurrentCash
+		countedCash := types.FromDecimal(counted)
+		variance := types.FromDecimal(counted.Sub(decimal.Decimal(expectedCash)))
+		shift.ExpectedCash = &expectedCash
+		shift.CountedCash = &countedCash
+		shift.Variance = &variance
+		shift.Notes = closeData.Notes
+		shift.ClosedBy = &userID
+
+		closed, err := tx.ShiftRepo.CloseShift(shift)
+		if err != nil {
+			if errors.Is(err, repositories.ErrShiftNotOpen) {
+				return errors.New("shift is already closed")
+			}
+			return fmt.Errorf("failed to close shift: %v", err)
+		}
+
+		for _, count := range closeData.Counts {
+			if err := tx.ShiftRepo.CreateCount(shiftID, count); err != nil {
+				return fmt.Errorf("failed to record cash count: %v", err)
+			}
+		}
+
+		current.CashShift = *closed
+		current.Counts = closeData.Counts
+		summary = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Shift closed successfully",
+		Data:    summary,
+	}, nil
+}
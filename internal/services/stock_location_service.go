@@ -0,0 +1,429 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// StockLocationService handles the places stock is kept in, the stock held at
+// each and transfers of stock between them
+type StockLocationService struct {
+	repos
+	menuAvailability *MenuAvailability
+}
+
+// NewStockLocationService creates a new stock location service
+func NewStockLocationService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	menuAvailability *MenuAvailability,
+) *StockLocationService {
+	return &StockLocationService{
+		repos:            repos{repo: repo, uow: uow},
+		menuAvailability: menuAvailability,
+	}
+}
+
+// CreateStockLocation adds a place to keep stock in. A new selling location
+// takes over selling from the current one.
+func (s *StockLocationService) CreateStockLocation(locationData *models.StockLocationCreate) (*types.APIResponse, error) {
+	code := strings.ToUpper(strings.TrimSpace(locationData.Code))
+	name := strings.TrimSpace(locationData.Name)
+	if code == "" || name == "" {
+		return nil, errors.New("stock location code and name are required")
+	}
+
+	var location *models.StockLocation
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		if locationData.IsSelling {
+			if err := tx.StockLocationRepo.ClearSellingStockLocation(uuid.Nil.String()); err != nil {
+				return fmt.Errorf("failed to clear selling location: %v", err)
+			}
+		}
+
+		var err error
+		location, err = tx.StockLocationRepo.CreateStockLocation(&models.StockLocation{
+			Code:        code,
+			Name:        name,
+			Description: locationData.Description,
+			IsSelling:   locationData.IsSelling,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stock location: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock location created successfully",
+		Data:    location,
+	}, nil
+}
+
+// UpdateStockLocation updates a stock location. Making a location the selling
+// location stops selling from the one before; the selling location itself
+// cannot stop selling or be deactivated, and a location still holding stock
+// cannot be deactivated.
+func (s *StockLocationService) UpdateStockLocation(id string, locationData *models.StockLocationUpdate) (*types.APIResponse, error) {
+	// Validate stock location ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock location ID")
+	}
+
+	var location *models.StockLocation
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		existing, err := tx.StockLocationRepo.GetStockLocation(id)
+		if err != nil {
+			return err
+		}
+
+		if locationData.Name != nil {
+			existing.Name = strings.TrimSpace(*locationData.Name)
+		}
+		if locationData.Description != nil {
+			existing.Description = locationData.Description
+		}
+		if locationData.IsSelling != nil {
+			if !*locationData.IsSelling && existing.IsSelling {
+				return errors.New("make another location the selling location instead")
+			}
+			if *locationData.IsSelling && !existing.IsSelling {
+				if err := tx.StockLocationRepo.ClearSellingStockLocation(id); err != nil {
+					return fmt.Errorf("failed to clear selling location: %v", err)
+				}
+				existing.IsSelling = true
+			}
+		}
+		if locationData.IsActive != nil {
+			if !*locationData.IsActive && existing.IsActive {
+				if existing.IsSelling {
+					return errors.New("the selling location cannot be deactivated")
+				}
+				balances, err := tx.StockLocationRepo.ListStockBalances(id)
+				if err != nil {
+					return fmt.Errorf("failed to get stock balances: %v", err)
+				}
+				if len(balances) > 0 {
+					return fmt.Errorf("%s still holds stock; transfer it out first", existing.Name)
+				}
+			}
+			existing.IsActive = *locationData.IsActive
+		}
+		if existing.IsSelling && !existing.IsActive {
+			return errors.New("an inactive location cannot be the selling location")
+		}
+
+		location, err = tx.StockLocationRepo.UpdateStockLocation(existing)
+		if err != nil {
+			return fmt.Errorf("failed to update stock location: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock location updated successfully",
+		Data:    location,
+	}, nil
+}
+
+// ListStockLocations retrieves the stock locations, the selling location first
+func (s *StockLocationService) ListStockLocations(includeInactive bool) (*types.APIResponse, error) {
+	locations, err := s.repo.StockLocationRepo.ListStockLocations(includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock locations: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    locations,
+	}, nil
+}
+
+// ListStockBalances retrieves the stock held at a location
+func (s *StockLocationService) ListStockBalances(id string) (*types.APIResponse, error) {
+	// Validate stock location ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock location ID")
+	}
+
+	location, err := s.repo.StockLocationRepo.GetStockLocation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.repo.StockLocationRepo.ListStockBalances(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock balances: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"location": location,
+			"balances": balances,
+		},
+	}, nil
+}
+
+// transferredLine is a transfer line resolved to an item and a quantity in
+// its stock unit
+type transferredLine struct {
+	menuItemID   *string
+	ingredientID *string
+	itemName     string
+	unit         string
+	quantity     decimal.Decimal
+}
+
+// itemID returns the ID of the menu item or ingredient the line moves
+func (l transferredLine) itemID() string {
+	if l.ingredientID != nil {
+		return *l.ingredientID
+	}
+	return *l.menuItemID
+}
+
+// transferredLines resolves the items of a transfer, in a fixed order so that
+// concurrent stock movements lock the same stock rows in the same order
+func transferredLines(tx *repositories.Repository, items []models.StockTransferItemInput) ([]transferredLine, error) {
+	lines := make([]transferredLine, 0, len(items))
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if (item.MenuItemID == nil) == (item.IngredientID == nil) {
+			return nil, errors.New("each transfer item must name either a menu item or an ingredient")
+		}
+
+		var line transferredLine
+		if item.IngredientID != nil {
+			ingredient, err := tx.IngredientRepo.GetIngredient(*item.IngredientID)
+			if err != nil {
+				return nil, fmt.Errorf("ingredient not found: %s", *item.IngredientID)
+			}
+			line = transferredLine{ingredientID: &ingredient.ID, itemName: ingredient.Name, unit: ingredient.Unit}
+		} else {
+			inventory, err := getOrCreateInventory(tx.InventoryRepo, *item.MenuItemID)
+			if err != nil {
+				return nil, err
+			}
+			line = transferredLine{menuItemID: &inventory.MenuItemID, itemName: inventory.MenuItemName, unit: inventory.Unit}
+		}
+
+		if seen[line.itemID()] {
+			return nil, fmt.Errorf("%s is listed more than once", line.itemName)
+		}
+		seen[line.itemID()] = true
+
+		quantity, err := toStockUnit(tx.UnitRepo, item.Quantity, item.Unit, line.unit)
+		if err != nil {
+			return nil, err
+		}
+		if !quantity.IsPositive() {
+			return nil, fmt.Errorf("quantity of %s to transfer must be greater than zero", line.itemName)
+		}
+		line.quantity = quantity
+
+		lines = append(lines, line)
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].itemID() < lines[j].itemID()
+	})
+	return lines, nil
+}
+
+// postTransferLine moves the stock of one line as an `out` stock transaction
+// at the location it leaves and an `in` one at the location it arrives at.
+// The item's stock across all locations ends where it started.
+func postTransferLine(tx *repositories.Repository, transfer *models.StockTransfer, line transferredLine, userID string, changes *availabilityChanges) error {
+	stock, err := tx.StockLocationRepo.LockItemStock(line.menuItemID, line.ingredientID)
+	if err != nil {
+		return fmt.Errorf("failed to lock stock of %s: %v", line.itemName, err)
+	}
+	inTransit := types.FromDecimal(decimal.Decimal(stock).Sub(line.quantity))
+
+	referenceType := types.ReferenceTypeStockTransfer
+	movements := []*models.StockTransaction{
+		{
+			TransactionType: types.TransactionTypeOut,
+			Quantity:        types.FromDecimal(line.quantity.Neg()),
+			PreviousStock:   stock,
+			CurrentStock:    inTransit,
+			Reason:          fmt.Sprintf("Transfer %s to %s", transfer.TransferNumber, transfer.ToLocationName),
+			LocationID:      &transfer.FromLocationID,
+		},
+		{
+			TransactionType: types.TransactionTypeIn,
+			Quantity:        types.FromDecimal(line.quantity),
+			PreviousStock:   inTransit,
+			CurrentStock:    stock,
+			Reason:          fmt.Sprintf("Transfer %s from %s", transfer.TransferNumber, transfer.FromLocationName),
+			LocationID:      &transfer.ToLocationID,
+		},
+	}
+	for _, movement := range movements {
+		movement.ID = uuid.New().String()
+		movement.IngredientID = line.ingredientID
+		if line.menuItemID != nil {
+			movement.MenuItemID = *line.menuItemID
+		}
+		movement.ReferenceType = &referenceType
+		movement.ReferenceID = &transfer.ID
+		movement.UserID = &userID
+
+		createdTransaction, err := recordStockTransaction(tx, movement)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInsufficientLocationStock) {
+				return fmt.Errorf("insufficient stock of %s at %s: %s %s requested", line.itemName, transfer.FromLocationName, line.quantity, line.unit)
+			}
+			return fmt.Errorf("failed to create stock transaction for %s: %v", line.itemName, err)
+		}
+		changes.add(createdTransaction)
+	}
+
+	if err := tx.StockLocationRepo.CreateStockTransferLine(&models.StockTransferLine{
+		StockTransferID: transfer.ID,
+		MenuItemID:      line.menuItemID,
+		IngredientID:    line.ingredientID,
+		Unit:            line.unit,
+		Quantity:        types.FromDecimal(line.quantity),
+	}); err != nil {
+		return fmt.Errorf("failed to record transfer of %s: %v", line.itemName, err)
+	}
+
+	return nil
+}
+
+// loadStockTransfer retrieves a stock transfer with its lines
+func loadStockTransfer(stockLocationRepo repositories.StockLocationRepo, id string) (*models.StockTransferSummary, error) {
+	transfer, err := stockLocationRepo.GetStockTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := stockLocationRepo.ListStockTransferLines(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock transfer lines: %v", err)
+	}
+
+	return &models.StockTransferSummary{
+		StockTransfer: *transfer,
+		Lines:         lines,
+	}, nil
+}
+
+// CreateStockTransfer moves stock from one location to another. The whole
+// transfer is posted at once, or not at all when the location it leaves does
+// not hold enough of any item.
+func (s *StockLocationService) CreateStockTransfer(userID string, transferData *models.StockTransferCreate) (*types.APIResponse, error) {
+	if transferData.FromLocationID == transferData.ToLocationID {
+		return nil, errors.New("stock must be transferred to a different location")
+	}
+
+	var summary *models.StockTransferSummary
+	var changes availabilityChanges
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		from, err := tx.StockLocationRepo.GetStockLocation(transferData.FromLocationID)
+		if err != nil {
+			return err
+		}
+		to, err := tx.StockLocationRepo.GetStockLocation(transferData.ToLocationID)
+		if err != nil {
+			return err
+		}
+		if !to.IsActive {
+			return fmt.Errorf("%s is not active", to.Name)
+		}
+
+		lines, err := transferredLines(tx, transferData.Items)
+		if err != nil {
+			return err
+		}
+
+		transfer, err := tx.StockLocationRepo.CreateStockTransfer(&models.StockTransfer{
+			FromLocationID:   from.ID,
+			FromLocationName: from.Name,
+			ToLocationID:     to.ID,
+			ToLocationName:   to.Name,
+			Notes:            transferData.Notes,
+			CreatedBy:        userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stock transfer: %v", err)
+		}
+
+		for _, line := range lines {
+			if err := postTransferLine(tx, transfer, line, userID, &changes); err != nil {
+				return err
+			}
+		}
+
+		summary, err = loadStockTransfer(tx.StockLocationRepo, transfer.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock transferred successfully",
+		Data:    summary,
+	}, nil
+}
+
+// GetStockTransfer retrieves a stock transfer with its lines
+func (s *StockLocationService) GetStockTransfer(id string) (*types.APIResponse, error) {
+	// Validate stock transfer ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock transfer ID")
+	}
+
+	summary, err := loadStockTransfer(s.repo.StockLocationRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    summary,
+	}, nil
+}
+
+// ListStockTransfers retrieves stock transfers, newest first
+func (s *StockLocationService) ListStockTransfers(filter models.StockTransferFilter) (*types.APIResponse, error) {
+	if filter.LocationID != nil {
+		if _, err := uuid.Parse(*filter.LocationID); err != nil {
+			return nil, errors.New("invalid stock location ID")
+		}
+	}
+
+	transfers, err := s.repo.StockLocationRepo.ListStockTransfers(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock transfers: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    transfers,
+	}, nil
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// StockTakeService handles physical stock counts and reconciling them with
+// the stock the system holds
+type StockTakeService struct {
+	repos
+	menuAvailability *MenuAvailability
+}
+
+// NewStockTakeService creates a new stock take service
+func NewStockTakeService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	menuAvailability *MenuAvailability,
+) *StockTakeService {
+	return &StockTakeService{
+		repos:            repos{repo: repo, uow: uow},
+		menuAvailability: menuAvailability,
+	}
+}
+
+// loadStockTake retrieves a stock take with its lines and totals the
+// variances counted so far
+func loadStockTake(stockTakeRepo repositories.StockTakeRepo, id string) (*models.StockTakeSummary, error) {
+	stockTake, err := stockTakeRepo.GetStockTake(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := stockTakeRepo.ListStockTakeLines(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock take lines: %v", err)
+	}
+
+	summary := &models.StockTakeSummary{
+		StockTake:  *stockTake,
+		TotalLines: len(lines),
+		Lines:      lines,
+	}
+	varianceValue := decimal.Zero
+	for _, line := range lines {
+		if line.CountedQuantity == nil {
+			continue
+		}
+		summary.CountedLines++
+		varianceValue = varianceValue.Add(decimal.Decimal(*line.VarianceValue))
+	}
+	summary.VarianceValue = types.FromDecimal(varianceValue)
+
+	return summary, nil
+}
+
+// CreateStockTake starts a stock take, recording the stock the system holds of
+// every item to be counted at this moment
+func (s *StockTakeService) CreateStockTake(userID string, stockTakeData *models.StockTakeCreate) (*types.APIResponse, error) {
+	includeMenuItems := true
+	if stockTakeData.IncludeMenuItems != nil {
+		includeMenuItems = *stockTakeData.IncludeMenuItems
+	}
+	// Only menu items have a category, so a cycle count of one category
+	// leaves ingredients out unless asked for
+	includeIngredients := stockTakeData.CategoryID == nil
+	if stockTakeData.IncludeIngredients != nil {
+		includeIngredients = *stockTakeData.IncludeIngredients
+	}
+	if !includeMenuItems && !includeIngredients {
+		return nil, errors.New("a stock take must include menu items, ingredients or both")
+	}
+
+	var summary *models.StockTakeSummary
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		if stockTakeData.CategoryID != nil {
+			if _, err := tx.MenuRepo.GetCategory(*stockTakeData.CategoryID); err != nil {
+				return fmt.Errorf("category not found: %s", *stockTakeData.CategoryID)
+			}
+		}
+
+		stockTake, err := tx.StockTakeRepo.CreateStockTake(&models.StockTake{
+			CategoryID:         stockTakeData.CategoryID,
+			IncludeMenuItems:   includeMenuItems,
+			IncludeIngredients: includeIngredients,
+			Notes:              stockTakeData.Notes,
+			CreatedBy:          userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stock take: %v", err)
+		}
+
+		if includeMenuItems {
+			if err := tx.StockTakeRepo.SnapshotStockTakeMenuItems(stockTake.ID, stockTakeData.CategoryID); err != nil {
+				return fmt.Errorf("failed to record menu item stock: %v", err)
+			}
+		}
+		if includeIngredients {
+			if err := tx.StockTakeRepo.SnapshotStockTakeIngredients(stockTake.ID); err != nil {
+				return fmt.Errorf("failed to record ingredient stock: %v", err)
+			}
+		}
+
+		summary, err = loadStockTake(tx.StockTakeRepo, stockTake.ID)
+		if err != nil {
+			return err
+		}
+		if summary.TotalLines == 0 {
+			return errors.New("there is no stock to count")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock take started successfully",
+		Data:    summary,
+	}, nil
+}
+
+// GetStockTake retrieves a stock take with its lines and variances
+func (s *StockTakeService) GetStockTake(id string) (*types.APIResponse, error) {
+	// Validate stock take ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock take ID")
+	}
+
+	summary, err := loadStockTake(s.repo.StockTakeRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    summary,
+	}, nil
+}
+
+// ListStockTakes retrieves stock takes with optional filtering
+func (s *StockTakeService) ListStockTakes(filter models.StockTakeFilter) (*types.APIResponse, error) {
+	stockTakes, err := s.repo.StockTakeRepo.ListStockTakes(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock takes: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    stockTakes,
+	}, nil
+}
+
+// countedLine finds the line of a stock take for the item a count names and
+// converts the quantity counted into the line's stock unit
+func countedLine(tx *repositories.Repository, lines []models.StockTakeLine, menuItemID, ingredientID *string, quantity types.DecimalText, unit *string) (models.StockTakeLine, decimal.Decimal, error) {
+	if (menuItemID == nil) == (ingredientID == nil) {
+		return models.StockTakeLine{}, decimal.Zero, errors.New("each count must name either a menu item or an ingredient")
+	}
+
+	for _, line := range lines {
+		if (menuItemID != nil && line.MenuItemID != nil && *line.MenuItemID == *menuItemID) ||
+			(ingredientID != nil && line.IngredientID != nil && *line.IngredientID == *ingredientID) {
+			stockQuantity, err := toStockUnit(tx.UnitRepo, quantity, unit, line.Unit)
+			if err != nil {
+				return models.StockTakeLine{}, decimal.Zero, err
+			}
+			return line, stockQuantity, nil
+		}
+	}
+
+	if menuItemID != nil {
+		return models.StockTakeLine{}, decimal.Zero, fmt.Errorf("menu item %s is not part of this stock take", *menuItemID)
+	}
+	return models.StockTakeLine{}, decimal.Zero, fmt.Errorf("ingredient %s is not part of this stock take", *ingredientID)
+}
+
+// recordCounts runs fn against the lines of an open stock take, locking it so
+// that counts cannot change while it is being approved
+func (s *StockTakeService) recordCounts(id string, fn func(tx *repositories.Repository, lines []models.StockTakeLine) error) (*models.StockTakeSummary, error) {
+	// Validate stock take ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock take ID")
+	}
+
+	var summary *models.StockTakeSummary
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		stockTake, err := tx.StockTakeRepo.GetStockTakeForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stockTake.Status != types.StockTakeStatusOpen {
+			return fmt.Errorf("cannot record counts: stock take is %s", stockTake.Status)
+		}
+
+		lines, err := tx.StockTakeRepo.ListStockTakeLines(id)
+		if err != nil {
+			return fmt.Errorf("failed to get stock take lines: %v", err)
+		}
+
+		if err := fn(tx, lines); err != nil {
+			return err
+		}
+
+		summary, err = loadStockTake(tx.StockTakeRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// RecordCounts enters counted quantities in bulk. Each replaces what was
+// counted for the item before, so a recount simply overwrites it.
+func (s *StockTakeService) RecordCounts(id, userID string, countData *models.StockTakeCounts) (*types.APIResponse, error) {
+	summary, err := s.recordCounts(id, func(tx *repositories.Repository, lines []models.StockTakeLine) error {
+		for _, count := range countData.Counts {
+			// A missing quantity is not a count of zero, which would empty the stock
+			if count.Quantity == nil {
+				return errors.New("counted quantity is required")
+			}
+
+			line, quantity, err := countedLine(tx, lines, count.MenuItemID, count.IngredientID, *count.Quantity, count.Unit)
+			if err != nil {
+				return err
+			}
+			if quantity.IsNegative() {
+				return fmt.Errorf("counted quantity of %s must not be negative", line.ItemName)
+			}
+
+			if err := tx.StockTakeRepo.SetStockTakeLineCount(line.ID, types.FromDecimal(quantity), userID); err != nil {
+				return fmt.Errorf("failed to record count of %s: %v", line.ItemName, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Counts recorded successfully",
+		Data:    summary,
+	}, nil
+}
+
+// RecordScan adds one scan of an item's label to what was counted for it.
+// Each scan counts one stock unit unless a quantity is given.
+func (s *StockTakeService) RecordScan(id, userID string, scanData *models.StockTakeScan) (*types.APIResponse, error) {
+	scanned := types.FromDecimal(decimal.NewFromInt(1))
+	if scanData.Quantity != nil {
+		scanned = *scanData.Quantity
+	}
+
+	summary, err := s.recordCounts(id, func(tx *repositories.Repository, lines []models.StockTakeLine) error {
+		line, quantity, err := countedLine(tx, lines, scanData.MenuItemID, scanData.IngredientID, scanned, scanData.Unit)
+		if err != nil {
+			return err
+		}
+		if !quantity.IsPositive() {
+			return errors.New("scanned quantity must be greater than zero")
+		}
+
+		if err := tx.StockTakeRepo.AddStockTakeLineCount(line.ID, types.FromDecimal(quantity), userID); err != nil {
+			return fmt.Errorf("failed to record scan of %s: %v", line.ItemName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Scan recorded successfully",
+		Data:    summary,
+	}, nil
+}
+
+// postVariance brings the stock of a counted item in line with the count. The
+// variance is applied to the stock as it is now rather than overwriting it
+// with the count, so sales made while the count was in progress are kept.
+func postVariance(tx *repositories.Repository, stockTake *models.StockTake, line models.StockTakeLine, userID string, changes *availabilityChanges) error {
+	variance := *line.Variance
+
+	var previousStock, currentStock types.DecimalText
+	var err error
+	if line.IngredientID != nil {
+		previousStock, currentStock, err = tx.IngredientRepo.AdjustIngredientStock(*line.IngredientID, variance, userID)
+	} else {
+		previousStock, currentStock, err = tx.InventoryRepo.AdjustInventoryStock(*line.MenuItemID, variance, userID)
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientStock) {
+			return fmt.Errorf("cannot post a variance of %s %s for %s: less than that is left in stock", variance, line.Unit, line.ItemName)
+		}
+		return fmt.Errorf("failed to update stock of %s: %v", line.ItemName, err)
+	}
+
+	// Stock found missing comes out of the lots like any other
+	if decimal.Decimal(variance).IsNegative() {
+		menuItemID := ""
+		if line.MenuItemID != nil {
+			menuItemID = *line.MenuItemID
+		}
+		if err := tx.StockLotRepo.ConsumeStockLots(menuItemID, line.IngredientID, types.FromDecimal(decimal.Decimal(variance).Neg())); err != nil {
+			return fmt.Errorf("failed to consume stock lots of %s: %v", line.ItemName, err)
+		}
+	}
+
+	referenceType := types.ReferenceTypeStockTake
+	stockTransaction := &models.StockTransaction{
+		ID:              uuid.New().String(),
+		IngredientID:    line.IngredientID,
+		TransactionType: types.TransactionTypeAdjustment,
+		Quantity:        variance,
+		PreviousStock:   previousStock,
+		CurrentStock:    currentStock,
+		Reason:          fmt.Sprintf("Stock take %s", stockTake.StockTakeNumber),
+		ReferenceType:   &referenceType,
+		ReferenceID:     &stockTake.ID,
+		UserID:          &userID,
+		CreatedAt:       time.Now(),
+	}
+	if line.MenuItemID != nil {
+		stockTransaction.MenuItemID = *line.MenuItemID
+	}
+	createdTransaction, err := recordStockTransaction(tx, stockTransaction)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientLocationStock) {
+			return fmt.Errorf("cannot post a variance of %s %s for %s: less than that is held at the selling location", variance, line.Unit, line.ItemName)
+		}
+		return fmt.Errorf("failed to create stock transaction for %s: %v", line.ItemName, err)
+	}
+	changes.add(createdTransaction)
+
+	return nil
+}
+
+// ApproveStockTake closes a stock take and posts the variance of every counted
+// item as an `adjustment` stock transaction referencing it. Items left
+// uncounted keep their stock.
+func (s *StockTakeService) ApproveStockTake(id, userID string) (*types.APIResponse, error) {
+	// Validate stock take ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock take ID")
+	}
+
+	var summary *models.StockTakeSummary
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		stockTake, err := tx.StockTakeRepo.GetStockTakeForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stockTake.Status != types.StockTakeStatusOpen {
+			return fmt.Errorf("cannot approve stock take: it is %s", stockTake.Status)
+		}
+
+		lines, err := tx.StockTakeRepo.ListStockTakeLines(id)
+		if err != nil {
+			return fmt.Errorf("failed to get stock take lines: %v", err)
+		}
+
+		for _, line := range lines {
+			if line.Variance == nil || decimal.Decimal(*line.Variance).IsZero() {
+				continue
+			}
+			if err := postVariance(tx, stockTake, line, userID, &changes); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.StockTakeRepo.UpdateStockTakeStatus(id, types.StockTakeStatusApproved, &userID); err != nil {
+			return fmt.Errorf("failed to update stock take status: %v", err)
+		}
+
+		summary, err = loadStockTake(tx.StockTakeRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock take approved successfully",
+		Data:    summary,
+	}, nil
+}
+
+// CancelStockTake abandons an open stock take without changing any stock
+func (s *StockTakeService) CancelStockTake(id string) (*types.APIResponse, error) {
+	// Validate stock take ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid stock take ID")
+	}
+
+	var summary *models.StockTakeSummary
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		stockTake, err := tx.StockTakeRepo.GetStockTakeForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stockTake.Status != types.StockTakeStatusOpen {
+			return fmt.Errorf("cannot cancel stock take: it is %s", stockTake.Status)
+		}
+
+		if err := tx.StockTakeRepo.UpdateStockTakeStatus(id, types.StockTakeStatusCancelled, nil); err != nil {
+			return fmt.Errorf("failed to update stock take status: %v", err)
+		}
+
+		summary, err = loadStockTake(tx.StockTakeRepo, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Stock take cancelled successfully",
+		Data:    summary,
+	}, nil
+}
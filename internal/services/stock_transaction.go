@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/costing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// recordStockTransaction records a stock movement that has just been applied
+// to the item's stock. The stock moves at the transaction's location, the
+// selling location unless it names another, whose balance is adjusted to
+// match; a location short of stock fails with
+// repositories.ErrInsufficientLocationStock. The movement is valued under
+// weighted-average costing: stock coming in at a known ReceivedCost moves the
+// item's average cost towards it, and every other movement is valued at the
+// average.
+//
+// A low stock alert is raised when the movement takes the item from above its
+// minimum stock to it or below, and resolved when it takes it back above. The
+// menu items made from the stock are sold out or put back on the menu to match
+// the selling location, and returned as AvailabilityChanges. It must run in
+// the database transaction that adjusted the stock, which keeps the item
+// locked until the movement is recorded.
+func recordStockTransaction(tx *repositories.Repository, transaction *models.StockTransaction) (*models.StockTransaction, error) {
+	if transaction.LocationID == nil {
+		location, err := tx.StockLocationRepo.GetSellingStockLocation()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get selling stock location: %v", err)
+		}
+		transaction.LocationID = &location.ID
+	}
+
+	if err := tx.StockLocationRepo.AdjustStockBalance(*transaction.LocationID, transaction.MenuItemID, transaction.IngredientID, transaction.Quantity); err != nil {
+		return nil, err
+	}
+
+	averageCost, err := tx.StockTransactionRepo.GetStockAverageCost(transaction.MenuItemID, transaction.IngredientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get average cost: %v", err)
+	}
+
+	var receivedCost *decimal.Decimal
+	if transaction.ReceivedCost != nil {
+		cost := decimal.Decimal(*transaction.ReceivedCost)
+		receivedCost = &cost
+	}
+	movement := costing.Value(decimal.Decimal(transaction.PreviousStock), decimal.Decimal(averageCost), decimal.Decimal(transaction.Quantity), receivedCost)
+	transaction.UnitCost = types.FromDecimal(movement.UnitCost)
+	transaction.TotalCost = types.FromDecimal(movement.TotalCost)
+	transaction.AverageCost = types.FromDecimal(movement.AverageCost)
+
+	createdTransaction, err := tx.StockTransactionRepo.CreateStockTransaction(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if !movement.AverageCost.Equal(decimal.Decimal(averageCost)) {
+		if err := tx.StockTransactionRepo.SetStockAverageCost(transaction.MenuItemID, transaction.IngredientID, transaction.AverageCost); err != nil {
+			return nil, fmt.Errorf("failed to update average cost: %v", err)
+		}
+	}
+
+	if err := tx.LowStockAlertRepo.SyncLowStockAlert(createdTransaction.ID, transaction.MenuItemID, transaction.IngredientID, transaction.PreviousStock); err != nil {
+		return nil, fmt.Errorf("failed to update low stock alerts: %v", err)
+	}
+
+	createdTransaction.AvailabilityChanges, err = tx.MenuRepo.SyncMenuItemAvailability(transaction.MenuItemID, transaction.IngredientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update menu item availability: %v", err)
+	}
+
+	return createdTransaction, nil
+}
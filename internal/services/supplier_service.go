@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// SupplierService handles the businesses stock is bought from
+type SupplierService struct {
+	supplierRepo repositories.SupplierRepo
+}
+
+// NewSupplierService creates a new supplier service
+func NewSupplierService(supplierRepo repositories.SupplierRepo) *SupplierService {
+	return &SupplierService{
+		supplierRepo: supplierRepo,
+	}
+}
+
+// CreateSupplier creates a supplier
+func (s *SupplierService) CreateSupplier(supplierData *models.SupplierCreate) (*types.APIResponse, error) {
+	isActive := true
+	if supplierData.IsActive != nil {
+		isActive = *supplierData.IsActive
+	}
+
+	createdSupplier, err := s.supplierRepo.CreateSupplier(&models.Supplier{
+		Name:        strings.TrimSpace(supplierData.Name),
+		ContactName: supplierData.ContactName,
+		Phone:       supplierData.Phone,
+		Email:       supplierData.Email,
+		Address:     supplierData.Address,
+		IsActive:    isActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Supplier created successfully",
+		Data:    createdSupplier,
+	}, nil
+}
+
+// GetSupplier retrieves a supplier
+func (s *SupplierService) GetSupplier(id string) (*types.APIResponse, error) {
+	// Validate supplier ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid supplier ID")
+	}
+
+	supplier, err := s.supplierRepo.GetSupplier(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    supplier,
+	}, nil
+}
+
+// ListSuppliers retrieves suppliers with optional filtering
+func (s *SupplierService) ListSuppliers(filter models.SupplierFilter) (*types.APIResponse, error) {
+	suppliers, err := s.supplierRepo.ListSuppliers(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppliers: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    suppliers,
+	}, nil
+}
+
+// UpdateSupplier updates a supplier's details. Suppliers are never deleted, as
+// purchase orders refer to them; deactivate one instead.
+func (s *SupplierService) UpdateSupplier(id string, supplierData *models.SupplierUpdate) (*types.APIResponse, error) {
+	// Validate supplier ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid supplier ID")
+	}
+
+	supplier, err := s.supplierRepo.GetSupplier(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if supplierData.Name != nil {
+		supplier.Name = strings.TrimSpace(*supplierData.Name)
+	}
+	if supplierData.ContactName != nil {
+		supplier.ContactName = supplierData.ContactName
+	}
+	if supplierData.Phone != nil {
+		supplier.Phone = supplierData.Phone
+	}
+	if supplierData.Email != nil {
+		supplier.Email = supplierData.Email
+	}
+	if supplierData.Address != nil {
+		supplier.Address = supplierData.Address
+	}
+	if supplierData.IsActive != nil {
+		supplier.IsActive = *supplierData.IsActive
+	}
+
+	updatedSupplier, err := s.supplierRepo.UpdateSupplier(supplier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update supplier: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Supplier updated successfully",
+		Data:    updatedSupplier,
+	}, nil
+}
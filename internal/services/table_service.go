@@ -0,0 +1,381 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+)
+
+// TableService handles floor areas, dining tables and the floor plan of open tabs
+type TableService struct {
+	tableRepo repositories.TableRepo
+}
+
+// NewTableService creates a new table service
+func NewTableService(tableRepo repositories.TableRepo) *TableService {
+	return &TableService{
+		tableRepo: tableRepo,
+	}
+}
+
+// CreateFloorArea creates a floor area
+func (s *TableService) CreateFloorArea(areaData *models.FloorAreaCreate) (*types.APIResponse, error) {
+	area := &models.FloorArea{
+		Name:      strings.TrimSpace(areaData.Name),
+		SortOrder: areaData.SortOrder,
+		IsActive:  true,
+	}
+	if areaData.IsActive != nil {
+		area.IsActive = *areaData.IsActive
+	}
+
+	if area.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	createdArea, err := s.tableRepo.CreateFloorArea(area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create floor area: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdArea,
+	}, nil
+}
+
+// GetFloorArea retrieves a floor area by ID
+func (s *TableService) GetFloorArea(id string) (*types.APIResponse, error) {
+	// Validate floor area ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid floor area ID")
+	}
+
+	area, err := s.tableRepo.GetFloorArea(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    area,
+	}, nil
+}
+
+// ListFloorAreas retrieves the floor areas
+func (s *TableService) ListFloorAreas(isActive *bool) (*types.APIResponse, error) {
+	areas, err := s.tableRepo.ListFloorAreas(isActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floor areas: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    areas,
+	}, nil
+}
+
+// UpdateFloorArea updates a floor area
+func (s *TableService) UpdateFloorArea(id string, areaData *models.FloorAreaUpdate) (*types.APIResponse, error) {
+	// Validate floor area ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid floor area ID")
+	}
+
+	area, err := s.tableRepo.GetFloorArea(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if areaData.Name != nil {
+		area.Name = strings.TrimSpace(*areaData.Name)
+		if area.Name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+	}
+	if areaData.SortOrder != nil {
+		area.SortOrder = *areaData.SortOrder
+	}
+	if areaData.IsActive != nil {
+		area.IsActive = *areaData.IsActive
+	}
+
+	updatedArea, err := s.tableRepo.UpdateFloorArea(area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update floor area: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedArea,
+	}, nil
+}
+
+// DeleteFloorArea deletes a floor area that has no tables left
+func (s *TableService) DeleteFloorArea(id string) (*types.APIResponse, error) {
+	// Validate floor area ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid floor area ID")
+	}
+
+	if _, err := s.tableRepo.GetFloorArea(id); err != nil {
+		return nil, err
+	}
+
+	tables, err := s.tableRepo.CountTablesByFloorArea(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check floor area tables: %v", err)
+	}
+	if tables > 0 {
+		return nil, fmt.Errorf("floor area still has %d tables; move or delete them first", tables)
+	}
+
+	if err := s.tableRepo.DeleteFloorArea(id); err != nil {
+		return nil, fmt.Errorf("failed to delete floor area: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Floor area deleted successfully",
+	}, nil
+}
+
+// CreateTable creates a dining table in a floor area
+func (s *TableService) CreateTable(tableData *models.DiningTableCreate) (*types.APIResponse, error) {
+	// Validate floor area ID
+	_, err := uuid.Parse(tableData.FloorAreaID)
+	if err != nil {
+		return nil, errors.New("invalid floor area ID")
+	}
+
+	if _, err := s.tableRepo.GetFloorArea(tableData.FloorAreaID); err != nil {
+		return nil, err
+	}
+
+	table := &models.DiningTable{
+		FloorAreaID: tableData.FloorAreaID,
+		Name:        strings.TrimSpace(tableData.Name),
+		Seats:       2,
+		SortOrder:   tableData.SortOrder,
+		IsActive:    true,
+	}
+	if tableData.Seats != nil {
+		table.Seats = *tableData.Seats
+	}
+	if tableData.IsActive != nil {
+		table.IsActive = *tableData.IsActive
+	}
+
+	if table.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	createdTable, err := s.tableRepo.CreateTable(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    createdTable,
+	}, nil
+}
+
+// GetTable retrieves a dining table by ID
+func (s *TableService) GetTable(id string) (*types.APIResponse, error) {
+	// Validate table ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid table ID")
+	}
+
+	table, err := s.tableRepo.GetTable(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    table,
+	}, nil
+}
+
+// ListTables retrieves the dining tables
+func (s *TableService) ListTables(filter models.DiningTableFilter) (*types.APIResponse, error) {
+	if filter.FloorAreaID != nil {
+		if _, err := uuid.Parse(*filter.FloorAreaID); err != nil {
+			return nil, errors.New("invalid floor area ID")
+		}
+	}
+
+	tables, err := s.tableRepo.ListTables(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    tables,
+	}, nil
+}
+
+// UpdateTable updates a dining table, which may move it to another floor area
+func (s *TableService) UpdateTable(id string, tableData *models.DiningTableUpdate) (*types.APIResponse, error) {
+	// Validate table ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid table ID")
+	}
+
+	table, err := s.tableRepo.GetTable(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if tableData.FloorAreaID != nil {
+		if _, err := s.tableRepo.GetFloorArea(*tableData.FloorAreaID); err != nil {
+			return nil, err
+		}
+		table.FloorAreaID = *tableData.FloorAreaID
+	}
+	if tableData.Name != nil {
+		table.Name = strings.TrimSpace(*tableData.Name)
+		if table.Name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+	}
+	if tableData.Seats != nil {
+		table.Seats = *tableData.Seats
+	}
+	if tableData.SortOrder != nil {
+		table.SortOrder = *tableData.SortOrder
+	}
+	if tableData.IsActive != nil {
+		table.IsActive = *tableData.IsActive
+	}
+
+	updatedTable, err := s.tableRepo.UpdateTable(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update table: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    updatedTable,
+	}, nil
+}
+
+// DeleteTable deletes a dining table that has no open orders. Past orders keep
+// their lines but no longer show the table.
+func (s *TableService) DeleteTable(id string) (*types.APIResponse, error) {
+	// Validate table ID
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.New("invalid table ID")
+	}
+
+	table, err := s.tableRepo.GetTable(id)
+	if err != nil {
+		return nil, err
+	}
+
+	openOrders, err := s.tableRepo.CountOpenOrdersByTable(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check open orders on table: %v", err)
+	}
+	if openOrders > 0 {
+		return nil, fmt.Errorf("table %s has %d open orders; settle or transfer them first", table.Name, openOrders)
+	}
+
+	if err := s.tableRepo.DeleteTable(id); err != nil {
+		return nil, fmt.Errorf("failed to delete table: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Table deleted successfully",
+	}, nil
+}
+
+// GetFloorPlan retrieves the active floor areas and tables with the orders open
+// on each table
+func (s *TableService) GetFloorPlan(floorAreaID *string) (*types.APIResponse, error) {
+	if floorAreaID != nil {
+		if _, err := uuid.Parse(*floorAreaID); err != nil {
+			return nil, errors.New("invalid floor area ID")
+		}
+	}
+
+	active := true
+	areas, err := s.tableRepo.ListFloorAreas(&active)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floor areas: %v", err)
+	}
+
+	tables, err := s.tableRepo.ListTables(models.DiningTableFilter{
+		FloorAreaID: floorAreaID,
+		IsActive:    &active,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %v", err)
+	}
+
+	openOrders, err := s.tableRepo.ListOpenTableOrders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %v", err)
+	}
+
+	ordersByTable := make(map[string][]models.TableOrder)
+	for _, order := range openOrders {
+		ordersByTable[order.TableID] = append(ordersByTable[order.TableID], *order)
+	}
+
+	tablesByArea := make(map[string][]models.TableStatus)
+	for _, table := range tables {
+		status := models.TableStatus{
+			DiningTable: *table,
+			OpenOrders:  ordersByTable[table.ID],
+		}
+		if status.OpenOrders == nil {
+			status.OpenOrders = []models.TableOrder{}
+		}
+		for _, order := range status.OpenOrders {
+			if order.GuestCount != nil {
+				status.GuestCount += *order.GuestCount
+			}
+		}
+		status.Occupied = len(status.OpenOrders) > 0
+
+		tablesByArea[table.FloorAreaID] = append(tablesByArea[table.FloorAreaID], status)
+	}
+
+	floorPlan := make([]models.FloorAreaWithTables, 0, len(areas))
+	for _, area := range areas {
+		if floorAreaID != nil && area.ID != *floorAreaID {
+			continue
+		}
+
+		areaTables := tablesByArea[area.ID]
+		if areaTables == nil {
+			areaTables = []models.TableStatus{}
+		}
+
+		floorPlan = append(floorPlan, models.FloorAreaWithTables{
+			FloorArea: *area,
+			Tables:    areaTables,
+		})
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    floorPlan,
+	}, nil
+}
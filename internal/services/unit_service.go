@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/units"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// UnitService handles the registry of units of measure stock can be counted,
+// bought and used in
+type UnitService struct {
+	unitRepo repositories.UnitRepo
+}
+
+// NewUnitService creates a new unit of measure service
+func NewUnitService(unitRepo repositories.UnitRepo) *UnitService {
+	return &UnitService{
+		unitRepo: unitRepo,
+	}
+}
+
+// ListUnits retrieves every unit of measure
+func (s *UnitService) ListUnits() (*types.APIResponse, error) {
+	unitList, err := s.unitRepo.ListUnits()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units of measure: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    unitList,
+	}, nil
+}
+
+// CreateUnit registers a unit of measure. Its factor is the number of base
+// units of its dimension one of it is worth, e.g. 1000 for kg when g is 1.
+func (s *UnitService) CreateUnit(unitData *models.UnitOfMeasureCreate) (*types.APIResponse, error) {
+	if !decimal.Decimal(unitData.Factor).IsPositive() {
+		return nil, errors.New("factor must be greater than zero")
+	}
+
+	code := strings.TrimSpace(unitData.Code)
+	if _, err := s.unitRepo.GetUnit(code); err == nil {
+		return nil, fmt.Errorf("unit of measure already exists: %s", code)
+	}
+
+	createdUnit, err := s.unitRepo.CreateUnit(&models.UnitOfMeasure{
+		Code:      code,
+		Name:      strings.TrimSpace(unitData.Name),
+		Dimension: strings.ToLower(strings.TrimSpace(unitData.Dimension)),
+		Factor:    unitData.Factor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create unit of measure: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Unit of measure created successfully",
+		Data:    createdUnit,
+	}, nil
+}
+
+// getUnit looks up a unit of measure in the form conversions need
+func getUnit(unitRepo repositories.UnitRepo, code string) (units.Unit, error) {
+	unit, err := unitRepo.GetUnit(code)
+	if err != nil {
+		return units.Unit{}, fmt.Errorf("unknown unit of measure: %s", code)
+	}
+
+	return units.Unit{
+		Code:      unit.Code,
+		Dimension: unit.Dimension,
+		Factor:    decimal.Decimal(unit.Factor),
+	}, nil
+}
+
+// checkUnitsCompatible verifies that a unit exists and converts into the stock unit
+func checkUnitsCompatible(unitRepo repositories.UnitRepo, code, stockUnit string) error {
+	stock, err := getUnit(unitRepo, stockUnit)
+	if err != nil {
+		return err
+	}
+	if code == stockUnit {
+		return nil
+	}
+
+	unit, err := getUnit(unitRepo, code)
+	if err != nil {
+		return err
+	}
+	if !units.Compatible(unit, stock) {
+		return fmt.Errorf("%v: cannot convert %s to %s", units.ErrIncompatibleUnits, code, stockUnit)
+	}
+
+	return nil
+}
+
+// toStockUnit converts a quantity given in unit into the stock unit. A missing
+// unit means the quantity is already in the stock unit.
+func toStockUnit(unitRepo repositories.UnitRepo, quantity types.DecimalText, unit *string, stockUnit string) (decimal.Decimal, error) {
+	if unit == nil || strings.TrimSpace(*unit) == "" || strings.TrimSpace(*unit) == stockUnit {
+		return decimal.Decimal(quantity).Round(units.Scale), nil
+	}
+	code := strings.TrimSpace(*unit)
+
+	from, err := getUnit(unitRepo, code)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	to, err := getUnit(unitRepo, stockUnit)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	converted, err := units.Convert(decimal.Decimal(quantity), from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if converted.IsZero() && !decimal.Decimal(quantity).IsZero() {
+		return decimal.Zero, fmt.Errorf("quantity %s %s is too small to count in %s", quantity, code, stockUnit)
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/units"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PortionUnit is the unit a menu item made from a recipe is wasted in
+const PortionUnit = "pcs"
+
+// WasteService handles writing off stock that is spoiled, damaged, eaten by
+// staff or given away
+type WasteService struct {
+	repos
+	menuAvailability *MenuAvailability
+}
+
+// NewWasteService creates a new waste service
+func NewWasteService(
+	repo *repositories.Repository,
+	uow repositories.UnitOfWork,
+	menuAvailability *MenuAvailability,
+) *WasteService {
+	return &WasteService{
+		repos:            repos{repo: repo, uow: uow},
+		menuAvailability: menuAvailability,
+	}
+}
+
+// wastedStock is the stock a waste record uses up and what one unit of it cost
+type wastedStock struct {
+	record   *models.WasteRecord
+	usage    *stockUsage
+	unitCost decimal.Decimal
+}
+
+// wastedIngredient works out the stock and cost of wasting an ingredient
+func wastedIngredient(tx *repositories.Repository, ingredientID string, wasteData *models.WasteCreate) (*wastedStock, error) {
+	ingredient, err := tx.IngredientRepo.GetIngredient(ingredientID)
+	if err != nil {
+		return nil, fmt.Errorf("ingredient not found: %s", ingredientID)
+	}
+
+	quantity, err := toStockUnit(tx.UnitRepo, wasteData.Quantity, wasteData.Unit, ingredient.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wastedStock{
+		record: &models.WasteRecord{
+			IngredientID: &ingredient.ID,
+			ItemName:     ingredient.Name,
+			Quantity:     types.FromDecimal(quantity),
+			Unit:         ingredient.Unit,
+		},
+		usage: &stockUsage{
+			menuItems:   map[string]decimal.Decimal{},
+			ingredients: map[string]decimal.Decimal{ingredient.ID: quantity},
+		},
+		unitCost: decimal.Decimal(ingredient.CostPrice),
+	}, nil
+}
+
+// wastedMenuItem works out the stock and cost of wasting a menu item. One made
+// from a recipe uses up its ingredients at their cost price; one stocked as
+// finished goods uses up its own stock at its cost.
+func wastedMenuItem(tx *repositories.Repository, menuItemID string, wasteData *models.WasteCreate) (*wastedStock, error) {
+	menuItem, err := tx.MenuRepo.GetMenuItem(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("menu item not found: %s", menuItemID)
+	}
+
+	recipe, err := tx.RecipeRepo.GetMenuItemRecipe(menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipe for menu item %s: %v", menuItemID, err)
+	}
+
+	if len(recipe) == 0 {
+		inventory, err := getOrCreateInventory(tx.InventoryRepo, menuItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := toStockUnit(tx.UnitRepo, wasteData.Quantity, wasteData.Unit, inventory.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		return &wastedStock{
+			record: &models.WasteRecord{
+				MenuItemID: &menuItem.ID,
+				ItemName:   menuItem.Name,
+				Quantity:   types.FromDecimal(quantity),
+				Unit:       inventory.Unit,
+			},
+			usage: &stockUsage{
+				menuItems:   map[string]decimal.Decimal{menuItem.ID: quantity},
+				ingredients: map[string]decimal.Decimal{},
+			},
+			unitCost: decimal.Decimal(menuItem.Cost),
+		}, nil
+	}
+
+	if wasteData.Unit != nil && strings.TrimSpace(*wasteData.Unit) != "" && strings.TrimSpace(*wasteData.Unit) != PortionUnit {
+		return nil, fmt.Errorf("%s is made from a recipe and is wasted in portions, not %s", menuItem.Name, strings.TrimSpace(*wasteData.Unit))
+	}
+	portions := decimal.Decimal(wasteData.Quantity).Round(units.Scale)
+
+	wasted := &wastedStock{
+		record: &models.WasteRecord{
+			MenuItemID: &menuItem.ID,
+			ItemName:   menuItem.Name,
+			Quantity:   types.FromDecimal(portions),
+			Unit:       PortionUnit,
+		},
+		usage: &stockUsage{
+			menuItems:   map[string]decimal.Decimal{},
+			ingredients: map[string]decimal.Decimal{},
+		},
+		unitCost: decimal.Zero,
+	}
+	for _, item := range recipe {
+		ingredient, err := tx.IngredientRepo.GetIngredient(item.IngredientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingredient %s: %v", item.IngredientID, err)
+		}
+
+		perPortion := decimal.Decimal(item.Quantity)
+		wasted.usage.ingredients[item.IngredientID] = wasted.usage.ingredients[item.IngredientID].Add(perPortion.Mul(portions))
+		wasted.unitCost = wasted.unitCost.Add(perPortion.Mul(decimal.Decimal(ingredient.CostPrice)))
+	}
+
+	return wasted, nil
+}
+
+// RecordWaste writes stock off as waste. The stock it used up is taken out as
+// `out` stock transactions referencing the waste record, which is valued at
+// the cost of that stock.
+func (s *WasteService) RecordWaste(userID string, wasteData *models.WasteCreate) (*types.APIResponse, error) {
+	if (wasteData.MenuItemID == nil) == (wasteData.IngredientID == nil) {
+		return nil, errors.New("waste must name either a menu item or an ingredient")
+	}
+
+	var record *models.WasteRecord
+	var changes availabilityChanges
+	err := s.runInTx(func(tx *repositories.Repository) error {
+		var wasted *wastedStock
+		var err error
+		if wasteData.IngredientID != nil {
+			wasted, err = wastedIngredient(tx, *wasteData.IngredientID, wasteData)
+		} else {
+			wasted, err = wastedMenuItem(tx, *wasteData.MenuItemID, wasteData)
+		}
+		if err != nil {
+			return err
+		}
+
+		quantity := decimal.Decimal(wasted.record.Quantity)
+		if !quantity.IsPositive() {
+			return errors.New("wasted quantity must be greater than zero")
+		}
+
+		wasted.record.Reason = wasteData.Reason
+		wasted.record.UnitCost = types.FromDecimal(wasted.unitCost.Round(4))
+		wasted.record.TotalCost = types.FromDecimal(quantity.Mul(wasted.unitCost).Round(2))
+		wasted.record.Notes = wasteData.Notes
+		wasted.record.UserID = userID
+
+		record, err = tx.WasteRepo.CreateWasteRecord(wasted.record)
+		if err != nil {
+			return fmt.Errorf("failed to create waste record: %v", err)
+		}
+
+		referenceType := types.ReferenceTypeWaste
+		return moveStock(tx, wasted.usage, false, stockMovement{
+			userID:        userID,
+			reason:        fmt.Sprintf("Waste: %s", strings.ReplaceAll(string(wasteData.Reason), "_", " ")),
+			referenceType: &referenceType,
+			referenceID:   &record.ID,
+			changes:       &changes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Waste recorded successfully",
+		Data:    record,
+	}, nil
+}
+
+// expiredLotWaste works out how much of an expired lot is still in stock and
+// so can be written off. The lot may hold more than the stock it belongs to
+// when stock has gone out other than through a sale or adjustment.
+func expiredLotWaste(tx *repositories.Repository, lot *models.StockLot) (*wastedStock, error) {
+	var inStock decimal.Decimal
+	usage := &stockUsage{
+		menuItems:   map[string]decimal.Decimal{},
+		ingredients: map[string]decimal.Decimal{},
+	}
+	if lot.IngredientID != nil {
+		ingredient, err := tx.IngredientRepo.GetIngredient(*lot.IngredientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingredient %s: %v", *lot.IngredientID, err)
+		}
+		inStock = decimal.Decimal(ingredient.CurrentStock)
+	} else {
+		inventory, err := getOrCreateInventory(tx.InventoryRepo, *lot.MenuItemID)
+		if err != nil {
+			return nil, err
+		}
+		inStock = decimal.Decimal(inventory.CurrentStock)
+	}
+
+	quantity := decimal.Min(decimal.Decimal(lot.QuantityRemaining), inStock)
+	if lot.IngredientID != nil {
+		usage.ingredients[*lot.IngredientID] = quantity
+	} else {
+		usage.menuItems[*lot.MenuItemID] = quantity
+	}
+
+	notes := fmt.Sprintf("Lot received on %s expired on %s", lot.ReceivedAt.Format("2006-01-02"), lot.ExpiresAt.Format("2006-01-02"))
+	if lot.LotNumber != nil {
+		notes = fmt.Sprintf("Lot %s expired on %s", *lot.LotNumber, lot.ExpiresAt.Format("2006-01-02"))
+	}
+
+	return &wastedStock{
+		record: &models.WasteRecord{
+			MenuItemID:   lot.MenuItemID,
+			IngredientID: lot.IngredientID,
+			ItemName:     lot.ItemName,
+			Reason:       types.WasteReasonExpired,
+			Quantity:     types.FromDecimal(quantity),
+			Unit:         lot.Unit,
+			Notes:        &notes,
+		},
+		usage:    usage,
+		unitCost: decimal.Decimal(lot.UnitCost),
+	}, nil
+}
+
+// WriteOffExpiredLots writes off what is left of every lot that has expired as
+// waste, valued at what the lot cost. The stock written off comes out of the
+// expired lots, which are emptied, and leaves the lots that have not expired
+// alone.
+func (s *WasteService) WriteOffExpiredLots(userID string) (*types.APIResponse, error) {
+	// Validate user ID
+	_, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	records := []*models.WasteRecord{}
+	var changes availabilityChanges
+	err = s.runInTx(func(tx *repositories.Repository) error {
+		lots, err := tx.StockLotRepo.ListStockLotsExpiringBefore(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to list expired stock lots: %v", err)
+		}
+
+		referenceType := types.ReferenceTypeWaste
+		for _, lot := range lots {
+			wasted, err := expiredLotWaste(tx, lot)
+			if err != nil {
+				return err
+			}
+
+			quantity := decimal.Decimal(wasted.record.Quantity)
+			if quantity.IsPositive() {
+				wasted.record.UnitCost = types.FromDecimal(wasted.unitCost.Round(4))
+				wasted.record.TotalCost = types.FromDecimal(quantity.Mul(wasted.unitCost).Round(2))
+				wasted.record.UserID = userID
+
+				record, err := tx.WasteRepo.CreateWasteRecord(wasted.record)
+				if err != nil {
+					return fmt.Errorf("failed to create waste record for %s: %v", lot.ItemName, err)
+				}
+				if err := moveStock(tx, wasted.usage, false, stockMovement{
+					userID:        userID,
+					reason:        "Waste: expired",
+					referenceType: &referenceType,
+					referenceID:   &record.ID,
+					changes:       &changes,
+					expiredLots:   true,
+				}); err != nil {
+					return err
+				}
+				records = append(records, record)
+			}
+
+			// Whatever the stock could not cover is gone as well
+			if err := tx.StockLotRepo.EmptyStockLot(lot.ID); err != nil {
+				return fmt.Errorf("failed to empty stock lot of %s: %v", lot.ItemName, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.menuAvailability.announce(changes)
+
+	return &types.APIResponse{
+		Success: true,
+		Message: "Expired stock written off successfully",
+		Data:    records,
+	}, nil
+}
+
+// ListWaste retrieves waste records with optional filtering
+func (s *WasteService) ListWaste(filter models.WasteFilter) (*types.APIResponse, error) {
+	if filter.UserID != nil {
+		if _, err := uuid.Parse(*filter.UserID); err != nil {
+			return nil, errors.New("invalid user ID")
+		}
+	}
+
+	records, err := s.repo.WasteRepo.ListWasteRecords(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waste records: %v", err)
+	}
+
+	return &types.APIResponse{
+		Success: true,
+		Data:    records,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Scale is the number of decimal places stock quantities are kept to
+const Scale = 3
+
+// ErrIncompatibleUnits is returned when converting between units of different
+// dimensions, e.g. grams to millilitres
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// Unit is a unit of measure. Units of the same dimension convert into each
+// other through their factors, the number of base units one of them is worth.
+type Unit struct {
+	Code      string
+	Dimension string
+	Factor    decimal.Decimal
+}
+
+// Compatible reports whether quantities can be converted between the units
+func Compatible(from, to Unit) bool {
+	return from.Dimension == to.Dimension
+}
+
+// Convert converts a quantity from one unit into another of the same
+// dimension, rounded to Scale decimal places
+func Convert(quantity decimal.Decimal, from, to Unit) (decimal.Decimal, error) {
+	if !Compatible(from, to) {
+		return decimal.Zero, fmt.Errorf("%w: cannot convert %s to %s", ErrIncompatibleUnits, from.Code, to.Code)
+	}
+	if from.Code == to.Code {
+		return quantity.Round(Scale), nil
+	}
+	if !to.Factor.IsPositive() {
+		return decimal.Zero, fmt.Errorf("unit %s has no conversion factor", to.Code)
+	}
+
+	return quantity.Mul(from.Factor).Div(to.Factor).Round(Scale), nil
+}
@@ -113,6 +113,26 @@ func RegisterValidatorRegistrations(validate *validator.Validate) {
 	validate.RegisterCustomTypeFunc(validateDecimalText, DecimalText{})
 	// Also register validation for *DecimalText (pointers)
 	validate.RegisterCustomTypeFunc(validateDecimalTextPtr, (*DecimalText)(nil))
+	// Exact amount comparisons, e.g. decimal_gt=0, as gt and lte compare the
+	// length of the text
+	validate.RegisterValidation("decimal_gt", compareDecimal(func(cmp int) bool { return cmp > 0 }))
+	validate.RegisterValidation("decimal_lte", compareDecimal(func(cmp int) bool { return cmp <= 0 }))
+}
+
+// compareDecimal builds a validation that compares a DecimalText with the tag
+// parameter exactly, passing when ok accepts the result of the comparison
+func compareDecimal(ok func(cmp int) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value, err := decimal.NewFromString(fl.Field().String())
+		if err != nil {
+			return false
+		}
+		param, err := decimal.NewFromString(fl.Param())
+		if err != nil {
+			return false
+		}
+		return ok(value.Cmp(param))
+	}
 }
 
 // validateDecimalText provides validation for DecimalText types
@@ -141,15 +161,94 @@ const (
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusCompleted OrderStatus = "completed"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusMerged    OrderStatus = "merged" // Its lines were moved into another order
+)
+
+// OrderType represents how an order is served to the guest
+type OrderType string
+
+const (
+	OrderTypeDineIn   OrderType = "dine_in"
+	OrderTypeTakeaway OrderType = "takeaway"
+	OrderTypeDelivery OrderType = "delivery"
+)
+
+// KitchenStatus represents how far the kitchen has got with an order line
+type KitchenStatus string
+
+const (
+	KitchenStatusQueued    KitchenStatus = "queued"
+	KitchenStatusPreparing KitchenStatus = "preparing"
+	KitchenStatusReady     KitchenStatus = "ready"
+	KitchenStatusServed    KitchenStatus = "served"
+)
+
+// ShiftStatus represents whether a cash drawer shift is still taking payments
+type ShiftStatus string
+
+const (
+	ShiftStatusOpen   ShiftStatus = "open"
+	ShiftStatusClosed ShiftStatus = "closed"
+)
+
+// PurchaseOrderStatus represents how far a purchase order has got, from
+// drafting it to receiving everything that was ordered
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// StockTakeStatus represents whether a stock take is still being counted
+type StockTakeStatus string
+
+const (
+	StockTakeStatusOpen      StockTakeStatus = "open"
+	StockTakeStatusApproved  StockTakeStatus = "approved" // Variances posted as stock adjustments
+	StockTakeStatusCancelled StockTakeStatus = "cancelled"
+)
+
+// WasteReason represents why stock was written off as waste
+type WasteReason string
+
+const (
+	WasteReasonExpired   WasteReason = "expired"
+	WasteReasonDamaged   WasteReason = "damaged"
+	WasteReasonStaffMeal WasteReason = "staff_meal"
+	WasteReasonComp      WasteReason = "comp" // Given away to a customer
+)
+
+// SalesReportType represents whether a sales report is a read-only X report or
+// a stored Z report that closes the period
+type SalesReportType string
+
+const (
+	SalesReportTypeX SalesReportType = "X"
+	SalesReportTypeZ SalesReportType = "Z"
+)
+
+// CashMovementType represents why cash left the drawer during a shift
+type CashMovementType string
+
+const (
+	CashMovementDrop   CashMovementType = "drop"   // Moved to the safe
+	CashMovementPayout CashMovementType = "payout" // Paid out, e.g. for supplies
 )
 
 // PaymentStatus represents the payment status of an order
 type PaymentStatus string
 
 const (
-	PaymentStatusPending PaymentStatus = "pending"
-	PaymentStatusPaid    PaymentStatus = "paid"
-	PaymentStatusFailed  PaymentStatus = "failed"
+	PaymentStatusPending  PaymentStatus = "pending"
+	PaymentStatusPaid     PaymentStatus = "paid"
+	PaymentStatusFailed   PaymentStatus = "failed"
+	PaymentStatusRefunded PaymentStatus = "refunded"
+	// PaymentStatusPartiallyRefunded marks a completed order with some of its lines returned
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
 )
 
 // PaymentMethod represents the payment method used for an order
@@ -160,6 +259,7 @@ const (
 	PaymentMethodCard     PaymentMethod = "card"
 	PaymentMethodQris     PaymentMethod = "qris"
 	PaymentMethodTransfer PaymentMethod = "transfer"
+	PaymentMethodSplit    PaymentMethod = "split" // Order paid with more than one tender
 )
 
 // TransactionType represents the type of stock transaction
@@ -171,6 +271,34 @@ const (
 	TransactionTypeAdjustment TransactionType = "adjustment"
 )
 
+// Reference types recorded on stock transactions to link them to their source document
+const (
+	ReferenceTypeOrder         = "order"
+	ReferenceTypeRefund        = "refund"
+	ReferenceTypePurchaseOrder = "purchase_order"
+	ReferenceTypeStockTake     = "stock_take"
+	ReferenceTypeWaste         = "waste"
+	ReferenceTypeStockTransfer = "stock_transfer"
+)
+
+// PromotionType represents how a promotion discounts an order
+type PromotionType string
+
+const (
+	PromotionTypePercentage  PromotionType = "percentage"
+	PromotionTypeFixedAmount PromotionType = "fixed_amount"
+	PromotionTypeBuyXGetY    PromotionType = "buy_x_get_y"
+)
+
+// PromotionTarget represents what part of an order a promotion applies to
+type PromotionTarget string
+
+const (
+	PromotionTargetOrder    PromotionTarget = "order"
+	PromotionTargetCategory PromotionTarget = "category"
+	PromotionTargetMenuItem PromotionTarget = "menu_item"
+)
+
 // UserRole represents the role of a user in the system
 type UserRole string
 
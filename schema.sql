@@ -66,9 +66,13 @@ CREATE TABLE menu_items (
     is_available BOOLEAN NOT NULL DEFAULT true,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- Taken off the menu because the selling location ran out of stock, and
+    -- put back on when it is restocked
+    sold_out BOOLEAN NOT NULL DEFAULT false,
 
     -- Ensure cost is not greater than price
-    CONSTRAINT cost_not_greater_than_price CHECK (cost <= price)
+    CONSTRAINT cost_not_greater_than_price CHECK (cost <= price),
+    CONSTRAINT menu_items_sold_out_unavailable CHECK (NOT (sold_out AND is_available))
 );
 
 -- Create indexes for menu_items table
@@ -78,15 +82,34 @@ CREATE INDEX idx_menu_items_is_available ON menu_items(is_available);
 CREATE INDEX idx_menu_items_price ON menu_items(price);
 CREATE INDEX idx_menu_items_created_at ON menu_items(created_at);
 
+-- Create units_of_measure table
+CREATE TABLE units_of_measure (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    dimension VARCHAR(50) NOT NULL,
+    factor NUMERIC(18,6) NOT NULL CHECK (factor > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO units_of_measure (code, name, dimension, factor) VALUES
+    ('mg', 'Milligram', 'mass', 0.001),
+    ('g', 'Gram', 'mass', 1),
+    ('kg', 'Kilogram', 'mass', 1000),
+    ('ml', 'Millilitre', 'volume', 1),
+    ('l', 'Litre', 'volume', 1000),
+    ('pcs', 'Piece', 'count', 1),
+    ('dozen', 'Dozen', 'count', 12);
+
 -- Create inventory table
 CREATE TABLE inventory (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     menu_item_id UUID NOT NULL UNIQUE REFERENCES menu_items(id) ON DELETE CASCADE,
-    current_stock INTEGER NOT NULL DEFAULT 0 CHECK (current_stock >= 0),
-    minimum_stock INTEGER NOT NULL DEFAULT 0 CHECK (minimum_stock >= 0),
-    unit VARCHAR(50) NOT NULL DEFAULT 'pieces',
+    current_stock NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (current_stock >= 0),
+    minimum_stock NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (minimum_stock >= 0),
+    unit VARCHAR(50) NOT NULL DEFAULT 'pcs' REFERENCES units_of_measure(code),
     last_updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
-    last_updated_by UUID REFERENCES users(id)
+    last_updated_by UUID REFERENCES users(id),
+    average_cost NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (average_cost >= 0)
 );
 
 -- Create indexes for inventory table
@@ -95,6 +118,90 @@ CREATE INDEX idx_inventory_current_stock ON inventory(current_stock);
 CREATE INDEX idx_inventory_minimum_stock ON inventory(minimum_stock);
 CREATE INDEX idx_inventory_last_updated_at ON inventory(last_updated_at);
 
+-- Create ingredients table
+CREATE TABLE ingredients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    current_stock NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (current_stock >= 0),
+    minimum_stock NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (minimum_stock >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_updated_by UUID REFERENCES users(id),
+    purchase_unit VARCHAR(50) REFERENCES units_of_measure(code),
+    cost_price NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (cost_price >= 0),
+    average_cost NUMERIC(12,4) NOT NULL DEFAULT 0 CHECK (average_cost >= 0)
+);
+
+-- Create indexes for ingredients table
+CREATE INDEX idx_ingredients_current_stock ON ingredients(current_stock);
+
+-- Create floor_areas table
+CREATE TABLE floor_areas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create dining_tables table
+CREATE TABLE dining_tables (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    floor_area_id UUID NOT NULL REFERENCES floor_areas(id) ON DELETE RESTRICT,
+    name VARCHAR(50) NOT NULL,
+    seats INTEGER NOT NULL DEFAULT 2 CHECK (seats > 0),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (floor_area_id, name)
+);
+
+-- Create index for dining_tables table
+CREATE INDEX idx_dining_tables_floor_area_id ON dining_tables(floor_area_id);
+
+-- Create cash_shifts table
+CREATE TABLE cash_shifts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float DECIMAL(12,2) NOT NULL CHECK (opening_float >= 0),
+    expected_cash DECIMAL(12,2),
+    counted_cash DECIMAL(12,2) CHECK (counted_cash >= 0),
+    variance DECIMAL(12,2),
+    notes TEXT,
+    opened_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    closed_by UUID REFERENCES users(id)
+);
+
+-- Create cash_movements table
+CREATE TABLE cash_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shift_id UUID NOT NULL REFERENCES cash_shifts(id) ON DELETE CASCADE,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('drop', 'payout')),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create cash_shift_counts table
+CREATE TABLE cash_shift_counts (
+    shift_id UUID NOT NULL REFERENCES cash_shifts(id) ON DELETE CASCADE,
+    denomination DECIMAL(12,2) NOT NULL CHECK (denomination > 0),
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    PRIMARY KEY (shift_id, denomination)
+);
+
+-- Create indexes for cash shift tables
+CREATE UNIQUE INDEX idx_cash_shifts_open_user_id ON cash_shifts(user_id) WHERE status = 'open';
+CREATE INDEX idx_cash_shifts_opened_at ON cash_shifts(opened_at);
+CREATE INDEX idx_cash_movements_shift_id ON cash_movements(shift_id);
+
 -- Create orders table
 CREATE TABLE orders (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -104,11 +211,23 @@ CREATE TABLE orders (
     total_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (total_amount >= 0),
     discount_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (discount_amount >= 0),
     tax_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (tax_amount >= 0),
-    payment_method VARCHAR(20) CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
-    payment_status VARCHAR(20) NOT NULL CHECK (payment_status IN ('pending', 'paid', 'failed')) DEFAULT 'pending',
+    payment_method VARCHAR(20) CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer', 'split')),
+    payment_status VARCHAR(20) NOT NULL CHECK (payment_status IN ('pending', 'paid', 'failed', 'refunded', 'partially_refunded')) DEFAULT 'pending',
     completed_at TIMESTAMP,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    cancellation_reason VARCHAR(255),
+    cancelled_by UUID REFERENCES users(id),
+    cancelled_at TIMESTAMP,
+    subtotal_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (subtotal_amount >= 0),
+    service_charge_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (service_charge_amount >= 0),
+    rounding_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    service_charge_rate DECIMAL(5,2) NOT NULL DEFAULT 0.00 CHECK (service_charge_rate >= 0),
+    tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0.00 CHECK (tax_rate >= 0),
+    order_type VARCHAR(20) NOT NULL DEFAULT 'takeaway' CHECK (order_type IN ('dine_in', 'takeaway', 'delivery')),
+    table_id UUID REFERENCES dining_tables(id) ON DELETE SET NULL,
+    guest_count INTEGER CHECK (guest_count > 0),
+    shift_id UUID REFERENCES cash_shifts(id) ON DELETE SET NULL
 );
 
 -- Create indexes for orders table
@@ -119,6 +238,9 @@ CREATE INDEX idx_orders_created_at ON orders(created_at);
 CREATE INDEX idx_orders_completed_at ON orders(completed_at);
 CREATE INDEX idx_orders_total_amount ON orders(total_amount);
 CREATE INDEX idx_orders_status_completed_at ON orders(status, completed_at);
+CREATE INDEX idx_orders_cancelled_at ON orders(cancelled_at);
+CREATE INDEX idx_orders_table_id_status ON orders(table_id, status);
+CREATE INDEX idx_orders_shift_id ON orders(shift_id);
 
 -- Create order_items table
 CREATE TABLE order_items (
@@ -128,6 +250,7 @@ CREATE TABLE order_items (
     quantity INTEGER NOT NULL CHECK (quantity > 0),
     unit_price DECIMAL(10,2) NOT NULL CHECK (unit_price >= 0),
     total_price DECIMAL(12,2) NOT NULL CHECK (total_price >= 0),
+    notes TEXT,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW()
 );
@@ -141,27 +264,80 @@ CREATE INDEX idx_order_items_created_at ON order_items(created_at);
 CREATE INDEX idx_order_items_updated_at ON order_items(updated_at);
 CREATE INDEX idx_order_items_order_id_menu_item_id ON order_items(order_id, menu_item_id);
 
+-- Create stock_locations table for the places stock is kept in. Sales are
+-- made from the one selling location.
+CREATE TABLE stock_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    is_selling BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (is_active OR NOT is_selling)
+);
+
+CREATE UNIQUE INDEX idx_stock_locations_selling ON stock_locations(is_selling) WHERE is_selling;
+
+INSERT INTO stock_locations (code, name, is_selling) VALUES ('MAIN', 'Main store', true);
+
+-- Create stock_balances table with the stock of each item at each location;
+-- inventory and ingredients keep each item's stock across all of them
+CREATE TABLE stock_balances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    location_id UUID NOT NULL REFERENCES stock_locations(id),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id UUID REFERENCES ingredients(id) ON DELETE CASCADE,
+    quantity NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_stock_balances_location_menu_item ON stock_balances(location_id, menu_item_id) WHERE menu_item_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_stock_balances_location_ingredient ON stock_balances(location_id, ingredient_id) WHERE ingredient_id IS NOT NULL;
+
 -- Create stock_transactions table
 CREATE TABLE stock_transactions (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    menu_item_id UUID NOT NULL REFERENCES menu_items(id),
+    menu_item_id UUID REFERENCES menu_items(id),
     transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('in', 'out', 'adjustment')),
-    quantity INTEGER NOT NULL,
-    previous_stock INTEGER NOT NULL,
-    current_stock INTEGER NOT NULL,
+    quantity NUMERIC(12,3) NOT NULL,
+    previous_stock NUMERIC(12,3) NOT NULL,
+    current_stock NUMERIC(12,3) NOT NULL,
     reason VARCHAR(255) NOT NULL,
     reference_type VARCHAR(50),
     reference_id UUID,
     user_id UUID REFERENCES users(id),
-    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+    created_at TIMESTAMP NOT NULL DEFAULT clock_timestamp(),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) REFERENCES units_of_measure(code),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    total_cost NUMERIC(12,2) NOT NULL DEFAULT 0,
+    average_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    location_id UUID REFERENCES stock_locations(id),
+    CONSTRAINT stock_transactions_item_check CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
 );
 
 -- Create indexes for stock_transactions table
 CREATE INDEX idx_stock_transactions_menu_item_id ON stock_transactions(menu_item_id);
+CREATE INDEX idx_stock_transactions_ingredient_id ON stock_transactions(ingredient_id);
 CREATE INDEX idx_stock_transactions_transaction_type ON stock_transactions(transaction_type);
 CREATE INDEX idx_stock_transactions_created_at ON stock_transactions(created_at);
 CREATE INDEX idx_stock_transactions_user_id ON stock_transactions(user_id);
 CREATE INDEX idx_stock_transactions_quantity ON stock_transactions(quantity);
+CREATE INDEX idx_stock_transactions_reference ON stock_transactions(reference_type, reference_id);
+CREATE INDEX idx_stock_transactions_reference_type_created_at ON stock_transactions(reference_type, created_at);
+CREATE INDEX idx_stock_transactions_location_id ON stock_transactions(location_id);
+
+-- Create order number sequences table
+CREATE TABLE order_number_sequences (
+    prefix VARCHAR(20) NOT NULL,
+    business_date DATE NOT NULL,
+    last_number INTEGER NOT NULL DEFAULT 0 CHECK (last_number >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (prefix, business_date)
+);
 
 -- Create expenses table
 CREATE TABLE expenses (
@@ -182,6 +358,512 @@ CREATE INDEX idx_expenses_amount ON expenses(amount);
 CREATE INDEX idx_expenses_created_at ON expenses(created_at);
 CREATE INDEX idx_expenses_date_category ON expenses(date, category);
 
+-- Create promotions table
+-- A promotion without a voucher code is applied automatically to every order it qualifies for
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    promotion_type VARCHAR(20) NOT NULL CHECK (promotion_type IN ('percentage', 'fixed_amount', 'buy_x_get_y')),
+    value DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (value >= 0),
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    target_type VARCHAR(20) NOT NULL DEFAULT 'order' CHECK (target_type IN ('order', 'category', 'menu_item')),
+    target_id UUID,
+    voucher_code VARCHAR(50) UNIQUE,
+    min_spend DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (min_spend >= 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    daily_start_time VARCHAR(5) CHECK (daily_start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    daily_end_time VARCHAR(5) CHECK (daily_end_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    usage_count INTEGER NOT NULL DEFAULT 0 CHECK (usage_count >= 0),
+    is_stackable BOOLEAN NOT NULL DEFAULT false,
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (promotion_type <> 'buy_x_get_y' OR (buy_quantity IS NOT NULL AND get_quantity IS NOT NULL)),
+    CHECK (target_type = 'order' OR target_id IS NOT NULL),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+-- Create order promotions table recording the promotions applied to each order
+CREATE TABLE order_promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id UUID REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name VARCHAR(100) NOT NULL,
+    discount_amount DECIMAL(12,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for promotions tables
+CREATE INDEX idx_promotions_is_active ON promotions(is_active);
+CREATE INDEX idx_promotions_voucher_code ON promotions(voucher_code);
+CREATE INDEX idx_promotions_starts_at_ends_at ON promotions(starts_at, ends_at);
+CREATE INDEX idx_order_promotions_order_id ON order_promotions(order_id);
+CREATE INDEX idx_order_promotions_promotion_id ON order_promotions(promotion_id);
+
+-- Create order_payments table
+CREATE TABLE order_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    reference VARCHAR(100),
+    change_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (change_amount >= 0 AND change_amount <= amount),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for order_payments table
+CREATE INDEX idx_order_payments_order_id ON order_payments(order_id);
+CREATE INDEX idx_order_payments_payment_method ON order_payments(payment_method);
+
+-- Create refunds table
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount >= 0),
+    tax_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00 CHECK (tax_amount >= 0),
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    reference VARCHAR(100),
+    reason VARCHAR(255) NOT NULL,
+    restocked BOOLEAN NOT NULL DEFAULT TRUE,
+    refunded_by UUID NOT NULL REFERENCES users(id),
+    approved_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create refund_items table
+CREATE TABLE refund_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    refund_id UUID NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount >= 0)
+);
+
+-- Create indexes for refunds tables
+CREATE INDEX idx_refunds_order_id ON refunds(order_id);
+CREATE INDEX idx_refunds_created_at ON refunds(created_at);
+CREATE INDEX idx_refund_items_refund_id ON refund_items(refund_id);
+CREATE INDEX idx_refund_items_order_item_id ON refund_items(order_item_id);
+
+-- Create modifier_groups table
+CREATE TABLE modifier_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 1 CHECK (max_select > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (max_select >= min_select)
+);
+
+-- Create modifier_options table
+CREATE TABLE modifier_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    modifier_group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    is_available BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create menu_item_modifier_groups table
+CREATE TABLE menu_item_modifier_groups (
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    modifier_group_id UUID NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (menu_item_id, modifier_group_id)
+);
+
+-- Create order_item_modifiers table
+CREATE TABLE order_item_modifiers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    modifier_option_id UUID REFERENCES modifier_options(id) ON DELETE SET NULL,
+    group_name VARCHAR(100) NOT NULL,
+    option_name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for modifier tables
+CREATE INDEX idx_modifier_options_modifier_group_id ON modifier_options(modifier_group_id);
+CREATE INDEX idx_menu_item_modifier_groups_modifier_group_id ON menu_item_modifier_groups(modifier_group_id);
+CREATE INDEX idx_order_item_modifiers_order_item_id ON order_item_modifiers(order_item_id);
+CREATE INDEX idx_order_item_modifiers_modifier_option_id ON order_item_modifiers(modifier_option_id);
+
+-- Create recipe_items table
+CREATE TABLE recipe_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    modifier_option_id UUID REFERENCES modifier_options(id) ON DELETE CASCADE,
+    ingredient_id UUID NOT NULL REFERENCES ingredients(id),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    CHECK ((menu_item_id IS NULL) <> (modifier_option_id IS NULL)),
+    UNIQUE (menu_item_id, ingredient_id),
+    UNIQUE (modifier_option_id, ingredient_id)
+);
+
+-- Create indexes for recipe_items table
+CREATE INDEX idx_recipe_items_ingredient_id ON recipe_items(ingredient_id);
+
+-- Create kitchen_stations table
+CREATE TABLE kitchen_stations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create kitchen_station_categories table
+CREATE TABLE kitchen_station_categories (
+    category_id UUID PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE,
+    station_id UUID NOT NULL REFERENCES kitchen_stations(id) ON DELETE CASCADE
+);
+
+-- Create kitchen_tickets table
+CREATE TABLE kitchen_tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID UNIQUE NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    station_id UUID REFERENCES kitchen_stations(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'preparing', 'ready', 'served')),
+    queued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    preparing_at TIMESTAMP,
+    ready_at TIMESTAMP,
+    served_at TIMESTAMP,
+    bumped_by UUID REFERENCES users(id)
+);
+
+-- Create indexes for kitchen display tables
+CREATE INDEX idx_kitchen_station_categories_station_id ON kitchen_station_categories(station_id);
+CREATE INDEX idx_kitchen_tickets_order_id ON kitchen_tickets(order_id);
+CREATE INDEX idx_kitchen_tickets_station_id_status ON kitchen_tickets(station_id, status);
+CREATE INDEX idx_kitchen_tickets_queued_at ON kitchen_tickets(queued_at);
+
+-- Create receipt_prints table
+CREATE TABLE receipt_prints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    printed_by UUID NOT NULL REFERENCES users(id),
+    format VARCHAR(20) NOT NULL CHECK (format IN ('escpos58', 'escpos80', 'text', 'pdf')),
+    copy_number INTEGER NOT NULL CHECK (copy_number > 0),
+    printed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, copy_number)
+);
+
+-- Create index for receipt_prints table
+CREATE INDEX idx_receipt_prints_printed_at ON receipt_prints(printed_at);
+
+-- Create z_reports table
+CREATE TABLE z_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    report_number INTEGER NOT NULL UNIQUE CHECK (report_number > 0),
+    period_start TIMESTAMP,
+    period_end TIMESTAMP NOT NULL,
+    generated_by UUID NOT NULL REFERENCES users(id),
+    generated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    gross_sales DECIMAL(12,2) NOT NULL,
+    discount_amount DECIMAL(12,2) NOT NULL,
+    service_charge_amount DECIMAL(12,2) NOT NULL,
+    tax_amount DECIMAL(12,2) NOT NULL,
+    rounding_amount DECIMAL(12,2) NOT NULL,
+    total_sales DECIMAL(12,2) NOT NULL,
+    refund_count INTEGER NOT NULL CHECK (refund_count >= 0),
+    refund_amount DECIMAL(12,2) NOT NULL,
+    refund_tax DECIMAL(12,2) NOT NULL,
+    cancelled_count INTEGER NOT NULL CHECK (cancelled_count >= 0),
+    voided_count INTEGER NOT NULL CHECK (voided_count >= 0),
+    voided_amount DECIMAL(12,2) NOT NULL,
+    net_sales DECIMAL(12,2) NOT NULL,
+    first_order_number VARCHAR(50),
+    last_order_number VARCHAR(50),
+    CHECK (period_start IS NULL OR period_start < period_end)
+);
+
+-- Create z_report_payment_methods table
+CREATE TABLE z_report_payment_methods (
+    z_report_id UUID NOT NULL REFERENCES z_reports(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash', 'card', 'qris', 'transfer')),
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    total_amount DECIMAL(12,2) NOT NULL,
+    PRIMARY KEY (z_report_id, payment_method)
+);
+
+-- Create z_report_cashiers table
+CREATE TABLE z_report_cashiers (
+    z_report_id UUID NOT NULL REFERENCES z_reports(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    cashier_name VARCHAR(255) NOT NULL,
+    order_count INTEGER NOT NULL CHECK (order_count >= 0),
+    total_sales DECIMAL(12,2) NOT NULL,
+    PRIMARY KEY (z_report_id, user_id)
+);
+
+-- Create index for z_reports table
+CREATE INDEX idx_z_reports_generated_at ON z_reports(generated_at);
+
+-- Create suppliers table for the businesses stock is bought from
+CREATE TABLE suppliers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    contact_name VARCHAR(100),
+    phone VARCHAR(50),
+    email VARCHAR(100),
+    address TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Purchase orders and goods received notes are numbered from their own sequences
+CREATE SEQUENCE purchase_order_number_seq;
+CREATE SEQUENCE goods_received_note_number_seq;
+
+-- Create purchase_orders table. An order is drafted, sent to the supplier and
+-- then received in one or more deliveries.
+CREATE TABLE purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    po_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'PO-' || LPAD(nextval('purchase_order_number_seq')::TEXT, 6, '0'),
+    supplier_id UUID NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+    expected_date DATE,
+    notes TEXT,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    created_by UUID NOT NULL REFERENCES users(id),
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create purchase_order_items table with the menu items (finished goods) or
+-- ingredients ordered, in any unit compatible with their stock unit
+CREATE TABLE purchase_order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    received_quantity NUMERIC(12,3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_cost DECIMAL(12,2) NOT NULL CHECK (unit_cost >= 0),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Create goods_received_notes table, one per delivery against a purchase order
+CREATE TABLE goods_received_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    grn_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'GRN-' || LPAD(nextval('goods_received_note_number_seq')::TEXT, 6, '0'),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id),
+    total_amount DECIMAL(12,2) NOT NULL CHECK (total_amount >= 0),
+    expense_id UUID REFERENCES expenses(id) ON DELETE SET NULL,
+    notes TEXT,
+    received_by UUID NOT NULL REFERENCES users(id),
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create goods_received_note_items table with the quantity received of each
+-- purchase order line, in the line's unit and converted to the stock unit
+CREATE TABLE goods_received_note_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    goods_received_note_id UUID NOT NULL REFERENCES goods_received_notes(id) ON DELETE CASCADE,
+    purchase_order_item_id UUID NOT NULL REFERENCES purchase_order_items(id),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    stock_quantity NUMERIC(12,3) NOT NULL CHECK (stock_quantity > 0),
+    unit_cost DECIMAL(12,2) NOT NULL CHECK (unit_cost >= 0)
+);
+
+-- Create indexes for purchasing tables
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX idx_purchase_orders_created_at ON purchase_orders(created_at);
+CREATE INDEX idx_purchase_order_items_purchase_order_id ON purchase_order_items(purchase_order_id);
+CREATE INDEX idx_goods_received_notes_purchase_order_id ON goods_received_notes(purchase_order_id);
+CREATE INDEX idx_goods_received_note_items_note_id ON goods_received_note_items(goods_received_note_id);
+
+-- Stock takes are numbered from their own sequence
+CREATE SEQUENCE stock_take_number_seq;
+
+-- Create stock_takes table for counting the shelf against the system stock. A
+-- stock take may cover every item or be a cycle count of one menu category.
+CREATE TABLE stock_takes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_take_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'ST-' || LPAD(nextval('stock_take_number_seq')::TEXT, 6, '0'),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    category_id UUID REFERENCES categories(id),
+    include_menu_items BOOLEAN NOT NULL DEFAULT true,
+    include_ingredients BOOLEAN NOT NULL DEFAULT true,
+    notes TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    approved_by UUID REFERENCES users(id),
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create stock_take_lines table with the system quantity and cost of each item
+-- when the stock take started, and the quantity counted on the shelf
+CREATE TABLE stock_take_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_take_id UUID NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    system_quantity NUMERIC(12,3) NOT NULL,
+    counted_quantity NUMERIC(12,3) CHECK (counted_quantity >= 0),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    counted_by UUID REFERENCES users(id),
+    counted_at TIMESTAMP,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    UNIQUE (stock_take_id, menu_item_id),
+    UNIQUE (stock_take_id, ingredient_id)
+);
+
+-- Create indexes for stock take tables
+CREATE INDEX idx_stock_takes_status ON stock_takes(status);
+CREATE INDEX idx_stock_takes_created_at ON stock_takes(created_at);
+CREATE INDEX idx_stock_take_lines_stock_take_id ON stock_take_lines(stock_take_id);
+
+-- Create waste_records table for stock thrown away, eaten by staff or given
+-- away, each valued at cost. A wasted menu item made from a recipe uses up
+-- its ingredients; one stocked as finished goods uses up its own stock.
+CREATE TABLE waste_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('expired', 'damaged', 'staff_meal', 'comp')),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    total_cost NUMERIC(12,2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    user_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Create indexes for waste tables
+CREATE INDEX idx_waste_records_created_at ON waste_records(created_at);
+CREATE INDEX idx_waste_records_reason ON waste_records(reason);
+CREATE INDEX idx_waste_records_user_id ON waste_records(user_id);
+
+-- Create stock_lots table so stock is held in lots, each with the date it was
+-- received, when it expires and what it cost. Stock taken out of a menu item
+-- or ingredient is taken from its lots first-expiring first, falling back to
+-- first-received first for lots that do not expire.
+CREATE TABLE stock_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    lot_number VARCHAR(100),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity_received NUMERIC(12,3) NOT NULL CHECK (quantity_received > 0),
+    quantity_remaining NUMERIC(12,3) NOT NULL CHECK (quantity_remaining >= 0),
+    unit_cost NUMERIC(12,4) NOT NULL DEFAULT 0,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    goods_received_note_id UUID REFERENCES goods_received_notes(id),
+    received_by UUID REFERENCES users(id),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    CHECK (quantity_remaining <= quantity_received)
+);
+
+-- Create indexes for stock lot tables
+CREATE INDEX idx_stock_lots_menu_item_open ON stock_lots(menu_item_id, expires_at, received_at) WHERE quantity_remaining > 0;
+CREATE INDEX idx_stock_lots_ingredient_open ON stock_lots(ingredient_id, expires_at, received_at) WHERE quantity_remaining > 0;
+CREATE INDEX idx_stock_lots_expires_at ON stock_lots(expires_at) WHERE quantity_remaining > 0;
+
+-- Stock transfers are numbered from their own sequence
+CREATE SEQUENCE stock_transfer_number_seq;
+
+-- Create stock_transfers table for moving stock from one location to another
+CREATE TABLE stock_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_number VARCHAR(50) UNIQUE NOT NULL DEFAULT 'TR-' || LPAD(nextval('stock_transfer_number_seq')::TEXT, 6, '0'),
+    from_location_id UUID NOT NULL REFERENCES stock_locations(id),
+    to_location_id UUID NOT NULL REFERENCES stock_locations(id),
+    notes TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (from_location_id <> to_location_id)
+);
+
+-- Create stock_transfer_lines table with the quantity of each item moved, in
+-- the item's stock unit
+CREATE TABLE stock_transfer_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_transfer_id UUID NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    menu_item_id UUID REFERENCES menu_items(id),
+    ingredient_id UUID REFERENCES ingredients(id),
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    quantity NUMERIC(12,3) NOT NULL CHECK (quantity > 0),
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL)),
+    UNIQUE (stock_transfer_id, menu_item_id),
+    UNIQUE (stock_transfer_id, ingredient_id)
+);
+
+-- Create indexes for stock transfer tables
+CREATE INDEX idx_stock_transfers_from_location_id ON stock_transfers(from_location_id);
+CREATE INDEX idx_stock_transfers_to_location_id ON stock_transfers(to_location_id);
+CREATE INDEX idx_stock_transfers_created_at ON stock_transfers(created_at);
+CREATE INDEX idx_stock_transfer_lines_stock_transfer_id ON stock_transfer_lines(stock_transfer_id);
+
+-- A low stock alert is raised when a stock movement takes a menu item or an
+-- ingredient from above its minimum stock to it or below, and stays open
+-- until the item is restocked above it. An item is alerted at most once while
+-- it is low, however often it sells.
+CREATE TABLE low_stock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id UUID REFERENCES ingredients(id) ON DELETE CASCADE,
+    item_name VARCHAR(255) NOT NULL,
+    unit VARCHAR(50) NOT NULL REFERENCES units_of_measure(code),
+    current_stock NUMERIC(12,3) NOT NULL,
+    minimum_stock NUMERIC(12,3) NOT NULL,
+    stock_transaction_id UUID NOT NULL REFERENCES stock_transactions(id),
+    raised_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP,
+    read_at TIMESTAMP,
+    read_by UUID REFERENCES users(id),
+    -- Delivery through the notification channels, retried with backoff
+    dispatched_at TIMESTAMP,
+    dispatch_attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Each item has at most one open alert
+CREATE UNIQUE INDEX idx_low_stock_alerts_open_menu_item ON low_stock_alerts(menu_item_id) WHERE menu_item_id IS NOT NULL AND resolved_at IS NULL;
+CREATE UNIQUE INDEX idx_low_stock_alerts_open_ingredient ON low_stock_alerts(ingredient_id) WHERE ingredient_id IS NOT NULL AND resolved_at IS NULL;
+
+-- Create indexes for low stock alert tables
+CREATE INDEX idx_low_stock_alerts_raised_at ON low_stock_alerts(raised_at);
+CREATE INDEX idx_low_stock_alerts_undispatched ON low_stock_alerts(next_attempt_at) WHERE dispatched_at IS NULL;
+
+-- Create low_stock_alert_deliveries table with the channels an alert has been
+-- delivered through, so a retry only goes to the channels that failed
+CREATE TABLE low_stock_alert_deliveries (
+    alert_id UUID NOT NULL REFERENCES low_stock_alerts(id) ON DELETE CASCADE,
+    channel VARCHAR(50) NOT NULL,
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (alert_id, channel)
+);
+
 -- Create view for menu items with category information
 CREATE VIEW menu_items_with_category AS
 SELECT
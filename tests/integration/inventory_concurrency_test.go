@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestDB connects to the database named by TEST_DATABASE_URL, which must
+// already have the migrations applied. Tests are skipped when it is not set.
+func setupTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping database integration test")
+	}
+
+	dbConn, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, dbConn.Ping())
+
+	// Enough connections for every goroutine to hold its own transaction
+	dbConn.SetMaxOpenConns(50)
+
+	return dbConn
+}
+
+// createStockedMenuItem inserts a user and a menu item with the given stock
+// level, all of it at the selling location
+func createStockedMenuItem(t *testing.T, dbConn *sql.DB, stock int) (menuItemID, userID string) {
+	suffix := uuid.New().String()[:8]
+
+	err := dbConn.QueryRow(`
+		INSERT INTO users (username, email, password_hash, role, first_name, last_name)
+		VALUES ($1, $2, 'x', 'manager', 'Test', 'User') RETURNING id`,
+		"user"+suffix, suffix+"@example.com").Scan(&userID)
+	require.NoError(t, err)
+
+	var categoryID string
+	err = dbConn.QueryRow(`INSERT INTO categories (name) VALUES ($1) RETURNING id`, "Category "+suffix).Scan(&categoryID)
+	require.NoError(t, err)
+
+	err = dbConn.QueryRow(`
+		INSERT INTO menu_items (name, category_id, price, cost, is_available)
+		VALUES ($1, $2, 25000, 10000, true) RETURNING id`,
+		"Croissant "+suffix, categoryID).Scan(&menuItemID)
+	require.NoError(t, err)
+
+	_, err = dbConn.Exec(`INSERT INTO inventory (menu_item_id, current_stock) VALUES ($1, $2)`, menuItemID, stock)
+	require.NoError(t, err)
+
+	// The stock is held at the selling location, where sales take it from
+	_, err = dbConn.Exec(`
+		INSERT INTO stock_balances (location_id, menu_item_id, quantity)
+		SELECT id, $1, $2 FROM stock_locations WHERE is_selling`, menuItemID, stock)
+	require.NoError(t, err)
+
+	return menuItemID, userID
+}
+
+func TestConcurrentStockDeductionsDoNotOversell(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	repo := repositories.NewRepository(dbConn)
+	inventoryService := services.NewInventoryService(repo, repo.UnitOfWork, nil)
+
+	const initialStock = 10
+	const registers = 25
+	menuItemID, userID := createStockedMenuItem(t, dbConn, initialStock)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+
+	for i := 0; i < registers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := inventoryService.UpdateStock(userID, &models.InventoryUpdate{
+				MenuItemID: menuItemID,
+				Quantity:   types.FromDecimal(decimal.NewFromInt(-1)),
+				Reason:     fmt.Sprintf("register %d sale", i),
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Exactly the available stock was sold, never more
+	assert.Equal(t, initialStock, succeeded)
+
+	inventory, err := repo.InventoryRepo.GetInventoryByMenuItem(menuItemID)
+	require.NoError(t, err)
+	assert.True(t, decimal.Decimal(inventory.CurrentStock).IsZero())
+
+	// Every successful sale left exactly one transaction with a consistent before/after pair
+	var transactions, totalOut int
+	err = dbConn.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(previous_stock - current_stock), 0)::int
+		FROM stock_transactions WHERE menu_item_id = $1`, menuItemID).Scan(&transactions, &totalOut)
+	require.NoError(t, err)
+	assert.Equal(t, initialStock, transactions)
+	assert.Equal(t, initialStock, totalOut)
+}
+
+func TestConcurrentStockAdjustmentsAreNotLost(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	repo := repositories.NewRepository(dbConn)
+	inventoryService := services.NewInventoryService(repo, repo.UnitOfWork, nil)
+
+	const initialStock = 100
+	const workers = 40
+	menuItemID, userID := createStockedMenuItem(t, dbConn, initialStock)
+
+	// Half the workers restock, half sell; the net change must be exactly zero
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		quantity := 1
+		if i%2 == 0 {
+			quantity = -1
+		}
+
+		wg.Add(1)
+		go func(quantity int) {
+			defer wg.Done()
+			_, err := inventoryService.UpdateStock(userID, &models.InventoryUpdate{
+				MenuItemID: menuItemID,
+				Quantity:   types.FromDecimal(decimal.NewFromInt(int64(quantity))),
+				Reason:     "concurrency test",
+			})
+			errs <- err
+		}(quantity)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	inventory, err := repo.InventoryRepo.GetInventoryByMenuItem(menuItemID)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(initialStock).Equal(decimal.Decimal(inventory.CurrentStock)))
+}
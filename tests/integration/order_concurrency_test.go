@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openShift opens a cash drawer shift for the user, which completing orders needs
+func openShift(t *testing.T, dbConn *sql.DB, userID string) {
+	_, err := dbConn.Exec(`INSERT INTO cash_shifts (user_id, opening_float) VALUES ($1, 0)`, userID)
+	require.NoError(t, err)
+}
+
+func TestConcurrentOrderCompletionsDoNotOversell(t *testing.T) {
+	dbConn := setupTestDB(t)
+	defer dbConn.Close()
+
+	repo := repositories.NewRepository(dbConn)
+	orderService := services.NewOrderService(repo, repo.UnitOfWork, nil, nil, services.OrderNumberFormat{}, pricing.Rules{}, nil)
+
+	const initialStock = 10
+	const registers = 25
+	menuItemID, userID := createStockedMenuItem(t, dbConn, initialStock)
+	openShift(t, dbConn, userID)
+
+	// Every register rings up the same croissant before any of them takes payment
+	orderIDs := make([]string, registers)
+	for i := range orderIDs {
+		response, err := orderService.CreateOrder(userID, &models.OrderCreate{
+			Items: []models.OrderItemCreate{{MenuItemID: menuItemID, Quantity: 1}},
+		})
+		require.NoError(t, err)
+		orderIDs[i] = response.Data.(*models.OrderWithDetails).ID
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+
+	cash := types.PaymentMethodCash
+	for _, orderID := range orderIDs {
+		wg.Add(1)
+		go func(orderID string) {
+			defer wg.Done()
+			_, err := orderService.CompleteOrder(orderID, userID, &models.OrderUpdate{PaymentMethod: &cash})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(orderID)
+	}
+	wg.Wait()
+
+	// Exactly the available stock was sold, never more
+	assert.Equal(t, initialStock, succeeded)
+
+	var completed int
+	err := dbConn.QueryRow(`
+		SELECT COUNT(*) FROM orders WHERE id::TEXT = ANY($1) AND status = 'completed'`,
+		pq.Array(orderIDs)).Scan(&completed)
+	require.NoError(t, err)
+	assert.Equal(t, initialStock, completed)
+
+	inventory, err := repo.InventoryRepo.GetInventoryByMenuItem(menuItemID)
+	require.NoError(t, err)
+	assert.True(t, decimal.Decimal(inventory.CurrentStock).IsZero())
+
+	var balance string
+	err = dbConn.QueryRow(`
+		SELECT sb.quantity::TEXT FROM stock_balances sb
+		JOIN stock_locations sl ON sl.id = sb.location_id
+		WHERE sl.is_selling AND sb.menu_item_id = $1`, menuItemID).Scan(&balance)
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString(balance).IsZero())
+
+	// No sale ever took the stock below zero
+	var transactions int
+	var lowestStock string
+	err = dbConn.QueryRow(`
+		SELECT COUNT(*), COALESCE(MIN(current_stock), 0)::TEXT
+		FROM stock_transactions WHERE menu_item_id = $1`, menuItemID).Scan(&transactions, &lowestStock)
+	require.NoError(t, err)
+	assert.Equal(t, initialStock, transactions)
+	assert.False(t, decimal.RequireFromString(lowestStock).IsNegative())
+}
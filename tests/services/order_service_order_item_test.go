@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -55,7 +56,7 @@ func TestOrderServiceWithOrderItemRepo(t *testing.T) {
 	mockStockTransactionRepo := new(MockStockTransactionRepo)
 
 	// Create service with the mocked repositories
-	orderService := NewOrderService(mockOrderRepo, mockOrderItemRepo, mockMenuRepo, mockInventoryRepo, mockStockTransactionRepo, nil)
+	orderService := NewOrderService(mockOrderRepo, mockOrderItemRepo, nil, mockMenuRepo, nil, mockInventoryRepo, mockStockTransactionRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, DefaultOrderNumberFormat, pricing.Rules{})
 
 	userID := "test-user-id"
 	orderID := "test-order-id"
@@ -0,0 +1,80 @@
+package availability_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/availability"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func soldOut(id, name string) *models.MenuItemAvailability {
+	return &models.MenuItemAvailability{MenuItemID: id, Name: name, SoldOut: true}
+}
+
+func TestBrokerFansEventsOutToEveryClient(t *testing.T) {
+	broker := availability.NewBroker()
+
+	till, unsubscribeTill := broker.Subscribe()
+	defer unsubscribeTill()
+	kiosk, unsubscribeKiosk := broker.Subscribe()
+	defer unsubscribeKiosk()
+
+	broker.Publish(availability.Event{
+		Type:  availability.EventMenuAvailabilityChanged,
+		Items: []*models.MenuItemAvailability{soldOut("latte", "Latte")},
+	})
+
+	for _, events := range []<-chan availability.Event{till, kiosk} {
+		require.Len(t, events, 1)
+		event := <-events
+		assert.Equal(t, availability.EventMenuAvailabilityChanged, event.Type)
+		require.Len(t, event.Items, 1)
+		assert.Equal(t, "latte", event.Items[0].MenuItemID)
+	}
+}
+
+func TestBrokerSkipsEmptyEvents(t *testing.T) {
+	broker := availability.NewBroker()
+
+	events, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	broker.Publish(availability.Event{Type: availability.EventMenuAvailabilityChanged})
+	assert.Empty(t, events)
+}
+
+func TestBrokerDropsEventsForSlowClients(t *testing.T) {
+	broker := availability.NewBroker()
+
+	events, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	// Publishing never blocks, even once the client has stopped reading
+	for i := 0; i < 100; i++ {
+		broker.Publish(availability.Event{
+			Type:  availability.EventMenuAvailabilityChanged,
+			Items: []*models.MenuItemAvailability{soldOut("latte", "Latte")},
+		})
+	}
+	assert.Less(t, len(events), 100)
+}
+
+func TestBrokerCloseEndsSubscriptions(t *testing.T) {
+	broker := availability.NewBroker()
+
+	events, unsubscribe := broker.Subscribe()
+	broker.Close()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// Unsubscribing after close is harmless
+	unsubscribe()
+
+	var nilBroker *availability.Broker
+	assert.NotPanics(t, func() {
+		nilBroker.Publish(availability.Event{Items: []*models.MenuItemAvailability{soldOut("latte", "Latte")}})
+	})
+}
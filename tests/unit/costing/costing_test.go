@@ -0,0 +1,50 @@
+package costing_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/costing"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func cost(value string) *decimal.Decimal {
+	d := dec(value)
+	return &d
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		previousStock string
+		averageCost   string
+		quantity      string
+		receivedCost  *decimal.Decimal
+		wantUnit      string
+		wantTotal     string
+		wantAverage   string
+	}{
+		{"delivery blends into the average", "1000", "120", "2000", cost("150"), "150", "300000", "140"},
+		{"first delivery sets the average", "0", "0", "500", cost("80"), "80", "40000", "80"},
+		{"delivery onto negative stock sets the average", "-10", "100", "50", cost("90"), "90", "4500", "90"},
+		{"sale goes out at the average", "2500", "140", "-18", nil, "140", "-2520", "140"},
+		{"restock without a cost comes in at the average", "40", "12.5", "10", nil, "12.5", "125", "12.5"},
+		{"outgoing stock ignores a received cost", "40", "12.5", "-10", cost("99"), "12.5", "-125", "12.5"},
+		{"average weighs each cost by quantity", "3", "1", "1", cost("2"), "2", "2", "1.25"},
+		{"average rounded to the cost scale", "2", "1", "1", cost("2"), "2", "2", "1.3333"},
+		{"value rounded to money", "3", "1.3333", "-1", nil, "1.3333", "-1.33", "1.3333"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := costing.Value(dec(tt.previousStock), dec(tt.averageCost), dec(tt.quantity), tt.receivedCost)
+			assert.True(t, got.UnitCost.Equal(dec(tt.wantUnit)), "unit cost: got %s, want %s", got.UnitCost, tt.wantUnit)
+			assert.True(t, got.TotalCost.Equal(dec(tt.wantTotal)), "total cost: got %s, want %s", got.TotalCost, tt.wantTotal)
+			assert.True(t, got.AverageCost.Equal(dec(tt.wantAverage)), "average cost: got %s, want %s", got.AverageCost, tt.wantAverage)
+		})
+	}
+}
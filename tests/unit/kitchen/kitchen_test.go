@@ -0,0 +1,122 @@
+package kitchen_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/kitchen"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextStatus(t *testing.T) {
+	next, err := kitchen.NextStatus(types.KitchenStatusQueued)
+	require.NoError(t, err)
+	assert.Equal(t, types.KitchenStatusPreparing, next)
+
+	next, err = kitchen.NextStatus(types.KitchenStatusPreparing)
+	require.NoError(t, err)
+	assert.Equal(t, types.KitchenStatusReady, next)
+
+	next, err = kitchen.NextStatus(types.KitchenStatusReady)
+	require.NoError(t, err)
+	assert.Equal(t, types.KitchenStatusServed, next)
+
+	_, err = kitchen.NextStatus(types.KitchenStatusServed)
+	assert.Error(t, err, "a served line has nowhere left to go")
+
+	_, err = kitchen.NextStatus("cooking")
+	assert.Error(t, err)
+}
+
+func TestCheckBump(t *testing.T) {
+	assert.NoError(t, kitchen.CheckBump(types.KitchenStatusQueued, types.KitchenStatusPreparing))
+	assert.NoError(t, kitchen.CheckBump(types.KitchenStatusQueued, types.KitchenStatusReady), "stages may be skipped")
+	assert.NoError(t, kitchen.CheckBump(types.KitchenStatusPreparing, types.KitchenStatusServed))
+
+	assert.Error(t, kitchen.CheckBump(types.KitchenStatusReady, types.KitchenStatusPreparing), "lines never move back")
+	assert.Error(t, kitchen.CheckBump(types.KitchenStatusReady, types.KitchenStatusReady))
+	assert.Error(t, kitchen.CheckBump(types.KitchenStatusQueued, "cooking"))
+}
+
+func ticket(id string, stationID *string) *models.KitchenTicket {
+	return &models.KitchenTicket{ID: id, StationID: stationID, Status: types.KitchenStatusQueued}
+}
+
+func TestBrokerRoutesTicketsToStations(t *testing.T) {
+	bar, kitchenStation := "bar", "kitchen"
+	broker := kitchen.NewBroker()
+
+	barEvents, unsubscribeBar := broker.Subscribe(bar)
+	defer unsubscribeBar()
+	kitchenEvents, unsubscribeKitchen := broker.Subscribe(kitchenStation)
+	defer unsubscribeKitchen()
+	allEvents, unsubscribeAll := broker.Subscribe("")
+	defer unsubscribeAll()
+
+	broker.Publish(kitchen.Event{
+		Type: kitchen.EventTicketsQueued,
+		Tickets: []*models.KitchenTicket{
+			ticket("latte", &bar),
+			ticket("toast", &kitchenStation),
+			ticket("special", nil),
+		},
+	})
+
+	event := <-barEvents
+	assert.Equal(t, kitchen.EventTicketsQueued, event.Type)
+	require.Len(t, event.Tickets, 2)
+	assert.Equal(t, "latte", event.Tickets[0].ID)
+	assert.Equal(t, "special", event.Tickets[1].ID, "unrouted lines show on every station")
+
+	event = <-kitchenEvents
+	require.Len(t, event.Tickets, 2)
+	assert.Equal(t, "toast", event.Tickets[0].ID)
+	assert.Equal(t, "special", event.Tickets[1].ID)
+
+	event = <-allEvents
+	assert.Len(t, event.Tickets, 3)
+
+	// A bar-only change does not reach the kitchen display
+	broker.Publish(kitchen.Event{Type: kitchen.EventTicketUpdated, Tickets: []*models.KitchenTicket{ticket("latte", &bar)}})
+	assert.Len(t, barEvents, 1)
+	assert.Len(t, kitchenEvents, 0)
+	assert.Len(t, allEvents, 1)
+}
+
+func TestBrokerUnsubscribeAndClose(t *testing.T) {
+	broker := kitchen.NewBroker()
+
+	events, unsubscribe := broker.Subscribe("")
+	unsubscribe()
+	unsubscribe() // Safe to call twice
+
+	_, open := <-events
+	assert.False(t, open)
+
+	broker.Publish(kitchen.Event{Type: kitchen.EventTicketsQueued, Tickets: []*models.KitchenTicket{ticket("latte", nil)}})
+
+	other, unsubscribeOther := broker.Subscribe("")
+	broker.Close()
+	_, open = <-other
+	assert.False(t, open)
+	unsubscribeOther() // Unsubscribing after Close does nothing
+
+	// Publishing without a broker is a no-op
+	var disabled *kitchen.Broker
+	disabled.Publish(kitchen.Event{Type: kitchen.EventTicketsQueued, Tickets: []*models.KitchenTicket{ticket("latte", nil)}})
+}
+
+func TestBrokerDropsEventsForSlowDisplays(t *testing.T) {
+	broker := kitchen.NewBroker()
+	events, unsubscribe := broker.Subscribe("")
+	defer unsubscribe()
+
+	// Publishing never blocks, even when nobody is reading
+	for i := 0; i < 100; i++ {
+		broker.Publish(kitchen.Event{Type: kitchen.EventTicketUpdated, Tickets: []*models.KitchenTicket{ticket("latte", nil)}})
+	}
+
+	assert.Less(t, len(events), 100)
+}
@@ -0,0 +1,102 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/notify"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lowMilkAlert() *models.LowStockAlert {
+	return &models.LowStockAlert{
+		ID:           "alert-1",
+		ItemName:     "Milk",
+		Unit:         "ml",
+		CurrentStock: types.FromDecimal(decimal.NewFromInt(800)),
+		MinimumStock: types.FromDecimal(decimal.NewFromInt(1000)),
+		RaisedAt:     time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestMessageDescribesTheAlert(t *testing.T) {
+	alert := lowMilkAlert()
+
+	assert.Equal(t, "Low stock: Milk", notify.Subject(alert))
+	assert.Equal(t, "Milk is running low.\n\n"+
+		"Current stock: 800 ml\n"+
+		"Minimum stock: 1000 ml\n"+
+		"Raised at: 2025-03-14 09:30\n", notify.Body(alert))
+}
+
+func TestSubjectStaysOnOneLine(t *testing.T) {
+	alert := lowMilkAlert()
+	alert.ItemName = "Milk\r\nBcc: someone@example.com"
+
+	assert.Equal(t, "Low stock: Milk  Bcc: someone@example.com", notify.Subject(alert))
+}
+
+func TestWebhookPostsSignedAlert(t *testing.T) {
+	var body []byte
+	var signature, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(notify.SignatureHeader)
+		contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(server.URL, "s3cret")
+	require.NoError(t, channel.Send(context.Background(), lowMilkAlert()))
+
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, notify.Sign("s3cret", body), signature)
+
+	var payload struct {
+		Event   string `json:"event"`
+		Subject string `json:"subject"`
+		Alert   struct {
+			ItemName string `json:"item_name"`
+		} `json:"alert"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, notify.EventLowStock, payload.Event)
+	assert.Equal(t, "Low stock: Milk", payload.Subject)
+	assert.Equal(t, "Milk", payload.Alert.ItemName)
+}
+
+func TestWebhookWithoutSecretIsNotSigned(t *testing.T) {
+	signed := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header[notify.SignatureHeader]
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(server.URL, "")
+	require.NoError(t, channel.Send(context.Background(), lowMilkAlert()))
+
+	assert.False(t, signed)
+}
+
+func TestWebhookFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(server.URL, "")
+	err := channel.Send(context.Background(), lowMilkAlert())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
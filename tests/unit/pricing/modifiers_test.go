@@ -0,0 +1,61 @@
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectModifiers(t *testing.T) {
+	groups := []pricing.ModifierGroup{
+		{ID: "size", Name: "Size", MinSelect: 1, MaxSelect: 1, Options: []pricing.ModifierOption{
+			{ID: "regular", Name: "Regular", PriceDelta: dec("0"), IsAvailable: true},
+			{ID: "large", Name: "Large", PriceDelta: dec("5000"), IsAvailable: true},
+		}},
+		{ID: "addons", Name: "Add-ons", MinSelect: 0, MaxSelect: 2, Options: []pricing.ModifierOption{
+			{ID: "shot", Name: "Extra shot", PriceDelta: dec("6000"), IsAvailable: true},
+			{ID: "oat", Name: "Oat milk", PriceDelta: dec("7000"), IsAvailable: true},
+			{ID: "syrup", Name: "Vanilla syrup", PriceDelta: dec("4000"), IsAvailable: false},
+		}},
+	}
+
+	t.Run("size and add-ons add up", func(t *testing.T) {
+		selected, delta, err := pricing.SelectModifiers(groups, []string{"oat", "large", "shot"})
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+
+		assert.Equal(t, "Large", selected[0].OptionName)
+		assert.Equal(t, "Add-ons", selected[1].GroupName)
+		assert.True(t, delta.Equal(dec("18000")))
+	})
+
+	t.Run("a required group must be chosen", func(t *testing.T) {
+		_, _, err := pricing.SelectModifiers(groups, []string{"shot"})
+		assert.EqualError(t, err, "choose at least 1 from Size")
+	})
+
+	t.Run("too many options in a group", func(t *testing.T) {
+		_, _, err := pricing.SelectModifiers(groups, []string{"regular", "large"})
+		assert.EqualError(t, err, "choose at most 1 from Size")
+	})
+
+	t.Run("unavailable, foreign and repeated options are rejected", func(t *testing.T) {
+		_, _, err := pricing.SelectModifiers(groups, []string{"regular", "syrup"})
+		assert.Error(t, err)
+
+		_, _, err = pricing.SelectModifiers(groups, []string{"regular", "cheese"})
+		assert.Error(t, err)
+
+		_, _, err = pricing.SelectModifiers(groups, []string{"regular", "shot", "shot"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no groups and no options", func(t *testing.T) {
+		selected, delta, err := pricing.SelectModifiers(nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, selected)
+		assert.True(t, delta.IsZero())
+	})
+}
@@ -0,0 +1,93 @@
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules := pricing.Rules{
+		TaxRate:           dec("11"),
+		ServiceChargeRate: dec("5"),
+		RoundingIncrement: dec("100"),
+		RoundingMode:      pricing.RoundingNearest,
+	}
+
+	t.Run("service charge and tax without discount", func(t *testing.T) {
+		breakdown, err := rules.Apply(dec("100000"), pricing.Discount{})
+		require.NoError(t, err)
+
+		assert.True(t, breakdown.ServiceCharge.Equal(dec("5000")))
+		// PPN is levied on the subtotal plus service charge
+		assert.True(t, breakdown.Tax.Equal(dec("11550")))
+		assert.True(t, breakdown.Total.Equal(dec("116600")))
+		assert.True(t, breakdown.Rounding.Equal(dec("50")))
+	})
+
+	t.Run("percentage discount comes off before charges", func(t *testing.T) {
+		breakdown, err := rules.Apply(dec("50000"), pricing.Discount{Percent: dec("10")})
+		require.NoError(t, err)
+
+		assert.True(t, breakdown.Discount.Equal(dec("5000")))
+		assert.True(t, breakdown.ServiceCharge.Equal(dec("2250")))
+		assert.True(t, breakdown.Tax.Equal(dec("5197.5")))
+		assert.True(t, breakdown.Total.Equal(dec("52400")))
+		assert.True(t, breakdown.Rounding.Equal(dec("-47.5")))
+	})
+
+	t.Run("fixed discount", func(t *testing.T) {
+		noCharges := pricing.Rules{}
+		breakdown, err := noCharges.Apply(dec("45000"), pricing.Discount{Amount: dec("7500")})
+		require.NoError(t, err)
+
+		assert.True(t, breakdown.Discount.Equal(dec("7500")))
+		assert.True(t, breakdown.Total.Equal(dec("37500")))
+		assert.True(t, breakdown.Rounding.IsZero())
+	})
+
+	t.Run("rounding modes", func(t *testing.T) {
+		up := rules
+		up.RoundingMode = pricing.RoundingUp
+		breakdown, err := up.Apply(dec("100000"), pricing.Discount{})
+		require.NoError(t, err)
+		assert.True(t, breakdown.Total.Equal(dec("116600")))
+
+		down := rules
+		down.RoundingMode = pricing.RoundingDown
+		breakdown, err = down.Apply(dec("100000"), pricing.Discount{})
+		require.NoError(t, err)
+		assert.True(t, breakdown.Total.Equal(dec("116500")))
+	})
+
+	t.Run("invalid discounts", func(t *testing.T) {
+		_, err := rules.Apply(dec("10000"), pricing.Discount{Amount: dec("20000")})
+		assert.Error(t, err)
+
+		_, err = rules.Apply(dec("10000"), pricing.Discount{Percent: dec("10"), Amount: dec("1000")})
+		assert.Error(t, err)
+
+		_, err = rules.Apply(dec("10000"), pricing.Discount{Percent: dec("150")})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := pricing.ParseRules("11", "5", "100", "up")
+	require.NoError(t, err)
+	assert.True(t, rules.TaxRate.Equal(dec("11")))
+	assert.Equal(t, pricing.RoundingUp, rules.RoundingMode)
+
+	_, err = pricing.ParseRules("abc", "0", "0", "nearest")
+	assert.Error(t, err)
+
+	_, err = pricing.ParseRules("11", "0", "0", "sideways")
+	assert.Error(t, err)
+}
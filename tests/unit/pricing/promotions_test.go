@@ -0,0 +1,115 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePromotions(t *testing.T) {
+	const (
+		coffee    = "coffee-category"
+		pastry    = "pastry-category"
+		latte     = "latte"
+		espresso  = "espresso"
+		croissant = "croissant"
+	)
+
+	lines := []pricing.Line{
+		{MenuItemID: latte, CategoryID: coffee, Quantity: 2, UnitPrice: dec("30000")},
+		{MenuItemID: espresso, CategoryID: coffee, Quantity: 1, UnitPrice: dec("20000")},
+		{MenuItemID: croissant, CategoryID: pastry, Quantity: 1, UnitPrice: dec("25000")},
+	}
+	// 15:30 local time
+	at := time.Date(2026, 3, 2, 15, 30, 0, 0, time.Local)
+
+	t.Run("percentage off a category", func(t *testing.T) {
+		applied := pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "p1", Name: "Coffee 10%", Type: types.PromotionTypePercentage, Value: dec("10"), TargetType: types.PromotionTargetCategory, TargetID: coffee},
+		}, lines, at)
+
+		require.Len(t, applied, 1)
+		assert.True(t, applied[0].Amount.Equal(dec("8000")))
+	})
+
+	t.Run("buy x get y gives the cheapest units free", func(t *testing.T) {
+		applied := pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "p1", Name: "Buy 2 coffees get 1", Type: types.PromotionTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1, TargetType: types.PromotionTargetCategory, TargetID: coffee},
+		}, lines, at)
+
+		require.Len(t, applied, 1)
+		assert.True(t, applied[0].Amount.Equal(dec("20000")))
+	})
+
+	t.Run("fixed amount is capped at the targeted lines", func(t *testing.T) {
+		applied := pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "p1", Name: "Croissant voucher", Type: types.PromotionTypeFixedAmount, Value: dec("40000"), TargetType: types.PromotionTargetMenuItem, TargetID: croissant},
+		}, lines, at)
+
+		require.Len(t, applied, 1)
+		assert.True(t, applied[0].Amount.Equal(dec("25000")))
+	})
+
+	t.Run("minimum spend and time windows", func(t *testing.T) {
+		ended := at.Add(-time.Hour)
+		applied := pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "min", Name: "Big spender", Type: types.PromotionTypeFixedAmount, Value: dec("5000"), MinSpend: dec("200000")},
+			{ID: "happy", Name: "Morning", Type: types.PromotionTypePercentage, Value: dec("10"), DailyStartTime: "07:00", DailyEndTime: "10:00"},
+			{ID: "ended", Name: "Last week", Type: types.PromotionTypePercentage, Value: dec("10"), EndsAt: &ended},
+		}, lines, at)
+		assert.Empty(t, applied)
+
+		// A window running past midnight
+		late := time.Date(2026, 3, 2, 23, 15, 0, 0, time.Local)
+		applied = pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "night", Name: "Late night", Type: types.PromotionTypeFixedAmount, Value: dec("5000"), DailyStartTime: "22:00", DailyEndTime: "02:00"},
+		}, lines, late)
+		require.Len(t, applied, 1)
+	})
+
+	t.Run("stackable promotions combine unless a single one is better", func(t *testing.T) {
+		stackable := []pricing.Promotion{
+			{ID: "s1", Name: "Coffee 10%", Type: types.PromotionTypePercentage, Value: dec("10"), TargetType: types.PromotionTargetCategory, TargetID: coffee, IsStackable: true},
+			{ID: "s2", Name: "Rp5.000 off", Type: types.PromotionTypeFixedAmount, Value: dec("5000"), IsStackable: true},
+		}
+
+		applied := pricing.EvaluatePromotions(append(stackable,
+			pricing.Promotion{ID: "x", Name: "Rp10.000 off", Type: types.PromotionTypeFixedAmount, Value: dec("10000")},
+		), lines, at)
+		require.Len(t, applied, 2)
+		assert.True(t, pricing.TotalDiscount(applied).Equal(dec("13000")))
+
+		applied = pricing.EvaluatePromotions(append(stackable,
+			pricing.Promotion{ID: "x", Name: "Rp20.000 off", Type: types.PromotionTypeFixedAmount, Value: dec("20000")},
+		), lines, at)
+		require.Len(t, applied, 1)
+		assert.Equal(t, "x", applied[0].PromotionID)
+	})
+
+	t.Run("ties go to the higher priority", func(t *testing.T) {
+		applied := pricing.EvaluatePromotions([]pricing.Promotion{
+			{ID: "low", Name: "Low", Type: types.PromotionTypeFixedAmount, Value: dec("5000"), Priority: 1},
+			{ID: "high", Name: "High", Type: types.PromotionTypeFixedAmount, Value: dec("5000"), Priority: 5},
+		}, lines, at)
+
+		require.Len(t, applied, 1)
+		assert.Equal(t, "high", applied[0].PromotionID)
+	})
+}
+
+func TestRules_ApplyWithPromotion(t *testing.T) {
+	rules := pricing.Rules{TaxRate: dec("10")}
+
+	// The manual percentage comes off what the promotions left
+	breakdown, err := rules.Apply(dec("100000"), pricing.Discount{Promotion: dec("20000"), Percent: dec("10")})
+	require.NoError(t, err)
+	assert.True(t, breakdown.Discount.Equal(dec("28000")))
+	assert.True(t, breakdown.Total.Equal(dec("79200")))
+
+	_, err = rules.Apply(dec("100000"), pricing.Discount{Promotion: dec("90000"), Amount: dec("20000")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,19 @@
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProrate(t *testing.T) {
+	// A Rp30.000 line out of a Rp100.000 subtotal that was paid Rp94.050
+	assert.True(t, pricing.Prorate(dec("94050"), dec("30000"), dec("100000")).Equal(dec("28215")))
+
+	// Shares are rounded to whole cents
+	assert.True(t, pricing.Prorate(dec("100"), dec("1"), dec("3")).Equal(dec("33.33")))
+
+	// Nothing to share out of an empty whole
+	assert.True(t, pricing.Prorate(dec("100"), dec("1"), dec("0")).IsZero())
+}
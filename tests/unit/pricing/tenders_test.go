@@ -0,0 +1,81 @@
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/pricing"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettleTenders(t *testing.T) {
+	t.Run("cash and QRIS split with change from the cash", func(t *testing.T) {
+		settled, change, err := pricing.SettleTenders(dec("87500"), []pricing.Tender{
+			{Method: types.PaymentMethodQris, Amount: dec("40000"), Reference: "QR-123"},
+			{Method: types.PaymentMethodCash, Amount: dec("50000")},
+		})
+		require.NoError(t, err)
+		require.Len(t, settled, 2)
+
+		assert.True(t, change.Equal(dec("2500")))
+		assert.True(t, settled[0].Change.IsZero())
+		assert.True(t, settled[1].Change.Equal(dec("2500")))
+		assert.Equal(t, types.PaymentMethodSplit, pricing.PaymentMethodOf(settled))
+	})
+
+	t.Run("exact single tender", func(t *testing.T) {
+		settled, change, err := pricing.SettleTenders(dec("25000"), []pricing.Tender{
+			{Method: types.PaymentMethodCard, Amount: dec("25000")},
+		})
+		require.NoError(t, err)
+		assert.True(t, change.IsZero())
+		assert.Equal(t, types.PaymentMethodCard, pricing.PaymentMethodOf(settled))
+	})
+
+	t.Run("change spread over several cash tenders", func(t *testing.T) {
+		settled, change, err := pricing.SettleTenders(dec("10000"), []pricing.Tender{
+			{Method: types.PaymentMethodCash, Amount: dec("20000")},
+			{Method: types.PaymentMethodCash, Amount: dec("5000")},
+		})
+		require.NoError(t, err)
+		assert.True(t, change.Equal(dec("15000")))
+		assert.True(t, settled[1].Change.Equal(dec("5000")))
+		assert.True(t, settled[0].Change.Equal(dec("10000")))
+		assert.Equal(t, types.PaymentMethodCash, pricing.PaymentMethodOf(settled))
+	})
+
+	t.Run("rejected tenders", func(t *testing.T) {
+		_, _, err := pricing.SettleTenders(dec("50000"), []pricing.Tender{
+			{Method: types.PaymentMethodCash, Amount: dec("20000")},
+			{Method: types.PaymentMethodCard, Amount: dec("20000")},
+		})
+		assert.Error(t, err, "payments must cover the total")
+
+		_, _, err = pricing.SettleTenders(dec("50000"), []pricing.Tender{
+			{Method: types.PaymentMethodCard, Amount: dec("60000")},
+		})
+		assert.Error(t, err, "card payments cannot be overpaid")
+
+		_, _, err = pricing.SettleTenders(dec("50000"), nil)
+		assert.Error(t, err)
+
+		_, _, err = pricing.SettleTenders(dec("50000"), []pricing.Tender{
+			{Method: "voucher", Amount: dec("50000")},
+		})
+		assert.Error(t, err)
+
+		_, _, err = pricing.SettleTenders(dec("50000"), []pricing.Tender{
+			{Method: types.PaymentMethodCash, Amount: dec("60000")},
+			{Method: types.PaymentMethodCash, Amount: dec("0")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("fully discounted order needs no payment", func(t *testing.T) {
+		settled, change, err := pricing.SettleTenders(dec("0"), nil)
+		require.NoError(t, err)
+		assert.Empty(t, settled)
+		assert.True(t, change.IsZero())
+	})
+}
@@ -0,0 +1,231 @@
+package receipt_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -update to rewrite the golden files after an intended layout change
+var update = flag.Bool("update", false, "update golden files")
+
+func amount(value string) types.DecimalText {
+	return types.DecimalText(decimal.RequireFromString(value))
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+var settings = receipt.Settings{
+	StoreName: "Kopi Kita Café",
+	Address:   "Jl. Braga No. 12\nBandung 40111",
+	NPWP:      "01.234.567.8-901.000",
+	Footer:    "Terima kasih atas kunjungan Anda\nWifi: kopikita — pass: aren123",
+}
+
+// completedOrder is a dine-in order with modifiers, notes, a promotion and a
+// manual discount, service charge, PPN, rounding and a split payment
+func completedOrder() *models.OrderWithDetails {
+	completedAt := time.Date(2026, 10, 16, 14, 35, 0, 0, time.UTC)
+	guests := 3
+	change := amount("8100")
+
+	return &models.OrderWithDetails{
+		OrderNumber:         "ORD-20261016-0042",
+		UserName:            "Siti Rahma",
+		Status:              types.OrderStatusCompleted,
+		OrderType:           types.OrderTypeDineIn,
+		TableName:           strPtr("T4"),
+		GuestCount:          &guests,
+		CreatedAt:           completedAt.Add(-40 * time.Minute),
+		CompletedAt:         &completedAt,
+		SubtotalAmount:      amount("106000"),
+		DiscountAmount:      amount("10000"),
+		ServiceChargeRate:   amount("5"),
+		ServiceChargeAmount: amount("4800"),
+		TaxRate:             amount("11"),
+		TaxAmount:           amount("11088"),
+		RoundingAmount:      amount("12"),
+		TotalAmount:         amount("111900"),
+		Items: []models.OrderItemWithDetails{
+			{
+				MenuItemName: "Es Kopi Susu Gula Aren",
+				Quantity:     2,
+				UnitPrice:    amount("28000"),
+				TotalPrice:   amount("56000"),
+				Notes:        strPtr("extra ice"),
+				Modifiers: []models.OrderItemModifier{
+					{GroupName: "Size", OptionName: "Large", PriceDelta: amount("5000")},
+					{GroupName: "Sugar", OptionName: "Less sugar", PriceDelta: amount("0")},
+				},
+			},
+			{
+				MenuItemName: "Croissant Almond Butter with Chocolate Drizzle",
+				Quantity:     1,
+				UnitPrice:    amount("32000"),
+				TotalPrice:   amount("32000"),
+			},
+			{
+				MenuItemName: "Americano",
+				Quantity:     1,
+				UnitPrice:    amount("18000"),
+				TotalPrice:   amount("18000"),
+			},
+		},
+		Promotions: []models.OrderPromotion{
+			{PromotionName: "Happy Hour 10%", DiscountAmount: amount("5600")},
+		},
+		Payments: []models.OrderPayment{
+			{PaymentMethod: types.PaymentMethodQris, Amount: amount("50000"), Reference: strPtr("QR-8812-0042")},
+			{PaymentMethod: types.PaymentMethodCash, Amount: amount("70000"), ChangeAmount: amount("8100")},
+		},
+		ChangeAmount: &change,
+	}
+}
+
+// takeawayOrder is a plain takeaway order paid by card, before split tenders
+// were recorded
+func takeawayOrder() *models.OrderWithDetails {
+	createdAt := time.Date(2026, 10, 16, 8, 5, 0, 0, time.UTC)
+	card := types.PaymentMethodCard
+
+	return &models.OrderWithDetails{
+		OrderNumber:    "ORD-20261016-0001",
+		Status:         types.OrderStatusCompleted,
+		OrderType:      types.OrderTypeTakeaway,
+		CreatedAt:      createdAt,
+		SubtotalAmount: amount("12500.50"),
+		TotalAmount:    amount("12500.50"),
+		PaymentMethod:  &card,
+		Items: []models.OrderItemWithDetails{
+			{MenuItemName: "Teh Tarik", Quantity: 1, UnitPrice: amount("12500.50"), TotalPrice: amount("12500.50")},
+		},
+	}
+}
+
+// assertGolden compares got with the golden file, rewriting it under -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing; run go test with -update to create it")
+	assert.True(t, bytes.Equal(want, got), "%s does not match the golden file:\n%q\nwant:\n%q", name, got, want)
+}
+
+func TestRender_Formats(t *testing.T) {
+	cases := []struct {
+		format      receipt.Format
+		golden      string
+		contentType string
+	}{
+		{receipt.FormatText, "completed_order.txt", "text/plain; charset=utf-8"},
+		{receipt.FormatESCPOS58, "completed_order_58mm.bin", "application/octet-stream"},
+		{receipt.FormatESCPOS80, "completed_order_80mm.bin", "application/octet-stream"},
+		{receipt.FormatPDF, "completed_order.pdf", "application/pdf"},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			document, err := receipt.Render(completedOrder(), settings, tc.format, receipt.Options{Copy: 1})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.contentType, document.ContentType)
+			assert.Equal(t, 1, document.Copy)
+			assertGolden(t, tc.golden, document.Body)
+		})
+	}
+}
+
+func TestRender_Reprint(t *testing.T) {
+	document, err := receipt.Render(completedOrder(), settings, receipt.FormatText, receipt.Options{
+		Copy:      3,
+		PrintedAt: time.Date(2026, 10, 17, 9, 10, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(document.Body), "*** REPRINT #2 ***")
+	assertGolden(t, "reprint.txt", document.Body)
+}
+
+func TestRender_TakeawayWithoutSettings(t *testing.T) {
+	document, err := receipt.Render(takeawayOrder(), receipt.Settings{}, receipt.FormatText, receipt.Options{Copy: 1})
+	require.NoError(t, err)
+
+	assertGolden(t, "takeaway_order.txt", document.Body)
+}
+
+func TestRender_ESCPOSFraming(t *testing.T) {
+	document, err := receipt.Render(completedOrder(), settings, receipt.FormatESCPOS58, receipt.Options{Copy: 1})
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(document.Body, []byte{0x1b, 0x40}), "starts by initializing the printer")
+	assert.True(t, bytes.HasSuffix(document.Body, []byte{0x1d, 0x56, 0x01}), "ends by cutting the paper")
+
+	for _, row := range bytes.Split(document.Body, []byte("\n")) {
+		printable := regexp.MustCompile(`\x1b@|\x1b[Ed].|\x1d[!V].`).ReplaceAll(row, nil)
+		assert.LessOrEqual(t, len(printable), 32, "row %q overflows a 58mm roll", printable)
+		for _, b := range printable {
+			assert.True(t, b >= 0x20 && b <= 0x7e, "row %q has a byte the printer cannot print", printable)
+		}
+	}
+}
+
+func TestRender_PDFCrossReference(t *testing.T) {
+	document, err := receipt.Render(completedOrder(), settings, receipt.FormatPDF, receipt.Options{Copy: 1})
+	require.NoError(t, err)
+	body := document.Body
+
+	require.True(t, bytes.HasPrefix(body, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(body, []byte("%%EOF\n")))
+
+	// Every object offset in the xref table points at that object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(body)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(body[xref:], []byte("xref\n")))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(body[xref:], -1)
+	require.Len(t, offsets, 6)
+	for i, offset := range offsets {
+		position, err := strconv.Atoi(string(offset[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(body[position:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d offset", i+1)
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := receipt.Render(completedOrder(), settings, "html", receipt.Options{Copy: 1})
+	assert.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := receipt.ParseFormat("ESCPOS58")
+	require.NoError(t, err)
+	assert.Equal(t, receipt.FormatESCPOS58, format)
+
+	format, err = receipt.ParseFormat("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, receipt.FormatPDF, format)
+
+	_, err = receipt.ParseFormat("escpos")
+	assert.Error(t, err)
+}
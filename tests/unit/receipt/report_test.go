@@ -0,0 +1,78 @@
+package receipt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zReport is the twelfth Z report of a day with refunds, a voided order and
+// two cashiers
+func zReport() *models.SalesReport {
+	reportNumber := 12
+	periodStart := time.Date(2026, 10, 15, 22, 5, 0, 0, time.UTC)
+	periodEnd := time.Date(2026, 10, 16, 22, 10, 0, 0, time.UTC)
+
+	return &models.SalesReport{
+		ID:               "0b6f3c1e-2a4d-4f8e-9b7a-5c3d2e1f0a9b",
+		ReportType:       types.SalesReportTypeZ,
+		ReportNumber:     &reportNumber,
+		PeriodStart:      &periodStart,
+		PeriodEnd:        periodEnd,
+		GeneratedAt:      periodEnd,
+		OrderCount:       57,
+		GrossSales:       amount("3450000"),
+		DiscountAmount:   amount("125000"),
+		ServiceCharge:    amount("166250"),
+		TaxAmount:        amount("384037.50"),
+		RoundingAmount:   amount("-287.50"),
+		TotalSales:       amount("3875000"),
+		RefundCount:      2,
+		RefundAmount:     amount("55500"),
+		RefundTax:        amount("5500"),
+		CancelledCount:   3,
+		VoidedCount:      1,
+		VoidedAmount:     amount("42000"),
+		NetSales:         amount("3440962.50"),
+		FirstOrderNumber: strPtr("ORD-20261016-0001"),
+		LastOrderNumber:  strPtr("ORD-20261016-0057"),
+		PaymentMethods: []models.PaymentMethodTotal{
+			{PaymentMethod: types.PaymentMethodCash, TotalOrders: 31, TotalAmount: amount("1925000")},
+			{PaymentMethod: types.PaymentMethodQris, TotalOrders: 26, TotalAmount: amount("1950000")},
+		},
+		Cashiers: []models.CashierTotal{
+			{CashierName: "Siti Rahma", OrderCount: 40, TotalSales: amount("2710000")},
+			{CashierName: "Budi", OrderCount: 17, TotalSales: amount("1165000")},
+		},
+	}
+}
+
+func TestRenderSalesReport_ZReport(t *testing.T) {
+	document, err := receipt.RenderSalesReport(zReport(), settings, receipt.FormatText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "z-report-0012.txt", document.Filename)
+	assert.Contains(t, string(document.Body), "Z REPORT #0012")
+	assertGolden(t, "z_report.txt", document.Body)
+}
+
+func TestRenderSalesReport_XReport(t *testing.T) {
+	report := zReport()
+	report.ID = ""
+	report.ReportType = types.SalesReportTypeX
+	report.ReportNumber = nil
+	report.PeriodStart = nil
+
+	document, err := receipt.RenderSalesReport(report, receipt.Settings{}, receipt.FormatESCPOS58)
+	require.NoError(t, err)
+
+	assert.Equal(t, "x-report-20261016-221000.bin", document.Filename)
+	assert.Contains(t, string(document.Body), "X REPORT")
+	assert.Contains(t, string(document.Body), "First sale")
+	assert.NotContains(t, string(document.Body), "END OF DAY")
+}
@@ -0,0 +1,311 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRecipeRepo is a mock implementation of RecipeRepo interface
+type MockRecipeRepo struct {
+	mock.Mock
+}
+
+func (m *MockRecipeRepo) GetMenuItemRecipe(menuItemID string) ([]models.RecipeItem, error) {
+	args := m.Called(menuItemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeItem), args.Error(1)
+}
+
+func (m *MockRecipeRepo) SetMenuItemRecipe(menuItemID string, items []models.RecipeItemInput) error {
+	args := m.Called(menuItemID, items)
+	return args.Error(0)
+}
+
+func (m *MockRecipeRepo) GetModifierOptionRecipe(optionID string) ([]models.RecipeItem, error) {
+	args := m.Called(optionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeItem), args.Error(1)
+}
+
+func (m *MockRecipeRepo) SetModifierOptionRecipe(optionID string, items []models.RecipeItemInput) error {
+	args := m.Called(optionID, items)
+	return args.Error(0)
+}
+
+func (m *MockRecipeRepo) CountRecipesByIngredient(ingredientID string) (int, error) {
+	args := m.Called(ingredientID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockIngredientRepo is a mock implementation of IngredientRepo interface
+type MockIngredientRepo struct {
+	mock.Mock
+}
+
+func (m *MockIngredientRepo) CreateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error) {
+	args := m.Called(ingredient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ingredient), args.Error(1)
+}
+
+func (m *MockIngredientRepo) GetIngredient(id string) (*models.Ingredient, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ingredient), args.Error(1)
+}
+
+func (m *MockIngredientRepo) ListIngredients(filter models.IngredientFilter) ([]*models.Ingredient, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ingredient), args.Error(1)
+}
+
+func (m *MockIngredientRepo) UpdateIngredient(ingredient *models.Ingredient) (*models.Ingredient, error) {
+	args := m.Called(ingredient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ingredient), args.Error(1)
+}
+
+func (m *MockIngredientRepo) AdjustIngredientStock(id string, quantity types.DecimalText, userID string) (types.DecimalText, types.DecimalText, error) {
+	args := m.Called(id, quantity, userID)
+	return args.Get(0).(types.DecimalText), args.Get(1).(types.DecimalText), args.Error(2)
+}
+
+func (m *MockIngredientRepo) UpdateIngredientCostPrice(id string, costPrice types.DecimalText) error {
+	args := m.Called(id, costPrice)
+	return args.Error(0)
+}
+
+// MockStockTransactionRepo is a mock implementation of StockTransactionRepo interface
+type MockStockTransactionRepo struct {
+	mock.Mock
+}
+
+func (m *MockStockTransactionRepo) CreateStockTransaction(transaction *models.StockTransaction) (*models.StockTransaction, error) {
+	args := m.Called(transaction)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransaction), args.Error(1)
+}
+
+func (m *MockStockTransactionRepo) GetStockAverageCost(menuItemID string, ingredientID *string) (types.DecimalText, error) {
+	args := m.Called(menuItemID, ingredientID)
+	return args.Get(0).(types.DecimalText), args.Error(1)
+}
+
+func (m *MockStockTransactionRepo) SetStockAverageCost(menuItemID string, ingredientID *string, averageCost types.DecimalText) error {
+	args := m.Called(menuItemID, ingredientID, averageCost)
+	return args.Error(0)
+}
+
+func (m *MockStockTransactionRepo) ListStockTransactions(filter models.StockTransactionFilter) ([]*models.StockTransaction, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockTransaction), args.Error(1)
+}
+
+func (m *MockStockTransactionRepo) ListStockMovedByReference(referenceType, referenceID string) ([]*models.StockMoved, error) {
+	args := m.Called(referenceType, referenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockMoved), args.Error(1)
+}
+
+const (
+	latteID     = "2f0c6a1e-8b3d-4c5e-9f7a-1b2c3d4e5f60"
+	extraShotID = "7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+	beansID     = "0d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a"
+	milkID      = "5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f"
+	stockUserID = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"
+)
+
+// stockOf returns a whole stock quantity
+func stockOf(value int64) types.DecimalText {
+	return types.FromDecimal(decimal.NewFromInt(value))
+}
+
+// stockMatching matches a stock quantity argument by value, whatever its scale
+func stockMatching(value string) interface{} {
+	expected := decimal.RequireFromString(value)
+	return mock.MatchedBy(func(quantity types.DecimalText) bool {
+		return decimal.Decimal(quantity).Equal(expected)
+	})
+}
+
+// withoutRecipes returns a recipe repository in which every item is sold as
+// finished goods
+func withoutRecipes() *MockRecipeRepo {
+	mockRecipeRepo := new(MockRecipeRepo)
+	mockRecipeRepo.On("GetMenuItemRecipe", mock.Anything).Return([]models.RecipeItem{}, nil)
+	mockRecipeRepo.On("GetModifierOptionRecipe", mock.Anything).Return([]models.RecipeItem{}, nil)
+	return mockRecipeRepo
+}
+
+// latteRecipes is a latte made with 18 g of beans and 200 ml of milk, and an
+// extra shot that adds another 18 g of beans
+func latteRecipes() *MockRecipeRepo {
+	mockRecipeRepo := new(MockRecipeRepo)
+	mockRecipeRepo.On("GetMenuItemRecipe", latteID).Return([]models.RecipeItem{
+		{IngredientID: beansID, IngredientName: "Coffee beans", Unit: "g", Quantity: stockOf(18)},
+		{IngredientID: milkID, IngredientName: "Milk", Unit: "ml", Quantity: stockOf(200)},
+	}, nil)
+	mockRecipeRepo.On("GetModifierOptionRecipe", extraShotID).Return([]models.RecipeItem{
+		{IngredientID: beansID, IngredientName: "Coffee beans", Unit: "g", Quantity: stockOf(18)},
+	}, nil)
+	return mockRecipeRepo
+}
+
+// twoLattes is one plain latte and one with an extra shot
+var twoLattes = []models.OrderItemCreate{
+	{MenuItemID: latteID, Quantity: 1},
+	{MenuItemID: latteID, Quantity: 1, ModifierOptionIDs: []string{extraShotID}},
+}
+
+func TestInventoryService_ValidateInventoryForOrder_Ingredients(t *testing.T) {
+	mockInventoryRepo := new(MockInventoryRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo:  mockInventoryRepo,
+		IngredientRepo: mockIngredientRepo,
+		RecipeRepo:     latteRecipes(),
+		StockLotRepo:   consumableLots(),
+	}, nil, nil)
+
+	// Two lattes and an extra shot take 54 g of beans and 400 ml of milk
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(54)}, nil)
+	mockIngredientRepo.On("GetIngredient", milkID).Return(&models.Ingredient{ID: milkID, Name: "Milk", Unit: "ml", CurrentStock: stockOf(1000)}, nil)
+
+	err := inventoryService.ValidateInventoryForOrder(twoLattes)
+	require.NoError(t, err)
+
+	// Items made to a recipe have no finished goods to check
+	mockInventoryRepo.AssertNotCalled(t, "GetInventoryByMenuItem", mock.Anything)
+	mockIngredientRepo.AssertExpectations(t)
+}
+
+func TestInventoryService_ValidateInventoryForOrder_InsufficientIngredient(t *testing.T) {
+	mockIngredientRepo := new(MockIngredientRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo:  new(MockInventoryRepo),
+		IngredientRepo: mockIngredientRepo,
+		RecipeRepo:     latteRecipes(),
+		StockLotRepo:   consumableLots(),
+	}, nil, nil)
+
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(50)}, nil)
+
+	err := inventoryService.ValidateInventoryForOrder(twoLattes)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient inventory for ingredient Coffee beans: required 54 g, available 50 g")
+}
+
+func TestInventoryService_UpdateInventoryAfterOrder_DeductsIngredients(t *testing.T) {
+	mockInventoryRepo := new(MockInventoryRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo:        mockInventoryRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		RecipeRepo:           latteRecipes(),
+		StockLotRepo:         consumableLots(),
+	}, nil, nil)
+
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-54"), stockUserID).Return(stockOf(500), stockOf(446), nil)
+	mockIngredientRepo.On("AdjustIngredientStock", milkID, stockMatching("-400"), stockUserID).Return(stockOf(1000), stockOf(600), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.MenuItemID == "" && transaction.IngredientID != nil &&
+			(*transaction.IngredientID == beansID && transaction.Quantity.Equals(stockOf(-54)) && transaction.CurrentStock.Equals(stockOf(446)) ||
+				*transaction.IngredientID == milkID && transaction.Quantity.Equals(stockOf(-400)) && transaction.CurrentStock.Equals(stockOf(600)))
+	})).Return(&models.StockTransaction{}, nil).Twice()
+
+	err := inventoryService.UpdateInventoryAfterOrder(twoLattes, stockUserID)
+	require.NoError(t, err)
+
+	mockInventoryRepo.AssertNotCalled(t, "AdjustInventoryStock", mock.Anything, mock.Anything, mock.Anything)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+}
+
+// expiredBeans holds stock of coffee beans of which everything but 50 g is in
+// lots that have expired
+func expiredBeans() *MockStockLotRepo {
+	mockStockLotRepo := new(MockStockLotRepo)
+	mockStockLotRepo.On("GetExpiredStock", "", mock.MatchedBy(func(ingredientID *string) bool {
+		return *ingredientID == beansID
+	})).Return(stockOf(450), nil)
+	mockStockLotRepo.On("GetExpiredStock", mock.Anything, mock.Anything).Return(stockOf(0), nil)
+	return mockStockLotRepo
+}
+
+func TestInventoryService_ValidateInventoryForOrder_ExpiredIngredient(t *testing.T) {
+	mockIngredientRepo := new(MockIngredientRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo:  new(MockInventoryRepo),
+		IngredientRepo: mockIngredientRepo,
+		RecipeRepo:     latteRecipes(),
+		StockLotRepo:   expiredBeans(),
+	}, nil, nil)
+
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(500)}, nil)
+
+	err := inventoryService.ValidateInventoryForOrder(twoLattes)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient inventory for ingredient Coffee beans: required 54 g, available 50 g")
+}
+
+func TestInventoryService_UpdateInventoryAfterOrder_DoesNotSellExpiredStock(t *testing.T) {
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockStockLotRepo := expiredBeans()
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo:        new(MockInventoryRepo),
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		RecipeRepo:           latteRecipes(),
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	// 500 g are in stock, but only 50 g of it has not expired
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-54"), stockUserID).Return(stockOf(500), stockOf(446), nil)
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(446)}, nil)
+
+	err := inventoryService.UpdateInventoryAfterOrder(twoLattes, stockUserID)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, repositories.ErrInsufficientStock)
+	assert.Contains(t, err.Error(), "insufficient stock for ingredient Coffee beans: only 50 g available, 54 g requested")
+
+	mockStockLotRepo.AssertNotCalled(t, "ConsumeStockLots", mock.Anything, mock.Anything, mock.Anything)
+	mockStockTransactionRepo.AssertNotCalled(t, "CreateStockTransaction", mock.Anything)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
 	"github.com/AndikaPrasetia/pos-cafee/internal/services"
 	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
 	"github.com/shopspring/decimal"
@@ -15,7 +16,12 @@ import (
 func TestInventoryService_ValidateInventoryForOrder(t *testing.T) {
 	mockInventoryRepo := new(MockInventoryRepo)
 	mockMenuRepo := new(MockMenuRepo)
-	inventoryService := services.NewInventoryService(mockInventoryRepo, nil, mockMenuRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo: mockInventoryRepo,
+		MenuRepo:      mockMenuRepo,
+		RecipeRepo:    withoutRecipes(),
+		StockLotRepo:  consumableLots(),
+	}, nil, nil)
 
 	orderItems := []models.OrderItemCreate{
 		{
@@ -26,8 +32,8 @@ func TestInventoryService_ValidateInventoryForOrder(t *testing.T) {
 
 	inventory := &models.Inventory{
 		MenuItemID:    "valid-item-id",
-		CurrentStock:  10,
-		MinimumStock:  2,
+		CurrentStock:  stockOf(10),
+		MinimumStock:  stockOf(2),
 		Unit:          "pieces",
 		LastUpdatedAt: time.Now(),
 	}
@@ -53,7 +59,12 @@ func TestInventoryService_ValidateInventoryForOrder(t *testing.T) {
 func TestInventoryService_ValidateInventoryForOrder_InsufficientStock(t *testing.T) {
 	mockInventoryRepo := new(MockInventoryRepo)
 	mockMenuRepo := new(MockMenuRepo)
-	inventoryService := services.NewInventoryService(mockInventoryRepo, nil, mockMenuRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		InventoryRepo: mockInventoryRepo,
+		MenuRepo:      mockMenuRepo,
+		RecipeRepo:    withoutRecipes(),
+		StockLotRepo:  consumableLots(),
+	}, nil, nil)
 
 	orderItems := []models.OrderItemCreate{
 		{
@@ -64,8 +75,8 @@ func TestInventoryService_ValidateInventoryForOrder_InsufficientStock(t *testing
 
 	inventory := &models.Inventory{
 		MenuItemID:    "low-stock-item-id",
-		CurrentStock:  10,
-		MinimumStock:  2,
+		CurrentStock:  stockOf(10),
+		MinimumStock:  stockOf(2),
 		Unit:          "pieces",
 		LastUpdatedAt: time.Now(),
 	}
@@ -110,11 +121,16 @@ func (m *MockInventoryRepo) ListInventory(filter models.InventoryFilter) ([]*mod
 	return args.Get(0).([]*models.Inventory), args.Error(1)
 }
 
-func (m *MockInventoryRepo) UpdateInventoryStock(menuItemID string, stock int, userID string) error {
+func (m *MockInventoryRepo) UpdateInventoryStock(menuItemID string, stock types.DecimalText, userID string) error {
 	args := m.Called(menuItemID, stock, userID)
 	return args.Error(0)
 }
 
+func (m *MockInventoryRepo) AdjustInventoryStock(menuItemID string, quantity types.DecimalText, userID string) (types.DecimalText, types.DecimalText, error) {
+	args := m.Called(menuItemID, quantity, userID)
+	return args.Get(0).(types.DecimalText), args.Get(1).(types.DecimalText), args.Error(2)
+}
+
 func (m *MockInventoryRepo) CreateInventoryRecord(menuItemID string) error {
 	args := m.Called(menuItemID)
 	return args.Error(0)
@@ -207,3 +223,23 @@ func (m *MockMenuRepo) DeleteMenuItem(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockMenuRepo) UpdateMenuItemCost(id string, cost types.DecimalText) error {
+	args := m.Called(id, cost)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepo) SyncMenuItemAvailability(menuItemID string, ingredientID *string) ([]*models.MenuItemAvailability, error) {
+	args := m.Called(menuItemID, ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MenuItemAvailability), args.Error(1)
+}
+
+// unchangedMenu is a menu that no stock movement sells out or brings back
+func unchangedMenu() *MockMenuRepo {
+	mockMenuRepo := new(MockMenuRepo)
+	mockMenuRepo.On("SyncMenuItemAvailability", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return mockMenuRepo
+}
+
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/notify"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLowStockAlertRepo is a mock implementation of LowStockAlertRepo interface
+type MockLowStockAlertRepo struct {
+	mock.Mock
+}
+
+func (m *MockLowStockAlertRepo) ListLowStockAlerts(filter models.LowStockAlertFilter) ([]*models.LowStockAlert, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LowStockAlert), args.Error(1)
+}
+
+func (m *MockLowStockAlertRepo) CountUnreadLowStockAlerts() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLowStockAlertRepo) MarkLowStockAlertRead(id, userID string) (*models.LowStockAlert, error) {
+	args := m.Called(id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LowStockAlert), args.Error(1)
+}
+
+func (m *MockLowStockAlertRepo) ClaimLowStockAlerts(maxAttempts, batchSize int) ([]*models.LowStockAlert, error) {
+	args := m.Called(maxAttempts, batchSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LowStockAlert), args.Error(1)
+}
+
+func (m *MockLowStockAlertRepo) ListLowStockAlertDeliveries(alertID string) ([]string, error) {
+	args := m.Called(alertID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockLowStockAlertRepo) CreateLowStockAlertDelivery(alertID, channel string) error {
+	args := m.Called(alertID, channel)
+	return args.Error(0)
+}
+
+func (m *MockLowStockAlertRepo) MarkLowStockAlertDispatched(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockLowStockAlertRepo) SetLowStockAlertError(id, message string) error {
+	args := m.Called(id, message)
+	return args.Error(0)
+}
+
+func (m *MockLowStockAlertRepo) SyncLowStockAlert(stockTransactionID, menuItemID string, ingredientID *string, previousStock types.DecimalText) error {
+	args := m.Called(stockTransactionID, menuItemID, ingredientID, previousStock)
+	return args.Error(0)
+}
+
+// noStockAlerts keeps low stock alerts for tests that move stock without
+// caring whether it runs low
+func noStockAlerts() *MockLowStockAlertRepo {
+	mockLowStockAlertRepo := new(MockLowStockAlertRepo)
+	mockLowStockAlertRepo.On("SyncLowStockAlert", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockLowStockAlertRepo
+}
+
+// fakeChannel records the alerts sent through it, failing with err when set
+type fakeChannel struct {
+	name string
+	err  error
+	sent []*models.LowStockAlert
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Send(ctx context.Context, alert *models.LowStockAlert) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, alert)
+	return nil
+}
+
+const lowStockAlertID = "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d"
+
+func lowBeansAlert() *models.LowStockAlert {
+	ingredientID := beansID
+	return &models.LowStockAlert{
+		ID:           lowStockAlertID,
+		IngredientID: &ingredientID,
+		ItemName:     "Coffee beans",
+		Unit:         "g",
+		CurrentStock: stockOf(300),
+		MinimumStock: stockOf(500),
+		RaisedAt:     time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestLowStockAlertService_DispatchSendsThroughEveryChannel(t *testing.T) {
+	mockRepo := new(MockLowStockAlertRepo)
+	email, webhook := &fakeChannel{name: "email"}, &fakeChannel{name: "webhook"}
+	alertService := services.NewLowStockAlertService(mockRepo, []notify.Channel{email, webhook})
+
+	mockRepo.On("ClaimLowStockAlerts", services.MaxLowStockAlertAttempts, services.LowStockAlertBatchSize).Return([]*models.LowStockAlert{lowBeansAlert()}, nil)
+	mockRepo.On("ListLowStockAlertDeliveries", lowStockAlertID).Return([]string{}, nil)
+	mockRepo.On("CreateLowStockAlertDelivery", lowStockAlertID, "email").Return(nil)
+	mockRepo.On("CreateLowStockAlertDelivery", lowStockAlertID, "webhook").Return(nil)
+	mockRepo.On("MarkLowStockAlertDispatched", lowStockAlertID).Return(nil)
+
+	dispatched, err := alertService.DispatchLowStockAlerts(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, dispatched)
+	assert.Len(t, email.sent, 1)
+	assert.Len(t, webhook.sent, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLowStockAlertService_RetryOnlySendsToChannelsThatFailed(t *testing.T) {
+	mockRepo := new(MockLowStockAlertRepo)
+	email, webhook := &fakeChannel{name: "email"}, &fakeChannel{name: "webhook"}
+	alertService := services.NewLowStockAlertService(mockRepo, []notify.Channel{email, webhook})
+
+	// The email went out on the last attempt but the webhook did not
+	mockRepo.On("ClaimLowStockAlerts", services.MaxLowStockAlertAttempts, services.LowStockAlertBatchSize).Return([]*models.LowStockAlert{lowBeansAlert()}, nil)
+	mockRepo.On("ListLowStockAlertDeliveries", lowStockAlertID).Return([]string{"email"}, nil)
+	mockRepo.On("CreateLowStockAlertDelivery", lowStockAlertID, "webhook").Return(nil)
+	mockRepo.On("MarkLowStockAlertDispatched", lowStockAlertID).Return(nil)
+
+	_, err := alertService.DispatchLowStockAlerts(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, email.sent)
+	assert.Len(t, webhook.sent, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLowStockAlertService_FailedChannelLeavesAlertForRetry(t *testing.T) {
+	mockRepo := new(MockLowStockAlertRepo)
+	email := &fakeChannel{name: "email"}
+	webhook := &fakeChannel{name: "webhook", err: errors.New("connection refused")}
+	alertService := services.NewLowStockAlertService(mockRepo, []notify.Channel{email, webhook})
+
+	mockRepo.On("ClaimLowStockAlerts", services.MaxLowStockAlertAttempts, services.LowStockAlertBatchSize).Return([]*models.LowStockAlert{lowBeansAlert()}, nil)
+	mockRepo.On("ListLowStockAlertDeliveries", lowStockAlertID).Return([]string{}, nil)
+	mockRepo.On("CreateLowStockAlertDelivery", lowStockAlertID, "email").Return(nil)
+	mockRepo.On("SetLowStockAlertError", lowStockAlertID, "webhook: connection refused").Return(nil)
+
+	dispatched, err := alertService.DispatchLowStockAlerts(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, dispatched)
+	assert.Len(t, email.sent, 1)
+	mockRepo.AssertNotCalled(t, "MarkLowStockAlertDispatched", lowStockAlertID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLowStockAlertService_ListIncludesUnreadCount(t *testing.T) {
+	mockRepo := new(MockLowStockAlertRepo)
+	alertService := services.NewLowStockAlertService(mockRepo, nil)
+
+	filter := models.LowStockAlertFilter{OpenOnly: true, Limit: 50}
+	mockRepo.On("ListLowStockAlerts", filter).Return([]*models.LowStockAlert{lowBeansAlert()}, nil)
+	mockRepo.On("CountUnreadLowStockAlerts").Return(int64(3), nil)
+
+	response, err := alertService.ListLowStockAlerts(filter)
+	require.NoError(t, err)
+
+	feed := response.Data.(*models.LowStockAlertFeed)
+	assert.Len(t, feed.Alerts, 1)
+	assert.Equal(t, int64(3), feed.UnreadCount)
+}
@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/availability"
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMenuAvailability_WasteThatSellsOutAnItemIsAnnounced(t *testing.T) {
+	menuAvailability := services.NewMenuAvailability(nil, availability.NewBroker())
+	menuService := services.NewMenuService(nil, nil, nil, menuAvailability)
+
+	mockWasteRepo := new(MockWasteRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockMenuRepo := new(MockMenuRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{
+		WasteRepo:            mockWasteRepo,
+		MenuRepo:             mockMenuRepo,
+		IngredientRepo:       mockIngredientRepo,
+		UnitRepo:             seededUnits(),
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         consumableLots(),
+	}, nil, menuAvailability)
+
+	events, unsubscribe, err := menuService.SubscribeAvailability()
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CostPrice: amount(150), IsActive: true}, nil)
+	mockWasteRepo.On("CreateWasteRecord", mock.Anything).Return(&models.WasteRecord{ID: wasteRecordID}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-500"), stockUserID).Return(stockOf(510), stockOf(10), nil)
+
+	// Ten grams of beans left at the bar is not enough for a latte
+	latte := &models.MenuItemAvailability{MenuItemID: latteID, Name: "Latte", IsAvailable: false, SoldOut: true}
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.Anything).Return(&models.StockTransaction{}, nil)
+	mockMenuRepo.On("SyncMenuItemAvailability", "", mock.Anything).Return([]*models.MenuItemAvailability{latte}, nil)
+
+	ingredientID, unit := beansID, "kg"
+	_, err = wasteService.RecordWaste(stockUserID, &models.WasteCreate{
+		IngredientID: &ingredientID,
+		Reason:       types.WasteReasonDamaged,
+		Quantity:     types.FromDecimal(decimal.RequireFromString("0.5")),
+		Unit:         &unit,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, availability.EventMenuAvailabilityChanged, event.Type)
+	assert.Equal(t, []*models.MenuItemAvailability{latte}, event.Items)
+}
+
+func TestMenuAvailability_StockTakeLeavesOutItemsThatCameBack(t *testing.T) {
+	menuAvailability := services.NewMenuAvailability(nil, availability.NewBroker())
+	menuService := services.NewMenuService(nil, nil, nil, menuAvailability)
+
+	mockStockTakeRepo := new(MockStockTakeRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockMenuRepo := new(MockMenuRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	stockTakeService := services.NewStockTakeService(&repositories.Repository{
+		StockTakeRepo:        mockStockTakeRepo,
+		MenuRepo:             mockMenuRepo,
+		IngredientRepo:       mockIngredientRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         consumableLots(),
+	}, nil, menuAvailability)
+
+	events, unsubscribe, err := menuService.SubscribeAvailability()
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	// More milk was found than expected and fewer beans
+	milk, beans := milkID, beansID
+	milkCounted, milkVariance, milkValue := stockOf(1300), stockOf(300), amount(6000)
+	beansCounted, beansVariance, beansValue := stockOf(10), stockOf(-490), amount(-73500)
+	mockStockTakeRepo.On("GetStockTakeForUpdate", stockTakeID).Return(openStockTake(), nil)
+	mockStockTakeRepo.On("ListStockTakeLines", stockTakeID).Return([]models.StockTakeLine{
+		{ID: milkLineID, IngredientID: &milk, ItemName: "Milk", Unit: "ml", SystemQuantity: stockOf(1000), CountedQuantity: &milkCounted, Variance: &milkVariance, VarianceValue: &milkValue},
+		{ID: beansLineID, IngredientID: &beans, ItemName: "Coffee beans", Unit: "g", SystemQuantity: stockOf(500), CountedQuantity: &beansCounted, Variance: &beansVariance, VarianceValue: &beansValue},
+	}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", milkID, stockMatching("300"), reportManagerID).Return(stockOf(100), stockOf(400), nil)
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-490"), reportManagerID).Return(stockOf(500), stockOf(10), nil)
+
+	// The milk puts the latte back on the menu, then the beans sell it out
+	// again along with the espresso
+	latteBack := &models.MenuItemAvailability{MenuItemID: latteID, Name: "Latte", IsAvailable: true}
+	latteOut := &models.MenuItemAvailability{MenuItemID: latteID, Name: "Latte", SoldOut: true}
+	espressoOut := &models.MenuItemAvailability{MenuItemID: extraShotID, Name: "Espresso", SoldOut: true}
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.Anything).Return(&models.StockTransaction{}, nil).Twice()
+	mockMenuRepo.On("SyncMenuItemAvailability", "", mock.MatchedBy(func(ingredientID *string) bool {
+		return *ingredientID == milkID
+	})).Return([]*models.MenuItemAvailability{latteBack}, nil).Once()
+	mockMenuRepo.On("SyncMenuItemAvailability", "", mock.MatchedBy(func(ingredientID *string) bool {
+		return *ingredientID == beansID
+	})).Return([]*models.MenuItemAvailability{latteOut, espressoOut}, nil).Once()
+
+	approvedBy := reportManagerID
+	mockStockTakeRepo.On("UpdateStockTakeStatus", stockTakeID, types.StockTakeStatusApproved, &approvedBy).Return(nil)
+	mockStockTakeRepo.On("GetStockTake", stockTakeID).Return(openStockTake(), nil)
+
+	_, err = stockTakeService.ApproveStockTake(stockTakeID, reportManagerID)
+	require.NoError(t, err)
+
+	// The latte ends where it started, so only the espresso is announced
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, []*models.MenuItemAvailability{espressoOut}, event.Items)
+	mockStockTransactionRepo.AssertExpectations(t)
+	mockMenuRepo.AssertExpectations(t)
+}
+
+func TestMenuAvailability_SubscribeRequiresNotifier(t *testing.T) {
+	menuService := services.NewMenuService(nil, nil, nil, nil)
+
+	_, _, err := menuService.SubscribeAvailability()
+	assert.EqualError(t, err, "live menu updates are not enabled")
+}
@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderNumberFormat_Format(t *testing.T) {
+	businessDate := time.Date(2026, time.October, 16, 14, 30, 0, 0, time.Local)
+
+	t.Run("default format", func(t *testing.T) {
+		assert.Equal(t, "ORD-20261016-0001", services.DefaultOrderNumberFormat.Format(businessDate, 1))
+		assert.Equal(t, "ORD-20261016-12345", services.DefaultOrderNumberFormat.Format(businessDate, 12345))
+	})
+
+	t.Run("per-outlet prefix and pattern", func(t *testing.T) {
+		format := services.OrderNumberFormat{
+			Prefix:         "JKT01",
+			Pattern:        "{prefix}/{date}/{seq}",
+			DateLayout:     "060102",
+			SequenceDigits: 3,
+		}
+		assert.Equal(t, "JKT01/261016/042", format.Format(businessDate, 42))
+	})
+
+	t.Run("unset fields fall back to defaults", func(t *testing.T) {
+		format := services.OrderNumberFormat{Prefix: "BDG"}
+		assert.Equal(t, "BDG-20261016-0007", format.Format(businessDate, 7))
+	})
+}
+
+func TestOrderNumberFormat_Validate(t *testing.T) {
+	t.Run("default format", func(t *testing.T) {
+		assert.NoError(t, services.DefaultOrderNumberFormat.Validate())
+	})
+
+	t.Run("pattern without sequence", func(t *testing.T) {
+		format := services.OrderNumberFormat{Pattern: "{prefix}-{date}"}
+		assert.ErrorContains(t, format.Validate(), "{seq}")
+	})
+
+	t.Run("pattern without date", func(t *testing.T) {
+		format := services.OrderNumberFormat{Pattern: "{prefix}-{seq}"}
+		assert.ErrorContains(t, format.Validate(), "{date}")
+	})
+
+	t.Run("date layout without the day", func(t *testing.T) {
+		format := services.OrderNumberFormat{DateLayout: "200601"}
+		assert.ErrorContains(t, format.Validate(), "does not include the day")
+	})
+
+	t.Run("date layout with only the day of the month", func(t *testing.T) {
+		format := services.OrderNumberFormat{DateLayout: "02"}
+		assert.ErrorContains(t, format.Validate(), "does not include the year")
+	})
+
+	t.Run("date layout with a weekday instead of the day", func(t *testing.T) {
+		format := services.OrderNumberFormat{DateLayout: "200601Mon"}
+		assert.ErrorContains(t, format.Validate(), "does not include the day")
+	})
+
+	t.Run("pattern without prefix", func(t *testing.T) {
+		format := services.OrderNumberFormat{Prefix: "BDG", Pattern: "{date}-{seq}"}
+		assert.ErrorContains(t, format.Validate(), "{prefix}")
+	})
+}
@@ -0,0 +1,306 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPurchaseOrderRepo is a mock implementation of PurchaseOrderRepo interface
+type MockPurchaseOrderRepo struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseOrderRepo) CreatePurchaseOrder(order *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	args := m.Called(order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) GetPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) GetPurchaseOrderForUpdate(id string) (*models.PurchaseOrder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) ListPurchaseOrders(filter models.PurchaseOrderFilter) ([]*models.PurchaseOrder, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseOrderRepo) UpdatePurchaseOrderStatus(id string, status types.PurchaseOrderStatus) error {
+	args := m.Called(id, status)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseOrderRepo) SetPurchaseOrderItems(id string, items []models.PurchaseOrderItem) error {
+	args := m.Called(id, items)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseOrderRepo) ListPurchaseOrderItems(id string) ([]models.PurchaseOrderItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PurchaseOrderItem), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) ReceivePurchaseOrderItem(itemID string, quantity types.DecimalText) error {
+	args := m.Called(itemID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseOrderRepo) CreateGoodsReceivedNote(note *models.GoodsReceivedNote) (*models.GoodsReceivedNote, error) {
+	args := m.Called(note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GoodsReceivedNote), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepo) CreateGoodsReceivedNoteItem(item *models.GoodsReceivedNoteItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseOrderRepo) ListGoodsReceivedNotes(purchaseOrderID string) ([]models.GoodsReceivedNote, error) {
+	args := m.Called(purchaseOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GoodsReceivedNote), args.Error(1)
+}
+
+// MockExpenseRepo is a mock implementation of ExpenseRepo interface
+type MockExpenseRepo struct {
+	mock.Mock
+}
+
+func (m *MockExpenseRepo) CreateExpense(expense *models.Expense) (*models.Expense, error) {
+	args := m.Called(expense)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Expense), args.Error(1)
+}
+
+func (m *MockExpenseRepo) GetExpense(id string) (*models.Expense, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Expense), args.Error(1)
+}
+
+func (m *MockExpenseRepo) ListExpenses(filter models.ExpenseFilter) ([]*models.Expense, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Expense), args.Error(1)
+}
+
+func (m *MockExpenseRepo) UpdateExpense(expense *models.Expense) (*models.Expense, error) {
+	args := m.Called(expense)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Expense), args.Error(1)
+}
+
+func (m *MockExpenseRepo) DeleteExpense(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockExpenseRepo) GetExpensesByDateRange(startDate, endDate time.Time) ([]*models.Expense, error) {
+	args := m.Called(startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Expense), args.Error(1)
+}
+
+const (
+	purchaseOrderID     = "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+	purchaseOrderItemID = "4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a"
+	goodsReceivedNoteID = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+)
+
+// sentBeansOrder returns a sent purchase order for 2 kg of coffee beans at
+// 150000 per kg, of which received kg have arrived so far
+func sentBeansOrder(received int64) (*models.PurchaseOrder, models.PurchaseOrderItem) {
+	ingredientID := beansID
+	order := &models.PurchaseOrder{
+		ID:           purchaseOrderID,
+		PONumber:     "PO-000001",
+		SupplierName: "Kopi Nusantara",
+		Status:       types.PurchaseOrderStatusSent,
+	}
+	item := models.PurchaseOrderItem{
+		ID:               purchaseOrderItemID,
+		PurchaseOrderID:  purchaseOrderID,
+		IngredientID:     &ingredientID,
+		ItemName:         "Coffee beans",
+		Unit:             "kg",
+		Quantity:         stockOf(2),
+		ReceivedQuantity: stockOf(received),
+		UnitCost:         amount(150000),
+	}
+	return order, item
+}
+
+func TestPurchaseOrderService_ReceiveGoods_RestocksAndCostsIngredient(t *testing.T) {
+	mockPurchaseOrderRepo := new(MockPurchaseOrderRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockExpenseRepo := new(MockExpenseRepo)
+	mockStockLotRepo := new(MockStockLotRepo)
+	purchaseOrderService := services.NewPurchaseOrderService(&repositories.Repository{
+		PurchaseOrderRepo:    mockPurchaseOrderRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		UnitRepo:             seededUnits(),
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		ExpenseRepo:          mockExpenseRepo,
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	order, item := sentBeansOrder(0)
+	receivedItem := item
+	receivedItem.ReceivedQuantity = stockOf(2)
+
+	mockPurchaseOrderRepo.On("GetPurchaseOrderForUpdate", purchaseOrderID).Return(order, nil)
+	mockPurchaseOrderRepo.On("ListPurchaseOrderItems", purchaseOrderID).Return([]models.PurchaseOrderItem{item}, nil).Once()
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", IsActive: true}, nil)
+
+	// The delivery is worth 300000 and is booked as a purchase expense
+	mockExpenseRepo.On("CreateExpense", mock.MatchedBy(func(expense *models.Expense) bool {
+		return expense.Category == services.PurchaseExpenseCategory && expense.Amount.Equals(amount(300000))
+	})).Return(&models.Expense{ID: "expense-1"}, nil)
+	mockPurchaseOrderRepo.On("CreateGoodsReceivedNote", mock.MatchedBy(func(note *models.GoodsReceivedNote) bool {
+		return note.TotalAmount.Equals(amount(300000)) && note.ExpenseID != nil && *note.ExpenseID == "expense-1"
+	})).Return(&models.GoodsReceivedNote{ID: goodsReceivedNoteID, GRNNumber: "GRN-000001"}, nil)
+
+	// 2 kg go into stock as 2000 g, recorded against the purchase order and
+	// valued at what was paid for them. The 500 g already held cost 120 a
+	// gram, so the average moves to (500 × 120 + 2000 × 150) / 2500 = 144.
+	mockPurchaseOrderRepo.On("ReceivePurchaseOrderItem", purchaseOrderItemID, stockMatching("2")).Return(nil)
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("2000"), stockUserID).Return(stockOf(500), stockOf(2500), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", "", mock.MatchedBy(func(ingredientID *string) bool {
+		return ingredientID != nil && *ingredientID == beansID
+	})).Return(amount(120), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeIn &&
+			transaction.Quantity.Equals(stockOf(2000)) &&
+			transaction.ReceivedCost != nil && transaction.ReceivedCost.Equals(stockOf(150)) &&
+			transaction.UnitCost.Equals(amount(150)) &&
+			transaction.TotalCost.Equals(amount(300000)) &&
+			transaction.AverageCost.Equals(amount(144)) &&
+			*transaction.ReferenceType == types.ReferenceTypePurchaseOrder &&
+			*transaction.ReferenceID == purchaseOrderID
+	})).Return(&models.StockTransaction{}, nil)
+	mockStockTransactionRepo.On("SetStockAverageCost", "", mock.Anything, stockMatching("144")).Return(nil)
+	mockPurchaseOrderRepo.On("CreateGoodsReceivedNoteItem", mock.MatchedBy(func(noteItem *models.GoodsReceivedNoteItem) bool {
+		return noteItem.GoodsReceivedNoteID == goodsReceivedNoteID && noteItem.StockQuantity.Equals(stockOf(2000))
+	})).Return(nil)
+
+	// A gram now costs 150000 / 1000, and the delivery is a lot of 2000 g at that cost
+	expiresAt := time.Now().AddDate(0, 6, 0)
+	mockStockLotRepo.On("CreateStockLot", mock.MatchedBy(func(lot *models.StockLot) bool {
+		return *lot.IngredientID == beansID &&
+			lot.Unit == "g" &&
+			lot.QuantityReceived.Equals(stockOf(2000)) &&
+			lot.UnitCost.Equals(stockOf(150)) &&
+			*lot.GoodsReceivedNoteID == goodsReceivedNoteID &&
+			lot.ExpiresAt.Equal(expiresAt)
+	})).Return(&models.StockLot{}, nil)
+	mockIngredientRepo.On("UpdateIngredientCostPrice", beansID, stockMatching("150")).Return(nil)
+
+	mockPurchaseOrderRepo.On("ListPurchaseOrderItems", purchaseOrderID).Return([]models.PurchaseOrderItem{receivedItem}, nil)
+	mockPurchaseOrderRepo.On("UpdatePurchaseOrderStatus", purchaseOrderID, types.PurchaseOrderStatusReceived).Return(nil)
+	mockPurchaseOrderRepo.On("GetPurchaseOrder", purchaseOrderID).Return(order, nil)
+	mockPurchaseOrderRepo.On("ListGoodsReceivedNotes", purchaseOrderID).Return([]models.GoodsReceivedNote{}, nil)
+
+	_, err := purchaseOrderService.ReceiveGoods(purchaseOrderID, stockUserID, &models.GoodsReceiptCreate{
+		Items: []models.GoodsReceiptItemInput{{PurchaseOrderItemID: purchaseOrderItemID, Quantity: stockOf(2), ExpiresAt: &expiresAt}},
+	})
+	require.NoError(t, err)
+
+	mockPurchaseOrderRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+	mockExpenseRepo.AssertExpectations(t)
+	mockStockLotRepo.AssertExpectations(t)
+}
+
+func TestPurchaseOrderService_ReceiveGoods_RejectsMoreThanOutstanding(t *testing.T) {
+	mockPurchaseOrderRepo := new(MockPurchaseOrderRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	purchaseOrderService := services.NewPurchaseOrderService(&repositories.Repository{
+		PurchaseOrderRepo: mockPurchaseOrderRepo,
+		IngredientRepo:    mockIngredientRepo,
+		UnitRepo:          seededUnits(),
+	}, nil, nil)
+
+	order, item := sentBeansOrder(1)
+	order.Status = types.PurchaseOrderStatusPartiallyReceived
+	mockPurchaseOrderRepo.On("GetPurchaseOrderForUpdate", purchaseOrderID).Return(order, nil)
+	mockPurchaseOrderRepo.On("ListPurchaseOrderItems", purchaseOrderID).Return([]models.PurchaseOrderItem{item}, nil)
+
+	_, err := purchaseOrderService.ReceiveGoods(purchaseOrderID, stockUserID, &models.GoodsReceiptCreate{
+		Items: []models.GoodsReceiptItemInput{{PurchaseOrderItemID: purchaseOrderItemID, Quantity: stockOf(2)}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only 1 kg outstanding")
+
+	mockIngredientRepo.AssertNotCalled(t, "AdjustIngredientStock", mock.Anything, mock.Anything, mock.Anything)
+	mockPurchaseOrderRepo.AssertNotCalled(t, "CreateGoodsReceivedNote", mock.Anything)
+}
+
+func TestPurchaseOrderService_ReceiveGoods_RejectsDraftOrder(t *testing.T) {
+	mockPurchaseOrderRepo := new(MockPurchaseOrderRepo)
+	purchaseOrderService := services.NewPurchaseOrderService(&repositories.Repository{PurchaseOrderRepo: mockPurchaseOrderRepo}, nil, nil)
+
+	order, _ := sentBeansOrder(0)
+	order.Status = types.PurchaseOrderStatusDraft
+	mockPurchaseOrderRepo.On("GetPurchaseOrderForUpdate", purchaseOrderID).Return(order, nil)
+
+	_, err := purchaseOrderService.ReceiveGoods(purchaseOrderID, stockUserID, &models.GoodsReceiptCreate{
+		Items: []models.GoodsReceiptItemInput{{PurchaseOrderItemID: purchaseOrderItemID, Quantity: stockOf(1)}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot receive goods against a draft purchase order")
+
+	mockPurchaseOrderRepo.AssertNotCalled(t, "ListPurchaseOrderItems", mock.Anything)
+}
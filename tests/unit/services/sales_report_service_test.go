@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/receipt"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSalesReportRepo is a mock implementation of SalesReportRepo interface
+type MockSalesReportRepo struct {
+	mock.Mock
+}
+
+func (m *MockSalesReportRepo) SummarizeSales(periodStart *time.Time, periodEnd time.Time) (*models.SalesReport, error) {
+	args := m.Called(periodStart, periodEnd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesReport), args.Error(1)
+}
+
+func (m *MockSalesReportRepo) LockZReports() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockSalesReportRepo) GetDatabaseTime() (time.Time, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockSalesReportRepo) GetLatestZReport() (*models.SalesReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesReport), args.Error(1)
+}
+
+func (m *MockSalesReportRepo) GetZReport(id string) (*models.SalesReport, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesReport), args.Error(1)
+}
+
+func (m *MockSalesReportRepo) ListZReports(limit, offset int) ([]*models.SalesReport, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SalesReport), args.Error(1)
+}
+
+func (m *MockSalesReportRepo) CreateZReport(report *models.SalesReport) (*models.SalesReport, error) {
+	args := m.Called(report)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesReport), args.Error(1)
+}
+
+const reportManagerID = "e1a2b3c4-d5e6-4f70-8a9b-0c1d2e3f4a5b"
+
+// summarizedSales is what the repository adds up for a period: 3.875.000 in
+// sales including 384.000 PPN, and 55.500 refunded including 5.500 PPN
+func summarizedSales(periodStart *time.Time, periodEnd time.Time) *models.SalesReport {
+	return &models.SalesReport{
+		PeriodStart:  periodStart,
+		PeriodEnd:    periodEnd,
+		OrderCount:   57,
+		TotalSales:   amount(3875000),
+		TaxAmount:    amount(384000),
+		RefundCount:  2,
+		RefundAmount: amount(55500),
+		RefundTax:    amount(5500),
+	}
+}
+
+func storedZReport(reportNumber int) *models.SalesReport {
+	return &models.SalesReport{ReportType: types.SalesReportTypeZ, ReportNumber: &reportNumber}
+}
+
+func TestSalesReportService_GenerateZReport_First(t *testing.T) {
+	mockSalesReportRepo := new(MockSalesReportRepo)
+	salesReportService := services.NewSalesReportService(&repositories.Repository{SalesReportRepo: mockSalesReportRepo}, nil, receipt.Settings{})
+
+	mockSalesReportRepo.On("LockZReports").Return(nil)
+	mockSalesReportRepo.On("GetLatestZReport").Return(nil, repositories.ErrNoZReport)
+	mockSalesReportRepo.On("GetDatabaseTime").Return(time.Now(), nil)
+	mockSalesReportRepo.On("SummarizeSales", (*time.Time)(nil), mock.AnythingOfType("time.Time")).
+		Return(summarizedSales(nil, time.Now()), nil)
+	mockSalesReportRepo.On("CreateZReport", mock.MatchedBy(func(report *models.SalesReport) bool {
+		return *report.ReportNumber == 1 && report.PeriodStart == nil
+	})).Return(storedZReport(1), nil)
+
+	response, err := salesReportService.GenerateZReport(reportManagerID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *response.Data.(*models.SalesReport).ReportNumber)
+	mockSalesReportRepo.AssertExpectations(t)
+}
+
+func TestSalesReportService_GenerateZReport_ContinuesFromPrevious(t *testing.T) {
+	mockSalesReportRepo := new(MockSalesReportRepo)
+	salesReportService := services.NewSalesReportService(&repositories.Repository{SalesReportRepo: mockSalesReportRepo}, nil, receipt.Settings{})
+
+	previousNumber := 11
+	previousEnd := time.Date(2026, 10, 15, 22, 5, 0, 0, time.UTC)
+	mockSalesReportRepo.On("LockZReports").Return(nil)
+	mockSalesReportRepo.On("GetLatestZReport").Return(&models.SalesReport{
+		ReportType:   types.SalesReportTypeZ,
+		ReportNumber: &previousNumber,
+		PeriodEnd:    previousEnd,
+	}, nil)
+	// The period ends on the database's clock, not the application server's
+	databaseNow := time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)
+	mockSalesReportRepo.On("GetDatabaseTime").Return(databaseNow, nil)
+	mockSalesReportRepo.On("SummarizeSales", mock.MatchedBy(func(periodStart *time.Time) bool {
+		return periodStart != nil && periodStart.Equal(previousEnd)
+	}), databaseNow).Return(summarizedSales(&previousEnd, databaseNow), nil)
+
+	// Net sales: 3.875.000 - 384.000 PPN - (55.500 - 5.500) refunded = 3.441.000
+	mockSalesReportRepo.On("CreateZReport", mock.MatchedBy(func(report *models.SalesReport) bool {
+		return *report.ReportNumber == 12 &&
+			report.ReportType == types.SalesReportTypeZ &&
+			report.GeneratedBy == reportManagerID &&
+			report.NetSales.Equals(amount(3441000))
+	})).Return(storedZReport(12), nil)
+
+	response, err := salesReportService.GenerateZReport(reportManagerID)
+	require.NoError(t, err)
+
+	assert.Contains(t, response.Message, "#12")
+	mockSalesReportRepo.AssertExpectations(t)
+}
+
+func TestSalesReportService_GenerateZReport_TakesOffEarlierSaleVoided(t *testing.T) {
+	mockSalesReportRepo := new(MockSalesReportRepo)
+	salesReportService := services.NewSalesReportService(&repositories.Repository{SalesReportRepo: mockSalesReportRepo}, nil, receipt.Settings{})
+
+	previousNumber := 11
+	previousEnd := time.Date(2026, 10, 15, 22, 5, 0, 0, time.UTC)
+	mockSalesReportRepo.On("LockZReports").Return(nil)
+	mockSalesReportRepo.On("GetLatestZReport").Return(&models.SalesReport{
+		ReportType:   types.SalesReportTypeZ,
+		ReportNumber: &previousNumber,
+		PeriodEnd:    previousEnd,
+	}, nil)
+
+	mockSalesReportRepo.On("GetDatabaseTime").Return(time.Now(), nil)
+
+	// An order of 111.000 including 11.000 PPN, counted in report #11, is voided
+	sales := summarizedSales(&previousEnd, time.Now())
+	sales.CancelledCount = 1
+	sales.VoidedCount = 1
+	sales.VoidedAmount = amount(111000)
+	sales.EarlierVoidedNet = amount(100000)
+	mockSalesReportRepo.On("SummarizeSales", mock.Anything, mock.AnythingOfType("time.Time")).Return(sales, nil)
+
+	// Net sales: 3.441.000 - (111.000 - 11.000) voided = 3.341.000
+	mockSalesReportRepo.On("CreateZReport", mock.MatchedBy(func(report *models.SalesReport) bool {
+		return report.NetSales.Equals(amount(3341000))
+	})).Return(storedZReport(12), nil)
+
+	_, err := salesReportService.GenerateZReport(reportManagerID)
+	require.NoError(t, err)
+
+	mockSalesReportRepo.AssertExpectations(t)
+}
+
+func TestSalesReportService_GetXReport_DoesNotStore(t *testing.T) {
+	mockSalesReportRepo := new(MockSalesReportRepo)
+	salesReportService := services.NewSalesReportService(&repositories.Repository{SalesReportRepo: mockSalesReportRepo}, nil, receipt.Settings{})
+
+	mockSalesReportRepo.On("GetLatestZReport").Return(nil, repositories.ErrNoZReport)
+	mockSalesReportRepo.On("GetDatabaseTime").Return(time.Now(), nil)
+	mockSalesReportRepo.On("SummarizeSales", (*time.Time)(nil), mock.AnythingOfType("time.Time")).
+		Return(summarizedSales(nil, time.Now()), nil)
+
+	response, err := salesReportService.GetXReport(reportManagerID)
+	require.NoError(t, err)
+
+	report := response.Data.(*models.SalesReport)
+	assert.Equal(t, types.SalesReportTypeX, report.ReportType)
+	assert.Nil(t, report.ReportNumber)
+	assert.True(t, report.NetSales.Equals(amount(3441000)))
+	assert.NotNil(t, report.PaymentMethods)
+	assert.NotNil(t, report.Cashiers)
+	mockSalesReportRepo.AssertNotCalled(t, "LockZReports")
+	mockSalesReportRepo.AssertNotCalled(t, "CreateZReport", mock.Anything)
+}
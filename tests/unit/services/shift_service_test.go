@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockShiftRepo is a mock implementation of ShiftRepo interface
+type MockShiftRepo struct {
+	mock.Mock
+}
+
+func (m *MockShiftRepo) CreateShift(shift *models.CashShift) (*models.CashShift, error) {
+	args := m.Called(shift)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) GetShift(id string) (*models.CashShift, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) GetShiftForUpdate(id string) (*models.CashShift, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) GetOpenShiftByUser(userID string) (*models.CashShift, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) ListShifts(filter models.CashShiftFilter) ([]*models.CashShift, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) CloseShift(shift *models.CashShift) (*models.CashShift, error) {
+	args := m.Called(shift)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashShift), args.Error(1)
+}
+
+func (m *MockShiftRepo) CreateMovement(movement *models.CashMovement) (*models.CashMovement, error) {
+	args := m.Called(movement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashMovement), args.Error(1)
+}
+
+func (m *MockShiftRepo) ListMovements(shiftID string) ([]*models.CashMovement, error) {
+	args := m.Called(shiftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CashMovement), args.Error(1)
+}
+
+func (m *MockShiftRepo) CreateCount(shiftID string, count models.CashCount) error {
+	args := m.Called(shiftID, count)
+	return args.Error(0)
+}
+
+func (m *MockShiftRepo) ListCounts(shiftID string) ([]models.CashCount, error) {
+	args := m.Called(shiftID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CashCount), args.Error(1)
+}
+
+func (m *MockShiftRepo) GetCashSales(shiftID string) (int, types.DecimalText, error) {
+	args := m.Called(shiftID)
+	return args.Int(0), args.Get(1).(types.DecimalText), args.Error(2)
+}
+
+func (m *MockShiftRepo) GetCashRefunds(shiftID string) (types.DecimalText, error) {
+	args := m.Called(shiftID)
+	return args.Get(0).(types.DecimalText), args.Error(1)
+}
+
+func (m *MockShiftRepo) GetCashVoids(shiftID string) (types.DecimalText, error) {
+	args := m.Called(shiftID)
+	return args.Get(0).(types.DecimalText), args.Error(1)
+}
+
+const (
+	shiftID        = "5a0e4c2b-7d1f-4b8e-9c3a-2f6d8e1b0a11"
+	cashierID      = "c1a2b3c4-d5e6-4f70-8a9b-0c1d2e3f4a5b"
+	otherCashier   = "c1a2b3c4-d5e6-4f70-8a9b-0c1d2e3f4a5c"
+	shiftManagerID = "d1a2b3c4-d5e6-4f70-8a9b-0c1d2e3f4a5b"
+)
+
+func amount(value int64) types.DecimalText {
+	return types.DecimalText(decimal.NewFromInt(value))
+}
+
+func openShift() *models.CashShift {
+	return &models.CashShift{ID: shiftID, UserID: cashierID, Status: types.ShiftStatusOpen, OpeningFloat: amount(200000)}
+}
+
+func TestShiftService_OpenShift_AlreadyOpen(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepo)
+	shiftService := services.NewShiftService(&repositories.Repository{ShiftRepo: mockShiftRepo}, nil)
+
+	mockShiftRepo.On("GetOpenShiftByUser", cashierID).Return(openShift(), nil)
+
+	_, err := shiftService.OpenShift(cashierID, &models.CashShiftOpen{OpeningFloat: amount(200000)})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already have an open")
+	mockShiftRepo.AssertNotCalled(t, "CreateShift", mock.Anything)
+}
+
+func TestShiftService_OpenShift(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepo)
+	shiftService := services.NewShiftService(&repositories.Repository{ShiftRepo: mockShiftRepo}, nil)
+
+	mockShiftRepo.On("GetOpenShiftByUser", cashierID).Return(nil, repositories.ErrNoOpenShift)
+	mockShiftRepo.On("CreateShift", mock.MatchedBy(func(shift *models.CashShift) bool {
+		return shift.UserID == cashierID && shift.OpeningFloat.Equals(amount(200000))
+	})).Return(openShift(), nil)
+
+	response, err := shiftService.OpenShift(cashierID, &models.CashShiftOpen{OpeningFloat: amount(200000)})
+	require.NoError(t, err)
+
+	assert.Equal(t, shiftID, response.Data.(*models.CashShift).ID)
+	mockShiftRepo.AssertExpectations(t)
+}
+
+func TestShiftService_AddMovement_OtherCashier(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepo)
+	shiftService := services.NewShiftService(&repositories.Repository{ShiftRepo: mockShiftRepo}, nil)
+
+	mockShiftRepo.On("GetShiftForUpdate", shiftID).Return(openShift(), nil)
+
+	_, err := shiftService.AddMovement(shiftID, otherCashier, string(types.UserRoleCashier), &models.CashMovementCreate{
+		MovementType: types.CashMovementPayout,
+		Amount:       amount(15000),
+		Reason:       "Ice",
+	})
+
+	assert.Error(t, err)
+	mockShiftRepo.AssertNotCalled(t, "CreateMovement", mock.Anything)
+}
+
+func TestShiftService_CloseShift(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepo)
+	shiftService := services.NewShiftService(&repositories.Repository{ShiftRepo: mockShiftRepo}, nil)
+
+	mockShiftRepo.On("GetShiftForUpdate", shiftID).Return(openShift(), nil)
+	mockShiftRepo.On("GetCashSales", shiftID).Return(3, amount(205000), nil)
+	mockShiftRepo.On("GetCashRefunds", shiftID).Return(amount(20000), nil)
+	mockShiftRepo.On("GetCashVoids", shiftID).Return(amount(30000), nil)
+	mockShiftRepo.On("ListMovements", shiftID).Return([]*models.CashMovement{
+		{ShiftID: shiftID, MovementType: types.CashMovementDrop, Amount: amount(100000), Reason: "Safe drop"},
+		{ShiftID: shiftID, MovementType: types.CashMovementPayout, Amount: amount(15000), Reason: "Ice"},
+	}, nil)
+	mockShiftRepo.On("ListCounts", shiftID).Return([]models.CashCount{}, nil)
+	mockShiftRepo.On("CreateCount", shiftID, mock.Anything).Return(nil)
+
+	// Expected: 200.000 float + 205.000 sales - 20.000 refunded - 30.000 voided
+	// - 100.000 drop - 15.000 payout = 240.000
+	mockShiftRepo.On("CloseShift", mock.MatchedBy(func(shift *models.CashShift) bool {
+		return shift.ExpectedCash.Equals(amount(240000)) &&
+			shift.CountedCash.Equals(amount(235000)) &&
+			shift.Variance.Equals(amount(-5000)) &&
+			*shift.ClosedBy == shiftManagerID
+	})).Return(&models.CashShift{ID: shiftID, UserID: cashierID, Status: types.ShiftStatusClosed, OpeningFloat: amount(200000)}, nil)
+
+	response, err := shiftService.CloseShift(shiftID, shiftManagerID, string(types.UserRoleManager), &models.CashShiftClose{
+		Counts: []models.CashCount{
+			{Denomination: amount(100000), Quantity: 2},
+			{Denomination: amount(20000), Quantity: 1},
+			{Denomination: amount(5000), Quantity: 3},
+		},
+	})
+	require.NoError(t, err)
+
+	summary := response.Data.(*models.CashShiftSummary)
+	assert.Equal(t, types.ShiftStatusClosed, summary.Status)
+	assert.Equal(t, 3, summary.CashOrders)
+	assert.True(t, summary.CashRefunds.Equals(amount(20000)))
+	assert.True(t, summary.CashVoids.Equals(amount(30000)))
+	assert.True(t, summary.CurrentCash.Equals(amount(240000)))
+	assert.Len(t, summary.Counts, 3)
+	mockShiftRepo.AssertNumberOfCalls(t, "CreateCount", 3)
+	mockShiftRepo.AssertExpectations(t)
+}
+
+func TestShiftService_CloseShift_DuplicateDenomination(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepo)
+	shiftService := services.NewShiftService(&repositories.Repository{ShiftRepo: mockShiftRepo}, nil)
+
+	_, err := shiftService.CloseShift(shiftID, cashierID, string(types.UserRoleCashier), &models.CashShiftClose{
+		Counts: []models.CashCount{
+			{Denomination: amount(50000), Quantity: 1},
+			{Denomination: amount(50000), Quantity: 2},
+		},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "more than once")
+	mockShiftRepo.AssertNotCalled(t, "GetShiftForUpdate", shiftID)
+}
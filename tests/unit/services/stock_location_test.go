@@ -0,0 +1,316 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStockLocationRepo is a mock implementation of StockLocationRepo interface
+type MockStockLocationRepo struct {
+	mock.Mock
+}
+
+func (m *MockStockLocationRepo) CreateStockLocation(location *models.StockLocation) (*models.StockLocation, error) {
+	args := m.Called(location)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLocation), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) GetStockLocation(id string) (*models.StockLocation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLocation), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) GetSellingStockLocation() (*models.StockLocation, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLocation), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) ListStockLocations(includeInactive bool) ([]*models.StockLocation, error) {
+	args := m.Called(includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockLocation), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) UpdateStockLocation(location *models.StockLocation) (*models.StockLocation, error) {
+	args := m.Called(location)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLocation), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) ClearSellingStockLocation(exceptID string) error {
+	args := m.Called(exceptID)
+	return args.Error(0)
+}
+
+func (m *MockStockLocationRepo) ListStockBalances(locationID string) ([]*models.StockBalance, error) {
+	args := m.Called(locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockBalance), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) LockItemStock(menuItemID, ingredientID *string) (types.DecimalText, error) {
+	args := m.Called(menuItemID, ingredientID)
+	return args.Get(0).(types.DecimalText), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) AdjustStockBalance(locationID, menuItemID string, ingredientID *string, change types.DecimalText) error {
+	args := m.Called(locationID, menuItemID, ingredientID, change)
+	return args.Error(0)
+}
+
+func (m *MockStockLocationRepo) CreateStockTransfer(transfer *models.StockTransfer) (*models.StockTransfer, error) {
+	args := m.Called(transfer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) CreateStockTransferLine(line *models.StockTransferLine) error {
+	args := m.Called(line)
+	return args.Error(0)
+}
+
+func (m *MockStockLocationRepo) GetStockTransfer(id string) (*models.StockTransfer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) ListStockTransfers(filter models.StockTransferFilter) ([]*models.StockTransfer, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockLocationRepo) ListStockTransferLines(id string) ([]models.StockTransferLine, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockTransferLine), args.Error(1)
+}
+
+const (
+	storeRoomID     = "3b4c5d6e-7f80-4912-a3b4-c5d6e7f80912"
+	barID           = "8e9f0a1b-2c3d-4e4f-9a5b-6c7d8e9f0a1b"
+	stockTransferID = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+)
+
+// storeRoom is the selling location
+func storeRoom() *models.StockLocation {
+	return &models.StockLocation{ID: storeRoomID, Code: "MAIN", Name: "Store room", IsSelling: true, IsActive: true}
+}
+
+// sellingStockLocation holds all stock at the store room, for tests that move
+// stock without caring where it is kept
+func sellingStockLocation() *MockStockLocationRepo {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	mockStockLocationRepo.On("GetSellingStockLocation").Return(storeRoom(), nil).Maybe()
+	mockStockLocationRepo.On("AdjustStockBalance", storeRoomID, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockStockLocationRepo
+}
+
+func bar() *models.StockLocation {
+	return &models.StockLocation{ID: barID, Code: "BAR", Name: "Bar", IsActive: true}
+}
+
+func beansTransfer() *models.StockTransfer {
+	return &models.StockTransfer{
+		ID:               stockTransferID,
+		TransferNumber:   "TR-000001",
+		FromLocationID:   storeRoomID,
+		FromLocationName: "Store room",
+		ToLocationID:     barID,
+		ToLocationName:   "Bar",
+		CreatedBy:        stockUserID,
+	}
+}
+
+// beansIngredient matches the ingredient ID of a beans stock movement
+func beansIngredient() interface{} {
+	return mock.MatchedBy(func(ingredientID *string) bool {
+		return ingredientID != nil && *ingredientID == beansID
+	})
+}
+
+// transferringBeans expects a transfer of beans from the store room to the bar
+// to be looked up and started
+func transferringBeans(mockStockLocationRepo *MockStockLocationRepo, mockIngredientRepo *MockIngredientRepo) {
+	mockStockLocationRepo.On("GetStockLocation", storeRoomID).Return(storeRoom(), nil)
+	mockStockLocationRepo.On("GetStockLocation", barID).Return(bar(), nil)
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(2500)}, nil)
+	mockStockLocationRepo.On("CreateStockTransfer", mock.MatchedBy(func(transfer *models.StockTransfer) bool {
+		return transfer.FromLocationID == storeRoomID && transfer.ToLocationID == barID && transfer.CreatedBy == stockUserID
+	})).Return(beansTransfer(), nil)
+	mockStockLocationRepo.On("LockItemStock", (*string)(nil), mock.MatchedBy(func(ingredientID *string) bool {
+		return ingredientID != nil && *ingredientID == beansID
+	})).Return(stockOf(2500), nil)
+}
+
+func TestStockLocationService_CreateStockTransfer_MovesStockBetweenLocations(t *testing.T) {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	stockLocationService := services.NewStockLocationService(&repositories.Repository{
+		StockLocationRepo:    mockStockLocationRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		UnitRepo:             seededUnits(),
+		StockTransactionRepo: mockStockTransactionRepo,
+		LowStockAlertRepo:    noStockAlerts(),
+	}, nil, nil)
+
+	transferringBeans(mockStockLocationRepo, mockIngredientRepo)
+
+	// The beans leave the store room and arrive at the bar; the 2500 g held
+	// across both is the same afterwards
+	mockStockLocationRepo.On("AdjustStockBalance", storeRoomID, "", beansIngredient(), stockMatching("-1000")).Return(nil).Once()
+	mockStockLocationRepo.On("AdjustStockBalance", barID, "", beansIngredient(), stockMatching("1000")).Return(nil).Once()
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeOut &&
+			*transaction.IngredientID == beansID &&
+			*transaction.LocationID == storeRoomID &&
+			transaction.Quantity.Equals(stockOf(-1000)) &&
+			transaction.PreviousStock.Equals(stockOf(2500)) &&
+			transaction.CurrentStock.Equals(stockOf(1500)) &&
+			transaction.Reason == "Transfer TR-000001 to Bar" &&
+			*transaction.ReferenceType == types.ReferenceTypeStockTransfer &&
+			*transaction.ReferenceID == stockTransferID
+	})).Return(&models.StockTransaction{}, nil).Once()
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeIn &&
+			*transaction.IngredientID == beansID &&
+			*transaction.LocationID == barID &&
+			transaction.Quantity.Equals(stockOf(1000)) &&
+			transaction.PreviousStock.Equals(stockOf(1500)) &&
+			transaction.CurrentStock.Equals(stockOf(2500)) &&
+			transaction.Reason == "Transfer TR-000001 from Store room"
+	})).Return(&models.StockTransaction{}, nil).Once()
+	mockStockLocationRepo.On("CreateStockTransferLine", mock.MatchedBy(func(line *models.StockTransferLine) bool {
+		return line.StockTransferID == stockTransferID && *line.IngredientID == beansID && line.Unit == "g" && line.Quantity.Equals(stockOf(1000))
+	})).Return(nil)
+	mockStockLocationRepo.On("GetStockTransfer", stockTransferID).Return(beansTransfer(), nil)
+	mockStockLocationRepo.On("ListStockTransferLines", stockTransferID).Return([]models.StockTransferLine{}, nil)
+
+	// A 1 kg bag is moved in grams
+	ingredientID, unit := beansID, "kg"
+	response, err := stockLocationService.CreateStockTransfer(stockUserID, &models.StockTransferCreate{
+		FromLocationID: storeRoomID,
+		ToLocationID:   barID,
+		Items:          []models.StockTransferItemInput{{IngredientID: &ingredientID, Quantity: stockOf(1), Unit: &unit}},
+	})
+	require.NoError(t, err)
+
+	summary := response.Data.(*models.StockTransferSummary)
+	assert.Equal(t, "TR-000001", summary.TransferNumber)
+	mockStockLocationRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+}
+
+func TestStockLocationService_CreateStockTransfer_RejectsShortageAtLocation(t *testing.T) {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	stockLocationService := services.NewStockLocationService(&repositories.Repository{
+		StockLocationRepo:    mockStockLocationRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		LowStockAlertRepo:    noStockAlerts(),
+	}, nil, nil)
+
+	transferringBeans(mockStockLocationRepo, mockIngredientRepo)
+
+	// Most of the beans are already at the bar, so the store room is short
+	mockStockLocationRepo.On("AdjustStockBalance", storeRoomID, "", beansIngredient(), stockMatching("-1000")).Return(fmt.Errorf("%w Store room", repositories.ErrInsufficientLocationStock))
+
+	ingredientID := beansID
+	_, err := stockLocationService.CreateStockTransfer(stockUserID, &models.StockTransferCreate{
+		FromLocationID: storeRoomID,
+		ToLocationID:   barID,
+		Items:          []models.StockTransferItemInput{{IngredientID: &ingredientID, Quantity: stockOf(1000)}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient stock of Coffee beans at Store room")
+
+	mockStockTransactionRepo.AssertNotCalled(t, "CreateStockTransaction", mock.Anything)
+	mockStockLocationRepo.AssertNotCalled(t, "CreateStockTransferLine", mock.Anything)
+}
+
+func TestStockLocationService_CreateStockTransfer_RejectsSameLocation(t *testing.T) {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	stockLocationService := services.NewStockLocationService(&repositories.Repository{StockLocationRepo: mockStockLocationRepo}, nil, nil)
+
+	ingredientID := beansID
+	_, err := stockLocationService.CreateStockTransfer(stockUserID, &models.StockTransferCreate{
+		FromLocationID: barID,
+		ToLocationID:   barID,
+		Items:          []models.StockTransferItemInput{{IngredientID: &ingredientID, Quantity: stockOf(1000)}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different location")
+
+	mockStockLocationRepo.AssertNotCalled(t, "CreateStockTransfer", mock.Anything)
+}
+
+func TestStockLocationService_CreateStockLocation_TakesOverSelling(t *testing.T) {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	stockLocationService := services.NewStockLocationService(&repositories.Repository{StockLocationRepo: mockStockLocationRepo}, nil, nil)
+
+	mockStockLocationRepo.On("ClearSellingStockLocation", "00000000-0000-0000-0000-000000000000").Return(nil).Once()
+	mockStockLocationRepo.On("CreateStockLocation", mock.MatchedBy(func(location *models.StockLocation) bool {
+		return location.Code == "BAR" && location.Name == "Bar" && location.IsSelling
+	})).Return(&models.StockLocation{ID: barID, Code: "BAR", Name: "Bar", IsSelling: true, IsActive: true}, nil)
+
+	_, err := stockLocationService.CreateStockLocation(&models.StockLocationCreate{Code: " bar ", Name: "Bar", IsSelling: true})
+	require.NoError(t, err)
+
+	mockStockLocationRepo.AssertExpectations(t)
+}
+
+func TestStockLocationService_UpdateStockLocation_KeepsStockedLocationActive(t *testing.T) {
+	mockStockLocationRepo := new(MockStockLocationRepo)
+	stockLocationService := services.NewStockLocationService(&repositories.Repository{StockLocationRepo: mockStockLocationRepo}, nil, nil)
+
+	beans := beansID
+	mockStockLocationRepo.On("GetStockLocation", barID).Return(bar(), nil)
+	mockStockLocationRepo.On("ListStockBalances", barID).Return([]*models.StockBalance{
+		{LocationID: barID, IngredientID: &beans, ItemName: "Coffee beans", ItemType: "ingredient", Unit: "g", Quantity: stockOf(1000)},
+	}, nil)
+
+	inactive := false
+	_, err := stockLocationService.UpdateStockLocation(barID, &models.StockLocationUpdate{IsActive: &inactive})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Bar still holds stock")
+
+	mockStockLocationRepo.AssertNotCalled(t, "UpdateStockLocation", mock.Anything)
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStockLotRepo is a mock implementation of StockLotRepo interface
+type MockStockLotRepo struct {
+	mock.Mock
+}
+
+func (m *MockStockLotRepo) CreateStockLot(lot *models.StockLot) (*models.StockLot, error) {
+	args := m.Called(lot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLot), args.Error(1)
+}
+
+func (m *MockStockLotRepo) ListStockLots(filter models.StockLotFilter) ([]*models.StockLot, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockLot), args.Error(1)
+}
+
+func (m *MockStockLotRepo) ListStockLotsExpiringBefore(before time.Time) ([]*models.StockLot, error) {
+	args := m.Called(before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockLot), args.Error(1)
+}
+
+func (m *MockStockLotRepo) GetExpiredStock(menuItemID string, ingredientID *string) (types.DecimalText, error) {
+	args := m.Called(menuItemID, ingredientID)
+	return args.Get(0).(types.DecimalText), args.Error(1)
+}
+
+func (m *MockStockLotRepo) ConsumeStockLots(menuItemID string, ingredientID *string, quantity types.DecimalText) error {
+	args := m.Called(menuItemID, ingredientID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockStockLotRepo) EmptyStockLot(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// consumableLots takes stock out of lots for tests that do not care which
+// lots it comes from, none of which have expired
+func consumableLots() *MockStockLotRepo {
+	mockStockLotRepo := new(MockStockLotRepo)
+	mockStockLotRepo.On("GetExpiredStock", mock.Anything, mock.Anything).Return(stockOf(0), nil).Maybe()
+	mockStockLotRepo.On("ConsumeStockLots", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockStockLotRepo
+}
+
+const expiredMilkLotID = "2f3a4b5c-6d7e-4f8a-9b0c-1d2e3f4a5b6c"
+
+// expiredMilkLot is a lot of milk that expired yesterday with 300 ml left,
+// bought at 20 a millilitre
+func expiredMilkLot() *models.StockLot {
+	ingredientID := milkID
+	lotNumber := "M-0412"
+	expiresAt := time.Now().AddDate(0, 0, -1)
+	return &models.StockLot{
+		ID:                expiredMilkLotID,
+		IngredientID:      &ingredientID,
+		ItemName:          "Milk",
+		LotNumber:         &lotNumber,
+		Unit:              "ml",
+		QuantityReceived:  stockOf(1000),
+		QuantityRemaining: stockOf(300),
+		UnitCost:          amount(20),
+		ReceivedAt:        expiresAt.AddDate(0, 0, -7),
+		ExpiresAt:         &expiresAt,
+	}
+}
+
+func TestWasteService_WriteOffExpiredLots_WritesOffAtLotCost(t *testing.T) {
+	mockWasteRepo := new(MockWasteRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockStockLotRepo := new(MockStockLotRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{
+		WasteRepo:            mockWasteRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	mockStockLotRepo.On("ListStockLotsExpiringBefore", mock.AnythingOfType("time.Time")).Return([]*models.StockLot{expiredMilkLot()}, nil)
+	mockIngredientRepo.On("GetIngredient", milkID).Return(&models.Ingredient{ID: milkID, Name: "Milk", Unit: "ml", CurrentStock: stockOf(1000), CostPrice: amount(25)}, nil)
+
+	// The 300 ml left are valued at what the lot cost, not the current cost price
+	mockWasteRepo.On("CreateWasteRecord", mock.MatchedBy(func(record *models.WasteRecord) bool {
+		return record.Reason == types.WasteReasonExpired &&
+			*record.IngredientID == milkID &&
+			record.Quantity.Equals(stockOf(300)) &&
+			record.Unit == "ml" &&
+			record.TotalCost.Equals(amount(6000)) &&
+			*record.Notes == "Lot M-0412 expired on "+time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	})).Return(&models.WasteRecord{ID: wasteRecordID}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", milkID, stockMatching("-300"), stockUserID).Return(stockOf(1000), stockOf(700), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeOut &&
+			*transaction.ReferenceType == types.ReferenceTypeWaste &&
+			*transaction.ReferenceID == wasteRecordID
+	})).Return(&models.StockTransaction{}, nil)
+	mockStockLotRepo.On("EmptyStockLot", expiredMilkLotID).Return(nil)
+
+	response, err := wasteService.WriteOffExpiredLots(stockUserID)
+	require.NoError(t, err)
+	assert.Len(t, response.Data, 1)
+
+	// The milk comes out of the expired lot, not the lots still good to sell
+	mockStockLotRepo.AssertNotCalled(t, "ConsumeStockLots", mock.Anything, mock.Anything, mock.Anything)
+	mockWasteRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+	mockStockLotRepo.AssertExpectations(t)
+}
+
+func TestWasteService_WriteOffExpiredLots_WritesOffNoMoreThanInStock(t *testing.T) {
+	mockWasteRepo := new(MockWasteRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockStockLotRepo := new(MockStockLotRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{
+		WasteRepo:            mockWasteRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	// Only 100 ml of milk is left in stock, so the rest of the lot is already gone
+	mockStockLotRepo.On("ListStockLotsExpiringBefore", mock.AnythingOfType("time.Time")).Return([]*models.StockLot{expiredMilkLot()}, nil)
+	mockIngredientRepo.On("GetIngredient", milkID).Return(&models.Ingredient{ID: milkID, Name: "Milk", Unit: "ml", CurrentStock: stockOf(100)}, nil)
+	mockWasteRepo.On("CreateWasteRecord", mock.MatchedBy(func(record *models.WasteRecord) bool {
+		return record.Quantity.Equals(stockOf(100)) && record.TotalCost.Equals(amount(2000))
+	})).Return(&models.WasteRecord{ID: wasteRecordID}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", milkID, stockMatching("-100"), stockUserID).Return(stockOf(100), stockOf(0), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.Anything).Return(&models.StockTransaction{}, nil)
+	mockStockLotRepo.On("EmptyStockLot", expiredMilkLotID).Return(nil)
+
+	_, err := wasteService.WriteOffExpiredLots(stockUserID)
+	require.NoError(t, err)
+
+	mockWasteRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockLotRepo.AssertExpectations(t)
+}
+
+func TestInventoryService_ListExpiringStockLots(t *testing.T) {
+	mockStockLotRepo := new(MockStockLotRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{StockLotRepo: mockStockLotRepo}, nil, nil)
+
+	// Lots expiring within 3 days are those expiring before 3 days from now
+	mockStockLotRepo.On("ListStockLotsExpiringBefore", mock.MatchedBy(func(before time.Time) bool {
+		return before.Sub(time.Now().AddDate(0, 0, 3)).Abs() < time.Minute
+	})).Return([]*models.StockLot{expiredMilkLot()}, nil)
+
+	response, err := inventoryService.ListExpiringStockLots(3)
+	require.NoError(t, err)
+	assert.Len(t, response.Data, 1)
+
+	_, err = inventoryService.ListExpiringStockLots(-1)
+	require.Error(t, err)
+
+	mockStockLotRepo.AssertExpectations(t)
+}
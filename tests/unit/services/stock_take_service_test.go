@@ -0,0 +1,216 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStockTakeRepo is a mock implementation of StockTakeRepo interface
+type MockStockTakeRepo struct {
+	mock.Mock
+}
+
+func (m *MockStockTakeRepo) CreateStockTake(stockTake *models.StockTake) (*models.StockTake, error) {
+	args := m.Called(stockTake)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeRepo) SnapshotStockTakeMenuItems(id string, categoryID *string) error {
+	args := m.Called(id, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockStockTakeRepo) SnapshotStockTakeIngredients(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStockTakeRepo) GetStockTake(id string) (*models.StockTake, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeRepo) GetStockTakeForUpdate(id string) (*models.StockTake, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeRepo) ListStockTakes(filter models.StockTakeFilter) ([]*models.StockTake, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeRepo) ListStockTakeLines(id string) ([]models.StockTakeLine, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockTakeLine), args.Error(1)
+}
+
+func (m *MockStockTakeRepo) SetStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error {
+	args := m.Called(lineID, quantity, countedBy)
+	return args.Error(0)
+}
+
+func (m *MockStockTakeRepo) AddStockTakeLineCount(lineID string, quantity types.DecimalText, countedBy string) error {
+	args := m.Called(lineID, quantity, countedBy)
+	return args.Error(0)
+}
+
+func (m *MockStockTakeRepo) UpdateStockTakeStatus(id string, status types.StockTakeStatus, approvedBy *string) error {
+	args := m.Called(id, status, approvedBy)
+	return args.Error(0)
+}
+
+const (
+	stockTakeID    = "6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"
+	beansLineID    = "7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+	milkLineID     = "8b9c0d1e-2f3a-4b4c-9d5e-6f7a8b9c0d1e"
+	latteLineID    = "9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f"
+	stockCounterID = "0d1e2f3a-4b5c-4d6e-9f7a-8b9c0d1e2f3a"
+)
+
+func openStockTake() *models.StockTake {
+	return &models.StockTake{ID: stockTakeID, StockTakeNumber: "ST-000001", Status: types.StockTakeStatusOpen}
+}
+
+// countedStockTakeLines returns the lines of a stock take in which 450 g of
+// coffee beans were counted against 500 g expected, the lattes counted match
+// and the milk has not been counted yet
+func countedStockTakeLines() []models.StockTakeLine {
+	beans, milk, latte := beansID, milkID, latteID
+	counted, latteCount := stockOf(450), stockOf(12)
+	beansVariance, latteVariance := stockOf(-50), stockOf(0)
+	beansValue, latteValue := amount(-7500), amount(0)
+	return []models.StockTakeLine{
+		{ID: beansLineID, IngredientID: &beans, ItemName: "Coffee beans", Unit: "g", SystemQuantity: stockOf(500), CountedQuantity: &counted, UnitCost: amount(150), Variance: &beansVariance, VarianceValue: &beansValue},
+		{ID: milkLineID, IngredientID: &milk, ItemName: "Milk", Unit: "ml", SystemQuantity: stockOf(1000), UnitCost: amount(20)},
+		{ID: latteLineID, MenuItemID: &latte, ItemName: "Latte", Unit: "pcs", SystemQuantity: stockOf(12), CountedQuantity: &latteCount, UnitCost: amount(15000), Variance: &latteVariance, VarianceValue: &latteValue},
+	}
+}
+
+func TestStockTakeService_ApproveStockTake_PostsVariances(t *testing.T) {
+	mockStockTakeRepo := new(MockStockTakeRepo)
+	mockInventoryRepo := new(MockInventoryRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	stockTakeService := services.NewStockTakeService(&repositories.Repository{
+		StockTakeRepo:        mockStockTakeRepo,
+		MenuRepo:             unchangedMenu(),
+		InventoryRepo:        mockInventoryRepo,
+		IngredientRepo:       mockIngredientRepo,
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         consumableLots(),
+	}, nil, nil)
+
+	mockStockTakeRepo.On("GetStockTakeForUpdate", stockTakeID).Return(openStockTake(), nil)
+	mockStockTakeRepo.On("ListStockTakeLines", stockTakeID).Return(countedStockTakeLines(), nil)
+
+	// 20 g of beans were used while the count was in progress, so the 50 g
+	// missing are taken off the 480 g now in stock rather than setting it to 450 g
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-50"), reportManagerID).Return(stockOf(480), stockOf(430), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeAdjustment &&
+			*transaction.IngredientID == beansID &&
+			transaction.Quantity.Equals(stockOf(-50)) &&
+			transaction.Reason == "Stock take ST-000001" &&
+			*transaction.ReferenceType == types.ReferenceTypeStockTake &&
+			*transaction.ReferenceID == stockTakeID
+	})).Return(&models.StockTransaction{}, nil).Once()
+
+	approvedBy := reportManagerID
+	mockStockTakeRepo.On("UpdateStockTakeStatus", stockTakeID, types.StockTakeStatusApproved, &approvedBy).Return(nil)
+	mockStockTakeRepo.On("GetStockTake", stockTakeID).Return(openStockTake(), nil)
+
+	response, err := stockTakeService.ApproveStockTake(stockTakeID, reportManagerID)
+	require.NoError(t, err)
+
+	summary := response.Data.(*models.StockTakeSummary)
+	assert.Equal(t, 3, summary.TotalLines)
+	assert.Equal(t, 2, summary.CountedLines)
+	assert.True(t, summary.VarianceValue.Equals(amount(-7500)))
+
+	// Neither the matching lattes nor the uncounted milk are adjusted
+	mockInventoryRepo.AssertNotCalled(t, "AdjustInventoryStock", mock.Anything, mock.Anything, mock.Anything)
+	mockIngredientRepo.AssertNotCalled(t, "AdjustIngredientStock", milkID, mock.Anything, mock.Anything)
+	mockStockTakeRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+}
+
+func TestStockTakeService_RecordScan_AddsInStockUnit(t *testing.T) {
+	mockStockTakeRepo := new(MockStockTakeRepo)
+	stockTakeService := services.NewStockTakeService(&repositories.Repository{StockTakeRepo: mockStockTakeRepo, UnitRepo: seededUnits()}, nil, nil)
+
+	mockStockTakeRepo.On("GetStockTakeForUpdate", stockTakeID).Return(openStockTake(), nil)
+	mockStockTakeRepo.On("ListStockTakeLines", stockTakeID).Return(countedStockTakeLines(), nil)
+	mockStockTakeRepo.On("GetStockTake", stockTakeID).Return(openStockTake(), nil)
+
+	// A 1 kg bag of beans is counted as 1000 g on top of what was counted before
+	mockStockTakeRepo.On("AddStockTakeLineCount", beansLineID, stockMatching("1000"), stockCounterID).Return(nil)
+
+	ingredientID, unit, bag := beansID, "kg", stockOf(1)
+	_, err := stockTakeService.RecordScan(stockTakeID, stockCounterID, &models.StockTakeScan{IngredientID: &ingredientID, Quantity: &bag, Unit: &unit})
+	require.NoError(t, err)
+
+	mockStockTakeRepo.AssertExpectations(t)
+}
+
+func TestStockTakeService_RecordCounts_RejectsApprovedStockTake(t *testing.T) {
+	mockStockTakeRepo := new(MockStockTakeRepo)
+	stockTakeService := services.NewStockTakeService(&repositories.Repository{StockTakeRepo: mockStockTakeRepo}, nil, nil)
+
+	stockTake := openStockTake()
+	stockTake.Status = types.StockTakeStatusApproved
+	mockStockTakeRepo.On("GetStockTakeForUpdate", stockTakeID).Return(stockTake, nil)
+
+	menuItemID := latteID
+	counted := stockOf(10)
+	_, err := stockTakeService.RecordCounts(stockTakeID, stockCounterID, &models.StockTakeCounts{
+		Counts: []models.StockTakeCountInput{{MenuItemID: &menuItemID, Quantity: &counted}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot record counts: stock take is approved")
+
+	mockStockTakeRepo.AssertNotCalled(t, "SetStockTakeLineCount", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStockTakeService_RecordCounts_RejectsMissingQuantity(t *testing.T) {
+	mockStockTakeRepo := new(MockStockTakeRepo)
+	stockTakeService := services.NewStockTakeService(&repositories.Repository{StockTakeRepo: mockStockTakeRepo}, nil, nil)
+
+	mockStockTakeRepo.On("GetStockTakeForUpdate", stockTakeID).Return(openStockTake(), nil)
+	mockStockTakeRepo.On("ListStockTakeLines", stockTakeID).Return(countedStockTakeLines(), nil)
+
+	menuItemID := latteID
+	_, err := stockTakeService.RecordCounts(stockTakeID, stockCounterID, &models.StockTakeCounts{
+		Counts: []models.StockTakeCountInput{{MenuItemID: &menuItemID}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "counted quantity is required")
+
+	mockStockTakeRepo.AssertNotCalled(t, "SetStockTakeLineCount", mock.Anything, mock.Anything, mock.Anything)
+}
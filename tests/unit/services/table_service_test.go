@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTableRepo is a mock implementation of TableRepo interface
+type MockTableRepo struct {
+	mock.Mock
+}
+
+func (m *MockTableRepo) CreateFloorArea(area *models.FloorArea) (*models.FloorArea, error) {
+	args := m.Called(area)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FloorArea), args.Error(1)
+}
+
+func (m *MockTableRepo) GetFloorArea(id string) (*models.FloorArea, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FloorArea), args.Error(1)
+}
+
+func (m *MockTableRepo) ListFloorAreas(isActive *bool) ([]*models.FloorArea, error) {
+	args := m.Called(isActive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FloorArea), args.Error(1)
+}
+
+func (m *MockTableRepo) UpdateFloorArea(area *models.FloorArea) (*models.FloorArea, error) {
+	args := m.Called(area)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FloorArea), args.Error(1)
+}
+
+func (m *MockTableRepo) DeleteFloorArea(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTableRepo) CountTablesByFloorArea(floorAreaID string) (int, error) {
+	args := m.Called(floorAreaID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTableRepo) CreateTable(table *models.DiningTable) (*models.DiningTable, error) {
+	args := m.Called(table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DiningTable), args.Error(1)
+}
+
+func (m *MockTableRepo) GetTable(id string) (*models.DiningTable, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DiningTable), args.Error(1)
+}
+
+func (m *MockTableRepo) ListTables(filter models.DiningTableFilter) ([]*models.DiningTable, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DiningTable), args.Error(1)
+}
+
+func (m *MockTableRepo) UpdateTable(table *models.DiningTable) (*models.DiningTable, error) {
+	args := m.Called(table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DiningTable), args.Error(1)
+}
+
+func (m *MockTableRepo) DeleteTable(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTableRepo) CountOpenOrdersByTable(tableID string) (int, error) {
+	args := m.Called(tableID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTableRepo) ListOpenTableOrders() ([]*models.TableOrder, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TableOrder), args.Error(1)
+}
+
+const (
+	indoorAreaID = "9b1c0c1e-3a52-4a36-9f57-0d4c2e7f6a10"
+	terraceID    = "4e0d7f63-21a8-4c9e-8d8b-77f1a2b3c4d5"
+	tableOneID   = "0f6f1a8e-5b1d-4d7e-a0f2-3c9b8e7d6c01"
+	tableTwoID   = "0f6f1a8e-5b1d-4d7e-a0f2-3c9b8e7d6c02"
+	tableThreeID = "0f6f1a8e-5b1d-4d7e-a0f2-3c9b8e7d6c03"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestTableService_GetFloorPlan(t *testing.T) {
+	mockTableRepo := new(MockTableRepo)
+	tableService := services.NewTableService(mockTableRepo)
+
+	mockTableRepo.On("ListFloorAreas", mock.Anything).Return([]*models.FloorArea{
+		{ID: indoorAreaID, Name: "Indoor", IsActive: true},
+		{ID: terraceID, Name: "Terrace", IsActive: true},
+	}, nil)
+	mockTableRepo.On("ListTables", mock.Anything).Return([]*models.DiningTable{
+		{ID: tableOneID, FloorAreaID: indoorAreaID, Name: "T1", Seats: 4, IsActive: true},
+		{ID: tableTwoID, FloorAreaID: indoorAreaID, Name: "T2", Seats: 2, IsActive: true},
+		{ID: tableThreeID, FloorAreaID: terraceID, Name: "P1", Seats: 6, IsActive: true},
+	}, nil)
+	mockTableRepo.On("ListOpenTableOrders").Return([]*models.TableOrder{
+		{ID: "order-1", OrderNumber: "ORD-20261016-0001", TableID: tableOneID, Status: types.OrderStatusPending, GuestCount: intPtr(3), TotalAmount: types.DecimalText(decimal.NewFromInt(90000))},
+		{ID: "order-2", OrderNumber: "ORD-20261016-0004", TableID: tableOneID, Status: types.OrderStatusDraft, GuestCount: intPtr(1), TotalAmount: types.DecimalText(decimal.NewFromInt(25000))},
+		{ID: "order-3", OrderNumber: "ORD-20261016-0005", TableID: tableThreeID, Status: types.OrderStatusPending, TotalAmount: types.DecimalText(decimal.NewFromInt(40000))},
+	}, nil)
+
+	response, err := tableService.GetFloorPlan(nil)
+	require.NoError(t, err)
+
+	floorPlan := response.Data.([]models.FloorAreaWithTables)
+	require.Len(t, floorPlan, 2)
+
+	indoor := floorPlan[0]
+	assert.Equal(t, "Indoor", indoor.Name)
+	require.Len(t, indoor.Tables, 2)
+
+	// A table with a split bill shows both tabs and all of its guests
+	assert.True(t, indoor.Tables[0].Occupied)
+	assert.Len(t, indoor.Tables[0].OpenOrders, 2)
+	assert.Equal(t, 4, indoor.Tables[0].GuestCount)
+
+	assert.False(t, indoor.Tables[1].Occupied)
+	assert.Empty(t, indoor.Tables[1].OpenOrders)
+
+	terrace := floorPlan[1]
+	require.Len(t, terrace.Tables, 1)
+	assert.True(t, terrace.Tables[0].Occupied)
+	assert.Equal(t, 0, terrace.Tables[0].GuestCount)
+
+	mockTableRepo.AssertExpectations(t)
+}
+
+func TestTableService_DeleteTable_WithOpenOrders(t *testing.T) {
+	mockTableRepo := new(MockTableRepo)
+	tableService := services.NewTableService(mockTableRepo)
+
+	mockTableRepo.On("GetTable", tableOneID).Return(&models.DiningTable{ID: tableOneID, FloorAreaID: indoorAreaID, Name: "T1", IsActive: true}, nil)
+	mockTableRepo.On("CountOpenOrdersByTable", tableOneID).Return(1, nil)
+
+	_, err := tableService.DeleteTable(tableOneID)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "open orders")
+	mockTableRepo.AssertNotCalled(t, "DeleteTable", tableOneID)
+}
+
+func TestTableService_DeleteFloorArea_WithTables(t *testing.T) {
+	mockTableRepo := new(MockTableRepo)
+	tableService := services.NewTableService(mockTableRepo)
+
+	mockTableRepo.On("GetFloorArea", terraceID).Return(&models.FloorArea{ID: terraceID, Name: "Terrace", IsActive: true}, nil)
+	mockTableRepo.On("CountTablesByFloorArea", terraceID).Return(1, nil)
+
+	_, err := tableService.DeleteFloorArea(terraceID)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "still has 1 tables")
+	mockTableRepo.AssertNotCalled(t, "DeleteFloorArea", terraceID)
+}
@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUnitRepo is a mock implementation of UnitRepo interface
+type MockUnitRepo struct {
+	mock.Mock
+}
+
+func (m *MockUnitRepo) ListUnits() ([]*models.UnitOfMeasure, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UnitOfMeasure), args.Error(1)
+}
+
+func (m *MockUnitRepo) GetUnit(code string) (*models.UnitOfMeasure, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UnitOfMeasure), args.Error(1)
+}
+
+func (m *MockUnitRepo) CreateUnit(unit *models.UnitOfMeasure) (*models.UnitOfMeasure, error) {
+	args := m.Called(unit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UnitOfMeasure), args.Error(1)
+}
+
+// seededUnits returns a unit repository holding grams, kilograms and millilitres
+func seededUnits() *MockUnitRepo {
+	mockUnitRepo := new(MockUnitRepo)
+	mockUnitRepo.On("GetUnit", "g").Return(&models.UnitOfMeasure{Code: "g", Name: "Gram", Dimension: "mass", Factor: stockOf(1)}, nil)
+	mockUnitRepo.On("GetUnit", "kg").Return(&models.UnitOfMeasure{Code: "kg", Name: "Kilogram", Dimension: "mass", Factor: stockOf(1000)}, nil)
+	mockUnitRepo.On("GetUnit", "ml").Return(&models.UnitOfMeasure{Code: "ml", Name: "Millilitre", Dimension: "volume", Factor: stockOf(1)}, nil)
+	mockUnitRepo.On("GetUnit", mock.Anything).Return(nil, errors.New("unit of measure not found"))
+	return mockUnitRepo
+}
+
+func TestInventoryService_UpdateStock_ConvertsToStockUnit(t *testing.T) {
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockStockLotRepo := new(MockStockLotRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		UnitRepo:             seededUnits(),
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	beans := &models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CurrentStock: stockOf(250)}
+	mockIngredientRepo.On("GetIngredient", beansID).Return(beans, nil)
+
+	// A 1.25 kg bag goes into stock as 1250 g
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("1250"), stockUserID).Return(stockOf(250), stockOf(1500), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeIn && transaction.Quantity.Equals(stockOf(1250))
+	})).Return(&models.StockTransaction{}, nil)
+	mockStockLotRepo.On("CreateStockLot", mock.MatchedBy(func(lot *models.StockLot) bool {
+		return *lot.IngredientID == beansID && lot.Unit == "g" && lot.QuantityReceived.Equals(stockOf(1250))
+	})).Return(&models.StockLot{}, nil)
+
+	kg := "kg"
+	ingredientID := beansID
+	_, err := inventoryService.UpdateStock(stockUserID, &models.InventoryUpdate{
+		IngredientID: &ingredientID,
+		Quantity:     types.FromDecimal(decimal.RequireFromString("1.25")),
+		Unit:         &kg,
+		Reason:       "Delivery",
+	})
+	require.NoError(t, err)
+
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+	mockStockLotRepo.AssertExpectations(t)
+}
+
+func TestInventoryService_UpdateStock_RejectsIncompatibleUnit(t *testing.T) {
+	mockIngredientRepo := new(MockIngredientRepo)
+	inventoryService := services.NewInventoryService(&repositories.Repository{IngredientRepo: mockIngredientRepo, UnitRepo: seededUnits()}, nil, nil)
+
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g"}, nil)
+
+	ml := "ml"
+	ingredientID := beansID
+	_, err := inventoryService.UpdateStock(stockUserID, &models.InventoryUpdate{
+		IngredientID: &ingredientID,
+		Quantity:     stockOf(500),
+		Unit:         &ml,
+		Reason:       "Delivery",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incompatible units: cannot convert ml to g")
+
+	mockIngredientRepo.AssertNotCalled(t, "AdjustIngredientStock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecipeService_SetMenuItemRecipe_StoresStockUnitQuantities(t *testing.T) {
+	mockRecipeRepo := new(MockRecipeRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockMenuRepo := new(MockMenuRepo)
+	recipeService := services.NewRecipeService(&repositories.Repository{
+		RecipeRepo:     mockRecipeRepo,
+		IngredientRepo: mockIngredientRepo,
+		MenuRepo:       mockMenuRepo,
+		UnitRepo:       seededUnits(),
+	}, nil)
+
+	mockMenuRepo.On("GetMenuItem", latteID).Return(&models.MenuItem{ID: latteID, Name: "Latte"}, nil)
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", IsActive: true}, nil)
+
+	// 0.018 kg of beans is stored as 18 g
+	mockRecipeRepo.On("SetMenuItemRecipe", latteID, mock.MatchedBy(func(items []models.RecipeItemInput) bool {
+		return len(items) == 1 && items[0].Quantity.Equals(stockOf(18)) && *items[0].Unit == "g"
+	})).Return(nil)
+	mockRecipeRepo.On("GetMenuItemRecipe", latteID).Return([]models.RecipeItem{
+		{IngredientID: beansID, IngredientName: "Coffee beans", Unit: "g", Quantity: stockOf(18)},
+	}, nil)
+
+	kg := "kg"
+	_, err := recipeService.SetMenuItemRecipe(latteID, &models.RecipeUpdate{
+		Items: []models.RecipeItemInput{
+			{IngredientID: beansID, Quantity: types.FromDecimal(decimal.RequireFromString("0.018")), Unit: &kg},
+		},
+	})
+	require.NoError(t, err)
+
+	mockRecipeRepo.AssertExpectations(t)
+}
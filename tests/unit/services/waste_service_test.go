@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/models"
+	"github.com/AndikaPrasetia/pos-cafee/internal/repositories"
+	"github.com/AndikaPrasetia/pos-cafee/internal/services"
+	"github.com/AndikaPrasetia/pos-cafee/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWasteRepo is a mock implementation of WasteRepo interface
+type MockWasteRepo struct {
+	mock.Mock
+}
+
+func (m *MockWasteRepo) CreateWasteRecord(record *models.WasteRecord) (*models.WasteRecord, error) {
+	args := m.Called(record)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WasteRecord), args.Error(1)
+}
+
+func (m *MockWasteRepo) ListWasteRecords(filter models.WasteFilter) ([]*models.WasteRecord, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WasteRecord), args.Error(1)
+}
+
+const (
+	wasteRecordID      = "1e2f3a4b-5c6d-4e7f-8a9b-0c1d2e3f4a5b"
+	stockTransactionID = "6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"
+)
+
+func TestWasteService_RecordWaste_IngredientValuedAtCost(t *testing.T) {
+	mockWasteRepo := new(MockWasteRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	mockLowStockAlertRepo := new(MockLowStockAlertRepo)
+	mockStockLotRepo := new(MockStockLotRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{
+		WasteRepo:            mockWasteRepo,
+		MenuRepo:             unchangedMenu(),
+		IngredientRepo:       mockIngredientRepo,
+		UnitRepo:             seededUnits(),
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    mockLowStockAlertRepo,
+		StockLotRepo:         mockStockLotRepo,
+	}, nil, nil)
+
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CostPrice: amount(150), IsActive: true}, nil)
+
+	// Half a kilo of expired beans is 500 g at 150 per gram
+	mockWasteRepo.On("CreateWasteRecord", mock.MatchedBy(func(record *models.WasteRecord) bool {
+		return *record.IngredientID == beansID &&
+			record.Reason == types.WasteReasonExpired &&
+			record.Quantity.Equals(stockOf(500)) &&
+			record.Unit == "g" &&
+			record.TotalCost.Equals(amount(75000))
+	})).Return(&models.WasteRecord{ID: wasteRecordID}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-500"), stockUserID).Return(stockOf(800), stockOf(300), nil)
+	mockStockLotRepo.On("GetExpiredStock", "", mock.Anything).Return(stockOf(0), nil)
+	mockStockLotRepo.On("ConsumeStockLots", "", mock.MatchedBy(func(ingredientID *string) bool {
+		return *ingredientID == beansID
+	}), stockMatching("500")).Return(nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.TransactionType == types.TransactionTypeOut &&
+			transaction.Reason == "Waste: expired" &&
+			*transaction.ReferenceType == types.ReferenceTypeWaste &&
+			*transaction.ReferenceID == wasteRecordID
+	})).Return(&models.StockTransaction{ID: stockTransactionID}, nil)
+
+	// The beans may have run low, going by the 800 g held before
+	mockLowStockAlertRepo.On("SyncLowStockAlert", stockTransactionID, "", mock.MatchedBy(func(ingredientID *string) bool {
+		return *ingredientID == beansID
+	}), stockMatching("800")).Return(nil)
+
+	ingredientID, unit := beansID, "kg"
+	_, err := wasteService.RecordWaste(stockUserID, &models.WasteCreate{
+		IngredientID: &ingredientID,
+		Reason:       types.WasteReasonExpired,
+		Quantity:     types.FromDecimal(decimal.RequireFromString("0.5")),
+		Unit:         &unit,
+	})
+	require.NoError(t, err)
+
+	mockWasteRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+	mockLowStockAlertRepo.AssertExpectations(t)
+	mockStockLotRepo.AssertExpectations(t)
+}
+
+func TestWasteService_RecordWaste_StaffMealUsesRecipe(t *testing.T) {
+	mockWasteRepo := new(MockWasteRepo)
+	mockMenuRepo := new(MockMenuRepo)
+	mockInventoryRepo := new(MockInventoryRepo)
+	mockIngredientRepo := new(MockIngredientRepo)
+	mockStockTransactionRepo := new(MockStockTransactionRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{
+		WasteRepo:            mockWasteRepo,
+		MenuRepo:             mockMenuRepo,
+		InventoryRepo:        mockInventoryRepo,
+		IngredientRepo:       mockIngredientRepo,
+		RecipeRepo:           latteRecipes(),
+		UnitRepo:             seededUnits(),
+		StockTransactionRepo: mockStockTransactionRepo,
+		StockLocationRepo:    sellingStockLocation(),
+		LowStockAlertRepo:    noStockAlerts(),
+		StockLotRepo:         consumableLots(),
+	}, nil, nil)
+
+	mockMenuRepo.On("GetMenuItem", latteID).Return(&models.MenuItem{ID: latteID, Name: "Latte", Price: amount(35000), Cost: amount(12000)}, nil)
+	mockMenuRepo.On("SyncMenuItemAvailability", "", mock.Anything).Return(nil, nil)
+	mockIngredientRepo.On("GetIngredient", beansID).Return(&models.Ingredient{ID: beansID, Name: "Coffee beans", Unit: "g", CostPrice: amount(150)}, nil)
+	mockIngredientRepo.On("GetIngredient", milkID).Return(&models.Ingredient{ID: milkID, Name: "Milk", Unit: "ml", CostPrice: amount(20)}, nil)
+
+	// A latte costs 18 g of beans and 200 ml of milk: 2700 + 4000
+	mockWasteRepo.On("CreateWasteRecord", mock.MatchedBy(func(record *models.WasteRecord) bool {
+		return *record.MenuItemID == latteID &&
+			record.Reason == types.WasteReasonStaffMeal &&
+			record.Unit == services.PortionUnit &&
+			record.UnitCost.Equals(amount(6700)) &&
+			record.TotalCost.Equals(amount(13400))
+	})).Return(&models.WasteRecord{ID: wasteRecordID}, nil)
+	mockIngredientRepo.On("AdjustIngredientStock", beansID, stockMatching("-36"), stockUserID).Return(stockOf(500), stockOf(464), nil)
+	mockIngredientRepo.On("AdjustIngredientStock", milkID, stockMatching("-400"), stockUserID).Return(stockOf(1000), stockOf(600), nil)
+	mockStockTransactionRepo.On("GetStockAverageCost", mock.Anything, mock.Anything).Return(amount(0), nil)
+	mockStockTransactionRepo.On("CreateStockTransaction", mock.MatchedBy(func(transaction *models.StockTransaction) bool {
+		return transaction.Reason == "Waste: staff meal" && *transaction.ReferenceID == wasteRecordID
+	})).Return(&models.StockTransaction{}, nil).Twice()
+
+	menuItemID := latteID
+	_, err := wasteService.RecordWaste(stockUserID, &models.WasteCreate{
+		MenuItemID: &menuItemID,
+		Reason:     types.WasteReasonStaffMeal,
+		Quantity:   stockOf(2),
+	})
+	require.NoError(t, err)
+
+	// The latte is made to order, so no finished goods stock is touched
+	mockInventoryRepo.AssertNotCalled(t, "AdjustInventoryStock", mock.Anything, mock.Anything, mock.Anything)
+	mockWasteRepo.AssertExpectations(t)
+	mockIngredientRepo.AssertExpectations(t)
+	mockStockTransactionRepo.AssertExpectations(t)
+}
+
+func TestWasteService_RecordWaste_RequiresOneItem(t *testing.T) {
+	mockWasteRepo := new(MockWasteRepo)
+	wasteService := services.NewWasteService(&repositories.Repository{WasteRepo: mockWasteRepo}, nil, nil)
+
+	menuItemID, ingredientID := latteID, beansID
+	_, err := wasteService.RecordWaste(stockUserID, &models.WasteCreate{
+		MenuItemID:   &menuItemID,
+		IngredientID: &ingredientID,
+		Reason:       types.WasteReasonDamaged,
+		Quantity:     stockOf(1),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "either a menu item or an ingredient")
+
+	mockWasteRepo.AssertNotCalled(t, "CreateWasteRecord", mock.Anything)
+}
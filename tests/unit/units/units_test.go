@@ -0,0 +1,56 @@
+package units_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AndikaPrasetia/pos-cafee/internal/units"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	gram       = units.Unit{Code: "g", Dimension: "mass", Factor: dec("1")}
+	kilogram   = units.Unit{Code: "kg", Dimension: "mass", Factor: dec("1000")}
+	millilitre = units.Unit{Code: "ml", Dimension: "volume", Factor: dec("1")}
+	litre      = units.Unit{Code: "l", Dimension: "volume", Factor: dec("1000")}
+	dozen      = units.Unit{Code: "dozen", Dimension: "count", Factor: dec("12")}
+	piece      = units.Unit{Code: "pcs", Dimension: "count", Factor: dec("1")}
+)
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		from     units.Unit
+		to       units.Unit
+		want     string
+	}{
+		{"litres bought, millilitres stocked", "1.5", litre, millilitre, "1500"},
+		{"grams used, kilograms stocked", "18", gram, kilogram, "0.018"},
+		{"dozens to pieces", "2", dozen, piece, "24"},
+		{"same unit", "12.5", gram, gram, "12.5"},
+		{"rounded to the stock scale", "1", piece, dozen, "0.083"},
+		{"negative quantities convert too", "-250", millilitre, litre, "-0.25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := units.Convert(dec(tt.quantity), tt.from, tt.to)
+			require.NoError(t, err)
+			assert.True(t, got.Equal(dec(tt.want)), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestConvert_IncompatibleUnits(t *testing.T) {
+	_, err := units.Convert(dec("200"), millilitre, gram)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, units.ErrIncompatibleUnits))
+	assert.Contains(t, err.Error(), "cannot convert ml to g")
+}